@@ -0,0 +1,16 @@
+package accesslog_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAccessLog(t *testing.T) {
+	SetDefaultEventuallyTimeout(5 * time.Second)
+
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Access Log Suite")
+}
@@ -0,0 +1,37 @@
+package accesslog
+
+import "time"
+
+// Entry records a single request to the API
+type Entry struct {
+	Timestamp     time.Time         `json:"timestamp"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Identity      *Identity         `json:"identity,omitempty"`
+	AuthScheme    string            `json:"auth_scheme,omitempty"`
+	Method        string            `json:"method"`
+	Route         string            `json:"route"`
+	Path          string            `json:"path"`
+	ResourceGUIDs map[string]string `json:"resource_guids,omitempty"`
+	Status        int               `json:"status"`
+	LatencyMillis int64             `json:"latency_ms"`
+	RemoteAddr    string            `json:"remote_addr"`
+	RequestBody   string            `json:"request_body,omitempty"`
+
+	// ImpersonatedUser is the user the identity acted as, see handlers.ImpersonateUserHeader
+	ImpersonatedUser string `json:"impersonated_user,omitempty"`
+
+	// Chain names the API process that wrote the entry, and Sequence numbers the entries of that chain from 1.
+	// Dropped is the number of entries dropped since the previous one. See Logger.
+	Chain    string `json:"chain"`
+	Sequence uint64 `json:"seq"`
+	Dropped  uint64 `json:"dropped,omitempty"`
+
+	// PrevHash and Hash chain the entries together, see Logger
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+type Identity struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
@@ -0,0 +1,30 @@
+package accesslog
+
+import (
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileSink appends entries to a file, which is rotated once it reaches its maximum size
+type FileSink struct {
+	writer *lumberjack.Logger
+}
+
+func NewFileSink(path string, maxSizeMB, maxBackups int) *FileSink {
+	return &FileSink{
+		writer: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+		},
+	}
+}
+
+func (s *FileSink) Write(line []byte) error {
+	// a single write per line, so that rotation never splits an entry
+	_, err := s.writer.Write(append(line, '\n'))
+	return err
+}
+
+func (s *FileSink) Close() error {
+	return s.writer.Close()
+}
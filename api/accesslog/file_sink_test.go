@@ -0,0 +1,56 @@
+package accesslog_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileSink", func() {
+	var (
+		logDir  string
+		logPath string
+		sink    *accesslog.FileSink
+	)
+
+	BeforeEach(func() {
+		var err error
+		logDir, err = os.MkdirTemp("", "access-log")
+		Expect(err).NotTo(HaveOccurred())
+		logPath = filepath.Join(logDir, "access.log")
+
+		sink = accesslog.NewFileSink(logPath, 1, 2)
+	})
+
+	AfterEach(func() {
+		Expect(sink.Close()).To(Succeed())
+		Expect(os.RemoveAll(logDir)).To(Succeed())
+	})
+
+	It("appends a line per entry", func() {
+		Expect(sink.Write([]byte(`{"a":1}`))).To(Succeed())
+		Expect(sink.Write([]byte(`{"b":2}`))).To(Succeed())
+
+		Expect(os.ReadFile(logPath)).To(BeEquivalentTo("{\"a\":1}\n{\"b\":2}\n"))
+	})
+
+	It("rotates the file once it reaches its maximum size", func() {
+		line := bytes.Repeat([]byte("x"), 300*1024)
+		for i := 0; i < 4; i++ {
+			Expect(sink.Write(line)).To(Succeed())
+		}
+
+		files, err := os.ReadDir(logDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+
+		current, err := os.ReadFile(logPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(HaveLen(len(line) + 1))
+	})
+})
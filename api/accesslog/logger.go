@@ -0,0 +1,187 @@
+package accesslog
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+)
+
+// Sink stores the JSON encoded entries of the access log
+type Sink interface {
+	Write(line []byte) error
+}
+
+// Logger writes entries to the configured sinks. Requests only queue their entry, which is written by Start, so that a
+// slow sink does not hold up the API; entries are dropped when the queue is full, and the next entry written records
+// how many were.
+//
+// Every entry carries an HMAC of its own content, which includes the HMAC of the entry before it, so that changing,
+// removing or reordering lines breaks the chain from that line on. The HMAC key is not written to the log, so the chain
+// cannot be recomputed by whoever can change the log. Each API process writes its own chain, named after the replica
+// and numbered from 1, so that a chain started by a restart or by another replica can be told apart from a forged one.
+type Logger struct {
+	// dropped comes first so that it is 64-bit aligned for atomic operations on 32-bit platforms
+	dropped uint64
+
+	sinks   []Sink
+	key     []byte
+	chain   string
+	entries chan Entry
+	logger  logr.Logger
+
+	sequence uint64
+	prevHash string
+}
+
+func NewLogger(logger logr.Logger, key []byte, chain string, queueSize int, sinks ...Sink) *Logger {
+	return &Logger{
+		sinks:   sinks,
+		key:     key,
+		chain:   chain,
+		entries: make(chan Entry, queueSize),
+		logger:  logger,
+	}
+}
+
+// Log queues an entry to be written
+func (l *Logger) Log(entry Entry) {
+	select {
+	case l.entries <- entry:
+	default:
+		atomic.AddUint64(&l.dropped, 1)
+		l.logger.Error(errors.New("access log queue is full"), "dropped access log entry", "correlation-id", entry.CorrelationID)
+	}
+}
+
+// Start writes queued entries until ctx is done
+func (l *Logger) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-l.entries:
+			l.write(entry)
+		}
+	}
+}
+
+func (l *Logger) write(entry Entry) {
+	entry.Chain = l.chain
+	entry.Sequence = l.sequence + 1
+	entry.Dropped = atomic.SwapUint64(&l.dropped, 0)
+	entry.PrevHash = l.prevHash
+	hash, err := hashEntry(l.key, entry)
+	if err != nil {
+		l.logger.Error(err, "failed to hash access log entry")
+		return
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		l.logger.Error(err, "failed to encode access log entry")
+		return
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(line); err != nil {
+			l.logger.Error(err, "failed to write access log entry", "correlation-id", entry.CorrelationID)
+		}
+	}
+
+	l.sequence = entry.Sequence
+	l.prevHash = hash
+}
+
+// ChainHead describes the entries of a chain found by Verify
+type ChainHead struct {
+	Chain string
+	// FirstSequence is the sequence number of the first entry of the chain in the log. It is greater than 1 when the
+	// log starts in the middle of the chain, e.g. when it has been rotated.
+	FirstSequence uint64
+	LastSequence  uint64
+	LastHash      string
+	// Dropped is the number of entries the API dropped while writing the chain
+	Dropped uint64
+}
+
+// Verify checks the chains of the newline separated entries read from r against the HMAC key the log was written with.
+// The first entry of each chain is trusted to follow on from the previous log file when its sequence number is greater
+// than 1. Verify returns the chains it found, in the order they start, so that their heads can be compared with the
+// ones of the next log file or with a copy kept elsewhere, e.g. by the webhook sink: entries removed from the end of a
+// chain can only be detected that way.
+func Verify(r io.Reader, key []byte) ([]ChainHead, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	heads := []ChainHead{}
+	chains := map[string]int{}
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		hash := entry.Hash
+		entry.Hash = ""
+		expectedHash, err := hashEntry(key, entry)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if !hmac.Equal([]byte(hash), []byte(expectedHash)) {
+			return nil, fmt.Errorf("line %d: content does not match its hash", lineNumber)
+		}
+
+		i, ok := chains[entry.Chain]
+		if !ok {
+			if entry.Sequence == 1 && entry.PrevHash != "" {
+				return nil, fmt.Errorf("line %d: starts chain %q with a previous hash", lineNumber, entry.Chain)
+			}
+
+			chains[entry.Chain] = len(heads)
+			heads = append(heads, ChainHead{
+				Chain:         entry.Chain,
+				FirstSequence: entry.Sequence,
+				LastSequence:  entry.Sequence,
+				LastHash:      hash,
+				Dropped:       entry.Dropped,
+			})
+			continue
+		}
+
+		head := &heads[i]
+		if entry.Sequence != head.LastSequence+1 || entry.PrevHash != head.LastHash {
+			return nil, fmt.Errorf("line %d: does not follow on from the previous entry of chain %q", lineNumber, entry.Chain)
+		}
+		head.LastSequence = entry.Sequence
+		head.LastHash = hash
+		head.Dropped += entry.Dropped
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return heads, nil
+}
+
+// hashEntry computes the HMAC of the entry without its own hash, which includes the hash of the previous entry
+func hashEntry(key []byte, entry Entry) (string, error) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,206 @@
+package accesslog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type recordingSink struct {
+	mutex sync.Mutex
+	lines [][]byte
+	err   error
+}
+
+func (s *recordingSink) Write(line []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lines = append(s.lines, line)
+	return s.err
+}
+
+func (s *recordingSink) Lines() [][]byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([][]byte{}, s.lines...)
+}
+
+var _ = Describe("Logger", func() {
+	var (
+		key        []byte
+		ctx        context.Context
+		cancel     context.CancelFunc
+		sink       *recordingSink
+		failedSink *recordingSink
+		logger     *accesslog.Logger
+		lines      [][]byte
+	)
+
+	logEntries := func(logger *accesslog.Logger, methods ...string) {
+		for i, method := range methods {
+			logger.Log(accesslog.Entry{
+				Timestamp:     time.Date(2022, 11, 1, 10, 0, i, 0, time.UTC),
+				CorrelationID: "correlation-id",
+				Identity:      &accesslog.Identity{Name: "bob", Kind: "User"},
+				AuthScheme:    "bearer",
+				Method:        method,
+				Route:         "/v3/apps/{guid}",
+				Path:          "/v3/apps/app-guid",
+				ResourceGUIDs: map[string]string{"guid": "app-guid"},
+				Status:        200,
+				LatencyMillis: 12,
+			})
+		}
+	}
+
+	BeforeEach(func() {
+		key = []byte("access-log-key")
+		ctx, cancel = context.WithCancel(context.Background())
+		sink = new(recordingSink)
+		failedSink = &recordingSink{err: errors.New("boom")}
+		logger = accesslog.NewLogger(ctrl.Log, key, "api-pod/run-1", 10, failedSink, sink)
+		go logger.Start(ctx)
+
+		logEntries(logger, "GET", "POST", "DELETE")
+		Eventually(sink.Lines).Should(HaveLen(3))
+		lines = sink.Lines()
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	logFile := func(lines [][]byte) *bytes.Buffer {
+		buf := new(bytes.Buffer)
+		for _, line := range lines {
+			buf.Write(line)
+			buf.WriteString("\n")
+		}
+		return buf
+	}
+
+	It("writes the entries as JSON to every sink", func() {
+		Expect(failedSink.Lines()).To(HaveLen(3))
+
+		var entry map[string]interface{}
+		Expect(json.Unmarshal(lines[0], &entry)).To(Succeed())
+		Expect(entry).To(MatchKeys(IgnoreExtras, Keys{
+			"timestamp":      Equal("2022-11-01T10:00:00Z"),
+			"correlation_id": Equal("correlation-id"),
+			"identity":       Equal(map[string]interface{}{"name": "bob", "kind": "User"}),
+			"auth_scheme":    Equal("bearer"),
+			"method":         Equal("GET"),
+			"route":          Equal("/v3/apps/{guid}"),
+			"resource_guids": Equal(map[string]interface{}{"guid": "app-guid"}),
+			"status":         BeEquivalentTo(200),
+			"latency_ms":     BeEquivalentTo(12),
+			"chain":          Equal("api-pod/run-1"),
+			"seq":            BeEquivalentTo(1),
+			"prev_hash":      Equal(""),
+			"hash":           MatchRegexp("^[0-9a-f]{64}$"),
+		}))
+		Expect(entry).NotTo(HaveKey("dropped"))
+	})
+
+	It("chains the entries", func() {
+		var first, second accesslog.Entry
+		Expect(json.Unmarshal(lines[0], &first)).To(Succeed())
+		Expect(json.Unmarshal(lines[1], &second)).To(Succeed())
+		Expect(second.PrevHash).To(Equal(first.Hash))
+		Expect(second.Sequence).To(BeEquivalentTo(2))
+
+		var last accesslog.Entry
+		Expect(json.Unmarshal(lines[2], &last)).To(Succeed())
+		Expect(accesslog.Verify(logFile(lines), key)).To(Equal([]accesslog.ChainHead{{
+			Chain:         "api-pod/run-1",
+			FirstSequence: 1,
+			LastSequence:  3,
+			LastHash:      last.Hash,
+		}}))
+	})
+
+	It("detects changed entries", func() {
+		lines[1] = []byte(strings.Replace(string(lines[1]), `"POST"`, `"GET"`, 1))
+		_, err := accesslog.Verify(logFile(lines), key)
+		Expect(err).To(MatchError("line 2: content does not match its hash"))
+	})
+
+	It("detects entries rehashed without the key", func() {
+		forger := accesslog.NewLogger(ctrl.Log, []byte("another-key"), "api-pod/run-1", 10, sink)
+		go forger.Start(ctx)
+		logEntries(forger, "GET", "GET", "DELETE")
+		Eventually(sink.Lines).Should(HaveLen(6))
+
+		_, err := accesslog.Verify(logFile(sink.Lines()[3:]), key)
+		Expect(err).To(MatchError("line 1: content does not match its hash"))
+	})
+
+	It("detects removed entries", func() {
+		_, err := accesslog.Verify(logFile(append(lines[:1:1], lines[2:]...)), key)
+		Expect(err).To(MatchError(`line 2: does not follow on from the previous entry of chain "api-pod/run-1"`))
+	})
+
+	It("trusts the first entry of a rotated file", func() {
+		heads, err := accesslog.Verify(logFile(lines[1:]), key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(heads).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"FirstSequence": BeEquivalentTo(2),
+			"LastSequence":  BeEquivalentTo(3),
+		})))
+	})
+
+	When("the API restarts", func() {
+		BeforeEach(func() {
+			restarted := accesslog.NewLogger(ctrl.Log, key, "api-pod/run-2", 10, sink)
+			go restarted.Start(ctx)
+			logEntries(restarted, "PATCH")
+			Eventually(sink.Lines).Should(HaveLen(4))
+			lines = sink.Lines()
+		})
+
+		It("reports the chain it starts", func() {
+			heads, err := accesslog.Verify(logFile(lines), key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heads).To(HaveLen(2))
+			Expect(heads[0].Chain).To(Equal("api-pod/run-1"))
+			Expect(heads[1]).To(MatchFields(IgnoreExtras, Fields{
+				"Chain":         Equal("api-pod/run-2"),
+				"FirstSequence": BeEquivalentTo(1),
+				"LastSequence":  BeEquivalentTo(1),
+			}))
+		})
+	})
+
+	When("the queue is full", func() {
+		var stalledSink *recordingSink
+
+		BeforeEach(func() {
+			stalledSink = new(recordingSink)
+			stalled := accesslog.NewLogger(ctrl.Log, key, "api-pod/run-3", 1, stalledSink)
+			logEntries(stalled, "GET", "POST", "DELETE")
+			go stalled.Start(ctx)
+		})
+
+		It("drops entries without blocking and records how many were dropped", func() {
+			Eventually(stalledSink.Lines).Should(HaveLen(1))
+
+			var entry accesslog.Entry
+			Expect(json.Unmarshal(stalledSink.Lines()[0], &entry)).To(Succeed())
+			Expect(entry.Method).To(Equal("GET"))
+			Expect(entry.Dropped).To(BeEquivalentTo(2))
+		})
+	})
+})
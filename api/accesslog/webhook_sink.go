@@ -0,0 +1,72 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// WebhookSink posts each entry as a JSON document to a URL. Entries are sent in order from a queue, so that a slow
+// webhook does not hold up requests; entries are dropped when the queue is full.
+type WebhookSink struct {
+	url        string
+	httpClient *http.Client
+	queue      chan []byte
+	logger     logr.Logger
+}
+
+func NewWebhookSink(url string, httpClient *http.Client, queueSize int, logger logr.Logger) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		httpClient: httpClient,
+		queue:      make(chan []byte, queueSize),
+		logger:     logger,
+	}
+}
+
+func (s *WebhookSink) Write(line []byte) error {
+	select {
+	case s.queue <- line:
+		return nil
+	default:
+		return errors.New("webhook queue is full")
+	}
+}
+
+// Start sends queued entries until ctx is done
+func (s *WebhookSink) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-s.queue:
+			if err := s.post(ctx, line); err != nil {
+				s.logger.Error(err, "failed to send access log entry to webhook")
+			}
+		}
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, line []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package accesslog_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("WebhookSink", func() {
+	var (
+		server      *httptest.Server
+		mutex       sync.Mutex
+		received    []string
+		contentType string
+		sink        *accesslog.WebhookSink
+		cancel      context.CancelFunc
+	)
+
+	receivedEntries := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, received...)
+	}
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			received = append(received, string(body))
+			contentType = r.Header.Get("Content-Type")
+		}))
+
+		sink = accesslog.NewWebhookSink(server.URL, server.Client(), 1, ctrl.Log)
+	})
+
+	AfterEach(func() {
+		if cancel != nil {
+			cancel()
+		}
+		server.Close()
+	})
+
+	It("posts the entries in order", func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go sink.Start(ctx)
+
+		Expect(sink.Write([]byte(`{"a":1}`))).To(Succeed())
+		Eventually(receivedEntries).Should(HaveLen(1))
+		Expect(sink.Write([]byte(`{"b":2}`))).To(Succeed())
+
+		Eventually(receivedEntries).Should(Equal([]string{`{"a":1}`, `{"b":2}`}))
+		Expect(contentType).To(Equal("application/json"))
+	})
+
+	When("the queue is full", func() {
+		It("drops the entry", func() {
+			Expect(sink.Write([]byte(`{"a":1}`))).To(Succeed())
+			Expect(sink.Write([]byte(`{"b":2}`))).To(MatchError("webhook queue is full"))
+		})
+	})
+})
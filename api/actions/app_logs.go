@@ -2,8 +2,8 @@ package actions
 
 import (
 	"context"
-	"errors"
 	"sort"
+	"strconv"
 
 	"code.cloudfoundry.org/korifi/api/actions/shared"
 	"code.cloudfoundry.org/korifi/api/apierrors"
@@ -14,17 +14,22 @@ import (
 	"github.com/go-logr/logr"
 )
 
+const (
+	logEnvelopeType   = "LOG"
+	gaugeEnvelopeType = "GAUGE"
+)
+
 type AppLogs struct {
-	appRepo   shared.CFAppRepository
-	buildRepo shared.CFBuildRepository
-	podRepo   shared.PodRepository
+	appRepo  shared.CFAppRepository
+	podRepo  shared.PodRepository
+	logStore shared.LogStore
 }
 
-func NewAppLogs(appRepo shared.CFAppRepository, buildRepo shared.CFBuildRepository, podRepo shared.PodRepository) *AppLogs {
+func NewAppLogs(appRepo shared.CFAppRepository, podRepo shared.PodRepository, logStore shared.LogStore) *AppLogs {
 	return &AppLogs{
-		appRepo:   appRepo,
-		buildRepo: buildRepo,
-		podRepo:   podRepo,
+		appRepo:  appRepo,
+		podRepo:  podRepo,
+		logStore: logStore,
 	}
 }
 
@@ -38,57 +43,104 @@ func (a *AppLogs) Read(ctx context.Context, logger logr.Logger, authInfo authori
 		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
 	}
 
-	build, err := a.buildRepo.GetLatestBuildByAppGUID(ctx, authInfo, app.SpaceGUID, appGUID)
-	if err != nil {
-		if !errors.As(err, new(apierrors.NotFoundError)) {
-			return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch latest app CFBuild from Kubernetes", "AppGUID", appGUID)
+	logs := []repositories.LogRecord{}
+	if len(read.EnvelopeTypes) == 0 || containsEnvelopeType(read.EnvelopeTypes, logEnvelopeType) {
+		logs, err = a.logStore.Read(ctx, app.GUID)
+		if err != nil {
+			return nil, apierrors.LogAndReturn(logger, err, "Failed to read app logs", "AppGUID", appGUID)
 		}
-		return []repositories.LogRecord{}, nil
 	}
 
-	buildLogs, err := a.buildRepo.GetBuildLogs(ctx, authInfo, app.SpaceGUID, build.GUID)
-	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch build logs", "AppGUID", appGUID, "BuildGUID", build.GUID)
+	// gauges are only returned on request, as fetching them hits the metrics server
+	if containsEnvelopeType(read.EnvelopeTypes, gaugeEnvelopeType) {
+		var podMetrics []repositories.PodMetricsRecord
+		podMetrics, err = a.podRepo.ListPodMetrics(ctx, authInfo, repositories.ListPodMetricsMessage{
+			Namespace: app.SpaceGUID,
+			AppGUID:   app.GUID,
+		})
+		if err != nil {
+			return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch app metrics", "AppGUID", appGUID)
+		}
+
+		for _, metrics := range podMetrics {
+			logs = append(logs, gaugeRecord(metrics))
+		}
+		sort.SliceStable(logs, func(i, j int) bool {
+			return logs[i].Timestamp < logs[j].Timestamp
+		})
+	}
+
+	// filter any entries from before the start time
+	if read.StartTime != nil {
+		first := sort.Search(len(logs), func(i int) bool { return *read.StartTime <= logs[i].Timestamp })
+		logs = logs[first:]
+	}
+
+	// filter any entries from the end time on, as log-cache excludes the end time
+	if read.EndTime != nil {
+		end := sort.Search(len(logs), func(i int) bool { return *read.EndTime <= logs[i].Timestamp })
+		logs = logs[:end]
 	}
 
+	// keep the most recent entries up to the log limit
 	logLimit := int64(defaultLogLimit)
 	if read.Limit != nil {
 		logLimit = *read.Limit
 	}
+	if int64(len(logs)) > logLimit {
+		logs = logs[int64(len(logs))-logLimit:]
+	}
 
-	runtimeLogs, err := a.podRepo.GetRuntimeLogsForApp(ctx, logger, authInfo, repositories.RuntimeLogsMessage{
-		SpaceGUID:   app.SpaceGUID,
-		AppGUID:     app.GUID,
-		AppRevision: app.Revision,
-		Limit:       logLimit,
-	})
-	if err != nil {
-		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch app runtime logs from Kubernetes", "AppGUID", appGUID)
+	if read.Descending != nil && *read.Descending {
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
 	}
 
-	logs := append(buildLogs, runtimeLogs...)
+	return logs, nil
+}
 
-	sort.Slice(logs, func(i, j int) bool {
-		return logs[i].Timestamp < logs[j].Timestamp
-	})
+// Stream returns a channel receiving the log records of an app as they are collected, and a function ending the
+// stream. Every stream shares the container log follows of the collector, no matter how many clients watch an app or
+// which API replica serves them.
+func (a *AppLogs) Stream(ctx context.Context, logger logr.Logger, authInfo authorization.Info, appGUID string) (<-chan repositories.LogRecord, func(), error) {
+	app, err := a.appRepo.GetApp(ctx, authInfo, appGUID)
+	if err != nil {
+		return nil, nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app from Kubernetes", "AppGUID", appGUID)
+	}
 
-	// ensure that we didn't exceed the log limit
-	if read.Limit != nil && int64(len(logs)) > *read.Limit {
-		first := int64(len(logs)) - *read.Limit
-		logs = logs[first:]
+	records, cancel, err := a.logStore.Subscribe(ctx, app.GUID)
+	if err != nil {
+		return nil, nil, apierrors.LogAndReturn(logger, err, "Failed to stream app logs", "AppGUID", appGUID)
 	}
 
-	// filter any entries from before the start time
-	if read.StartTime != nil {
-		first := sort.Search(len(logs), func(i int) bool { return *read.StartTime <= logs[i].Timestamp })
-		logs = logs[first:]
+	return records, cancel, nil
+}
+
+func gaugeRecord(metrics repositories.PodMetricsRecord) repositories.LogRecord {
+	return repositories.LogRecord{
+		Timestamp:  metrics.Timestamp.UnixNano(),
+		InstanceID: strconv.Itoa(metrics.Index),
+		Tags: map[string]string{
+			"process_id":   metrics.ProcessGUID,
+			"process_type": metrics.ProcessType,
+		},
+		Gauge: map[string]repositories.GaugeMetric{
+			"cpu":          {Unit: "percentage", Value: metrics.CPU * 100},
+			"memory":       {Unit: "bytes", Value: float64(metrics.Mem)},
+			"disk":         {Unit: "bytes", Value: float64(metrics.Disk)},
+			"memory_quota": {Unit: "bytes", Value: float64(metrics.MemQuota)},
+			"disk_quota":   {Unit: "bytes", Value: float64(metrics.DiskQuota)},
+		},
 	}
+}
 
-	if read.Descending != nil && *read.Descending {
-		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
-			logs[i], logs[j] = logs[j], logs[i]
+func containsEnvelopeType(envelopeTypes []string, soughtType string) bool {
+	for _, envelopeType := range envelopeTypes {
+		if envelopeType == soughtType {
+			return true
 		}
 	}
 
-	return logs, nil
+	return false
 }
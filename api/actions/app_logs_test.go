@@ -21,15 +21,13 @@ import (
 var _ = Describe("ReadAppLogs", func() {
 	const (
 		appGUID   = "test-app-guid"
-		buildGUID = "test-build-guid"
-
 		spaceGUID = "test-space-guid"
 	)
 
 	var (
-		appRepo   *fake.CFAppRepository
-		buildRepo *fake.CFBuildRepository
-		podRepo   *fake.PodRepository
+		appRepo  *fake.CFAppRepository
+		podRepo  *fake.PodRepository
+		logStore *fake.LogStore
 
 		appLogs *AppLogs
 
@@ -44,10 +42,10 @@ var _ = Describe("ReadAppLogs", func() {
 
 	BeforeEach(func() {
 		appRepo = new(fake.CFAppRepository)
-		buildRepo = new(fake.CFBuildRepository)
 		podRepo = new(fake.PodRepository)
+		logStore = new(fake.LogStore)
 
-		appLogs = NewAppLogs(appRepo, buildRepo, podRepo)
+		appLogs = NewAppLogs(appRepo, podRepo, logStore)
 
 		appRepo.GetAppReturns(repositories.AppRecord{
 			GUID:      appGUID,
@@ -55,11 +53,6 @@ var _ = Describe("ReadAppLogs", func() {
 			SpaceGUID: spaceGUID,
 		}, nil)
 
-		buildRepo.GetLatestBuildByAppGUIDReturns(repositories.BuildRecord{
-			GUID:    buildGUID,
-			AppGUID: appGUID,
-		}, nil)
-
 		nowTime := time.Now()
 		buildLogs = []repositories.LogRecord{
 			{
@@ -71,7 +64,6 @@ var _ = Describe("ReadAppLogs", func() {
 				Timestamp: nowTime.Add(time.Nanosecond).UnixNano(),
 			},
 		}
-		buildRepo.GetBuildLogsReturns(buildLogs, nil)
 
 		logs = []repositories.LogRecord{
 			{
@@ -83,7 +75,9 @@ var _ = Describe("ReadAppLogs", func() {
 				Timestamp: nowTime.Add(time.Nanosecond * 3).UnixNano(),
 			},
 		}
-		podRepo.GetRuntimeLogsForAppReturns(logs, nil)
+		logStore.ReadStub = func(context.Context, string) ([]repositories.LogRecord, error) {
+			return append(append([]repositories.LogRecord{}, buildLogs...), logs...), nil
+		}
 
 		requestPayload = payloads.LogRead{
 			StartTime:     nil,
@@ -99,10 +93,10 @@ var _ = Describe("ReadAppLogs", func() {
 		returnedRecords, returnedErr = appLogs.Read(context.Background(), logf.Log.WithName("testlogger"), authInfo, appGUID, requestPayload)
 	})
 
-	It("sets the log limit to 100 when not specified", func() {
-		Expect(podRepo.GetRuntimeLogsForAppCallCount()).To(BeNumerically(">=", 1))
-		_, _, _, message := podRepo.GetRuntimeLogsForAppArgsForCall(0)
-		Expect(message.Limit).To(Equal(int64(100)))
+	It("reads the app logs from the log store", func() {
+		Expect(logStore.ReadCallCount()).To(Equal(1))
+		_, actualAppGUID := logStore.ReadArgsForCall(0)
+		Expect(actualAppGUID).To(Equal(appGUID))
 	})
 
 	It("returns the list of build and app records", func() {
@@ -110,6 +104,23 @@ var _ = Describe("ReadAppLogs", func() {
 		Expect(returnedRecords).To(Equal(append(buildLogs, logs...)))
 	})
 
+	When("more logs than the default limit are stored", func() {
+		BeforeEach(func() {
+			logStore.ReadStub = nil
+			manyLogs := []repositories.LogRecord{}
+			for i := 0; i < 150; i++ {
+				manyLogs = append(manyLogs, repositories.LogRecord{Message: "AppMessage", Timestamp: int64(i)})
+			}
+			logStore.ReadReturns(manyLogs, nil)
+		})
+
+		It("returns the 100 most recent logs", func() {
+			Expect(returnedErr).NotTo(HaveOccurred())
+			Expect(returnedRecords).To(HaveLen(100))
+			Expect(returnedRecords[0].Timestamp).To(Equal(int64(50)))
+		})
+	})
+
 	When("the limit is lower than the total number of logs available", func() {
 		BeforeEach(func() {
 			limit := int64(2)
@@ -135,18 +146,6 @@ var _ = Describe("ReadAppLogs", func() {
 				Expect(returnedRecords[1].Message).To(Equal("AppMessage2"))
 			})
 		})
-
-		When("the build and run logs are chronologically interleaved", func() {
-			BeforeEach(func() {
-				buildLogs[1].Timestamp, logs[0].Timestamp = logs[0].Timestamp, buildLogs[1].Timestamp
-			})
-			It("gives us the most recent logs up to the limit", func() {
-				Expect(returnedErr).NotTo(HaveOccurred())
-				Expect(returnedRecords).To(HaveLen(2))
-				Expect(returnedRecords[0].Message).To(Equal("BuildMessage2"))
-				Expect(returnedRecords[1].Message).To(Equal("AppMessage2"))
-			})
-		})
 	})
 
 	When("the descending flag in the request is set to true", func() {
@@ -187,81 +186,229 @@ var _ = Describe("ReadAppLogs", func() {
 		})
 	})
 
-	When("GetApp returns a Forbidden error", func() {
+	When("the end time is set", func() {
 		BeforeEach(func() {
-			appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(errors.New("blah"), repositories.AppResourceType))
+			endTime := logs[0].Timestamp
+			requestPayload.EndTime = &endTime
 		})
-		It("returns a NotFound error", func() {
-			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+
+		It("returns only the entries from before the end time", func() {
+			Expect(returnedErr).NotTo(HaveOccurred())
+			Expect(returnedRecords).To(Equal(buildLogs))
+		})
+
+		When("the limit is lower than the number of entries before the end time", func() {
+			BeforeEach(func() {
+				limit := int64(1)
+				requestPayload.Limit = &limit
+			})
+
+			It("returns the most recent entries before the end time", func() {
+				Expect(returnedErr).NotTo(HaveOccurred())
+				Expect(returnedRecords).To(Equal(buildLogs[1:]))
+			})
 		})
 	})
 
-	When("GetApp returns a random error", func() {
-		var getAppError error
+	It("does not fetch the app metrics", func() {
+		Expect(podRepo.ListPodMetricsCallCount()).To(BeZero())
+	})
+
+	When("the envelope types include LOG", func() {
 		BeforeEach(func() {
-			getAppError = errors.New("blah")
-			appRepo.GetAppReturns(repositories.AppRecord{}, getAppError)
+			requestPayload.EnvelopeTypes = []string{"TIMER", "LOG"}
 		})
-		It("returns the error transparently", func() {
-			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(Equal(getAppError))
+
+		It("returns the logs", func() {
+			Expect(returnedErr).NotTo(HaveOccurred())
+			Expect(returnedRecords).To(HaveLen(4))
 		})
 	})
 
-	When("GetLatestBuildByAppGUID returns a Forbidden error", func() {
+	When("the envelope types do not include LOG", func() {
 		BeforeEach(func() {
-			buildRepo.GetLatestBuildByAppGUIDReturns(repositories.BuildRecord{}, apierrors.NewForbiddenError(errors.New("blah"), repositories.BuildResourceType))
+			requestPayload.EnvelopeTypes = []string{"TIMER"}
 		})
-		It("returns a NotFound error", func() {
-			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+
+		It("returns an empty list", func() {
+			Expect(returnedErr).NotTo(HaveOccurred())
+			Expect(returnedRecords).To(BeEmpty())
 		})
 	})
 
-	When("GetLatestBuildByAppGUID returns a random error", func() {
-		var getLatestBuildByAppGUID error
+	When("the envelope types include GAUGE", func() {
+		var metricsTime time.Time
+
 		BeforeEach(func() {
-			getLatestBuildByAppGUID = errors.New("blah")
-			buildRepo.GetLatestBuildByAppGUIDReturns(repositories.BuildRecord{}, getLatestBuildByAppGUID)
+			requestPayload.EnvelopeTypes = []string{"GAUGE"}
+
+			metricsTime = time.Now().Add(time.Minute)
+			podRepo.ListPodMetricsReturns([]repositories.PodMetricsRecord{{
+				ProcessGUID: "process-guid",
+				ProcessType: "web",
+				Index:       1,
+				Timestamp:   metricsTime,
+				CPU:         0.25,
+				Mem:         100,
+				Disk:        200,
+				MemQuota:    1024,
+				DiskQuota:   2048,
+			}}, nil)
 		})
-		It("returns the error transparently", func() {
-			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(Equal(getLatestBuildByAppGUID))
+
+		It("fetches the metrics of the app", func() {
+			Expect(podRepo.ListPodMetricsCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := podRepo.ListPodMetricsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message).To(Equal(repositories.ListPodMetricsMessage{
+				Namespace: spaceGUID,
+				AppGUID:   appGUID,
+			}))
+		})
+
+		It("returns a gauge envelope per instance", func() {
+			Expect(returnedErr).NotTo(HaveOccurred())
+			Expect(returnedRecords).To(Equal([]repositories.LogRecord{{
+				Timestamp:  metricsTime.UnixNano(),
+				InstanceID: "1",
+				Tags: map[string]string{
+					"process_id":   "process-guid",
+					"process_type": "web",
+				},
+				Gauge: map[string]repositories.GaugeMetric{
+					"cpu":          {Unit: "percentage", Value: 25},
+					"memory":       {Unit: "bytes", Value: 100},
+					"disk":         {Unit: "bytes", Value: 200},
+					"memory_quota": {Unit: "bytes", Value: 1024},
+					"disk_quota":   {Unit: "bytes", Value: 2048},
+				},
+			}}))
+		})
+
+		When("LOG envelopes are requested too", func() {
+			BeforeEach(func() {
+				requestPayload.EnvelopeTypes = []string{"LOG", "GAUGE"}
+			})
+
+			It("returns the gauges merged with the logs in chronological order", func() {
+				Expect(returnedErr).NotTo(HaveOccurred())
+				Expect(returnedRecords).To(HaveLen(5))
+				Expect(returnedRecords[4].Gauge).NotTo(BeEmpty())
+			})
+		})
+
+		When("fetching the metrics fails", func() {
+			BeforeEach(func() {
+				podRepo.ListPodMetricsReturns(nil, errors.New("metrics-boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(returnedErr).To(MatchError("metrics-boom"))
+			})
 		})
 	})
 
-	When("GetLatestBuildByAppGUID returns a NotFound error", func() {
+	When("reading the log store fails", func() {
 		BeforeEach(func() {
-			buildRepo.GetLatestBuildByAppGUIDReturns(repositories.BuildRecord{}, apierrors.NewNotFoundError(errors.New("blah"), repositories.BuildResourceType))
+			logStore.ReadStub = nil
+			logStore.ReadReturns(nil, errors.New("read-boom"))
 		})
-		It("returns no log records", func() {
-			Expect(returnedErr).NotTo(HaveOccurred())
-			Expect(returnedRecords).To(Equal([]repositories.LogRecord{}))
+
+		It("returns the error", func() {
+			Expect(returnedErr).To(MatchError("read-boom"))
 		})
 	})
 
-	When("GetBuildLogsReturns returns an error", func() {
-		var getBuildLogsReturns error
+	When("GetApp returns a Forbidden error", func() {
 		BeforeEach(func() {
-			getBuildLogsReturns = errors.New("blah")
-			buildRepo.GetBuildLogsReturns(nil, getBuildLogsReturns)
+			appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(errors.New("blah"), repositories.AppResourceType))
 		})
-		It("returns the error transparently", func() {
+		It("returns a NotFound error", func() {
 			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(Equal(getBuildLogsReturns))
+			Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+
+		It("does not read the log store", func() {
+			Expect(logStore.ReadCallCount()).To(BeZero())
 		})
 	})
 
-	When("GetRuntimeLogsForAppReturns returns an error", func() {
-		var getRuntimeLogsReturns error
+	When("GetApp returns a random error", func() {
+		var getAppError error
 		BeforeEach(func() {
-			getRuntimeLogsReturns = errors.New("blah")
-			podRepo.GetRuntimeLogsForAppReturns(nil, getRuntimeLogsReturns)
+			getAppError = errors.New("blah")
+			appRepo.GetAppReturns(repositories.AppRecord{}, getAppError)
 		})
 		It("returns the error transparently", func() {
 			Expect(returnedErr).To(HaveOccurred())
-			Expect(returnedErr).To(Equal(getRuntimeLogsReturns))
+			Expect(returnedErr).To(Equal(getAppError))
+		})
+	})
+})
+
+var _ = Describe("StreamAppLogs", func() {
+	const appGUID = "test-app-guid"
+
+	var (
+		appRepo  *fake.CFAppRepository
+		logStore *fake.LogStore
+		appLogs  *AppLogs
+
+		subscription chan repositories.LogRecord
+		cancelCalled bool
+
+		returnedRecords <-chan repositories.LogRecord
+		returnedCancel  func()
+		returnedErr     error
+	)
+
+	BeforeEach(func() {
+		appRepo = new(fake.CFAppRepository)
+		logStore = new(fake.LogStore)
+		appLogs = NewAppLogs(appRepo, new(fake.PodRepository), logStore)
+
+		appRepo.GetAppReturns(repositories.AppRecord{GUID: appGUID}, nil)
+
+		subscription = make(chan repositories.LogRecord, 1)
+		cancelCalled = false
+		logStore.SubscribeReturns(subscription, func() { cancelCalled = true }, nil)
+	})
+
+	JustBeforeEach(func() {
+		returnedRecords, returnedCancel, returnedErr = appLogs.Stream(context.Background(), logf.Log.WithName("testlogger"), authorization.Info{Token: "a-token"}, appGUID)
+	})
+
+	It("subscribes to the logs of the app", func() {
+		Expect(returnedErr).NotTo(HaveOccurred())
+		Expect(logStore.SubscribeCallCount()).To(Equal(1))
+		_, actualAppGUID := logStore.SubscribeArgsForCall(0)
+		Expect(actualAppGUID).To(Equal(appGUID))
+
+		subscription <- repositories.LogRecord{Message: "streamed"}
+		Expect(returnedRecords).To(Receive(Equal(repositories.LogRecord{Message: "streamed"})))
+
+		returnedCancel()
+		Expect(cancelCalled).To(BeTrue())
+	})
+
+	When("GetApp returns a Forbidden error", func() {
+		BeforeEach(func() {
+			appRepo.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(errors.New("blah"), repositories.AppResourceType))
+		})
+
+		It("returns a NotFound error without subscribing", func() {
+			Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			Expect(logStore.SubscribeCallCount()).To(BeZero())
+		})
+	})
+
+	When("subscribing to the log store fails", func() {
+		BeforeEach(func() {
+			logStore.SubscribeReturns(nil, nil, errors.New("subscribe-boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(returnedErr).To(MatchError("subscribe-boom"))
 		})
 	})
 })
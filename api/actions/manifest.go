@@ -27,6 +27,7 @@ type Applier interface {
 }
 
 type Manifest struct {
+	appRepo           shared.CFAppRepository
 	domainRepo        shared.CFDomainRepository
 	defaultDomainName string
 	stateCollector    StateCollector
@@ -34,9 +35,10 @@ type Manifest struct {
 	applier           Applier
 }
 
-func NewManifest(domainRepo shared.CFDomainRepository, defaultDomainName string, stateCollector StateCollector, normalizer Normalizer, applier Applier,
+func NewManifest(appRepo shared.CFAppRepository, domainRepo shared.CFDomainRepository, defaultDomainName string, stateCollector StateCollector, normalizer Normalizer, applier Applier,
 ) *Manifest {
 	return &Manifest{
+		appRepo:           appRepo,
 		domainRepo:        domainRepo,
 		defaultDomainName: defaultDomainName,
 		stateCollector:    stateCollector,
@@ -61,6 +63,28 @@ func (a *Manifest) Apply(ctx context.Context, authInfo authorization.Info, space
 	return a.applier.Apply(ctx, authInfo, spaceGUID, appInfo, appState)
 }
 
+func (a *Manifest) Export(ctx context.Context, authInfo authorization.Info, appGUID string) (payloads.Manifest, error) {
+	app, err := a.appRepo.GetApp(ctx, authInfo, appGUID)
+	if err != nil {
+		return payloads.Manifest{}, apierrors.ForbiddenAsNotFound(err)
+	}
+
+	appState, err := a.stateCollector.CollectState(ctx, authInfo, app.Name, app.SpaceGUID)
+	if err != nil {
+		return payloads.Manifest{}, err
+	}
+
+	appEnv, err := a.appRepo.GetAppEnv(ctx, authInfo, appGUID)
+	if err != nil {
+		return payloads.Manifest{}, err
+	}
+
+	return payloads.Manifest{
+		Version:      1,
+		Applications: []payloads.ManifestApplication{manifest.Export(appState, appEnv.EnvironmentVariables)},
+	}, nil
+}
+
 func (a *Manifest) ensureDefaultDomainConfigured(ctx context.Context, authInfo authorization.Info) error {
 	_, err := a.domainRepo.GetDomainByName(ctx, authInfo, a.defaultDomainName)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/korifi/api/actions/shared"
+	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -13,10 +14,12 @@ import (
 )
 
 type Applier struct {
-	appRepo     shared.CFAppRepository
-	domainRepo  shared.CFDomainRepository
-	processRepo shared.CFProcessRepository
-	routeRepo   shared.CFRouteRepository
+	appRepo             shared.CFAppRepository
+	domainRepo          shared.CFDomainRepository
+	processRepo         shared.CFProcessRepository
+	routeRepo           shared.CFRouteRepository
+	serviceInstanceRepo shared.CFServiceInstanceRepository
+	serviceBindingRepo  shared.CFServiceBindingRepository
 }
 
 func NewApplier(
@@ -24,12 +27,16 @@ func NewApplier(
 	domainRepo shared.CFDomainRepository,
 	processRepo shared.CFProcessRepository,
 	routeRepo shared.CFRouteRepository,
+	serviceInstanceRepo shared.CFServiceInstanceRepository,
+	serviceBindingRepo shared.CFServiceBindingRepository,
 ) *Applier {
 	return &Applier{
-		appRepo:     appRepo,
-		domainRepo:  domainRepo,
-		processRepo: processRepo,
-		routeRepo:   routeRepo,
+		appRepo:             appRepo,
+		domainRepo:          domainRepo,
+		processRepo:         processRepo,
+		routeRepo:           routeRepo,
+		serviceInstanceRepo: serviceInstanceRepo,
+		serviceBindingRepo:  serviceBindingRepo,
 	}
 }
 
@@ -43,7 +50,11 @@ func (a *Applier) Apply(ctx context.Context, authInfo authorization.Info, spaceG
 		return err
 	}
 
-	return a.applyRoutes(ctx, authInfo, appInfo, appState)
+	if err := a.applyRoutes(ctx, authInfo, appInfo, appState); err != nil {
+		return err
+	}
+
+	return a.applyServiceBindings(ctx, authInfo, appInfo, appState)
 }
 
 func (a *Applier) applyApp(
@@ -201,6 +212,37 @@ func removeDestinationFromList(destinationGUID string, destinations []repositori
 	return result
 }
 
+func (a *Applier) applyServiceBindings(ctx context.Context, authInfo authorization.Info, appInfo payloads.ManifestApplication, appState AppState) error {
+	for _, service := range appInfo.Services {
+		if _, bound := appState.ServiceBindings[service.Name]; bound {
+			continue
+		}
+
+		instances, err := a.serviceInstanceRepo.ListServiceInstances(ctx, authInfo, repositories.ListServiceInstanceMessage{
+			Names:      []string{service.Name},
+			SpaceGuids: []string{appState.App.SpaceGUID},
+		})
+		if err != nil {
+			return fmt.Errorf("listServiceInstances: %w", err)
+		}
+		if len(instances) == 0 {
+			return apierrors.NewUnprocessableEntityError(nil, fmt.Sprintf("Service instance %q not found", service.Name))
+		}
+
+		_, err = a.serviceBindingRepo.CreateServiceBinding(ctx, authInfo, repositories.CreateServiceBindingMessage{
+			Name:                service.BindingName,
+			ServiceInstanceGUID: instances[0].GUID,
+			AppGUID:             appState.App.GUID,
+			SpaceGUID:           appState.App.SpaceGUID,
+		})
+		if err != nil {
+			return fmt.Errorf("createServiceBinding: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func splitRoute(route string) (string, string, string) {
 	parts := strings.SplitN(route, ".", 2)
 	hostName := parts[0]
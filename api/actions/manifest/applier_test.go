@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/actions/manifest"
 	"code.cloudfoundry.org/korifi/api/actions/shared/fake"
+	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -18,16 +19,18 @@ import (
 
 var _ = Describe("Applier", func() {
 	var (
-		appRepo     *fake.CFAppRepository
-		domainRepo  *fake.CFDomainRepository
-		processRepo *fake.CFProcessRepository
-		routeRepo   *fake.CFRouteRepository
-		applier     *manifest.Applier
-		applierErr  error
-		ctx         context.Context
-		authInfo    authorization.Info
-		appInfo     payloads.ManifestApplication
-		appState    manifest.AppState
+		appRepo      *fake.CFAppRepository
+		domainRepo   *fake.CFDomainRepository
+		processRepo  *fake.CFProcessRepository
+		routeRepo    *fake.CFRouteRepository
+		instanceRepo *fake.CFServiceInstanceRepository
+		bindingRepo  *fake.CFServiceBindingRepository
+		applier      *manifest.Applier
+		applierErr   error
+		ctx          context.Context
+		authInfo     authorization.Info
+		appInfo      payloads.ManifestApplication
+		appState     manifest.AppState
 	)
 
 	BeforeEach(func() {
@@ -35,7 +38,9 @@ var _ = Describe("Applier", func() {
 		domainRepo = new(fake.CFDomainRepository)
 		processRepo = new(fake.CFProcessRepository)
 		routeRepo = new(fake.CFRouteRepository)
-		applier = manifest.NewApplier(appRepo, domainRepo, processRepo, routeRepo)
+		instanceRepo = new(fake.CFServiceInstanceRepository)
+		bindingRepo = new(fake.CFServiceBindingRepository)
+		applier = manifest.NewApplier(appRepo, domainRepo, processRepo, routeRepo, instanceRepo, bindingRepo)
 		ctx = context.Background()
 		authInfo = authorization.Info{Token: "a-token"}
 		appInfo = payloads.ManifestApplication{
@@ -437,4 +442,72 @@ var _ = Describe("Applier", func() {
 			})
 		})
 	})
+
+	Describe("applying service bindings", func() {
+		BeforeEach(func() {
+			appState.App.GUID = "app-guid"
+			appState.App.SpaceGUID = "space-guid"
+			appState.ServiceBindings = map[string]repositories.ServiceBindingRecord{
+				"bound-instance": {GUID: "binding-guid"},
+			}
+			appInfo.Services = []payloads.ManifestApplicationService{
+				{Name: "bound-instance"},
+				{Name: "new-instance", BindingName: tools.PtrTo("my-binding")},
+			}
+			instanceRepo.ListServiceInstancesReturns([]repositories.ServiceInstanceRecord{
+				{GUID: "new-instance-guid", Name: "new-instance"},
+			}, nil)
+		})
+
+		It("looks up the service instances that are not bound yet", func() {
+			Expect(applierErr).NotTo(HaveOccurred())
+			Expect(instanceRepo.ListServiceInstancesCallCount()).To(Equal(1))
+			_, _, listMsg := instanceRepo.ListServiceInstancesArgsForCall(0)
+			Expect(listMsg.Names).To(ConsistOf("new-instance"))
+			Expect(listMsg.SpaceGuids).To(ConsistOf("space-guid"))
+		})
+
+		It("binds the app to them", func() {
+			Expect(bindingRepo.CreateServiceBindingCallCount()).To(Equal(1))
+			_, actualAuthInfo, createMsg := bindingRepo.CreateServiceBindingArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(createMsg).To(Equal(repositories.CreateServiceBindingMessage{
+				Name:                tools.PtrTo("my-binding"),
+				ServiceInstanceGUID: "new-instance-guid",
+				AppGUID:             "app-guid",
+				SpaceGUID:           "space-guid",
+			}))
+		})
+
+		When("the service instance does not exist", func() {
+			BeforeEach(func() {
+				instanceRepo.ListServiceInstancesReturns(nil, nil)
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(applierErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+				Expect(bindingRepo.CreateServiceBindingCallCount()).To(BeZero())
+			})
+		})
+
+		When("listing the service instances fails", func() {
+			BeforeEach(func() {
+				instanceRepo.ListServiceInstancesReturns(nil, errors.New("list-instances-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(applierErr).To(MatchError(ContainSubstring("list-instances-error")))
+			})
+		})
+
+		When("creating the service binding fails", func() {
+			BeforeEach(func() {
+				bindingRepo.CreateServiceBindingReturns(repositories.ServiceBindingRecord{}, errors.New("create-binding-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(applierErr).To(MatchError(ContainSubstring("create-binding-error")))
+			})
+		})
+	})
 })
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+)
+
+// Export builds the manifest application that describes the current state of an app. It is the inverse of
+// applying a manifest: normalizing and applying the result to the same app does not change it.
+func Export(appState AppState, envVars map[string]string) payloads.ManifestApplication {
+	appInfo := payloads.ManifestApplication{
+		Name:       appState.App.Name,
+		Env:        envVars,
+		Buildpacks: appState.App.Lifecycle.Data.Buildpacks,
+		Processes:  exportProcesses(appState.Processes),
+		Routes:     exportRoutes(appState.Routes),
+		Services:   exportServices(appState.ServiceBindings),
+	}
+
+	if len(appInfo.Env) == 0 {
+		appInfo.Env = nil
+	}
+	if len(appInfo.Routes) == 0 {
+		appInfo.NoRoute = true
+	}
+
+	return appInfo
+}
+
+func exportProcesses(processes map[string]repositories.ProcessRecord) []payloads.ManifestApplicationProcess {
+	result := []payloads.ManifestApplicationProcess{}
+	for _, p := range processes {
+		process := payloads.ManifestApplicationProcess{
+			Type:      p.Type,
+			Instances: tools.PtrTo(p.DesiredInstances),
+			Memory:    tools.PtrTo(fmt.Sprintf("%dM", p.MemoryMB)),
+			DiskQuota: tools.PtrTo(fmt.Sprintf("%dM", p.DiskQuotaMB)),
+		}
+		if p.Command != "" {
+			process.Command = tools.PtrTo(p.Command)
+		}
+		if p.HealthCheck.Type != "" {
+			process.HealthCheckType = tools.PtrTo(p.HealthCheck.Type)
+		}
+		if p.HealthCheck.Data.HTTPEndpoint != "" {
+			process.HealthCheckHTTPEndpoint = tools.PtrTo(p.HealthCheck.Data.HTTPEndpoint)
+		}
+		// zero means the timeouts have never been set and would not pass manifest validation
+		if p.HealthCheck.Data.InvocationTimeoutSeconds != 0 {
+			process.HealthCheckInvocationTimeout = tools.PtrTo(p.HealthCheck.Data.InvocationTimeoutSeconds)
+		}
+		if p.HealthCheck.Data.TimeoutSeconds != 0 {
+			process.Timeout = tools.PtrTo(p.HealthCheck.Data.TimeoutSeconds)
+		}
+
+		result = append(result, process)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+func exportRoutes(routes map[string]repositories.RouteRecord) []payloads.ManifestRoute {
+	routeStrings := []string{}
+	for _, r := range routes {
+		routeStrings = append(routeStrings, unsplitRoute(r))
+	}
+	sort.Strings(routeStrings)
+
+	result := []payloads.ManifestRoute{}
+	for i := range routeStrings {
+		result = append(result, payloads.ManifestRoute{Route: &routeStrings[i]})
+	}
+
+	return result
+}
+
+func exportServices(bindings map[string]repositories.ServiceBindingRecord) []payloads.ManifestApplicationService {
+	result := []payloads.ManifestApplicationService{}
+	for instanceName, binding := range bindings {
+		result = append(result, payloads.ManifestApplicationService{
+			Name:        instanceName,
+			BindingName: binding.Name,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,187 @@
+package manifest_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/actions/manifest"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		appState manifest.AppState
+		envVars  map[string]string
+		appInfo  payloads.ManifestApplication
+	)
+
+	BeforeEach(func() {
+		appState = manifest.AppState{
+			App: repositories.AppRecord{
+				GUID:      "app-guid",
+				Name:      "my-app",
+				SpaceGUID: "space-guid",
+				Lifecycle: repositories.Lifecycle{
+					Data: repositories.LifecycleData{
+						Buildpacks: []string{"buildpack-a"},
+					},
+				},
+			},
+			Processes: map[string]repositories.ProcessRecord{
+				"worker": {
+					GUID:             "worker-guid",
+					Type:             "worker",
+					Command:          "bundle exec work",
+					DesiredInstances: 2,
+					MemoryMB:         512,
+					DiskQuotaMB:      1024,
+					HealthCheck:      repositories.HealthCheck{Type: "process"},
+				},
+				"web": {
+					GUID:             "web-guid",
+					Type:             "web",
+					Command:          "bundle exec rackup",
+					DesiredInstances: 1,
+					MemoryMB:         256,
+					DiskQuotaMB:      128,
+					HealthCheck: repositories.HealthCheck{
+						Type: "http",
+						Data: repositories.HealthCheckData{
+							HTTPEndpoint:             "/health",
+							InvocationTimeoutSeconds: 5,
+							TimeoutSeconds:           60,
+						},
+					},
+				},
+			},
+			Routes: map[string]repositories.RouteRecord{
+				"my-app.my.domain/path": {Host: "my-app", Path: "/path", Domain: repositories.DomainRecord{Name: "my.domain"}},
+				"another.my.domain":     {Host: "another", Domain: repositories.DomainRecord{Name: "my.domain"}},
+			},
+			ServiceBindings: map[string]repositories.ServiceBindingRecord{
+				"my-db":    {GUID: "db-binding-guid", Name: tools.PtrTo("db")},
+				"my-cache": {GUID: "cache-binding-guid"},
+			},
+		}
+		envVars = map[string]string{"FOO": "bar"}
+	})
+
+	JustBeforeEach(func() {
+		appInfo = manifest.Export(appState, envVars)
+	})
+
+	It("exports the app", func() {
+		Expect(appInfo.Name).To(Equal("my-app"))
+		Expect(appInfo.Env).To(Equal(map[string]string{"FOO": "bar"}))
+		Expect(appInfo.Buildpacks).To(Equal([]string{"buildpack-a"}))
+		Expect(appInfo.NoRoute).To(BeFalse())
+	})
+
+	It("exports the processes sorted by type", func() {
+		Expect(appInfo.Processes).To(Equal([]payloads.ManifestApplicationProcess{
+			{
+				Type:                         "web",
+				Command:                      tools.PtrTo("bundle exec rackup"),
+				Instances:                    tools.PtrTo(1),
+				Memory:                       tools.PtrTo("256M"),
+				DiskQuota:                    tools.PtrTo("128M"),
+				HealthCheckType:              tools.PtrTo("http"),
+				HealthCheckHTTPEndpoint:      tools.PtrTo("/health"),
+				HealthCheckInvocationTimeout: tools.PtrTo(int64(5)),
+				Timeout:                      tools.PtrTo(int64(60)),
+			},
+			{
+				Type:            "worker",
+				Command:         tools.PtrTo("bundle exec work"),
+				Instances:       tools.PtrTo(2),
+				Memory:          tools.PtrTo("512M"),
+				DiskQuota:       tools.PtrTo("1024M"),
+				HealthCheckType: tools.PtrTo("process"),
+			},
+		}))
+	})
+
+	It("exports the routes sorted", func() {
+		Expect(appInfo.Routes).To(Equal([]payloads.ManifestRoute{
+			{Route: tools.PtrTo("another.my.domain")},
+			{Route: tools.PtrTo("my-app.my.domain/path")},
+		}))
+	})
+
+	It("exports the services sorted by instance name", func() {
+		Expect(appInfo.Services).To(Equal([]payloads.ManifestApplicationService{
+			{Name: "my-cache"},
+			{Name: "my-db", BindingName: tools.PtrTo("db")},
+		}))
+	})
+
+	When("the app has no routes", func() {
+		BeforeEach(func() {
+			appState.Routes = map[string]repositories.RouteRecord{}
+		})
+
+		It("sets no-route", func() {
+			Expect(appInfo.Routes).To(BeEmpty())
+			Expect(appInfo.NoRoute).To(BeTrue())
+		})
+	})
+
+	When("the app has no env vars", func() {
+		BeforeEach(func() {
+			envVars = map[string]string{}
+		})
+
+		It("omits them", func() {
+			Expect(appInfo.Env).To(BeNil())
+		})
+	})
+
+	Describe("round trip", func() {
+		var normalizedAppInfo payloads.ManifestApplication
+
+		JustBeforeEach(func() {
+			normalizedAppInfo = manifest.NewNormalizer("my.domain").Normalize(appInfo, appState)
+		})
+
+		It("does not add routes or service bindings", func() {
+			for _, route := range normalizedAppInfo.Routes {
+				Expect(appState.Routes).To(HaveKey(*route.Route))
+			}
+			for _, service := range normalizedAppInfo.Services {
+				Expect(appState.ServiceBindings).To(HaveKey(service.Name))
+			}
+		})
+
+		It("patches the app with its current state", func() {
+			patchMsg := normalizedAppInfo.ToAppPatchMessage("app-guid", "space-guid")
+			Expect(patchMsg.Name).To(Equal(appState.App.Name))
+			Expect(patchMsg.Lifecycle.Data.Buildpacks).To(Equal(appState.App.Lifecycle.Data.Buildpacks))
+			Expect(patchMsg.EnvironmentVariables).To(Equal(envVars))
+		})
+
+		It("patches the processes with their current state", func() {
+			Expect(normalizedAppInfo.Processes).To(HaveLen(len(appState.Processes)))
+			for _, process := range normalizedAppInfo.Processes {
+				current := appState.Processes[process.Type]
+				patchMsg := process.ToProcessPatchMessage(current.GUID, "space-guid")
+
+				Expect(*patchMsg.Command).To(Equal(current.Command))
+				Expect(*patchMsg.DesiredInstances).To(Equal(current.DesiredInstances))
+				Expect(*patchMsg.MemoryMB).To(Equal(current.MemoryMB))
+				Expect(*patchMsg.DiskQuotaMB).To(Equal(current.DiskQuotaMB))
+				Expect(*patchMsg.HealthCheckType).To(Equal(current.HealthCheck.Type))
+				if patchMsg.HealthCheckHTTPEndpoint != nil {
+					Expect(*patchMsg.HealthCheckHTTPEndpoint).To(Equal(current.HealthCheck.Data.HTTPEndpoint))
+				}
+				if patchMsg.HealthCheckInvocationTimeoutSeconds != nil {
+					Expect(*patchMsg.HealthCheckInvocationTimeoutSeconds).To(Equal(current.HealthCheck.Data.InvocationTimeoutSeconds))
+				}
+				if patchMsg.HealthCheckTimeoutSeconds != nil {
+					Expect(*patchMsg.HealthCheckTimeoutSeconds).To(Equal(current.HealthCheck.Data.TimeoutSeconds))
+				}
+			}
+		})
+	})
+})
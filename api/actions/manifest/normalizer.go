@@ -31,6 +31,7 @@ func (n Normalizer) Normalize(appInfo payloads.ManifestApplication, appState App
 		Processes:  processes,
 		Routes:     routes,
 		NoRoute:    appInfo.NoRoute,
+		Services:   appInfo.Services,
 	}
 }
 
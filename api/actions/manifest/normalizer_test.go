@@ -42,6 +42,7 @@ var _ = Describe("Normalizer", func() {
 			Name:       "my-app",
 			Env:        map[string]string{"FOO": "bar"},
 			Buildpacks: []string{"buildpack-one", "buildpack-two"},
+			Services:   []payloads.ManifestApplicationService{{Name: "my-instance"}},
 		}
 		appState = manifest.AppState{
 			App:       repositories.AppRecord{},
@@ -61,6 +62,7 @@ var _ = Describe("Normalizer", func() {
 			Expect(normalizedAppInfo.NoRoute).To(Equal(appInfo.NoRoute))
 			Expect(normalizedAppInfo.Env).To(Equal(appInfo.Env))
 			Expect(normalizedAppInfo.Buildpacks).To(Equal(appInfo.Buildpacks))
+			Expect(normalizedAppInfo.Services).To(Equal(appInfo.Services))
 		})
 
 		When("no-route is set", func() {
@@ -13,16 +13,20 @@ import (
 )
 
 type StateCollector struct {
-	appRepo     shared.CFAppRepository
-	domainRepo  shared.CFDomainRepository
-	processRepo shared.CFProcessRepository
-	routeRepo   shared.CFRouteRepository
+	appRepo             shared.CFAppRepository
+	domainRepo          shared.CFDomainRepository
+	processRepo         shared.CFProcessRepository
+	routeRepo           shared.CFRouteRepository
+	serviceInstanceRepo shared.CFServiceInstanceRepository
+	serviceBindingRepo  shared.CFServiceBindingRepository
 }
 
 type AppState struct {
 	App       repositories.AppRecord
 	Processes map[string]repositories.ProcessRecord
 	Routes    map[string]repositories.RouteRecord
+	// ServiceBindings are keyed by the name of the bound service instance
+	ServiceBindings map[string]repositories.ServiceBindingRecord
 }
 
 func NewStateCollector(
@@ -30,12 +34,16 @@ func NewStateCollector(
 	domainRepo shared.CFDomainRepository,
 	processRepo shared.CFProcessRepository,
 	routeRepo shared.CFRouteRepository,
+	serviceInstanceRepo shared.CFServiceInstanceRepository,
+	serviceBindingRepo shared.CFServiceBindingRepository,
 ) StateCollector {
 	return StateCollector{
-		appRepo:     appRepo,
-		domainRepo:  domainRepo,
-		processRepo: processRepo,
-		routeRepo:   routeRepo,
+		appRepo:             appRepo,
+		domainRepo:          domainRepo,
+		processRepo:         processRepo,
+		routeRepo:           routeRepo,
+		serviceInstanceRepo: serviceInstanceRepo,
+		serviceBindingRepo:  serviceBindingRepo,
 	}
 }
 
@@ -47,6 +55,7 @@ func (s StateCollector) CollectState(ctx context.Context, authInfo authorization
 
 	existingProcesses := map[string]repositories.ProcessRecord{}
 	existingAppRoutes := map[string]repositories.RouteRecord{}
+	existingServiceBindings := map[string]repositories.ServiceBindingRecord{}
 	if appRecord.GUID != "" {
 		procs, err := s.processRepo.ListProcesses(ctx, authInfo, repositories.ListProcessesMessage{
 			AppGUIDs:  []string{appRecord.GUID},
@@ -67,15 +76,55 @@ func (s StateCollector) CollectState(ctx context.Context, authInfo authorization
 		for _, r := range routes {
 			existingAppRoutes[unsplitRoute(r)] = r
 		}
+
+		existingServiceBindings, err = s.collectServiceBindings(ctx, authInfo, appRecord.GUID, spaceGUID)
+		if err != nil {
+			return AppState{}, err
+		}
 	}
 
 	return AppState{
-		App:       appRecord,
-		Processes: existingProcesses,
-		Routes:    existingAppRoutes,
+		App:             appRecord,
+		Processes:       existingProcesses,
+		Routes:          existingAppRoutes,
+		ServiceBindings: existingServiceBindings,
 	}, nil
 }
 
+func (s StateCollector) collectServiceBindings(ctx context.Context, authInfo authorization.Info, appGUID, spaceGUID string) (map[string]repositories.ServiceBindingRecord, error) {
+	result := map[string]repositories.ServiceBindingRecord{}
+
+	bindings, err := s.serviceBindingRepo.ListServiceBindings(ctx, authInfo, repositories.ListServiceBindingsMessage{
+		AppGUIDs: []string{appGUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) == 0 {
+		return result, nil
+	}
+
+	instances, err := s.serviceInstanceRepo.ListServiceInstances(ctx, authInfo, repositories.ListServiceInstanceMessage{
+		SpaceGuids: []string{spaceGUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instanceNames := map[string]string{}
+	for _, instance := range instances {
+		instanceNames[instance.GUID] = instance.Name
+	}
+
+	for _, binding := range bindings {
+		if name, ok := instanceNames[binding.ServiceInstanceGUID]; ok {
+			result[name] = binding
+		}
+	}
+
+	return result, nil
+}
+
 func unsplitRoute(route repositories.RouteRecord) string {
 	return path.Join(fmt.Sprintf("%s.%s", route.Host, route.Domain.Name), route.Path)
 }
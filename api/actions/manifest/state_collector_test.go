@@ -12,6 +12,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
 )
 
 var _ = Describe("StateCollector", func() {
@@ -20,6 +21,8 @@ var _ = Describe("StateCollector", func() {
 		domainRepo      *fake.CFDomainRepository
 		processRepo     *fake.CFProcessRepository
 		routeRepo       *fake.CFRouteRepository
+		instanceRepo    *fake.CFServiceInstanceRepository
+		bindingRepo     *fake.CFServiceBindingRepository
 		stateCollector  manifest.StateCollector
 		appState        manifest.AppState
 		collectStateErr error
@@ -30,11 +33,15 @@ var _ = Describe("StateCollector", func() {
 		domainRepo = new(fake.CFDomainRepository)
 		processRepo = new(fake.CFProcessRepository)
 		routeRepo = new(fake.CFRouteRepository)
+		instanceRepo = new(fake.CFServiceInstanceRepository)
+		bindingRepo = new(fake.CFServiceBindingRepository)
 		stateCollector = manifest.NewStateCollector(
 			appRepo,
 			domainRepo,
 			processRepo,
 			routeRepo,
+			instanceRepo,
+			bindingRepo,
 		)
 	})
 
@@ -183,4 +190,71 @@ var _ = Describe("StateCollector", func() {
 			}))
 		})
 	})
+
+	Describe("service bindings", func() {
+		BeforeEach(func() {
+			appRepo.GetAppByNameAndSpaceReturns(repositories.AppRecord{GUID: "app-guid"}, nil)
+			bindingRepo.ListServiceBindingsReturns([]repositories.ServiceBindingRecord{
+				{GUID: "binding-1-guid", ServiceInstanceGUID: "instance-1-guid"},
+				{GUID: "binding-2-guid", ServiceInstanceGUID: "instance-2-guid", Name: tools.PtrTo("my-binding")},
+			}, nil)
+			instanceRepo.ListServiceInstancesReturns([]repositories.ServiceInstanceRecord{
+				{GUID: "instance-1-guid", Name: "instance-1"},
+				{GUID: "instance-2-guid", Name: "instance-2"},
+				{GUID: "instance-3-guid", Name: "instance-3"},
+			}, nil)
+		})
+
+		It("lists the app service bindings", func() {
+			Expect(bindingRepo.ListServiceBindingsCallCount()).To(Equal(1))
+			_, _, listMsg := bindingRepo.ListServiceBindingsArgsForCall(0)
+			Expect(listMsg.AppGUIDs).To(ConsistOf("app-guid"))
+		})
+
+		It("lists the service instances in the space", func() {
+			Expect(instanceRepo.ListServiceInstancesCallCount()).To(Equal(1))
+			_, _, listMsg := instanceRepo.ListServiceInstancesArgsForCall(0)
+			Expect(listMsg.SpaceGuids).To(ConsistOf("space-guid"))
+		})
+
+		It("populates the service bindings map using the service instance names", func() {
+			Expect(collectStateErr).NotTo(HaveOccurred())
+			Expect(appState.ServiceBindings).To(Equal(map[string]repositories.ServiceBindingRecord{
+				"instance-1": {GUID: "binding-1-guid", ServiceInstanceGUID: "instance-1-guid"},
+				"instance-2": {GUID: "binding-2-guid", ServiceInstanceGUID: "instance-2-guid", Name: tools.PtrTo("my-binding")},
+			}))
+		})
+
+		When("the app has no service bindings", func() {
+			BeforeEach(func() {
+				bindingRepo.ListServiceBindingsReturns(nil, nil)
+			})
+
+			It("does not list the service instances", func() {
+				Expect(collectStateErr).NotTo(HaveOccurred())
+				Expect(appState.ServiceBindings).To(BeEmpty())
+				Expect(instanceRepo.ListServiceInstancesCallCount()).To(BeZero())
+			})
+		})
+
+		When("listing the service bindings fails", func() {
+			BeforeEach(func() {
+				bindingRepo.ListServiceBindingsReturns(nil, errors.New("list-bindings-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(collectStateErr).To(MatchError("list-bindings-error"))
+			})
+		})
+
+		When("listing the service instances fails", func() {
+			BeforeEach(func() {
+				instanceRepo.ListServiceInstancesReturns(nil, errors.New("list-instances-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(collectStateErr).To(MatchError("list-instances-error"))
+			})
+		})
+	})
 })
@@ -21,6 +21,7 @@ var _ = Describe("ApplyManifest", func() {
 		manifestAction *actions.Manifest
 		applyErr       error
 
+		appRepository      *reposfake.CFAppRepository
 		domainRepository   *reposfake.CFDomainRepository
 		stateCollector     *fake.StateCollector
 		normalizer         *fake.Normalizer
@@ -32,6 +33,7 @@ var _ = Describe("ApplyManifest", func() {
 	)
 
 	BeforeEach(func() {
+		appRepository = new(reposfake.CFAppRepository)
 		domainRepository = new(reposfake.CFDomainRepository)
 		stateCollector = new(fake.StateCollector)
 		normalizer = new(fake.Normalizer)
@@ -56,7 +58,7 @@ var _ = Describe("ApplyManifest", func() {
 			}},
 		}
 
-		manifestAction = actions.NewManifest(domainRepository, "my.domain", stateCollector, normalizer, applier)
+		manifestAction = actions.NewManifest(appRepository, domainRepository, "my.domain", stateCollector, normalizer, applier)
 	})
 
 	JustBeforeEach(func() {
@@ -135,3 +137,86 @@ var _ = Describe("ApplyManifest", func() {
 		})
 	})
 })
+
+var _ = Describe("ExportManifest", func() {
+	var (
+		manifestAction *actions.Manifest
+		appRepository  *reposfake.CFAppRepository
+		stateCollector *fake.StateCollector
+
+		exportedManifest payloads.Manifest
+		exportErr        error
+	)
+
+	BeforeEach(func() {
+		appRepository = new(reposfake.CFAppRepository)
+		stateCollector = new(fake.StateCollector)
+
+		appRepository.GetAppReturns(repositories.AppRecord{
+			GUID:      "app-guid",
+			Name:      "app-name",
+			SpaceGUID: "space-guid",
+		}, nil)
+		appRepository.GetAppEnvReturns(repositories.AppEnvRecord{
+			EnvironmentVariables: map[string]string{"FOO": "bar"},
+		}, nil)
+		stateCollector.CollectStateReturns(manifest.AppState{
+			App: repositories.AppRecord{
+				GUID: "app-guid",
+				Name: "app-name",
+			},
+		}, nil)
+
+		manifestAction = actions.NewManifest(appRepository, new(reposfake.CFDomainRepository), "my.domain", stateCollector, new(fake.Normalizer), new(fake.Applier))
+	})
+
+	JustBeforeEach(func() {
+		exportedManifest, exportErr = manifestAction.Export(context.Background(), authorization.Info{}, "app-guid")
+	})
+
+	It("exports the app state as a manifest", func() {
+		Expect(exportErr).NotTo(HaveOccurred())
+		Expect(exportedManifest.Version).To(Equal(1))
+		Expect(exportedManifest.Applications).To(HaveLen(1))
+		Expect(exportedManifest.Applications[0].Name).To(Equal("app-name"))
+		Expect(exportedManifest.Applications[0].Env).To(Equal(map[string]string{"FOO": "bar"}))
+		Expect(exportedManifest.Applications[0].NoRoute).To(BeTrue())
+	})
+
+	It("collects the state of the app in its space", func() {
+		Expect(stateCollector.CollectStateCallCount()).To(Equal(1))
+		_, _, actualAppName, actualSpaceGUID := stateCollector.CollectStateArgsForCall(0)
+		Expect(actualAppName).To(Equal("app-name"))
+		Expect(actualSpaceGUID).To(Equal("space-guid"))
+	})
+
+	When("the app is not accessible", func() {
+		BeforeEach(func() {
+			appRepository.GetAppReturns(repositories.AppRecord{}, apierrors.NewForbiddenError(nil, repositories.AppResourceType))
+		})
+
+		It("returns a not found error", func() {
+			Expect(exportErr).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+		})
+	})
+
+	When("collecting the app state fails", func() {
+		BeforeEach(func() {
+			stateCollector.CollectStateReturns(manifest.AppState{}, errors.New("collect-state-err"))
+		})
+
+		It("returns the error", func() {
+			Expect(exportErr).To(MatchError("collect-state-err"))
+		})
+	})
+
+	When("getting the app env fails", func() {
+		BeforeEach(func() {
+			appRepository.GetAppEnvReturns(repositories.AppEnvRecord{}, errors.New("get-env-err"))
+		})
+
+		It("returns the error", func() {
+			Expect(exportErr).To(MatchError("get-env-err"))
+		})
+	})
+})
@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/actions/shared"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type CFServiceBindingRepository struct {
+	CreateServiceBindingStub        func(context.Context, authorization.Info, repositories.CreateServiceBindingMessage) (repositories.ServiceBindingRecord, error)
+	createServiceBindingMutex       sync.RWMutex
+	createServiceBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.CreateServiceBindingMessage
+	}
+	createServiceBindingReturns struct {
+		result1 repositories.ServiceBindingRecord
+		result2 error
+	}
+	createServiceBindingReturnsOnCall map[int]struct {
+		result1 repositories.ServiceBindingRecord
+		result2 error
+	}
+	ListServiceBindingsStub        func(context.Context, authorization.Info, repositories.ListServiceBindingsMessage) ([]repositories.ServiceBindingRecord, error)
+	listServiceBindingsMutex       sync.RWMutex
+	listServiceBindingsArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListServiceBindingsMessage
+	}
+	listServiceBindingsReturns struct {
+		result1 []repositories.ServiceBindingRecord
+		result2 error
+	}
+	listServiceBindingsReturnsOnCall map[int]struct {
+		result1 []repositories.ServiceBindingRecord
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBinding(arg1 context.Context, arg2 authorization.Info, arg3 repositories.CreateServiceBindingMessage) (repositories.ServiceBindingRecord, error) {
+	fake.createServiceBindingMutex.Lock()
+	ret, specificReturn := fake.createServiceBindingReturnsOnCall[len(fake.createServiceBindingArgsForCall)]
+	fake.createServiceBindingArgsForCall = append(fake.createServiceBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.CreateServiceBindingMessage
+	}{arg1, arg2, arg3})
+	stub := fake.CreateServiceBindingStub
+	fakeReturns := fake.createServiceBindingReturns
+	fake.recordInvocation("CreateServiceBinding", []interface{}{arg1, arg2, arg3})
+	fake.createServiceBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBindingCallCount() int {
+	fake.createServiceBindingMutex.RLock()
+	defer fake.createServiceBindingMutex.RUnlock()
+	return len(fake.createServiceBindingArgsForCall)
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBindingCalls(stub func(context.Context, authorization.Info, repositories.CreateServiceBindingMessage) (repositories.ServiceBindingRecord, error)) {
+	fake.createServiceBindingMutex.Lock()
+	defer fake.createServiceBindingMutex.Unlock()
+	fake.CreateServiceBindingStub = stub
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBindingArgsForCall(i int) (context.Context, authorization.Info, repositories.CreateServiceBindingMessage) {
+	fake.createServiceBindingMutex.RLock()
+	defer fake.createServiceBindingMutex.RUnlock()
+	argsForCall := fake.createServiceBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBindingReturns(result1 repositories.ServiceBindingRecord, result2 error) {
+	fake.createServiceBindingMutex.Lock()
+	defer fake.createServiceBindingMutex.Unlock()
+	fake.CreateServiceBindingStub = nil
+	fake.createServiceBindingReturns = struct {
+		result1 repositories.ServiceBindingRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFServiceBindingRepository) CreateServiceBindingReturnsOnCall(i int, result1 repositories.ServiceBindingRecord, result2 error) {
+	fake.createServiceBindingMutex.Lock()
+	defer fake.createServiceBindingMutex.Unlock()
+	fake.CreateServiceBindingStub = nil
+	if fake.createServiceBindingReturnsOnCall == nil {
+		fake.createServiceBindingReturnsOnCall = make(map[int]struct {
+			result1 repositories.ServiceBindingRecord
+			result2 error
+		})
+	}
+	fake.createServiceBindingReturnsOnCall[i] = struct {
+		result1 repositories.ServiceBindingRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindings(arg1 context.Context, arg2 authorization.Info, arg3 repositories.ListServiceBindingsMessage) ([]repositories.ServiceBindingRecord, error) {
+	fake.listServiceBindingsMutex.Lock()
+	ret, specificReturn := fake.listServiceBindingsReturnsOnCall[len(fake.listServiceBindingsArgsForCall)]
+	fake.listServiceBindingsArgsForCall = append(fake.listServiceBindingsArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListServiceBindingsMessage
+	}{arg1, arg2, arg3})
+	stub := fake.ListServiceBindingsStub
+	fakeReturns := fake.listServiceBindingsReturns
+	fake.recordInvocation("ListServiceBindings", []interface{}{arg1, arg2, arg3})
+	fake.listServiceBindingsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindingsCallCount() int {
+	fake.listServiceBindingsMutex.RLock()
+	defer fake.listServiceBindingsMutex.RUnlock()
+	return len(fake.listServiceBindingsArgsForCall)
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindingsCalls(stub func(context.Context, authorization.Info, repositories.ListServiceBindingsMessage) ([]repositories.ServiceBindingRecord, error)) {
+	fake.listServiceBindingsMutex.Lock()
+	defer fake.listServiceBindingsMutex.Unlock()
+	fake.ListServiceBindingsStub = stub
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindingsArgsForCall(i int) (context.Context, authorization.Info, repositories.ListServiceBindingsMessage) {
+	fake.listServiceBindingsMutex.RLock()
+	defer fake.listServiceBindingsMutex.RUnlock()
+	argsForCall := fake.listServiceBindingsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindingsReturns(result1 []repositories.ServiceBindingRecord, result2 error) {
+	fake.listServiceBindingsMutex.Lock()
+	defer fake.listServiceBindingsMutex.Unlock()
+	fake.ListServiceBindingsStub = nil
+	fake.listServiceBindingsReturns = struct {
+		result1 []repositories.ServiceBindingRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFServiceBindingRepository) ListServiceBindingsReturnsOnCall(i int, result1 []repositories.ServiceBindingRecord, result2 error) {
+	fake.listServiceBindingsMutex.Lock()
+	defer fake.listServiceBindingsMutex.Unlock()
+	fake.ListServiceBindingsStub = nil
+	if fake.listServiceBindingsReturnsOnCall == nil {
+		fake.listServiceBindingsReturnsOnCall = make(map[int]struct {
+			result1 []repositories.ServiceBindingRecord
+			result2 error
+		})
+	}
+	fake.listServiceBindingsReturnsOnCall[i] = struct {
+		result1 []repositories.ServiceBindingRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFServiceBindingRepository) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createServiceBindingMutex.RLock()
+	defer fake.createServiceBindingMutex.RUnlock()
+	fake.listServiceBindingsMutex.RLock()
+	defer fake.listServiceBindingsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CFServiceBindingRepository) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ shared.CFServiceBindingRepository = new(CFServiceBindingRepository)
@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/actions/shared"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type LogStore struct {
+	ReadStub        func(context.Context, string) ([]repositories.LogRecord, error)
+	readMutex       sync.RWMutex
+	readArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	readReturns struct {
+		result1 []repositories.LogRecord
+		result2 error
+	}
+	readReturnsOnCall map[int]struct {
+		result1 []repositories.LogRecord
+		result2 error
+	}
+	SubscribeStub        func(context.Context, string) (<-chan repositories.LogRecord, func(), error)
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	subscribeReturns struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}
+	subscribeReturnsOnCall map[int]struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *LogStore) Read(arg1 context.Context, arg2 string) ([]repositories.LogRecord, error) {
+	fake.readMutex.Lock()
+	ret, specificReturn := fake.readReturnsOnCall[len(fake.readArgsForCall)]
+	fake.readArgsForCall = append(fake.readArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReadStub
+	fakeReturns := fake.readReturns
+	fake.recordInvocation("Read", []interface{}{arg1, arg2})
+	fake.readMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *LogStore) ReadCallCount() int {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return len(fake.readArgsForCall)
+}
+
+func (fake *LogStore) ReadCalls(stub func(context.Context, string) ([]repositories.LogRecord, error)) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = stub
+}
+
+func (fake *LogStore) ReadArgsForCall(i int) (context.Context, string) {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	argsForCall := fake.readArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *LogStore) ReadReturns(result1 []repositories.LogRecord, result2 error) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = nil
+	fake.readReturns = struct {
+		result1 []repositories.LogRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *LogStore) ReadReturnsOnCall(i int, result1 []repositories.LogRecord, result2 error) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = nil
+	if fake.readReturnsOnCall == nil {
+		fake.readReturnsOnCall = make(map[int]struct {
+			result1 []repositories.LogRecord
+			result2 error
+		})
+	}
+	fake.readReturnsOnCall[i] = struct {
+		result1 []repositories.LogRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *LogStore) Subscribe(arg1 context.Context, arg2 string) (<-chan repositories.LogRecord, func(), error) {
+	fake.subscribeMutex.Lock()
+	ret, specificReturn := fake.subscribeReturnsOnCall[len(fake.subscribeArgsForCall)]
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SubscribeStub
+	fakeReturns := fake.subscribeReturns
+	fake.recordInvocation("Subscribe", []interface{}{arg1, arg2})
+	fake.subscribeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *LogStore) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *LogStore) SubscribeCalls(stub func(context.Context, string) (<-chan repositories.LogRecord, func(), error)) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = stub
+}
+
+func (fake *LogStore) SubscribeArgsForCall(i int) (context.Context, string) {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	argsForCall := fake.subscribeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *LogStore) SubscribeReturns(result1 <-chan repositories.LogRecord, result2 func(), result3 error) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *LogStore) SubscribeReturnsOnCall(i int, result1 <-chan repositories.LogRecord, result2 func(), result3 error) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	if fake.subscribeReturnsOnCall == nil {
+		fake.subscribeReturnsOnCall = make(map[int]struct {
+			result1 <-chan repositories.LogRecord
+			result2 func()
+			result3 error
+		})
+	}
+	fake.subscribeReturnsOnCall[i] = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *LogStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *LogStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ shared.LogStore = new(LogStore)
@@ -8,24 +8,22 @@ import (
 	"code.cloudfoundry.org/korifi/api/actions/shared"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/repositories"
-	"github.com/go-logr/logr"
 )
 
 type PodRepository struct {
-	GetRuntimeLogsForAppStub        func(context.Context, logr.Logger, authorization.Info, repositories.RuntimeLogsMessage) ([]repositories.LogRecord, error)
-	getRuntimeLogsForAppMutex       sync.RWMutex
-	getRuntimeLogsForAppArgsForCall []struct {
+	ListPodMetricsStub        func(context.Context, authorization.Info, repositories.ListPodMetricsMessage) ([]repositories.PodMetricsRecord, error)
+	listPodMetricsMutex       sync.RWMutex
+	listPodMetricsArgsForCall []struct {
 		arg1 context.Context
-		arg2 logr.Logger
-		arg3 authorization.Info
-		arg4 repositories.RuntimeLogsMessage
+		arg2 authorization.Info
+		arg3 repositories.ListPodMetricsMessage
 	}
-	getRuntimeLogsForAppReturns struct {
-		result1 []repositories.LogRecord
+	listPodMetricsReturns struct {
+		result1 []repositories.PodMetricsRecord
 		result2 error
 	}
-	getRuntimeLogsForAppReturnsOnCall map[int]struct {
-		result1 []repositories.LogRecord
+	listPodMetricsReturnsOnCall map[int]struct {
+		result1 []repositories.PodMetricsRecord
 		result2 error
 	}
 	ListPodStatsStub        func(context.Context, authorization.Info, repositories.ListPodStatsMessage) ([]repositories.PodStatsRecord, error)
@@ -47,21 +45,20 @@ type PodRepository struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PodRepository) GetRuntimeLogsForApp(arg1 context.Context, arg2 logr.Logger, arg3 authorization.Info, arg4 repositories.RuntimeLogsMessage) ([]repositories.LogRecord, error) {
-	fake.getRuntimeLogsForAppMutex.Lock()
-	ret, specificReturn := fake.getRuntimeLogsForAppReturnsOnCall[len(fake.getRuntimeLogsForAppArgsForCall)]
-	fake.getRuntimeLogsForAppArgsForCall = append(fake.getRuntimeLogsForAppArgsForCall, struct {
+func (fake *PodRepository) ListPodMetrics(arg1 context.Context, arg2 authorization.Info, arg3 repositories.ListPodMetricsMessage) ([]repositories.PodMetricsRecord, error) {
+	fake.listPodMetricsMutex.Lock()
+	ret, specificReturn := fake.listPodMetricsReturnsOnCall[len(fake.listPodMetricsArgsForCall)]
+	fake.listPodMetricsArgsForCall = append(fake.listPodMetricsArgsForCall, struct {
 		arg1 context.Context
-		arg2 logr.Logger
-		arg3 authorization.Info
-		arg4 repositories.RuntimeLogsMessage
-	}{arg1, arg2, arg3, arg4})
-	stub := fake.GetRuntimeLogsForAppStub
-	fakeReturns := fake.getRuntimeLogsForAppReturns
-	fake.recordInvocation("GetRuntimeLogsForApp", []interface{}{arg1, arg2, arg3, arg4})
-	fake.getRuntimeLogsForAppMutex.Unlock()
+		arg2 authorization.Info
+		arg3 repositories.ListPodMetricsMessage
+	}{arg1, arg2, arg3})
+	stub := fake.ListPodMetricsStub
+	fakeReturns := fake.listPodMetricsReturns
+	fake.recordInvocation("ListPodMetrics", []interface{}{arg1, arg2, arg3})
+	fake.listPodMetricsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -69,47 +66,47 @@ func (fake *PodRepository) GetRuntimeLogsForApp(arg1 context.Context, arg2 logr.
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *PodRepository) GetRuntimeLogsForAppCallCount() int {
-	fake.getRuntimeLogsForAppMutex.RLock()
-	defer fake.getRuntimeLogsForAppMutex.RUnlock()
-	return len(fake.getRuntimeLogsForAppArgsForCall)
+func (fake *PodRepository) ListPodMetricsCallCount() int {
+	fake.listPodMetricsMutex.RLock()
+	defer fake.listPodMetricsMutex.RUnlock()
+	return len(fake.listPodMetricsArgsForCall)
 }
 
-func (fake *PodRepository) GetRuntimeLogsForAppCalls(stub func(context.Context, logr.Logger, authorization.Info, repositories.RuntimeLogsMessage) ([]repositories.LogRecord, error)) {
-	fake.getRuntimeLogsForAppMutex.Lock()
-	defer fake.getRuntimeLogsForAppMutex.Unlock()
-	fake.GetRuntimeLogsForAppStub = stub
+func (fake *PodRepository) ListPodMetricsCalls(stub func(context.Context, authorization.Info, repositories.ListPodMetricsMessage) ([]repositories.PodMetricsRecord, error)) {
+	fake.listPodMetricsMutex.Lock()
+	defer fake.listPodMetricsMutex.Unlock()
+	fake.ListPodMetricsStub = stub
 }
 
-func (fake *PodRepository) GetRuntimeLogsForAppArgsForCall(i int) (context.Context, logr.Logger, authorization.Info, repositories.RuntimeLogsMessage) {
-	fake.getRuntimeLogsForAppMutex.RLock()
-	defer fake.getRuntimeLogsForAppMutex.RUnlock()
-	argsForCall := fake.getRuntimeLogsForAppArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+func (fake *PodRepository) ListPodMetricsArgsForCall(i int) (context.Context, authorization.Info, repositories.ListPodMetricsMessage) {
+	fake.listPodMetricsMutex.RLock()
+	defer fake.listPodMetricsMutex.RUnlock()
+	argsForCall := fake.listPodMetricsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *PodRepository) GetRuntimeLogsForAppReturns(result1 []repositories.LogRecord, result2 error) {
-	fake.getRuntimeLogsForAppMutex.Lock()
-	defer fake.getRuntimeLogsForAppMutex.Unlock()
-	fake.GetRuntimeLogsForAppStub = nil
-	fake.getRuntimeLogsForAppReturns = struct {
-		result1 []repositories.LogRecord
+func (fake *PodRepository) ListPodMetricsReturns(result1 []repositories.PodMetricsRecord, result2 error) {
+	fake.listPodMetricsMutex.Lock()
+	defer fake.listPodMetricsMutex.Unlock()
+	fake.ListPodMetricsStub = nil
+	fake.listPodMetricsReturns = struct {
+		result1 []repositories.PodMetricsRecord
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *PodRepository) GetRuntimeLogsForAppReturnsOnCall(i int, result1 []repositories.LogRecord, result2 error) {
-	fake.getRuntimeLogsForAppMutex.Lock()
-	defer fake.getRuntimeLogsForAppMutex.Unlock()
-	fake.GetRuntimeLogsForAppStub = nil
-	if fake.getRuntimeLogsForAppReturnsOnCall == nil {
-		fake.getRuntimeLogsForAppReturnsOnCall = make(map[int]struct {
-			result1 []repositories.LogRecord
+func (fake *PodRepository) ListPodMetricsReturnsOnCall(i int, result1 []repositories.PodMetricsRecord, result2 error) {
+	fake.listPodMetricsMutex.Lock()
+	defer fake.listPodMetricsMutex.Unlock()
+	fake.ListPodMetricsStub = nil
+	if fake.listPodMetricsReturnsOnCall == nil {
+		fake.listPodMetricsReturnsOnCall = make(map[int]struct {
+			result1 []repositories.PodMetricsRecord
 			result2 error
 		})
 	}
-	fake.getRuntimeLogsForAppReturnsOnCall[i] = struct {
-		result1 []repositories.LogRecord
+	fake.listPodMetricsReturnsOnCall[i] = struct {
+		result1 []repositories.PodMetricsRecord
 		result2 error
 	}{result1, result2}
 }
@@ -183,8 +180,8 @@ func (fake *PodRepository) ListPodStatsReturnsOnCall(i int, result1 []repositori
 func (fake *PodRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.getRuntimeLogsForAppMutex.RLock()
-	defer fake.getRuntimeLogsForAppMutex.RUnlock()
+	fake.listPodMetricsMutex.RLock()
+	defer fake.listPodMetricsMutex.RUnlock()
 	fake.listPodStatsMutex.RLock()
 	defer fake.listPodStatsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
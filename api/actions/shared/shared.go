@@ -5,7 +5,6 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/repositories"
-	"github.com/go-logr/logr"
 )
 
 //counterfeiter:generate -o fake -fake-name CFProcessRepository . CFProcessRepository
@@ -26,20 +25,21 @@ type CFAppRepository interface {
 	CreateOrPatchAppEnvVars(context.Context, authorization.Info, repositories.CreateOrPatchAppEnvVarsMessage) (repositories.AppEnvVarsRecord, error)
 	CreateApp(context.Context, authorization.Info, repositories.CreateAppMessage) (repositories.AppRecord, error)
 	PatchApp(context.Context, authorization.Info, repositories.PatchAppMessage) (repositories.AppRecord, error)
+	GetAppEnv(context.Context, authorization.Info, string) (repositories.AppEnvRecord, error)
 }
 
-//counterfeiter:generate -o fake -fake-name CFBuildRepository . CFBuildRepository
+//counterfeiter:generate -o fake -fake-name LogStore . LogStore
 
-type CFBuildRepository interface {
-	GetLatestBuildByAppGUID(context.Context, authorization.Info, string, string) (repositories.BuildRecord, error)
-	GetBuildLogs(context.Context, authorization.Info, string, string) ([]repositories.LogRecord, error)
+type LogStore interface {
+	Read(ctx context.Context, appGUID string) ([]repositories.LogRecord, error)
+	Subscribe(ctx context.Context, appGUID string) (<-chan repositories.LogRecord, func(), error)
 }
 
 //counterfeiter:generate -o fake -fake-name PodRepository . PodRepository
 
 type PodRepository interface {
 	ListPodStats(ctx context.Context, authInfo authorization.Info, message repositories.ListPodStatsMessage) ([]repositories.PodStatsRecord, error)
-	GetRuntimeLogsForApp(context.Context, logr.Logger, authorization.Info, repositories.RuntimeLogsMessage) ([]repositories.LogRecord, error)
+	ListPodMetrics(ctx context.Context, authInfo authorization.Info, message repositories.ListPodMetricsMessage) ([]repositories.PodMetricsRecord, error)
 }
 
 //counterfeiter:generate -o fake -fake-name CFDomainRepository . CFDomainRepository
@@ -56,3 +56,16 @@ type CFRouteRepository interface {
 	AddDestinationsToRoute(ctx context.Context, c authorization.Info, message repositories.AddDestinationsToRouteMessage) (repositories.RouteRecord, error)
 	RemoveDestinationFromRoute(ctx context.Context, authInfo authorization.Info, message repositories.RemoveDestinationFromRouteMessage) (repositories.RouteRecord, error)
 }
+
+//counterfeiter:generate -o fake -fake-name CFServiceInstanceRepository . CFServiceInstanceRepository
+
+type CFServiceInstanceRepository interface {
+	ListServiceInstances(context.Context, authorization.Info, repositories.ListServiceInstanceMessage) ([]repositories.ServiceInstanceRecord, error)
+}
+
+//counterfeiter:generate -o fake -fake-name CFServiceBindingRepository . CFServiceBindingRepository
+
+type CFServiceBindingRepository interface {
+	CreateServiceBinding(context.Context, authorization.Info, repositories.CreateServiceBindingMessage) (repositories.ServiceBindingRecord, error)
+	ListServiceBindings(context.Context, authorization.Info, repositories.ListServiceBindingsMessage) ([]repositories.ServiceBindingRecord, error)
+}
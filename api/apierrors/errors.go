@@ -280,6 +280,22 @@ func NewBlobstoreUnavailableError(cause error) BlobstoreUnavailableError {
 	}
 }
 
+type RateLimitExceededError struct {
+	apiError
+}
+
+func NewRateLimitExceededError(cause error) RateLimitExceededError {
+	return RateLimitExceededError{
+		apiError: apiError{
+			cause:      cause,
+			title:      "CF-RateLimitExceeded",
+			detail:     "Rate Limit Exceeded",
+			code:       10013,
+			httpStatus: http.StatusTooManyRequests,
+		},
+	}
+}
+
 func FromK8sError(err error, resourceType string) error {
 	if webhookValidationError, ok := webhooks.WebhookErrorToValidationError(err); ok {
 		return NewUnprocessableEntityError(err, webhookValidationError.GetMessage())
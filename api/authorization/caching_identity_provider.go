@@ -2,9 +2,14 @@ package authorization
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"sync"
 	"time"
 
+	"code.cloudfoundry.org/korifi/api/metrics"
+
 	"k8s.io/apimachinery/pkg/util/cache"
 )
 
@@ -15,17 +20,24 @@ const (
 type CachingIdentityProvider struct {
 	identityProvider IdentityProvider
 	identityCache    *cache.Expiring
+
+	// certificates holds the client certificates of the cached identities by cache key, so that their identities
+	// can be evicted when the certificates are revoked
+	mu           sync.Mutex
+	certificates map[string]*x509.Certificate
 }
 
 func NewCachingIdentityProvider(identityProvider IdentityProvider, identityCache *cache.Expiring) *CachingIdentityProvider {
 	return &CachingIdentityProvider{
 		identityProvider: identityProvider,
 		identityCache:    identityCache,
+		certificates:     map[string]*x509.Certificate{},
 	}
 }
 
 func (p *CachingIdentityProvider) GetIdentity(ctx context.Context, info Info) (Identity, error) {
 	idInterface, ok := p.identityCache.Get(info.Hash())
+	metrics.ObserveCacheLookup(metrics.IdentityCache, ok)
 	if ok {
 		id, castOK := idInterface.(Identity)
 		if castOK {
@@ -37,7 +49,53 @@ func (p *CachingIdentityProvider) GetIdentity(ctx context.Context, info Info) (I
 	identity, err := p.identityProvider.GetIdentity(ctx, info)
 	if err == nil {
 		p.identityCache.Set(info.Hash(), identity, cacheTTL)
+		p.trackCertificate(info)
 	}
 
 	return identity, err
 }
+
+// EvictCertificates drops the cached identities of the client certificates that the check rejects, so that
+// revoking a certificate takes effect immediately rather than when its cached identity expires
+func (p *CachingIdentityProvider) EvictCertificates(check func(*x509.Certificate) error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.forgetExpiredCertificates()
+	for key, cert := range p.certificates {
+		if check(cert) != nil {
+			p.identityCache.Delete(key)
+			delete(p.certificates, key)
+		}
+	}
+}
+
+func (p *CachingIdentityProvider) trackCertificate(info Info) {
+	if len(info.CertData) == 0 {
+		return
+	}
+
+	certBlock, _ := pem.Decode(info.CertData)
+	if certBlock == nil {
+		return
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.forgetExpiredCertificates()
+	p.certificates[info.Hash()] = cert
+}
+
+func (p *CachingIdentityProvider) forgetExpiredCertificates() {
+	for key := range p.certificates {
+		if _, ok := p.identityCache.Get(key); !ok {
+			delete(p.certificates, key)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package authorization_test
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/utils/clock/testing"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var _ = Describe("IdentityProvider", func() {
@@ -22,6 +24,8 @@ var _ = Describe("IdentityProvider", func() {
 		aliceId, id   authorization.Identity
 		identityCache *cache.Expiring
 		getErr        error
+		hitsBefore    float64
+		missesBefore  float64
 	)
 
 	BeforeEach(func() {
@@ -38,9 +42,16 @@ var _ = Describe("IdentityProvider", func() {
 	})
 
 	JustBeforeEach(func() {
+		hitsBefore = identityCacheLookups("hit")
+		missesBefore = identityCacheLookups("miss")
 		id, getErr = idProvider.GetIdentity(context.Background(), authInfo)
 	})
 
+	It("counts a cache miss", func() {
+		Expect(identityCacheLookups("miss")).To(Equal(missesBefore + 1))
+		Expect(identityCacheLookups("hit")).To(Equal(hitsBefore))
+	})
+
 	It("succeeds", func() {
 		Expect(getErr).NotTo(HaveOccurred())
 	})
@@ -75,6 +86,11 @@ var _ = Describe("IdentityProvider", func() {
 			Expect(id).To(Equal(aliceId))
 		})
 
+		It("counts a cache hit", func() {
+			Expect(identityCacheLookups("hit")).To(Equal(hitsBefore + 1))
+			Expect(identityCacheLookups("miss")).To(Equal(missesBefore))
+		})
+
 		It("uses the hash of the auth info as a key", func() {
 			Expect(identityCache.Len()).To(Equal(1))
 			_, ok := identityCache.Get(authInfo.Hash())
@@ -105,4 +121,72 @@ var _ = Describe("IdentityProvider", func() {
 			})
 		})
 	})
+
+	Describe("EvictCertificates", func() {
+		var rejectedName string
+
+		BeforeEach(func() {
+			authInfo = authorization.Info{CertData: generateUnsignedCert("alice")}
+			rejectedName = "alice"
+
+			_, err := idProvider.GetIdentity(context.Background(), authInfo)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = idProvider.GetIdentity(context.Background(), authorization.Info{Token: "a-token"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			idProvider.EvictCertificates(func(cert *x509.Certificate) error {
+				if cert.Subject.CommonName == rejectedName {
+					return errors.New("revoked")
+				}
+				return nil
+			})
+
+			_, err := idProvider.GetIdentity(context.Background(), authInfo)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("evicts the identities of rejected certificates", func() {
+			Expect(fakeProvider.GetIdentityCallCount()).To(Equal(3))
+		})
+
+		It("keeps the identities of tokens", func() {
+			_, ok := identityCache.Get(authorization.Info{Token: "a-token"}.Hash())
+			Expect(ok).To(BeTrue())
+		})
+
+		When("the certificate is not rejected", func() {
+			BeforeEach(func() {
+				rejectedName = "bob"
+			})
+
+			It("keeps its identity", func() {
+				Expect(fakeProvider.GetIdentityCallCount()).To(Equal(2))
+			})
+		})
+	})
 })
+
+func identityCacheLookups(result string) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "korifi_api_cache_lookups_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["cache"] == "identity" && labels["result"] == result {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
@@ -15,11 +15,13 @@ import (
 
 type CertInspector struct {
 	restConfig *rest.Config
+	certPolicy *CertificatePolicy
 }
 
-func NewCertInspector(restConfig *rest.Config) *CertInspector {
+func NewCertInspector(restConfig *rest.Config, certPolicy *CertificatePolicy) *CertInspector {
 	return &CertInspector{
 		restConfig: restConfig,
+		certPolicy: certPolicy,
 	}
 }
 
@@ -34,6 +36,11 @@ func (c *CertInspector) WhoAmI(ctx context.Context, certPEM []byte) (Identity, e
 		return Identity{}, apierrors.NewInvalidAuthError(fmt.Errorf("failed to parse certificate: %w", err))
 	}
 
+	// revoked certificates are still accepted by the cluster, so they have to be rejected before asking it
+	if err = c.certPolicy.Check(cert); err != nil {
+		return Identity{}, err
+	}
+
 	keyBlock, _ := pem.Decode(rst)
 	if keyBlock == nil {
 		return Identity{}, apierrors.NewInvalidAuthError(errors.New("failed to decode key PEM"))
@@ -51,8 +58,10 @@ func (c *CertInspector) WhoAmI(ctx context.Context, certPEM []byte) (Identity, e
 		return Identity{}, apierrors.FromK8sError(err, "")
 	}
 
+	// like the Kubernetes API server, take the organizations of the certificate as groups
 	return Identity{
-		Name: cert.Subject.CommonName,
-		Kind: rbacv1.UserKind,
+		Name:   cert.Subject.CommonName,
+		Kind:   rbacv1.UserKind,
+		Groups: cert.Subject.Organization,
 	}, nil
 }
@@ -3,8 +3,10 @@ package authorization_test
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"time"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
@@ -19,6 +21,7 @@ var _ = Describe("CertInspector", func() {
 	var (
 		ctx           context.Context
 		certInspector *authorization.CertInspector
+		certPolicy    *authorization.CertificatePolicy
 		id            authorization.Identity
 		certPEM       []byte
 		inspectorErr  error
@@ -26,13 +29,14 @@ var _ = Describe("CertInspector", func() {
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		certInspector = authorization.NewCertInspector(k8sConfig)
+		certPolicy = authorization.NewCertificatePolicy(0)
 		certData, keyData := testhelpers.ObtainClientCert(testEnv, "alice")
 		certPEM = certData
 		certPEM = append(certPEM, keyData...)
 	})
 
 	JustBeforeEach(func() {
+		certInspector = authorization.NewCertInspector(k8sConfig, certPolicy)
 		id, inspectorErr = certInspector.WhoAmI(ctx, certPEM)
 	})
 
@@ -42,6 +46,32 @@ var _ = Describe("CertInspector", func() {
 		Expect(id.Name).To(Equal("alice"))
 	})
 
+	When("the certificate has been revoked", func() {
+		BeforeEach(func() {
+			certBlock, _ := pem.Decode(certPEM)
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+
+			certPolicy.SetRevocationList(authorization.RevocationList{Serials: []string{cert.SerialNumber.Text(16)}})
+		})
+
+		It("returns a InvalidAuthError", func() {
+			Expect(inspectorErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(inspectorErr).To(MatchError(ContainSubstring("has been revoked")))
+		})
+	})
+
+	When("the certificate is valid for longer than the maximum lifetime", func() {
+		BeforeEach(func() {
+			certPolicy = authorization.NewCertificatePolicy(time.Minute)
+		})
+
+		It("returns a InvalidAuthError", func() {
+			Expect(inspectorErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(inspectorErr).To(MatchError(ContainSubstring("longer than the maximum")))
+		})
+	})
+
 	When("the certificate is not recognized by the cluster", func() {
 		BeforeEach(func() {
 			certPEM = generateUnsignedCert("alice")
@@ -0,0 +1,215 @@
+package authorization
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch,namespace=ROOT_NAMESPACE
+
+const (
+	// RevokedSerialsKey lists the hex serial numbers of revoked certificates in a revocation list ConfigMap, one
+	// per line
+	RevokedSerialsKey = "serials"
+	// RevokedFingerprintsKey lists the hex SHA-256 fingerprints of revoked certificates in a revocation list
+	// ConfigMap, one per line
+	RevokedFingerprintsKey = "fingerprints"
+	// CRLKey holds PEM encoded X.509 certificate revocation lists in a revocation list ConfigMap
+	CRLKey = "crl.pem"
+)
+
+//counterfeiter:generate -o fake -fake-name CertificateEvicter . CertificateEvicter
+
+type CertificateEvicter interface {
+	EvictCertificates(check func(*x509.Certificate) error)
+}
+
+// RevocationList identifies revoked certificates by serial number or SHA-256 fingerprint. Serial numbers are
+// matched regardless of the issuer, as client certificates are expected to be issued by the cluster CA
+type RevocationList struct {
+	Serials      []string
+	Fingerprints []string
+}
+
+// CertificatePolicy rejects client certificates that the Kubernetes API server accepts but the API should not:
+// certificates that have been revoked and certificates that are valid for longer than the maximum lifetime
+type CertificatePolicy struct {
+	maxLifetime time.Duration
+	logger      logr.Logger
+
+	mu                  sync.RWMutex
+	revokedSerials      map[string]bool
+	revokedFingerprints map[string]bool
+}
+
+// NewCertificatePolicy creates a policy without revoked certificates. A zero maxLifetime accepts any lifetime
+func NewCertificatePolicy(maxLifetime time.Duration) *CertificatePolicy {
+	return &CertificatePolicy{
+		maxLifetime:         maxLifetime,
+		logger:              ctrl.Log.WithName("CertificatePolicy"),
+		revokedSerials:      map[string]bool{},
+		revokedFingerprints: map[string]bool{},
+	}
+}
+
+// Check returns an InvalidAuthError if the certificate has been revoked or is valid for longer than the maximum
+// lifetime
+func (p *CertificatePolicy) Check(cert *x509.Certificate) error {
+	if p.isRevoked(cert) {
+		return apierrors.NewInvalidAuthError(fmt.Errorf("certificate %q with serial %s has been revoked", cert.Subject.CommonName, serialOf(cert)))
+	}
+
+	if lifetime := cert.NotAfter.Sub(cert.NotBefore); p.maxLifetime > 0 && lifetime > p.maxLifetime {
+		return apierrors.NewInvalidAuthError(fmt.Errorf("certificate %q is valid for %s, longer than the maximum of %s", cert.Subject.CommonName, lifetime, p.maxLifetime))
+	}
+
+	return nil
+}
+
+// SetRevocationList replaces the revoked certificates. The serials and fingerprints of the list must be
+// normalized, as ParseRevocationList does
+func (p *CertificatePolicy) SetRevocationList(list RevocationList) {
+	revokedSerials := map[string]bool{}
+	for _, serial := range list.Serials {
+		revokedSerials[serial] = true
+	}
+
+	revokedFingerprints := map[string]bool{}
+	for _, fingerprint := range list.Fingerprints {
+		revokedFingerprints[fingerprint] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.revokedSerials = revokedSerials
+	p.revokedFingerprints = revokedFingerprints
+}
+
+// RevocationListEventHandler returns the handler to register with an informer on the revocation list ConfigMap.
+// It keeps the revoked certificates in sync with the ConfigMap and evicts the cached identities of certificates
+// as soon as they are revoked. A ConfigMap that cannot be parsed is ignored, and deleting the ConfigMap keeps the
+// last known list, so that neither a typo nor a stray delete can reinstate revoked certificates
+func (p *CertificatePolicy) RevocationListEventHandler(evicter CertificateEvicter) toolscache.ResourceEventHandler {
+	update := func(obj interface{}) {
+		configMap, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return
+		}
+
+		list, err := ParseRevocationList(configMap.Data)
+		if err != nil {
+			p.logger.Error(err, "ignoring invalid certificate revocation list", "namespace", configMap.Namespace, "name", configMap.Name)
+			return
+		}
+
+		p.SetRevocationList(list)
+		evicter.EvictCertificates(p.Check)
+	}
+
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: update,
+		UpdateFunc: func(_, obj interface{}) {
+			update(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			configMap, ok := obj.(*corev1.ConfigMap)
+			if !ok {
+				configMap = &corev1.ConfigMap{}
+			}
+
+			p.logger.Info("warning: certificate revocation list deleted, keeping the last known list",
+				"namespace", configMap.Namespace, "name", configMap.Name)
+		},
+	}
+}
+
+func (p *CertificatePolicy) isRevoked(cert *x509.Certificate) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.revokedSerials[serialOf(cert)] || p.revokedFingerprints[fingerprintOf(cert)]
+}
+
+// ParseRevocationList reads the serials, fingerprints and CRLs in the data of a revocation list ConfigMap.
+// Serials and fingerprints are hex encoded and may be separated by colons, as printed by openssl
+func ParseRevocationList(data map[string]string) (RevocationList, error) {
+	list := RevocationList{}
+
+	for _, line := range nonEmptyLines(data[RevokedSerialsKey]) {
+		serial, ok := new(big.Int).SetString(stripColons(line), 16)
+		if !ok {
+			return RevocationList{}, fmt.Errorf("invalid certificate serial %q", line)
+		}
+		list.Serials = append(list.Serials, serial.Text(16))
+	}
+
+	for _, line := range nonEmptyLines(data[RevokedFingerprintsKey]) {
+		fingerprint := strings.ToLower(stripColons(line))
+		if decoded, err := hex.DecodeString(fingerprint); err != nil || len(decoded) != sha256.Size {
+			return RevocationList{}, fmt.Errorf("invalid SHA-256 certificate fingerprint %q", line)
+		}
+		list.Fingerprints = append(list.Fingerprints, fingerprint)
+	}
+
+	rest := []byte(data[CRLKey])
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return RevocationList{}, fmt.Errorf("failed to parse certificate revocation list: %w", err)
+		}
+
+		for _, revoked := range crl.RevokedCertificates {
+			list.Serials = append(list.Serials, revoked.SerialNumber.Text(16))
+		}
+	}
+
+	if strings.TrimSpace(string(rest)) != "" {
+		return RevocationList{}, errors.New("failed to decode certificate revocation list PEM")
+	}
+
+	return list, nil
+}
+
+func serialOf(cert *x509.Certificate) string {
+	return cert.SerialNumber.Text(16)
+}
+
+func fingerprintOf(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func stripColons(s string) string {
+	return strings.ReplaceAll(s, ":", "")
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
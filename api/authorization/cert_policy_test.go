@@ -0,0 +1,258 @@
+package authorization_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CertificatePolicy", func() {
+	var (
+		certPolicy *authorization.CertificatePolicy
+		cert       *x509.Certificate
+		checkErr   error
+	)
+
+	BeforeEach(func() {
+		certPolicy = authorization.NewCertificatePolicy(0)
+		cert = newClientCert(0x1a2b, 24*time.Hour)
+	})
+
+	JustBeforeEach(func() {
+		checkErr = certPolicy.Check(cert)
+	})
+
+	It("accepts the certificate", func() {
+		Expect(checkErr).NotTo(HaveOccurred())
+	})
+
+	When("the serial of the certificate has been revoked", func() {
+		BeforeEach(func() {
+			list, err := authorization.ParseRevocationList(map[string]string{
+				authorization.RevokedSerialsKey: "ff\n1A:2B\n",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			certPolicy.SetRevocationList(list)
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(checkErr).To(MatchError(ContainSubstring("has been revoked")))
+		})
+	})
+
+	When("the fingerprint of the certificate has been revoked", func() {
+		BeforeEach(func() {
+			sum := sha256.Sum256(cert.Raw)
+			list, err := authorization.ParseRevocationList(map[string]string{
+				authorization.RevokedFingerprintsKey: hex.EncodeToString(sum[:]),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			certPolicy.SetRevocationList(list)
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+		})
+	})
+
+	When("the certificate is listed in a certificate revocation list", func() {
+		BeforeEach(func() {
+			list, err := authorization.ParseRevocationList(map[string]string{
+				authorization.CRLKey: newCRL(big.NewInt(0x1a2b)),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			certPolicy.SetRevocationList(list)
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+		})
+	})
+
+	When("other certificates have been revoked", func() {
+		BeforeEach(func() {
+			certPolicy.SetRevocationList(authorization.RevocationList{Serials: []string{"ff"}})
+		})
+
+		It("accepts the certificate", func() {
+			Expect(checkErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the certificate is valid for longer than the maximum lifetime", func() {
+		BeforeEach(func() {
+			certPolicy = authorization.NewCertificatePolicy(time.Hour)
+		})
+
+		It("returns an invalid auth error", func() {
+			Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			Expect(checkErr).To(MatchError(ContainSubstring("longer than the maximum of 1h0m0s")))
+		})
+	})
+
+	When("the certificate is valid for the maximum lifetime", func() {
+		BeforeEach(func() {
+			certPolicy = authorization.NewCertificatePolicy(24 * time.Hour)
+		})
+
+		It("accepts the certificate", func() {
+			Expect(checkErr).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("RevocationListEventHandler", func() {
+		var (
+			evicter   *fake.CertificateEvicter
+			configMap *corev1.ConfigMap
+		)
+
+		BeforeEach(func() {
+			evicter = new(fake.CertificateEvicter)
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "revoked-certs", Namespace: "cf"},
+				Data:       map[string]string{authorization.RevokedSerialsKey: "1a2b"},
+			}
+
+			certPolicy.RevocationListEventHandler(evicter).OnAdd(configMap)
+		})
+
+		It("revokes the listed certificates", func() {
+			Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+		})
+
+		It("evicts the cached identities of the revoked certificates", func() {
+			Expect(evicter.EvictCertificatesCallCount()).To(Equal(1))
+			check := evicter.EvictCertificatesArgsForCall(0)
+			Expect(check(cert)).To(HaveOccurred())
+			Expect(check(newClientCert(0xff, time.Hour))).To(Succeed())
+		})
+
+		When("the revocation list becomes invalid", func() {
+			BeforeEach(func() {
+				updated := configMap.DeepCopy()
+				updated.Data[authorization.RevokedSerialsKey] = "not-a-serial"
+				certPolicy.RevocationListEventHandler(evicter).OnUpdate(configMap, updated)
+			})
+
+			It("keeps the previous list", func() {
+				Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+				Expect(evicter.EvictCertificatesCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the revocation list is deleted", func() {
+			BeforeEach(func() {
+				certPolicy.RevocationListEventHandler(evicter).OnDelete(configMap)
+			})
+
+			It("keeps rejecting the revoked certificates", func() {
+				Expect(checkErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			})
+		})
+	})
+})
+
+var _ = Describe("ParseRevocationList", func() {
+	It("normalizes serials and fingerprints", func() {
+		list, err := authorization.ParseRevocationList(map[string]string{
+			authorization.RevokedSerialsKey:      "  00:1A:2B  \n\n",
+			authorization.RevokedFingerprintsKey: "AB:" + hex.EncodeToString(make([]byte, 31)),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.Serials).To(ConsistOf("1a2b"))
+		Expect(list.Fingerprints).To(ConsistOf("ab" + hex.EncodeToString(make([]byte, 31))))
+	})
+
+	It("rejects invalid serials", func() {
+		_, err := authorization.ParseRevocationList(map[string]string{authorization.RevokedSerialsKey: "xyz"})
+		Expect(err).To(MatchError(ContainSubstring("invalid certificate serial")))
+	})
+
+	It("rejects fingerprints that are not SHA-256", func() {
+		_, err := authorization.ParseRevocationList(map[string]string{authorization.RevokedFingerprintsKey: "abcd"})
+		Expect(err).To(MatchError(ContainSubstring("invalid SHA-256 certificate fingerprint")))
+	})
+
+	It("rejects CRLs that are not PEM encoded", func() {
+		_, err := authorization.ParseRevocationList(map[string]string{authorization.CRLKey: "garbage"})
+		Expect(err).To(MatchError(ContainSubstring("failed to decode certificate revocation list PEM")))
+	})
+})
+
+func newClientCert(serial int64, lifetime time.Duration) *x509.Certificate {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	notBefore := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "alice"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(lifetime),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(certBytes)
+	Expect(err).NotTo(HaveOccurred())
+
+	return cert
+}
+
+func newCRL(revokedSerials ...*big.Int) string {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &privKey.PublicKey, privKey)
+	Expect(err).NotTo(HaveOccurred())
+	ca, err := x509.ParseCertificate(caBytes)
+	Expect(err).NotTo(HaveOccurred())
+
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range revokedSerials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: serial, RevocationTime: time.Now()})
+	}
+
+	crlBytes, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(1),
+		ThisUpdate:          time.Now(),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: revoked,
+	}, ca, privKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	buf := new(bytes.Buffer)
+	Expect(pem.Encode(buf, &pem.Block{Type: "X509 CRL", Bytes: crlBytes})).To(Succeed())
+
+	return buf.String()
+}
@@ -0,0 +1,77 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"crypto/x509"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+)
+
+type CertificateEvicter struct {
+	EvictCertificatesStub        func(func(*x509.Certificate) error)
+	evictCertificatesMutex       sync.RWMutex
+	evictCertificatesArgsForCall []struct {
+		arg1 func(*x509.Certificate) error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CertificateEvicter) EvictCertificates(arg1 func(*x509.Certificate) error) {
+	fake.evictCertificatesMutex.Lock()
+	fake.evictCertificatesArgsForCall = append(fake.evictCertificatesArgsForCall, struct {
+		arg1 func(*x509.Certificate) error
+	}{arg1})
+	stub := fake.EvictCertificatesStub
+	fake.recordInvocation("EvictCertificates", []interface{}{arg1})
+	fake.evictCertificatesMutex.Unlock()
+	if stub != nil {
+		fake.EvictCertificatesStub(arg1)
+	}
+}
+
+func (fake *CertificateEvicter) EvictCertificatesCallCount() int {
+	fake.evictCertificatesMutex.RLock()
+	defer fake.evictCertificatesMutex.RUnlock()
+	return len(fake.evictCertificatesArgsForCall)
+}
+
+func (fake *CertificateEvicter) EvictCertificatesCalls(stub func(func(*x509.Certificate) error)) {
+	fake.evictCertificatesMutex.Lock()
+	defer fake.evictCertificatesMutex.Unlock()
+	fake.EvictCertificatesStub = stub
+}
+
+func (fake *CertificateEvicter) EvictCertificatesArgsForCall(i int) func(*x509.Certificate) error {
+	fake.evictCertificatesMutex.RLock()
+	defer fake.evictCertificatesMutex.RUnlock()
+	argsForCall := fake.evictCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CertificateEvicter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.evictCertificatesMutex.RLock()
+	defer fake.evictCertificatesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CertificateEvicter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ authorization.CertificateEvicter = new(CertificateEvicter)
@@ -5,28 +5,63 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
 )
 
 const (
 	BearerScheme  string = "bearer"
 	CertScheme    string = "clientcert"
 	UnknownScheme string = "unknown"
+
+	// ReservedNamePrefix prefixes the users and groups of Kubernetes itself
+	ReservedNamePrefix = "system:"
 )
 
+// IsReservedName tells whether a user or group name is reserved for Kubernetes, so that no identity provider may
+// claim it on behalf of a user
+func IsReservedName(name string) bool {
+	return strings.HasPrefix(name, ReservedNamePrefix)
+}
+
 //counterfeiter:generate -o fake -fake-name TokenIdentityInspector . TokenIdentityInspector
 //counterfeiter:generate -o fake -fake-name CertIdentityInspector . CertIdentityInspector
 
 type Identity struct {
 	Name string
 	Kind string
+	// Groups are the Kubernetes groups the identity is a member of
+	Groups []string
+	// Impersonator is the identity of the caller acting as this identity, see ImpersonationIdentityProvider
+	Impersonator *Identity
 }
 
 func (i *Identity) Hash() string {
 	key := append([]byte(i.Name), []byte(i.Kind)...)
+	key = append(key, []byte(strings.Join(i.Groups, ","))...)
 	hasher := sha256.New()
 	return hex.EncodeToString(hasher.Sum(key))
 }
 
+// IsSubject tells whether a role binding subject refers to the identity, either directly or through one of its groups
+func (i *Identity) IsSubject(subject rbacv1.Subject) bool {
+	if subject.Kind == rbacv1.GroupKind {
+		return i.IsMemberOf(subject.Name)
+	}
+
+	return subject.Kind == i.Kind && subject.Name == i.Name
+}
+
+func (i *Identity) IsMemberOf(group string) bool {
+	for _, g := range i.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
 type TokenIdentityInspector interface {
 	WhoAmI(context.Context, string) (Identity, error)
 }
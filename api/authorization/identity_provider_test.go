@@ -101,3 +101,45 @@ var _ = Describe("IdentityProvider", func() {
 		})
 	})
 })
+
+var _ = Describe("Identity", func() {
+	var identity authorization.Identity
+
+	BeforeEach(func() {
+		identity = authorization.Identity{
+			Name:   "alice",
+			Kind:   rbacv1.UserKind,
+			Groups: []string{"team-a", "team-b"},
+		}
+	})
+
+	Describe("IsSubject", func() {
+		It("matches subjects of the same kind and name", func() {
+			Expect(identity.IsSubject(rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"})).To(BeTrue())
+		})
+
+		It("does not match subjects of another kind", func() {
+			Expect(identity.IsSubject(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "alice"})).To(BeFalse())
+		})
+
+		It("does not match subjects with another name", func() {
+			Expect(identity.IsSubject(rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"})).To(BeFalse())
+		})
+
+		It("matches the groups of the identity", func() {
+			Expect(identity.IsSubject(rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "team-b"})).To(BeTrue())
+		})
+
+		It("does not match other groups", func() {
+			Expect(identity.IsSubject(rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "team-c"})).To(BeFalse())
+		})
+	})
+
+	Describe("Hash", func() {
+		It("depends on the groups", func() {
+			otherIdentity := identity
+			otherIdentity.Groups = []string{"team-a"}
+			Expect(otherIdentity.Hash()).NotTo(Equal(identity.Hash()))
+		})
+	})
+})
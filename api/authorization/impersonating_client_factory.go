@@ -0,0 +1,64 @@
+package authorization
+
+import (
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups="",resources=users;groups,verbs=impersonate
+
+// ImpersonatingClientFactory builds clients that use the credentials of the API itself to act as a given user. It is
+// used for identities that the Kubernetes API server cannot authenticate on its own, such as the subjects of tokens
+// issued by the API. Impersonating groups lets RoleBindings to those groups apply. Service accounts can only be
+// impersonated in the namespaces the chart grants it for, see oauth.ServiceAccountVerifier.
+type ImpersonatingClientFactory struct {
+	config  *rest.Config
+	mapper  meta.RESTMapper
+	backoff wait.Backoff
+}
+
+func NewImpersonatingClientFactory(config *rest.Config, mapper meta.RESTMapper, backoff wait.Backoff) ImpersonatingClientFactory {
+	impersonatingConfig := rest.CopyConfig(config)
+	impersonatingConfig.Wrap(metrics.InstrumentK8sClient)
+
+	return ImpersonatingClientFactory{
+		config:  impersonatingConfig,
+		mapper:  mapper,
+		backoff: backoff,
+	}
+}
+
+func (f ImpersonatingClientFactory) BuildClient(userName string, groups []string) (client.WithWatch, error) {
+	userClient, err := client.NewWithWatch(f.configFor(userName, groups), client.Options{
+		Scheme: scheme.Scheme,
+		Mapper: f.mapper,
+	})
+	if err != nil {
+		return nil, apierrors.FromK8sError(err, "")
+	}
+
+	return NewTracingClient(NewAuthRetryingClient(userClient, f.backoff)), nil
+}
+
+func (f ImpersonatingClientFactory) BuildK8sClient(userName string, groups []string) (k8sclient.Interface, error) {
+	userK8sClient, err := k8sclient.NewForConfig(f.configFor(userName, groups))
+	if err != nil {
+		return nil, apierrors.FromK8sError(err, "")
+	}
+
+	return userK8sClient, nil
+}
+
+func (f ImpersonatingClientFactory) configFor(userName string, groups []string) *rest.Config {
+	config := rest.CopyConfig(f.config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: userName, Groups: groups}
+
+	return config
+}
@@ -0,0 +1,57 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// ImpersonationIdentityProvider lets members of the admin group act as another user, e.g. to see what a developer
+// sees. Requests whose auth info names an impersonated user get the identity of that user, provided that the caller
+// is a member of the admin group and that the user is not reserved for Kubernetes. Impersonation is disabled when the
+// admin group is empty.
+type ImpersonationIdentityProvider struct {
+	identityProvider IdentityProvider
+	adminGroup       string
+}
+
+func NewImpersonationIdentityProvider(identityProvider IdentityProvider, adminGroup string) *ImpersonationIdentityProvider {
+	return &ImpersonationIdentityProvider{
+		identityProvider: identityProvider,
+		adminGroup:       adminGroup,
+	}
+}
+
+func (p *ImpersonationIdentityProvider) GetIdentity(ctx context.Context, info Info) (Identity, error) {
+	if info.ImpersonatedUser == "" {
+		return p.identityProvider.GetIdentity(ctx, info)
+	}
+
+	impersonator, err := p.identityProvider.GetIdentity(ctx, info.Impersonator())
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if p.adminGroup == "" || !impersonator.IsMemberOf(p.adminGroup) {
+		return Identity{}, apierrors.NewForbiddenError(
+			fmt.Errorf("%s %q may not impersonate users", impersonator.Kind, impersonator.Name),
+			"",
+		)
+	}
+
+	if IsReservedName(info.ImpersonatedUser) {
+		return Identity{}, apierrors.NewForbiddenError(
+			fmt.Errorf("user %q is reserved for Kubernetes and may not be impersonated", info.ImpersonatedUser),
+			"",
+		)
+	}
+
+	return Identity{
+		Name:         info.ImpersonatedUser,
+		Kind:         rbacv1.UserKind,
+		Impersonator: &impersonator,
+	}, nil
+}
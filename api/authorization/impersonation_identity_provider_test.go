@@ -0,0 +1,116 @@
+package authorization_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("ImpersonationIdentityProvider", func() {
+	var (
+		authInfo     authorization.Info
+		fakeProvider *fake.IdentityProvider
+		adminGroup   string
+		id           authorization.Identity
+		getErr       error
+	)
+
+	BeforeEach(func() {
+		fakeProvider = new(fake.IdentityProvider)
+		fakeProvider.GetIdentityReturns(authorization.Identity{
+			Kind:   rbacv1.UserKind,
+			Name:   "alice",
+			Groups: []string{"support", "developers"},
+		}, nil)
+
+		adminGroup = "support"
+		authInfo = authorization.Info{Token: "a-token"}
+	})
+
+	JustBeforeEach(func() {
+		idProvider := authorization.NewImpersonationIdentityProvider(fakeProvider, adminGroup)
+		id, getErr = idProvider.GetIdentity(context.Background(), authInfo)
+	})
+
+	It("returns the identity of the caller", func() {
+		Expect(getErr).NotTo(HaveOccurred())
+		Expect(id.Name).To(Equal("alice"))
+		Expect(fakeProvider.GetIdentityCallCount()).To(Equal(1))
+		_, actualAuthInfo := fakeProvider.GetIdentityArgsForCall(0)
+		Expect(actualAuthInfo).To(Equal(authInfo))
+	})
+
+	When("the auth info impersonates a user", func() {
+		BeforeEach(func() {
+			authInfo.ImpersonatedUser = "bob"
+		})
+
+		It("resolves the identity of the caller without impersonation", func() {
+			Expect(fakeProvider.GetIdentityCallCount()).To(Equal(1))
+			_, actualAuthInfo := fakeProvider.GetIdentityArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "a-token"}))
+		})
+
+		It("returns the identity of the impersonated user", func() {
+			Expect(getErr).NotTo(HaveOccurred())
+			Expect(id.Name).To(Equal("bob"))
+			Expect(id.Kind).To(Equal(rbacv1.UserKind))
+			Expect(id.Groups).To(BeEmpty())
+		})
+
+		It("records the identity of the caller as the impersonator", func() {
+			Expect(id.Impersonator).To(PointTo(Equal(authorization.Identity{
+				Kind:   rbacv1.UserKind,
+				Name:   "alice",
+				Groups: []string{"support", "developers"},
+			})))
+		})
+
+		When("the impersonated user is reserved for Kubernetes", func() {
+			BeforeEach(func() {
+				authInfo.ImpersonatedUser = "system:admin"
+			})
+
+			It("returns a forbidden error", func() {
+				Expect(getErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("the caller is not a member of the admin group", func() {
+			BeforeEach(func() {
+				adminGroup = "cluster-admins"
+			})
+
+			It("returns a forbidden error", func() {
+				Expect(getErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("impersonation is disabled", func() {
+			BeforeEach(func() {
+				adminGroup = ""
+			})
+
+			It("returns a forbidden error", func() {
+				Expect(getErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("resolving the identity of the caller fails", func() {
+			BeforeEach(func() {
+				fakeProvider.GetIdentityReturns(authorization.Identity{}, errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(getErr).To(MatchError("boom"))
+			})
+		})
+	})
+})
@@ -9,6 +9,8 @@ import (
 type Info struct {
 	Token    string
 	CertData []byte
+	// ImpersonatedUser is the user an admin acts as, see ImpersonationIdentityProvider
+	ImpersonatedUser string
 }
 
 type key int
@@ -40,8 +42,17 @@ func (i Info) Scheme() string {
 	return UnknownScheme
 }
 
+// Impersonator returns the auth info of the caller itself, without the impersonated user
+func (i Info) Impersonator() Info {
+	i.ImpersonatedUser = ""
+	return i
+}
+
 func (i Info) Hash() string {
 	key := append([]byte(i.Token), i.CertData...)
+	if i.ImpersonatedUser != "" {
+		key = append(key, []byte("\x00impersonate:"+i.ImpersonatedUser)...)
+	}
 	hasher := sha256.New()
 	return hex.EncodeToString(hasher.Sum(key))
 }
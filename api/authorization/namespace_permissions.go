@@ -9,11 +9,15 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=list
-//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch
+
+// RoleBindingSubjectsIndex indexes role bindings by the kind and name of each of their subjects
+const RoleBindingSubjectsIndex = "roleBindingSubjects"
 
 //counterfeiter:generate -o fake -fake-name IdentityProvider . IdentityProvider
 
@@ -21,18 +25,52 @@ type IdentityProvider interface {
 	GetIdentity(context.Context, Info) (Identity, error)
 }
 
+// NamespacePermissions answers which CF namespaces an identity has role bindings in. Listing the authorized
+// namespaces reads from an informer cache of role bindings and namespaces, with role bindings indexed by
+// RoleBindingSubjectsIndex (see IndexRoleBindingSubjects). AuthorizedIn guards writes, so it reads from the API
+// server to see role bindings that have just been created.
 type NamespacePermissions struct {
 	privilegedClient client.Client
+	permissionsCache client.Reader
 	identityProvider IdentityProvider
 }
 
-func NewNamespacePermissions(privilegedClient client.Client, identityProvider IdentityProvider) *NamespacePermissions {
+func NewNamespacePermissions(privilegedClient client.Client, permissionsCache client.Reader, identityProvider IdentityProvider) *NamespacePermissions {
 	return &NamespacePermissions{
 		privilegedClient: privilegedClient,
+		permissionsCache: permissionsCache,
 		identityProvider: identityProvider,
 	}
 }
 
+// IndexRoleBindingSubjects registers the RoleBindingSubjectsIndex with the cache that is passed to
+// NewNamespacePermissions. It must be called before the cache is started.
+func IndexRoleBindingSubjects(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &rbacv1.RoleBinding{}, RoleBindingSubjectsIndex, roleBindingSubjectsIndexFn)
+}
+
+func roleBindingSubjectsIndexFn(rawObj client.Object) []string {
+	roleBinding := rawObj.(*rbacv1.RoleBinding)
+	var subjectKeys []string
+	for _, subject := range roleBinding.Subjects {
+		subjectKeys = append(subjectKeys, subjectKey(subject.Kind, subject.Name))
+	}
+	return subjectKeys
+}
+
+func subjectKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// subjectKeys returns the RoleBindingSubjectsIndex keys of the subjects the identity matches, see Identity.IsSubject
+func subjectKeys(identity Identity) []string {
+	keys := []string{subjectKey(identity.Kind, identity.Name)}
+	for _, group := range identity.Groups {
+		keys = append(keys, subjectKey(rbacv1.GroupKind, group))
+	}
+	return keys
+}
+
 func (o *NamespacePermissions) GetAuthorizedOrgNamespaces(ctx context.Context, info Info) (map[string]bool, error) {
 	return o.getAuthorizedNamespaces(ctx, info, korifiv1alpha1.OrgNameLabel, "Org")
 }
@@ -47,29 +85,27 @@ func (o *NamespacePermissions) getAuthorizedNamespaces(ctx context.Context, info
 		return nil, fmt.Errorf("failed to get identity: %w", err)
 	}
 
-	var rolebindings rbacv1.RoleBindingList
-	if err := o.privilegedClient.List(ctx, &rolebindings); err != nil {
-		return nil, fmt.Errorf("failed to list rolebindings: %w", apierrors.FromK8sError(err, resourceType))
-	}
-
-	var cfOrgsOrSpaces corev1.NamespaceList
-	if err := o.privilegedClient.List(ctx, &cfOrgsOrSpaces, client.HasLabels([]string{orgSpaceLabel})); err != nil {
-		return nil, fmt.Errorf("failed to list namespaces: %w", apierrors.FromK8sError(err, resourceType))
-	}
-
+	authorizedNamespaces := map[string]bool{}
 	cfNamespaces := map[string]bool{}
-	for _, ns := range cfOrgsOrSpaces.Items {
-		cfNamespaces[ns.Name] = true
-	}
 
-	authorizedNamespaces := map[string]bool{}
+	for _, key := range subjectKeys(identity) {
+		var rolebindings rbacv1.RoleBindingList
+		if err := o.permissionsCache.List(ctx, &rolebindings, client.MatchingFields{RoleBindingSubjectsIndex: key}); err != nil {
+			return nil, fmt.Errorf("failed to list rolebindings: %w", apierrors.FromK8sError(err, resourceType))
+		}
 
-	for _, roleBinding := range rolebindings.Items {
-		for _, subject := range roleBinding.Subjects {
-			if subject.Kind == identity.Kind && subject.Name == identity.Name {
-				if cfNamespaces[roleBinding.Namespace] {
-					authorizedNamespaces[roleBinding.Namespace] = true
+		for _, roleBinding := range rolebindings.Items {
+			isCFNamespace, checked := cfNamespaces[roleBinding.Namespace]
+			if !checked {
+				isCFNamespace, err = o.hasLabel(ctx, roleBinding.Namespace, orgSpaceLabel)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get namespace: %w", apierrors.FromK8sError(err, resourceType))
 				}
+				cfNamespaces[roleBinding.Namespace] = isCFNamespace
+			}
+
+			if isCFNamespace {
+				authorizedNamespaces[roleBinding.Namespace] = true
 			}
 		}
 	}
@@ -77,6 +113,19 @@ func (o *NamespacePermissions) getAuthorizedNamespaces(ctx context.Context, info
 	return authorizedNamespaces, nil
 }
 
+func (o *NamespacePermissions) hasLabel(ctx context.Context, namespace, label string) (bool, error) {
+	var ns corev1.Namespace
+	if err := o.permissionsCache.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	_, ok := ns.Labels[label]
+	return ok, nil
+}
+
 func (o *NamespacePermissions) AuthorizedIn(ctx context.Context, identity Identity, namespace string) (bool, error) {
 	var rolebindings rbacv1.RoleBindingList
 	err := o.privilegedClient.List(ctx, &rolebindings, client.InNamespace(namespace))
@@ -86,7 +135,7 @@ func (o *NamespacePermissions) AuthorizedIn(ctx context.Context, identity Identi
 
 	for _, roleBinding := range rolebindings.Items {
 		for _, subject := range roleBinding.Subjects {
-			if subject.Kind == identity.Kind && subject.Name == identity.Name {
+			if identity.IsSubject(subject) {
 				return true, nil
 			}
 		}
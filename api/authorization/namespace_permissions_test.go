@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
@@ -15,12 +14,18 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("Namespace Permissions", func() {
 	var (
 		ctx              context.Context
+		cancelCache      context.CancelFunc
+		permissionsCache crcache.Cache
 		nsPerms          *authorization.NamespacePermissions
 		namespaces       map[string]bool
 		getErr           error
@@ -35,14 +40,22 @@ var _ = Describe("Namespace Permissions", func() {
 		roleName1, roleName2 string
 	)
 
+	waitForCache := func(obj client.Object) {
+		EventuallyWithOffset(2, func() error {
+			return permissionsCache.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		}).Should(Succeed())
+	}
+
 	createNamespace := func(name string, labels map[string]string) string {
 		guid := generateGUID(name)
-		Expect(k8sClient.Create(ctx, &corev1.Namespace{
+		namespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:   guid,
 				Labels: labels,
 			},
-		})).To(Succeed())
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		waitForCache(namespace)
 
 		return guid
 	}
@@ -59,16 +72,16 @@ var _ = Describe("Namespace Permissions", func() {
 		return role
 	}
 
-	createRoleBindingForUser := func(user, roleName, namespace string) *rbacv1.RoleBinding {
+	createRoleBindingForSubject := func(kind, name, roleName, namespace string) *rbacv1.RoleBinding {
 		role := &rbacv1.RoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-%s", user, roleName),
+				Name:      fmt.Sprintf("%s-%s", name, roleName),
 				Namespace: namespace,
 			},
 			Subjects: []rbacv1.Subject{
 				{
-					Name: user,
-					Kind: "User",
+					Name: name,
+					Kind: kind,
 				},
 			},
 			RoleRef: rbacv1.RoleRef{
@@ -79,10 +92,15 @@ var _ = Describe("Namespace Permissions", func() {
 		}
 
 		Expect(k8sClient.Create(ctx, role)).To(Succeed())
+		waitForCache(role)
 
 		return role
 	}
 
+	createRoleBindingForUser := func(user, roleName, namespace string) *rbacv1.RoleBinding {
+		return createRoleBindingForSubject(rbacv1.UserKind, user, roleName, namespace)
+	}
+
 	BeforeEach(func() {
 		userName = generateGUID("alice")
 		ctx = context.Background()
@@ -97,7 +115,20 @@ var _ = Describe("Namespace Permissions", func() {
 		identityProvider = new(fake.IdentityProvider)
 		identityProvider.GetIdentityReturns(identity, nil)
 
-		nsPerms = authorization.NewNamespacePermissions(k8sClient, identityProvider)
+		var err error
+		permissionsCache, err = crcache.New(k8sConfig, crcache.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorization.IndexRoleBindingSubjects(ctx, permissionsCache)).To(Succeed())
+
+		var cacheCtx context.Context
+		cacheCtx, cancelCache = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(permissionsCache.Start(cacheCtx)).To(Succeed())
+		}()
+		Expect(permissionsCache.WaitForCacheSync(ctx)).To(BeTrue())
+
+		nsPerms = authorization.NewNamespacePermissions(k8sClient, permissionsCache, identityProvider)
 
 		nonCFNS = createNamespace("non-cf", nil)
 
@@ -110,6 +141,7 @@ var _ = Describe("Namespace Permissions", func() {
 	})
 
 	AfterEach(func() {
+		cancelCache()
 		ctx = context.Background()
 		Expect(k8sClient.Delete(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nonCFNS}})).To(Succeed())
 		Expect(k8sClient.Delete(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: roleName1}})).To(Succeed())
@@ -165,15 +197,27 @@ var _ = Describe("Namespace Permissions", func() {
 			})
 		})
 
-		When("listing the rolebindings fails", func() {
-			var cancelCtx context.CancelFunc
-
+		When("a rolebinding of the user is deleted", func() {
 			BeforeEach(func() {
-				ctx, cancelCtx = context.WithDeadline(ctx, time.Now().Add(-time.Minute))
+				roleBinding := createRoleBindingForUser(userName, roleName1, org2NS)
+				Expect(k8sClient.Delete(ctx, roleBinding)).To(Succeed())
+				Eventually(func() bool {
+					return k8serrors.IsNotFound(permissionsCache.Get(ctx, client.ObjectKeyFromObject(roleBinding), &rbacv1.RoleBinding{}))
+				}).Should(BeTrue())
 			})
 
-			AfterEach(func() {
-				cancelCtx()
+			It("no longer lists its namespace", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(namespaces).To(Equal(map[string]bool{org1NS: true}))
+			})
+		})
+
+		When("the permissions cache has not been started", func() {
+			BeforeEach(func() {
+				unstartedCache, err := crcache.New(k8sConfig, crcache.Options{Scheme: scheme.Scheme})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorization.IndexRoleBindingSubjects(ctx, unstartedCache)).To(Succeed())
+				nsPerms = authorization.NewNamespacePermissions(k8sClient, unstartedCache, identityProvider)
 			})
 
 			It("returns an error", func() {
@@ -218,6 +262,35 @@ var _ = Describe("Namespace Permissions", func() {
 				Expect(namespaces).To(BeEmpty())
 			})
 		})
+
+		When("a service account with the name of the user has a rolebinding", func() {
+			BeforeEach(func() {
+				createRoleBindingForSubject(rbacv1.ServiceAccountKind, userName, roleName1, space2NS)
+			})
+
+			It("does not include its namespace", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(namespaces).To(Equal(map[string]bool{space1NS: true}))
+			})
+		})
+
+		When("a group of the user has a rolebinding", func() {
+			BeforeEach(func() {
+				groupName := generateGUID("team")
+				createRoleBindingForSubject(rbacv1.GroupKind, groupName, roleName1, space2NS)
+
+				identityProvider.GetIdentityReturns(authorization.Identity{
+					Name:   userName,
+					Kind:   "User",
+					Groups: []string{groupName},
+				}, nil)
+			})
+
+			It("includes the namespaces of the group bindings", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(namespaces).To(Equal(map[string]bool{space1NS: true, space2NS: true}))
+			})
+		})
 	})
 
 	Describe("Authorized In", func() {
@@ -249,6 +322,18 @@ var _ = Describe("Namespace Permissions", func() {
 				Expect(authorized).To(BeFalse())
 			})
 		})
+
+		When("a group of the user has a RoleBinding in the namespace", func() {
+			It("returns true", func() {
+				groupName := generateGUID("team")
+				createRoleBindingForSubject(rbacv1.GroupKind, groupName, roleName1, org2NS)
+				identity.Groups = []string{groupName}
+
+				authorized, err := nsPerms.AuthorizedIn(ctx, identity, org2NS)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(authorized).To(BeTrue())
+			})
+		})
 	})
 })
 
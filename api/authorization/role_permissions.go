@@ -0,0 +1,172 @@
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/config"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Action is a mutation or sensitive read of the CF API that PermissionMatrix grants to CF roles
+type Action string
+
+const (
+	ActionManagePlatform              Action = "platform.manage"
+	ActionUpdateOrg                   Action = "organizations.update"
+	ActionDeleteOrg                   Action = "organizations.delete"
+	ActionCreateSpace                 Action = "spaces.create"
+	ActionUpdateSpace                 Action = "spaces.update"
+	ActionDeleteSpace                 Action = "spaces.delete"
+	ActionAssignSpaceIsolationSegment Action = "spaces.assign_isolation_segment"
+	ActionManageSpaceQuotas           Action = "space_quotas.manage"
+	ActionManageRoles                 Action = "roles.manage"
+	ActionManageApps                  Action = "apps.manage"
+	ActionChangeAppState              Action = "apps.change_state"
+	ActionScaleProcesses              Action = "processes.scale"
+	ActionReadAppEnv                  Action = "apps.read_env"
+	ActionManageRoutes                Action = "routes.manage"
+	ActionManageServices              Action = "services.manage"
+)
+
+// PermissionMatrix maps CF role types, as used in the role mappings of the API config, to the actions they allow,
+// following the permission tables of the CF v3 API docs. Reading the resources of a namespace is not listed, as
+// every CF role bound in a namespace may do that.
+var PermissionMatrix = map[string][]Action{
+	"admin": {
+		ActionManagePlatform,
+		ActionUpdateOrg,
+		ActionDeleteOrg,
+		ActionCreateSpace,
+		ActionUpdateSpace,
+		ActionDeleteSpace,
+		ActionAssignSpaceIsolationSegment,
+		ActionManageSpaceQuotas,
+		ActionManageRoles,
+		ActionManageApps,
+		ActionChangeAppState,
+		ActionScaleProcesses,
+		ActionReadAppEnv,
+		ActionManageRoutes,
+		ActionManageServices,
+	},
+	"admin_read_only": {ActionReadAppEnv},
+	"global_auditor":  {},
+	"cf_user":         {},
+	"organization_manager": {
+		ActionUpdateOrg,
+		ActionCreateSpace,
+		ActionUpdateSpace,
+		ActionDeleteSpace,
+		ActionAssignSpaceIsolationSegment,
+		ActionManageSpaceQuotas,
+		ActionManageRoles,
+	},
+	"organization_user":            {},
+	"organization_auditor":         {},
+	"organization_billing_manager": {},
+	"space_manager": {
+		ActionUpdateSpace,
+		ActionManageRoles,
+	},
+	"space_developer": {
+		ActionManageApps,
+		ActionChangeAppState,
+		ActionScaleProcesses,
+		ActionReadAppEnv,
+		ActionManageRoutes,
+		ActionManageServices,
+	},
+	"space_supporter": {ActionChangeAppState},
+	"space_auditor":   {},
+}
+
+// RolePermissions evaluates PermissionMatrix against the CF roles an identity is bound to in a namespace. It reads
+// role bindings from the same cache as NamespacePermissions, so the cache must be indexed with
+// IndexRoleBindingSubjects. Role bindings that do not refer to the ClusterRole of a CF role are ignored.
+type RolePermissions struct {
+	permissionsCache client.Reader
+	identityProvider IdentityProvider
+	roleTypes        map[string]string
+}
+
+func NewRolePermissions(permissionsCache client.Reader, identityProvider IdentityProvider, roleMappings map[string]config.Role) *RolePermissions {
+	roleTypes := map[string]string{}
+	for roleType, role := range roleMappings {
+		roleTypes[role.Name] = roleType
+	}
+
+	return &RolePermissions{
+		permissionsCache: permissionsCache,
+		identityProvider: identityProvider,
+		roleTypes:        roleTypes,
+	}
+}
+
+// Authorize returns a ForbiddenError unless one of the CF roles of the identity in the namespace allows the action.
+// Identities without CF roles in the namespace are denied too, even if Kubernetes RBAC would grant them access by
+// other means.
+func (p *RolePermissions) Authorize(ctx context.Context, info Info, action Action, namespace string) error {
+	identity, err := p.identityProvider.GetIdentity(ctx, info)
+	if err != nil {
+		return fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	roleTypes, err := p.roleTypesIn(ctx, identity, namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(roleTypes) == 0 {
+		return apierrors.NewForbiddenError(
+			fmt.Errorf("%s %q has no roles in namespace %q", identity.Kind, identity.Name, namespace),
+			"",
+		)
+	}
+
+	for _, roleType := range roleTypes {
+		if allows(roleType, action) {
+			return nil
+		}
+	}
+
+	return apierrors.NewForbiddenError(
+		fmt.Errorf("%s %q with roles %v in namespace %q may not %s", identity.Kind, identity.Name, roleTypes, namespace, action),
+		"",
+	)
+}
+
+func (p *RolePermissions) roleTypesIn(ctx context.Context, identity Identity, namespace string) ([]string, error) {
+	var roleTypes []string
+	for _, key := range subjectKeys(identity) {
+		var roleBindings rbacv1.RoleBindingList
+		err := p.permissionsCache.List(ctx, &roleBindings, client.InNamespace(namespace), client.MatchingFields{RoleBindingSubjectsIndex: key})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rolebindings: %w", apierrors.FromK8sError(err, ""))
+		}
+
+		for _, roleBinding := range roleBindings.Items {
+			if roleBinding.RoleRef.Kind != "ClusterRole" {
+				continue
+			}
+
+			if roleType, ok := p.roleTypes[roleBinding.RoleRef.Name]; ok {
+				roleTypes = append(roleTypes, roleType)
+			}
+		}
+	}
+
+	return roleTypes, nil
+}
+
+func allows(roleType string, action Action) bool {
+	for _, allowed := range PermissionMatrix[roleType] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
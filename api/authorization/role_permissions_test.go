@@ -0,0 +1,207 @@
+package authorization_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("RolePermissions", func() {
+	var (
+		ctx              context.Context
+		cancelCache      context.CancelFunc
+		permissionsCache crcache.Cache
+		identityProvider *fake.IdentityProvider
+		rolePerms        *authorization.RolePermissions
+		namespace        string
+		userName         string
+		action           authorization.Action
+		authorizeErr     error
+	)
+
+	createRoleBinding := func(kind, name, roleName string) {
+		roleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generateGUID("binding"),
+				Namespace: namespace,
+			},
+			Subjects: []rbacv1.Subject{{Kind: kind, Name: name}},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     roleName,
+			},
+		}
+		ExpectWithOffset(1, k8sClient.Create(ctx, roleBinding)).To(Succeed())
+		EventuallyWithOffset(1, func() error {
+			return permissionsCache.Get(ctx, client.ObjectKeyFromObject(roleBinding), roleBinding)
+		}).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userName = generateGUID("alice")
+		action = authorization.ActionManageApps
+
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{
+			Name:   userName,
+			Kind:   rbacv1.UserKind,
+			Groups: []string{"developers"},
+		}, nil)
+
+		var err error
+		permissionsCache, err = crcache.New(k8sConfig, crcache.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorization.IndexRoleBindingSubjects(ctx, permissionsCache)).To(Succeed())
+
+		var cacheCtx context.Context
+		cacheCtx, cancelCache = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(permissionsCache.Start(cacheCtx)).To(Succeed())
+		}()
+		Expect(permissionsCache.WaitForCacheSync(ctx)).To(BeTrue())
+
+		rolePerms = authorization.NewRolePermissions(permissionsCache, identityProvider, map[string]config.Role{
+			"space_developer": {Name: "cf-space-developer"},
+			"space_auditor":   {Name: "cf-space-auditor"},
+		})
+
+		namespace = generateGUID("space")
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		cancelCache()
+		Expect(k8sClient.Delete(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		authorizeErr = rolePerms.Authorize(ctx, authorization.Info{Token: "alice-token"}, action, namespace)
+	})
+
+	When("the user has no roles in the namespace", func() {
+		It("returns a forbidden error", func() {
+			Expect(authorizeErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+	})
+
+	When("the user only has role bindings that are not CF roles", func() {
+		BeforeEach(func() {
+			createRoleBinding(rbacv1.UserKind, userName, "some-custom-role")
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(authorizeErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+	})
+
+	When("the user has a role that allows the action", func() {
+		BeforeEach(func() {
+			createRoleBinding(rbacv1.UserKind, userName, "cf-space-auditor")
+			createRoleBinding(rbacv1.UserKind, userName, "cf-space-developer")
+		})
+
+		It("succeeds", func() {
+			Expect(authorizeErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("a group of the user has a role that allows the action", func() {
+		BeforeEach(func() {
+			createRoleBinding(rbacv1.GroupKind, "developers", "cf-space-developer")
+		})
+
+		It("succeeds", func() {
+			Expect(authorizeErr).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the roles of the user do not allow the action", func() {
+		BeforeEach(func() {
+			createRoleBinding(rbacv1.UserKind, userName, "cf-space-auditor")
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(authorizeErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the action is reading app environments", func() {
+			BeforeEach(func() {
+				action = authorization.ActionReadAppEnv
+			})
+
+			It("returns a forbidden error", func() {
+				Expect(authorizeErr).To(BeAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+	})
+
+	When("getting the identity fails", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(authorizeErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
+
+var _ = Describe("PermissionMatrix", func() {
+	It("only allows read only admins and developers to read app environments", func() {
+		var roleTypes []string
+		for roleType, actions := range authorization.PermissionMatrix {
+			for _, action := range actions {
+				if action == authorization.ActionReadAppEnv {
+					roleTypes = append(roleTypes, roleType)
+				}
+			}
+		}
+		Expect(roleTypes).To(ConsistOf("admin", "admin_read_only", "space_developer"))
+	})
+
+	It("lets space supporters change the state of apps and nothing else", func() {
+		Expect(authorization.PermissionMatrix["space_supporter"]).To(ConsistOf(authorization.ActionChangeAppState))
+	})
+
+	It("only allows admins and developers to scale processes", func() {
+		var roleTypes []string
+		for roleType, actions := range authorization.PermissionMatrix {
+			for _, action := range actions {
+				if action == authorization.ActionScaleProcesses {
+					roleTypes = append(roleTypes, roleType)
+				}
+			}
+		}
+		Expect(roleTypes).To(ConsistOf("admin", "space_developer"))
+	})
+
+	It("does not allow org auditors and billing managers to change anything", func() {
+		Expect(authorization.PermissionMatrix).To(HaveKeyWithValue("organization_auditor", BeEmpty()))
+		Expect(authorization.PermissionMatrix).To(HaveKeyWithValue("organization_billing_manager", BeEmpty()))
+	})
+
+	It("only allows admins to manage the platform", func() {
+		for roleType, actions := range authorization.PermissionMatrix {
+			if roleType == "admin" {
+				continue
+			}
+			Expect(actions).NotTo(ContainElement(authorization.ActionManagePlatform), roleType)
+		}
+	})
+})
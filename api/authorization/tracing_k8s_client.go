@@ -0,0 +1,94 @@
+package authorization
+
+import (
+	"context"
+	"reflect"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// TracingClient records a span for each request the repositories make to the k8s API. Korifi objects that are
+// created or changed get the trace context of the request stamped on them, so that the controllers continue the
+// trace when reconciling them.
+type TracingClient struct {
+	client.WithWatch
+}
+
+func NewTracingClient(c client.WithWatch) client.WithWatch {
+	return TracingClient{WithWatch: c}
+}
+
+func (c TracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, span := c.start(ctx, "get", obj)
+	err := c.WithWatch.Get(ctx, key, obj, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, span := tracing.Start(ctx, "k8s list "+typeName(list), trace.WithSpanKind(trace.SpanKindClient))
+	err := c.WithWatch.List(ctx, list, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := c.start(ctx, "create", obj)
+	c.stampTraceContext(ctx, obj)
+	err := c.WithWatch.Create(ctx, obj, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := c.start(ctx, "delete", obj)
+	err := c.WithWatch.Delete(ctx, obj, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := c.start(ctx, "update", obj)
+	c.stampTraceContext(ctx, obj)
+	err := c.WithWatch.Update(ctx, obj, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := c.start(ctx, "patch", obj)
+	// merge patches are computed from obj when sent, so the annotation ends up in the patch
+	c.stampTraceContext(ctx, obj)
+	err := c.WithWatch.Patch(ctx, obj, patch, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, span := c.start(ctx, "deleteAllOf", obj)
+	err := c.WithWatch.DeleteAllOf(ctx, obj, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+func (c TracingClient) start(ctx context.Context, op string, obj client.Object) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "k8s "+op+" "+typeName(obj), trace.WithSpanKind(trace.SpanKindClient), tracing.ObjectAttributes(obj))
+}
+
+func (c TracingClient) stampTraceContext(ctx context.Context, obj client.Object) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil || gvk.Group != korifiv1alpha1.GroupVersion.Group {
+		return
+	}
+
+	tracing.InjectIntoObject(ctx, obj)
+}
+
+func typeName(obj any) string {
+	return reflect.TypeOf(obj).Elem().Name()
+}
@@ -0,0 +1,107 @@
+package authorization_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/authorization/fake"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("TracingK8sClient", func() {
+	var (
+		ctx           context.Context
+		exporter      *tracetest.InMemoryExporter
+		k8sClient     *fake.WithWatch
+		tracingClient client.WithWatch
+		cfApp         *korifiv1alpha1.CFApp
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(tracing.NewTracerProvider("test", sdktrace.WithSyncer(exporter)))
+
+		testScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(testScheme)).To(Succeed())
+		Expect(korifiv1alpha1.AddToScheme(testScheme)).To(Succeed())
+
+		k8sClient = new(fake.WithWatch)
+		k8sClient.SchemeReturns(testScheme)
+		tracingClient = authorization.NewTracingClient(k8sClient)
+
+		ctx = context.Background()
+		cfApp = &korifiv1alpha1.CFApp{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-app"},
+		}
+	})
+
+	It("records a client span for each request", func() {
+		Expect(tracingClient.Get(ctx, client.ObjectKeyFromObject(cfApp), cfApp)).To(Succeed())
+		Expect(tracingClient.List(ctx, &korifiv1alpha1.CFAppList{})).To(Succeed())
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("k8s get CFApp"))
+		Expect(spans[0].Attributes).To(ContainElements(
+			attribute.String("k8s.namespace.name", "my-ns"),
+			attribute.String("k8s.object.name", "my-app"),
+		))
+		Expect(spans[1].Name).To(Equal("k8s list CFAppList"))
+	})
+
+	When("the request fails", func() {
+		BeforeEach(func() {
+			k8sClient.DeleteReturns(errors.New("boom"))
+		})
+
+		It("marks the span as failed", func() {
+			Expect(tracingClient.Delete(ctx, cfApp)).To(MatchError("boom"))
+
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status.Code).To(Equal(codes.Error))
+		})
+	})
+
+	Describe("trace context stamping", func() {
+		It("stamps the span of a create on korifi objects", func() {
+			Expect(tracingClient.Create(ctx, cfApp)).To(Succeed())
+
+			Expect(k8sClient.CreateCallCount()).To(Equal(1))
+			_, createdObj, _ := k8sClient.CreateArgsForCall(0)
+			createSpan := exporter.GetSpans()[0]
+			Expect(createdObj.GetAnnotations()).To(HaveKeyWithValue(tracing.TraceParentAnnotation, ContainSubstring(createSpan.SpanContext.SpanID().String())))
+		})
+
+		It("stamps korifi objects on patch and update", func() {
+			Expect(tracingClient.Patch(ctx, cfApp, client.MergeFrom(cfApp.DeepCopy()))).To(Succeed())
+			Expect(tracingClient.Update(ctx, cfApp)).To(Succeed())
+
+			_, patchedObj, _, _ := k8sClient.PatchArgsForCall(0)
+			Expect(patchedObj.GetAnnotations()).To(HaveKey(tracing.TraceParentAnnotation))
+			_, updatedObj, _ := k8sClient.UpdateArgsForCall(0)
+			Expect(updatedObj.GetAnnotations()).To(HaveKey(tracing.TraceParentAnnotation))
+		})
+
+		It("does not stamp other objects", func() {
+			secret := &corev1.Secret{}
+			Expect(tracingClient.Create(ctx, secret)).To(Succeed())
+			Expect(secret.Annotations).NotTo(HaveKey(tracing.TraceParentAnnotation))
+		})
+	})
+})
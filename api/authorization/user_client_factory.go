@@ -1,6 +1,7 @@
 package authorization
 
 import (
+	"context"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	k8sclient "k8s.io/client-go/kubernetes"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/metrics"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -17,10 +19,13 @@ import (
 )
 
 type UserK8sClientFactory interface {
-	BuildClient(Info) (client.WithWatch, error)
-	BuildK8sClient(info Info) (k8sclient.Interface, error)
+	BuildClient(ctx context.Context, info Info) (client.WithWatch, error)
+	BuildK8sClient(ctx context.Context, info Info) (k8sclient.Interface, error)
 }
 
+// UnprivilegedClientFactory builds clients with the credentials of the user. It does not impersonate anyone, as that
+// would need callers to be allowed to impersonate users in Kubernetes; clients of impersonated users are built by the
+// API instead, see ImpersonatingClientFactory.
 type UnprivilegedClientFactory struct {
 	config  *rest.Config
 	mapper  meta.RESTMapper
@@ -28,14 +33,22 @@ type UnprivilegedClientFactory struct {
 }
 
 func NewUnprivilegedClientFactory(config *rest.Config, mapper meta.RESTMapper, backoff wait.Backoff) UnprivilegedClientFactory {
+	// the anonymous config drops any transport wrappers of the original config
+	anonymousConfig := rest.AnonymousClientConfig(rest.CopyConfig(config))
+	anonymousConfig.Wrap(metrics.InstrumentK8sClient)
+
 	return UnprivilegedClientFactory{
-		config:  rest.AnonymousClientConfig(rest.CopyConfig(config)),
+		config:  anonymousConfig,
 		mapper:  mapper,
 		backoff: backoff,
 	}
 }
 
-func (f UnprivilegedClientFactory) BuildClient(authInfo Info) (client.WithWatch, error) {
+func (f UnprivilegedClientFactory) BuildClient(_ context.Context, authInfo Info) (client.WithWatch, error) {
+	if authInfo.ImpersonatedUser != "" {
+		return nil, errors.New("clients of impersonated users are not built with the credentials of the caller")
+	}
+
 	config := rest.CopyConfig(f.config)
 
 	switch strings.ToLower(authInfo.Scheme()) {
@@ -68,10 +81,14 @@ func (f UnprivilegedClientFactory) BuildClient(authInfo Info) (client.WithWatch,
 		return nil, apierrors.FromK8sError(err, "")
 	}
 
-	return NewAuthRetryingClient(userClient, f.backoff), nil
+	return NewTracingClient(NewAuthRetryingClient(userClient, f.backoff)), nil
 }
 
-func (f UnprivilegedClientFactory) BuildK8sClient(authInfo Info) (k8sclient.Interface, error) {
+func (f UnprivilegedClientFactory) BuildK8sClient(_ context.Context, authInfo Info) (k8sclient.Interface, error) {
+	if authInfo.ImpersonatedUser != "" {
+		return nil, errors.New("clients of impersonated users are not built with the credentials of the caller")
+	}
+
 	config := rest.CopyConfig(f.config)
 
 	switch strings.ToLower(authInfo.Scheme()) {
@@ -46,7 +46,7 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 	})
 
 	JustBeforeEach(func() {
-		userClient, buildClientErr = clientFactory.BuildClient(authInfo)
+		userClient, buildClientErr = clientFactory.BuildClient(ctx, authInfo)
 	})
 
 	allowListingPods := func(user string) {
@@ -139,6 +139,18 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 		})
 	})
 
+	When("the auth info impersonates a user", func() {
+		BeforeEach(func() {
+			cert, key := testhelpers.ObtainClientCert(testEnv, userName)
+			authInfo.CertData = testhelpers.JoinCertAndKey(cert, key)
+			authInfo.ImpersonatedUser = uuid.NewString()
+		})
+
+		It("does not build a client with the credentials of the caller", func() {
+			Expect(buildClientErr).To(MatchError(ContainSubstring("impersonated users")))
+		})
+	})
+
 	Context("isolation", func() {
 		When("two clients are created simulaneously", func() {
 			var (
@@ -170,7 +182,7 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 						defer wg.Done()
 
 						var err error
-						client1, err = clientFactory.BuildClient(authInfo1)
+						client1, err = clientFactory.BuildClient(ctx, authInfo1)
 						Expect(err).NotTo(HaveOccurred(), "iteration: %d", i)
 					}()
 
@@ -179,7 +191,7 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 						defer wg.Done()
 
 						var err error
-						client2, err = clientFactory.BuildClient(authInfo2)
+						client2, err = clientFactory.BuildClient(ctx, authInfo2)
 						Expect(err).NotTo(HaveOccurred(), "iteration: %d", i)
 					}()
 
@@ -189,7 +201,7 @@ var _ = Describe("Unprivileged User Client Factory", func() {
 					err := client1.List(ctx, podList)
 					Expect(err).ToNot(HaveOccurred(), "expected user: %s, iteration: %d", name1, i)
 
-					client2, err = clientFactory.BuildClient(authInfo2)
+					client2, err = clientFactory.BuildClient(ctx, authInfo2)
 					Expect(err).NotTo(HaveOccurred())
 					err = client2.List(ctx, podList)
 					Expect(err).To(HaveOccurred(), "iteration: %d", i)
@@ -3,18 +3,37 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 	"k8s.io/client-go/rest"
 )
 
 const (
 	defaultExternalProtocol = "https"
+
+	defaultMaxLogEnvelopesPerApp = 1000
+	defaultLogCollectorPort      = 8081
+	defaultMetricsPort           = 8080
+
+	defaultAccessLogMaxFileSizeMB  = 100
+	defaultAccessLogMaxFileBackups = 10
+
+	OAuthServiceAccountBackend = "serviceaccount"
+	OAuthOIDCBackend           = "oidc"
+
+	defaultOAuthAccessTokenTTL      = time.Hour
+	defaultOAuthRefreshTokenTTL     = 7 * 24 * time.Hour
+	defaultOAuthKeyRotationInterval = 24 * time.Hour
+	defaultOIDCUsernameClaim        = "sub"
 )
 
 type APIConfig struct {
 	InternalPort      int `yaml:"internalPort"`
+	MetricsPort       int `yaml:"metricsPort"`
 	IdleTimeout       int `yaml:"idleTimeout"`
 	ReadTimeout       int `yaml:"readTimeout"`
 	ReadHeaderTimeout int `yaml:"readHeaderTimeout"`
@@ -37,6 +56,133 @@ type APIConfig struct {
 
 	AuthProxyHost   string `yaml:"authProxyHost"`
 	AuthProxyCACert string `yaml:"authProxyCACert"`
+
+	LogCache LogCacheConfig `yaml:"logCache"`
+
+	SyslogDrains SyslogDrainsConfig `yaml:"syslogDrains"`
+
+	AccessLog AccessLogConfig `yaml:"accessLog"`
+
+	Tracing tracing.Config `yaml:"tracing"`
+
+	OAuth OAuthConfig `yaml:"oauth"`
+
+	Impersonation ImpersonationConfig `yaml:"impersonation"`
+
+	ClientCertificates ClientCertificatesConfig `yaml:"clientCertificates"`
+
+	RateLimits RateLimitsConfig `yaml:"rateLimits"`
+}
+
+// LogCacheConfig configures the in-memory store that app, task and staging logs are collected into. One API replica
+// collects the logs, and the other replicas read them from it on the collector port, checking that it presents a
+// certificate for the collector server name.
+type LogCacheConfig struct {
+	MaxEnvelopesPerApp  int    `yaml:"maxEnvelopesPerApp"`
+	CollectorPort       int    `yaml:"collectorPort"`
+	CollectorServerName string `yaml:"collectorServerName"`
+}
+
+// SyslogDrainsConfig restricts the addresses that app logs are forwarded to. Drains may never connect to loopback,
+// link-local, private, unspecified or multicast addresses, nor to the DeniedCIDRs, e.g. the pod and service CIDRs of
+// the cluster, unless they are in the AllowedCIDRs.
+type SyslogDrainsConfig struct {
+	AllowedCIDRs []string `yaml:"allowedCIDRs"`
+	DeniedCIDRs  []string `yaml:"deniedCIDRs"`
+}
+
+// AccessLogConfig configures where the access log of every API request is written to
+type AccessLogConfig struct {
+	File                         AccessLogFileConfig `yaml:"file"`
+	WebhookURL                   string              `yaml:"webhookURL"`
+	IncludeRequestBodies         bool                `yaml:"includeRequestBodies"`
+	RedactSensitiveRequestBodies *bool               `yaml:"redactSensitiveRequestBodies"`
+	// HMACKeySecretName is the name of a Secret in the root namespace with the key the entries are chained with under
+	// the "key" key. It is required when the access log is written anywhere.
+	HMACKeySecretName string `yaml:"hmacKeySecretName"`
+}
+
+type AccessLogFileConfig struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"maxSizeMB"`
+	MaxBackups int    `yaml:"maxBackups"`
+}
+
+// OAuthConfig configures the UAA compatible token endpoint of the API
+type OAuthConfig struct {
+	// Backend verifies the credentials of token requests, either "serviceaccount" (the default) or "oidc"
+	Backend             string     `yaml:"backend"`
+	AccessTokenTTL      string     `yaml:"accessTokenTTL"`
+	RefreshTokenTTL     string     `yaml:"refreshTokenTTL"`
+	KeyRotationInterval string     `yaml:"keyRotationInterval"`
+	OIDC                OIDCConfig `yaml:"oidc"`
+	// ServiceAccountNamespaces are the namespaces, besides the root namespace, whose service accounts may log in with
+	// the "serviceaccount" backend. The API may only impersonate service accounts in those namespaces.
+	ServiceAccountNamespaces []string `yaml:"serviceAccountNamespaces"`
+}
+
+type OIDCConfig struct {
+	IssuerURL string `yaml:"issuerURL"`
+	ClientID  string `yaml:"clientID"`
+	// ClientSecretName is the name of a Secret in the root namespace with the client secret under the
+	// `clientSecret` key
+	ClientSecretName string `yaml:"clientSecretName"`
+	// UsernameClaim, UsernamePrefix, GroupsClaim and GroupsPrefix must match the OIDC settings of the Kubernetes API
+	// server
+	UsernameClaim  string `yaml:"usernameClaim"`
+	UsernamePrefix string `yaml:"usernamePrefix"`
+	GroupsClaim    string `yaml:"groupsClaim"`
+	GroupsPrefix   string `yaml:"groupsPrefix"`
+	CACert         string `yaml:"caCert"`
+	// AuthenticateBearerTokens makes the API validate ID tokens of the provider itself, instead of asking the
+	// Kubernetes API server to review them, and impersonate their user and groups
+	AuthenticateBearerTokens bool `yaml:"authenticateBearerTokens"`
+}
+
+// ImpersonationConfig configures which users may act as other users by sending the X-Korifi-Impersonate-User header
+type ImpersonationConfig struct {
+	// AdminGroup is the group whose members may impersonate other users, impersonation is disabled when empty
+	AdminGroup string `yaml:"adminGroup"`
+}
+
+// ClientCertificatesConfig restricts the client certificates the API accepts beyond what the Kubernetes API server
+// accepts
+type ClientCertificatesConfig struct {
+	// MaxLifetime rejects certificates that are valid for longer, any lifetime is accepted when empty
+	MaxLifetime string `yaml:"maxLifetime"`
+	// RevocationListConfigMapName is the name of a ConfigMap in the root namespace listing revoked certificates by
+	// serial number or fingerprint, or as certificate revocation lists, see authorization.ParseRevocationList
+	RevocationListConfigMapName string `yaml:"revocationListConfigMapName"`
+}
+
+// RateLimitsConfig limits the requests each identity can make to the API, separately for reads, writes and package
+// uploads. Classes of requests without limits are not limited
+type RateLimitsConfig struct {
+	Reads   RateLimitConfig `yaml:"reads"`
+	Writes  RateLimitConfig `yaml:"writes"`
+	Uploads RateLimitConfig `yaml:"uploads"`
+	// ExemptUsers and ExemptGroups are the users and groups that are never limited, e.g. platform admins
+	ExemptUsers  []string `yaml:"exemptUsers"`
+	ExemptGroups []string `yaml:"exemptGroups"`
+}
+
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which the token bucket of an identity refills, requests are not rate limited
+	// when zero
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	// Burst is the size of the token bucket of an identity, it defaults to RequestsPerSecond rounded up
+	Burst int `yaml:"burst"`
+	// MaxConcurrentRequests caps the requests of an identity that are in flight at the same time, there is no cap
+	// when zero
+	MaxConcurrentRequests int `yaml:"maxConcurrentRequests"`
+}
+
+func (c RateLimitConfig) validate(class string) error {
+	if c.RequestsPerSecond < 0 || c.Burst < 0 || c.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("RateLimits.%s must not be negative", class)
+	}
+
+	return nil
 }
 
 type Role struct {
@@ -91,10 +237,79 @@ func (c *APIConfig) validate() error {
 		}
 	}
 
+	if c.ClientCertificates.MaxLifetime != "" {
+		if _, err := time.ParseDuration(c.ClientCertificates.MaxLifetime); err != nil {
+			return errors.New(`Invalid duration format for clientCertificates.maxLifetime. Use a format like "24h"`)
+		}
+	}
+
 	if c.BuilderName == "" {
 		return errors.New("BuilderName must have a value")
 	}
 
+	if (c.AccessLog.File.Path != "" || c.AccessLog.WebhookURL != "") && c.AccessLog.HMACKeySecretName == "" {
+		return errors.New("AccessLog.HMACKeySecretName is required when the access log is enabled")
+	}
+
+	if c.LogCache.MaxEnvelopesPerApp < 0 {
+		return errors.New("LogCache.MaxEnvelopesPerApp must not be negative")
+	}
+
+	for _, cidr := range append(append([]string{}, c.SyslogDrains.AllowedCIDRs...), c.SyslogDrains.DeniedCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("SyslogDrains contains an invalid CIDR %q", cidr)
+		}
+	}
+
+	for class, limit := range map[string]RateLimitConfig{
+		"Reads":   c.RateLimits.Reads,
+		"Writes":  c.RateLimits.Writes,
+		"Uploads": c.RateLimits.Uploads,
+	} {
+		if err := limit.validate(class); err != nil {
+			return err
+		}
+	}
+
+	return c.OAuth.validate()
+}
+
+func isSafeOIDCPrefix(prefix string) bool {
+	return prefix != "" && !strings.HasPrefix(prefix, "system:")
+}
+
+func (c OAuthConfig) validate() error {
+	switch c.Backend {
+	case "", OAuthServiceAccountBackend:
+	case OAuthOIDCBackend:
+	default:
+		return fmt.Errorf("OAuth.Backend must be %q or %q", OAuthServiceAccountBackend, OAuthOIDCBackend)
+	}
+
+	if c.Backend == OAuthOIDCBackend || c.OIDC.AuthenticateBearerTokens {
+		if c.OIDC.IssuerURL == "" || c.OIDC.ClientID == "" {
+			return errors.New("OAuth.OIDC requires an issuerURL and a clientID")
+		}
+
+		// without prefixes, users of the provider could be named like Kubernetes users and groups
+		if !isSafeOIDCPrefix(c.OIDC.UsernamePrefix) || !isSafeOIDCPrefix(c.OIDC.GroupsPrefix) {
+			return errors.New(`OAuth.OIDC requires a usernamePrefix and a groupsPrefix that do not start with "system:"`)
+		}
+	}
+
+	for name, duration := range map[string]string{
+		"accessTokenTTL":      c.AccessTokenTTL,
+		"refreshTokenTTL":     c.RefreshTokenTTL,
+		"keyRotationInterval": c.KeyRotationInterval,
+	} {
+		if duration == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(duration); err != nil {
+			return fmt.Errorf(`Invalid duration format for oauth.%s. Use a format like "1h"`, name)
+		}
+	}
+
 	return nil
 }
 
@@ -106,6 +321,88 @@ func (c *APIConfig) GetUserCertificateDuration() time.Duration {
 	return d
 }
 
+// GetClientCertificateMaxLifetime returns zero when client certificates may be valid for any length of time
+func (c *APIConfig) GetClientCertificateMaxLifetime() time.Duration {
+	return durationOrDefault(c.ClientCertificates.MaxLifetime, 0)
+}
+
+func (c *APIConfig) GetMaxLogEnvelopesPerApp() int {
+	if c.LogCache.MaxEnvelopesPerApp == 0 {
+		return defaultMaxLogEnvelopesPerApp
+	}
+	return c.LogCache.MaxEnvelopesPerApp
+}
+
+func (c *APIConfig) GetLogCollectorPort() int {
+	if c.LogCache.CollectorPort == 0 {
+		return defaultLogCollectorPort
+	}
+	return c.LogCache.CollectorPort
+}
+
+func (c *APIConfig) GetAccessLogMaxFileSizeMB() int {
+	if c.AccessLog.File.MaxSizeMB == 0 {
+		return defaultAccessLogMaxFileSizeMB
+	}
+	return c.AccessLog.File.MaxSizeMB
+}
+
+func (c *APIConfig) GetAccessLogMaxFileBackups() int {
+	if c.AccessLog.File.MaxBackups == 0 {
+		return defaultAccessLogMaxFileBackups
+	}
+	return c.AccessLog.File.MaxBackups
+}
+
+// GetRedactSensitiveRequestBodies defaults to redacting the bodies of requests that may carry secrets
+func (c *APIConfig) GetRedactSensitiveRequestBodies() bool {
+	if c.AccessLog.RedactSensitiveRequestBodies == nil {
+		return true
+	}
+	return *c.AccessLog.RedactSensitiveRequestBodies
+}
+
+func (c *APIConfig) GetMetricsPort() int {
+	if c.MetricsPort == 0 {
+		return defaultMetricsPort
+	}
+	return c.MetricsPort
+}
+
+func (c *APIConfig) GetOAuthBackend() string {
+	if c.OAuth.Backend == "" {
+		return OAuthServiceAccountBackend
+	}
+	return c.OAuth.Backend
+}
+
+func (c *APIConfig) GetOAuthAccessTokenTTL() time.Duration {
+	return durationOrDefault(c.OAuth.AccessTokenTTL, defaultOAuthAccessTokenTTL)
+}
+
+func (c *APIConfig) GetOAuthRefreshTokenTTL() time.Duration {
+	return durationOrDefault(c.OAuth.RefreshTokenTTL, defaultOAuthRefreshTokenTTL)
+}
+
+func (c *APIConfig) GetOAuthKeyRotationInterval() time.Duration {
+	return durationOrDefault(c.OAuth.KeyRotationInterval, defaultOAuthKeyRotationInterval)
+}
+
+func (c *APIConfig) GetOIDCUsernameClaim() string {
+	if c.OAuth.OIDC.UsernameClaim == "" {
+		return defaultOIDCUsernameClaim
+	}
+	return c.OAuth.OIDC.UsernameClaim
+}
+
+func durationOrDefault(duration string, defaultDuration time.Duration) time.Duration {
+	if duration == "" {
+		return defaultDuration
+	}
+	d, _ := time.ParseDuration(duration)
+	return d
+}
+
 func (c *APIConfig) composeServerURL() (string, error) {
 	toReturn := defaultExternalProtocol + "://" + c.ExternalFQDN
 
@@ -2,6 +2,17 @@ package correlation
 
 import "context"
 
+type key int
+
+var correlationIDKey key
+
 func ContextWithId(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, correlationIDKey, id)
 }
+
+func IdFromContext(ctx context.Context) (string, bool) {
+	id := ctx.Value(correlationIDKey)
+	s, ok := id.(string)
+
+	return s, ok
+}
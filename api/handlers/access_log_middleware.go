@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+	"code.cloudfoundry.org/korifi/api/correlation"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/go-http-utils/headers"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxLoggedRequestBodyBytes = 64 * 1024
+	redactedRequestBody       = "[REDACTED]"
+)
+
+// sensitiveRequestRoutes are the route templates whose request bodies may carry environment variables or
+// credentials
+var sensitiveRequestRoutes = map[string]bool{
+	AppsPath:               true,
+	AppEnvVarsPath:         true,
+	EnvVarGroupPath:        true,
+	ServiceInstancesPath:   true,
+	ServiceInstancePath:    true,
+	ServiceBindingsPath:    true,
+	SpaceManifestApplyPath: true,
+	SpaceManifestDiffPath:  true,
+	OAuthTokenPath:         true,
+}
+
+//counterfeiter:generate -o fake -fake-name AccessLogger . AccessLogger
+type AccessLogger interface {
+	Log(accesslog.Entry)
+}
+
+type AccessLogMiddleware struct {
+	accessLogger                 AccessLogger
+	authInfoParser               AuthInfoParser
+	identityProvider             IdentityProvider
+	includeRequestBodies         bool
+	redactSensitiveRequestBodies bool
+}
+
+func NewAccessLogMiddleware(
+	accessLogger AccessLogger,
+	authInfoParser AuthInfoParser,
+	identityProvider IdentityProvider,
+	includeRequestBodies bool,
+	redactSensitiveRequestBodies bool,
+) *AccessLogMiddleware {
+	return &AccessLogMiddleware{
+		accessLogger:                 accessLogger,
+		authInfoParser:               authInfoParser,
+		identityProvider:             identityProvider,
+		includeRequestBodies:         includeRequestBodies,
+		redactSensitiveRequestBodies: redactSensitiveRequestBodies,
+	}
+}
+
+// Middleware records every request in the access log, including the ones that fail authentication. It runs
+// before the authentication middleware, so it resolves the identity of the caller itself.
+func (m *AccessLogMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		entry := accesslog.Entry{
+			Timestamp:     time.Now().UTC(),
+			Method:        r.Method,
+			Route:         route,
+			Path:          r.URL.Path,
+			ResourceGUIDs: resourceGUIDs(mux.Vars(r)),
+			RemoteAddr:    r.RemoteAddr,
+			// the identity is the caller itself, also when it impersonates another user
+			ImpersonatedUser: r.Header.Get(ImpersonateUserHeader),
+		}
+		entry.CorrelationID, _ = correlation.IdFromContext(r.Context())
+
+		if m.includeRequestBodies {
+			entry.RequestBody = m.requestBody(r, route)
+		}
+
+		captured := httpsnoop.CaptureMetrics(next, w, r)
+		entry.Status = captured.Code
+		entry.LatencyMillis = captured.Duration.Milliseconds()
+
+		if authHeader := r.Header.Get(headers.Authorization); authHeader != "" {
+			if authInfo, err := m.authInfoParser.Parse(authHeader); err == nil {
+				entry.AuthScheme = authInfo.Scheme()
+				if identity, err := m.identityProvider.GetIdentity(r.Context(), authInfo); err == nil {
+					entry.Identity = &accesslog.Identity{Name: identity.Name, Kind: identity.Kind}
+				}
+			}
+		}
+
+		m.accessLogger.Log(entry)
+	})
+}
+
+// requestBody returns up to maxLoggedRequestBodyBytes of the request body and leaves the body intact for the
+// handler. Uploads are left out.
+func (m *AccessLogMiddleware) requestBody(r *http.Request, route string) string {
+	if r.Body == nil || r.Body == http.NoBody || strings.HasPrefix(r.Header.Get(headers.ContentType), "multipart/") {
+		return ""
+	}
+
+	if m.redactSensitiveRequestBodies && sensitiveRequestRoutes[route] {
+		return redactedRequestBody
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLoggedRequestBodyBytes+1))
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	if err != nil {
+		return ""
+	}
+
+	if len(body) > maxLoggedRequestBodyBytes {
+		return string(body[:maxLoggedRequestBodyBytes]) + "...(truncated)"
+	}
+
+	return string(body)
+}
+
+// resourceGUIDs picks the guids out of the path variables of a route, e.g. guid and spaceGUID
+func resourceGUIDs(vars map[string]string) map[string]string {
+	guids := map[string]string{}
+	for name, value := range vars {
+		if strings.HasSuffix(strings.ToLower(name), "guid") {
+			guids[name] = value
+		}
+	}
+
+	return guids
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,194 @@
+package handlers_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("AccessLogMiddleware", func() {
+	var (
+		accessLogger         *fake.AccessLogger
+		authInfoParser       *fake.AuthInfoParser
+		identityProvider     *fake.IdentityProvider
+		includeRequestBodies bool
+		redactBodies         bool
+		accessLogRouter      *mux.Router
+		req                  *http.Request
+		handlerBody          string
+	)
+
+	BeforeEach(func() {
+		accessLogger = new(fake.AccessLogger)
+
+		authInfoParser = new(fake.AuthInfoParser)
+		authInfoParser.ParseReturns(authorization.Info{Token: "a-token"}, nil)
+
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{Name: "bob", Kind: rbacv1.UserKind}, nil)
+
+		includeRequestBodies = false
+		redactBodies = true
+
+		req = httptest.NewRequest(http.MethodPatch, "/v3/spaces/space-guid/things/thing-guid/stuff", strings.NewReader(`{"name":"foo"}`))
+		req.Header.Set("Authorization", "Bearer a-token")
+		req = req.WithContext(correlation.ContextWithId(req.Context(), "correlation-id"))
+	})
+
+	JustBeforeEach(func() {
+		accessLogRouter = mux.NewRouter()
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			handlerBody = string(body)
+			w.WriteHeader(http.StatusTeapot)
+		}
+		accessLogRouter.Path("/v3/spaces/{spaceGUID}/things/{guid}/{kind}").HandlerFunc(handlerFunc)
+		accessLogRouter.Path(handlers.AppEnvVarsPath).HandlerFunc(handlerFunc)
+		accessLogRouter.Use(handlers.NewAccessLogMiddleware(
+			accessLogger,
+			authInfoParser,
+			identityProvider,
+			includeRequestBodies,
+			redactBodies,
+		).Middleware)
+
+		accessLogRouter.ServeHTTP(rr, req)
+	})
+
+	loggedEntry := func() accesslog.Entry {
+		Expect(accessLogger.LogCallCount()).To(Equal(1))
+		return accessLogger.LogArgsForCall(0)
+	}
+
+	It("delegates to the next handler", func() {
+		Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		Expect(handlerBody).To(Equal(`{"name":"foo"}`))
+	})
+
+	It("logs the request", func() {
+		Expect(loggedEntry()).To(MatchFields(IgnoreExtras, Fields{
+			"Timestamp":     Not(BeZero()),
+			"CorrelationID": Equal("correlation-id"),
+			"Identity":      Equal(&accesslog.Identity{Name: "bob", Kind: rbacv1.UserKind}),
+			"AuthScheme":    Equal(authorization.BearerScheme),
+			"Method":        Equal(http.MethodPatch),
+			"Route":         Equal("/v3/spaces/{spaceGUID}/things/{guid}/{kind}"),
+			"Path":          Equal("/v3/spaces/space-guid/things/thing-guid/stuff"),
+			"ResourceGUIDs": Equal(map[string]string{"spaceGUID": "space-guid", "guid": "thing-guid"}),
+			"Status":        Equal(http.StatusTeapot),
+			"RequestBody":   BeEmpty(),
+		}))
+
+		Expect(authInfoParser.ParseArgsForCall(0)).To(Equal("Bearer a-token"))
+		_, authInfo := identityProvider.GetIdentityArgsForCall(0)
+		Expect(authInfo).To(Equal(authorization.Info{Token: "a-token"}))
+	})
+
+	When("the request is not authenticated", func() {
+		BeforeEach(func() {
+			req.Header.Del("Authorization")
+		})
+
+		It("logs the request without an identity", func() {
+			entry := loggedEntry()
+			Expect(entry.Identity).To(BeNil())
+			Expect(entry.AuthScheme).To(BeEmpty())
+			Expect(authInfoParser.ParseCallCount()).To(BeZero())
+		})
+	})
+
+	When("the request impersonates a user", func() {
+		BeforeEach(func() {
+			req.Header.Set(handlers.ImpersonateUserHeader, "alice")
+		})
+
+		It("logs the impersonated user next to the identity of the caller", func() {
+			entry := loggedEntry()
+			Expect(entry.Identity).To(Equal(&accesslog.Identity{Name: "bob", Kind: rbacv1.UserKind}))
+			Expect(entry.ImpersonatedUser).To(Equal("alice"))
+
+			_, authInfo := identityProvider.GetIdentityArgsForCall(0)
+			Expect(authInfo).To(Equal(authorization.Info{Token: "a-token"}))
+		})
+	})
+
+	When("the identity cannot be resolved", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("invalid token"))
+		})
+
+		It("logs the request with the auth scheme only", func() {
+			entry := loggedEntry()
+			Expect(entry.Identity).To(BeNil())
+			Expect(entry.AuthScheme).To(Equal(authorization.BearerScheme))
+		})
+	})
+
+	When("request bodies are included", func() {
+		BeforeEach(func() {
+			includeRequestBodies = true
+		})
+
+		It("logs the body and passes it on to the handler", func() {
+			Expect(loggedEntry().RequestBody).To(Equal(`{"name":"foo"}`))
+			Expect(handlerBody).To(Equal(`{"name":"foo"}`))
+		})
+
+		When("the body is large", func() {
+			BeforeEach(func() {
+				req.Body = io.NopCloser(strings.NewReader(strings.Repeat("x", 100*1024)))
+			})
+
+			It("truncates the logged body", func() {
+				Expect(loggedEntry().RequestBody).To(HaveLen(64*1024 + len("...(truncated)")))
+				Expect(handlerBody).To(HaveLen(100 * 1024))
+			})
+		})
+
+		When("the request is an upload", func() {
+			BeforeEach(func() {
+				req.Header.Set("Content-Type", "multipart/form-data; boundary=foo")
+			})
+
+			It("does not log the body", func() {
+				Expect(loggedEntry().RequestBody).To(BeEmpty())
+			})
+		})
+
+		When("the route may carry secrets", func() {
+			BeforeEach(func() {
+				req = httptest.NewRequest(http.MethodPatch, "/v3/apps/app-guid/environment_variables", strings.NewReader(`{"var":{"PASSWORD":"s3cr3t"}}`))
+			})
+
+			It("redacts the body", func() {
+				Expect(loggedEntry().RequestBody).To(Equal("[REDACTED]"))
+				Expect(handlerBody).To(Equal(`{"var":{"PASSWORD":"s3cr3t"}}`))
+			})
+
+			When("redaction is disabled", func() {
+				BeforeEach(func() {
+					redactBodies = false
+				})
+
+				It("logs the body", func() {
+					Expect(loggedEntry().RequestBody).To(Equal(`{"var":{"PASSWORD":"s3cr3t"}}`))
+				})
+			})
+		})
+	})
+})
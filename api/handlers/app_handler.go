@@ -65,6 +65,7 @@ type AppHandler struct {
 	domainRepo       CFDomainRepository
 	spaceRepo        SpaceRepository
 	appProcessScaler AppProcessScaler
+	envVarGroupRepo  CFEnvVarGroupRepository
 	decoderValidator *DecoderValidator
 }
 
@@ -77,6 +78,7 @@ func NewAppHandler(
 	domainRepo CFDomainRepository,
 	spaceRepo SpaceRepository,
 	appProcessScaler AppProcessScaler,
+	envVarGroupRepo CFEnvVarGroupRepository,
 	decoderValidator *DecoderValidator,
 ) *AppHandler {
 	return &AppHandler{
@@ -90,6 +92,7 @@ func NewAppHandler(
 		decoderValidator: decoderValidator,
 		spaceRepo:        spaceRepo,
 		appProcessScaler: appProcessScaler,
+		envVarGroupRepo:  envVarGroupRepo,
 	}
 }
 
@@ -442,7 +445,17 @@ func (h *AppHandler) appGetEnvHandler(ctx context.Context, logger logr.Logger, a
 		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch app environment variables", "AppGUID", appGUID)
 	}
 
-	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAppEnv(appEnvRecord)), nil
+	runningEnvVarGroup, err := h.envVarGroupRepo.GetEnvVarGroup(ctx, authInfo, repositories.RunningEnvVarGroupName)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch running environment variable group")
+	}
+
+	stagingEnvVarGroup, err := h.envVarGroupRepo.GetEnvVarGroup(ctx, authInfo, repositories.StagingEnvVarGroupName)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to fetch staging environment variable group")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAppEnv(appEnvRecord, runningEnvVarGroup, stagingEnvVarGroup)), nil
 }
 
 func (h *AppHandler) getProcessByTypeForAppHander(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
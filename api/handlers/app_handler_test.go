@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
 	. "code.cloudfoundry.org/korifi/api/handlers"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -29,14 +31,15 @@ const (
 
 var _ = Describe("AppHandler", func() {
 	var (
-		appRepo       *fake.CFAppRepository
-		dropletRepo   *fake.CFDropletRepository
-		processRepo   *fake.CFProcessRepository
-		routeRepo     *fake.CFRouteRepository
-		processScaler *fake.AppProcessScaler
-		domainRepo    *fake.CFDomainRepository
-		spaceRepo     *fake.SpaceRepository
-		req           *http.Request
+		appRepo         *fake.CFAppRepository
+		dropletRepo     *fake.CFDropletRepository
+		processRepo     *fake.CFProcessRepository
+		routeRepo       *fake.CFRouteRepository
+		processScaler   *fake.AppProcessScaler
+		domainRepo      *fake.CFDomainRepository
+		spaceRepo       *fake.SpaceRepository
+		envVarGroupRepo *fake.CFEnvVarGroupRepository
+		req             *http.Request
 	)
 
 	BeforeEach(func() {
@@ -47,6 +50,7 @@ var _ = Describe("AppHandler", func() {
 		domainRepo = new(fake.CFDomainRepository)
 		processScaler = new(fake.AppProcessScaler)
 		spaceRepo = new(fake.SpaceRepository)
+		envVarGroupRepo = new(fake.CFEnvVarGroupRepository)
 		decoderValidator, err := NewDefaultDecoderValidator()
 		Expect(err).NotTo(HaveOccurred())
 
@@ -59,6 +63,7 @@ var _ = Describe("AppHandler", func() {
 			domainRepo,
 			spaceRepo,
 			processScaler,
+			envVarGroupRepo,
 			decoderValidator,
 		)
 		apiHandler.RegisterRoutes(router)
@@ -2977,6 +2982,13 @@ var _ = Describe("AppHandler", func() {
 			}
 			appRepo.GetAppEnvReturns(appEnvRecord, nil)
 
+			envVarGroupRepo.GetEnvVarGroupStub = func(_ context.Context, _ authorization.Info, name string) (repositories.EnvVarGroupRecord, error) {
+				return repositories.EnvVarGroupRecord{
+					Name:                 name,
+					EnvironmentVariables: map[string]string{"GROUP": name},
+				}, nil
+			}
+
 			var err error
 			req, err = http.NewRequestWithContext(ctx, "GET", "/v3/apps/"+appGUID+"/env", nil)
 			Expect(err).NotTo(HaveOccurred())
@@ -2998,8 +3010,8 @@ var _ = Describe("AppHandler", func() {
 				Expect(contentTypeHeader).To(Equal(jsonHeader), "Matching Content-Type header:")
 
 				Expect(rr.Body.String()).To(MatchJSON(`{
-                  "staging_env_json": {},
-                  "running_env_json": {},
+                  "staging_env_json": { "GROUP": "staging" },
+                  "running_env_json": { "GROUP": "running" },
                   "environment_variables": { "VAR": "VAL" },
                   "system_env_json": {},
                   "application_env_json": {}
@@ -3016,6 +3028,17 @@ var _ = Describe("AppHandler", func() {
 				expectUnknownError()
 			})
 		})
+
+		When("there is an error fetching an env var group", func() {
+			BeforeEach(func() {
+				envVarGroupRepo.GetEnvVarGroupStub = nil
+				envVarGroupRepo.GetEnvVarGroupReturns(repositories.EnvVarGroupRecord{}, errors.New("unknown!"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
 	})
 
 	Describe("the PATCH /v3/apps/:guid/environment_variables", func() {
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+)
+
+const (
+	AppManifestPath = "/v3/apps/{guid}/manifest"
+)
+
+//counterfeiter:generate -o fake -fake-name ManifestExporter . ManifestExporter
+type ManifestExporter interface {
+	Export(ctx context.Context, authInfo authorization.Info, appGUID string) (payloads.Manifest, error)
+}
+
+type AppManifestHandler struct {
+	handlerWrapper   *AuthAwareHandlerFuncWrapper
+	serverURL        url.URL
+	manifestExporter ManifestExporter
+}
+
+func NewAppManifestHandler(
+	serverURL url.URL,
+	manifestExporter ManifestExporter,
+) *AppManifestHandler {
+	return &AppManifestHandler{
+		handlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("AppManifestHandler")),
+		serverURL:        serverURL,
+		manifestExporter: manifestExporter,
+	}
+}
+
+func (h *AppManifestHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(AppManifestPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.appManifestGetHandler))
+}
+
+func (h *AppManifestHandler) appManifestGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	appGUID := mux.Vars(r)["guid"]
+
+	manifest, err := h.manifestExporter.Export(ctx, authInfo, appGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to export app manifest", "AppGUID", appGUID)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithYAMLBody(manifest), nil
+}
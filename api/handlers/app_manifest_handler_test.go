@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	. "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppManifestHandler", func() {
+	var manifestExporter *fake.ManifestExporter
+
+	BeforeEach(func() {
+		manifestExporter = new(fake.ManifestExporter)
+		manifestExporter.ExportReturns(payloads.Manifest{
+			Version: 1,
+			Applications: []payloads.ManifestApplication{{
+				Name:       "my-app",
+				Env:        map[string]string{"FOO": "bar"},
+				Buildpacks: []string{"my-buildpack"},
+				Processes: []payloads.ManifestApplicationProcess{{
+					Type:            "web",
+					Command:         tools.PtrTo("start-web.sh"),
+					Instances:       tools.PtrTo(2),
+					Memory:          tools.PtrTo("256M"),
+					DiskQuota:       tools.PtrTo("512M"),
+					HealthCheckType: tools.PtrTo("port"),
+				}},
+				Routes: []payloads.ManifestRoute{{Route: tools.PtrTo("my-app.my.domain")}},
+				Services: []payloads.ManifestApplicationService{
+					{Name: "my-instance"},
+					{Name: "other-instance", BindingName: tools.PtrTo("my-binding")},
+				},
+			}},
+		}, nil)
+
+		NewAppManifestHandler(*serverURL, manifestExporter).RegisterRoutes(router)
+	})
+
+	Describe("GET /v3/apps/{guid}/manifest", func() {
+		JustBeforeEach(func() {
+			req, err := http.NewRequestWithContext(ctx, "GET", "/v3/apps/app-guid/manifest", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			router.ServeHTTP(rr, req)
+		})
+
+		It("exports the manifest of the app", func() {
+			Expect(manifestExporter.ExportCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualAppGUID := manifestExporter.ExportArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualAppGUID).To(Equal("app-guid"))
+		})
+
+		It("returns the manifest as YAML", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/x-yaml"))
+			Expect(rr).To(HaveHTTPBody(MatchYAML(`---
+                version: 1
+                applications:
+                - name: my-app
+                  env:
+                    FOO: bar
+                  buildpacks:
+                  - my-buildpack
+                  processes:
+                  - type: web
+                    command: start-web.sh
+                    disk_quota: 512M
+                    health-check-type: port
+                    instances: 2
+                    memory: 256M
+                  routes:
+                  - route: my-app.my.domain
+                  services:
+                  - my-instance
+                  - name: other-instance
+                    binding_name: my-binding
+            `)))
+		})
+
+		When("the app does not exist", func() {
+			BeforeEach(func() {
+				manifestExporter.ExportReturns(payloads.Manifest{}, apierrors.NewNotFoundError(nil, "App"))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("App not found")
+			})
+		})
+
+		When("exporting the manifest fails", func() {
+			BeforeEach(func() {
+				manifestExporter.ExportReturns(payloads.Manifest{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	AppUsageEventsPath       = "/v3/app_usage_events"
+	AppUsageEventPath        = "/v3/app_usage_events/{guid}"
+	AppUsageEventsReseedPath = "/v3/app_usage_events/actions/destructively_purge_all_and_reseed"
+)
+
+//counterfeiter:generate -o fake -fake-name CFAppUsageEventRepository . CFAppUsageEventRepository
+type CFAppUsageEventRepository interface {
+	GetAppUsageEvent(context.Context, authorization.Info, string) (repositories.AppUsageEventRecord, error)
+	ListAppUsageEvents(context.Context, authorization.Info, repositories.ListAppUsageEventsMessage) ([]repositories.AppUsageEventRecord, error)
+	PurgeAndReseed(context.Context, authorization.Info) error
+}
+
+type AppUsageEventHandler struct {
+	handlerWrapper    *AuthAwareHandlerFuncWrapper
+	apiBaseURL        url.URL
+	appUsageEventRepo CFAppUsageEventRepository
+}
+
+func NewAppUsageEventHandler(apiBaseURL url.URL, appUsageEventRepo CFAppUsageEventRepository) *AppUsageEventHandler {
+	return &AppUsageEventHandler{
+		handlerWrapper:    NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("AppUsageEventHandler")),
+		apiBaseURL:        apiBaseURL,
+		appUsageEventRepo: appUsageEventRepo,
+	}
+}
+
+func (h *AppUsageEventHandler) appUsageEventGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.appUsageEventRepo.GetAppUsageEvent(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch app usage event", "AppUsageEventGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAppUsageEvent(record, h.apiBaseURL)), nil
+}
+
+func (h *AppUsageEventHandler) appUsageEventListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	appUsageEventListFilter := new(payloads.AppUsageEventList)
+	if err := payloads.Decode(appUsageEventListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	records, err := h.appUsageEventRepo.ListAppUsageEvents(ctx, authInfo, appUsageEventListFilter.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list app usage events")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAppUsageEventList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *AppUsageEventHandler) appUsageEventsReseedHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := h.appUsageEventRepo.PurgeAndReseed(ctx, authInfo); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to purge and reseed app usage events")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(map[string]interface{}{}), nil
+}
+
+func (h *AppUsageEventHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(AppUsageEventsPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.appUsageEventListHandler))
+	router.Path(AppUsageEventPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.appUsageEventGetHandler))
+	router.Path(AppUsageEventsReseedPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.appUsageEventsReseedHandler))
+}
@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppUsageEventHandler", func() {
+	var (
+		appUsageEventRepo *fake.CFAppUsageEventRepository
+		requestMethod     string
+		requestPath       string
+		record            repositories.AppUsageEventRecord
+	)
+
+	BeforeEach(func() {
+		appUsageEventRepo = new(fake.CFAppUsageEventRepository)
+
+		record = repositories.AppUsageEventRecord{
+			GUID:                          "event-guid",
+			CreatedAt:                     time.Date(2021, 9, 17, 15, 23, 10, 0, time.UTC),
+			State:                         "STARTED",
+			PreviousState:                 "STARTED",
+			AppGUID:                       "app-guid",
+			AppName:                       "my-app",
+			ProcessGUID:                   "process-guid",
+			ProcessType:                   "web",
+			SpaceGUID:                     "space-guid",
+			SpaceName:                     "my-space",
+			OrganizationGUID:              "org-guid",
+			InstanceCount:                 3,
+			PreviousInstanceCount:         tools.PtrTo(1),
+			MemoryInMBPerInstance:         256,
+			PreviousMemoryInMBPerInstance: tools.PtrTo(int64(256)),
+		}
+
+		apis.NewAppUsageEventHandler(*serverURL, appUsageEventRepo).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "event-guid",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"state": {
+				"current": "STARTED",
+				"previous": "STARTED"
+			},
+			"app": {
+				"guid": "app-guid",
+				"name": "my-app"
+			},
+			"process": {
+				"guid": "process-guid",
+				"type": "web"
+			},
+			"task": null,
+			"space": {
+				"guid": "space-guid",
+				"name": "my-space"
+			},
+			"organization": {
+				"guid": "org-guid"
+			},
+			"memory_in_mb_per_instance": {
+				"current": 256,
+				"previous": 256
+			},
+			"instance_count": {
+				"current": 3,
+				"previous": 1
+			},
+			"links": {
+				"self": {
+					"href": "%s/v3/app_usage_events/event-guid"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("GET /v3/app_usage_events/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/app_usage_events/event-guid"
+			appUsageEventRepo.GetAppUsageEventReturns(record, nil)
+		})
+
+		It("returns the app usage event", func() {
+			Expect(appUsageEventRepo.GetAppUsageEventCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := appUsageEventRepo.GetAppUsageEventArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("event-guid"))
+
+			expectJSONResponse(http.StatusOK, expectedRecordJSON())
+		})
+
+		When("the event is the first of a task", func() {
+			BeforeEach(func() {
+				record.State = "TASK_STARTED"
+				record.PreviousState = ""
+				record.PreviousInstanceCount = nil
+				record.PreviousMemoryInMBPerInstance = nil
+				record.ProcessGUID = ""
+				record.TaskGUID = "task-guid"
+				record.TaskName = "task-name"
+				appUsageEventRepo.GetAppUsageEventReturns(record, nil)
+			})
+
+			It("presents the task and null previous values", func() {
+				Expect(rr.Body.String()).To(ContainSubstring(`"state":{"current":"TASK_STARTED","previous":null}`))
+				Expect(rr.Body.String()).To(ContainSubstring(`"process":null`))
+				Expect(rr.Body.String()).To(ContainSubstring(`"task":{"guid":"task-guid","name":"task-name"}`))
+				Expect(rr.Body.String()).To(ContainSubstring(`"instance_count":{"current":3,"previous":null}`))
+			})
+		})
+
+		When("the user is not authorized", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.GetAppUsageEventReturns(repositories.AppUsageEventRecord{}, apierrors.NewForbiddenError(nil, repositories.AppUsageEventResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("App Usage Event not found")
+			})
+		})
+	})
+
+	Describe("GET /v3/app_usage_events", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/app_usage_events"
+			appUsageEventRepo.ListAppUsageEventsReturns([]repositories.AppUsageEventRecord{record}, nil)
+		})
+
+		It("returns the app usage events", func() {
+			Expect(appUsageEventRepo.ListAppUsageEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo, _ := appUsageEventRepo.ListAppUsageEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			expectJSONResponse(http.StatusOK, fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {
+						"href": "%[1]s/v3/app_usage_events"
+					},
+					"last": {
+						"href": "%[1]s/v3/app_usage_events"
+					},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))
+		})
+
+		When("cursor parameters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/app_usage_events?after_guid=previous-guid&per_page=10&guids=g1,g2&order_by=created_at"
+			})
+
+			It("passes them to the repository", func() {
+				Expect(appUsageEventRepo.ListAppUsageEventsCallCount()).To(Equal(1))
+				_, _, message := appUsageEventRepo.ListAppUsageEventsArgsForCall(0)
+				Expect(message).To(Equal(repositories.ListAppUsageEventsMessage{
+					GUIDs:     []string{"g1", "g2"},
+					AfterGUID: "previous-guid",
+					Limit:     10,
+				}))
+			})
+		})
+
+		When("per_page is not a number", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/app_usage_events?per_page=many"
+			})
+
+			It("returns a bad request error", func() {
+				expectBadRequestError()
+			})
+		})
+
+		When("the after guid is not a known event", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/app_usage_events?after_guid=unknown"
+				appUsageEventRepo.ListAppUsageEventsReturns(nil, apierrors.NewUnprocessableEntityError(nil, "After guid filter must be a valid app usage event guid."))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("After guid filter must be a valid app usage event guid.")
+			})
+		})
+
+		When("listing the events fails", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.ListAppUsageEventsReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("POST /v3/app_usage_events/actions/destructively_purge_all_and_reseed", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/app_usage_events/actions/destructively_purge_all_and_reseed"
+		})
+
+		It("purges and reseeds the app usage events", func() {
+			Expect(appUsageEventRepo.PurgeAndReseedCallCount()).To(Equal(1))
+			_, actualAuthInfo := appUsageEventRepo.PurgeAndReseedArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			expectJSONResponse(http.StatusOK, `{}`)
+		})
+
+		When("the user is not authorized", func() {
+			BeforeEach(func() {
+				appUsageEventRepo.PurgeAndReseedReturns(apierrors.NewForbiddenError(nil, repositories.AppUsageEventResourceType))
+			})
+
+			It("returns a not authorized error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	AuditEventsPath = "/v3/audit_events"
+	AuditEventPath  = "/v3/audit_events/{guid}"
+)
+
+//counterfeiter:generate -o fake -fake-name CFAuditEventRepository . CFAuditEventRepository
+type CFAuditEventRepository interface {
+	GetAuditEvent(context.Context, authorization.Info, string) (repositories.AuditEventRecord, error)
+	ListAuditEvents(context.Context, authorization.Info, repositories.ListAuditEventsMessage) ([]repositories.AuditEventRecord, error)
+}
+
+type AuditEventHandler struct {
+	handlerWrapper *AuthAwareHandlerFuncWrapper
+	apiBaseURL     url.URL
+	auditEventRepo CFAuditEventRepository
+}
+
+func NewAuditEventHandler(apiBaseURL url.URL, auditEventRepo CFAuditEventRepository) *AuditEventHandler {
+	return &AuditEventHandler{
+		handlerWrapper: NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("AuditEventHandler")),
+		apiBaseURL:     apiBaseURL,
+		auditEventRepo: auditEventRepo,
+	}
+}
+
+func (h *AuditEventHandler) auditEventGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.auditEventRepo.GetAuditEvent(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch audit event", "AuditEventGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAuditEvent(record, h.apiBaseURL)), nil
+}
+
+func (h *AuditEventHandler) auditEventListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	auditEventListFilter := new(payloads.AuditEventList)
+	if err := payloads.Decode(auditEventListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	message, err := auditEventListFilter.ToMessage()
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Invalid audit event filter")
+	}
+
+	records, err := h.auditEventRepo.ListAuditEvents(ctx, authInfo, message)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list audit events")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForAuditEventList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *AuditEventHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(AuditEventsPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.auditEventListHandler))
+	router.Path(AuditEventPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.auditEventGetHandler))
+}
@@ -0,0 +1,269 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("AuditEventHandler", func() {
+	var (
+		auditEventRepo *fake.CFAuditEventRepository
+		requestPath    string
+		record         repositories.AuditEventRecord
+	)
+
+	BeforeEach(func() {
+		auditEventRepo = new(fake.CFAuditEventRepository)
+
+		record = repositories.AuditEventRecord{
+			GUID: "event-guid",
+			Type: "audit.app.create",
+			Actor: repositories.AuditEventParticipant{
+				GUID: "bob",
+				Type: "user",
+				Name: "bob",
+			},
+			Target: repositories.AuditEventParticipant{
+				GUID: "app-guid",
+				Type: "app",
+				Name: "my-app",
+			},
+			SpaceGUID:        "space-guid",
+			OrganizationGUID: "org-guid",
+			Request: repositories.AuditEventRequest{
+				Method:        "POST",
+				Path:          "/v3/apps",
+				UserAgent:     "cf/8.5.0",
+				CorrelationID: "correlation-id",
+			},
+			CreatedAt: time.Date(2021, 9, 17, 15, 23, 10, 0, time.UTC),
+		}
+
+		apis.NewAuditEventHandler(*serverURL, auditEventRepo).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "event-guid",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"type": "audit.app.create",
+			"actor": {
+				"guid": "bob",
+				"type": "user",
+				"name": "bob"
+			},
+			"target": {
+				"guid": "app-guid",
+				"type": "app",
+				"name": "my-app"
+			},
+			"data": {
+				"request": {
+					"method": "POST",
+					"path": "/v3/apps",
+					"user_agent": "cf/8.5.0",
+					"correlation_id": "correlation-id"
+				}
+			},
+			"space": {
+				"guid": "space-guid"
+			},
+			"organization": {
+				"guid": "org-guid"
+			},
+			"links": {
+				"self": {
+					"href": "%s/v3/audit_events/event-guid"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("GET /v3/audit_events/{guid}", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/audit_events/event-guid"
+			auditEventRepo.GetAuditEventReturns(record, nil)
+		})
+
+		It("returns the audit event", func() {
+			Expect(auditEventRepo.GetAuditEventCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := auditEventRepo.GetAuditEventArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("event-guid"))
+
+			expectJSONResponse(http.StatusOK, expectedRecordJSON())
+		})
+
+		When("the event is not scoped to a space or org", func() {
+			BeforeEach(func() {
+				record.SpaceGUID = ""
+				record.OrganizationGUID = ""
+				auditEventRepo.GetAuditEventReturns(record, nil)
+			})
+
+			It("presents null space and organization", func() {
+				Expect(rr.Body.String()).To(ContainSubstring(`"space":null`))
+				Expect(rr.Body.String()).To(ContainSubstring(`"organization":null`))
+			})
+		})
+
+		When("the event is an app instance crash", func() {
+			BeforeEach(func() {
+				record.Type = "audit.app.process.crash"
+				record.Request = repositories.AuditEventRequest{}
+				record.Crash = &repositories.AuditEventCrash{
+					Index:           1,
+					Reason:          "OOMKilled",
+					ExitDescription: "out of memory",
+					ExitStatus:      137,
+					CrashCount:      2,
+					CrashTimestamp:  time.Date(2021, 9, 17, 15, 20, 0, 0, time.UTC),
+				}
+				auditEventRepo.GetAuditEventReturns(record, nil)
+			})
+
+			It("presents the crash details as the event data", func() {
+				Expect(rr.Body.String()).To(ContainSubstring(`"data":{"index":1,"reason":"OOMKilled","exit_description":"out of memory","exit_status":137,"crash_count":2,"crash_timestamp":"2021-09-17T15:20:00Z"}`))
+			})
+		})
+
+		When("the event is not found", func() {
+			BeforeEach(func() {
+				auditEventRepo.GetAuditEventReturns(repositories.AuditEventRecord{}, apierrors.NewNotFoundError(nil, repositories.AuditEventResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Audit Event not found")
+			})
+		})
+
+		When("the user is not authorized", func() {
+			BeforeEach(func() {
+				auditEventRepo.GetAuditEventReturns(repositories.AuditEventRecord{}, apierrors.NewForbiddenError(nil, repositories.AuditEventResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Audit Event not found")
+			})
+		})
+	})
+
+	Describe("GET /v3/audit_events", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/audit_events"
+			auditEventRepo.ListAuditEventsReturns([]repositories.AuditEventRecord{record}, nil)
+		})
+
+		It("returns the audit events", func() {
+			Expect(auditEventRepo.ListAuditEventsCallCount()).To(Equal(1))
+			_, actualAuthInfo, _ := auditEventRepo.ListAuditEventsArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			expectJSONResponse(http.StatusOK, fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {
+						"href": "%[1]s/v3/audit_events"
+					},
+					"last": {
+						"href": "%[1]s/v3/audit_events"
+					},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))
+		})
+
+		When("filters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/audit_events?types=audit.app.create,audit.app.update&target_guids=app-guid&space_guids=space-guid&organization_guids=org-guid&created_ats=2021-09-17T15:23:10Z"
+			})
+
+			It("passes them to the repository", func() {
+				Expect(auditEventRepo.ListAuditEventsCallCount()).To(Equal(1))
+				_, _, message := auditEventRepo.ListAuditEventsArgsForCall(0)
+				Expect(message.Types).To(ConsistOf("audit.app.create", "audit.app.update"))
+				Expect(message.TargetGUIDs).To(ConsistOf("app-guid"))
+				Expect(message.SpaceGUIDs).To(ConsistOf("space-guid"))
+				Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+				Expect(message.CreatedAts).To(ConsistOf(BeTemporally("==", record.CreatedAt)))
+			})
+		})
+
+		When("relational created_ats filters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/audit_events?created_ats[gt]=2021-09-17T15:23:10Z&created_ats[lte]=2021-09-18T15:23:10Z"
+			})
+
+			It("passes them to the repository", func() {
+				Expect(auditEventRepo.ListAuditEventsCallCount()).To(Equal(1))
+				_, _, message := auditEventRepo.ListAuditEventsArgsForCall(0)
+				Expect(message.CreatedAfter).To(PointTo(BeTemporally("==", record.CreatedAt)))
+				Expect(message.CreatedAtOrBefore).To(PointTo(BeTemporally("==", record.CreatedAt.Add(24*time.Hour))))
+				Expect(message.CreatedBefore).To(BeNil())
+				Expect(message.CreatedAtOrAfter).To(BeNil())
+			})
+		})
+
+		When("a created_ats filter is not a timestamp", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/audit_events?created_ats[lt]=yesterday"
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("created_ats[lt] must be a timestamp in ISO 8601 format")
+			})
+		})
+
+		When("paging and ordering parameters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/audit_events?order_by=created_at&per_page=50&page=1"
+			})
+
+			It("ignores them", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			})
+		})
+
+		When("an invalid query parameter is given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/audit_events?foo=bar"
+			})
+
+			It("returns an unknown key error", func() {
+				expectUnknownKeyError("The query parameter is invalid: Valid parameters are: 'types, target_guids, space_guids, organization_guids, created_ats, created_ats[lt], created_ats[lte], created_ats[gt], created_ats[gte], order_by, per_page, page'")
+			})
+		})
+
+		When("listing the events fails", func() {
+			BeforeEach(func() {
+				auditEventRepo.ListAuditEventsReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+})
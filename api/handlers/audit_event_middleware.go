@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/gorilla/mux"
+	rbacv1 "k8s.io/api/rbac/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//counterfeiter:generate -o fake -fake-name AuditEventSink . AuditEventSink
+type AuditEventSink interface {
+	RecordAuditEvent(context.Context, repositories.CreateAuditEventMessage) error
+}
+
+//counterfeiter:generate -o fake -fake-name ResourceNamespaceRetriever . ResourceNamespaceRetriever
+type ResourceNamespaceRetriever interface {
+	NamespaceFor(ctx context.Context, resourceGUID, resourceType string) (string, error)
+}
+
+type auditedCollection struct {
+	targetType   string
+	resourceType string
+	eventPrefix  string
+}
+
+var (
+	auditedCollections = map[string]auditedCollection{
+		"apps":                        {targetType: "app", resourceType: repositories.AppResourceType, eventPrefix: "audit.app"},
+		"builds":                      {targetType: "build", resourceType: repositories.BuildResourceType, eventPrefix: "audit.app.build"},
+		"droplets":                    {targetType: "droplet", resourceType: repositories.DropletResourceType, eventPrefix: "audit.app.droplet"},
+		"packages":                    {targetType: "package", resourceType: repositories.PackageResourceType, eventPrefix: "audit.app.package"},
+		"processes":                   {targetType: "process", resourceType: repositories.ProcessResourceType, eventPrefix: "audit.app.process"},
+		"tasks":                       {targetType: "task", resourceType: repositories.TaskResourceType, eventPrefix: "audit.app.task"},
+		"routes":                      {targetType: "route", resourceType: repositories.RouteResourceType, eventPrefix: "audit.route"},
+		"domains":                     {targetType: "domain", resourceType: repositories.DomainResourceType, eventPrefix: "audit.domain"},
+		"spaces":                      {targetType: "space", resourceType: repositories.SpaceResourceType, eventPrefix: "audit.space"},
+		"organizations":               {targetType: "organization", eventPrefix: "audit.organization"},
+		"service_instances":           {targetType: "user_provided_service_instance", resourceType: repositories.ServiceInstanceResourceType, eventPrefix: "audit.user_provided_service_instance"},
+		"service_credential_bindings": {targetType: "service_binding", resourceType: repositories.ServiceBindingResourceType, eventPrefix: "audit.service_binding"},
+	}
+
+	// auditEventTypeOverrides maps derived event types to the names the CF API uses for them
+	auditEventTypeOverrides = map[string]string{
+		"audit.app.current_droplet.update": "audit.app.droplet.mapped",
+		"audit.app.package.upload.create":  "audit.app.package.upload",
+	}
+)
+
+type AuditEventMiddleware struct {
+	auditEventSink                  AuditEventSink
+	identityProvider                IdentityProvider
+	namespaceRetriever              ResourceNamespaceRetriever
+	rootNamespace                   string
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry
+}
+
+func NewAuditEventMiddleware(
+	auditEventSink AuditEventSink,
+	identityProvider IdentityProvider,
+	namespaceRetriever ResourceNamespaceRetriever,
+	rootNamespace string,
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry,
+) *AuditEventMiddleware {
+	return &AuditEventMiddleware{
+		auditEventSink:                  auditEventSink,
+		identityProvider:                identityProvider,
+		namespaceRetriever:              namespaceRetriever,
+		rootNamespace:                   rootNamespace,
+		unauthenticatedEndpointRegistry: unauthenticatedEndpointRegistry,
+	}
+}
+
+func (m *AuditEventMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) || m.unauthenticatedEndpointRegistry.IsUnauthenticatedEndpoint(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authInfo, ok := authorization.InfoFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		pathTemplate, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil || !strings.HasPrefix(pathTemplate, "/v3/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		collection, subresources := splitPathTemplate(pathTemplate)
+		audited := auditedCollectionFor(collection)
+
+		// the target of a delete is gone once the handler returns, so its scope is resolved upfront
+		targetGUID := mux.Vars(r)["guid"]
+		var spaceGUID, orgGUID string
+		if targetGUID != "" {
+			spaceGUID, orgGUID = m.resolveScope(r.Context(), audited, targetGUID)
+		}
+
+		recorder := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status < 200 || recorder.status > 299 {
+			return
+		}
+
+		body := map[string]interface{}{}
+		_ = json.Unmarshal(recorder.body.Bytes(), &body)
+
+		if targetGUID == "" {
+			targetGUID, _ = body["guid"].(string)
+			spaceGUID, orgGUID = m.resolveScope(r.Context(), audited, targetGUID)
+		}
+		targetName, _ := body["name"].(string)
+
+		logger := correlation.AddCorrelationIDToLogger(r.Context(), logf.Log.WithName("audit-event-middleware"))
+
+		identity, err := m.identityProvider.GetIdentity(r.Context(), authInfo)
+		if err != nil {
+			logger.Error(err, "failed to resolve identity for audit event")
+			return
+		}
+
+		message := repositories.CreateAuditEventMessage{
+			Type: auditEventType(audited, subresources, r.Method, recorder.status),
+			Actor: repositories.AuditEventParticipant{
+				GUID: actorGUID(identity),
+				Type: strings.ToLower(identity.Kind),
+				Name: identity.Name,
+			},
+			Target: repositories.AuditEventParticipant{
+				GUID: targetGUID,
+				Type: audited.targetType,
+				Name: targetName,
+			},
+			SpaceGUID:        spaceGUID,
+			OrganizationGUID: orgGUID,
+			Request: repositories.AuditEventRequest{
+				Method:        r.Method,
+				Path:          r.URL.Path,
+				UserAgent:     r.UserAgent(),
+				CorrelationID: w.Header().Get(CorrelationIDHeader),
+			},
+		}
+
+		if identity.Impersonator != nil {
+			message.Request.ImpersonatedBy = identity.Impersonator.Name
+		}
+
+		if err := m.auditEventSink.RecordAuditEvent(r.Context(), message); err != nil {
+			logger.Error(err, "failed to record audit event", "type", message.Type, "target", targetGUID)
+		}
+	})
+}
+
+// actorGUID returns the GUID of the CFUser of users, so that audit events link up with /v3/users. Other identities
+// have no CFUser and are identified by their name.
+func actorGUID(identity authorization.Identity) string {
+	if identity.Kind != rbacv1.UserKind {
+		return identity.Name
+	}
+
+	return repositories.UserGUID(repositories.KubernetesUserOrigin, identity.Name)
+}
+
+func (m *AuditEventMiddleware) resolveScope(ctx context.Context, audited auditedCollection, guid string) (string, string) {
+	if guid == "" {
+		return "", ""
+	}
+
+	if audited.targetType == "organization" {
+		return "", guid
+	}
+
+	if audited.resourceType == "" {
+		return "", ""
+	}
+
+	spaceGUID := guid
+	if audited.resourceType != repositories.SpaceResourceType {
+		namespace, err := m.namespaceRetriever.NamespaceFor(ctx, guid, audited.resourceType)
+		if err != nil || namespace == m.rootNamespace {
+			return "", ""
+		}
+		spaceGUID = namespace
+	}
+
+	orgGUID, err := m.namespaceRetriever.NamespaceFor(ctx, spaceGUID, repositories.SpaceResourceType)
+	if err != nil {
+		return spaceGUID, ""
+	}
+
+	return spaceGUID, orgGUID
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitPathTemplate returns the top level collection of a /v3 path template and the remaining static path
+// segments, leaving out path variables and the "relationships" segment
+func splitPathTemplate(pathTemplate string) (string, []string) {
+	segments := strings.Split(strings.TrimPrefix(pathTemplate, "/v3/"), "/")
+
+	subresources := []string{}
+	for _, segment := range segments[1:] {
+		if strings.HasPrefix(segment, "{") || segment == "relationships" {
+			continue
+		}
+		subresources = append(subresources, segment)
+	}
+
+	return segments[0], subresources
+}
+
+func auditedCollectionFor(collection string) auditedCollection {
+	if audited, ok := auditedCollections[collection]; ok {
+		return audited
+	}
+
+	singular := strings.TrimSuffix(collection, "s")
+
+	return auditedCollection{
+		targetType:  singular,
+		eventPrefix: "audit." + singular,
+	}
+}
+
+func auditEventType(audited auditedCollection, subresources []string, method string, status int) string {
+	parts := []string{audited.eventPrefix}
+
+	action := ""
+	for i, subresource := range subresources {
+		if subresource == "actions" && i+1 < len(subresources) {
+			action = subresources[i+1]
+			break
+		}
+
+		if nested, ok := auditedCollections[subresource]; ok {
+			subresource = nested.targetType
+		}
+		parts = append(parts, subresource)
+	}
+
+	if action == "" {
+		action = methodAction(method, len(subresources) == 0, status)
+	}
+	parts = append(parts, action)
+
+	eventType := strings.Join(parts, ".")
+	if override, ok := auditEventTypeOverrides[eventType]; ok {
+		return override
+	}
+
+	return eventType
+}
+
+func methodAction(method string, isResource bool, status int) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodDelete:
+		if isResource && status == http.StatusAccepted {
+			return "delete-request"
+		}
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
@@ -0,0 +1,316 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("AuditEventMiddleware", func() {
+	var (
+		auditEventSink                  *fake.AuditEventSink
+		identityProvider                *fake.IdentityProvider
+		namespaceRetriever              *fake.ResourceNamespaceRetriever
+		unauthenticatedEndpointRegistry *fake.UnauthenticatedEndpointRegistry
+		middlewareRouter                *mux.Router
+		responseStatus                  int
+		responseBody                    string
+		requestMethod                   string
+		requestPath                     string
+	)
+
+	BeforeEach(func() {
+		auditEventSink = new(fake.AuditEventSink)
+
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{
+			Name: "bob",
+			Kind: rbacv1.UserKind,
+		}, nil)
+
+		namespaceRetriever = new(fake.ResourceNamespaceRetriever)
+		namespaceRetriever.NamespaceForStub = func(_ context.Context, guid, resourceType string) (string, error) {
+			switch resourceType {
+			case repositories.SpaceResourceType:
+				return "org-guid", nil
+			case repositories.DomainResourceType:
+				return "cf", nil
+			default:
+				return "space-guid", nil
+			}
+		}
+
+		unauthenticatedEndpointRegistry = new(fake.UnauthenticatedEndpointRegistry)
+		unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(false)
+
+		responseStatus = http.StatusOK
+		responseBody = `{"guid": "app-guid", "name": "my-app"}`
+
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(responseStatus)
+			_, _ = w.Write([]byte(responseBody))
+		}
+
+		middlewareRouter = mux.NewRouter()
+		middlewareRouter.Path("/v3/apps").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/apps/{guid}").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/apps/{guid}/actions/start").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/apps/{guid}/processes/{type}/actions/scale").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/apps/{guid}/relationships/current_droplet").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/spaces/{guid}").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/organizations").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/domains/{guid}").HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/oauth/token").HandlerFunc(handlerFunc)
+		middlewareRouter.Use(
+			handlers.NewCorrelationIDMiddleware().Middleware,
+			handlers.NewAuditEventMiddleware(
+				auditEventSink,
+				identityProvider,
+				namespaceRetriever,
+				"cf",
+				unauthenticatedEndpointRegistry,
+			).Middleware,
+		)
+
+		requestMethod = http.MethodPost
+		requestPath = "/v3/apps"
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequestWithContext(ctx, requestMethod, "http://localhost"+requestPath, nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("User-Agent", "cf/8.5.0")
+		request.Header.Set(handlers.CorrelationIDHeader, "correlation-id")
+
+		rr = httptest.NewRecorder()
+		middlewareRouter.ServeHTTP(rr, request)
+	})
+
+	recordedEvent := func() repositories.CreateAuditEventMessage {
+		Expect(auditEventSink.RecordAuditEventCallCount()).To(Equal(1))
+		_, message := auditEventSink.RecordAuditEventArgsForCall(0)
+		return message
+	}
+
+	It("delegates to the next handler", func() {
+		Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+		Expect(rr.Body.String()).To(Equal(responseBody))
+	})
+
+	It("records an audit event for the created resource", func() {
+		Expect(recordedEvent()).To(Equal(repositories.CreateAuditEventMessage{
+			Type: "audit.app.create",
+			Actor: repositories.AuditEventParticipant{
+				GUID: repositories.UserGUID(repositories.KubernetesUserOrigin, "bob"),
+				Type: "user",
+				Name: "bob",
+			},
+			Target: repositories.AuditEventParticipant{
+				GUID: "app-guid",
+				Type: "app",
+				Name: "my-app",
+			},
+			SpaceGUID:        "space-guid",
+			OrganizationGUID: "org-guid",
+			Request: repositories.AuditEventRequest{
+				Method:        http.MethodPost,
+				Path:          "/v3/apps",
+				UserAgent:     "cf/8.5.0",
+				CorrelationID: "correlation-id",
+			},
+		}))
+	})
+
+	When("the actor is a service account", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{
+				Name: "system:serviceaccount:cf:bot",
+				Kind: rbacv1.ServiceAccountKind,
+			}, nil)
+		})
+
+		It("identifies the actor by its name", func() {
+			Expect(recordedEvent().Actor).To(Equal(repositories.AuditEventParticipant{
+				GUID: "system:serviceaccount:cf:bot",
+				Type: "serviceaccount",
+				Name: "system:serviceaccount:cf:bot",
+			}))
+		})
+	})
+
+	When("an admin impersonates the actor", func() {
+		BeforeEach(func() {
+			ctx = authorization.NewContext(ctx, &authorization.Info{Token: "admin-token", ImpersonatedUser: "bob"})
+			identityProvider.GetIdentityStub = func(_ context.Context, info authorization.Info) (authorization.Identity, error) {
+				if info.ImpersonatedUser != "" {
+					return authorization.Identity{
+						Name:         info.ImpersonatedUser,
+						Kind:         rbacv1.UserKind,
+						Impersonator: &authorization.Identity{Name: "admin", Kind: rbacv1.UserKind},
+					}, nil
+				}
+				return authorization.Identity{Name: "admin", Kind: rbacv1.UserKind}, nil
+			}
+		})
+
+		It("records the event for the impersonated user and the admin that made the request", func() {
+			event := recordedEvent()
+			Expect(event.Actor.Name).To(Equal("bob"))
+			Expect(event.Request.ImpersonatedBy).To(Equal("admin"))
+		})
+	})
+
+	When("the request is not mutating", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+		})
+
+		It("does not record an audit event", func() {
+			Expect(auditEventSink.RecordAuditEventCallCount()).To(BeZero())
+		})
+	})
+
+	When("the endpoint is unauthenticated", func() {
+		BeforeEach(func() {
+			requestPath = "/oauth/token"
+			unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(true)
+		})
+
+		It("does not record an audit event", func() {
+			Expect(auditEventSink.RecordAuditEventCallCount()).To(BeZero())
+		})
+	})
+
+	When("the request fails", func() {
+		BeforeEach(func() {
+			responseStatus = http.StatusUnprocessableEntity
+		})
+
+		It("does not record an audit event", func() {
+			Expect(auditEventSink.RecordAuditEventCallCount()).To(BeZero())
+		})
+	})
+
+	When("the resource is updated", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/apps/app-guid"
+		})
+
+		It("records an update event", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.app.update"))
+			Expect(recordedEvent().Target.GUID).To(Equal("app-guid"))
+		})
+	})
+
+	When("the resource deletion is asynchronous", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/apps/app-guid"
+			responseStatus = http.StatusAccepted
+			responseBody = ""
+		})
+
+		It("records a delete-request event", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.app.delete-request"))
+		})
+
+		It("resolves the target scope before the resource is deleted", func() {
+			Expect(recordedEvent().SpaceGUID).To(Equal("space-guid"))
+			Expect(recordedEvent().OrganizationGUID).To(Equal("org-guid"))
+		})
+	})
+
+	When("an action is invoked", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/apps/app-guid/actions/start"
+		})
+
+		It("records an event named after the action", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.app.start"))
+		})
+	})
+
+	When("a nested resource action is invoked", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/apps/app-guid/processes/web/actions/scale"
+		})
+
+		It("records an event for the nested resource", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.app.process.scale"))
+			Expect(recordedEvent().Target.GUID).To(Equal("app-guid"))
+		})
+	})
+
+	When("the current droplet is set", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/apps/app-guid/relationships/current_droplet"
+		})
+
+		It("records a droplet mapped event", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.app.droplet.mapped"))
+		})
+	})
+
+	When("the target is a space", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/spaces/the-space-guid"
+		})
+
+		It("scopes the event to the space and its org", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.space.update"))
+			Expect(recordedEvent().SpaceGUID).To(Equal("the-space-guid"))
+			Expect(recordedEvent().OrganizationGUID).To(Equal("org-guid"))
+		})
+	})
+
+	When("the target is an org", func() {
+		BeforeEach(func() {
+			responseBody = `{"guid": "the-org-guid", "name": "my-org"}`
+			requestPath = "/v3/organizations"
+		})
+
+		It("scopes the event to the org", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.organization.create"))
+			Expect(recordedEvent().SpaceGUID).To(BeEmpty())
+			Expect(recordedEvent().OrganizationGUID).To(Equal("the-org-guid"))
+		})
+	})
+
+	When("the target lives in the root namespace", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/domains/domain-guid"
+			responseBody = ""
+		})
+
+		It("does not scope the event", func() {
+			Expect(recordedEvent().Type).To(Equal("audit.domain.delete"))
+			Expect(recordedEvent().SpaceGUID).To(BeEmpty())
+			Expect(recordedEvent().OrganizationGUID).To(BeEmpty())
+		})
+	})
+
+	When("recording the event fails", func() {
+		BeforeEach(func() {
+			auditEventSink.RecordAuditEventReturns(errors.New("boom"))
+		})
+
+		It("does not fail the request", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+		})
+	})
+})
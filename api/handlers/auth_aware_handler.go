@@ -14,11 +14,13 @@ import (
 
 	"github.com/go-http-utils/headers"
 	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
 )
 
 type HandlerResponse struct {
 	httpStatus int
 	body       interface{}
+	yamlBody   bool
 	headers    map[string][]string
 }
 
@@ -39,6 +41,13 @@ func (r *HandlerResponse) WithBody(body interface{}) *HandlerResponse {
 	return r
 }
 
+// WithYAMLBody sets a body that is written as YAML rather than JSON
+func (r *HandlerResponse) WithYAMLBody(body interface{}) *HandlerResponse {
+	r.body = body
+	r.yamlBody = true
+	return r
+}
+
 //counterfeiter:generate -o fake -fake-name AuthAwareHandlerFunc . AuthAwareHandlerFunc
 
 type AuthAwareHandlerFunc func(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error)
@@ -128,6 +137,10 @@ func (response *HandlerResponse) writeTo(w http.ResponseWriter) error {
 		return nil
 	}
 
+	if response.yamlBody {
+		return response.writeYAMLBodyTo(w)
+	}
+
 	w.Header().Set(headers.ContentType, "application/json")
 	w.WriteHeader(response.httpStatus)
 
@@ -141,3 +154,18 @@ func (response *HandlerResponse) writeTo(w http.ResponseWriter) error {
 
 	return nil
 }
+
+func (response *HandlerResponse) writeYAMLBodyTo(w http.ResponseWriter) error {
+	w.Header().Set(headers.ContentType, "application/x-yaml")
+	w.WriteHeader(response.httpStatus)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(response.body)
+	if err != nil {
+		return fmt.Errorf("failed to encode and write response: %w", err)
+	}
+
+	return encoder.Close()
+}
@@ -12,6 +12,10 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// ImpersonateUserHeader names the user that a member of the admin group acts as, see
+// authorization.ImpersonationIdentityProvider
+const ImpersonateUserHeader = "X-Korifi-Impersonate-User"
+
 //counterfeiter:generate -o fake -fake-name UnauthenticatedEndpointRegistry . UnauthenticatedEndpointRegistry
 //counterfeiter:generate -o fake -fake-name AuthInfoParser . AuthInfoParser
 
@@ -59,14 +63,26 @@ func (a *AuthenticationMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		authInfo.ImpersonatedUser = r.Header.Get(ImpersonateUserHeader)
+
 		r = r.WithContext(authorization.NewContext(r.Context(), &authInfo))
 
-		_, err = a.identityProvider.GetIdentity(r.Context(), authInfo)
+		identity, err := a.identityProvider.GetIdentity(r.Context(), authInfo)
 		if err != nil {
 			presentError(logger, w, apierrors.LogAndReturn(logger, err, "failed to get identity"))
 			return
 		}
 
+		if impersonator := identity.Impersonator; impersonator != nil {
+			logger.Info("impersonated request",
+				"impersonator", impersonator.Name,
+				"impersonatorKind", impersonator.Kind,
+				"user", identity.Name,
+				"method", r.Method,
+				"path", r.URL.Path,
+			)
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
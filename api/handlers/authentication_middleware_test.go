@@ -23,6 +23,7 @@ var _ = Describe("Authentication Middleware", func() {
 		identityProvider                *fake.IdentityProvider
 		authInfoParser                  *fake.AuthInfoParser
 		requestPath                     string
+		impersonatedUser                string
 		actualReq                       *http.Request
 		unauthenticatedEndpointRegistry *fake.UnauthenticatedEndpointRegistry
 	)
@@ -34,6 +35,7 @@ var _ = Describe("Authentication Middleware", func() {
 		})
 
 		requestPath = "/v3/apps"
+		impersonatedUser = ""
 
 		authInfoParser = new(fake.AuthInfoParser)
 		authInfoParser.ParseReturns(authorization.Info{Token: "the-token"}, nil)
@@ -55,6 +57,9 @@ var _ = Describe("Authentication Middleware", func() {
 		request, err := http.NewRequest(http.MethodGet, "http://localhost"+requestPath, nil)
 		Expect(err).NotTo(HaveOccurred())
 		request.Header.Add(headers.Authorization, authHeader)
+		if impersonatedUser != "" {
+			request.Header.Add(apis.ImpersonateUserHeader, impersonatedUser)
+		}
 		authMiddleware.Middleware(nextHandler).ServeHTTP(rr, request)
 	})
 
@@ -142,4 +147,43 @@ var _ = Describe("Authentication Middleware", func() {
                 }`)))
 		})
 	})
+
+	When("the request impersonates a user", func() {
+		BeforeEach(func() {
+			impersonatedUser = "bob"
+		})
+
+		It("gets the identity of the impersonated user", func() {
+			Expect(identityProvider.GetIdentityCallCount()).To(Equal(1))
+			_, actualAuthInfo := identityProvider.GetIdentityArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authorization.Info{Token: "the-token", ImpersonatedUser: "bob"}))
+
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+
+		It("injects the impersonated user in the request context", func() {
+			actualAuthInfo, ok := authorization.InfoFromContext(actualReq.Context())
+			Expect(ok).To(BeTrue())
+			Expect(actualAuthInfo.ImpersonatedUser).To(Equal("bob"))
+		})
+
+		When("the caller may not impersonate users", func() {
+			BeforeEach(func() {
+				identityProvider.GetIdentityReturns(authorization.Identity{}, apierrors.NewForbiddenError(nil, ""))
+			})
+
+			It("returns a CF-NotAuthorized error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusForbidden))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+					"errors": [
+					{
+						"detail": "You are not authorized to perform the requested action",
+						"title": "CF-NotAuthorized",
+						"code": 10003
+					}
+					]
+				}`)))
+			})
+		})
+	})
 })
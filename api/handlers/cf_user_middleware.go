@@ -7,8 +7,12 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/metrics"
+	"github.com/go-logr/logr"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/util/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,17 +20,29 @@ const (
 	cacheTTL = 120 * time.Second
 )
 
+//counterfeiter:generate -o fake -fake-name UserRecorder . UserRecorder
+type UserRecorder interface {
+	RecordUser(ctx context.Context, username string) error
+}
+
+// recordedUserKey is the key under which cfUserCache remembers that a user has been recorded, so that it cannot
+// clash with the identity hashes of CF users
+type recordedUserKey string
+
 type CFUserMiddleware struct {
 	privilegedClient                client.Client
 	identityProvider                IdentityProvider
+	userRecorder                    UserRecorder
 	cfRootNamespace                 string
 	cfUserCache                     *cache.Expiring
 	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry
+	logger                          logr.Logger
 }
 
 func NewCFUserMiddleware(
 	privilegedClient client.Client,
 	identityProvider IdentityProvider,
+	userRecorder UserRecorder,
 	cfRootNamespace string,
 	cfUserCache *cache.Expiring,
 	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry,
@@ -34,9 +50,11 @@ func NewCFUserMiddleware(
 	return &CFUserMiddleware{
 		privilegedClient:                privilegedClient,
 		identityProvider:                identityProvider,
+		userRecorder:                    userRecorder,
 		cfRootNamespace:                 cfRootNamespace,
 		cfUserCache:                     cfUserCache,
 		unauthenticatedEndpointRegistry: unauthenticatedEndpointRegistry,
+		logger:                          ctrl.Log.WithName("CFUserMiddleware"),
 	}
 }
 
@@ -59,6 +77,8 @@ func (m *CFUserMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		m.recordUser(r.Context(), identity)
+
 		isCFUser, err := m.isCFUser(r.Context(), identity)
 		if err != nil {
 			next.ServeHTTP(w, r)
@@ -75,6 +95,7 @@ func (m *CFUserMiddleware) Middleware(next http.Handler) http.Handler {
 
 func (m *CFUserMiddleware) isCFUser(ctx context.Context, identity authorization.Identity) (bool, error) {
 	_, isCFUser := m.cfUserCache.Get(identity.Hash())
+	metrics.ObserveCacheLookup(metrics.CFUserCache, isCFUser)
 	if isCFUser {
 		return true, nil
 	}
@@ -87,7 +108,7 @@ func (m *CFUserMiddleware) isCFUser(ctx context.Context, identity authorization.
 
 	for _, rb := range roleBindings.Items {
 		for _, subj := range rb.Subjects {
-			if subj.Name == identity.Name && subj.Kind == identity.Kind {
+			if identity.IsSubject(subj) {
 				m.cfUserCache.Set(identity.Hash(), struct{}{}, cacheTTL)
 				return true, nil
 			}
@@ -96,3 +117,24 @@ func (m *CFUserMiddleware) isCFUser(ctx context.Context, identity authorization.
 
 	return false, nil
 }
+
+// recordUser makes sure there is a CFUser for users that authenticate against the API. Impersonated users have not
+// authenticated themselves, so they are not recorded. Failing to record a user does not affect the request, so errors
+// are only logged
+func (m *CFUserMiddleware) recordUser(ctx context.Context, identity authorization.Identity) {
+	if identity.Kind != rbacv1.UserKind || identity.Impersonator != nil {
+		return
+	}
+
+	key := recordedUserKey(identity.Name)
+	if _, recorded := m.cfUserCache.Get(key); recorded {
+		return
+	}
+
+	if err := m.userRecorder.RecordUser(ctx, identity.Name); err != nil {
+		correlation.AddCorrelationIDToLogger(ctx, m.logger).Info("failed to record user", "user", identity.Name, "reason", err)
+		return
+	}
+
+	m.cfUserCache.Set(key, struct{}{}, cacheTTL)
+}
@@ -25,6 +25,7 @@ var _ = Describe("CfUserMiddleware", func() {
 		cfUserMiddleware                *handlers.CFUserMiddleware
 		k8sClient                       *controllersfake.Client
 		identityProvider                *fake.IdentityProvider
+		userRecorder                    *fake.UserRecorder
 		teapotHandler                   http.Handler
 		cfUserCache                     *cache.Expiring
 		unauthenticatedEndpointRegistry *fake.UnauthenticatedEndpointRegistry
@@ -57,11 +58,13 @@ var _ = Describe("CfUserMiddleware", func() {
 			Kind: rbacv1.UserKind,
 		}, nil)
 
+		userRecorder = new(fake.UserRecorder)
+
 		unauthenticatedEndpointRegistry = new(fake.UnauthenticatedEndpointRegistry)
 		unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(false)
 
 		cfUserCache = cache.NewExpiringWithClock(testing.NewFakeClock(time.Now()))
-		cfUserMiddleware = handlers.NewCFUserMiddleware(k8sClient, identityProvider, "cfroot", cfUserCache, unauthenticatedEndpointRegistry)
+		cfUserMiddleware = handlers.NewCFUserMiddleware(k8sClient, identityProvider, userRecorder, "cfroot", cfUserCache, unauthenticatedEndpointRegistry)
 	})
 
 	JustBeforeEach(func() {
@@ -81,6 +84,40 @@ var _ = Describe("CfUserMiddleware", func() {
 		Expect(actualAuthInfo).To(Equal(authInfo))
 	})
 
+	It("records the user", func() {
+		Expect(userRecorder.RecordUserCallCount()).To(Equal(1))
+		_, actualUsername := userRecorder.RecordUserArgsForCall(0)
+		Expect(actualUsername).To(Equal("bob"))
+	})
+
+	When("the user is impersonated", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{
+				Name:         "bob",
+				Kind:         rbacv1.UserKind,
+				Impersonator: &authorization.Identity{Name: "admin", Kind: rbacv1.UserKind},
+			}, nil)
+		})
+
+		It("does not record the user", func() {
+			Expect(userRecorder.RecordUserCallCount()).To(BeZero())
+		})
+
+		It("delegates to the next middleware", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
+	When("recording the user fails", func() {
+		BeforeEach(func() {
+			userRecorder.RecordUserReturns(errors.New("record-err"))
+		})
+
+		It("delegates to the next middleware", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
 	When("requesting unauthenticated endpoint", func() {
 		BeforeEach(func() {
 			unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(true)
@@ -90,6 +127,10 @@ var _ = Describe("CfUserMiddleware", func() {
 			Expect(identityProvider.GetIdentityCallCount()).To(BeZero())
 		})
 
+		It("does not record the user", func() {
+			Expect(userRecorder.RecordUserCallCount()).To(BeZero())
+		})
+
 		It("delegates to the next middleware", func() {
 			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
 		})
@@ -160,11 +201,44 @@ var _ = Describe("CfUserMiddleware", func() {
 			Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
 		})
 
+		It("does not record a user", func() {
+			Expect(userRecorder.RecordUserCallCount()).To(BeZero())
+		})
+
 		It("sets the X-Cf-Warning header", func() {
 			Expect(rr).To(HaveHTTPHeaderWithValue("X-Cf-Warnings", ContainSubstring("has no CF roles assigned")))
 		})
 	})
 
+	When("one of the groups of the user has a rolebinding in the root namespace", func() {
+		BeforeEach(func() {
+			k8sClient.ListStub = func(_ context.Context, objectsList client.ObjectList, _ ...client.ListOption) error {
+				rbList, ok := objectsList.(*rbacv1.RoleBindingList)
+				Expect(ok).To(BeTrue())
+				*rbList = rbacv1.RoleBindingList{
+					Items: []rbacv1.RoleBinding{{
+						Subjects: []rbacv1.Subject{{
+							Kind: rbacv1.GroupKind,
+							Name: "team-a",
+						}},
+					}},
+				}
+
+				return nil
+			}
+
+			identityProvider.GetIdentityReturns(authorization.Identity{
+				Name:   "jim",
+				Kind:   rbacv1.UserKind,
+				Groups: []string{"team-a"},
+			}, nil)
+		})
+
+		It("does not set the X-Cf-Warning header", func() {
+			Expect(rr).NotTo(HaveHTTPHeaderWithValue("X-Cf-Warnings", ContainSubstring("has no CF roles assigned")))
+		})
+	})
+
 	When("there are no rolebindings in the root namespace", func() {
 		BeforeEach(func() {
 			k8sClient.ListStub = func(_ context.Context, objectsList client.ObjectList, _ ...client.ListOption) error {
@@ -209,6 +283,12 @@ var _ = Describe("CfUserMiddleware", func() {
 		It("lists the role bindings again (does not cache previous result)", func() {
 			Expect(k8sClient.ListCallCount()).To(Equal(2))
 		})
+
+		It("records the other user too", func() {
+			Expect(userRecorder.RecordUserCallCount()).To(Equal(2))
+			_, actualUsername := userRecorder.RecordUserArgsForCall(1)
+			Expect(actualUsername).To(Equal("alice"))
+		})
 	})
 
 	When("on subsequent calls with the same authInfo", func() {
@@ -228,5 +308,9 @@ var _ = Describe("CfUserMiddleware", func() {
 		It("does not check identity again (caches the previous result)", func() {
 			Expect(k8sClient.ListCallCount()).To(Equal(1))
 		})
+
+		It("does not record the user again", func() {
+			Expect(userRecorder.RecordUserCallCount()).To(Equal(1))
+		})
 	})
 })
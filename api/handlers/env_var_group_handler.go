@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	EnvVarGroupPath = "/v3/environment_variable_groups/{name}"
+)
+
+//counterfeiter:generate -o fake -fake-name CFEnvVarGroupRepository . CFEnvVarGroupRepository
+type CFEnvVarGroupRepository interface {
+	GetEnvVarGroup(context.Context, authorization.Info, string) (repositories.EnvVarGroupRecord, error)
+	PatchEnvVarGroup(context.Context, authorization.Info, repositories.PatchEnvVarGroupMessage) (repositories.EnvVarGroupRecord, error)
+}
+
+type EnvVarGroupHandler struct {
+	handlerWrapper   *AuthAwareHandlerFuncWrapper
+	apiBaseURL       url.URL
+	envVarGroupRepo  CFEnvVarGroupRepository
+	decoderValidator *DecoderValidator
+}
+
+func NewEnvVarGroupHandler(apiBaseURL url.URL, envVarGroupRepo CFEnvVarGroupRepository, decoderValidator *DecoderValidator) *EnvVarGroupHandler {
+	return &EnvVarGroupHandler{
+		handlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("EnvVarGroupHandler")),
+		apiBaseURL:       apiBaseURL,
+		envVarGroupRepo:  envVarGroupRepo,
+		decoderValidator: decoderValidator,
+	}
+}
+
+func (h *EnvVarGroupHandler) envVarGroupGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	name := mux.Vars(r)["name"]
+
+	record, err := h.envVarGroupRepo.GetEnvVarGroup(ctx, authInfo, name)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch environment variable group", "Name", name)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForEnvVarGroup(record, h.apiBaseURL)), nil
+}
+
+func (h *EnvVarGroupHandler) envVarGroupPatchHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	name := mux.Vars(r)["name"]
+
+	var payload payloads.EnvVarGroupPatch
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-patch-env-var-group")
+	}
+
+	record, err := h.envVarGroupRepo.PatchEnvVarGroup(ctx, authInfo, payload.ToMessage(name))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to patch environment variable group", "Name", name)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForEnvVarGroup(record, h.apiBaseURL)), nil
+}
+
+func (h *EnvVarGroupHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(EnvVarGroupPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.envVarGroupGetHandler))
+	router.Path(EnvVarGroupPath).Methods("PATCH").HandlerFunc(h.handlerWrapper.Wrap(h.envVarGroupPatchHandler))
+}
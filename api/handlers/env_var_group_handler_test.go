@@ -0,0 +1,169 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("EnvVarGroupHandler", func() {
+	var (
+		envVarGroupRepo *fake.CFEnvVarGroupRepository
+		requestMethod   string
+		requestPath     string
+		requestBody     string
+		record          repositories.EnvVarGroupRecord
+	)
+
+	BeforeEach(func() {
+		requestBody = ""
+		envVarGroupRepo = new(fake.CFEnvVarGroupRepository)
+		decoderValidator, err := apis.NewDefaultDecoderValidator()
+		Expect(err).NotTo(HaveOccurred())
+
+		record = repositories.EnvVarGroupRecord{
+			Name:                 "running",
+			EnvironmentVariables: map[string]string{"https_proxy": "proxy.example.com"},
+			UpdatedAt:            "2021-09-17T15:23:10Z",
+		}
+
+		apis.NewEnvVarGroupHandler(*serverURL, envVarGroupRepo, decoderValidator).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	Describe("GET /v3/environment_variable_groups/{name}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/environment_variable_groups/running"
+			envVarGroupRepo.GetEnvVarGroupReturns(record, nil)
+		})
+
+		It("returns the group", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"name": "running",
+				"var": {
+					"https_proxy": "proxy.example.com"
+				},
+				"updated_at": "2021-09-17T15:23:10Z",
+				"links": {
+					"self": {
+						"href": "%s/v3/environment_variable_groups/running"
+					}
+				}
+			}`, defaultServerURL))))
+
+			Expect(envVarGroupRepo.GetEnvVarGroupCallCount()).To(Equal(1))
+			_, actualAuthInfo, name := envVarGroupRepo.GetEnvVarGroupArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(name).To(Equal("running"))
+		})
+
+		When("the group has never been set", func() {
+			BeforeEach(func() {
+				envVarGroupRepo.GetEnvVarGroupReturns(repositories.EnvVarGroupRecord{
+					Name:                 "running",
+					EnvironmentVariables: map[string]string{},
+				}, nil)
+			})
+
+			It("returns an empty group without an update time", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+					"name": "running",
+					"var": {},
+					"updated_at": null,
+					"links": {
+						"self": {
+							"href": "%s/v3/environment_variable_groups/running"
+						}
+					}
+				}`, defaultServerURL))))
+			})
+		})
+
+		When("the group is unknown", func() {
+			BeforeEach(func() {
+				envVarGroupRepo.GetEnvVarGroupReturns(repositories.EnvVarGroupRecord{}, apierrors.NewNotFoundError(nil, repositories.EnvVarGroupResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Environment Variable Group not found")
+			})
+		})
+	})
+
+	Describe("PATCH /v3/environment_variable_groups/{name}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/environment_variable_groups/running"
+			requestBody = `{
+				"var": {
+					"https_proxy": "proxy.example.com",
+					"no_proxy": null
+				}
+			}`
+			envVarGroupRepo.PatchEnvVarGroupReturns(record, nil)
+		})
+
+		It("patches the group", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"name": "running",
+				"var": {
+					"https_proxy": "proxy.example.com"
+				},
+				"updated_at": "2021-09-17T15:23:10Z",
+				"links": {
+					"self": {
+						"href": "%s/v3/environment_variable_groups/running"
+					}
+				}
+			}`, defaultServerURL))))
+
+			Expect(envVarGroupRepo.PatchEnvVarGroupCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := envVarGroupRepo.PatchEnvVarGroupArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message.Name).To(Equal("running"))
+			Expect(message.EnvironmentVariables).To(HaveLen(2))
+			Expect(message.EnvironmentVariables).To(HaveKeyWithValue("https_proxy", PointTo(Equal("proxy.example.com"))))
+			Expect(message.EnvironmentVariables).To(HaveKeyWithValue("no_proxy", BeNil()))
+		})
+
+		When("the payload sets a reserved variable", func() {
+			BeforeEach(func() {
+				requestBody = `{ "var": { "PORT": "8080" } }`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnprocessableEntity))
+				Expect(envVarGroupRepo.PatchEnvVarGroupCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the user is not an admin", func() {
+			BeforeEach(func() {
+				envVarGroupRepo.PatchEnvVarGroupReturns(repositories.EnvVarGroupRecord{}, apierrors.NewForbiddenError(nil, repositories.EnvVarGroupResourceType))
+			})
+
+			It("returns a not authorized error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+	})
+})
@@ -0,0 +1,77 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/accesslog"
+	"code.cloudfoundry.org/korifi/api/handlers"
+)
+
+type AccessLogger struct {
+	LogStub        func(accesslog.Entry)
+	logMutex       sync.RWMutex
+	logArgsForCall []struct {
+		arg1 accesslog.Entry
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *AccessLogger) Log(arg1 accesslog.Entry) {
+	fake.logMutex.Lock()
+	fake.logArgsForCall = append(fake.logArgsForCall, struct {
+		arg1 accesslog.Entry
+	}{arg1})
+	stub := fake.LogStub
+	fake.recordInvocation("Log", []interface{}{arg1})
+	fake.logMutex.Unlock()
+	if stub != nil {
+		fake.LogStub(arg1)
+	}
+}
+
+func (fake *AccessLogger) LogCallCount() int {
+	fake.logMutex.RLock()
+	defer fake.logMutex.RUnlock()
+	return len(fake.logArgsForCall)
+}
+
+func (fake *AccessLogger) LogCalls(stub func(accesslog.Entry)) {
+	fake.logMutex.Lock()
+	defer fake.logMutex.Unlock()
+	fake.LogStub = stub
+}
+
+func (fake *AccessLogger) LogArgsForCall(i int) accesslog.Entry {
+	fake.logMutex.RLock()
+	defer fake.logMutex.RUnlock()
+	argsForCall := fake.logArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *AccessLogger) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.logMutex.RLock()
+	defer fake.logMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *AccessLogger) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.AccessLogger = new(AccessLogger)
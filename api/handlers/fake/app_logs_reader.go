@@ -30,6 +30,24 @@ type AppLogsReader struct {
 		result1 []repositories.LogRecord
 		result2 error
 	}
+	StreamStub        func(context.Context, logr.Logger, authorization.Info, string) (<-chan repositories.LogRecord, func(), error)
+	streamMutex       sync.RWMutex
+	streamArgsForCall []struct {
+		arg1 context.Context
+		arg2 logr.Logger
+		arg3 authorization.Info
+		arg4 string
+	}
+	streamReturns struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}
+	streamReturnsOnCall map[int]struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -102,11 +120,83 @@ func (fake *AppLogsReader) ReadReturnsOnCall(i int, result1 []repositories.LogRe
 	}{result1, result2}
 }
 
+func (fake *AppLogsReader) Stream(arg1 context.Context, arg2 logr.Logger, arg3 authorization.Info, arg4 string) (<-chan repositories.LogRecord, func(), error) {
+	fake.streamMutex.Lock()
+	ret, specificReturn := fake.streamReturnsOnCall[len(fake.streamArgsForCall)]
+	fake.streamArgsForCall = append(fake.streamArgsForCall, struct {
+		arg1 context.Context
+		arg2 logr.Logger
+		arg3 authorization.Info
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.StreamStub
+	fakeReturns := fake.streamReturns
+	fake.recordInvocation("Stream", []interface{}{arg1, arg2, arg3, arg4})
+	fake.streamMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *AppLogsReader) StreamCallCount() int {
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
+	return len(fake.streamArgsForCall)
+}
+
+func (fake *AppLogsReader) StreamCalls(stub func(context.Context, logr.Logger, authorization.Info, string) (<-chan repositories.LogRecord, func(), error)) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = stub
+}
+
+func (fake *AppLogsReader) StreamArgsForCall(i int) (context.Context, logr.Logger, authorization.Info, string) {
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
+	argsForCall := fake.streamArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *AppLogsReader) StreamReturns(result1 <-chan repositories.LogRecord, result2 func(), result3 error) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = nil
+	fake.streamReturns = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *AppLogsReader) StreamReturnsOnCall(i int, result1 <-chan repositories.LogRecord, result2 func(), result3 error) {
+	fake.streamMutex.Lock()
+	defer fake.streamMutex.Unlock()
+	fake.StreamStub = nil
+	if fake.streamReturnsOnCall == nil {
+		fake.streamReturnsOnCall = make(map[int]struct {
+			result1 <-chan repositories.LogRecord
+			result2 func()
+			result3 error
+		})
+	}
+	fake.streamReturnsOnCall[i] = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *AppLogsReader) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.readMutex.RLock()
 	defer fake.readMutex.RUnlock()
+	fake.streamMutex.RLock()
+	defer fake.streamMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
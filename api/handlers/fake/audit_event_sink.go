@@ -0,0 +1,115 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type AuditEventSink struct {
+	RecordAuditEventStub        func(context.Context, repositories.CreateAuditEventMessage) error
+	recordAuditEventMutex       sync.RWMutex
+	recordAuditEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 repositories.CreateAuditEventMessage
+	}
+	recordAuditEventReturns struct {
+		result1 error
+	}
+	recordAuditEventReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *AuditEventSink) RecordAuditEvent(arg1 context.Context, arg2 repositories.CreateAuditEventMessage) error {
+	fake.recordAuditEventMutex.Lock()
+	ret, specificReturn := fake.recordAuditEventReturnsOnCall[len(fake.recordAuditEventArgsForCall)]
+	fake.recordAuditEventArgsForCall = append(fake.recordAuditEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 repositories.CreateAuditEventMessage
+	}{arg1, arg2})
+	stub := fake.RecordAuditEventStub
+	fakeReturns := fake.recordAuditEventReturns
+	fake.recordInvocation("RecordAuditEvent", []interface{}{arg1, arg2})
+	fake.recordAuditEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *AuditEventSink) RecordAuditEventCallCount() int {
+	fake.recordAuditEventMutex.RLock()
+	defer fake.recordAuditEventMutex.RUnlock()
+	return len(fake.recordAuditEventArgsForCall)
+}
+
+func (fake *AuditEventSink) RecordAuditEventCalls(stub func(context.Context, repositories.CreateAuditEventMessage) error) {
+	fake.recordAuditEventMutex.Lock()
+	defer fake.recordAuditEventMutex.Unlock()
+	fake.RecordAuditEventStub = stub
+}
+
+func (fake *AuditEventSink) RecordAuditEventArgsForCall(i int) (context.Context, repositories.CreateAuditEventMessage) {
+	fake.recordAuditEventMutex.RLock()
+	defer fake.recordAuditEventMutex.RUnlock()
+	argsForCall := fake.recordAuditEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *AuditEventSink) RecordAuditEventReturns(result1 error) {
+	fake.recordAuditEventMutex.Lock()
+	defer fake.recordAuditEventMutex.Unlock()
+	fake.RecordAuditEventStub = nil
+	fake.recordAuditEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *AuditEventSink) RecordAuditEventReturnsOnCall(i int, result1 error) {
+	fake.recordAuditEventMutex.Lock()
+	defer fake.recordAuditEventMutex.Unlock()
+	fake.RecordAuditEventStub = nil
+	if fake.recordAuditEventReturnsOnCall == nil {
+		fake.recordAuditEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordAuditEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *AuditEventSink) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordAuditEventMutex.RLock()
+	defer fake.recordAuditEventMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *AuditEventSink) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.AuditEventSink = new(AuditEventSink)
@@ -0,0 +1,282 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type CFAppUsageEventRepository struct {
+	GetAppUsageEventStub        func(context.Context, authorization.Info, string) (repositories.AppUsageEventRecord, error)
+	getAppUsageEventMutex       sync.RWMutex
+	getAppUsageEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}
+	getAppUsageEventReturns struct {
+		result1 repositories.AppUsageEventRecord
+		result2 error
+	}
+	getAppUsageEventReturnsOnCall map[int]struct {
+		result1 repositories.AppUsageEventRecord
+		result2 error
+	}
+	ListAppUsageEventsStub        func(context.Context, authorization.Info, repositories.ListAppUsageEventsMessage) ([]repositories.AppUsageEventRecord, error)
+	listAppUsageEventsMutex       sync.RWMutex
+	listAppUsageEventsArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListAppUsageEventsMessage
+	}
+	listAppUsageEventsReturns struct {
+		result1 []repositories.AppUsageEventRecord
+		result2 error
+	}
+	listAppUsageEventsReturnsOnCall map[int]struct {
+		result1 []repositories.AppUsageEventRecord
+		result2 error
+	}
+	PurgeAndReseedStub        func(context.Context, authorization.Info) error
+	purgeAndReseedMutex       sync.RWMutex
+	purgeAndReseedArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+	}
+	purgeAndReseedReturns struct {
+		result1 error
+	}
+	purgeAndReseedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEvent(arg1 context.Context, arg2 authorization.Info, arg3 string) (repositories.AppUsageEventRecord, error) {
+	fake.getAppUsageEventMutex.Lock()
+	ret, specificReturn := fake.getAppUsageEventReturnsOnCall[len(fake.getAppUsageEventArgsForCall)]
+	fake.getAppUsageEventArgsForCall = append(fake.getAppUsageEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetAppUsageEventStub
+	fakeReturns := fake.getAppUsageEventReturns
+	fake.recordInvocation("GetAppUsageEvent", []interface{}{arg1, arg2, arg3})
+	fake.getAppUsageEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEventCallCount() int {
+	fake.getAppUsageEventMutex.RLock()
+	defer fake.getAppUsageEventMutex.RUnlock()
+	return len(fake.getAppUsageEventArgsForCall)
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEventCalls(stub func(context.Context, authorization.Info, string) (repositories.AppUsageEventRecord, error)) {
+	fake.getAppUsageEventMutex.Lock()
+	defer fake.getAppUsageEventMutex.Unlock()
+	fake.GetAppUsageEventStub = stub
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEventArgsForCall(i int) (context.Context, authorization.Info, string) {
+	fake.getAppUsageEventMutex.RLock()
+	defer fake.getAppUsageEventMutex.RUnlock()
+	argsForCall := fake.getAppUsageEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEventReturns(result1 repositories.AppUsageEventRecord, result2 error) {
+	fake.getAppUsageEventMutex.Lock()
+	defer fake.getAppUsageEventMutex.Unlock()
+	fake.GetAppUsageEventStub = nil
+	fake.getAppUsageEventReturns = struct {
+		result1 repositories.AppUsageEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAppUsageEventRepository) GetAppUsageEventReturnsOnCall(i int, result1 repositories.AppUsageEventRecord, result2 error) {
+	fake.getAppUsageEventMutex.Lock()
+	defer fake.getAppUsageEventMutex.Unlock()
+	fake.GetAppUsageEventStub = nil
+	if fake.getAppUsageEventReturnsOnCall == nil {
+		fake.getAppUsageEventReturnsOnCall = make(map[int]struct {
+			result1 repositories.AppUsageEventRecord
+			result2 error
+		})
+	}
+	fake.getAppUsageEventReturnsOnCall[i] = struct {
+		result1 repositories.AppUsageEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEvents(arg1 context.Context, arg2 authorization.Info, arg3 repositories.ListAppUsageEventsMessage) ([]repositories.AppUsageEventRecord, error) {
+	fake.listAppUsageEventsMutex.Lock()
+	ret, specificReturn := fake.listAppUsageEventsReturnsOnCall[len(fake.listAppUsageEventsArgsForCall)]
+	fake.listAppUsageEventsArgsForCall = append(fake.listAppUsageEventsArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListAppUsageEventsMessage
+	}{arg1, arg2, arg3})
+	stub := fake.ListAppUsageEventsStub
+	fakeReturns := fake.listAppUsageEventsReturns
+	fake.recordInvocation("ListAppUsageEvents", []interface{}{arg1, arg2, arg3})
+	fake.listAppUsageEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEventsCallCount() int {
+	fake.listAppUsageEventsMutex.RLock()
+	defer fake.listAppUsageEventsMutex.RUnlock()
+	return len(fake.listAppUsageEventsArgsForCall)
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEventsCalls(stub func(context.Context, authorization.Info, repositories.ListAppUsageEventsMessage) ([]repositories.AppUsageEventRecord, error)) {
+	fake.listAppUsageEventsMutex.Lock()
+	defer fake.listAppUsageEventsMutex.Unlock()
+	fake.ListAppUsageEventsStub = stub
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEventsArgsForCall(i int) (context.Context, authorization.Info, repositories.ListAppUsageEventsMessage) {
+	fake.listAppUsageEventsMutex.RLock()
+	defer fake.listAppUsageEventsMutex.RUnlock()
+	argsForCall := fake.listAppUsageEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEventsReturns(result1 []repositories.AppUsageEventRecord, result2 error) {
+	fake.listAppUsageEventsMutex.Lock()
+	defer fake.listAppUsageEventsMutex.Unlock()
+	fake.ListAppUsageEventsStub = nil
+	fake.listAppUsageEventsReturns = struct {
+		result1 []repositories.AppUsageEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAppUsageEventRepository) ListAppUsageEventsReturnsOnCall(i int, result1 []repositories.AppUsageEventRecord, result2 error) {
+	fake.listAppUsageEventsMutex.Lock()
+	defer fake.listAppUsageEventsMutex.Unlock()
+	fake.ListAppUsageEventsStub = nil
+	if fake.listAppUsageEventsReturnsOnCall == nil {
+		fake.listAppUsageEventsReturnsOnCall = make(map[int]struct {
+			result1 []repositories.AppUsageEventRecord
+			result2 error
+		})
+	}
+	fake.listAppUsageEventsReturnsOnCall[i] = struct {
+		result1 []repositories.AppUsageEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseed(arg1 context.Context, arg2 authorization.Info) error {
+	fake.purgeAndReseedMutex.Lock()
+	ret, specificReturn := fake.purgeAndReseedReturnsOnCall[len(fake.purgeAndReseedArgsForCall)]
+	fake.purgeAndReseedArgsForCall = append(fake.purgeAndReseedArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+	}{arg1, arg2})
+	stub := fake.PurgeAndReseedStub
+	fakeReturns := fake.purgeAndReseedReturns
+	fake.recordInvocation("PurgeAndReseed", []interface{}{arg1, arg2})
+	fake.purgeAndReseedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseedCallCount() int {
+	fake.purgeAndReseedMutex.RLock()
+	defer fake.purgeAndReseedMutex.RUnlock()
+	return len(fake.purgeAndReseedArgsForCall)
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseedCalls(stub func(context.Context, authorization.Info) error) {
+	fake.purgeAndReseedMutex.Lock()
+	defer fake.purgeAndReseedMutex.Unlock()
+	fake.PurgeAndReseedStub = stub
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseedArgsForCall(i int) (context.Context, authorization.Info) {
+	fake.purgeAndReseedMutex.RLock()
+	defer fake.purgeAndReseedMutex.RUnlock()
+	argsForCall := fake.purgeAndReseedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseedReturns(result1 error) {
+	fake.purgeAndReseedMutex.Lock()
+	defer fake.purgeAndReseedMutex.Unlock()
+	fake.PurgeAndReseedStub = nil
+	fake.purgeAndReseedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *CFAppUsageEventRepository) PurgeAndReseedReturnsOnCall(i int, result1 error) {
+	fake.purgeAndReseedMutex.Lock()
+	defer fake.purgeAndReseedMutex.Unlock()
+	fake.PurgeAndReseedStub = nil
+	if fake.purgeAndReseedReturnsOnCall == nil {
+		fake.purgeAndReseedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.purgeAndReseedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *CFAppUsageEventRepository) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAppUsageEventMutex.RLock()
+	defer fake.getAppUsageEventMutex.RUnlock()
+	fake.listAppUsageEventsMutex.RLock()
+	defer fake.listAppUsageEventsMutex.RUnlock()
+	fake.purgeAndReseedMutex.RLock()
+	defer fake.purgeAndReseedMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CFAppUsageEventRepository) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CFAppUsageEventRepository = new(CFAppUsageEventRepository)
@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type CFAuditEventRepository struct {
+	GetAuditEventStub        func(context.Context, authorization.Info, string) (repositories.AuditEventRecord, error)
+	getAuditEventMutex       sync.RWMutex
+	getAuditEventArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}
+	getAuditEventReturns struct {
+		result1 repositories.AuditEventRecord
+		result2 error
+	}
+	getAuditEventReturnsOnCall map[int]struct {
+		result1 repositories.AuditEventRecord
+		result2 error
+	}
+	ListAuditEventsStub        func(context.Context, authorization.Info, repositories.ListAuditEventsMessage) ([]repositories.AuditEventRecord, error)
+	listAuditEventsMutex       sync.RWMutex
+	listAuditEventsArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListAuditEventsMessage
+	}
+	listAuditEventsReturns struct {
+		result1 []repositories.AuditEventRecord
+		result2 error
+	}
+	listAuditEventsReturnsOnCall map[int]struct {
+		result1 []repositories.AuditEventRecord
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CFAuditEventRepository) GetAuditEvent(arg1 context.Context, arg2 authorization.Info, arg3 string) (repositories.AuditEventRecord, error) {
+	fake.getAuditEventMutex.Lock()
+	ret, specificReturn := fake.getAuditEventReturnsOnCall[len(fake.getAuditEventArgsForCall)]
+	fake.getAuditEventArgsForCall = append(fake.getAuditEventArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetAuditEventStub
+	fakeReturns := fake.getAuditEventReturns
+	fake.recordInvocation("GetAuditEvent", []interface{}{arg1, arg2, arg3})
+	fake.getAuditEventMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFAuditEventRepository) GetAuditEventCallCount() int {
+	fake.getAuditEventMutex.RLock()
+	defer fake.getAuditEventMutex.RUnlock()
+	return len(fake.getAuditEventArgsForCall)
+}
+
+func (fake *CFAuditEventRepository) GetAuditEventCalls(stub func(context.Context, authorization.Info, string) (repositories.AuditEventRecord, error)) {
+	fake.getAuditEventMutex.Lock()
+	defer fake.getAuditEventMutex.Unlock()
+	fake.GetAuditEventStub = stub
+}
+
+func (fake *CFAuditEventRepository) GetAuditEventArgsForCall(i int) (context.Context, authorization.Info, string) {
+	fake.getAuditEventMutex.RLock()
+	defer fake.getAuditEventMutex.RUnlock()
+	argsForCall := fake.getAuditEventArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFAuditEventRepository) GetAuditEventReturns(result1 repositories.AuditEventRecord, result2 error) {
+	fake.getAuditEventMutex.Lock()
+	defer fake.getAuditEventMutex.Unlock()
+	fake.GetAuditEventStub = nil
+	fake.getAuditEventReturns = struct {
+		result1 repositories.AuditEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAuditEventRepository) GetAuditEventReturnsOnCall(i int, result1 repositories.AuditEventRecord, result2 error) {
+	fake.getAuditEventMutex.Lock()
+	defer fake.getAuditEventMutex.Unlock()
+	fake.GetAuditEventStub = nil
+	if fake.getAuditEventReturnsOnCall == nil {
+		fake.getAuditEventReturnsOnCall = make(map[int]struct {
+			result1 repositories.AuditEventRecord
+			result2 error
+		})
+	}
+	fake.getAuditEventReturnsOnCall[i] = struct {
+		result1 repositories.AuditEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAuditEventRepository) ListAuditEvents(arg1 context.Context, arg2 authorization.Info, arg3 repositories.ListAuditEventsMessage) ([]repositories.AuditEventRecord, error) {
+	fake.listAuditEventsMutex.Lock()
+	ret, specificReturn := fake.listAuditEventsReturnsOnCall[len(fake.listAuditEventsArgsForCall)]
+	fake.listAuditEventsArgsForCall = append(fake.listAuditEventsArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListAuditEventsMessage
+	}{arg1, arg2, arg3})
+	stub := fake.ListAuditEventsStub
+	fakeReturns := fake.listAuditEventsReturns
+	fake.recordInvocation("ListAuditEvents", []interface{}{arg1, arg2, arg3})
+	fake.listAuditEventsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFAuditEventRepository) ListAuditEventsCallCount() int {
+	fake.listAuditEventsMutex.RLock()
+	defer fake.listAuditEventsMutex.RUnlock()
+	return len(fake.listAuditEventsArgsForCall)
+}
+
+func (fake *CFAuditEventRepository) ListAuditEventsCalls(stub func(context.Context, authorization.Info, repositories.ListAuditEventsMessage) ([]repositories.AuditEventRecord, error)) {
+	fake.listAuditEventsMutex.Lock()
+	defer fake.listAuditEventsMutex.Unlock()
+	fake.ListAuditEventsStub = stub
+}
+
+func (fake *CFAuditEventRepository) ListAuditEventsArgsForCall(i int) (context.Context, authorization.Info, repositories.ListAuditEventsMessage) {
+	fake.listAuditEventsMutex.RLock()
+	defer fake.listAuditEventsMutex.RUnlock()
+	argsForCall := fake.listAuditEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFAuditEventRepository) ListAuditEventsReturns(result1 []repositories.AuditEventRecord, result2 error) {
+	fake.listAuditEventsMutex.Lock()
+	defer fake.listAuditEventsMutex.Unlock()
+	fake.ListAuditEventsStub = nil
+	fake.listAuditEventsReturns = struct {
+		result1 []repositories.AuditEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAuditEventRepository) ListAuditEventsReturnsOnCall(i int, result1 []repositories.AuditEventRecord, result2 error) {
+	fake.listAuditEventsMutex.Lock()
+	defer fake.listAuditEventsMutex.Unlock()
+	fake.ListAuditEventsStub = nil
+	if fake.listAuditEventsReturnsOnCall == nil {
+		fake.listAuditEventsReturnsOnCall = make(map[int]struct {
+			result1 []repositories.AuditEventRecord
+			result2 error
+		})
+	}
+	fake.listAuditEventsReturnsOnCall[i] = struct {
+		result1 []repositories.AuditEventRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFAuditEventRepository) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAuditEventMutex.RLock()
+	defer fake.getAuditEventMutex.RUnlock()
+	fake.listAuditEventsMutex.RLock()
+	defer fake.listAuditEventsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CFAuditEventRepository) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CFAuditEventRepository = new(CFAuditEventRepository)
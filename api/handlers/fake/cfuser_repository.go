@@ -0,0 +1,289 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type CFUserRepository struct {
+	CreateUserStub        func(context.Context, authorization.Info, repositories.CreateUserMessage) (repositories.UserRecord, error)
+	createUserMutex       sync.RWMutex
+	createUserArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.CreateUserMessage
+	}
+	createUserReturns struct {
+		result1 repositories.UserRecord
+		result2 error
+	}
+	createUserReturnsOnCall map[int]struct {
+		result1 repositories.UserRecord
+		result2 error
+	}
+	GetUserStub        func(context.Context, authorization.Info, string) (repositories.UserRecord, error)
+	getUserMutex       sync.RWMutex
+	getUserArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}
+	getUserReturns struct {
+		result1 repositories.UserRecord
+		result2 error
+	}
+	getUserReturnsOnCall map[int]struct {
+		result1 repositories.UserRecord
+		result2 error
+	}
+	ListUsersStub        func(context.Context, authorization.Info, repositories.ListUsersMessage) ([]repositories.UserRecord, error)
+	listUsersMutex       sync.RWMutex
+	listUsersArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListUsersMessage
+	}
+	listUsersReturns struct {
+		result1 []repositories.UserRecord
+		result2 error
+	}
+	listUsersReturnsOnCall map[int]struct {
+		result1 []repositories.UserRecord
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CFUserRepository) CreateUser(arg1 context.Context, arg2 authorization.Info, arg3 repositories.CreateUserMessage) (repositories.UserRecord, error) {
+	fake.createUserMutex.Lock()
+	ret, specificReturn := fake.createUserReturnsOnCall[len(fake.createUserArgsForCall)]
+	fake.createUserArgsForCall = append(fake.createUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.CreateUserMessage
+	}{arg1, arg2, arg3})
+	stub := fake.CreateUserStub
+	fakeReturns := fake.createUserReturns
+	fake.recordInvocation("CreateUser", []interface{}{arg1, arg2, arg3})
+	fake.createUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFUserRepository) CreateUserCallCount() int {
+	fake.createUserMutex.RLock()
+	defer fake.createUserMutex.RUnlock()
+	return len(fake.createUserArgsForCall)
+}
+
+func (fake *CFUserRepository) CreateUserCalls(stub func(context.Context, authorization.Info, repositories.CreateUserMessage) (repositories.UserRecord, error)) {
+	fake.createUserMutex.Lock()
+	defer fake.createUserMutex.Unlock()
+	fake.CreateUserStub = stub
+}
+
+func (fake *CFUserRepository) CreateUserArgsForCall(i int) (context.Context, authorization.Info, repositories.CreateUserMessage) {
+	fake.createUserMutex.RLock()
+	defer fake.createUserMutex.RUnlock()
+	argsForCall := fake.createUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFUserRepository) CreateUserReturns(result1 repositories.UserRecord, result2 error) {
+	fake.createUserMutex.Lock()
+	defer fake.createUserMutex.Unlock()
+	fake.CreateUserStub = nil
+	fake.createUserReturns = struct {
+		result1 repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) CreateUserReturnsOnCall(i int, result1 repositories.UserRecord, result2 error) {
+	fake.createUserMutex.Lock()
+	defer fake.createUserMutex.Unlock()
+	fake.CreateUserStub = nil
+	if fake.createUserReturnsOnCall == nil {
+		fake.createUserReturnsOnCall = make(map[int]struct {
+			result1 repositories.UserRecord
+			result2 error
+		})
+	}
+	fake.createUserReturnsOnCall[i] = struct {
+		result1 repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) GetUser(arg1 context.Context, arg2 authorization.Info, arg3 string) (repositories.UserRecord, error) {
+	fake.getUserMutex.Lock()
+	ret, specificReturn := fake.getUserReturnsOnCall[len(fake.getUserArgsForCall)]
+	fake.getUserArgsForCall = append(fake.getUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetUserStub
+	fakeReturns := fake.getUserReturns
+	fake.recordInvocation("GetUser", []interface{}{arg1, arg2, arg3})
+	fake.getUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFUserRepository) GetUserCallCount() int {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	return len(fake.getUserArgsForCall)
+}
+
+func (fake *CFUserRepository) GetUserCalls(stub func(context.Context, authorization.Info, string) (repositories.UserRecord, error)) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = stub
+}
+
+func (fake *CFUserRepository) GetUserArgsForCall(i int) (context.Context, authorization.Info, string) {
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	argsForCall := fake.getUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFUserRepository) GetUserReturns(result1 repositories.UserRecord, result2 error) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = nil
+	fake.getUserReturns = struct {
+		result1 repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) GetUserReturnsOnCall(i int, result1 repositories.UserRecord, result2 error) {
+	fake.getUserMutex.Lock()
+	defer fake.getUserMutex.Unlock()
+	fake.GetUserStub = nil
+	if fake.getUserReturnsOnCall == nil {
+		fake.getUserReturnsOnCall = make(map[int]struct {
+			result1 repositories.UserRecord
+			result2 error
+		})
+	}
+	fake.getUserReturnsOnCall[i] = struct {
+		result1 repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) ListUsers(arg1 context.Context, arg2 authorization.Info, arg3 repositories.ListUsersMessage) ([]repositories.UserRecord, error) {
+	fake.listUsersMutex.Lock()
+	ret, specificReturn := fake.listUsersReturnsOnCall[len(fake.listUsersArgsForCall)]
+	fake.listUsersArgsForCall = append(fake.listUsersArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 repositories.ListUsersMessage
+	}{arg1, arg2, arg3})
+	stub := fake.ListUsersStub
+	fakeReturns := fake.listUsersReturns
+	fake.recordInvocation("ListUsers", []interface{}{arg1, arg2, arg3})
+	fake.listUsersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CFUserRepository) ListUsersCallCount() int {
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	return len(fake.listUsersArgsForCall)
+}
+
+func (fake *CFUserRepository) ListUsersCalls(stub func(context.Context, authorization.Info, repositories.ListUsersMessage) ([]repositories.UserRecord, error)) {
+	fake.listUsersMutex.Lock()
+	defer fake.listUsersMutex.Unlock()
+	fake.ListUsersStub = stub
+}
+
+func (fake *CFUserRepository) ListUsersArgsForCall(i int) (context.Context, authorization.Info, repositories.ListUsersMessage) {
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	argsForCall := fake.listUsersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CFUserRepository) ListUsersReturns(result1 []repositories.UserRecord, result2 error) {
+	fake.listUsersMutex.Lock()
+	defer fake.listUsersMutex.Unlock()
+	fake.ListUsersStub = nil
+	fake.listUsersReturns = struct {
+		result1 []repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) ListUsersReturnsOnCall(i int, result1 []repositories.UserRecord, result2 error) {
+	fake.listUsersMutex.Lock()
+	defer fake.listUsersMutex.Unlock()
+	fake.ListUsersStub = nil
+	if fake.listUsersReturnsOnCall == nil {
+		fake.listUsersReturnsOnCall = make(map[int]struct {
+			result1 []repositories.UserRecord
+			result2 error
+		})
+	}
+	fake.listUsersReturnsOnCall[i] = struct {
+		result1 []repositories.UserRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CFUserRepository) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createUserMutex.RLock()
+	defer fake.createUserMutex.RUnlock()
+	fake.getUserMutex.RLock()
+	defer fake.getUserMutex.RUnlock()
+	fake.listUsersMutex.RLock()
+	defer fake.listUsersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CFUserRepository) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CFUserRepository = new(CFUserRepository)
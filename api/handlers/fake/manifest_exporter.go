@@ -0,0 +1,123 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/payloads"
+)
+
+type ManifestExporter struct {
+	ExportStub        func(context.Context, authorization.Info, string) (payloads.Manifest, error)
+	exportMutex       sync.RWMutex
+	exportArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}
+	exportReturns struct {
+		result1 payloads.Manifest
+		result2 error
+	}
+	exportReturnsOnCall map[int]struct {
+		result1 payloads.Manifest
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ManifestExporter) Export(arg1 context.Context, arg2 authorization.Info, arg3 string) (payloads.Manifest, error) {
+	fake.exportMutex.Lock()
+	ret, specificReturn := fake.exportReturnsOnCall[len(fake.exportArgsForCall)]
+	fake.exportArgsForCall = append(fake.exportArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ExportStub
+	fakeReturns := fake.exportReturns
+	fake.recordInvocation("Export", []interface{}{arg1, arg2, arg3})
+	fake.exportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ManifestExporter) ExportCallCount() int {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return len(fake.exportArgsForCall)
+}
+
+func (fake *ManifestExporter) ExportCalls(stub func(context.Context, authorization.Info, string) (payloads.Manifest, error)) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = stub
+}
+
+func (fake *ManifestExporter) ExportArgsForCall(i int) (context.Context, authorization.Info, string) {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	argsForCall := fake.exportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ManifestExporter) ExportReturns(result1 payloads.Manifest, result2 error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = nil
+	fake.exportReturns = struct {
+		result1 payloads.Manifest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ManifestExporter) ExportReturnsOnCall(i int, result1 payloads.Manifest, result2 error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = nil
+	if fake.exportReturnsOnCall == nil {
+		fake.exportReturnsOnCall = make(map[int]struct {
+			result1 payloads.Manifest
+			result2 error
+		})
+	}
+	fake.exportReturnsOnCall[i] = struct {
+		result1 payloads.Manifest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ManifestExporter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ManifestExporter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.ManifestExporter = new(ManifestExporter)
@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+)
+
+type PermissionChecker struct {
+	AuthorizeStub        func(context.Context, authorization.Info, authorization.Action, string) error
+	authorizeMutex       sync.RWMutex
+	authorizeArgsForCall []struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 authorization.Action
+		arg4 string
+	}
+	authorizeReturns struct {
+		result1 error
+	}
+	authorizeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PermissionChecker) Authorize(arg1 context.Context, arg2 authorization.Info, arg3 authorization.Action, arg4 string) error {
+	fake.authorizeMutex.Lock()
+	ret, specificReturn := fake.authorizeReturnsOnCall[len(fake.authorizeArgsForCall)]
+	fake.authorizeArgsForCall = append(fake.authorizeArgsForCall, struct {
+		arg1 context.Context
+		arg2 authorization.Info
+		arg3 authorization.Action
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.AuthorizeStub
+	fakeReturns := fake.authorizeReturns
+	fake.recordInvocation("Authorize", []interface{}{arg1, arg2, arg3, arg4})
+	fake.authorizeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PermissionChecker) AuthorizeCallCount() int {
+	fake.authorizeMutex.RLock()
+	defer fake.authorizeMutex.RUnlock()
+	return len(fake.authorizeArgsForCall)
+}
+
+func (fake *PermissionChecker) AuthorizeCalls(stub func(context.Context, authorization.Info, authorization.Action, string) error) {
+	fake.authorizeMutex.Lock()
+	defer fake.authorizeMutex.Unlock()
+	fake.AuthorizeStub = stub
+}
+
+func (fake *PermissionChecker) AuthorizeArgsForCall(i int) (context.Context, authorization.Info, authorization.Action, string) {
+	fake.authorizeMutex.RLock()
+	defer fake.authorizeMutex.RUnlock()
+	argsForCall := fake.authorizeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *PermissionChecker) AuthorizeReturns(result1 error) {
+	fake.authorizeMutex.Lock()
+	defer fake.authorizeMutex.Unlock()
+	fake.AuthorizeStub = nil
+	fake.authorizeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PermissionChecker) AuthorizeReturnsOnCall(i int, result1 error) {
+	fake.authorizeMutex.Lock()
+	defer fake.authorizeMutex.Unlock()
+	fake.AuthorizeStub = nil
+	if fake.authorizeReturnsOnCall == nil {
+		fake.authorizeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.authorizeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PermissionChecker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.authorizeMutex.RLock()
+	defer fake.authorizeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PermissionChecker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.PermissionChecker = new(PermissionChecker)
@@ -0,0 +1,120 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/oauth"
+)
+
+type TokenGranter struct {
+	GrantStub        func(context.Context, oauth.GrantRequest) (oauth.Token, error)
+	grantMutex       sync.RWMutex
+	grantArgsForCall []struct {
+		arg1 context.Context
+		arg2 oauth.GrantRequest
+	}
+	grantReturns struct {
+		result1 oauth.Token
+		result2 error
+	}
+	grantReturnsOnCall map[int]struct {
+		result1 oauth.Token
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *TokenGranter) Grant(arg1 context.Context, arg2 oauth.GrantRequest) (oauth.Token, error) {
+	fake.grantMutex.Lock()
+	ret, specificReturn := fake.grantReturnsOnCall[len(fake.grantArgsForCall)]
+	fake.grantArgsForCall = append(fake.grantArgsForCall, struct {
+		arg1 context.Context
+		arg2 oauth.GrantRequest
+	}{arg1, arg2})
+	stub := fake.GrantStub
+	fakeReturns := fake.grantReturns
+	fake.recordInvocation("Grant", []interface{}{arg1, arg2})
+	fake.grantMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TokenGranter) GrantCallCount() int {
+	fake.grantMutex.RLock()
+	defer fake.grantMutex.RUnlock()
+	return len(fake.grantArgsForCall)
+}
+
+func (fake *TokenGranter) GrantCalls(stub func(context.Context, oauth.GrantRequest) (oauth.Token, error)) {
+	fake.grantMutex.Lock()
+	defer fake.grantMutex.Unlock()
+	fake.GrantStub = stub
+}
+
+func (fake *TokenGranter) GrantArgsForCall(i int) (context.Context, oauth.GrantRequest) {
+	fake.grantMutex.RLock()
+	defer fake.grantMutex.RUnlock()
+	argsForCall := fake.grantArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *TokenGranter) GrantReturns(result1 oauth.Token, result2 error) {
+	fake.grantMutex.Lock()
+	defer fake.grantMutex.Unlock()
+	fake.GrantStub = nil
+	fake.grantReturns = struct {
+		result1 oauth.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TokenGranter) GrantReturnsOnCall(i int, result1 oauth.Token, result2 error) {
+	fake.grantMutex.Lock()
+	defer fake.grantMutex.Unlock()
+	fake.GrantStub = nil
+	if fake.grantReturnsOnCall == nil {
+		fake.grantReturnsOnCall = make(map[int]struct {
+			result1 oauth.Token
+			result2 error
+		})
+	}
+	fake.grantReturnsOnCall[i] = struct {
+		result1 oauth.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TokenGranter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.grantMutex.RLock()
+	defer fake.grantMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *TokenGranter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.TokenGranter = new(TokenGranter)
@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+type TokenKeySet struct {
+	PublicKeysStub        func(context.Context) (jose.JSONWebKeySet, error)
+	publicKeysMutex       sync.RWMutex
+	publicKeysArgsForCall []struct {
+		arg1 context.Context
+	}
+	publicKeysReturns struct {
+		result1 jose.JSONWebKeySet
+		result2 error
+	}
+	publicKeysReturnsOnCall map[int]struct {
+		result1 jose.JSONWebKeySet
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *TokenKeySet) PublicKeys(arg1 context.Context) (jose.JSONWebKeySet, error) {
+	fake.publicKeysMutex.Lock()
+	ret, specificReturn := fake.publicKeysReturnsOnCall[len(fake.publicKeysArgsForCall)]
+	fake.publicKeysArgsForCall = append(fake.publicKeysArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.PublicKeysStub
+	fakeReturns := fake.publicKeysReturns
+	fake.recordInvocation("PublicKeys", []interface{}{arg1})
+	fake.publicKeysMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *TokenKeySet) PublicKeysCallCount() int {
+	fake.publicKeysMutex.RLock()
+	defer fake.publicKeysMutex.RUnlock()
+	return len(fake.publicKeysArgsForCall)
+}
+
+func (fake *TokenKeySet) PublicKeysCalls(stub func(context.Context) (jose.JSONWebKeySet, error)) {
+	fake.publicKeysMutex.Lock()
+	defer fake.publicKeysMutex.Unlock()
+	fake.PublicKeysStub = stub
+}
+
+func (fake *TokenKeySet) PublicKeysArgsForCall(i int) context.Context {
+	fake.publicKeysMutex.RLock()
+	defer fake.publicKeysMutex.RUnlock()
+	argsForCall := fake.publicKeysArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *TokenKeySet) PublicKeysReturns(result1 jose.JSONWebKeySet, result2 error) {
+	fake.publicKeysMutex.Lock()
+	defer fake.publicKeysMutex.Unlock()
+	fake.PublicKeysStub = nil
+	fake.publicKeysReturns = struct {
+		result1 jose.JSONWebKeySet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TokenKeySet) PublicKeysReturnsOnCall(i int, result1 jose.JSONWebKeySet, result2 error) {
+	fake.publicKeysMutex.Lock()
+	defer fake.publicKeysMutex.Unlock()
+	fake.PublicKeysStub = nil
+	if fake.publicKeysReturnsOnCall == nil {
+		fake.publicKeysReturnsOnCall = make(map[int]struct {
+			result1 jose.JSONWebKeySet
+			result2 error
+		})
+	}
+	fake.publicKeysReturnsOnCall[i] = struct {
+		result1 jose.JSONWebKeySet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *TokenKeySet) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.publicKeysMutex.RLock()
+	defer fake.publicKeysMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *TokenKeySet) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.TokenKeySet = new(TokenKeySet)
@@ -0,0 +1,114 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+)
+
+type UserRecorder struct {
+	RecordUserStub        func(context.Context, string) error
+	recordUserMutex       sync.RWMutex
+	recordUserArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	recordUserReturns struct {
+		result1 error
+	}
+	recordUserReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UserRecorder) RecordUser(arg1 context.Context, arg2 string) error {
+	fake.recordUserMutex.Lock()
+	ret, specificReturn := fake.recordUserReturnsOnCall[len(fake.recordUserArgsForCall)]
+	fake.recordUserArgsForCall = append(fake.recordUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RecordUserStub
+	fakeReturns := fake.recordUserReturns
+	fake.recordInvocation("RecordUser", []interface{}{arg1, arg2})
+	fake.recordUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *UserRecorder) RecordUserCallCount() int {
+	fake.recordUserMutex.RLock()
+	defer fake.recordUserMutex.RUnlock()
+	return len(fake.recordUserArgsForCall)
+}
+
+func (fake *UserRecorder) RecordUserCalls(stub func(context.Context, string) error) {
+	fake.recordUserMutex.Lock()
+	defer fake.recordUserMutex.Unlock()
+	fake.RecordUserStub = stub
+}
+
+func (fake *UserRecorder) RecordUserArgsForCall(i int) (context.Context, string) {
+	fake.recordUserMutex.RLock()
+	defer fake.recordUserMutex.RUnlock()
+	argsForCall := fake.recordUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *UserRecorder) RecordUserReturns(result1 error) {
+	fake.recordUserMutex.Lock()
+	defer fake.recordUserMutex.Unlock()
+	fake.RecordUserStub = nil
+	fake.recordUserReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UserRecorder) RecordUserReturnsOnCall(i int, result1 error) {
+	fake.recordUserMutex.Lock()
+	defer fake.recordUserMutex.Unlock()
+	fake.RecordUserStub = nil
+	if fake.recordUserReturnsOnCall == nil {
+		fake.recordUserReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordUserReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UserRecorder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordUserMutex.RLock()
+	defer fake.recordUserMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *UserRecorder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.UserRecorder = new(UserRecorder)
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gorilla/mux"
+)
+
+type HTTPMetrics struct{}
+
+func NewHTTPMetrics() HTTPMetrics {
+	return HTTPMetrics{}
+}
+
+// Middleware records the count and latency of requests, labelled by the template of the matched route so that
+// resource guids do not end up in metric labels
+func (m HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		// httpsnoop keeps the optional interfaces of the writer, e.g. http.Flusher for log streams
+		captured := httpsnoop.CaptureMetrics(next, w, r)
+		metrics.ObserveHTTPRequest(route, r.Method, captured.Code, captured.Duration)
+	})
+}
@@ -0,0 +1,59 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTPMetricsMiddleware", func() {
+	var (
+		metricsRouter *mux.Router
+		isFlusher     bool
+	)
+
+	scrape := func() string {
+		metricsRecorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(metricsRecorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return metricsRecorder.Body.String()
+	}
+
+	BeforeEach(func() {
+		metricsRouter = mux.NewRouter()
+		metricsRouter.Path("/http-metrics-test/{guid}").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, isFlusher = w.(http.Flusher)
+			w.WriteHeader(http.StatusTeapot)
+		})
+		metricsRouter.Use(handlers.NewHTTPMetrics().Middleware)
+	})
+
+	JustBeforeEach(func() {
+		metricsRouter.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/http-metrics-test/some-guid", nil))
+	})
+
+	It("delegates to the next handler", func() {
+		Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+	})
+
+	It("keeps the writer flushable", func() {
+		Expect(isFlusher).To(BeTrue())
+	})
+
+	It("counts the request by route template, method and status code", func() {
+		Expect(scrape()).To(ContainSubstring(`korifi_api_http_requests_total{code="418",method="GET",route="/http-metrics-test/{guid}"}`))
+	})
+
+	It("records the request latency by route template", func() {
+		Expect(scrape()).To(ContainSubstring(`korifi_api_http_request_duration_seconds_count{method="GET",route="/http-metrics-test/{guid}"}`))
+	})
+
+	It("does not use the request path as a label", func() {
+		Expect(scrape()).NotTo(ContainSubstring("some-guid"))
+	})
+})
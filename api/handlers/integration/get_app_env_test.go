@@ -43,6 +43,7 @@ var _ = Describe("GET /v3/apps/:guid/env", func() {
 			domainRepo,
 			spaceRepo,
 			processScaler,
+			repositories.NewEnvVarGroupRepo(rootNamespace, clientFactory),
 			decoderValidator,
 		)
 		apiHandler.RegisterRoutes(router)
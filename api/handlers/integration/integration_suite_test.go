@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -48,6 +49,8 @@ func TestIntegration(t *testing.T) {
 var (
 	testEnv               *envtest.Environment
 	k8sClient             client.WithWatch
+	permissionsCache      crcache.Cache
+	stopPermissionsCache  context.CancelFunc
 	k8sConfig             *rest.Config
 	namespaceRetriever    repositories.NamespaceRetriever
 	server                *http.Server
@@ -93,6 +96,17 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	permissionsCache, err = crcache.New(k8sConfig, crcache.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(authorization.IndexRoleBindingSubjects(context.Background(), permissionsCache)).To(Succeed())
+	var cacheCtx context.Context
+	cacheCtx, stopPermissionsCache = context.WithCancel(context.Background())
+	go func() {
+		defer GinkgoRecover()
+		Expect(permissionsCache.Start(cacheCtx)).To(Succeed())
+	}()
+	Expect(permissionsCache.WaitForCacheSync(context.Background())).To(BeTrue())
+
 	dynamicClient, err := dynamic.NewForConfig(k8sConfig)
 	Expect(err).NotTo(HaveOccurred())
 	Expect(dynamicClient).NotTo(BeNil())
@@ -111,6 +125,9 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
+	if stopPermissionsCache != nil {
+		stopPermissionsCache()
+	}
 	Expect(testEnv.Stop()).To(Succeed())
 })
 
@@ -121,9 +138,9 @@ var _ = BeforeEach(func() {
 	Expect(err).NotTo(HaveOccurred())
 	clientFactory = authorization.NewUnprivilegedClientFactory(k8sConfig, mapper, authorization.NewDefaultBackoff())
 	tokenInspector := authorization.NewTokenReviewer(k8sClient)
-	certInspector := authorization.NewCertInspector(k8sConfig)
+	certInspector := authorization.NewCertInspector(k8sConfig, authorization.NewCertificatePolicy(0))
 	identityProvider := authorization.NewCertTokenIdentityProvider(tokenInspector, certInspector)
-	nsPermissions = authorization.NewNamespacePermissions(k8sClient, identityProvider)
+	nsPermissions = authorization.NewNamespacePermissions(k8sClient, permissionsCache, identityProvider)
 
 	userName = generateGUID()
 
@@ -202,6 +219,7 @@ func createOrgWithCleanup(ctx context.Context, name string) *korifiv1alpha1.CFOr
 		},
 	}
 	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	waitForPermissionsCache(ctx, namespace)
 
 	DeferCleanup(func() {
 		_ = k8sClient.Delete(ctx, cfOrg)
@@ -242,6 +260,7 @@ func createSpaceWithCleanup(ctx context.Context, orgGUID, name string) *korifiv1
 		},
 	}
 	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	waitForPermissionsCache(ctx, namespace)
 
 	DeferCleanup(func() {
 		_ = k8sClient.Delete(ctx, cfSpace)
@@ -251,6 +270,13 @@ func createSpaceWithCleanup(ctx context.Context, orgGUID, name string) *korifiv1
 	return cfSpace
 }
 
+// waitForPermissionsCache waits for the namespace permissions to see an object that has just been created
+func waitForPermissionsCache(ctx context.Context, obj client.Object) {
+	EventuallyWithOffset(2, func() error {
+		return permissionsCache.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+	}).Should(Succeed())
+}
+
 func createClusterRole(ctx context.Context, filename string) *rbacv1.ClusterRole {
 	filepath := filepath.Join("..", "..", "..", "helm", "controllers", "templates", "cf_roles", filename+".yaml")
 	content, err := os.ReadFile(filepath)
@@ -282,4 +308,5 @@ func createRoleBinding(ctx context.Context, userName, roleName, namespace string
 		},
 	}
 	Expect(k8sClient.Create(ctx, &roleBinding)).To(Succeed())
+	waitForPermissionsCache(ctx, &roleBinding)
 }
@@ -32,7 +32,7 @@ var _ = Describe("Role", func() {
 		}
 		orgRepo := repositories.NewOrgRepo(rootNamespace, k8sClient, clientFactory, nsPermissions, time.Minute)
 		spaceRepo := repositories.NewSpaceRepo(namespaceRetriever, orgRepo, clientFactory, nsPermissions, time.Minute)
-		roleRepo := repositories.NewRoleRepo(clientFactory, spaceRepo, nsPermissions, rootNamespace, roleMappings)
+		roleRepo := repositories.NewRoleRepo(clientFactory, spaceRepo, repositories.NewUserRepo(rootNamespace, k8sClient, clientFactory), nsPermissions, rootNamespace, roleMappings)
 		decoderValidator, err := handlers.NewDefaultDecoderValidator()
 		Expect(err).NotTo(HaveOccurred())
 
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	IsolationSegmentsPath                 = "/v3/isolation_segments"
+	IsolationSegmentPath                  = "/v3/isolation_segments/{guid}"
+	IsolationSegmentOrganizationsPath     = "/v3/isolation_segments/{guid}/relationships/organizations"
+	IsolationSegmentOrganizationPath      = "/v3/isolation_segments/{guid}/relationships/organizations/{orgGUID}"
+	SpaceIsolationSegmentRelationshipPath = "/v3/spaces/{guid}/relationships/isolation_segment"
+)
+
+//counterfeiter:generate -o fake -fake-name CFIsolationSegmentRepository . CFIsolationSegmentRepository
+type CFIsolationSegmentRepository interface {
+	CreateIsolationSegment(context.Context, authorization.Info, repositories.CreateIsolationSegmentMessage) (repositories.IsolationSegmentRecord, error)
+	GetIsolationSegment(context.Context, authorization.Info, string) (repositories.IsolationSegmentRecord, error)
+	ListIsolationSegments(context.Context, authorization.Info, repositories.ListIsolationSegmentsMessage) ([]repositories.IsolationSegmentRecord, error)
+	DeleteIsolationSegment(context.Context, authorization.Info, string) error
+	EntitleOrganizations(context.Context, authorization.Info, repositories.EntitleIsolationSegmentMessage) (repositories.IsolationSegmentRecord, error)
+	RevokeOrganization(context.Context, authorization.Info, repositories.RevokeIsolationSegmentMessage) error
+	GetSpaceIsolationSegment(context.Context, authorization.Info, string) (repositories.SpaceIsolationSegmentRecord, error)
+	AssignSpaceIsolationSegment(context.Context, authorization.Info, repositories.AssignSpaceIsolationSegmentMessage) (repositories.SpaceIsolationSegmentRecord, error)
+}
+
+type IsolationSegmentHandler struct {
+	handlerWrapper       *AuthAwareHandlerFuncWrapper
+	apiBaseURL           url.URL
+	isolationSegmentRepo CFIsolationSegmentRepository
+	decoderValidator     *DecoderValidator
+}
+
+func NewIsolationSegmentHandler(apiBaseURL url.URL, isolationSegmentRepo CFIsolationSegmentRepository, decoderValidator *DecoderValidator) *IsolationSegmentHandler {
+	return &IsolationSegmentHandler{
+		handlerWrapper:       NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("IsolationSegmentHandler")),
+		apiBaseURL:           apiBaseURL,
+		isolationSegmentRepo: isolationSegmentRepo,
+		decoderValidator:     decoderValidator,
+	}
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentCreateHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	var payload payloads.IsolationSegmentCreate
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-create-isolation-segment")
+	}
+
+	record, err := h.isolationSegmentRepo.CreateIsolationSegment(ctx, authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to create isolation segment", "Isolation Segment Name", payload.Name)
+	}
+
+	return NewHandlerResponse(http.StatusCreated).WithBody(presenter.ForIsolationSegment(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.isolationSegmentRepo.GetIsolationSegment(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch isolation segment", "IsolationSegmentGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForIsolationSegment(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	isolationSegmentListFilter := new(payloads.IsolationSegmentList)
+	if err := payloads.Decode(isolationSegmentListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	records, err := h.isolationSegmentRepo.ListIsolationSegments(ctx, authInfo, isolationSegmentListFilter.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list isolation segments")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForIsolationSegmentList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentDeleteHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	if err := h.isolationSegmentRepo.DeleteIsolationSegment(ctx, authInfo, guid); err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to delete isolation segment", "IsolationSegmentGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusNoContent), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentGetOrganizationsHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.isolationSegmentRepo.GetIsolationSegment(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch isolation segment", "IsolationSegmentGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForIsolationSegmentOrganizations(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentEntitleHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	var payload payloads.ToManyRelationship
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-entitle-isolation-segment")
+	}
+
+	record, err := h.isolationSegmentRepo.EntitleOrganizations(ctx, authInfo, repositories.EntitleIsolationSegmentMessage{
+		GUID:              guid,
+		OrganizationGUIDs: payload.GUIDs(),
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to entitle orgs to isolation segment", "IsolationSegmentGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForIsolationSegmentOrganizations(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) isolationSegmentRevokeHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+	orgGUID := mux.Vars(r)["orgGUID"]
+
+	err := h.isolationSegmentRepo.RevokeOrganization(ctx, authInfo, repositories.RevokeIsolationSegmentMessage{
+		GUID:             guid,
+		OrganizationGUID: orgGUID,
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to revoke org entitlement", "IsolationSegmentGUID", guid, "OrgGUID", orgGUID)
+	}
+
+	return NewHandlerResponse(http.StatusNoContent), nil
+}
+
+func (h *IsolationSegmentHandler) spaceIsolationSegmentGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	spaceGUID := mux.Vars(r)["guid"]
+
+	record, err := h.isolationSegmentRepo.GetSpaceIsolationSegment(ctx, authInfo, spaceGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch space isolation segment", "SpaceGUID", spaceGUID)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForSpaceIsolationSegment(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) spaceIsolationSegmentPatchHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	spaceGUID := mux.Vars(r)["guid"]
+
+	var payload payloads.SpaceIsolationSegmentPatch
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-assign-space-isolation-segment")
+	}
+
+	record, err := h.isolationSegmentRepo.AssignSpaceIsolationSegment(ctx, authInfo, payload.ToMessage(spaceGUID))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to assign space isolation segment", "SpaceGUID", spaceGUID)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForSpaceIsolationSegment(record, h.apiBaseURL)), nil
+}
+
+func (h *IsolationSegmentHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(IsolationSegmentsPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentListHandler))
+	router.Path(IsolationSegmentsPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentCreateHandler))
+	router.Path(IsolationSegmentPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentGetHandler))
+	router.Path(IsolationSegmentPath).Methods("DELETE").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentDeleteHandler))
+	router.Path(IsolationSegmentOrganizationsPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentGetOrganizationsHandler))
+	router.Path(IsolationSegmentOrganizationsPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentEntitleHandler))
+	router.Path(IsolationSegmentOrganizationPath).Methods("DELETE").HandlerFunc(h.handlerWrapper.Wrap(h.isolationSegmentRevokeHandler))
+	router.Path(SpaceIsolationSegmentRelationshipPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.spaceIsolationSegmentGetHandler))
+	router.Path(SpaceIsolationSegmentRelationshipPath).Methods("PATCH").HandlerFunc(h.handlerWrapper.Wrap(h.spaceIsolationSegmentPatchHandler))
+}
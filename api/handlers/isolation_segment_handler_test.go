@@ -0,0 +1,396 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IsolationSegmentHandler", func() {
+	var (
+		isolationSegmentRepo *fake.CFIsolationSegmentRepository
+		requestMethod        string
+		requestPath          string
+		requestBody          string
+		record               repositories.IsolationSegmentRecord
+	)
+
+	BeforeEach(func() {
+		requestBody = ""
+		isolationSegmentRepo = new(fake.CFIsolationSegmentRepository)
+		decoderValidator, err := apis.NewDefaultDecoderValidator()
+		Expect(err).NotTo(HaveOccurred())
+
+		record = repositories.IsolationSegmentRecord{
+			GUID:              "segment-guid",
+			Name:              "my-segment",
+			OrganizationGUIDs: []string{"org-guid"},
+			CreatedAt:         "2021-09-17T15:23:10Z",
+			UpdatedAt:         "2021-09-17T15:23:10Z",
+		}
+
+		apis.NewIsolationSegmentHandler(*serverURL, isolationSegmentRepo, decoderValidator).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "segment-guid",
+			"name": "my-segment",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"metadata": {
+				"labels": {},
+				"annotations": {}
+			},
+			"links": {
+				"self": {
+					"href": "%[1]s/v3/isolation_segments/segment-guid"
+				},
+				"organizations": {
+					"href": "%[1]s/v3/isolation_segments/segment-guid/organizations"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("POST /v3/isolation_segments", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/isolation_segments"
+			requestBody = `{"name": "my-segment"}`
+
+			isolationSegmentRepo.CreateIsolationSegmentReturns(record, nil)
+		})
+
+		It("returns the created isolation segment", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+
+			Expect(isolationSegmentRepo.CreateIsolationSegmentCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := isolationSegmentRepo.CreateIsolationSegmentArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message.Name).To(Equal("my-segment"))
+		})
+
+		When("the name is missing", func() {
+			BeforeEach(func() {
+				requestBody = `{}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Name is a required field")
+			})
+		})
+
+		When("the repository fails", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.CreateIsolationSegmentReturns(repositories.IsolationSegmentRecord{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/isolation_segments", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/isolation_segments?names=my-segment&organization_guids=org-guid"
+			isolationSegmentRepo.ListIsolationSegmentsReturns([]repositories.IsolationSegmentRecord{record}, nil)
+		})
+
+		It("lists the isolation segments", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {"href": "%[1]s/v3/isolation_segments?names=my-segment&organization_guids=org-guid"},
+					"last": {"href": "%[1]s/v3/isolation_segments?names=my-segment&organization_guids=org-guid"},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))))
+		})
+
+		It("filters using the query parameters", func() {
+			Expect(isolationSegmentRepo.ListIsolationSegmentsCallCount()).To(Equal(1))
+			_, _, message := isolationSegmentRepo.ListIsolationSegmentsArgsForCall(0)
+			Expect(message.Names).To(ConsistOf("my-segment"))
+			Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+		})
+
+		When("paging and ordering parameters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/isolation_segments?order_by=created_at&per_page=50&page=1"
+			})
+
+			It("ignores them", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(isolationSegmentRepo.ListIsolationSegmentsCallCount()).To(Equal(1))
+			})
+		})
+
+		When("an unknown query parameter is given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/isolation_segments?foo=bar"
+			})
+
+			It("returns an unknown key error", func() {
+				expectUnknownKeyError("The query parameter is invalid: Valid parameters are: 'guids, names, organization_guids, order_by, per_page, page'")
+			})
+		})
+	})
+
+	Describe("GET /v3/isolation_segments/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/isolation_segments/segment-guid"
+			isolationSegmentRepo.GetIsolationSegmentReturns(record, nil)
+		})
+
+		It("returns the isolation segment", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+
+			_, _, guid := isolationSegmentRepo.GetIsolationSegmentArgsForCall(0)
+			Expect(guid).To(Equal("segment-guid"))
+		})
+
+		When("the isolation segment is not found", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.GetIsolationSegmentReturns(repositories.IsolationSegmentRecord{}, apierrors.NewNotFoundError(nil, repositories.IsolationSegmentResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Isolation Segment not found")
+			})
+		})
+	})
+
+	Describe("DELETE /v3/isolation_segments/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/isolation_segments/segment-guid"
+		})
+
+		It("deletes the isolation segment", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusNoContent))
+
+			Expect(isolationSegmentRepo.DeleteIsolationSegmentCallCount()).To(Equal(1))
+			_, _, guid := isolationSegmentRepo.DeleteIsolationSegmentArgsForCall(0)
+			Expect(guid).To(Equal("segment-guid"))
+		})
+
+		When("the isolation segment is still entitled to orgs", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.DeleteIsolationSegmentReturns(apierrors.NewUnprocessableEntityError(nil, "Revoke the Organization entitlements for your Isolation Segment."))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Revoke the Organization entitlements for your Isolation Segment.")
+			})
+		})
+	})
+
+	Describe("GET /v3/isolation_segments/{guid}/relationships/organizations", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/isolation_segments/segment-guid/relationships/organizations"
+			isolationSegmentRepo.GetIsolationSegmentReturns(record, nil)
+		})
+
+		It("returns the entitled orgs", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": [{"guid": "org-guid"}],
+				"links": {
+					"self": {"href": "%s/v3/isolation_segments/segment-guid/relationships/organizations"}
+				}
+			}`, defaultServerURL))))
+		})
+	})
+
+	Describe("POST /v3/isolation_segments/{guid}/relationships/organizations", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/isolation_segments/segment-guid/relationships/organizations"
+			requestBody = `{"data": [{"guid": "org-guid"}]}`
+			isolationSegmentRepo.EntitleOrganizationsReturns(record, nil)
+		})
+
+		It("entitles the orgs and returns all entitled orgs", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": [{"guid": "org-guid"}],
+				"links": {
+					"self": {"href": "%s/v3/isolation_segments/segment-guid/relationships/organizations"}
+				}
+			}`, defaultServerURL))))
+
+			Expect(isolationSegmentRepo.EntitleOrganizationsCallCount()).To(Equal(1))
+			_, _, message := isolationSegmentRepo.EntitleOrganizationsArgsForCall(0)
+			Expect(message.GUID).To(Equal("segment-guid"))
+			Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+		})
+
+		When("an org does not exist", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.EntitleOrganizationsReturns(repositories.IsolationSegmentRecord{}, apierrors.NewUnprocessableEntityError(nil, `Organizations with guids ["org-guid"] do not exist, or you do not have access to them.`))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError(`Organizations with guids ["org-guid"] do not exist, or you do not have access to them.`)
+			})
+		})
+	})
+
+	Describe("DELETE /v3/isolation_segments/{guid}/relationships/organizations/{org_guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/isolation_segments/segment-guid/relationships/organizations/org-guid"
+		})
+
+		It("revokes the org entitlement", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusNoContent))
+
+			Expect(isolationSegmentRepo.RevokeOrganizationCallCount()).To(Equal(1))
+			_, _, message := isolationSegmentRepo.RevokeOrganizationArgsForCall(0)
+			Expect(message.GUID).To(Equal("segment-guid"))
+			Expect(message.OrganizationGUID).To(Equal("org-guid"))
+		})
+
+		When("the isolation segment is not found", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.RevokeOrganizationReturns(apierrors.NewForbiddenError(nil, repositories.IsolationSegmentResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Isolation Segment not found")
+			})
+		})
+	})
+
+	Describe("GET /v3/spaces/{guid}/relationships/isolation_segment", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/spaces/space-guid/relationships/isolation_segment"
+			isolationSegmentRepo.GetSpaceIsolationSegmentReturns(repositories.SpaceIsolationSegmentRecord{
+				SpaceGUID:            "space-guid",
+				IsolationSegmentGUID: "segment-guid",
+			}, nil)
+		})
+
+		It("returns the space's isolation segment", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": {"guid": "segment-guid"},
+				"links": {
+					"self": {"href": "%[1]s/v3/spaces/space-guid/relationships/isolation_segment"},
+					"related": {"href": "%[1]s/v3/isolation_segments/segment-guid"}
+				}
+			}`, defaultServerURL))))
+
+			_, _, spaceGUID := isolationSegmentRepo.GetSpaceIsolationSegmentArgsForCall(0)
+			Expect(spaceGUID).To(Equal("space-guid"))
+		})
+
+		When("the space has no isolation segment", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.GetSpaceIsolationSegmentReturns(repositories.SpaceIsolationSegmentRecord{SpaceGUID: "space-guid"}, nil)
+			})
+
+			It("returns null data", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+					"data": null,
+					"links": {
+						"self": {"href": "%s/v3/spaces/space-guid/relationships/isolation_segment"}
+					}
+				}`, defaultServerURL))))
+			})
+		})
+
+		When("the space is not found", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.GetSpaceIsolationSegmentReturns(repositories.SpaceIsolationSegmentRecord{}, apierrors.NewNotFoundError(nil, repositories.SpaceResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Space not found")
+			})
+		})
+	})
+
+	Describe("PATCH /v3/spaces/{guid}/relationships/isolation_segment", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/spaces/space-guid/relationships/isolation_segment"
+			requestBody = `{"data": {"guid": "segment-guid"}}`
+			isolationSegmentRepo.AssignSpaceIsolationSegmentReturns(repositories.SpaceIsolationSegmentRecord{
+				SpaceGUID:            "space-guid",
+				IsolationSegmentGUID: "segment-guid",
+			}, nil)
+		})
+
+		It("assigns the isolation segment to the space", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": {"guid": "segment-guid"},
+				"links": {
+					"self": {"href": "%[1]s/v3/spaces/space-guid/relationships/isolation_segment"},
+					"related": {"href": "%[1]s/v3/isolation_segments/segment-guid"}
+				}
+			}`, defaultServerURL))))
+
+			Expect(isolationSegmentRepo.AssignSpaceIsolationSegmentCallCount()).To(Equal(1))
+			_, _, message := isolationSegmentRepo.AssignSpaceIsolationSegmentArgsForCall(0)
+			Expect(message).To(Equal(repositories.AssignSpaceIsolationSegmentMessage{
+				SpaceGUID:            "space-guid",
+				IsolationSegmentGUID: "segment-guid",
+			}))
+		})
+
+		When("the data is null", func() {
+			BeforeEach(func() {
+				requestBody = `{"data": null}`
+			})
+
+			It("resets the space to the shared isolation segment", func() {
+				_, _, message := isolationSegmentRepo.AssignSpaceIsolationSegmentArgsForCall(0)
+				Expect(message).To(Equal(repositories.AssignSpaceIsolationSegmentMessage{
+					SpaceGUID: "space-guid",
+				}))
+			})
+		})
+
+		When("the isolation segment is not entitled to the space's org", func() {
+			BeforeEach(func() {
+				isolationSegmentRepo.AssignSpaceIsolationSegmentReturns(repositories.SpaceIsolationSegmentRecord{}, apierrors.NewUnprocessableEntityError(nil, "Unable to assign isolation segment with guid 'segment-guid'. Ensure it has been entitled to the organization that this space belongs to."))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Unable to assign isolation segment with guid 'segment-guid'. Ensure it has been entitled to the organization that this space belongs to.")
+			})
+		})
+	})
+})
@@ -17,12 +17,14 @@ import (
 )
 
 const (
-	JobPath           = "/v3/jobs/{guid}"
-	syncSpacePrefix   = "space.apply_manifest"
-	appDeletePrefix   = "app.delete"
-	orgDeletePrefix   = "org.delete"
-	routeDeletePrefix = "route.delete"
-	spaceDeletePrefix = "space.delete"
+	JobPath                = "/v3/jobs/{guid}"
+	syncSpacePrefix        = "space.apply_manifest"
+	appDeletePrefix        = "app.delete"
+	orgDeletePrefix        = "org.delete"
+	orgQuotaDeletePrefix   = "organization_quota.delete"
+	routeDeletePrefix      = "route.delete"
+	spaceDeletePrefix      = "space.delete"
+	spaceQuotaDeletePrefix = "space_quota.delete"
 )
 
 const JobResourceType = "Job"
@@ -58,7 +60,7 @@ func (h *JobHandler) jobGetHandler(ctx context.Context, logger logr.Logger, auth
 	switch jobType {
 	case syncSpacePrefix:
 		jobResponse = presenter.ForManifestApplyJob(jobGUID, resourceGUID, h.serverURL)
-	case appDeletePrefix, orgDeletePrefix, spaceDeletePrefix, routeDeletePrefix:
+	case appDeletePrefix, orgDeletePrefix, spaceDeletePrefix, routeDeletePrefix, orgQuotaDeletePrefix, spaceQuotaDeletePrefix:
 		jobResponse = presenter.ForDeleteJob(jobGUID, jobType, h.serverURL)
 	default:
 		return nil, apierrors.LogAndReturn(
@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
+	"time"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/correlation"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/presenter"
 	"code.cloudfoundry.org/korifi/api/repositories"
@@ -17,19 +22,33 @@ import (
 )
 
 const (
-	LogCacheInfoPath = "/api/v1/info"
-	LogCacheReadPath = "/api/v1/read/{guid}"
-	logCacheVersion  = "2.11.4+cf-k8s"
+	LogCacheInfoPath   = "/api/v1/info"
+	LogCacheReadPath   = "/api/v1/read/{guid}"
+	LogCacheStreamPath = "/api/v1/stream/{guid}"
+	logCacheVersion    = "2.11.4+cf-k8s"
+
+	// logStreamHeartbeatInterval keeps idle streams from being closed by proxies and load balancers
+	logStreamHeartbeatInterval = 30 * time.Second
 )
 
+type connContextKey struct{}
+
+// ConnContext keeps the connection of a request in its context, so that log streams can lift the write timeout of the
+// server from it. It is meant to be the ConnContext of the API server.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
 //counterfeiter:generate -o fake -fake-name AppLogsReader . AppLogsReader
 type AppLogsReader interface {
 	Read(ctx context.Context, logger logr.Logger, authInfo authorization.Info, appGUID string, read payloads.LogRead) ([]repositories.LogRecord, error)
+	Stream(ctx context.Context, logger logr.Logger, authInfo authorization.Info, appGUID string) (<-chan repositories.LogRecord, func(), error)
 }
 
 // LogCacheHandler implements the minimal set of log-cache API endpoints/features necessary
 // to support the "cf push" workfloh.handlerWrapper.
 type LogCacheHandler struct {
+	logger                        logr.Logger
 	authenticatedHandlerWrapper   *AuthAwareHandlerFuncWrapper
 	unauthenticatedHandlerWrapper *AuthAwareHandlerFuncWrapper
 	appRepo                       CFAppRepository
@@ -43,6 +62,7 @@ func NewLogCacheHandler(
 	appLogsReader AppLogsReader,
 ) *LogCacheHandler {
 	return &LogCacheHandler{
+		logger:                        ctrl.Log.WithName("LogCacheHandler"),
 		authenticatedHandlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("LogCacheHandler")),
 		unauthenticatedHandlerWrapper: NewUnauthenticatedHandlerFuncWrapper(ctrl.Log.WithName("LogCacheHandler")),
 		appRepo:                       appRepo,
@@ -90,7 +110,81 @@ func (h *LogCacheHandler) logCacheReadHandler(ctx context.Context, logger logr.L
 	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForLogs(logs)), nil
 }
 
+// logCacheStreamHandler sends the logs of an app as server-sent events until the client disconnects. It writes
+// to the response directly, as a HandlerResponse can only carry a single body.
+func (h *LogCacheHandler) logCacheStreamHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := correlation.AddCorrelationIDToLogger(ctx, h.logger)
+
+	authInfo, ok := authorization.InfoFromContext(ctx)
+	if !ok {
+		logger.Error(nil, "unable to get auth info")
+		presentError(logger, w, nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		logger.Error(nil, "response writer does not support streaming")
+		presentError(logger, w, nil)
+		return
+	}
+
+	appGUID := mux.Vars(r)["guid"]
+	records, cancel, err := h.appLogsReader.Stream(ctx, logger, authInfo, appGUID)
+	if err != nil {
+		logger.Info("failed to stream app logs", "appGUID", appGUID, "error", err)
+		presentError(logger, w, err)
+		return
+	}
+	defer cancel()
+
+	// streams outlive the write timeout of the server, which would otherwise close them; the heartbeats below notice
+	// clients that are gone instead
+	if conn, ok := ctx.Value(connContextKey{}).(net.Conn); ok {
+		if err := conn.SetWriteDeadline(time.Time{}); err != nil {
+			logger.Info("failed to clear the write deadline of the log stream", "error", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(logStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case record, ok := <-records:
+			if !ok {
+				return
+			}
+
+			event, err := json.Marshal(presenter.ForLogEnvelopes([]repositories.LogRecord{record}))
+			if err != nil {
+				logger.Info("failed to encode log envelope", "error", err)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
 func (h *LogCacheHandler) RegisterRoutes(router *mux.Router) {
 	router.Path(LogCacheInfoPath).Methods("GET").HandlerFunc(h.unauthenticatedHandlerWrapper.Wrap(h.logCacheInfoHandler))
 	router.Path(LogCacheReadPath).Methods("GET").HandlerFunc(h.authenticatedHandlerWrapper.Wrap(h.logCacheReadHandler))
+	router.Path(LogCacheStreamPath).Methods("GET").HandlerFunc(h.logCacheStreamHandler)
 }
@@ -1,17 +1,23 @@
 package handlers_test
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
 	. "code.cloudfoundry.org/korifi/api/handlers"
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
 	"code.cloudfoundry.org/korifi/api/repositories"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -160,6 +166,42 @@ var _ = Describe("LogCacheHandler", func() {
 			Expect(rr.Body).To(MatchJSON(expectedBody))
 		})
 
+		When("the action returns gauge envelopes", func() {
+			BeforeEach(func() {
+				appLogsReader.ReadReturns([]repositories.LogRecord{{
+					Timestamp:  42,
+					InstanceID: "1",
+					Tags:       map[string]string{"process_type": "web"},
+					Gauge: map[string]repositories.GaugeMetric{
+						"cpu":    {Unit: "percentage", Value: 12.5},
+						"memory": {Unit: "bytes", Value: 1024},
+					},
+				}}, nil)
+			})
+
+			It("presents them as gauges", func() {
+				Expect(rr.Body).To(MatchJSON(`{
+					"envelopes": {
+						"batch": [
+							{
+								"timestamp": 42,
+								"instance_id": "1",
+								"gauge": {
+									"metrics": {
+										"cpu": {"unit": "percentage", "value": 12.5},
+										"memory": {"unit": "bytes", "value": 1024}
+									}
+								},
+								"tags": {
+									"process_type": "web"
+								}
+							}
+						]
+					}
+				}`))
+			})
+		})
+
 		When("query parameters are specified", func() {
 			BeforeEach(func() {
 				var err error
@@ -227,4 +269,119 @@ var _ = Describe("LogCacheHandler", func() {
 			})
 		})
 	})
+	Describe("the GET /api/v1/stream/<app-guid> endpoint", func() {
+		const testAppGUID = "streamed-app-guid"
+
+		var (
+			records      chan repositories.LogRecord
+			cancelCalled bool
+		)
+
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequestWithContext(ctx, "GET", "/api/v1/stream/"+testAppGUID, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			records = make(chan repositories.LogRecord, 2)
+			records <- repositories.LogRecord{
+				Message:    "AppMessage1",
+				Timestamp:  1,
+				InstanceID: "1",
+				Tags:       map[string]string{"source_type": "APP"},
+			}
+			records <- repositories.LogRecord{
+				Message:    "AppMessage2",
+				Timestamp:  2,
+				InstanceID: "0",
+				Tags:       map[string]string{"source_type": "APP"},
+			}
+			// closing the channel ends the stream, so that the request returns
+			close(records)
+
+			cancelCalled = false
+			appLogsReader.StreamReturns(records, func() { cancelCalled = true }, nil)
+		})
+
+		It("streams the logs of the app", func() {
+			Expect(appLogsReader.StreamCallCount()).To(Equal(1))
+			_, _, _, appGUID := appLogsReader.StreamArgsForCall(0)
+			Expect(appGUID).To(Equal(testAppGUID))
+		})
+
+		It("returns server-sent events", func() {
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+		})
+
+		It("sends every log record as a log envelope batch", func() {
+			Expect(rr.Body.String()).To(Equal(fmt.Sprintf(
+				"data: %s\n\ndata: %s\n\n",
+				`{"batch":[{"timestamp":1,"instance_id":"1","log":{"payload":"`+base64.StdEncoding.EncodeToString([]byte("AppMessage1"))+`","type":0},"tags":{"source_type":"APP"}}]}`,
+				`{"batch":[{"timestamp":2,"instance_id":"0","log":{"payload":"`+base64.StdEncoding.EncodeToString([]byte("AppMessage2"))+`","type":0},"tags":{"source_type":"APP"}}]}`,
+			)))
+		})
+
+		It("ends the subscription", func() {
+			Expect(cancelCalled).To(BeTrue())
+		})
+
+		When("the stream lasts longer than the write timeout of the server", func() {
+			const writeTimeout = 100 * time.Millisecond
+
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				appLogsReader.StreamStub = func(context.Context, logr.Logger, authorization.Info, string) (<-chan repositories.LogRecord, func(), error) {
+					delayed := make(chan repositories.LogRecord)
+					go func() {
+						defer close(delayed)
+						time.Sleep(3 * writeTimeout)
+						delayed <- repositories.LogRecord{Message: "LateMessage", Tags: map[string]string{"source_type": "APP"}}
+					}()
+
+					return delayed, func() {}, nil
+				}
+
+				server = httptest.NewUnstartedServer(router)
+				server.Config.WriteTimeout = writeTimeout
+				server.Config.BaseContext = func(net.Listener) context.Context { return ctx }
+				server.Config.ConnContext = ConnContext
+				server.Start()
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("keeps sending log records", func() {
+				resp, err := http.Get(server.URL + "/api/v1/stream/" + testAppGUID)
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+
+				body, err := io.ReadAll(resp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("LateMessage"))))
+			})
+		})
+
+		When("the action returns a not-found error", func() {
+			BeforeEach(func() {
+				appLogsReader.StreamReturns(nil, nil, apierrors.NewNotFoundError(nil, repositories.AppResourceType))
+			})
+
+			It("returns the error", func() {
+				expectNotFoundError("App not found")
+			})
+		})
+
+		When("the action returns a random error", func() {
+			BeforeEach(func() {
+				appLogsReader.StreamReturns(nil, nil, errors.New("i-am-made-up"))
+			})
+
+			It("returns an Unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
 })
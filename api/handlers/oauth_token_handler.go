@@ -2,52 +2,121 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
-	"time"
 
+	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/oauth"
+	"code.cloudfoundry.org/korifi/api/presenter"
 
 	"github.com/go-logr/logr"
-	"github.com/golang-jwt/jwt"
 	"github.com/gorilla/mux"
+	"gopkg.in/square/go-jose.v2"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 const (
 	OAuthTokenPath = "/oauth/token"
+	TokenKeysPath  = "/token_keys"
 )
 
+//counterfeiter:generate -o fake -fake-name TokenGranter . TokenGranter
+
+type TokenGranter interface {
+	Grant(ctx context.Context, request oauth.GrantRequest) (oauth.Token, error)
+}
+
+//counterfeiter:generate -o fake -fake-name TokenKeySet . TokenKeySet
+
+type TokenKeySet interface {
+	PublicKeys(ctx context.Context) (jose.JSONWebKeySet, error)
+}
+
 type OAuthTokenHandler struct {
 	handlerWrapper *AuthAwareHandlerFuncWrapper
 	apiBaseURL     url.URL
+	tokenGranter   TokenGranter
+	tokenKeySet    TokenKeySet
 }
 
-func NewOAuthToken(apiBaseURL url.URL) *OAuthTokenHandler {
+func NewOAuthToken(apiBaseURL url.URL, tokenGranter TokenGranter, tokenKeySet TokenKeySet) *OAuthTokenHandler {
 	return &OAuthTokenHandler{
 		handlerWrapper: NewUnauthenticatedHandlerFuncWrapper(ctrl.Log.WithName("OAuthTokenHandler")),
 		apiBaseURL:     apiBaseURL,
+		tokenGranter:   tokenGranter,
+		tokenKeySet:    tokenKeySet,
 	}
 }
 
 func (h *OAuthTokenHandler) oauthTokenHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"exp": time.Now().Add(time.Hour).Unix(),
+	if err := r.ParseForm(); err != nil {
+		return oauthErrorResponse(logger, oauth.NewInvalidRequestError("malformed request body")), nil
+	}
+
+	clientID, clientSecret, err := clientCredentials(r)
+	if err != nil {
+		return oauthErrorResponse(logger, oauth.NewInvalidRequestError("malformed client credentials")), nil
+	}
+
+	token, err := h.tokenGranter.Grant(ctx, oauth.GrantRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Username:     r.PostForm.Get("username"),
+		Password:     r.PostForm.Get("password"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
 	})
+	if err != nil {
+		var oauthErr oauth.Error
+		if errors.As(err, &oauthErr) {
+			return oauthErrorResponse(logger, oauthErr), nil
+		}
+		return nil, apierrors.LogAndReturn(logger, err, "failed to grant token")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForOAuthToken(token)), nil
+}
 
-	tokenString, err := token.SignedString([]byte("not-a-real-secret"))
+func (h *OAuthTokenHandler) tokenKeysHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	keySet, err := h.tokenKeySet.PublicKeys(ctx)
 	if err != nil {
-		// we don't expect to hit this ever, given that the string above is hard coded.
-		panic(err.Error())
+		return nil, apierrors.LogAndReturn(logger, err, "failed to get token keys")
 	}
 
-	return NewHandlerResponse(http.StatusOK).WithBody(map[string]string{
-		"token_type":   "bearer",
-		"access_token": tokenString,
-	}), nil
+	return NewHandlerResponse(http.StatusOK).WithBody(keySet), nil
 }
 
 func (h *OAuthTokenHandler) RegisterRoutes(router *mux.Router) {
 	router.Path(OAuthTokenPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.oauthTokenHandler))
+	router.Path(TokenKeysPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.tokenKeysHandler))
+}
+
+// clientCredentials reads the client credentials from the basic auth header or, failing that, from the form
+func clientCredentials(r *http.Request) (string, string, error) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), nil
+	}
+
+	// credentials in the basic auth header are form encoded, see RFC 6749, section 2.3.1
+	clientID, err := url.QueryUnescape(clientID)
+	if err != nil {
+		return "", "", err
+	}
+
+	clientSecret, err = url.QueryUnescape(clientSecret)
+	if err != nil {
+		return "", "", err
+	}
+
+	return clientID, clientSecret, nil
+}
+
+func oauthErrorResponse(logger logr.Logger, err oauth.Error) *HandlerResponse {
+	logger.Info("token request rejected", "reason", err.Error())
+
+	return NewHandlerResponse(err.HTTPStatus()).WithBody(presenter.ForOAuthError(err))
 }
@@ -1,58 +1,222 @@
 package handlers_test
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
+	"net/url"
+	"strings"
 
-	"code.cloudfoundry.org/korifi/api/authorization"
 	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/oauth"
 
-	"github.com/SermoDigital/jose/jws"
-	"github.com/go-http-utils/headers"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2"
 )
 
 var _ = Describe("OAuthToken", func() {
-	const oauthTokenBase = "/oauth/token"
-
 	var (
-		OAuthTokenHandler *apis.OAuthTokenHandler
-		requestMethod     string
-		requestPath       string
+		tokenGranter *fake.TokenGranter
+		tokenKeySet  *fake.TokenKeySet
 	)
 
 	BeforeEach(func() {
-		requestPath = oauthTokenBase
-		requestMethod = http.MethodPost
-		ctx = authorization.NewContext(ctx, &authorization.Info{Token: "the-token"})
-		OAuthTokenHandler = apis.NewOAuthToken(*serverURL)
-		OAuthTokenHandler.RegisterRoutes(router)
+		tokenGranter = new(fake.TokenGranter)
+		tokenKeySet = new(fake.TokenKeySet)
+
+		apis.NewOAuthToken(*serverURL, tokenGranter, tokenKeySet).RegisterRoutes(router)
 	})
 
-	JustBeforeEach(func() {
-		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, nil)
-		req.Header.Add(headers.Authorization, authHeader)
-		Expect(err).NotTo(HaveOccurred())
+	Describe("POST /oauth/token", func() {
+		var (
+			form            url.Values
+			basicAuthID     string
+			basicAuthSecret string
+		)
 
-		router.ServeHTTP(rr, req)
-	})
+		BeforeEach(func() {
+			form = url.Values{
+				"grant_type": {"password"},
+				"username":   {"my-user"},
+				"password":   {"my-password"},
+			}
+			basicAuthID = "cf"
+			basicAuthSecret = ""
+
+			tokenGranter.GrantReturns(oauth.Token{
+				AccessToken:  "the-access-token",
+				RefreshToken: "the-refresh-token",
+				ExpiresIn:    3600,
+				Scope:        []string{"openid", "cloud_controller.read"},
+				JTI:          "the-jti",
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if basicAuthID != "" {
+				req.SetBasicAuth(basicAuthID, basicAuthSecret)
+			}
+
+			router.ServeHTTP(rr, req)
+		})
+
+		It("grants a token for the request parameters", func() {
+			Expect(tokenGranter.GrantCallCount()).To(Equal(1))
+			_, actualRequest := tokenGranter.GrantArgsForCall(0)
+			Expect(actualRequest).To(Equal(oauth.GrantRequest{
+				GrantType: "password",
+				ClientID:  "cf",
+				Username:  "my-user",
+				Password:  "my-password",
+			}))
+		})
 
-	Describe("POST OAuthToken", func() {
-		It("returns 201 with appropriate success JSON", func() {
+		It("returns the token", func() {
 			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
 			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
-			jsonBody := map[string]string{}
-			Expect(json.NewDecoder(rr.Body).Decode(&jsonBody)).To(Succeed())
-			Expect(jsonBody).To(HaveKeyWithValue("token_type", "bearer"))
-			Expect(jsonBody).To(HaveKeyWithValue("access_token", Not(BeEmpty())))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+				"access_token": "the-access-token",
+				"token_type": "bearer",
+				"refresh_token": "the-refresh-token",
+				"expires_in": 3600,
+				"scope": "openid cloud_controller.read",
+				"jti": "the-jti"
+			}`)))
+		})
+
+		When("the client credentials are form encoded in the basic auth header", func() {
+			BeforeEach(func() {
+				form = url.Values{"grant_type": {"client_credentials"}}
+				basicAuthID = "my-ns%3Amy-sa"
+				basicAuthSecret = "s%26cret"
+			})
+
+			It("decodes them", func() {
+				Expect(tokenGranter.GrantCallCount()).To(Equal(1))
+				_, actualRequest := tokenGranter.GrantArgsForCall(0)
+				Expect(actualRequest.GrantType).To(Equal("client_credentials"))
+				Expect(actualRequest.ClientID).To(Equal("my-ns:my-sa"))
+				Expect(actualRequest.ClientSecret).To(Equal("s&cret"))
+			})
+		})
+
+		When("the client credentials are in the form", func() {
+			BeforeEach(func() {
+				basicAuthID = ""
+				form = url.Values{
+					"grant_type":    {"client_credentials"},
+					"client_id":     {"my-client"},
+					"client_secret": {"my-secret"},
+				}
+			})
+
+			It("reads them from the form", func() {
+				Expect(tokenGranter.GrantCallCount()).To(Equal(1))
+				_, actualRequest := tokenGranter.GrantArgsForCall(0)
+				Expect(actualRequest.ClientID).To(Equal("my-client"))
+				Expect(actualRequest.ClientSecret).To(Equal("my-secret"))
+			})
+		})
+
+		When("the basic auth header is not form encoded", func() {
+			BeforeEach(func() {
+				basicAuthID = "%zz"
+			})
+
+			It("returns an invalid request error", func() {
+				Expect(tokenGranter.GrantCallCount()).To(BeZero())
+				Expect(rr).To(HaveHTTPStatus(http.StatusBadRequest))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+					"error": "invalid_request",
+					"error_description": "malformed client credentials"
+				}`)))
+			})
+		})
+
+		When("the credentials are rejected", func() {
+			BeforeEach(func() {
+				tokenGranter.GrantReturns(oauth.Token{}, oauth.NewUnauthorizedError(errors.New("nope")))
+			})
+
+			It("returns an unauthorized error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnauthorized))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+					"error": "unauthorized",
+					"error_description": "Bad credentials"
+				}`)))
+			})
+		})
+
+		When("the grant type is not supported", func() {
+			BeforeEach(func() {
+				tokenGranter.GrantReturns(oauth.Token{}, oauth.NewUnsupportedGrantTypeError("implicit"))
+			})
+
+			It("returns a bad request error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusBadRequest))
+				Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+					"error": "unsupported_grant_type",
+					"error_description": "Unsupported grant type: implicit"
+				}`)))
+			})
+		})
+
+		When("granting the token fails", func() {
+			BeforeEach(func() {
+				tokenGranter.GrantReturns(oauth.Token{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /token_keys", func() {
+		BeforeEach(func() {
+			tokenKeySet.PublicKeysReturns(jose.JSONWebKeySet{
+				Keys: []jose.JSONWebKey{{
+					Key:       []byte("not-really-a-key"),
+					KeyID:     "key-1",
+					Algorithm: "HS256",
+					Use:       "sig",
+				}},
+			}, nil)
+		})
 
-			tokenout, err := jws.ParseJWT([]byte(jsonBody["access_token"]))
+		JustBeforeEach(func() {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/token_keys", nil)
 			Expect(err).NotTo(HaveOccurred())
-			expiration, ok := tokenout.Claims().Expiration()
-			Expect(ok).To(BeTrue())
-			Expect(expiration.Unix()).To(BeNumerically(">", time.Now().Add(time.Minute*59).Unix()))
+
+			router.ServeHTTP(rr, req)
+		})
+
+		It("returns the key set", func() {
+			Expect(tokenKeySet.PublicKeysCallCount()).To(Equal(1))
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(`{
+				"keys": [{
+					"kty": "oct",
+					"kid": "key-1",
+					"alg": "HS256",
+					"use": "sig",
+					"k": "bm90LXJlYWxseS1hLWtleQ"
+				}]
+			}`)))
+		})
+
+		When("getting the keys fails", func() {
+			BeforeEach(func() {
+				tokenKeySet.PublicKeysReturns(jose.JSONWebKeySet{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
 		})
 	})
 })
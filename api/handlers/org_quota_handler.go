@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	OrgQuotasPath             = "/v3/organization_quotas"
+	OrgQuotaPath              = "/v3/organization_quotas/{guid}"
+	OrgQuotaOrganizationsPath = "/v3/organization_quotas/{guid}/relationships/organizations"
+)
+
+//counterfeiter:generate -o fake -fake-name CFOrgQuotaRepository . CFOrgQuotaRepository
+type CFOrgQuotaRepository interface {
+	CreateOrgQuota(context.Context, authorization.Info, repositories.CreateOrgQuotaMessage) (repositories.OrgQuotaRecord, error)
+	GetOrgQuota(context.Context, authorization.Info, string) (repositories.OrgQuotaRecord, error)
+	ListOrgQuotas(context.Context, authorization.Info, repositories.ListOrgQuotasMessage) ([]repositories.OrgQuotaRecord, error)
+	ApplyOrgQuota(context.Context, authorization.Info, repositories.ApplyOrgQuotaMessage) (repositories.OrgQuotaRecord, error)
+	DeleteOrgQuota(context.Context, authorization.Info, string) error
+}
+
+type OrgQuotaHandler struct {
+	handlerWrapper   *AuthAwareHandlerFuncWrapper
+	apiBaseURL       url.URL
+	orgQuotaRepo     CFOrgQuotaRepository
+	decoderValidator *DecoderValidator
+}
+
+func NewOrgQuotaHandler(apiBaseURL url.URL, orgQuotaRepo CFOrgQuotaRepository, decoderValidator *DecoderValidator) *OrgQuotaHandler {
+	return &OrgQuotaHandler{
+		handlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("OrgQuotaHandler")),
+		apiBaseURL:       apiBaseURL,
+		orgQuotaRepo:     orgQuotaRepo,
+		decoderValidator: decoderValidator,
+	}
+}
+
+func (h *OrgQuotaHandler) orgQuotaCreateHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	var payload payloads.OrgQuotaCreate
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-create-org-quota")
+	}
+
+	record, err := h.orgQuotaRepo.CreateOrgQuota(ctx, authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to create org quota", "Org Quota Name", payload.Name)
+	}
+
+	return NewHandlerResponse(http.StatusCreated).WithBody(presenter.ForOrgQuota(record, h.apiBaseURL)), nil
+}
+
+func (h *OrgQuotaHandler) orgQuotaGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.orgQuotaRepo.GetOrgQuota(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch org quota", "OrgQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForOrgQuota(record, h.apiBaseURL)), nil
+}
+
+func (h *OrgQuotaHandler) orgQuotaListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	orgQuotaListFilter := new(payloads.OrgQuotaList)
+	if err := payloads.Decode(orgQuotaListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	records, err := h.orgQuotaRepo.ListOrgQuotas(ctx, authInfo, orgQuotaListFilter.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list org quotas")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForOrgQuotaList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *OrgQuotaHandler) orgQuotaApplyHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	var payload payloads.ToManyRelationship
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-apply-org-quota")
+	}
+
+	record, err := h.orgQuotaRepo.ApplyOrgQuota(ctx, authInfo, repositories.ApplyOrgQuotaMessage{
+		GUID:              guid,
+		OrganizationGUIDs: payload.GUIDs(),
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to apply org quota", "OrgQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForOrgQuotaOrganizations(record, h.apiBaseURL)), nil
+}
+
+func (h *OrgQuotaHandler) orgQuotaDeleteHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	if err := h.orgQuotaRepo.DeleteOrgQuota(ctx, authInfo, guid); err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to delete org quota", "OrgQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusAccepted).WithHeader("Location", presenter.JobURLForRedirects(guid, presenter.OrgQuotaDeleteOperation, h.apiBaseURL)), nil
+}
+
+func (h *OrgQuotaHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(OrgQuotasPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.orgQuotaListHandler))
+	router.Path(OrgQuotasPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.orgQuotaCreateHandler))
+	router.Path(OrgQuotaPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.orgQuotaGetHandler))
+	router.Path(OrgQuotaPath).Methods("DELETE").HandlerFunc(h.handlerWrapper.Wrap(h.orgQuotaDeleteHandler))
+	router.Path(OrgQuotaOrganizationsPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.orgQuotaApplyHandler))
+}
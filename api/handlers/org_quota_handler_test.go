@@ -0,0 +1,310 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OrgQuotaHandler", func() {
+	var (
+		orgQuotaRepo  *fake.CFOrgQuotaRepository
+		requestMethod string
+		requestPath   string
+		requestBody   string
+		record        repositories.OrgQuotaRecord
+	)
+
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	BeforeEach(func() {
+		requestBody = ""
+		orgQuotaRepo = new(fake.CFOrgQuotaRepository)
+		decoderValidator, err := apis.NewDefaultDecoderValidator()
+		Expect(err).NotTo(HaveOccurred())
+
+		record = repositories.OrgQuotaRecord{
+			GUID: "quota-guid",
+			Name: "my-quota",
+			Apps: repositories.QuotaApps{
+				TotalMemoryInMB: int64Ptr(2048),
+				TotalInstances:  int64Ptr(10),
+			},
+			Services: repositories.QuotaServices{
+				PaidServicesAllowed: true,
+			},
+			Routes: repositories.QuotaRoutes{
+				TotalRoutes: int64Ptr(5),
+			},
+			OrganizationGUIDs: []string{"org-guid"},
+			CreatedAt:         "2021-09-17T15:23:10Z",
+			UpdatedAt:         "2021-09-17T15:23:10Z",
+		}
+
+		apis.NewOrgQuotaHandler(*serverURL, orgQuotaRepo, decoderValidator).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "quota-guid",
+			"name": "my-quota",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"apps": {
+				"total_memory_in_mb": 2048,
+				"per_process_memory_in_mb": null,
+				"total_instances": 10,
+				"per_app_tasks": null,
+				"log_rate_limit_in_bytes_per_second": null
+			},
+			"services": {
+				"paid_services_allowed": true,
+				"total_service_instances": null,
+				"total_service_keys": null
+			},
+			"routes": {
+				"total_routes": 5,
+				"total_reserved_ports": null
+			},
+			"domains": {
+				"total_domains": null
+			},
+			"relationships": {
+				"organizations": {
+					"data": [{"guid": "org-guid"}]
+				}
+			},
+			"links": {
+				"self": {
+					"href": "%s/v3/organization_quotas/quota-guid"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("POST /v3/organization_quotas", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/organization_quotas"
+			requestBody = `{
+				"name": "my-quota",
+				"apps": {
+					"total_memory_in_mb": 2048,
+					"total_instances": 10
+				},
+				"routes": {
+					"total_routes": 5
+				},
+				"relationships": {
+					"organizations": {
+						"data": [{"guid": "org-guid"}]
+					}
+				}
+			}`
+
+			orgQuotaRepo.CreateOrgQuotaReturns(record, nil)
+		})
+
+		It("returns the created quota", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+		})
+
+		It("passes the limits and orgs to the repository", func() {
+			Expect(orgQuotaRepo.CreateOrgQuotaCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := orgQuotaRepo.CreateOrgQuotaArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message.Name).To(Equal("my-quota"))
+			Expect(message.Apps.TotalMemoryInMB).To(Equal(int64Ptr(2048)))
+			Expect(message.Apps.TotalInstances).To(Equal(int64Ptr(10)))
+			Expect(message.Apps.PerProcessMemoryInMB).To(BeNil())
+			Expect(message.Routes.TotalRoutes).To(Equal(int64Ptr(5)))
+			Expect(message.Services.PaidServicesAllowed).To(BeTrue())
+			Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+		})
+
+		When("the name is missing", func() {
+			BeforeEach(func() {
+				requestBody = `{"apps": {"total_memory_in_mb": 2048}}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Name is a required field")
+			})
+		})
+
+		When("a limit is negative", func() {
+			BeforeEach(func() {
+				requestBody = `{"name": "my-quota", "apps": {"total_instances": -1}}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnprocessableEntity))
+			})
+		})
+
+		When("the repository fails", func() {
+			BeforeEach(func() {
+				orgQuotaRepo.CreateOrgQuotaReturns(repositories.OrgQuotaRecord{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/organization_quotas", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/organization_quotas?names=my-quota&organization_guids=org-guid"
+			orgQuotaRepo.ListOrgQuotasReturns([]repositories.OrgQuotaRecord{record}, nil)
+		})
+
+		It("lists the quotas", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {"href": "%[1]s/v3/organization_quotas?names=my-quota&organization_guids=org-guid"},
+					"last": {"href": "%[1]s/v3/organization_quotas?names=my-quota&organization_guids=org-guid"},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))))
+		})
+
+		It("filters using the query parameters", func() {
+			Expect(orgQuotaRepo.ListOrgQuotasCallCount()).To(Equal(1))
+			_, _, message := orgQuotaRepo.ListOrgQuotasArgsForCall(0)
+			Expect(message.Names).To(ConsistOf("my-quota"))
+			Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+		})
+
+		When("paging and ordering parameters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/organization_quotas?order_by=created_at&per_page=50&page=1"
+			})
+
+			It("ignores them", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(orgQuotaRepo.ListOrgQuotasCallCount()).To(Equal(1))
+			})
+		})
+
+		When("an unknown query parameter is given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/organization_quotas?foo=bar"
+			})
+
+			It("returns an unknown key error", func() {
+				expectUnknownKeyError("The query parameter is invalid: Valid parameters are: 'guids, names, organization_guids, order_by, per_page, page'")
+			})
+		})
+	})
+
+	Describe("GET /v3/organization_quotas/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/organization_quotas/quota-guid"
+			orgQuotaRepo.GetOrgQuotaReturns(record, nil)
+		})
+
+		It("returns the quota", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+
+			_, _, guid := orgQuotaRepo.GetOrgQuotaArgsForCall(0)
+			Expect(guid).To(Equal("quota-guid"))
+		})
+
+		When("the quota is not found", func() {
+			BeforeEach(func() {
+				orgQuotaRepo.GetOrgQuotaReturns(repositories.OrgQuotaRecord{}, apierrors.NewNotFoundError(nil, repositories.OrgQuotaResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Organization Quota not found")
+			})
+		})
+	})
+
+	Describe("POST /v3/organization_quotas/{guid}/relationships/organizations", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/organization_quotas/quota-guid/relationships/organizations"
+			requestBody = `{"data": [{"guid": "org-guid"}]}`
+			orgQuotaRepo.ApplyOrgQuotaReturns(record, nil)
+		})
+
+		It("applies the quota and returns the orgs it applies to", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": [{"guid": "org-guid"}],
+				"links": {
+					"self": {"href": "%s/v3/organization_quotas/quota-guid/relationships/organizations"}
+				}
+			}`, defaultServerURL))))
+
+			Expect(orgQuotaRepo.ApplyOrgQuotaCallCount()).To(Equal(1))
+			_, _, message := orgQuotaRepo.ApplyOrgQuotaArgsForCall(0)
+			Expect(message.GUID).To(Equal("quota-guid"))
+			Expect(message.OrganizationGUIDs).To(ConsistOf("org-guid"))
+		})
+
+		When("an org does not exist", func() {
+			BeforeEach(func() {
+				orgQuotaRepo.ApplyOrgQuotaReturns(repositories.OrgQuotaRecord{}, apierrors.NewUnprocessableEntityError(nil, `Organizations with guids ["org-guid"] do not exist, or you do not have access to them.`))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError(`Organizations with guids ["org-guid"] do not exist, or you do not have access to them.`)
+			})
+		})
+	})
+
+	Describe("DELETE /v3/organization_quotas/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/organization_quotas/quota-guid"
+		})
+
+		It("deletes the quota and redirects to the job", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusAccepted))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Location", defaultServerURL+"/v3/jobs/organization_quota.delete~quota-guid"))
+
+			Expect(orgQuotaRepo.DeleteOrgQuotaCallCount()).To(Equal(1))
+			_, _, guid := orgQuotaRepo.DeleteOrgQuotaArgsForCall(0)
+			Expect(guid).To(Equal("quota-guid"))
+		})
+
+		When("the user is not allowed to see the quota", func() {
+			BeforeEach(func() {
+				orgQuotaRepo.DeleteOrgQuotaReturns(apierrors.NewForbiddenError(nil, repositories.OrgQuotaResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Organization Quota not found")
+			})
+		})
+	})
+})
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-http-utils/headers"
+	"github.com/gorilla/mux"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//counterfeiter:generate -o fake -fake-name PermissionChecker . PermissionChecker
+type PermissionChecker interface {
+	Authorize(ctx context.Context, info authorization.Info, action authorization.Action, namespace string) error
+}
+
+// guidSource locates the GUID that a request acts on, either in a path variable or in the JSON request body.
+// Spaces and orgs are identified by the GUID of their namespace, so they have no resource type.
+type guidSource struct {
+	pathVar      string
+	bodyPath     []string
+	resourceType string
+}
+
+type routePermission struct {
+	action authorization.Action
+	// root marks actions on platform wide resources, which live in the root namespace
+	root bool
+	// scope lists where to find the GUID the namespace of the request is derived from; the first one present wins
+	scope []guidSource
+}
+
+func pathGUID(resourceType string) guidSource {
+	return guidSource{pathVar: "guid", resourceType: resourceType}
+}
+
+func relationshipGUID(relationship, resourceType string) guidSource {
+	return guidSource{bodyPath: []string{"relationships", relationship, "data", "guid"}, resourceType: resourceType}
+}
+
+func permissionRoute(method, pathTemplate string) string {
+	return method + " " + pathTemplate
+}
+
+var (
+	appScope             = []guidSource{pathGUID(repositories.AppResourceType)}
+	spaceScope           = []guidSource{pathGUID("")}
+	orgScope             = []guidSource{pathGUID("")}
+	routeScope           = []guidSource{pathGUID(repositories.RouteResourceType)}
+	spaceQuotaScope      = []guidSource{pathGUID(repositories.SpaceQuotaResourceType)}
+	newInSpaceScope      = []guidSource{relationshipGUID("space", "")}
+	newInOrgScope        = []guidSource{relationshipGUID("organization", "")}
+	manifestScope        = []guidSource{{pathVar: "spaceGUID"}}
+	serviceInstanceScope = []guidSource{pathGUID(repositories.ServiceInstanceResourceType)}
+	platformPermission   = routePermission{action: authorization.ActionManagePlatform, root: true}
+
+	// routePermissions maps the method and path template of the routes that mutate resources or expose secrets to
+	// the action they perform, see authorization.PermissionMatrix. Other read only routes are left to Kubernetes
+	// RBAC, other mutating routes are denied unless they are listed in unrestrictedRoutes.
+	routePermissions = map[string]routePermission{
+		permissionRoute(http.MethodPost, AppsPath):                           {action: authorization.ActionManageApps, scope: newInSpaceScope},
+		permissionRoute(http.MethodPatch, AppPath):                           {action: authorization.ActionManageApps, scope: appScope},
+		permissionRoute(http.MethodDelete, AppPath):                          {action: authorization.ActionManageApps, scope: appScope},
+		permissionRoute(http.MethodPatch, AppCurrentDropletRelationshipPath): {action: authorization.ActionManageApps, scope: appScope},
+		permissionRoute(http.MethodPatch, AppEnvVarsPath):                    {action: authorization.ActionManageApps, scope: appScope},
+		permissionRoute(http.MethodPost, AppStartPath):                       {action: authorization.ActionChangeAppState, scope: appScope},
+		permissionRoute(http.MethodPost, AppStopPath):                        {action: authorization.ActionChangeAppState, scope: appScope},
+		permissionRoute(http.MethodPost, AppRestartPath):                     {action: authorization.ActionChangeAppState, scope: appScope},
+		permissionRoute(http.MethodPost, AppProcessScalePath):                {action: authorization.ActionScaleProcesses, scope: appScope},
+		permissionRoute(http.MethodGet, AppEnvPath):                          {action: authorization.ActionReadAppEnv, scope: appScope},
+		permissionRoute(http.MethodGet, AppManifestPath):                     {action: authorization.ActionReadAppEnv, scope: appScope},
+
+		permissionRoute(http.MethodPost, BuildsPath): {action: authorization.ActionManageApps, scope: []guidSource{
+			{bodyPath: []string{"package", "guid"}, resourceType: repositories.PackageResourceType},
+		}},
+		permissionRoute(http.MethodPost, PackagesPath):            {action: authorization.ActionManageApps, scope: []guidSource{relationshipGUID("app", repositories.AppResourceType)}},
+		permissionRoute(http.MethodPatch, PackagePath):            {action: authorization.ActionManageApps, scope: []guidSource{pathGUID(repositories.PackageResourceType)}},
+		permissionRoute(http.MethodPost, PackageUploadPath):       {action: authorization.ActionManageApps, scope: []guidSource{pathGUID(repositories.PackageResourceType)}},
+		permissionRoute(http.MethodPatch, ProcessPath):            {action: authorization.ActionManageApps, scope: []guidSource{pathGUID(repositories.ProcessResourceType)}},
+		permissionRoute(http.MethodPost, ProcessScalePath):        {action: authorization.ActionScaleProcesses, scope: []guidSource{pathGUID(repositories.ProcessResourceType)}},
+		permissionRoute(http.MethodPost, TasksPath):               {action: authorization.ActionManageApps, scope: []guidSource{{pathVar: "appGUID", resourceType: repositories.AppResourceType}}},
+		permissionRoute(http.MethodPost, TaskCancelPath):          {action: authorization.ActionManageApps, scope: []guidSource{{pathVar: "taskGUID", resourceType: repositories.TaskResourceType}}},
+		permissionRoute(http.MethodPut, TaskCancelPathDeprecated): {action: authorization.ActionManageApps, scope: []guidSource{{pathVar: "taskGUID", resourceType: repositories.TaskResourceType}}},
+		permissionRoute(http.MethodPost, SpaceManifestApplyPath):  {action: authorization.ActionManageApps, scope: manifestScope},
+		permissionRoute(http.MethodPost, SpaceManifestDiffPath):   {action: authorization.ActionManageApps, scope: manifestScope},
+
+		permissionRoute(http.MethodPost, RoutesPath):             {action: authorization.ActionManageRoutes, scope: newInSpaceScope},
+		permissionRoute(http.MethodPatch, RoutePath):             {action: authorization.ActionManageRoutes, scope: routeScope},
+		permissionRoute(http.MethodDelete, RoutePath):            {action: authorization.ActionManageRoutes, scope: routeScope},
+		permissionRoute(http.MethodPost, RouteDestinationsPath):  {action: authorization.ActionManageRoutes, scope: routeScope},
+		permissionRoute(http.MethodDelete, RouteDestinationPath): {action: authorization.ActionManageRoutes, scope: routeScope},
+
+		permissionRoute(http.MethodPost, ServiceInstancesPath):  {action: authorization.ActionManageServices, scope: newInSpaceScope},
+		permissionRoute(http.MethodPatch, ServiceInstancePath):  {action: authorization.ActionManageServices, scope: serviceInstanceScope},
+		permissionRoute(http.MethodDelete, ServiceInstancePath): {action: authorization.ActionManageServices, scope: serviceInstanceScope},
+		permissionRoute(http.MethodPost, ServiceBindingsPath): {action: authorization.ActionManageServices, scope: []guidSource{
+			relationshipGUID("service_instance", repositories.ServiceInstanceResourceType),
+			relationshipGUID("app", repositories.AppResourceType),
+		}},
+		permissionRoute(http.MethodDelete, ServiceBindingPath): {action: authorization.ActionManageServices, scope: []guidSource{pathGUID(repositories.ServiceBindingResourceType)}},
+
+		permissionRoute(http.MethodPost, SpacesPath):                             {action: authorization.ActionCreateSpace, scope: newInOrgScope},
+		permissionRoute(http.MethodPatch, SpacePath):                             {action: authorization.ActionUpdateSpace, scope: spaceScope},
+		permissionRoute(http.MethodDelete, SpacePath):                            {action: authorization.ActionDeleteSpace, scope: spaceScope},
+		permissionRoute(http.MethodPatch, SpaceIsolationSegmentRelationshipPath): {action: authorization.ActionAssignSpaceIsolationSegment, scope: spaceScope},
+		permissionRoute(http.MethodPost, SpaceQuotasPath):                        {action: authorization.ActionManageSpaceQuotas, scope: newInOrgScope},
+		permissionRoute(http.MethodDelete, SpaceQuotaPath):                       {action: authorization.ActionManageSpaceQuotas, scope: spaceQuotaScope},
+		permissionRoute(http.MethodPost, SpaceQuotaSpacesPath):                   {action: authorization.ActionManageSpaceQuotas, scope: spaceQuotaScope},
+		permissionRoute(http.MethodDelete, SpaceQuotaSpacePath):                  {action: authorization.ActionManageSpaceQuotas, scope: spaceQuotaScope},
+		permissionRoute(http.MethodPost, RolesPath): {action: authorization.ActionManageRoles, scope: []guidSource{
+			relationshipGUID("space", ""),
+			relationshipGUID("organization", ""),
+		}},
+
+		permissionRoute(http.MethodPatch, OrgPath):  {action: authorization.ActionUpdateOrg, scope: orgScope},
+		permissionRoute(http.MethodDelete, OrgPath): {action: authorization.ActionDeleteOrg, scope: orgScope},
+
+		permissionRoute(http.MethodPost, OrgsPath):                           platformPermission,
+		permissionRoute(http.MethodPost, OrgQuotasPath):                      platformPermission,
+		permissionRoute(http.MethodDelete, OrgQuotaPath):                     platformPermission,
+		permissionRoute(http.MethodPost, OrgQuotaOrganizationsPath):          platformPermission,
+		permissionRoute(http.MethodPost, IsolationSegmentsPath):              platformPermission,
+		permissionRoute(http.MethodDelete, IsolationSegmentPath):             platformPermission,
+		permissionRoute(http.MethodPost, IsolationSegmentOrganizationsPath):  platformPermission,
+		permissionRoute(http.MethodDelete, IsolationSegmentOrganizationPath): platformPermission,
+		permissionRoute(http.MethodPatch, EnvVarGroupPath):                   platformPermission,
+		permissionRoute(http.MethodPost, AppUsageEventsReseedPath):           platformPermission,
+		permissionRoute(http.MethodPost, UsersPath):                          platformPermission,
+	}
+
+	// unrestrictedRoutes lists the mutating routes that any caller may use
+	unrestrictedRoutes = map[string]bool{
+		// logging in happens before the caller has any roles
+		permissionRoute(http.MethodPost, OAuthTokenPath): true,
+		// matching resources against the package cache does not change anything
+		permissionRoute(http.MethodPost, ResourceMatchesPath): true,
+	}
+)
+
+// HasRoutePermission tells whether PermissionMiddleware knows how to treat a route, i.e. the route is read only, has
+// an entry in routePermissions or is unrestricted. Mutating routes it does not know are denied.
+func HasRoutePermission(method, pathTemplate string) bool {
+	route := permissionRoute(method, pathTemplate)
+	if _, ok := routePermissions[route]; ok {
+		return true
+	}
+
+	return !isMutating(method) || unrestrictedRoutes[route]
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// PermissionMiddleware checks the routes in routePermissions against the CF roles of the caller before they reach
+// their handler, so that callers get a CF-NotAuthorized error rather than whatever Kubernetes RBAC makes of the
+// request. Mutating routes that are neither listed nor unrestricted are denied. Requests whose namespace cannot be
+// resolved, e.g. because the target does not exist, are passed on for the handler to report the problem.
+type PermissionMiddleware struct {
+	permissionChecker               PermissionChecker
+	namespaceRetriever              ResourceNamespaceRetriever
+	rootNamespace                   string
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry
+}
+
+func NewPermissionMiddleware(
+	permissionChecker PermissionChecker,
+	namespaceRetriever ResourceNamespaceRetriever,
+	rootNamespace string,
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry,
+) *PermissionMiddleware {
+	return &PermissionMiddleware{
+		permissionChecker:               permissionChecker,
+		namespaceRetriever:              namespaceRetriever,
+		rootNamespace:                   rootNamespace,
+		unauthenticatedEndpointRegistry: unauthenticatedEndpointRegistry,
+	}
+}
+
+func (m *PermissionMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.unauthenticatedEndpointRegistry.IsUnauthenticatedEndpoint(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authInfo, ok := authorization.InfoFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := correlation.AddCorrelationIDToLogger(r.Context(), logf.Log.WithName("permission-middleware"))
+
+		pathTemplate, err := mux.CurrentRoute(r).GetPathTemplate()
+		if err != nil {
+			pathTemplate = r.URL.Path
+		}
+
+		permission, ok := routePermissions[permissionRoute(r.Method, pathTemplate)]
+		if !ok {
+			if !HasRoutePermission(r.Method, pathTemplate) {
+				logger.Info("mutating route has no permission", "method", r.Method, "route", pathTemplate)
+				presentError(logger, w, apierrors.NewForbiddenError(
+					fmt.Errorf("%s %s has no permission", r.Method, pathTemplate),
+					"",
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		namespace := m.namespaceFor(r, permission)
+		if namespace == "" {
+			logger.V(1).Info("could not resolve the namespace of the request", "route", pathTemplate)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := m.permissionChecker.Authorize(r.Context(), authInfo, permission.action, namespace); err != nil {
+			logger.Info("request not authorized", "action", permission.action, "namespace", namespace, "reason", err)
+			presentError(logger, w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *PermissionMiddleware) namespaceFor(r *http.Request, permission routePermission) string {
+	if permission.root {
+		return m.rootNamespace
+	}
+
+	var body map[string]interface{}
+	for _, source := range permission.scope {
+		var guid string
+		if source.pathVar != "" {
+			guid = mux.Vars(r)[source.pathVar]
+		} else {
+			if body == nil {
+				body = peekJSONBody(r)
+			}
+			guid = lookupString(body, source.bodyPath)
+		}
+
+		if guid == "" {
+			continue
+		}
+
+		if source.resourceType == "" {
+			return guid
+		}
+
+		namespace, err := m.namespaceRetriever.NamespaceFor(r.Context(), guid, source.resourceType)
+		if err != nil {
+			return ""
+		}
+		return namespace
+	}
+
+	return ""
+}
+
+// peekJSONBody decodes the JSON request body and leaves the body intact for the handler
+func peekJSONBody(r *http.Request) map[string]interface{} {
+	body := map[string]interface{}{}
+	if r.Body == nil || r.Body == http.NoBody || strings.HasPrefix(r.Header.Get(headers.ContentType), "multipart/") {
+		return body
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body = readCloser{Reader: bytes.NewReader(data), Closer: r.Body}
+	if err != nil {
+		return body
+	}
+
+	_ = json.Unmarshal(data, &body)
+	return body
+}
+
+func lookupString(value interface{}, path []string) string {
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,368 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PermissionMiddleware", func() {
+	var (
+		permissionChecker               *fake.PermissionChecker
+		namespaceRetriever              *fake.ResourceNamespaceRetriever
+		unauthenticatedEndpointRegistry *fake.UnauthenticatedEndpointRegistry
+		middlewareRouter                *mux.Router
+		handlerCalled                   bool
+		handlerBody                     string
+		requestMethod                   string
+		requestPath                     string
+		requestBody                     string
+	)
+
+	BeforeEach(func() {
+		permissionChecker = new(fake.PermissionChecker)
+
+		namespaceRetriever = new(fake.ResourceNamespaceRetriever)
+		namespaceRetriever.NamespaceForReturns("space-guid", nil)
+
+		unauthenticatedEndpointRegistry = new(fake.UnauthenticatedEndpointRegistry)
+		unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(false)
+
+		handlerCalled = false
+		handlerBody = ""
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			handlerCalled = true
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			handlerBody = string(body)
+			w.WriteHeader(http.StatusOK)
+		}
+
+		middlewareRouter = mux.NewRouter()
+		middlewareRouter.Path(handlers.AppsPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.AppPath).Methods(http.MethodGet, http.MethodDelete).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.AppEnvPath).Methods(http.MethodGet).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.SpacePath).Methods(http.MethodPatch).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.OrgsPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.RolesPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.BuildsPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.ResourceMatchesPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path("/v3/unlisted").Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Use(
+			handlers.NewPermissionMiddleware(
+				permissionChecker,
+				namespaceRetriever,
+				"cf",
+				unauthenticatedEndpointRegistry,
+			).Middleware,
+		)
+
+		requestMethod = http.MethodDelete
+		requestPath = "/v3/apps/app-guid"
+		requestBody = ""
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequestWithContext(ctx, requestMethod, "http://localhost"+requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		rr = httptest.NewRecorder()
+		middlewareRouter.ServeHTTP(rr, request)
+	})
+
+	expectAuthorized := func(action authorization.Action, namespace string) {
+		ExpectWithOffset(1, permissionChecker.AuthorizeCallCount()).To(Equal(1))
+		_, actualAuthInfo, actualAction, actualNamespace := permissionChecker.AuthorizeArgsForCall(0)
+		ExpectWithOffset(1, actualAuthInfo).To(Equal(authInfo))
+		ExpectWithOffset(1, actualAction).To(Equal(action))
+		ExpectWithOffset(1, actualNamespace).To(Equal(namespace))
+	}
+
+	It("checks the action of the route in the namespace of the target", func() {
+		expectAuthorized(authorization.ActionManageApps, "space-guid")
+
+		Expect(namespaceRetriever.NamespaceForCallCount()).To(Equal(1))
+		_, actualGUID, actualResourceType := namespaceRetriever.NamespaceForArgsForCall(0)
+		Expect(actualGUID).To(Equal("app-guid"))
+		Expect(actualResourceType).To(Equal(repositories.AppResourceType))
+	})
+
+	It("delegates to the next handler", func() {
+		Expect(handlerCalled).To(BeTrue())
+		Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+	})
+
+	When("the action is not allowed", func() {
+		BeforeEach(func() {
+			permissionChecker.AuthorizeReturns(apierrors.NewForbiddenError(errors.New("nope"), ""))
+		})
+
+		It("returns a CF-NotAuthorized error without calling the handler", func() {
+			expectNotAuthorizedError()
+			Expect(handlerCalled).To(BeFalse())
+		})
+	})
+
+	When("checking the permission fails", func() {
+		BeforeEach(func() {
+			permissionChecker.AuthorizeReturns(errors.New("boom"))
+		})
+
+		It("returns an unknown error", func() {
+			expectUnknownError()
+			Expect(handlerCalled).To(BeFalse())
+		})
+	})
+
+	When("the namespace of the target cannot be resolved", func() {
+		BeforeEach(func() {
+			namespaceRetriever.NamespaceForReturns("", apierrors.NewNotFoundError(nil, repositories.AppResourceType))
+		})
+
+		It("leaves it to the handler to report the problem", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+
+	When("the route has no permission", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+		})
+
+		It("does not check it", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+
+	When("a mutating route has no permission", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/unlisted"
+		})
+
+		It("denies it without calling the handler", func() {
+			expectNotAuthorizedError()
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeFalse())
+		})
+	})
+
+	When("a mutating route is unrestricted", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/resource_matches"
+		})
+
+		It("does not check it", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+
+	When("the route exposes app environments", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/apps/app-guid/env"
+		})
+
+		It("checks that the app environment may be read", func() {
+			expectAuthorized(authorization.ActionReadAppEnv, "space-guid")
+		})
+	})
+
+	When("the target is a space", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPatch
+			requestPath = "/v3/spaces/the-space-guid"
+		})
+
+		It("checks the permission in the namespace of the space", func() {
+			expectAuthorized(authorization.ActionUpdateSpace, "the-space-guid")
+			Expect(namespaceRetriever.NamespaceForCallCount()).To(BeZero())
+		})
+	})
+
+	When("the resource is created in the space of its relationship", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/apps"
+			requestBody = `{"name": "my-app", "relationships": {"space": {"data": {"guid": "the-space-guid"}}}}`
+		})
+
+		It("checks the permission in that space", func() {
+			expectAuthorized(authorization.ActionManageApps, "the-space-guid")
+		})
+
+		It("leaves the request body intact for the handler", func() {
+			Expect(handlerBody).To(Equal(requestBody))
+		})
+	})
+
+	When("the request body refers to the scope by another path", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/builds"
+			requestBody = `{"package": {"guid": "package-guid"}}`
+		})
+
+		It("resolves the namespace of the referred resource", func() {
+			expectAuthorized(authorization.ActionManageApps, "space-guid")
+
+			_, actualGUID, actualResourceType := namespaceRetriever.NamespaceForArgsForCall(0)
+			Expect(actualGUID).To(Equal("package-guid"))
+			Expect(actualResourceType).To(Equal(repositories.PackageResourceType))
+		})
+	})
+
+	When("the route has alternative scopes", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/roles"
+			requestBody = `{"type": "organization_manager", "relationships": {"organization": {"data": {"guid": "the-org-guid"}}}}`
+		})
+
+		It("uses the one present in the request", func() {
+			expectAuthorized(authorization.ActionManageRoles, "the-org-guid")
+		})
+	})
+
+	When("the request body does not name the scope", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/apps"
+			requestBody = `{"name": "my-app"}`
+		})
+
+		It("leaves it to the handler to validate the request", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+
+	When("the route acts on platform wide resources", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/organizations"
+			requestBody = `{"name": "my-org"}`
+		})
+
+		It("checks the permission in the root namespace", func() {
+			expectAuthorized(authorization.ActionManagePlatform, "cf")
+		})
+	})
+
+	When("the request has no auth info", func() {
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("does not check permissions", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+
+	When("the endpoint is unauthenticated", func() {
+		BeforeEach(func() {
+			unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(true)
+		})
+
+		It("does not check permissions", func() {
+			Expect(permissionChecker.AuthorizeCallCount()).To(BeZero())
+			Expect(handlerCalled).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("HasRoutePermission", func() {
+	It("knows every route of the API handlers", func() {
+		// keep in line with the handlers registered in main.go
+		constructors := []interface{}{
+			handlers.NewRootV3Handler,
+			handlers.NewRootHandler,
+			handlers.NewResourceMatchesHandler,
+			handlers.NewAppHandler,
+			handlers.NewRouteHandler,
+			handlers.NewServiceRouteBindingHandler,
+			handlers.NewPackageHandler,
+			handlers.NewBuildHandler,
+			handlers.NewDropletHandler,
+			handlers.NewProcessHandler,
+			handlers.NewDomainHandler,
+			handlers.NewJobHandler,
+			handlers.NewLogCacheHandler,
+			handlers.NewOrgHandler,
+			handlers.NewSpaceHandler,
+			handlers.NewOrgQuotaHandler,
+			handlers.NewSpaceQuotaHandler,
+			handlers.NewIsolationSegmentHandler,
+			handlers.NewEnvVarGroupHandler,
+			handlers.NewAuditEventHandler,
+			handlers.NewAppUsageEventHandler,
+			handlers.NewSpaceManifestHandler,
+			handlers.NewAppManifestHandler,
+			handlers.NewRoleHandler,
+			handlers.NewUserHandler,
+			handlers.NewWhoAmI,
+			handlers.NewBuildpackHandler,
+			handlers.NewServiceInstanceHandler,
+			handlers.NewServiceBindingHandler,
+			handlers.NewTaskHandler,
+			handlers.NewOAuthToken,
+		}
+
+		router := mux.NewRouter()
+		for _, constructor := range constructors {
+			// the routes do not depend on the dependencies of the handlers, so zero values do
+			newHandler := reflect.ValueOf(constructor)
+			args := make([]reflect.Value, newHandler.Type().NumIn())
+			for i := range args {
+				args[i] = reflect.Zero(newHandler.Type().In(i))
+			}
+			newHandler.Call(args)[0].Interface().(interface{ RegisterRoutes(*mux.Router) }).RegisterRoutes(router)
+		}
+
+		var unknownRoutes []string
+		Expect(router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			pathTemplate, err := route.GetPathTemplate()
+			if err != nil {
+				return err
+			}
+
+			methods, err := route.GetMethods()
+			if err != nil {
+				return err
+			}
+
+			for _, method := range methods {
+				if !handlers.HasRoutePermission(method, pathTemplate) {
+					unknownRoutes = append(unknownRoutes, method+" "+pathTemplate)
+				}
+			}
+			return nil
+		})).To(Succeed())
+
+		Expect(unknownRoutes).To(BeEmpty())
+	})
+
+	It("rejects unlisted mutating routes", func() {
+		Expect(handlers.HasRoutePermission(http.MethodDelete, handlers.RolesPath+"/{guid}")).To(BeFalse())
+		Expect(handlers.HasRoutePermission(http.MethodGet, handlers.RolesPath)).To(BeTrue())
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	. "code.cloudfoundry.org/korifi/api/handlers"
@@ -633,6 +634,47 @@ var _ = Describe("ProcessHandler", func() {
 			})
 		})
 
+		When("the process instance keeps crashing", func() {
+			BeforeEach(func() {
+				uptime := int64(42)
+				processStatsFetcher.FetchStatsReturns([]repositories.PodStatsRecord{
+					{
+						Type:     "web",
+						Index:    0,
+						State:    "RUNNING",
+						Uptime:   &uptime,
+						Restarts: 3,
+						LastCrash: &repositories.CrashRecord{
+							Reason:    "OOMKilled",
+							ExitCode:  137,
+							CrashedAt: time.Date(2022, 10, 1, 12, 30, 0, 0, time.UTC),
+						},
+					},
+				}, nil)
+			})
+
+			It("reports the uptime and restart details", func() {
+				Expect(rr.Body.String()).To(MatchJSON(`{
+					"resources": [
+						{
+							"type": "web",
+							"index": 0,
+							"state": "RUNNING",
+							"host": null,
+							"instance_ports": [],
+							"uptime": 42,
+							"mem_quota": null,
+							"disk_quota": null,
+							"fds_quota": null,
+							"isolation_segment": null,
+							"details": "Restarted 3 times, last crash: OOMKilled (exit code 137) at 2022-10-01T12:30:00Z",
+							"usage": {}
+						}
+					]
+				}`), "Response body matches response:")
+			})
+		})
+
 		When("the process stats are not authorized", func() {
 			BeforeEach(func() {
 				processStatsFetcher.FetchStatsReturns(nil, apierrors.NewForbiddenError(nil, repositories.ProcessStatsResourceType))
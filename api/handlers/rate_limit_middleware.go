@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/utils/clock"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	readRequests   = "reads"
+	writeRequests  = "writes"
+	uploadRequests = "uploads"
+
+	// rateLimiterTTL is how long the limiters of an identity are kept after its last request. Token buckets that have
+	// been idle for that long are full again at any sensible rate, so forgetting them does not let anyone through
+	// early.
+	rateLimiterTTL = 10 * time.Minute
+)
+
+// uploadRoutes are the routes limited as uploads rather than writes, as they hold a connection for much longer
+var uploadRoutes = map[string]bool{
+	permissionRoute(http.MethodPost, PackageUploadPath): true,
+}
+
+// streamingRoutes are not limited at all. Their requests last for as long as the client follows the stream, so they
+// would hold a concurrency slot of their identity all along.
+var streamingRoutes = map[string]bool{
+	permissionRoute(http.MethodGet, LogCacheStreamPath): true,
+}
+
+// identityLimiter holds the token bucket and the in flight requests of an identity for one class of requests. Either
+// is nil when the class has no such limit.
+type identityLimiter struct {
+	tokens   *rate.Limiter
+	inFlight chan struct{}
+}
+
+// acquire takes a slot for a request in flight, see release
+func (l *identityLimiter) acquire() bool {
+	if l.inFlight == nil {
+		return true
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *identityLimiter) release() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// take takes a token from the bucket, or returns how long it takes for one to be available
+func (l *identityLimiter) take(now time.Time) (time.Duration, bool) {
+	if l.tokens == nil {
+		return 0, true
+	}
+
+	reservation := l.tokens.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+
+	return 0, true
+}
+
+// RateLimitMiddleware applies token bucket limits and concurrency caps to the requests of each identity, separately
+// for reads, writes and uploads, so that a single runaway client cannot saturate the API and the Kubernetes API
+// behind it. Requests over the limits get a CF-RateLimitExceeded error with a Retry-After header.
+type RateLimitMiddleware struct {
+	identityProvider                IdentityProvider
+	limits                          map[string]config.RateLimitConfig
+	exemptUsers                     map[string]bool
+	exemptGroups                    []string
+	clock                           clock.Clock
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry
+
+	mu       sync.Mutex
+	limiters *cache.Expiring
+}
+
+func NewRateLimitMiddleware(
+	identityProvider IdentityProvider,
+	rateLimits config.RateLimitsConfig,
+	clock clock.Clock,
+	unauthenticatedEndpointRegistry UnauthenticatedEndpointRegistry,
+) *RateLimitMiddleware {
+	exemptUsers := map[string]bool{}
+	for _, user := range rateLimits.ExemptUsers {
+		exemptUsers[user] = true
+	}
+
+	return &RateLimitMiddleware{
+		identityProvider: identityProvider,
+		limits: map[string]config.RateLimitConfig{
+			readRequests:   rateLimits.Reads,
+			writeRequests:  rateLimits.Writes,
+			uploadRequests: rateLimits.Uploads,
+		},
+		exemptUsers:                     exemptUsers,
+		exemptGroups:                    rateLimits.ExemptGroups,
+		clock:                           clock,
+		unauthenticatedEndpointRegistry: unauthenticatedEndpointRegistry,
+		limiters:                        cache.NewExpiringWithClock(clock),
+	}
+}
+
+func (m *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.unauthenticatedEndpointRegistry.IsUnauthenticatedEndpoint(r.URL.Path) || streamingRoutes[currentRoute(r)] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		class := requestClass(r)
+		limit := m.limits[class]
+		if limit.RequestsPerSecond == 0 && limit.MaxConcurrentRequests == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authInfo, ok := authorization.InfoFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := m.identityProvider.GetIdentity(r.Context(), authInfo)
+		if err != nil || m.isExempt(identity) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := correlation.AddCorrelationIDToLogger(r.Context(), logf.Log.WithName("rate-limit-middleware"))
+		limiter := m.limiterFor(identity, class, limit)
+
+		if !limiter.acquire() {
+			logger.Info("too many concurrent requests", "identity", identity.Name, "kind", identity.Kind, "class", class)
+			metrics.ObserveRateLimitedRequest(class, metrics.ConcurrencyLimit)
+			rejectRateLimited(logger, w, time.Second, fmt.Errorf("more than %d concurrent %s", limit.MaxConcurrentRequests, class))
+			return
+		}
+		defer limiter.release()
+
+		if retryAfter, ok := limiter.take(m.clock.Now()); !ok {
+			logger.Info("request rate exceeded", "identity", identity.Name, "kind", identity.Kind, "class", class)
+			metrics.ObserveRateLimitedRequest(class, metrics.RequestRateLimit)
+			rejectRateLimited(logger, w, retryAfter, fmt.Errorf("more than %v %s per second", limit.RequestsPerSecond, class))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *RateLimitMiddleware) isExempt(identity authorization.Identity) bool {
+	if identity.Kind == rbacv1.UserKind && m.exemptUsers[identity.Name] {
+		return true
+	}
+
+	for _, group := range m.exemptGroups {
+		if identity.IsMemberOf(group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *RateLimitMiddleware) limiterFor(identity authorization.Identity, class string, limit config.RateLimitConfig) *identityLimiter {
+	key := fmt.Sprintf("%s/%s/%s", class, identity.Kind, identity.Name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cached, ok := m.limiters.Get(key); ok {
+		limiter := cached.(*identityLimiter)
+		m.limiters.Set(key, limiter, rateLimiterTTL)
+		return limiter
+	}
+
+	limiter := &identityLimiter{}
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(limit.RequestsPerSecond))
+		}
+		limiter.tokens = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	if limit.MaxConcurrentRequests > 0 {
+		limiter.inFlight = make(chan struct{}, limit.MaxConcurrentRequests)
+	}
+
+	m.limiters.Set(key, limiter, rateLimiterTTL)
+	return limiter
+}
+
+func requestClass(r *http.Request) string {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return readRequests
+	}
+
+	if uploadRoutes[currentRoute(r)] {
+		return uploadRequests
+	}
+
+	return writeRequests
+}
+
+// currentRoute returns the method and path template of the route matching the request, see permissionRoute
+func currentRoute(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+
+	pathTemplate, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+
+	return permissionRoute(r.Method, pathTemplate)
+}
+
+func rejectRateLimited(logger logr.Logger, w http.ResponseWriter, retryAfter time.Duration, cause error) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	presentError(logger, w, apierrors.NewRateLimitExceededError(cause))
+}
@@ -0,0 +1,253 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/config"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/utils/clock/testing"
+)
+
+var _ = Describe("RateLimitMiddleware", func() {
+	var (
+		identityProvider                *fake.IdentityProvider
+		unauthenticatedEndpointRegistry *fake.UnauthenticatedEndpointRegistry
+		rateLimits                      config.RateLimitsConfig
+		fakeClock                       *testing.FakeClock
+		middlewareRouter                *mux.Router
+		nestedRecorder                  *httptest.ResponseRecorder
+	)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		request, err := http.NewRequestWithContext(ctx, method, "http://localhost"+path, nil)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		middlewareRouter.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	expectRateLimited := func(recorder *httptest.ResponseRecorder, retryAfter string) {
+		ExpectWithOffset(1, recorder).To(HaveHTTPStatus(http.StatusTooManyRequests))
+		ExpectWithOffset(1, recorder).To(HaveHTTPHeaderWithValue("Retry-After", retryAfter))
+		ExpectWithOffset(1, recorder).To(HaveHTTPBody(MatchJSON(`{
+			"errors": [
+				{
+					"title": "CF-RateLimitExceeded",
+					"detail": "Rate Limit Exceeded",
+					"code": 10013
+				}
+			]
+		}`)))
+	}
+
+	BeforeEach(func() {
+		identityProvider = new(fake.IdentityProvider)
+		identityProvider.GetIdentityReturns(authorization.Identity{Name: "ci-pipeline", Kind: rbacv1.UserKind, Groups: []string{"ci"}}, nil)
+
+		unauthenticatedEndpointRegistry = new(fake.UnauthenticatedEndpointRegistry)
+		unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(false)
+
+		rateLimits = config.RateLimitsConfig{
+			Reads: config.RateLimitConfig{RequestsPerSecond: 1, Burst: 2},
+		}
+		fakeClock = testing.NewFakeClock(time.Now())
+		nestedRecorder = nil
+	})
+
+	JustBeforeEach(func() {
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}
+
+		middlewareRouter = mux.NewRouter()
+		middlewareRouter.Path(handlers.AppsPath).Methods(http.MethodGet, http.MethodPost).HandlerFunc(handlerFunc)
+		middlewareRouter.Path(handlers.PackageUploadPath).Methods(http.MethodPost).HandlerFunc(handlerFunc)
+		// log streams are followed while the client makes other requests
+		middlewareRouter.Path(handlers.LogCacheStreamPath).Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nestedRecorder = serve(http.MethodGet, "/v3/apps")
+			w.WriteHeader(http.StatusTeapot)
+		})
+		// requests to the app are still in flight while they make another request
+		middlewareRouter.Path(handlers.AppPath).Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nestedRecorder = serve(http.MethodGet, "/v3/apps")
+			w.WriteHeader(http.StatusTeapot)
+		})
+		middlewareRouter.Use(
+			handlers.NewRateLimitMiddleware(identityProvider, rateLimits, fakeClock, unauthenticatedEndpointRegistry).Middleware,
+		)
+	})
+
+	It("lets requests within the burst through", func() {
+		Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+	})
+
+	When("the burst has been used up", func() {
+		JustBeforeEach(func() {
+			serve(http.MethodGet, "/v3/apps")
+			serve(http.MethodGet, "/v3/apps")
+		})
+
+		It("returns a rate limit exceeded error with the time until the next token", func() {
+			expectRateLimited(serve(http.MethodGet, "/v3/apps"), "1")
+		})
+
+		It("lets requests through again once the bucket refills", func() {
+			fakeClock.Step(time.Second)
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+
+		It("does not limit other classes of requests", func() {
+			Expect(serve(http.MethodPost, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+
+		It("does not limit other identities", func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{Name: "alice", Kind: rbacv1.UserKind}, nil)
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+
+		It("keeps limiting the identity when its groups change", func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{Name: "ci-pipeline", Kind: rbacv1.UserKind, Groups: []string{"ci", "deployers"}}, nil)
+			expectRateLimited(serve(http.MethodGet, "/v3/apps"), "1")
+		})
+	})
+
+	When("the rate is below one request per second", func() {
+		BeforeEach(func() {
+			rateLimits.Reads = config.RateLimitConfig{RequestsPerSecond: 0.1}
+		})
+
+		It("defaults the burst to one request and rounds the retry after up", func() {
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			expectRateLimited(serve(http.MethodGet, "/v3/apps"), "10")
+		})
+	})
+
+	When("uploads are limited", func() {
+		BeforeEach(func() {
+			rateLimits = config.RateLimitsConfig{
+				Writes:  config.RateLimitConfig{RequestsPerSecond: 100},
+				Uploads: config.RateLimitConfig{RequestsPerSecond: 1},
+			}
+		})
+
+		It("limits package uploads separately from other writes", func() {
+			Expect(serve(http.MethodPost, "/v3/packages/package-guid/upload")).To(HaveHTTPStatus(http.StatusTeapot))
+			expectRateLimited(serve(http.MethodPost, "/v3/packages/package-guid/upload"), "1")
+			Expect(serve(http.MethodPost, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
+	When("concurrent requests are capped", func() {
+		BeforeEach(func() {
+			rateLimits.Reads = config.RateLimitConfig{MaxConcurrentRequests: 1}
+		})
+
+		It("rejects requests while the cap is reached", func() {
+			Expect(serve(http.MethodGet, "/v3/apps/app-guid")).To(HaveHTTPStatus(http.StatusTeapot))
+			expectRateLimited(nestedRecorder, "1")
+		})
+
+		It("frees the slot when the request completes", func() {
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+
+		It("does not hold a slot for log streams", func() {
+			Expect(serve(http.MethodGet, "/api/v1/stream/app-guid")).To(HaveHTTPStatus(http.StatusTeapot))
+			Expect(nestedRecorder).To(HaveHTTPStatus(http.StatusTeapot))
+		})
+	})
+
+	When("the identity follows log streams", func() {
+		It("does not limit them", func() {
+			for i := 0; i < 5; i++ {
+				Expect(serve(http.MethodGet, "/api/v1/stream/app-guid")).To(HaveHTTPStatus(http.StatusTeapot))
+			}
+		})
+	})
+
+	When("the user is exempt", func() {
+		BeforeEach(func() {
+			rateLimits.ExemptUsers = []string{"ci-pipeline"}
+		})
+
+		It("does not limit them", func() {
+			for i := 0; i < 5; i++ {
+				Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			}
+		})
+	})
+
+	When("a group of the identity is exempt", func() {
+		BeforeEach(func() {
+			rateLimits.ExemptGroups = []string{"ci"}
+		})
+
+		It("does not limit it", func() {
+			for i := 0; i < 5; i++ {
+				Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			}
+		})
+	})
+
+	When("a service account has the name of an exempt user", func() {
+		BeforeEach(func() {
+			rateLimits.ExemptUsers = []string{"ci-pipeline"}
+			identityProvider.GetIdentityReturns(authorization.Identity{Name: "ci-pipeline", Kind: rbacv1.ServiceAccountKind}, nil)
+		})
+
+		It("limits it", func() {
+			serve(http.MethodGet, "/v3/apps")
+			serve(http.MethodGet, "/v3/apps")
+			expectRateLimited(serve(http.MethodGet, "/v3/apps"), "1")
+		})
+	})
+
+	When("the endpoint does not require authentication", func() {
+		BeforeEach(func() {
+			unauthenticatedEndpointRegistry.IsUnauthenticatedEndpointReturns(true)
+		})
+
+		It("does not limit it", func() {
+			for i := 0; i < 5; i++ {
+				Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			}
+			Expect(identityProvider.GetIdentityCallCount()).To(BeZero())
+		})
+	})
+
+	When("the identity cannot be resolved", func() {
+		BeforeEach(func() {
+			identityProvider.GetIdentityReturns(authorization.Identity{}, errors.New("boom"))
+		})
+
+		It("leaves the request to the handler", func() {
+			for i := 0; i < 5; i++ {
+				Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			}
+		})
+	})
+
+	When("no limits are configured", func() {
+		BeforeEach(func() {
+			rateLimits = config.RateLimitsConfig{}
+		})
+
+		It("does not resolve the identity", func() {
+			Expect(serve(http.MethodGet, "/v3/apps")).To(HaveHTTPStatus(http.StatusTeapot))
+			Expect(identityProvider.GetIdentityCallCount()).To(BeZero())
+		})
+	})
+})
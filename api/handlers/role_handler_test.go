@@ -181,6 +181,28 @@ var _ = Describe("RoleHandler", func() {
 			Expect(roleRecord.Kind).To(Equal(rbacv1.UserKind))
 		})
 
+		When("the role is assigned to a recorded user", func() {
+			BeforeEach(func() {
+				roleRepo.CreateRoleStub = nil
+				roleRepo.CreateRoleReturns(repositories.RoleRecord{
+					GUID:      "t-h-e-r-o-l-e",
+					CreatedAt: now,
+					UpdatedAt: now,
+					Type:      "space_developer",
+					Space:     "my-space",
+					User:      "my-user",
+					UserGUID:  "user-guid",
+					Kind:      rbacv1.UserKind,
+				}, nil)
+			})
+
+			It("relates the role to the user and links to it", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+				Expect(rr.Body.String()).To(ContainSubstring(`"user":{"data":{"guid":"user-guid"}}`))
+				Expect(rr.Body.String()).To(ContainSubstring(fmt.Sprintf(`"user":{"href":"%s/v3/users/user-guid"}`, defaultServerURL)))
+			})
+		})
+
 		When("username is passed in the guid field", func() {
 			BeforeEach(func() {
 				createRoleRequestBody = `{
@@ -32,6 +32,7 @@ type CFServiceInstanceRepository interface {
 	CreateServiceInstance(context.Context, authorization.Info, repositories.CreateServiceInstanceMessage) (repositories.ServiceInstanceRecord, error)
 	ListServiceInstances(context.Context, authorization.Info, repositories.ListServiceInstanceMessage) ([]repositories.ServiceInstanceRecord, error)
 	GetServiceInstance(context.Context, authorization.Info, string) (repositories.ServiceInstanceRecord, error)
+	PatchServiceInstance(context.Context, authorization.Info, repositories.PatchServiceInstanceMessage) (repositories.ServiceInstanceRecord, error)
 	DeleteServiceInstance(context.Context, authorization.Info, repositories.DeleteServiceInstanceMessage) error
 }
 
@@ -84,6 +85,28 @@ func (h *ServiceInstanceHandler) serviceInstanceCreateHandler(ctx context.Contex
 	return NewHandlerResponse(http.StatusCreated).WithBody(presenter.ForServiceInstance(serviceInstanceRecord, h.serverURL)), nil
 }
 
+func (h *ServiceInstanceHandler) serviceInstancePatchHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	vars := mux.Vars(r)
+	serviceInstanceGUID := vars["guid"]
+
+	serviceInstance, err := h.serviceInstanceRepo.GetServiceInstance(ctx, authInfo, serviceInstanceGUID)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "failed to get service instance", "guid", serviceInstanceGUID)
+	}
+
+	var payload payloads.ServiceInstancePatch
+	if err = h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	serviceInstance, err = h.serviceInstanceRepo.PatchServiceInstance(ctx, authInfo, payload.ToServiceInstancePatchMessage(serviceInstance.SpaceGUID, serviceInstanceGUID))
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to patch service instance", "guid", serviceInstanceGUID)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForServiceInstance(serviceInstance, h.serverURL)), nil
+}
+
 func (h *ServiceInstanceHandler) serviceInstanceListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
 	if err := r.ParseForm(); err != nil {
 		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
@@ -132,5 +155,6 @@ func (h *ServiceInstanceHandler) serviceInstanceDeleteHandler(ctx context.Contex
 func (h *ServiceInstanceHandler) RegisterRoutes(router *mux.Router) {
 	router.Path(ServiceInstancesPath).Methods(http.MethodPost).HandlerFunc(h.handlerWrapper.Wrap(h.serviceInstanceCreateHandler))
 	router.Path(ServiceInstancesPath).Methods(http.MethodGet).HandlerFunc(h.handlerWrapper.Wrap(h.serviceInstanceListHandler))
+	router.Path(ServiceInstancePath).Methods(http.MethodPatch).HandlerFunc(h.handlerWrapper.Wrap(h.serviceInstancePatchHandler))
 	router.Path(ServiceInstancePath).Methods(http.MethodDelete).HandlerFunc(h.handlerWrapper.Wrap(h.serviceInstanceDeleteHandler))
 }
@@ -13,11 +13,13 @@ import (
 	"code.cloudfoundry.org/korifi/api/repositories"
 
 	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/tools"
 
 	. "code.cloudfoundry.org/korifi/api/handlers"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 )
 
 var _ = Describe("ServiceInstanceHandler", func() {
@@ -183,11 +185,46 @@ var _ = Describe("ServiceInstanceHandler", func() {
 
 		When("the request body has syslog_drain_url set", func() {
 			BeforeEach(func() {
-				makePostRequest(`{"syslog_drain_url" : "Invalid Request"}`)
+				makePostRequest(`{
+				"name": "` + serviceInstanceName + `",
+				"syslog_drain_url": "syslog-tls://logs.example.com:6514",
+				"relationships": {
+					"space": {
+						"data": {
+							"guid": "` + serviceInstanceSpaceGUID + `"
+						}
+					}
+				},
+				"type": "` + serviceInstanceTypeUserProvided + `"
+			}`)
+			})
+
+			It("creates the service instance with the drain URL", func() {
+				Expect(rr.Code).To(Equal(http.StatusCreated))
+				Expect(serviceInstanceRepo.CreateServiceInstanceCallCount()).To(Equal(1))
+				_, _, actualCreate := serviceInstanceRepo.CreateServiceInstanceArgsForCall(0)
+				Expect(actualCreate.SyslogDrainURL).To(Equal("syslog-tls://logs.example.com:6514"))
+			})
+		})
+
+		When("the request body has a syslog_drain_url with an unsupported scheme", func() {
+			BeforeEach(func() {
+				makePostRequest(`{
+				"name": "` + serviceInstanceName + `",
+				"syslog_drain_url": "ftp://logs.example.com",
+				"relationships": {
+					"space": {
+						"data": {
+							"guid": "` + serviceInstanceSpaceGUID + `"
+						}
+					}
+				},
+				"type": "` + serviceInstanceTypeUserProvided + `"
+			}`)
 			})
 
 			It("returns an error", func() {
-				expectUnprocessableEntityError(`invalid request body: json: unknown field "syslog_drain_url"`)
+				expectUnprocessableEntityError(`"ftp://logs.example.com" is not a valid syslog drain URL: supported schemes are syslog, syslog-tls and https`)
 			})
 		})
 
@@ -646,6 +683,133 @@ var _ = Describe("ServiceInstanceHandler", func() {
 		})
 	})
 
+	Describe("the PATCH /v3/service_instances/:guid endpoint", func() {
+		makePatchRequest := func(body string) {
+			var err error
+			req, err = http.NewRequestWithContext(ctx, http.MethodPatch, "/v3/service_instances/"+serviceInstanceGUID, strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			serviceInstanceRepo.GetServiceInstanceReturns(repositories.ServiceInstanceRecord{
+				GUID:      serviceInstanceGUID,
+				SpaceGUID: serviceInstanceSpaceGUID,
+			}, nil)
+			serviceInstanceRepo.PatchServiceInstanceReturns(repositories.ServiceInstanceRecord{
+				Name:           "new-name",
+				GUID:           serviceInstanceGUID,
+				SpaceGUID:      serviceInstanceSpaceGUID,
+				SecretName:     serviceInstanceGUID,
+				Tags:           []string{"baz"},
+				Type:           serviceInstanceTypeUserProvided,
+				SyslogDrainURL: "https://logs.example.com/drain",
+			}, nil)
+
+			makePatchRequest(`{
+				"name": "new-name",
+				"tags": ["baz"],
+				"credentials": {"user": "admin"},
+				"syslog_drain_url": "https://logs.example.com/drain",
+				"metadata": {
+					"labels": {"foo": "bar"},
+					"annotations": {"bar": null}
+				}
+			}`)
+		})
+
+		It("returns status 200 OK", func() {
+			Expect(rr.Code).To(Equal(http.StatusOK))
+		})
+
+		It("gets the service instance", func() {
+			Expect(serviceInstanceRepo.GetServiceInstanceCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := serviceInstanceRepo.GetServiceInstanceArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal(serviceInstanceGUID))
+		})
+
+		It("patches the service instance", func() {
+			Expect(serviceInstanceRepo.PatchServiceInstanceCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := serviceInstanceRepo.PatchServiceInstanceArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message.GUID).To(Equal(serviceInstanceGUID))
+			Expect(message.SpaceGUID).To(Equal(serviceInstanceSpaceGUID))
+			Expect(message.Name).To(PointTo(Equal("new-name")))
+			Expect(message.Tags).To(PointTo(Equal([]string{"baz"})))
+			Expect(message.Credentials).To(PointTo(Equal(map[string]string{"user": "admin"})))
+			Expect(message.SyslogDrainURL).To(PointTo(Equal("https://logs.example.com/drain")))
+			Expect(message.MetadataPatch.Labels).To(Equal(map[string]*string{"foo": tools.PtrTo("bar")}))
+			Expect(message.MetadataPatch.Annotations).To(Equal(map[string]*string{"bar": nil}))
+		})
+
+		It("returns the patched service instance", func() {
+			Expect(rr.Body.String()).To(SatisfyAll(
+				ContainSubstring(`"name":"new-name"`),
+				ContainSubstring(`"syslog_drain_url":"https://logs.example.com/drain"`),
+			))
+		})
+
+		When("the syslog drain URL is cleared", func() {
+			BeforeEach(func() {
+				makePatchRequest(`{"syslog_drain_url": ""}`)
+			})
+
+			It("patches the service instance with an empty drain URL", func() {
+				Expect(serviceInstanceRepo.PatchServiceInstanceCallCount()).To(Equal(1))
+				_, _, message := serviceInstanceRepo.PatchServiceInstanceArgsForCall(0)
+				Expect(message.SyslogDrainURL).To(PointTo(BeEmpty()))
+				Expect(message.Name).To(BeNil())
+			})
+		})
+
+		When("the syslog drain URL has an unsupported scheme", func() {
+			BeforeEach(func() {
+				makePatchRequest(`{"syslog_drain_url": "tcp://logs.example.com"}`)
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(`"tcp://logs.example.com" is not a valid syslog drain URL: supported schemes are syslog, syslog-tls and https`)
+			})
+
+			It("does not patch the service instance", func() {
+				Expect(serviceInstanceRepo.PatchServiceInstanceCallCount()).To(BeZero())
+			})
+		})
+
+		When("the request body is not valid", func() {
+			BeforeEach(func() {
+				makePatchRequest(`{"type": "managed"}`)
+			})
+
+			It("returns an error", func() {
+				expectUnprocessableEntityError(`invalid request body: json: unknown field "type"`)
+			})
+		})
+
+		When("getting the service instance fails with forbidden", func() {
+			BeforeEach(func() {
+				serviceInstanceRepo.GetServiceInstanceReturns(
+					repositories.ServiceInstanceRecord{},
+					apierrors.NewForbiddenError(nil, repositories.ServiceInstanceResourceType),
+				)
+			})
+
+			It("returns 404 Not Found", func() {
+				expectNotFoundError("Service Instance not found")
+			})
+		})
+
+		When("patching the service instance fails", func() {
+			BeforeEach(func() {
+				serviceInstanceRepo.PatchServiceInstanceReturns(repositories.ServiceInstanceRecord{}, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
 	Describe("the DELETE /v3/service_instances endpoint", func() {
 		BeforeEach(func() {
 			serviceInstanceRepo.GetServiceInstanceReturns(repositories.ServiceInstanceRecord{SpaceGUID: spaceGUID}, nil)
@@ -11,6 +11,7 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
 
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/go-playground/locales/en"
@@ -120,6 +121,20 @@ func wireValidator() (*validator.Validate, ut.Translator, error) {
 		return nil, nil, err
 	}
 
+	err = v.RegisterValidation("syslogdrainurl", syslogDrainURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = v.RegisterTranslation("syslogdrainurl", trans, func(ut ut.Translator) error {
+		return ut.Add("syslogdrainurl", `"{0}" is not a valid syslog drain URL: supported schemes are syslog, syslog-tls and https`, false)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("syslogdrainurl", fmt.Sprintf("%v", fe.Value()))
+		return t
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
 	err = v.RegisterValidation("metadatavalidator", metadataValidator)
 	if err != nil {
 		return nil, nil, err
@@ -294,6 +309,15 @@ func serviceInstanceTagLength(fl validator.FieldLevel) bool {
 	return tagLen < 2048
 }
 
+func syslogDrainURL(fl validator.FieldLevel) bool {
+	drainURL := fl.Field().String()
+	if drainURL == "" {
+		return true // an empty URL removes the drain
+	}
+
+	return syslogdrain.ValidateURL(drainURL) == nil
+}
+
 func metadataValidator(fl validator.FieldLevel) bool {
 	metadata, ok := fl.Field().Interface().(map[string]*string)
 	if !ok {
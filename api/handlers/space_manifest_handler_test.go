@@ -68,6 +68,10 @@ var _ = Describe("SpaceManifestHandler", func() {
                     instances: 1
                     memory: 256M
                     timeout: 10
+                  services:
+                  - my-instance
+                  - name: other-instance
+                    binding_name: my-binding
                 `)
 			})
 
@@ -104,6 +108,12 @@ var _ = Describe("SpaceManifestHandler", func() {
 				Expect(payload.Applications[0].Processes[0].Instances).To(PointTo(Equal(1)))
 				Expect(payload.Applications[0].Processes[0].Memory).To(PointTo(Equal("256M")))
 				Expect(payload.Applications[0].Processes[0].Timeout).To(PointTo(Equal(int64(10))))
+
+				Expect(payload.Applications[0].Services).To(HaveLen(2))
+				Expect(payload.Applications[0].Services[0].Name).To(Equal("my-instance"))
+				Expect(payload.Applications[0].Services[0].BindingName).To(BeNil())
+				Expect(payload.Applications[0].Services[1].Name).To(Equal("other-instance"))
+				Expect(payload.Applications[0].Services[1].BindingName).To(PointTo(Equal("my-binding")))
 			})
 		})
 
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	SpaceQuotasPath      = "/v3/space_quotas"
+	SpaceQuotaPath       = "/v3/space_quotas/{guid}"
+	SpaceQuotaSpacesPath = "/v3/space_quotas/{guid}/relationships/spaces"
+	SpaceQuotaSpacePath  = "/v3/space_quotas/{guid}/relationships/spaces/{spaceGUID}"
+)
+
+//counterfeiter:generate -o fake -fake-name CFSpaceQuotaRepository . CFSpaceQuotaRepository
+type CFSpaceQuotaRepository interface {
+	CreateSpaceQuota(context.Context, authorization.Info, repositories.CreateSpaceQuotaMessage) (repositories.SpaceQuotaRecord, error)
+	GetSpaceQuota(context.Context, authorization.Info, string) (repositories.SpaceQuotaRecord, error)
+	ListSpaceQuotas(context.Context, authorization.Info, repositories.ListSpaceQuotasMessage) ([]repositories.SpaceQuotaRecord, error)
+	ApplySpaceQuota(context.Context, authorization.Info, repositories.ApplySpaceQuotaMessage) (repositories.SpaceQuotaRecord, error)
+	RemoveSpaceQuota(context.Context, authorization.Info, repositories.RemoveSpaceQuotaMessage) error
+	DeleteSpaceQuota(context.Context, authorization.Info, string) error
+}
+
+type SpaceQuotaHandler struct {
+	handlerWrapper   *AuthAwareHandlerFuncWrapper
+	apiBaseURL       url.URL
+	spaceQuotaRepo   CFSpaceQuotaRepository
+	decoderValidator *DecoderValidator
+}
+
+func NewSpaceQuotaHandler(apiBaseURL url.URL, spaceQuotaRepo CFSpaceQuotaRepository, decoderValidator *DecoderValidator) *SpaceQuotaHandler {
+	return &SpaceQuotaHandler{
+		handlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("SpaceQuotaHandler")),
+		apiBaseURL:       apiBaseURL,
+		spaceQuotaRepo:   spaceQuotaRepo,
+		decoderValidator: decoderValidator,
+	}
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaCreateHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	var payload payloads.SpaceQuotaCreate
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-create-space-quota")
+	}
+
+	record, err := h.spaceQuotaRepo.CreateSpaceQuota(ctx, authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(
+			logger,
+			apierrors.AsUnprocessableEntity(err, "Invalid organization. Ensure the organization exists and you have access to it.", apierrors.NotFoundError{}),
+			"Failed to create space quota",
+			"Space Quota Name", payload.Name,
+		)
+	}
+
+	return NewHandlerResponse(http.StatusCreated).WithBody(presenter.ForSpaceQuota(record, h.apiBaseURL)), nil
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.spaceQuotaRepo.GetSpaceQuota(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch space quota", "SpaceQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForSpaceQuota(record, h.apiBaseURL)), nil
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	spaceQuotaListFilter := new(payloads.SpaceQuotaList)
+	if err := payloads.Decode(spaceQuotaListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	records, err := h.spaceQuotaRepo.ListSpaceQuotas(ctx, authInfo, spaceQuotaListFilter.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list space quotas")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForSpaceQuotaList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaApplyHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	var payload payloads.ToManyRelationship
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "invalid-payload-for-apply-space-quota")
+	}
+
+	record, err := h.spaceQuotaRepo.ApplySpaceQuota(ctx, authInfo, repositories.ApplySpaceQuotaMessage{
+		GUID:       guid,
+		SpaceGUIDs: payload.GUIDs(),
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to apply space quota", "SpaceQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForSpaceQuotaSpaces(record, h.apiBaseURL)), nil
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaRemoveHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	vars := mux.Vars(r)
+	guid := vars["guid"]
+	spaceGUID := vars["spaceGUID"]
+
+	err := h.spaceQuotaRepo.RemoveSpaceQuota(ctx, authInfo, repositories.RemoveSpaceQuotaMessage{
+		GUID:      guid,
+		SpaceGUID: spaceGUID,
+	})
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to remove space quota", "SpaceQuotaGUID", guid, "SpaceGUID", spaceGUID)
+	}
+
+	return NewHandlerResponse(http.StatusNoContent), nil
+}
+
+func (h *SpaceQuotaHandler) spaceQuotaDeleteHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	if err := h.spaceQuotaRepo.DeleteSpaceQuota(ctx, authInfo, guid); err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to delete space quota", "SpaceQuotaGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusAccepted).WithHeader("Location", presenter.JobURLForRedirects(guid, presenter.SpaceQuotaDeleteOperation, h.apiBaseURL)), nil
+}
+
+func (h *SpaceQuotaHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(SpaceQuotasPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaListHandler))
+	router.Path(SpaceQuotasPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaCreateHandler))
+	router.Path(SpaceQuotaPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaGetHandler))
+	router.Path(SpaceQuotaPath).Methods("DELETE").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaDeleteHandler))
+	router.Path(SpaceQuotaSpacesPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaApplyHandler))
+	router.Path(SpaceQuotaSpacePath).Methods("DELETE").HandlerFunc(h.handlerWrapper.Wrap(h.spaceQuotaRemoveHandler))
+}
@@ -0,0 +1,295 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpaceQuotaHandler", func() {
+	var (
+		spaceQuotaRepo *fake.CFSpaceQuotaRepository
+		requestMethod  string
+		requestPath    string
+		requestBody    string
+		record         repositories.SpaceQuotaRecord
+	)
+
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	BeforeEach(func() {
+		requestBody = ""
+		spaceQuotaRepo = new(fake.CFSpaceQuotaRepository)
+		decoderValidator, err := apis.NewDefaultDecoderValidator()
+		Expect(err).NotTo(HaveOccurred())
+
+		record = repositories.SpaceQuotaRecord{
+			GUID:             "quota-guid",
+			Name:             "my-quota",
+			OrganizationGUID: "org-guid",
+			Apps: repositories.QuotaApps{
+				PerProcessMemoryInMB: int64Ptr(1024),
+			},
+			Services: repositories.QuotaServices{
+				PaidServicesAllowed:   false,
+				TotalServiceInstances: int64Ptr(3),
+			},
+			SpaceGUIDs: []string{"space-guid"},
+			CreatedAt:  "2021-09-17T15:23:10Z",
+			UpdatedAt:  "2021-09-17T15:23:10Z",
+		}
+
+		apis.NewSpaceQuotaHandler(*serverURL, spaceQuotaRepo, decoderValidator).RegisterRoutes(router)
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "quota-guid",
+			"name": "my-quota",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"apps": {
+				"total_memory_in_mb": null,
+				"per_process_memory_in_mb": 1024,
+				"total_instances": null,
+				"per_app_tasks": null,
+				"log_rate_limit_in_bytes_per_second": null
+			},
+			"services": {
+				"paid_services_allowed": false,
+				"total_service_instances": 3,
+				"total_service_keys": null
+			},
+			"routes": {
+				"total_routes": null,
+				"total_reserved_ports": null
+			},
+			"relationships": {
+				"organization": {
+					"data": {"guid": "org-guid"}
+				},
+				"spaces": {
+					"data": [{"guid": "space-guid"}]
+				}
+			},
+			"links": {
+				"self": {
+					"href": "%[1]s/v3/space_quotas/quota-guid"
+				},
+				"organization": {
+					"href": "%[1]s/v3/organizations/org-guid"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("POST /v3/space_quotas", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/space_quotas"
+			requestBody = `{
+				"name": "my-quota",
+				"apps": {
+					"per_process_memory_in_mb": 1024
+				},
+				"services": {
+					"paid_services_allowed": false,
+					"total_service_instances": 3
+				},
+				"relationships": {
+					"organization": {
+						"data": {"guid": "org-guid"}
+					},
+					"spaces": {
+						"data": [{"guid": "space-guid"}]
+					}
+				}
+			}`
+
+			spaceQuotaRepo.CreateSpaceQuotaReturns(record, nil)
+		})
+
+		It("returns the created quota", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusCreated))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+		})
+
+		It("passes the limits, org and spaces to the repository", func() {
+			Expect(spaceQuotaRepo.CreateSpaceQuotaCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := spaceQuotaRepo.CreateSpaceQuotaArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message.Name).To(Equal("my-quota"))
+			Expect(message.OrganizationGUID).To(Equal("org-guid"))
+			Expect(message.Apps.PerProcessMemoryInMB).To(Equal(int64Ptr(1024)))
+			Expect(message.Services.PaidServicesAllowed).To(BeFalse())
+			Expect(message.Services.TotalServiceInstances).To(Equal(int64Ptr(3)))
+			Expect(message.SpaceGUIDs).To(ConsistOf("space-guid"))
+		})
+
+		When("the organization relationship is missing", func() {
+			BeforeEach(func() {
+				requestBody = `{"name": "my-quota"}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusUnprocessableEntity))
+			})
+		})
+
+		When("the org does not exist", func() {
+			BeforeEach(func() {
+				spaceQuotaRepo.CreateSpaceQuotaReturns(repositories.SpaceQuotaRecord{}, apierrors.NewNotFoundError(nil, repositories.OrgResourceType))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Invalid organization. Ensure the organization exists and you have access to it.")
+			})
+		})
+
+		When("the repository fails", func() {
+			BeforeEach(func() {
+				spaceQuotaRepo.CreateSpaceQuotaReturns(repositories.SpaceQuotaRecord{}, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("GET /v3/space_quotas", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/space_quotas?space_guids=space-guid"
+			spaceQuotaRepo.ListSpaceQuotasReturns([]repositories.SpaceQuotaRecord{record}, nil)
+		})
+
+		It("lists the quotas", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {"href": "%[1]s/v3/space_quotas?space_guids=space-guid"},
+					"last": {"href": "%[1]s/v3/space_quotas?space_guids=space-guid"},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))))
+
+			_, _, message := spaceQuotaRepo.ListSpaceQuotasArgsForCall(0)
+			Expect(message.SpaceGUIDs).To(ConsistOf("space-guid"))
+		})
+
+		When("paging and ordering parameters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/space_quotas?order_by=created_at&per_page=50&page=1"
+			})
+
+			It("ignores them", func() {
+				Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+				Expect(spaceQuotaRepo.ListSpaceQuotasCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("GET /v3/space_quotas/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodGet
+			requestPath = "/v3/space_quotas/quota-guid"
+			spaceQuotaRepo.GetSpaceQuotaReturns(record, nil)
+		})
+
+		It("returns the quota", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(expectedRecordJSON())))
+		})
+
+		When("the user cannot see the quota", func() {
+			BeforeEach(func() {
+				spaceQuotaRepo.GetSpaceQuotaReturns(repositories.SpaceQuotaRecord{}, apierrors.NewForbiddenError(nil, repositories.SpaceQuotaResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("Space Quota not found")
+			})
+		})
+	})
+
+	Describe("POST /v3/space_quotas/{guid}/relationships/spaces", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/space_quotas/quota-guid/relationships/spaces"
+			requestBody = `{"data": [{"guid": "space-guid"}]}`
+			spaceQuotaRepo.ApplySpaceQuotaReturns(record, nil)
+		})
+
+		It("applies the quota and returns the spaces it applies to", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusOK))
+			Expect(rr).To(HaveHTTPBody(MatchJSON(fmt.Sprintf(`{
+				"data": [{"guid": "space-guid"}],
+				"links": {
+					"self": {"href": "%s/v3/space_quotas/quota-guid/relationships/spaces"}
+				}
+			}`, defaultServerURL))))
+
+			_, _, message := spaceQuotaRepo.ApplySpaceQuotaArgsForCall(0)
+			Expect(message.GUID).To(Equal("quota-guid"))
+			Expect(message.SpaceGUIDs).To(ConsistOf("space-guid"))
+		})
+	})
+
+	Describe("DELETE /v3/space_quotas/{guid}/relationships/spaces/{spaceGUID}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/space_quotas/quota-guid/relationships/spaces/space-guid"
+		})
+
+		It("removes the quota from the space", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusNoContent))
+
+			_, _, message := spaceQuotaRepo.RemoveSpaceQuotaArgsForCall(0)
+			Expect(message.GUID).To(Equal("quota-guid"))
+			Expect(message.SpaceGUID).To(Equal("space-guid"))
+		})
+	})
+
+	Describe("DELETE /v3/space_quotas/{guid}", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodDelete
+			requestPath = "/v3/space_quotas/quota-guid"
+		})
+
+		It("deletes the quota and redirects to the job", func() {
+			Expect(rr).To(HaveHTTPStatus(http.StatusAccepted))
+			Expect(rr).To(HaveHTTPHeaderWithValue("Location", defaultServerURL+"/v3/jobs/space_quota.delete~quota-guid"))
+		})
+
+		When("the quota is still applied to spaces", func() {
+			BeforeEach(func() {
+				spaceQuotaRepo.DeleteSpaceQuotaReturns(apierrors.NewUnprocessableEntityError(nil, "This quota is applied to one or more spaces. Remove this quota from all spaces before deleting."))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("This quota is applied to one or more spaces. Remove this quota from all spaces before deleting.")
+			})
+		})
+	})
+})
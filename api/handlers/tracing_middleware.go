@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Tracing struct{}
+
+func NewTracing() Tracing {
+	return Tracing{}
+}
+
+// Middleware starts a server span for each request, continuing the trace of the client when it sends a traceparent
+// header. The span is named after the matched route template and carries the correlation id of the request.
+func (t Tracing) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		attributes := []attribute.KeyValue{
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", route),
+		}
+		if id, ok := correlation.IdFromContext(r.Context()); ok {
+			attributes = append(attributes, attribute.String("korifi.correlation_id", id))
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attributes...),
+		)
+		defer span.End()
+
+		captured := httpsnoop.CaptureMetrics(next, w, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.status_code", captured.Code))
+		if captured.Code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(captured.Code))
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/korifi/api/correlation"
+	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ = Describe("TracingMiddleware", func() {
+	var (
+		exporter      *tracetest.InMemoryExporter
+		tracingRouter *mux.Router
+		req           *http.Request
+		status        int
+		handlerSpan   trace.SpanContext
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(tracing.NewTracerProvider("test", sdktrace.WithSyncer(exporter)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		status = http.StatusTeapot
+		tracingRouter = mux.NewRouter()
+		tracingRouter.Path("/tracing-test/{guid}").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerSpan = trace.SpanContextFromContext(r.Context())
+			w.WriteHeader(status)
+		})
+		tracingRouter.Use(handlers.NewTracing().Middleware)
+
+		req = httptest.NewRequest(http.MethodGet, "/tracing-test/some-guid", nil)
+		req = req.WithContext(correlation.ContextWithId(req.Context(), "my-correlation-id"))
+	})
+
+	JustBeforeEach(func() {
+		tracingRouter.ServeHTTP(rr, req)
+	})
+
+	It("delegates to the next handler", func() {
+		Expect(rr).To(HaveHTTPStatus(http.StatusTeapot))
+	})
+
+	It("records a server span named after the route template", func() {
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("GET /tracing-test/{guid}"))
+		Expect(spans[0].SpanKind).To(Equal(trace.SpanKindServer))
+		Expect(spans[0].Attributes).To(ContainElements(
+			attribute.String("http.method", "GET"),
+			attribute.String("http.route", "/tracing-test/{guid}"),
+			attribute.String("korifi.correlation_id", "my-correlation-id"),
+			attribute.Int("http.status_code", http.StatusTeapot),
+		))
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+	})
+
+	It("passes the span to the handler in the request context", func() {
+		Expect(exporter.GetSpans()).To(HaveLen(1))
+		Expect(handlerSpan).To(Equal(exporter.GetSpans()[0].SpanContext))
+	})
+
+	When("the client sends a traceparent header", func() {
+		BeforeEach(func() {
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		})
+
+		It("continues the client trace", func() {
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].SpanContext.TraceID().String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(spans[0].Parent.SpanID().String()).To(Equal("00f067aa0ba902b7"))
+		})
+	})
+
+	When("the handler fails", func() {
+		BeforeEach(func() {
+			status = http.StatusInternalServerError
+		})
+
+		It("marks the span as failed", func() {
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status.Code).To(Equal(codes.Error))
+		})
+	})
+})
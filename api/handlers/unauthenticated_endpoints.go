@@ -11,6 +11,7 @@ func NewUnauthenticatedEndpoints() *UnauthenticatedEndpoints {
 			"/v3":          struct{}{},
 			"/api/v1/info": struct{}{},
 			"/oauth/token": struct{}{},
+			"/token_keys":  struct{}{},
 		},
 	}
 }
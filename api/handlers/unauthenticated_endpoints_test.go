@@ -15,5 +15,6 @@ var _ = DescribeTable("UnauthenticatedEndpoint",
 	Entry("/v3", "/v3", true),
 	Entry("/api/v1/info", "/api/v1/info", true),
 	Entry("/oauth/token", "/oauth/token", true),
+	Entry("/token_keys", "/token_keys", true),
 	Entry("/v3/apps", "/v3/apps", false),
 )
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/payloads"
+	"code.cloudfoundry.org/korifi/api/presenter"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	UsersPath = "/v3/users"
+	UserPath  = "/v3/users/{guid}"
+)
+
+//counterfeiter:generate -o fake -fake-name CFUserRepository . CFUserRepository
+type CFUserRepository interface {
+	CreateUser(context.Context, authorization.Info, repositories.CreateUserMessage) (repositories.UserRecord, error)
+	GetUser(context.Context, authorization.Info, string) (repositories.UserRecord, error)
+	ListUsers(context.Context, authorization.Info, repositories.ListUsersMessage) ([]repositories.UserRecord, error)
+}
+
+type UserHandler struct {
+	handlerWrapper   *AuthAwareHandlerFuncWrapper
+	apiBaseURL       url.URL
+	userRepo         CFUserRepository
+	decoderValidator *DecoderValidator
+}
+
+func NewUserHandler(apiBaseURL url.URL, userRepo CFUserRepository, decoderValidator *DecoderValidator) *UserHandler {
+	return &UserHandler{
+		handlerWrapper:   NewAuthAwareHandlerFuncWrapper(ctrl.Log.WithName("UserHandler")),
+		apiBaseURL:       apiBaseURL,
+		userRepo:         userRepo,
+		decoderValidator: decoderValidator,
+	}
+}
+
+func (h *UserHandler) userCreateHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	var payload payloads.UserCreate
+	if err := h.decoderValidator.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "failed to decode payload")
+	}
+
+	record, err := h.userRepo.CreateUser(ctx, authInfo, payload.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to create user", "Username", payload.Username)
+	}
+
+	return NewHandlerResponse(http.StatusCreated).WithBody(presenter.ForUser(record, h.apiBaseURL)), nil
+}
+
+func (h *UserHandler) userGetHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	guid := mux.Vars(r)["guid"]
+
+	record, err := h.userRepo.GetUser(ctx, authInfo, guid)
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, apierrors.ForbiddenAsNotFound(err), "Failed to fetch user", "UserGUID", guid)
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForUser(record, h.apiBaseURL)), nil
+}
+
+func (h *UserHandler) userListHandler(ctx context.Context, logger logr.Logger, authInfo authorization.Info, r *http.Request) (*HandlerResponse, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to parse request query parameters")
+	}
+
+	userListFilter := new(payloads.UserList)
+	if err := payloads.Decode(userListFilter, r.Form); err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Unable to decode request query parameters")
+	}
+
+	records, err := h.userRepo.ListUsers(ctx, authInfo, userListFilter.ToMessage())
+	if err != nil {
+		return nil, apierrors.LogAndReturn(logger, err, "Failed to list users")
+	}
+
+	return NewHandlerResponse(http.StatusOK).WithBody(presenter.ForUserList(records, h.apiBaseURL, *r.URL)), nil
+}
+
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	router.Path(UsersPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.userListHandler))
+	router.Path(UsersPath).Methods("POST").HandlerFunc(h.handlerWrapper.Wrap(h.userCreateHandler))
+	router.Path(UserPath).Methods("GET").HandlerFunc(h.handlerWrapper.Wrap(h.userGetHandler))
+}
@@ -0,0 +1,227 @@
+package handlers_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	apis "code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/handlers/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserHandler", func() {
+	var (
+		userRepo      *fake.CFUserRepository
+		requestMethod string
+		requestPath   string
+		requestBody   string
+		record        repositories.UserRecord
+	)
+
+	BeforeEach(func() {
+		userRepo = new(fake.CFUserRepository)
+
+		record = repositories.UserRecord{
+			GUID:        "user-guid",
+			Username:    "bob@example.com",
+			Origin:      "kubernetes",
+			Labels:      map[string]string{"team": "a"},
+			Annotations: map[string]string{},
+			CreatedAt:   time.Date(2021, 9, 17, 15, 23, 10, 0, time.UTC),
+			UpdatedAt:   time.Date(2021, 9, 17, 15, 23, 10, 0, time.UTC),
+		}
+
+		decoderValidator, err := apis.NewDefaultDecoderValidator()
+		Expect(err).NotTo(HaveOccurred())
+
+		apis.NewUserHandler(*serverURL, userRepo, decoderValidator).RegisterRoutes(router)
+
+		requestMethod = http.MethodGet
+		requestBody = ""
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, requestMethod, requestPath, strings.NewReader(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(rr, req)
+	})
+
+	expectedRecordJSON := func() string {
+		return fmt.Sprintf(`{
+			"guid": "user-guid",
+			"created_at": "2021-09-17T15:23:10Z",
+			"updated_at": "2021-09-17T15:23:10Z",
+			"username": "bob@example.com",
+			"presentation_name": "bob@example.com",
+			"origin": "kubernetes",
+			"metadata": {
+				"labels": {"team": "a"},
+				"annotations": {}
+			},
+			"links": {
+				"self": {
+					"href": "%s/v3/users/user-guid"
+				}
+			}
+		}`, defaultServerURL)
+	}
+
+	Describe("GET /v3/users/{guid}", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/users/user-guid"
+			userRepo.GetUserReturns(record, nil)
+		})
+
+		It("returns the user", func() {
+			Expect(userRepo.GetUserCallCount()).To(Equal(1))
+			_, actualAuthInfo, actualGUID := userRepo.GetUserArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(actualGUID).To(Equal("user-guid"))
+
+			expectJSONResponse(http.StatusOK, expectedRecordJSON())
+		})
+
+		When("the user is not found", func() {
+			BeforeEach(func() {
+				userRepo.GetUserReturns(repositories.UserRecord{}, apierrors.NewNotFoundError(nil, repositories.UserResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("User not found")
+			})
+		})
+
+		When("the caller is not authorized to see the user", func() {
+			BeforeEach(func() {
+				userRepo.GetUserReturns(repositories.UserRecord{}, apierrors.NewForbiddenError(nil, repositories.UserResourceType))
+			})
+
+			It("returns a not found error", func() {
+				expectNotFoundError("User not found")
+			})
+		})
+	})
+
+	Describe("GET /v3/users", func() {
+		BeforeEach(func() {
+			requestPath = "/v3/users"
+			userRepo.ListUsersReturns([]repositories.UserRecord{record}, nil)
+		})
+
+		It("returns the users", func() {
+			Expect(userRepo.ListUsersCallCount()).To(Equal(1))
+			_, actualAuthInfo, _ := userRepo.ListUsersArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+
+			expectJSONResponse(http.StatusOK, fmt.Sprintf(`{
+				"pagination": {
+					"total_results": 1,
+					"total_pages": 1,
+					"first": {
+						"href": "%[1]s/v3/users"
+					},
+					"last": {
+						"href": "%[1]s/v3/users"
+					},
+					"next": null,
+					"previous": null
+				},
+				"resources": [%[2]s]
+			}`, defaultServerURL, expectedRecordJSON()))
+		})
+
+		When("filters are given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/users?guids=user-guid&usernames=bob@example.com,alice@example.com&origins=kubernetes"
+			})
+
+			It("passes them to the repository", func() {
+				Expect(userRepo.ListUsersCallCount()).To(Equal(1))
+				_, _, message := userRepo.ListUsersArgsForCall(0)
+				Expect(message.GUIDs).To(ConsistOf("user-guid"))
+				Expect(message.Usernames).To(ConsistOf("bob@example.com", "alice@example.com"))
+				Expect(message.Origins).To(ConsistOf("kubernetes"))
+			})
+		})
+
+		When("an invalid query parameter is given", func() {
+			BeforeEach(func() {
+				requestPath = "/v3/users?foo=bar"
+			})
+
+			It("returns an unknown key error", func() {
+				expectUnknownKeyError("The query parameter is invalid: Valid parameters are: 'guids, usernames, origins, order_by, per_page, page'")
+			})
+		})
+
+		When("listing the users fails", func() {
+			BeforeEach(func() {
+				userRepo.ListUsersReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an unknown error", func() {
+				expectUnknownError()
+			})
+		})
+	})
+
+	Describe("POST /v3/users", func() {
+		BeforeEach(func() {
+			requestMethod = http.MethodPost
+			requestPath = "/v3/users"
+			requestBody = `{"username": "bob@example.com", "origin": "ldap", "metadata": {"labels": {"team": "a"}}}`
+			userRepo.CreateUserReturns(record, nil)
+		})
+
+		It("creates the user", func() {
+			Expect(userRepo.CreateUserCallCount()).To(Equal(1))
+			_, actualAuthInfo, message := userRepo.CreateUserArgsForCall(0)
+			Expect(actualAuthInfo).To(Equal(authInfo))
+			Expect(message).To(Equal(repositories.CreateUserMessage{
+				Username: "bob@example.com",
+				Origin:   "ldap",
+				Labels:   map[string]string{"team": "a"},
+			}))
+
+			expectJSONResponse(http.StatusCreated, expectedRecordJSON())
+		})
+
+		When("the username is missing", func() {
+			BeforeEach(func() {
+				requestBody = `{"origin": "ldap"}`
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("Username is a required field")
+			})
+		})
+
+		When("the user already exists", func() {
+			BeforeEach(func() {
+				userRepo.CreateUserReturns(repositories.UserRecord{}, apierrors.NewUnprocessableEntityError(nil, "User with username 'bob@example.com' already exists"))
+			})
+
+			It("returns an unprocessable entity error", func() {
+				expectUnprocessableEntityError("User with username 'bob@example.com' already exists")
+			})
+		})
+
+		When("the caller is not allowed to create users", func() {
+			BeforeEach(func() {
+				userRepo.CreateUserReturns(repositories.UserRecord{}, apierrors.NewForbiddenError(nil, repositories.UserResourceType))
+			})
+
+			It("returns a not authorized error", func() {
+				expectNotAuthorizedError()
+			})
+		})
+	})
+})
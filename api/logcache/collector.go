@@ -0,0 +1,373 @@
+package logcache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// app instance and task logs are tagged APP/PROC/<PROCESS TYPE> and APP/TASK/<task name> respectively,
+	// matching the source types of Cloud Foundry
+	AppProcessLogSourceTypePrefix = "APP/PROC/"
+	AppTaskLogSourceTypePrefix    = "APP/TASK/"
+	StagingLogSourceType          = "STG"
+
+	defaultProcessType = "web"
+
+	// JobNameLabelKey is set by Kubernetes on the pods of a job. Task jobs are named after their CFTask.
+	JobNameLabelKey = "job-name"
+
+	instanceIndexEnvVar = "CF_INSTANCE_INDEX"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfbuilds;cftasks,verbs=get
+
+//counterfeiter:generate -o fake -fake-name PodLogStreamer . PodLogStreamer
+type PodLogStreamer interface {
+	StreamLogs(ctx context.Context, namespace, podName, containerName string, sinceTime *metav1.Time) (io.ReadCloser, error)
+}
+
+// DefaultFollowBackoff resumes interrupted container log streams with exponentially growing intervals of up to
+// half a minute
+var DefaultFollowBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Steps:    math.MaxInt32,
+	Cap:      30 * time.Second,
+}
+
+// Collector follows the logs of app, task and staging containers and writes them into a Store. Containers
+// are tailed from their first line, once per container restart. Interrupted log streams are resumed from the
+// last stored line for as long as the container runs.
+type Collector struct {
+	k8sClient   client.Reader
+	logStreamer PodLogStreamer
+	store       *Store
+	backoff     wait.Backoff
+	logger      logr.Logger
+
+	mutex sync.Mutex
+	pods  map[types.UID]*podTails
+}
+
+// podTails keeps the containers of a pod that are followed, keyed by name and restart count, along with the
+// restart count and termination of each container as last seen
+type podTails struct {
+	followed   map[string]struct{}
+	containers map[string]corev1.ContainerStatus
+}
+
+func NewCollector(k8sClient client.Reader, logStreamer PodLogStreamer, store *Store, backoff wait.Backoff, logger logr.Logger) *Collector {
+	return &Collector{
+		k8sClient:   k8sClient,
+		logStreamer: logStreamer,
+		store:       store,
+		backoff:     backoff,
+		logger:      logger,
+		pods:        map[types.UID]*podTails{},
+	}
+}
+
+// PodEventHandler returns the handler to register with a pod informer. Log streams are stopped when ctx is done.
+func (c *Collector) PodEventHandler(ctx context.Context) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.CollectPod(ctx, pod)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.CollectPod(ctx, pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.ForgetPod(pod)
+			}
+		},
+	}
+}
+
+// AppEventHandler returns the handler to register with a CFApp informer, so that the logs of deleted apps are
+// dropped from the store
+func (c *Collector) AppEventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if cfApp, ok := obj.(*korifiv1alpha1.CFApp); ok {
+				c.store.Delete(cfApp.Name)
+			}
+		},
+	}
+}
+
+// CollectPod starts following the logs of every started container of the pod that is not followed yet
+func (c *Collector) CollectPod(ctx context.Context, pod *corev1.Pod) {
+	appGUID, sourceType, err := c.resolveSource(ctx, pod)
+	if err != nil {
+		c.logger.Info("failed to resolve the app of a pod", "namespace", pod.Namespace, "pod", pod.Name, "err", err)
+		return
+	}
+	if appGUID == "" {
+		return
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil {
+			continue
+		}
+
+		if !c.startTail(pod.UID, status) {
+			continue
+		}
+
+		go c.follow(ctx, pod, status, appGUID, logSource{
+			sourceType: sourceType,
+			instanceID: instanceID(pod, status.Name),
+		})
+	}
+}
+
+// ForgetPod stops following the containers of a deleted pod
+func (c *Collector) ForgetPod(pod *corev1.Pod) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.pods, pod.UID)
+}
+
+// startTail records the last seen status of a container, and returns whether that container has to be followed
+func (c *Collector) startTail(podUID types.UID, status corev1.ContainerStatus) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	tails, ok := c.pods[podUID]
+	if !ok {
+		tails = &podTails{
+			followed:   map[string]struct{}{},
+			containers: map[string]corev1.ContainerStatus{},
+		}
+		c.pods[podUID] = tails
+	}
+	tails.containers[status.Name] = status
+
+	key := fmt.Sprintf("%s/%d", status.Name, status.RestartCount)
+	if _, ok := tails.followed[key]; ok {
+		return false
+	}
+	tails.followed[key] = struct{}{}
+
+	return true
+}
+
+// containerRunning returns whether a followed container may still log, i.e. whether its pod still exists and the
+// container has neither terminated nor been restarted since
+func (c *Collector) containerRunning(podUID types.UID, followed corev1.ContainerStatus) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	tails, ok := c.pods[podUID]
+	if !ok {
+		return false
+	}
+
+	status := tails.containers[followed.Name]
+	return status.RestartCount == followed.RestartCount && status.State.Terminated == nil
+}
+
+// containerForgotten returns whether the pod of a followed container has been deleted
+func (c *Collector) containerForgotten(podUID types.UID) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, ok := c.pods[podUID]
+	return !ok
+}
+
+func (c *Collector) resolveSource(ctx context.Context, pod *corev1.Pod) (string, string, error) {
+	if appGUID, ok := pod.Labels[korifiv1alpha1.CFAppGUIDLabelKey]; ok {
+		processType, ok := pod.Labels[korifiv1alpha1.CFProcessTypeLabelKey]
+		if !ok {
+			processType = defaultProcessType
+		}
+		return appGUID, AppProcessLogSourceTypePrefix + strings.ToUpper(processType), nil
+	}
+
+	if buildGUID, ok := pod.Labels[repositories.BuildWorkloadLabelKey]; ok {
+		cfBuild := new(korifiv1alpha1.CFBuild)
+		if err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: buildGUID}, cfBuild); err != nil {
+			return "", "", client.IgnoreNotFound(err)
+		}
+		return cfBuild.Spec.AppRef.Name, StagingLogSourceType, nil
+	}
+
+	if taskGUID, ok := pod.Labels[JobNameLabelKey]; ok {
+		cfTask := new(korifiv1alpha1.CFTask)
+		if err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: taskGUID}, cfTask); err != nil {
+			if k8serrors.IsNotFound(err) {
+				// not every job is a task
+				return "", "", nil
+			}
+			return "", "", err
+		}
+		return cfTask.Spec.AppRef.Name, AppTaskLogSourceTypePrefix + cfTask.Name, nil
+	}
+
+	return "", "", nil
+}
+
+// logSource identifies where the lines of a container come from
+type logSource struct {
+	sourceType string
+	instanceID string
+}
+
+// follow stores the logs of a container until it terminates or its pod is deleted. Log streams also end when the
+// API server or the kubelet restarts, or when the connection is reset, in which case they are resumed from the
+// timestamp of the last stored line.
+func (c *Collector) follow(ctx context.Context, pod *corev1.Pod, status corev1.ContainerStatus, appGUID string, source logSource) {
+	logger := c.logger.WithValues("namespace", pod.Namespace, "pod", pod.Name, "container", status.Name)
+
+	backoff := c.backoff
+	var lastTimestamp int64
+	for {
+		stored, err := c.tail(ctx, pod.Namespace, pod.Name, status.Name, appGUID, source, &lastTimestamp)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Info("failed to follow container logs", "err", err)
+		}
+
+		if c.containerForgotten(pod.UID) {
+			return
+		}
+		// a stream that ends once the container is gone has returned all its lines; an interrupted one is resumed
+		// to read the lines that have not been read yet
+		if err == nil && !c.containerRunning(pod.UID, status) {
+			return
+		}
+
+		if stored {
+			backoff = c.backoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// tail stores the lines of a container log stream that are newer than lastTimestamp, which is moved forward to the
+// last stored line. It returns whether any line was stored, and a nil error when the stream ended.
+func (c *Collector) tail(ctx context.Context, namespace, podName, containerName, appGUID string, source logSource, lastTimestamp *int64) (bool, error) {
+	var sinceTime *metav1.Time
+	if *lastTimestamp != 0 {
+		// the logs API only takes whole seconds, so lines of the last second are read again and skipped below
+		since := metav1.NewTime(time.Unix(0, *lastTimestamp))
+		sinceTime = &since
+	}
+
+	logReadCloser, err := c.logStreamer.StreamLogs(ctx, namespace, podName, containerName, sinceTime)
+	if err != nil {
+		return false, err
+	}
+	defer logReadCloser.Close()
+
+	stored := false
+	reader := bufio.NewReader(logReadCloser)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			// the partial line is read again when the stream is resumed
+			return stored, err
+		}
+
+		if len(line) > 0 {
+			record := lineToLogRecord(line, source)
+			if record.Timestamp == 0 || record.Timestamp > *lastTimestamp {
+				c.store.Append(appGUID, record)
+				stored = true
+			}
+			if record.Timestamp > *lastTimestamp {
+				*lastTimestamp = record.Timestamp
+			}
+		}
+
+		if err == io.EOF {
+			return stored, nil
+		}
+	}
+}
+
+// lineToLogRecord parses a log line prefixed with an RFC3339 timestamp, as returned by the pod logs API when
+// timestamps are requested
+func lineToLogRecord(line string, source logSource) repositories.LogRecord {
+	message := line
+	var timestamp int64
+
+	if prefix, rest, found := strings.Cut(line, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			message = rest
+			timestamp = t.UnixNano()
+		}
+	}
+
+	return repositories.LogRecord{
+		// trim trailing newlines so that the CLI doesn't render extra log lines for them
+		Message:    strings.TrimRight(message, "\r\n"),
+		Timestamp:  timestamp,
+		InstanceID: source.instanceID,
+		Tags: map[string]string{
+			"source_type": source.sourceType,
+		},
+	}
+}
+
+// instanceID returns the CF instance index of an app container, and "0" for task and staging containers,
+// which always run a single instance
+func instanceID(pod *corev1.Pod, containerName string) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+
+		for _, env := range container.Env {
+			if env.Name == instanceIndexEnvVar {
+				return env.Value
+			}
+		}
+	}
+
+	return "0"
+}
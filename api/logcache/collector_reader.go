@@ -0,0 +1,222 @@
+package logcache
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+)
+
+// CollectorReader reads the logs kept by the log collector. Only the API replica holding the log collector lease
+// follows container logs, so that the kubelets serve every container log once no matter how many replicas there
+// are. That replica reads its own store, while the other replicas ask it through its Server. The lease is read from an
+// informer cache, and the collector is only reached over TLS, as requests carry the service account token of the API.
+type CollectorReader struct {
+	store      *Store
+	identity   string
+	leases     coordinationv1listers.LeaseNamespaceLister
+	leaseName  string
+	httpClient *http.Client
+}
+
+// NewCollectorReader returns a reader of the logs collected by the holder of the lease. httpClient is nil when the API
+// has no TLS certificate, in which case only the logs collected by this replica can be read.
+func NewCollectorReader(
+	store *Store,
+	identity string,
+	leases coordinationv1listers.LeaseNamespaceLister,
+	leaseName string,
+	httpClient *http.Client,
+) *CollectorReader {
+	return &CollectorReader{
+		store:      store,
+		identity:   identity,
+		leases:     leases,
+		leaseName:  leaseName,
+		httpClient: httpClient,
+	}
+}
+
+// CollectorIdentity returns the lease holder identity of a replica whose Server listens on address. Identities are
+// unique per process, so that a restarted replica does not resume the lease of its previous run.
+func CollectorIdentity(address string) string {
+	return address + "_" + uuid.NewString()
+}
+
+func collectorAddress(identity string) string {
+	address, _, _ := strings.Cut(identity, "_")
+	return address
+}
+
+// NewCollectorTLSConfig returns the TLS config to reach the Server of the log collector with. Replicas are dialled
+// through their pod IP, as read from the lease, which anyone allowed to update the lease can change, so the collector
+// has to present a certificate for serverName, i.e. the service of the API, signed by the CA in caPath. The CA is read
+// on every connection, as it is rotated along with the certificate.
+func NewCollectorTLSConfig(caPath, serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		// the certificate is verified in VerifyConnection instead, so that the CA can be reloaded
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyConnection: func(state tls.ConnectionState) error {
+			caPEM, err := os.ReadFile(caPath)
+			if err != nil {
+				return fmt.Errorf("failed to read the log collector CA: %w", err)
+			}
+
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(caPEM) {
+				return errors.New("failed to parse the log collector CA")
+			}
+
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("the log collector presented no certificate")
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, cert := range state.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err = state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}
+}
+
+// Read returns the stored envelopes of an app in chronological order
+func (r *CollectorReader) Read(ctx context.Context, appGUID string) ([]repositories.LogRecord, error) {
+	address, elected, err := r.collector()
+	if err != nil {
+		return nil, err
+	}
+	if !elected {
+		return []repositories.LogRecord{}, nil
+	}
+	if address == "" {
+		return r.store.Read(appGUID), nil
+	}
+
+	resp, err := r.get(ctx, address, recordsPath, appGUID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	records := []repositories.LogRecord{}
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode the log records from the log collector at %s: %w", address, err)
+	}
+
+	return records, nil
+}
+
+// Subscribe returns a channel receiving the envelopes collected for an app from now on, and a function that ends the
+// subscription. The channel is also closed when the log collector goes away, and right away while no collector has
+// been elected, in which case clients have to subscribe again to follow the next one.
+func (r *CollectorReader) Subscribe(ctx context.Context, appGUID string) (<-chan repositories.LogRecord, func(), error) {
+	address, elected, err := r.collector()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !elected {
+		records := make(chan repositories.LogRecord)
+		close(records)
+		return records, func() {}, nil
+	}
+	if address == "" {
+		records, unsubscribe := r.store.Subscribe(appGUID)
+		return records, unsubscribe, nil
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	resp, err := r.get(streamCtx, address, streamPath, appGUID)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	records := make(chan repositories.LogRecord, subscriptionBufferSize)
+	go func() {
+		defer close(records)
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var record repositories.LogRecord
+			if err := decoder.Decode(&record); err != nil {
+				return
+			}
+
+			select {
+			case records <- record:
+			case <-streamCtx.Done():
+				return
+			}
+		}
+	}()
+
+	return records, cancel, nil
+}
+
+// collector returns the address of the replica holding the log collector lease, or an empty address when that is this
+// replica. It returns false when no collector has been elected yet.
+func (r *CollectorReader) collector() (string, bool, error) {
+	lease, err := r.leases.Get(r.leaseName)
+	if k8serrors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get the log collector lease: %w", err)
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return "", false, nil
+	}
+
+	if *lease.Spec.HolderIdentity == r.identity {
+		return "", true, nil
+	}
+
+	if r.httpClient == nil {
+		return "", false, errors.New("the log collector can only be reached when the API has a TLS certificate")
+	}
+
+	return collectorAddress(*lease.Spec.HolderIdentity), true, nil
+}
+
+func (r *CollectorReader) get(ctx context.Context, address, pathTemplate, appGUID string) (*http.Response, error) {
+	path := strings.Replace(pathTemplate, "{guid}", url.PathEscape(appGUID), 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+address+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log collector request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the log collector at %s: %w", address, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("the log collector at %s responded with status %d", address, resp.StatusCode)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,288 @@
+package logcache_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/logcache/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/tools"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	k8stransport "k8s.io/client-go/transport"
+)
+
+var _ = Describe("CollectorReader", func() {
+	const (
+		leaseNamespace = "cf"
+		leaseName      = "log-collector"
+		identity       = "10.0.0.1:8081_this-replica"
+	)
+
+	var (
+		ctx            context.Context
+		store          *logcache.Store
+		collectorStore *logcache.Store
+		authenticator  *fake.ReplicaAuthenticator
+		collector      *httptest.Server
+		caPath         string
+		serverName     string
+		lease          *coordinationv1.Lease
+		withoutTLS     bool
+		reader         *logcache.CollectorReader
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = logcache.NewStore(10)
+		store.Append("app-guid", repositories.LogRecord{Message: "local", Timestamp: 1})
+
+		collectorStore = logcache.NewStore(10)
+		collectorStore.Append("app-guid", repositories.LogRecord{Message: "collected", Timestamp: 1})
+		authenticator = new(fake.ReplicaAuthenticator)
+		collector = httptest.NewTLSServer(logcache.NewServer(collectorStore, authenticator, logr.Discard()))
+
+		caPath = filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: collector.Certificate().Raw})
+		Expect(os.WriteFile(caPath, caPEM, 0o600)).To(Succeed())
+		// the certificate of httptest servers is issued for example.com
+		serverName = "example.com"
+		withoutTLS = false
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: leaseNamespace, Name: leaseName},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: tools.PtrTo(logcache.CollectorIdentity(strings.TrimPrefix(collector.URL, "https://"))),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		collector.Close()
+	})
+
+	JustBeforeEach(func() {
+		indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc})
+		if lease != nil {
+			Expect(indexer.Add(lease)).To(Succeed())
+		}
+		leases := coordinationv1listers.NewLeaseLister(indexer).Leases(leaseNamespace)
+
+		var httpClient *http.Client
+		if !withoutTLS {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = logcache.NewCollectorTLSConfig(caPath, serverName)
+			httpClient = &http.Client{Transport: k8stransport.NewBearerAuthRoundTripper("replica-token", transport)}
+		}
+
+		reader = logcache.NewCollectorReader(store, identity, leases, leaseName, httpClient)
+	})
+
+	Describe("Read", func() {
+		var (
+			records []repositories.LogRecord
+			readErr error
+		)
+
+		JustBeforeEach(func() {
+			records, readErr = reader.Read(ctx, "app-guid")
+		})
+
+		It("reads the store of the collector", func() {
+			Expect(readErr).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]repositories.LogRecord{{Message: "collected", Timestamp: 1}}))
+		})
+
+		It("authenticates with the token of the replica", func() {
+			Expect(authenticator.AuthenticateCallCount()).To(Equal(1))
+			_, token := authenticator.AuthenticateArgsForCall(0)
+			Expect(token).To(Equal("replica-token"))
+		})
+
+		When("this replica is the collector", func() {
+			BeforeEach(func() {
+				lease.Spec.HolderIdentity = tools.PtrTo(identity)
+			})
+
+			It("reads its own store", func() {
+				Expect(readErr).NotTo(HaveOccurred())
+				Expect(records).To(Equal([]repositories.LogRecord{{Message: "local", Timestamp: 1}}))
+				Expect(authenticator.AuthenticateCallCount()).To(BeZero())
+			})
+		})
+
+		When("no collector has been elected", func() {
+			BeforeEach(func() {
+				lease.Spec.HolderIdentity = nil
+			})
+
+			It("returns no records", func() {
+				Expect(readErr).NotTo(HaveOccurred())
+				Expect(records).To(BeEmpty())
+			})
+		})
+
+		When("the lease does not exist yet", func() {
+			BeforeEach(func() {
+				lease = nil
+			})
+
+			It("returns no records", func() {
+				Expect(readErr).NotTo(HaveOccurred())
+				Expect(records).To(BeEmpty())
+			})
+		})
+
+		When("the collector does not present a certificate for the server name", func() {
+			BeforeEach(func() {
+				serverName = "korifi-api-svc.korifi.svc"
+			})
+
+			It("does not send the token of the replica", func() {
+				Expect(readErr).To(MatchError(ContainSubstring("failed to reach the log collector")))
+				Expect(authenticator.AuthenticateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the collector is signed by another CA", func() {
+			BeforeEach(func() {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Expect(err).NotTo(HaveOccurred())
+				template := &x509.Certificate{
+					SerialNumber:          big.NewInt(1),
+					NotAfter:              time.Now().Add(time.Hour),
+					DNSNames:              []string{serverName},
+					IsCA:                  true,
+					BasicConstraintsValid: true,
+					KeyUsage:              x509.KeyUsageCertSign,
+				}
+				otherCA, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+				Expect(err).NotTo(HaveOccurred())
+
+				caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherCA})
+				Expect(os.WriteFile(caPath, caPEM, 0o600)).To(Succeed())
+			})
+
+			It("does not send the token of the replica", func() {
+				Expect(readErr).To(MatchError(ContainSubstring("failed to reach the log collector")))
+				Expect(authenticator.AuthenticateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the API has no TLS certificate", func() {
+			BeforeEach(func() {
+				withoutTLS = true
+			})
+
+			It("does not reach the collector", func() {
+				Expect(readErr).To(MatchError(ContainSubstring("can only be reached when the API has a TLS certificate")))
+				Expect(authenticator.AuthenticateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the collector does not authenticate the replica", func() {
+			BeforeEach(func() {
+				authenticator.AuthenticateReturns(errors.New("who are you"))
+			})
+
+			It("returns an error", func() {
+				Expect(readErr).To(MatchError(ContainSubstring("responded with status 401")))
+			})
+		})
+	})
+
+	Describe("Subscribe", func() {
+		var (
+			records      <-chan repositories.LogRecord
+			unsubscribe  func()
+			subscribeErr error
+		)
+
+		JustBeforeEach(func() {
+			records, unsubscribe, subscribeErr = reader.Subscribe(ctx, "app-guid")
+		})
+
+		It("receives the records appended to the store of the collector", func() {
+			Expect(subscribeErr).NotTo(HaveOccurred())
+			defer unsubscribe()
+
+			collectorStore.Append("app-guid", repositories.LogRecord{Message: "streamed", Timestamp: 2})
+			collectorStore.Append("other-app-guid", repositories.LogRecord{Message: "other", Timestamp: 3})
+
+			Eventually(records).Should(Receive(Equal(repositories.LogRecord{Message: "streamed", Timestamp: 2})))
+			Consistently(records).ShouldNot(Receive())
+		})
+
+		It("closes the channel when unsubscribing", func() {
+			Expect(subscribeErr).NotTo(HaveOccurred())
+			unsubscribe()
+
+			Eventually(records).Should(BeClosed())
+		})
+
+		It("closes the channel when the collector goes away", func() {
+			Expect(subscribeErr).NotTo(HaveOccurred())
+			defer unsubscribe()
+			collector.CloseClientConnections()
+
+			Eventually(records).Should(BeClosed())
+		})
+
+		When("this replica is the collector", func() {
+			BeforeEach(func() {
+				lease.Spec.HolderIdentity = tools.PtrTo(identity)
+			})
+
+			It("subscribes to its own store", func() {
+				Expect(subscribeErr).NotTo(HaveOccurred())
+				defer unsubscribe()
+
+				store.Append("app-guid", repositories.LogRecord{Message: "streamed locally", Timestamp: 2})
+
+				Eventually(records).Should(Receive(Equal(repositories.LogRecord{Message: "streamed locally", Timestamp: 2})))
+			})
+		})
+
+		When("no collector has been elected", func() {
+			BeforeEach(func() {
+				lease.Spec.HolderIdentity = nil
+			})
+
+			It("returns a closed channel", func() {
+				Expect(subscribeErr).NotTo(HaveOccurred())
+				defer unsubscribe()
+
+				Expect(records).To(BeClosed())
+			})
+		})
+
+		When("the collector cannot be reached", func() {
+			BeforeEach(func() {
+				collector.Close()
+			})
+
+			It("returns an error", func() {
+				Expect(subscribeErr).To(MatchError(ContainSubstring("failed to reach the log collector")))
+			})
+		})
+	})
+})
@@ -0,0 +1,378 @@
+package logcache_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/logcache/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	controllersfake "code.cloudfoundry.org/korifi/controllers/fake"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		ctx         context.Context
+		cancel      context.CancelFunc
+		k8sClient   *controllersfake.Client
+		logStreamer *fake.PodLogStreamer
+		store       *logcache.Store
+		collector   *logcache.Collector
+		pod         *corev1.Pod
+	)
+
+	startedContainer := func(name string, restartCount int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:         name,
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			RestartCount: restartCount,
+		}
+	}
+
+	// openStream returns a log stream that stays open after the given lines, like the stream of a running container
+	openStream := func(streamCtx context.Context, lines string) io.ReadCloser {
+		reader, writer := io.Pipe()
+		go func() {
+			defer GinkgoRecover()
+			_, err := writer.Write([]byte(lines))
+			if err != nil {
+				return
+			}
+			<-streamCtx.Done()
+			writer.CloseWithError(streamCtx.Err())
+		}()
+
+		return reader
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		k8sClient = new(controllersfake.Client)
+		logStreamer = new(fake.PodLogStreamer)
+		logStreamer.StreamLogsStub = func(streamCtx context.Context, _, _, containerName string, _ *metav1.Time) (io.ReadCloser, error) {
+			lines := "2022-10-01T12:00:00.000000001Z hello from " + containerName + "\n" +
+				"2022-10-01T12:00:01.000000001Z bye from " + containerName + "\n"
+			return openStream(streamCtx, lines), nil
+		}
+		store = logcache.NewStore(100)
+		collector = logcache.NewCollector(k8sClient, logStreamer, store, wait.Backoff{Duration: time.Millisecond}, logr.Discard())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-pod",
+				Namespace: "space-guid",
+				UID:       "app-pod-uid",
+				Labels: map[string]string{
+					korifiv1alpha1.CFAppGUIDLabelKey:     "app-guid",
+					korifiv1alpha1.CFProcessTypeLabelKey: "web",
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{startedContainer("application", 0)},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	JustBeforeEach(func() {
+		collector.CollectPod(ctx, pod)
+	})
+
+	It("stores the logs of the app containers", func() {
+		Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(Equal([]repositories.LogRecord{
+			{
+				Message:    "hello from application",
+				Timestamp:  time.Date(2022, 10, 1, 12, 0, 0, 1, time.UTC).UnixNano(),
+				InstanceID: "0",
+				Tags:       map[string]string{"source_type": "APP/PROC/WEB"},
+			},
+			{
+				Message:    "bye from application",
+				Timestamp:  time.Date(2022, 10, 1, 12, 0, 1, 1, time.UTC).UnixNano(),
+				InstanceID: "0",
+				Tags:       map[string]string{"source_type": "APP/PROC/WEB"},
+			},
+		}))
+
+		Expect(logStreamer.StreamLogsCallCount()).To(Equal(1))
+		_, namespace, podName, containerName, sinceTime := logStreamer.StreamLogsArgsForCall(0)
+		Expect(namespace).To(Equal("space-guid"))
+		Expect(podName).To(Equal("app-pod"))
+		Expect(containerName).To(Equal("application"))
+		Expect(sinceTime).To(BeNil())
+	})
+
+	When("the log stream is interrupted", func() {
+		BeforeEach(func() {
+			logStreamer.StreamLogsStub = func(streamCtx context.Context, _, _, _ string, sinceTime *metav1.Time) (io.ReadCloser, error) {
+				if sinceTime == nil {
+					reader, writer := io.Pipe()
+					go func() {
+						defer GinkgoRecover()
+						_, _ = writer.Write([]byte("2022-10-01T12:00:00.000000001Z hello\n2022-10-01T12:00:00.5"))
+						writer.CloseWithError(errors.New("connection reset"))
+					}()
+					return reader, nil
+				}
+
+				lines := "2022-10-01T12:00:00.000000001Z hello\n" +
+					"2022-10-01T12:00:00.500000001Z still here\n" +
+					"2022-10-01T12:00:01.000000001Z bye\n"
+				return openStream(streamCtx, lines), nil
+			}
+		})
+
+		It("resumes it from the last stored line", func() {
+			Eventually(func() []string {
+				messages := []string{}
+				for _, record := range store.Read("app-guid") {
+					messages = append(messages, record.Message)
+				}
+				return messages
+			}).Should(Equal([]string{"hello", "still here", "bye"}))
+
+			Expect(logStreamer.StreamLogsCallCount()).To(Equal(2))
+			_, _, _, _, sinceTime := logStreamer.StreamLogsArgsForCall(1)
+			Expect(sinceTime).NotTo(BeNil())
+			Expect(sinceTime.Time).To(BeTemporally("==", time.Date(2022, 10, 1, 12, 0, 0, 1, time.UTC)))
+		})
+	})
+
+	When("the container has terminated", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "application",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+			}}
+			logStreamer.StreamLogsStub = func(_ context.Context, _, _, _ string, _ *metav1.Time) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("2022-10-01T12:00:00.000000001Z bye\n")), nil
+			}
+		})
+
+		It("stops following it once its log stream ends", func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(1))
+			Consistently(logStreamer.StreamLogsCallCount).Should(Equal(1))
+		})
+	})
+
+	When("the pod runs an instance of another process type", func() {
+		BeforeEach(func() {
+			pod.Labels[korifiv1alpha1.CFProcessTypeLabelKey] = "worker"
+		})
+
+		It("tags the logs with the process type", func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+			for _, record := range store.Read("app-guid") {
+				Expect(record.Tags).To(HaveKeyWithValue("source_type", "APP/PROC/WORKER"))
+			}
+		})
+	})
+
+	When("the container runs an app instance with a non-zero index", func() {
+		BeforeEach(func() {
+			pod.Spec.Containers = []corev1.Container{{
+				Name: "application",
+				Env: []corev1.EnvVar{
+					{Name: "CF_INSTANCE_INDEX", Value: "2"},
+				},
+			}}
+		})
+
+		It("tags the logs with the instance index", func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+			for _, record := range store.Read("app-guid") {
+				Expect(record.InstanceID).To(Equal("2"))
+			}
+		})
+	})
+
+	When("the pod is seen again", func() {
+		JustBeforeEach(func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+			collector.CollectPod(ctx, pod)
+		})
+
+		It("does not follow the same container twice", func() {
+			Consistently(logStreamer.StreamLogsCallCount).Should(Equal(1))
+		})
+	})
+
+	When("the container is restarted", func() {
+		JustBeforeEach(func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+
+			restartedPod := pod.DeepCopy()
+			restartedPod.Status.ContainerStatuses = []corev1.ContainerStatus{startedContainer("application", 1)}
+			collector.CollectPod(ctx, restartedPod)
+		})
+
+		It("follows the restarted container and keeps the logs of the previous one", func() {
+			Eventually(logStreamer.StreamLogsCallCount).Should(Equal(2))
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(4))
+		})
+	})
+
+	When("a container has not started yet", func() {
+		BeforeEach(func() {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "application",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}},
+			}}
+		})
+
+		It("does not follow it", func() {
+			Consistently(logStreamer.StreamLogsCallCount).Should(BeZero())
+		})
+	})
+
+	When("the pod is a staging pod", func() {
+		BeforeEach(func() {
+			pod.Labels = map[string]string{
+				repositories.BuildWorkloadLabelKey: "build-guid",
+			}
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{startedContainer("detect", 0)}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{startedContainer("completion", 0)}
+
+			k8sClient.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+				Expect(key).To(Equal(client.ObjectKey{Namespace: "space-guid", Name: "build-guid"}))
+				cfBuild, ok := obj.(*korifiv1alpha1.CFBuild)
+				Expect(ok).To(BeTrue())
+				cfBuild.Spec.AppRef.Name = "app-guid"
+				return nil
+			}
+		})
+
+		It("stores the logs of all its containers as staging logs of the app", func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(4))
+			for _, record := range store.Read("app-guid") {
+				Expect(record.Tags).To(HaveKeyWithValue("source_type", "STG"))
+			}
+		})
+	})
+
+	When("the pod belongs to a task job", func() {
+		BeforeEach(func() {
+			pod.Labels = map[string]string{
+				logcache.JobNameLabelKey: "task-guid",
+			}
+
+			k8sClient.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+				Expect(key).To(Equal(client.ObjectKey{Namespace: "space-guid", Name: "task-guid"}))
+				cfTask, ok := obj.(*korifiv1alpha1.CFTask)
+				Expect(ok).To(BeTrue())
+				cfTask.Name = "task-guid"
+				cfTask.Spec.AppRef.Name = "app-guid"
+				return nil
+			}
+		})
+
+		It("stores the logs as task logs of the app", func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+			for _, record := range store.Read("app-guid") {
+				Expect(record.Tags).To(HaveKeyWithValue("source_type", "APP/TASK/task-guid"))
+			}
+		})
+
+		When("the job is not a task", func() {
+			BeforeEach(func() {
+				k8sClient.GetReturns(k8serrors.NewNotFound(schema.GroupResource{}, "task-guid"))
+				k8sClient.GetStub = nil
+			})
+
+			It("ignores the pod", func() {
+				Consistently(logStreamer.StreamLogsCallCount).Should(BeZero())
+			})
+		})
+	})
+
+	When("the pod does not belong to an app", func() {
+		BeforeEach(func() {
+			pod.Labels = nil
+		})
+
+		It("ignores the pod", func() {
+			Consistently(logStreamer.StreamLogsCallCount).Should(BeZero())
+		})
+	})
+
+	When("following the logs fails", func() {
+		BeforeEach(func() {
+			logStreamer.StreamLogsStub = nil
+			logStreamer.StreamLogsReturns(nil, errors.New("boom"))
+		})
+
+		It("keeps retrying without storing any logs", func() {
+			Eventually(logStreamer.StreamLogsCallCount).Should(BeNumerically(">", 1))
+			Consistently(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(BeEmpty())
+		})
+	})
+
+	When("an app is deleted", func() {
+		JustBeforeEach(func() {
+			Eventually(func() []repositories.LogRecord { return store.Read("app-guid") }).Should(HaveLen(2))
+
+			collector.AppEventHandler().OnDelete(&korifiv1alpha1.CFApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-guid", Namespace: "space-guid"},
+			})
+		})
+
+		It("drops its logs from the store", func() {
+			Expect(store.Read("app-guid")).To(BeEmpty())
+		})
+	})
+
+	When("a pod is deleted", func() {
+		JustBeforeEach(func() {
+			Eventually(logStreamer.StreamLogsCallCount).Should(Equal(1))
+
+			handler := collector.PodEventHandler(ctx)
+			handler.OnDelete(toolscache.DeletedFinalStateUnknown{Obj: pod})
+			handler.OnAdd(pod)
+		})
+
+		It("forgets the containers it followed", func() {
+			Eventually(logStreamer.StreamLogsCallCount).Should(Equal(2))
+		})
+	})
+
+	When("a pod is deleted while its log stream is being resumed", func() {
+		BeforeEach(func() {
+			logStreamer.StreamLogsStub = func(_ context.Context, _, _, _ string, _ *metav1.Time) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("")), nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			Eventually(logStreamer.StreamLogsCallCount).Should(BeNumerically(">", 1))
+			collector.ForgetPod(pod)
+		})
+
+		It("stops resuming it", func() {
+			var calls int
+			Eventually(func() int {
+				calls = logStreamer.StreamLogsCallCount()
+				time.Sleep(10 * time.Millisecond)
+				return logStreamer.StreamLogsCallCount() - calls
+			}).Should(BeZero())
+			Consistently(logStreamer.StreamLogsCallCount).Should(Equal(calls))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package logcache
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+// K8sPodLogStreamer follows container logs through the Kubernetes pod logs API
+type K8sPodLogStreamer struct {
+	k8sClient k8sclient.Interface
+}
+
+func NewK8sPodLogStreamer(k8sClient k8sclient.Interface) *K8sPodLogStreamer {
+	return &K8sPodLogStreamer{k8sClient: k8sClient}
+}
+
+func (s *K8sPodLogStreamer) StreamLogs(ctx context.Context, namespace, podName, containerName string, sinceTime *metav1.Time) (io.ReadCloser, error) {
+	return s.k8sClient.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  containerName,
+		Follow:     true,
+		Timestamps: true,
+		SinceTime:  sinceTime,
+	}).Stream(ctx)
+}
@@ -0,0 +1,44 @@
+package logcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+// ServiceAccountAuthenticator accepts the tokens of the service account the API replicas run as
+type ServiceAccountAuthenticator struct {
+	k8sClient client.Client
+	username  string
+}
+
+func NewServiceAccountAuthenticator(k8sClient client.Client, namespace, serviceAccountName string) *ServiceAccountAuthenticator {
+	return &ServiceAccountAuthenticator{
+		k8sClient: k8sClient,
+		username:  fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccountName),
+	}
+}
+
+func (a *ServiceAccountAuthenticator) Authenticate(ctx context.Context, token string) error {
+	review := &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{Token: token},
+	}
+	if err := a.k8sClient.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review token: %w", err)
+	}
+
+	if !review.Status.Authenticated {
+		return errors.New("not authenticated")
+	}
+
+	if review.Status.User.Username != a.username {
+		return fmt.Errorf("%q is not an API replica", review.Status.User.Username)
+	}
+
+	return nil
+}
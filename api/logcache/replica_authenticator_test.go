@@ -0,0 +1,77 @@
+package logcache_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/logcache"
+	controllersfake "code.cloudfoundry.org/korifi/controllers/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ServiceAccountAuthenticator", func() {
+	var (
+		k8sClient *controllersfake.Client
+		status    authv1.TokenReviewStatus
+		authErr   error
+	)
+
+	BeforeEach(func() {
+		k8sClient = new(controllersfake.Client)
+		status = authv1.TokenReviewStatus{
+			Authenticated: true,
+			User:          authv1.UserInfo{Username: "system:serviceaccount:korifi:korifi-api"},
+		}
+		k8sClient.CreateStub = func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			obj.(*authv1.TokenReview).Status = status
+			return nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		authErr = logcache.NewServiceAccountAuthenticator(k8sClient, "korifi", "korifi-api").Authenticate(context.Background(), "a-token")
+	})
+
+	It("accepts the tokens of the service account", func() {
+		Expect(authErr).NotTo(HaveOccurred())
+
+		Expect(k8sClient.CreateCallCount()).To(Equal(1))
+		_, obj, _ := k8sClient.CreateArgsForCall(0)
+		Expect(obj.(*authv1.TokenReview).Spec.Token).To(Equal("a-token"))
+	})
+
+	When("the token belongs to another service account", func() {
+		BeforeEach(func() {
+			status.User.Username = "system:serviceaccount:other:korifi-api"
+		})
+
+		It("rejects it", func() {
+			Expect(authErr).To(MatchError(ContainSubstring("is not an API replica")))
+		})
+	})
+
+	When("the token is not authenticated", func() {
+		BeforeEach(func() {
+			status.Authenticated = false
+		})
+
+		It("rejects it", func() {
+			Expect(authErr).To(MatchError("not authenticated"))
+		})
+	})
+
+	When("reviewing the token fails", func() {
+		BeforeEach(func() {
+			k8sClient.CreateReturns(errors.New("boom"))
+			k8sClient.CreateStub = nil
+		})
+
+		It("returns the error", func() {
+			Expect(authErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+})
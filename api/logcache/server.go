@@ -0,0 +1,98 @@
+package logcache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+)
+
+const (
+	recordsPath = "/apps/{guid}/logs"
+	streamPath  = "/apps/{guid}/logs/stream"
+)
+
+//counterfeiter:generate -o fake -fake-name ReplicaAuthenticator . ReplicaAuthenticator
+
+// ReplicaAuthenticator checks that a bearer token belongs to an API replica
+type ReplicaAuthenticator interface {
+	Authenticate(ctx context.Context, token string) error
+}
+
+// Server exposes the store of the log collector to the other API replicas. Users are authorized by the replica
+// serving their request, so the server only lets API replicas in.
+type Server struct {
+	store         *Store
+	authenticator ReplicaAuthenticator
+	logger        logr.Logger
+	router        *mux.Router
+}
+
+func NewServer(store *Store, authenticator ReplicaAuthenticator, logger logr.Logger) *Server {
+	s := &Server{
+		store:         store,
+		authenticator: authenticator,
+		logger:        logger,
+		router:        mux.NewRouter(),
+	}
+	s.router.Path(recordsPath).Methods("GET").HandlerFunc(s.readHandler)
+	s.router.Path(streamPath).Methods("GET").HandlerFunc(s.streamHandler)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err := s.authenticator.Authenticate(r.Context(), strings.TrimPrefix(authHeader, "Bearer ")); err != nil {
+		s.logger.Info("rejected log collector request", "err", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) readHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.store.Read(mux.Vars(r)["guid"])); err != nil {
+		s.logger.Info("failed to write log records", "err", err)
+	}
+}
+
+// streamHandler writes the records appended for an app as one JSON document per line, until the replica reading
+// them disconnects
+func (s *Server) streamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	records, unsubscribe := s.store.Subscribe(mux.Vars(r)["guid"])
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case record := <-records:
+			if err := encoder.Encode(record); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
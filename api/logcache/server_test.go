@@ -0,0 +1,63 @@
+package logcache_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/logcache/fake"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		store         *logcache.Store
+		authenticator *fake.ReplicaAuthenticator
+		req           *http.Request
+		rr            *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		store = logcache.NewStore(10)
+		store.Append("app-guid", repositories.LogRecord{Message: "hello", Timestamp: 1})
+		authenticator = new(fake.ReplicaAuthenticator)
+
+		req = httptest.NewRequest(http.MethodGet, "/apps/app-guid/logs", nil)
+		req.Header.Set("Authorization", "Bearer replica-token")
+		rr = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		logcache.NewServer(store, authenticator, logr.Discard()).ServeHTTP(rr, req)
+	})
+
+	It("returns the stored records of the app", func() {
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		var records []repositories.LogRecord
+		Expect(json.Unmarshal(rr.Body.Bytes(), &records)).To(Succeed())
+		Expect(records).To(Equal([]repositories.LogRecord{{Message: "hello", Timestamp: 1}}))
+	})
+
+	It("authenticates the bearer token", func() {
+		Expect(authenticator.AuthenticateCallCount()).To(Equal(1))
+		_, token := authenticator.AuthenticateArgsForCall(0)
+		Expect(token).To(Equal("replica-token"))
+	})
+
+	When("the request carries no bearer token", func() {
+		BeforeEach(func() {
+			req.Header.Del("Authorization")
+		})
+
+		It("rejects it", func() {
+			Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+			Expect(authenticator.AuthenticateCallCount()).To(BeZero())
+		})
+	})
+})
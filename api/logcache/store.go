@@ -0,0 +1,139 @@
+package logcache
+
+import (
+	"sort"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+// subscriptionBufferSize is the number of envelopes a subscriber can lag behind before envelopes are dropped
+const subscriptionBufferSize = 256
+
+// Store keeps the most recent log envelopes of every app in a ring buffer of bounded size, so that logs
+// outlive the pods that emitted them. Envelopes are also fanned out to the subscribers of the app as they
+// are appended.
+type Store struct {
+	maxEnvelopesPerApp int
+
+	mutex         sync.RWMutex
+	buffers       map[string]*ringBuffer
+	subscriptions map[string]map[*subscription]struct{}
+}
+
+type subscription struct {
+	records chan repositories.LogRecord
+}
+
+func NewStore(maxEnvelopesPerApp int) *Store {
+	return &Store{
+		maxEnvelopesPerApp: maxEnvelopesPerApp,
+		buffers:            map[string]*ringBuffer{},
+		subscriptions:      map[string]map[*subscription]struct{}{},
+	}
+}
+
+func (s *Store) Append(appGUID string, records ...repositories.LogRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	buffer, ok := s.buffers[appGUID]
+	if !ok {
+		buffer = newRingBuffer(s.maxEnvelopesPerApp)
+		s.buffers[appGUID] = buffer
+	}
+
+	for _, record := range records {
+		buffer.add(record)
+
+		for sub := range s.subscriptions[appGUID] {
+			select {
+			case sub.records <- record:
+			default:
+				// never let a slow subscriber hold up log collection
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the envelopes appended for an app from now on, and a function that
+// ends the subscription and closes the channel
+func (s *Store) Subscribe(appGUID string) (<-chan repositories.LogRecord, func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub := &subscription{records: make(chan repositories.LogRecord, subscriptionBufferSize)}
+	if _, ok := s.subscriptions[appGUID]; !ok {
+		s.subscriptions[appGUID] = map[*subscription]struct{}{}
+	}
+	s.subscriptions[appGUID][sub] = struct{}{}
+
+	var once sync.Once
+	return sub.records, func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			delete(s.subscriptions[appGUID], sub)
+			if len(s.subscriptions[appGUID]) == 0 {
+				delete(s.subscriptions, appGUID)
+			}
+			close(sub.records)
+		})
+	}
+}
+
+// Read returns the stored envelopes of an app in chronological order
+func (s *Store) Read(appGUID string) []repositories.LogRecord {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	buffer, ok := s.buffers[appGUID]
+	if !ok {
+		return []repositories.LogRecord{}
+	}
+
+	records := buffer.items()
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+
+	return records
+}
+
+func (s *Store) Delete(appGUID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.buffers, appGUID)
+}
+
+// ringBuffer grows up to its size as records are added, rather than preallocating it, since most apps log far less
+// than the maximum kept
+type ringBuffer struct {
+	size    int
+	records []repositories.LogRecord
+	next    int
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{size: size}
+}
+
+func (b *ringBuffer) add(record repositories.LogRecord) {
+	if b.size == 0 {
+		return
+	}
+
+	if len(b.records) < b.size {
+		b.records = append(b.records, record)
+		return
+	}
+
+	b.records[b.next] = record
+	b.next = (b.next + 1) % b.size
+}
+
+func (b *ringBuffer) items() []repositories.LogRecord {
+	return append(append([]repositories.LogRecord{}, b.records[b.next:]...), b.records[:b.next]...)
+}
@@ -0,0 +1,128 @@
+package logcache_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/repositories"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var store *logcache.Store
+
+	BeforeEach(func() {
+		store = logcache.NewStore(3)
+	})
+
+	It("returns no records for an unknown app", func() {
+		Expect(store.Read("unknown-app")).To(BeEmpty())
+	})
+
+	It("returns the records of an app in chronological order", func() {
+		store.Append("app-1", repositories.LogRecord{Message: "second", Timestamp: 2})
+		store.Append("app-1", repositories.LogRecord{Message: "first", Timestamp: 1})
+		store.Append("app-2", repositories.LogRecord{Message: "other", Timestamp: 3})
+
+		Expect(store.Read("app-1")).To(Equal([]repositories.LogRecord{
+			{Message: "first", Timestamp: 1},
+			{Message: "second", Timestamp: 2},
+		}))
+	})
+
+	When("more records than the buffer size are appended", func() {
+		BeforeEach(func() {
+			for i := int64(1); i <= 5; i++ {
+				store.Append("app-1", repositories.LogRecord{Timestamp: i})
+			}
+		})
+
+		It("keeps only the most recent ones", func() {
+			Expect(store.Read("app-1")).To(Equal([]repositories.LogRecord{
+				{Timestamp: 3},
+				{Timestamp: 4},
+				{Timestamp: 5},
+			}))
+		})
+	})
+
+	It("does not let callers modify the stored records", func() {
+		store.Append("app-1", repositories.LogRecord{Message: "original", Timestamp: 1})
+		store.Read("app-1")[0].Message = "modified"
+
+		Expect(store.Read("app-1")[0].Message).To(Equal("original"))
+	})
+
+	When("an app is deleted", func() {
+		BeforeEach(func() {
+			store.Append("app-1", repositories.LogRecord{Timestamp: 1})
+			store.Delete("app-1")
+		})
+
+		It("drops its records", func() {
+			Expect(store.Read("app-1")).To(BeEmpty())
+		})
+	})
+
+	Describe("subscriptions", func() {
+		var (
+			records <-chan repositories.LogRecord
+			cancel  func()
+		)
+
+		BeforeEach(func() {
+			store.Append("app-1", repositories.LogRecord{Message: "before", Timestamp: 1})
+			records, cancel = store.Subscribe("app-1")
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		It("receives the records appended for the app from now on", func() {
+			store.Append("app-2", repositories.LogRecord{Message: "other", Timestamp: 2})
+			store.Append("app-1", repositories.LogRecord{Message: "after", Timestamp: 3})
+
+			Eventually(records).Should(Receive(Equal(repositories.LogRecord{Message: "after", Timestamp: 3})))
+			Consistently(records).ShouldNot(Receive())
+		})
+
+		It("shares the appended records between all subscribers", func() {
+			otherRecords, otherCancel := store.Subscribe("app-1")
+			defer otherCancel()
+
+			store.Append("app-1", repositories.LogRecord{Message: "after", Timestamp: 3})
+
+			Eventually(records).Should(Receive())
+			Eventually(otherRecords).Should(Receive())
+		})
+
+		It("drops records when a subscriber is not keeping up", func() {
+			for i := int64(0); i < 1000; i++ {
+				store.Append("app-1", repositories.LogRecord{Timestamp: i})
+			}
+
+			Expect(len(records)).To(BeNumerically("<", 1000))
+		})
+
+		When("the subscription is cancelled", func() {
+			BeforeEach(func() {
+				cancel()
+			})
+
+			It("closes the channel", func() {
+				Eventually(records).Should(BeClosed())
+			})
+
+			It("can be cancelled again", func() {
+				Expect(cancel).NotTo(Panic())
+			})
+
+			It("stops fanning out records", func() {
+				Expect(func() {
+					store.Append("app-1", repositories.LogRecord{Timestamp: 2})
+				}).NotTo(Panic())
+			})
+		})
+	})
+})
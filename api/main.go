@@ -6,37 +6,61 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"code.cloudfoundry.org/korifi/api/accesslog"
 	"code.cloudfoundry.org/korifi/api/actions"
 	"code.cloudfoundry.org/korifi/api/actions/manifest"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	"code.cloudfoundry.org/korifi/api/config"
 	"code.cloudfoundry.org/korifi/api/handlers"
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/metrics"
+	"code.cloudfoundry.org/korifi/api/oauth"
 	"code.cloudfoundry.org/korifi/api/payloads"
 	"code.cloudfoundry.org/korifi/api/repositories"
 	"code.cloudfoundry.org/korifi/api/repositories/conditions"
 	reporegistry "code.cloudfoundry.org/korifi/api/repositories/registry"
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	buildv1alpha2 "github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/cache"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	k8sclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	k8stransport "k8s.io/client-go/transport"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -45,6 +69,13 @@ import (
 
 var createTimeout = time.Second * 120
 
+const (
+	accessLogWebhookTimeout   = 10 * time.Second
+	accessLogWebhookQueueSize = 1000
+	accessLogQueueSize        = 1000
+	logCollectorLeaseName     = "korifi-api-log-collector"
+)
+
 func init() {
 	utilruntime.Must(korifiv1alpha1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(buildv1alpha2.AddToScheme(scheme.Scheme))
@@ -87,6 +118,16 @@ func main() {
 
 	klog.SetLogger(ctrl.Log)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "korifi-api", config.Tracing)
+	if err != nil {
+		panic(fmt.Sprintf("could not set up tracing: %v", err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			ctrl.Log.Error(err, "error shutting down tracing")
+		}
+	}()
+
 	privilegedCRClient, err := client.NewWithWatch(k8sClientConfig, client.Options{})
 	if err != nil {
 		panic(fmt.Sprintf("could not create privileged k8s client: %v", err))
@@ -100,18 +141,72 @@ func main() {
 	if err != nil {
 		panic(fmt.Sprintf("could not create dynamic k8s client: %v", err))
 	}
-	namespaceRetriever := repositories.NewNamespaceRetriever(dynamicClient)
+	metadataClient, err := metadata.NewForConfig(k8sClientConfig)
+	if err != nil {
+		panic(fmt.Sprintf("could not create metadata k8s client: %v", err))
+	}
+	namespaceInformerFactory := metadatainformer.NewSharedInformerFactory(metadataClient, 0)
+	namespaceRetriever, err := repositories.NewCachingNamespaceRetriever(dynamicClient, namespaceInformerFactory)
+	if err != nil {
+		panic(fmt.Sprintf("could not create namespace retriever: %v", err))
+	}
+	startNamespaceInformers(namespaceInformerFactory)
 
 	mapper, err := apiutil.NewDynamicRESTMapper(k8sClientConfig)
 	if err != nil {
 		panic(fmt.Sprintf("could not create kubernetes REST mapper: %v", err))
 	}
 
-	userClientFactory := authorization.NewUnprivilegedClientFactory(k8sClientConfig, mapper, authorization.NewDefaultBackoff())
+	tokenKeyStore := oauth.NewSecretKeyStore(
+		privilegedCRClient,
+		config.RootNamespace,
+		config.GetOAuthKeyRotationInterval(),
+		maxDuration(config.GetOAuthAccessTokenTTL(), config.GetOAuthRefreshTokenTTL()),
+	)
+	tokenIssuer := oauth.NewTokenIssuer(
+		tokenKeyStore,
+		config.ServerURL+handlers.OAuthTokenPath,
+		config.GetOAuthAccessTokenTTL(),
+		config.GetOAuthRefreshTokenTTL(),
+	)
 
-	identityProvider := wireIdentityProvider(privilegedCRClient, k8sClientConfig)
+	oidcProvider := wireOIDCProvider(config)
+	tokenAuthenticators := []oauth.Authenticator{tokenIssuer}
+	if config.OAuth.OIDC.AuthenticateBearerTokens {
+		tokenAuthenticators = append(tokenAuthenticators, oauth.NewOIDCAuthenticator(oidcProvider, config.OAuth.OIDC.ClientID, oidcClaimMapping(config)))
+	}
+
+	certPolicy := authorization.NewCertificatePolicy(config.GetClientCertificateMaxLifetime())
+	identityProvider := wireIdentityProvider(privilegedCRClient, k8sClientConfig, certPolicy, tokenAuthenticators)
 	cachingIdentityProvider := authorization.NewCachingIdentityProvider(identityProvider, cache.NewExpiring())
-	nsPermissions := authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider)
+	if config.ClientCertificates.RevocationListConfigMapName != "" {
+		startRevocationListInformer(privilegedK8sClient, config, certPolicy.RevocationListEventHandler(cachingIdentityProvider))
+	}
+	// members of the admin group may act as another user, whose identity the rest of the API then sees
+	userIdentityProvider := authorization.NewImpersonationIdentityProvider(
+		cachingIdentityProvider,
+		config.Impersonation.AdminGroup,
+	)
+	userClientFactory := oauth.NewUserClientFactory(
+		authorization.NewImpersonatingClientFactory(k8sClientConfig, mapper, authorization.NewDefaultBackoff()),
+		authorization.NewUnprivilegedClientFactory(k8sClientConfig, mapper, authorization.NewDefaultBackoff()),
+		userIdentityProvider,
+		tokenAuthenticators...,
+	)
+	permissionsCache, err := crcache.New(k8sClientConfig, crcache.Options{
+		Scheme: scheme.Scheme,
+		Mapper: mapper,
+		// the cached role bindings and namespaces are only ever read
+		UnsafeDisableDeepCopyByObject: crcache.DisableDeepCopyByObject{
+			&rbacv1.RoleBinding{}: true,
+			&corev1.Namespace{}:   true,
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("could not create permissions cache: %v", err))
+	}
+	startPermissionsCache(permissionsCache)
+	nsPermissions := authorization.NewNamespacePermissions(privilegedCRClient, permissionsCache, userIdentityProvider)
 
 	serverURL, err := url.Parse(config.ServerURL)
 	if err != nil {
@@ -124,6 +219,12 @@ func main() {
 	}
 	orgRepo := repositories.NewOrgRepo(config.RootNamespace, privilegedCRClient, userClientFactory, nsPermissions, createTimeout)
 	spaceRepo := repositories.NewSpaceRepo(namespaceRetriever, orgRepo, userClientFactory, nsPermissions, createTimeout)
+	orgQuotaRepo := repositories.NewOrgQuotaRepo(config.RootNamespace, userClientFactory)
+	spaceQuotaRepo := repositories.NewSpaceQuotaRepo(namespaceRetriever, userClientFactory, nsPermissions)
+	isolationSegmentRepo := repositories.NewIsolationSegmentRepo(config.RootNamespace, namespaceRetriever, userClientFactory)
+	envVarGroupRepo := repositories.NewEnvVarGroupRepo(config.RootNamespace, userClientFactory)
+	auditEventRepo := repositories.NewAuditEventRepo(config.RootNamespace, privilegedCRClient, userClientFactory, nsPermissions)
+	appUsageEventRepo := repositories.NewAppUsageEventRepo(config.RootNamespace, userClientFactory, nsPermissions)
 	processRepo := repositories.NewProcessRepo(namespaceRetriever, userClientFactory, nsPermissions)
 	podRepo := repositories.NewPodRepo(userClientFactory, metricsFetcherFunction)
 	cfAppConditionAwaiter := conditions.NewConditionAwaiter[*korifiv1alpha1.CFApp, korifiv1alpha1.CFAppList](createTimeout)
@@ -137,10 +238,12 @@ func main() {
 	bindingConditionAwaiter := conditions.NewConditionAwaiter[*korifiv1alpha1.CFServiceBinding, korifiv1alpha1.CFServiceBindingList](createTimeout)
 	serviceBindingRepo := repositories.NewServiceBindingRepo(namespaceRetriever, userClientFactory, nsPermissions, bindingConditionAwaiter)
 	buildpackRepo := repositories.NewBuildpackRepository(config.BuilderName, userClientFactory, config.RootNamespace)
+	userRepo := repositories.NewUserRepo(config.RootNamespace, privilegedCRClient, userClientFactory)
 	roleRepo := repositories.NewRoleRepo(
 		userClientFactory,
 		spaceRepo,
-		authorization.NewNamespacePermissions(privilegedCRClient, cachingIdentityProvider),
+		userRepo,
+		nsPermissions,
 		config.RootNamespace,
 		config.RoleMappings,
 	)
@@ -168,13 +271,68 @@ func main() {
 	processScaler := actions.NewProcessScaler(appRepo, processRepo)
 	processStats := actions.NewProcessStats(processRepo, podRepo, appRepo)
 	manifest := actions.NewManifest(
+		appRepo,
 		domainRepo,
 		config.DefaultDomainName,
-		manifest.NewStateCollector(appRepo, domainRepo, processRepo, routeRepo),
+		manifest.NewStateCollector(appRepo, domainRepo, processRepo, routeRepo, serviceInstanceRepo, serviceBindingRepo),
 		manifest.NewNormalizer(config.DefaultDomainName),
-		manifest.NewApplier(appRepo, domainRepo, processRepo, routeRepo),
+		manifest.NewApplier(appRepo, domainRepo, processRepo, routeRepo, serviceInstanceRepo, serviceBindingRepo),
+	)
+	logStore := logcache.NewStore(config.GetMaxLogEnvelopesPerApp())
+	logInformerCache, err := crcache.New(k8sClientConfig, crcache.Options{Scheme: scheme.Scheme, Mapper: mapper})
+	if err != nil {
+		panic(fmt.Sprintf("could not create log informer cache: %v", err))
+	}
+	drainClient, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
+		CacheReader: logInformerCache,
+		Client:      privilegedCRClient,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("could not create syslog drain client: %v", err))
+	}
+	drainAddressFilter, err := syslogdrain.NewAddressFilter(config.SyslogDrains.AllowedCIDRs, config.SyslogDrains.DeniedCIDRs)
+	if err != nil {
+		panic(fmt.Sprintf("could not create syslog drain address filter: %v", err))
+	}
+	tlsPath, tlsFound := os.LookupEnv("TLSCONFIG")
+	logCollectorIdentity := logcache.CollectorIdentity(logCollectorAddress(config.GetLogCollectorPort()))
+	startLogCollector(
+		logInformerCache,
+		privilegedK8sClient,
+		config.RootNamespace,
+		logCollectorIdentity,
+		logcache.NewCollector(
+			privilegedCRClient,
+			logcache.NewK8sPodLogStreamer(privilegedK8sClient),
+			logStore,
+			logcache.DefaultFollowBackoff,
+			ctrl.Log.WithName("log-collector"),
+		),
+		syslogdrain.NewManager(
+			drainClient,
+			logStore,
+			syslogdrain.NewWriterFactory(nil, drainAddressFilter),
+			syslogdrain.DefaultBackoff,
+			ctrl.Log.WithName("syslog-drains"),
+		),
+		newLogCollectorServer(
+			config.GetLogCollectorPort(),
+			tlsPath,
+			tlsFound,
+			logcache.NewServer(
+				logStore,
+				logcache.NewServiceAccountAuthenticator(privilegedCRClient, os.Getenv("POD_NAMESPACE"), os.Getenv("SERVICE_ACCOUNT_NAME")),
+				ctrl.Log.WithName("log-collector-server"),
+			),
+		),
 	)
-	appLogs := actions.NewAppLogs(appRepo, buildRepo, podRepo)
+	appLogs := actions.NewAppLogs(appRepo, podRepo, logcache.NewCollectorReader(
+		logStore,
+		logCollectorIdentity,
+		startLogCollectorLeaseInformer(privilegedK8sClient, config.RootNamespace),
+		logCollectorLeaseName,
+		newLogCollectorClient(k8sClientConfig, tlsPath, tlsFound, config.LogCache.CollectorServerName),
+	))
 
 	decoderValidator, err := handlers.NewDefaultDecoderValidator()
 	if err != nil {
@@ -196,6 +354,7 @@ func main() {
 			domainRepo,
 			spaceRepo,
 			processScaler,
+			envVarGroupRepo,
 			decoderValidator,
 		),
 		handlers.NewRouteHandler(
@@ -263,6 +422,40 @@ func main() {
 			decoderValidator,
 		),
 
+		handlers.NewOrgQuotaHandler(
+			*serverURL,
+			orgQuotaRepo,
+			decoderValidator,
+		),
+
+		handlers.NewSpaceQuotaHandler(
+			*serverURL,
+			spaceQuotaRepo,
+			decoderValidator,
+		),
+
+		handlers.NewIsolationSegmentHandler(
+			*serverURL,
+			isolationSegmentRepo,
+			decoderValidator,
+		),
+
+		handlers.NewEnvVarGroupHandler(
+			*serverURL,
+			envVarGroupRepo,
+			decoderValidator,
+		),
+
+		handlers.NewAuditEventHandler(
+			*serverURL,
+			auditEventRepo,
+		),
+
+		handlers.NewAppUsageEventHandler(
+			*serverURL,
+			appUsageEventRepo,
+		),
+
 		handlers.NewSpaceManifestHandler(
 			*serverURL,
 			manifest,
@@ -270,13 +463,24 @@ func main() {
 			decoderValidator,
 		),
 
+		handlers.NewAppManifestHandler(
+			*serverURL,
+			manifest,
+		),
+
 		handlers.NewRoleHandler(
 			*serverURL,
 			roleRepo,
 			decoderValidator,
 		),
 
-		handlers.NewWhoAmI(cachingIdentityProvider, *serverURL),
+		handlers.NewUserHandler(
+			*serverURL,
+			userRepo,
+			decoderValidator,
+		),
+
+		handlers.NewWhoAmI(userIdentityProvider, *serverURL),
 
 		handlers.NewBuildpackHandler(
 			*serverURL,
@@ -307,6 +511,8 @@ func main() {
 
 		handlers.NewOAuthToken(
 			*serverURL,
+			oauth.NewTokenGranter(wireCredentialVerifier(config, privilegedCRClient, oidcProvider), tokenIssuer),
+			tokenKeyStore,
 		),
 	}
 
@@ -319,24 +525,54 @@ func main() {
 	authInfoParser := authorization.NewInfoParser()
 	router.Use(
 		handlers.NewCorrelationIDMiddleware().Middleware,
+		handlers.NewTracing().Middleware,
+		handlers.NewAccessLogMiddleware(
+			newAccessLogger(config, privilegedCRClient),
+			authInfoParser,
+			userIdentityProvider,
+			config.AccessLog.IncludeRequestBodies,
+			config.GetRedactSensitiveRequestBodies(),
+		).Middleware,
 		handlers.NewCFCliVersionMiddleware().Middleware,
 		handlers.NewHTTPLogging().Middleware,
+		handlers.NewHTTPMetrics().Middleware,
 		handlers.NewAuthenticationMiddleware(
 			authInfoParser,
-			cachingIdentityProvider,
+			userIdentityProvider,
+			unauthenticatedEndpoints,
+		).Middleware,
+		handlers.NewRateLimitMiddleware(
+			userIdentityProvider,
+			config.RateLimits,
+			clock.RealClock{},
 			unauthenticatedEndpoints,
 		).Middleware,
 		handlers.NewCFUserMiddleware(
 			privilegedCRClient,
-			cachingIdentityProvider,
+			userIdentityProvider,
+			userRepo,
 			config.RootNamespace,
 			cache.NewExpiring(),
 			unauthenticatedEndpoints,
 		).Middleware,
+		handlers.NewPermissionMiddleware(
+			authorization.NewRolePermissions(permissionsCache, userIdentityProvider, config.RoleMappings),
+			namespaceRetriever,
+			config.RootNamespace,
+			unauthenticatedEndpoints,
+		).Middleware,
+		handlers.NewAuditEventMiddleware(
+			auditEventRepo,
+			userIdentityProvider,
+			namespaceRetriever,
+			config.RootNamespace,
+			unauthenticatedEndpoints,
+		).Middleware,
 	)
 
+	startMetricsServer(config.GetMetricsPort())
+
 	portString := fmt.Sprintf(":%v", config.InternalPort)
-	tlsPath, tlsFound := os.LookupEnv("TLSCONFIG")
 
 	srv := &http.Server{
 		Addr:              portString,
@@ -346,49 +582,366 @@ func main() {
 		ReadHeaderTimeout: time.Duration(config.ReadHeaderTimeout * int(time.Second)),
 		WriteTimeout:      time.Duration(config.WriteTimeout * int(time.Second)),
 		ErrorLog:          log.New(&logrWriter{Logger: ctrl.Log, Message: "HTTP server error"}, "", 0),
+		ConnContext:       handlers.ConnContext,
 	}
 
 	if tlsFound {
 		ctrl.Log.Info("Listening with TLS on " + portString)
-		certPath := filepath.Join(tlsPath, "tls.crt")
-		keyPath := filepath.Join(tlsPath, "tls.key")
+		srv.TLSConfig = &tls.Config{
+			NextProtos:     []string{"h2"},
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: startCertWatcher(tlsPath).GetCertificate,
+		}
+		err = srv.ListenAndServeTLS("", "")
+		if err != nil {
+			ctrl.Log.Error(err, "error serving TLS")
+			os.Exit(1)
+		}
+	} else {
+		ctrl.Log.Info("Listening without TLS on " + portString)
+		err := srv.ListenAndServe()
+		if err != nil {
+			ctrl.Log.Error(err, "error serving HTTP")
+			os.Exit(1)
+		}
+	}
+}
+
+// newAccessLogger writes the access log to the configured file and webhook, if any. Entries are chained with the HMAC
+// key of the access log secret, in a chain named after this pod and process.
+func newAccessLogger(apiConfig *config.APIConfig, privilegedClient client.Client) *accesslog.Logger {
+	accessLogConfig := apiConfig.AccessLog
+	sinks := []accesslog.Sink{}
+
+	if accessLogConfig.File.Path != "" {
+		sinks = append(sinks, accesslog.NewFileSink(accessLogConfig.File.Path, apiConfig.GetAccessLogMaxFileSizeMB(), apiConfig.GetAccessLogMaxFileBackups()))
+	}
 
-		var certWatcher *certwatcher.CertWatcher
-		certWatcher, err = certwatcher.New(certPath, keyPath)
+	if accessLogConfig.WebhookURL != "" {
+		webhookSink := accesslog.NewWebhookSink(
+			accessLogConfig.WebhookURL,
+			&http.Client{Timeout: accessLogWebhookTimeout},
+			accessLogWebhookQueueSize,
+			ctrl.Log.WithName("access-log-webhook"),
+		)
+		go webhookSink.Start(context.Background())
+		sinks = append(sinks, webhookSink)
+	}
+
+	var key []byte
+	if len(sinks) > 0 {
+		secret := new(corev1.Secret)
+		err := privilegedClient.Get(context.Background(), client.ObjectKey{Namespace: apiConfig.RootNamespace, Name: accessLogConfig.HMACKeySecretName}, secret)
 		if err != nil {
-			ctrl.Log.Error(err, "error creating TLS watcher")
+			panic(fmt.Sprintf("could not get access log HMAC key: %v", err))
+		}
+		key = secret.Data["key"]
+		if len(key) == 0 {
+			panic(fmt.Sprintf("the access log secret %q has no key", accessLogConfig.HMACKeySecretName))
+		}
+	}
+
+	accessLogger := accesslog.NewLogger(
+		ctrl.Log.WithName("access-log"),
+		key,
+		os.Getenv("POD_NAME")+"/"+uuid.NewString(),
+		accessLogQueueSize,
+		sinks...,
+	)
+	go accessLogger.Start(context.Background())
+
+	return accessLogger
+}
+
+// startMetricsServer serves the Prometheus metrics over plain HTTP on a separate port, so that they are not
+// exposed through the API ingress
+func startMetricsServer(port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		ctrl.Log.Info(fmt.Sprintf("Serving metrics on :%d", port))
+		if err := srv.ListenAndServe(); err != nil {
+			ctrl.Log.Error(err, "error serving metrics")
 			os.Exit(1)
 		}
+	}()
+}
+
+// startNamespaceInformers starts the metadata informers backing the namespace retriever and waits for them to sync
+func startNamespaceInformers(informerFactory metadatainformer.SharedInformerFactory) {
+	informerFactory.Start(wait.NeverStop)
+	for gvr, synced := range informerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			panic(fmt.Sprintf("could not sync the %s informer", gvr.Resource))
+		}
+	}
+}
+
+// startPermissionsCache indexes role bindings by subject, starts the informers backing the namespace permissions
+// and waits for them to sync, so that no request is answered from an empty cache
+func startPermissionsCache(permissionsCache crcache.Cache) {
+	ctx := context.Background()
+
+	if err := authorization.IndexRoleBindingSubjects(ctx, permissionsCache); err != nil {
+		panic(fmt.Sprintf("could not index role bindings: %v", err))
+	}
+
+	if _, err := permissionsCache.GetInformer(ctx, &corev1.Namespace{}); err != nil {
+		panic(fmt.Sprintf("could not create namespace informer: %v", err))
+	}
+
+	go func() {
+		if err := permissionsCache.Start(ctx); err != nil {
+			ctrl.Log.Error(err, "error running permissions informers")
+			os.Exit(1)
+		}
+	}()
+
+	if !permissionsCache.WaitForCacheSync(ctx) {
+		panic("could not sync the permissions cache")
+	}
+}
+
+// startRevocationListInformer watches the revocation list ConfigMap in the root namespace and waits for it to sync,
+// so that no revoked certificate is accepted after a restart
+func startRevocationListInformer(k8sClient k8sclient.Interface, apiConfig *config.APIConfig, handler toolscache.ResourceEventHandler) {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, 0,
+		informers.WithNamespace(apiConfig.RootNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", apiConfig.ClientCertificates.RevocationListConfigMapName).String()
+		}),
+	)
+	informerFactory.Core().V1().ConfigMaps().Informer().AddEventHandler(handler)
+
+	informerFactory.Start(wait.NeverStop)
+	for informerType, synced := range informerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			panic(fmt.Sprintf("could not sync the %s informer", informerType))
+		}
+	}
+}
+
+// startLogCollector elects the API replica that collects logs. That replica follows the logs of app, task and staging
+// containers, drops the logs of deleted apps, forwards logs to syslog drains and serves the collected logs to the other
+// replicas, so that the kubelets serve each container log once and every replica reads the same history.
+func startLogCollector(informerCache crcache.Cache, k8sClient k8sclient.Interface, leaseNamespace, identity string, collector *logcache.Collector, drainManager *syslogdrain.Manager, server *http.Server) {
+	ctx := context.Background()
+
+	go runAsLeader(ctx, k8sClient, leaseNamespace, logCollectorLeaseName, identity, func(leaderCtx context.Context) {
+		podInformer, err := informerCache.GetInformer(leaderCtx, &corev1.Pod{})
+		if err != nil {
+			panic(fmt.Sprintf("could not create pod informer: %v", err))
+		}
+		podInformer.AddEventHandler(collector.PodEventHandler(leaderCtx))
+
+		appInformer, err := informerCache.GetInformer(leaderCtx, &korifiv1alpha1.CFApp{})
+		if err != nil {
+			panic(fmt.Sprintf("could not create app informer: %v", err))
+		}
+		appInformer.AddEventHandler(collector.AppEventHandler())
+
+		for _, obj := range []client.Object{&korifiv1alpha1.CFServiceInstance{}, &korifiv1alpha1.CFServiceBinding{}} {
+			informer, err := informerCache.GetInformer(leaderCtx, obj)
+			if err != nil {
+				panic(fmt.Sprintf("could not create %T informer: %v", obj, err))
+			}
+			informer.AddEventHandler(drainManager.EventHandler(leaderCtx))
+		}
 
 		go func() {
-			if err2 := certWatcher.Start(context.Background()); err2 != nil {
-				ctrl.Log.Error(err, "error watching TLS")
+			if err := informerCache.Start(leaderCtx); err != nil {
+				ctrl.Log.Error(err, "error running log informers")
 				os.Exit(1)
 			}
 		}()
 
+		go func() {
+			ctrl.Log.Info("Serving collected logs on " + server.Addr)
+			var err error
+			if server.TLSConfig != nil {
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil {
+				ctrl.Log.Error(err, "error serving collected logs")
+				os.Exit(1)
+			}
+		}()
+	})
+}
+
+// logCollectorAddress returns the address the other replicas reach the log collector server of this replica at
+func logCollectorAddress(port int) string {
+	host, found := os.LookupEnv("POD_IP")
+	if !found {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// newLogCollectorServer serves the logs collected by this replica with the certificate of the API, if there is one.
+// It has no write timeout, as the other replicas follow the logs of an app for as long as their clients do.
+func newLogCollectorServer(port int, tlsPath string, tlsFound bool, handler http.Handler) *http.Server {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if tlsFound {
 		srv.TLSConfig = &tls.Config{
-			NextProtos:     []string{"h2"},
 			MinVersion:     tls.VersionTLS12,
-			GetCertificate: certWatcher.GetCertificate,
+			GetCertificate: startCertWatcher(tlsPath).GetCertificate,
 		}
-		err = srv.ListenAndServeTLS("", "")
-		if err != nil {
-			ctrl.Log.Error(err, "error serving TLS")
+	}
+
+	return srv
+}
+
+// newLogCollectorClient returns the client the other replicas reach the log collector server with. Requests carry the
+// service account token of the API, which the server reviews, so they are only sent over TLS to a server presenting a
+// certificate for serverName. There is no client without TLS.
+func newLogCollectorClient(restConfig *rest.Config, tlsPath string, tlsFound bool, serverName string) *http.Client {
+	if !tlsFound {
+		return nil
+	}
+
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	baseTransport.TLSClientConfig = logcache.NewCollectorTLSConfig(filepath.Join(tlsPath, "ca.crt"), serverName)
+
+	roundTripper, err := k8stransport.NewBearerAuthWithRefreshRoundTripper(restConfig.BearerToken, restConfig.BearerTokenFile, baseTransport)
+	if err != nil {
+		panic(fmt.Sprintf("could not create log collector client: %v", err))
+	}
+
+	return &http.Client{Transport: roundTripper}
+}
+
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=list;watch,namespace=ROOT_NAMESPACE
+
+// startLogCollectorLeaseInformer caches the log collector lease, so that reading logs does not get it from the
+// Kubernetes API every time
+func startLogCollectorLeaseInformer(k8sClient k8sclient.Interface, namespace string) coordinationv1listers.LeaseNamespaceLister {
+	factory := informers.NewSharedInformerFactoryWithOptions(k8sClient, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", logCollectorLeaseName).String()
+		}),
+	)
+	leaseLister := factory.Coordination().V1().Leases().Lister()
+
+	ctx := context.Background()
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			panic(fmt.Sprintf("could not sync %v informer", informerType))
+		}
+	}
+
+	return leaseLister.Leases(namespace)
+}
+
+func startCertWatcher(tlsPath string) *certwatcher.CertWatcher {
+	certWatcher, err := certwatcher.New(filepath.Join(tlsPath, "tls.crt"), filepath.Join(tlsPath, "tls.key"))
+	if err != nil {
+		ctrl.Log.Error(err, "error creating TLS watcher")
+		os.Exit(1)
+	}
+
+	go func() {
+		if err := certWatcher.Start(context.Background()); err != nil {
+			ctrl.Log.Error(err, "error watching TLS")
 			os.Exit(1)
 		}
-	} else {
-		ctrl.Log.Info("Listening without TLS on " + portString)
-		err := srv.ListenAndServe()
+	}()
+
+	return certWatcher
+}
+
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update,namespace=ROOT_NAMESPACE
+
+// runAsLeader calls run once this replica holds the lease with the given name. Like controller-runtime managers, the
+// process exits when the lease is lost, so that another replica takes over and this one restarts as a follower.
+func runAsLeader(ctx context.Context, k8sClient k8sclient.Interface, namespace, name, identity string, run func(ctx context.Context)) {
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Client:    k8sClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: identity,
+			},
+		},
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: run,
+			OnStoppedLeading: func() {
+				ctrl.Log.Error(errors.New("leader election lost"), "lost lease", "lease", name)
+				os.Exit(1)
+			},
+		},
+	})
+}
+
+func wireIdentityProvider(client client.Client, restConfig *rest.Config, certPolicy *authorization.CertificatePolicy, tokenAuthenticators []oauth.Authenticator) authorization.IdentityProvider {
+	tokenInspector := oauth.NewTokenInspector(authorization.NewTokenReviewer(client), tokenAuthenticators...)
+	certInspector := authorization.NewCertInspector(restConfig, certPolicy)
+	return authorization.NewCertTokenIdentityProvider(tokenInspector, certInspector)
+}
+
+func wireOIDCProvider(apiConfig *config.APIConfig) *oauth.OIDCProvider {
+	if apiConfig.OAuth.OIDC.IssuerURL == "" {
+		return nil
+	}
+
+	httpClient, err := oauth.NewOIDCHTTPClient(apiConfig.OAuth.OIDC.CACert)
+	if err != nil {
+		panic(fmt.Sprintf("could not create OIDC client: %v", err))
+	}
+
+	return oauth.NewOIDCProvider(apiConfig.OAuth.OIDC.IssuerURL, httpClient)
+}
+
+func oidcClaimMapping(apiConfig *config.APIConfig) oauth.OIDCClaimMapping {
+	return oauth.OIDCClaimMapping{
+		UsernameClaim:  apiConfig.GetOIDCUsernameClaim(),
+		UsernamePrefix: apiConfig.OAuth.OIDC.UsernamePrefix,
+		GroupsClaim:    apiConfig.OAuth.OIDC.GroupsClaim,
+		GroupsPrefix:   apiConfig.OAuth.OIDC.GroupsPrefix,
+	}
+}
+
+func wireCredentialVerifier(apiConfig *config.APIConfig, privilegedClient client.Client, oidcProvider *oauth.OIDCProvider) oauth.CredentialVerifier {
+	if apiConfig.GetOAuthBackend() != config.OAuthOIDCBackend {
+		return oauth.NewServiceAccountVerifier(privilegedClient, append([]string{apiConfig.RootNamespace}, apiConfig.OAuth.ServiceAccountNamespaces...))
+	}
+
+	var clientSecret string
+	if apiConfig.OAuth.OIDC.ClientSecretName != "" {
+		secret := new(corev1.Secret)
+		err := privilegedClient.Get(context.Background(), client.ObjectKey{Namespace: apiConfig.RootNamespace, Name: apiConfig.OAuth.OIDC.ClientSecretName}, secret)
 		if err != nil {
-			ctrl.Log.Error(err, "error serving HTTP")
-			os.Exit(1)
+			panic(fmt.Sprintf("could not get OIDC client secret: %v", err))
 		}
+		clientSecret = string(secret.Data["clientSecret"])
 	}
+
+	return oauth.NewOIDCVerifier(oidcProvider, apiConfig.OAuth.OIDC.ClientID, clientSecret, oidcClaimMapping(apiConfig))
 }
 
-func wireIdentityProvider(client client.Client, restConfig *rest.Config) authorization.IdentityProvider {
-	tokenReviewer := authorization.NewTokenReviewer(client)
-	certInspector := authorization.NewCertInspector(restConfig)
-	return authorization.NewCertTokenIdentityProvider(tokenReviewer, certInspector)
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
 }
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	IdentityCache  = "identity"
+	CFUserCache    = "cf_user"
+	NamespaceCache = "namespace"
+
+	// RequestRateLimit and ConcurrencyLimit tell which limit a rate limited request exceeded
+	RequestRateLimit = "rate"
+	ConcurrencyLimit = "concurrency"
+
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+
+	// transportErrorCode labels k8s client requests that did not get a response
+	transportErrorCode = "error"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_api_http_requests_total",
+		Help: "Number of HTTP requests handled by the API, by route template, method and status code",
+	}, []string{"route", "method", "code"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_api_http_request_duration_seconds",
+		Help:    "Time spent handling HTTP requests, by route template and method",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	k8sClientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_api_k8s_client_requests_total",
+		Help: "Number of Kubernetes API requests made on behalf of users, by method and status code",
+	}, []string{"method", "code"})
+
+	k8sClientRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_api_k8s_client_request_duration_seconds",
+		Help:    "Latency of Kubernetes API requests made on behalf of users, by method",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_api_cache_lookups_total",
+		Help: "Number of lookups in the API caches, by cache and result (hit or miss)",
+	}, []string{"cache", "result"})
+
+	conditionAwaitTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_api_condition_await_timeouts_total",
+		Help: "Number of times the API gave up waiting for a resource condition, by resource kind and condition",
+	}, []string{"kind", "condition"})
+
+	rateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_api_rate_limited_requests_total",
+		Help: "Number of requests rejected by the per identity rate limits, by request class and exceeded limit",
+	}, []string{"class", "limit"})
+)
+
+func init() {
+	// the controller-runtime registry also holds the client-go, Go runtime and process metrics
+	ctrlmetrics.Registry.MustRegister(
+		httpRequests,
+		httpRequestDuration,
+		k8sClientRequests,
+		k8sClientRequestDuration,
+		cacheLookups,
+		conditionAwaitTimeouts,
+		rateLimitedRequests,
+	)
+}
+
+// Handler serves all the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{})
+}
+
+func ObserveHTTPRequest(route, method string, code int, duration time.Duration) {
+	httpRequests.WithLabelValues(route, method, strconv.Itoa(code)).Inc()
+	httpRequestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+}
+
+func ObserveCacheLookup(cache string, hit bool) {
+	result := cacheMiss
+	if hit {
+		result = cacheHit
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+func ObserveConditionAwaitTimeout(kind, condition string) {
+	conditionAwaitTimeouts.WithLabelValues(kind, condition).Inc()
+}
+
+func ObserveRateLimitedRequest(class, limit string) {
+	rateLimitedRequests.WithLabelValues(class, limit).Inc()
+}
+
+// InstrumentK8sClient wraps the transport of a Kubernetes client so that its requests are counted and timed. It
+// is meant to be used as a rest.Config WrapTransport.
+func InstrumentK8sClient(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := rt.RoundTrip(req)
+
+		method := strings.ToUpper(req.Method)
+		code := transportErrorCode
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		k8sClientRequests.WithLabelValues(method, code).Inc()
+		k8sClientRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
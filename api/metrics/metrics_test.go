@@ -0,0 +1,119 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("Metrics", func() {
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(recorder).To(HaveHTTPStatus(http.StatusOK))
+		return recorder.Body.String()
+	}
+
+	// sample returns the value of a sample in the scraped metrics, or zero if it has not been recorded yet
+	sample := func(series string) float64 {
+		matches := regexp.MustCompile(regexp.QuoteMeta(series) + ` (\S+)`).FindStringSubmatch(scrape())
+		if matches == nil {
+			return 0
+		}
+		value, err := strconv.ParseFloat(matches[1], 64)
+		Expect(err).NotTo(HaveOccurred())
+		return value
+	}
+
+	Describe("ObserveCacheLookup", func() {
+		It("counts hits and misses per cache", func() {
+			hits := sample(`korifi_api_cache_lookups_total{cache="identity",result="hit"}`)
+			misses := sample(`korifi_api_cache_lookups_total{cache="identity",result="miss"}`)
+
+			metrics.ObserveCacheLookup(metrics.IdentityCache, true)
+			metrics.ObserveCacheLookup(metrics.IdentityCache, true)
+			metrics.ObserveCacheLookup(metrics.IdentityCache, false)
+
+			Expect(sample(`korifi_api_cache_lookups_total{cache="identity",result="hit"}`)).To(Equal(hits + 2))
+			Expect(sample(`korifi_api_cache_lookups_total{cache="identity",result="miss"}`)).To(Equal(misses + 1))
+		})
+	})
+
+	Describe("ObserveConditionAwaitTimeout", func() {
+		It("counts timeouts per kind and condition", func() {
+			metrics.ObserveConditionAwaitTimeout("CFOrg", "Ready")
+
+			Expect(sample(`korifi_api_condition_await_timeouts_total{condition="Ready",kind="CFOrg"}`)).To(BeNumerically(">=", 1))
+		})
+	})
+
+	Describe("ObserveRateLimitedRequest", func() {
+		It("counts rejected requests per class and limit", func() {
+			rejected := sample(`korifi_api_rate_limited_requests_total{class="reads",limit="rate"}`)
+
+			metrics.ObserveRateLimitedRequest("reads", metrics.RequestRateLimit)
+
+			Expect(sample(`korifi_api_rate_limited_requests_total{class="reads",limit="rate"}`)).To(Equal(rejected + 1))
+		})
+	})
+
+	Describe("InstrumentK8sClient", func() {
+		var (
+			roundTripErr error
+			resp         *http.Response
+			err          error
+		)
+
+		BeforeEach(func() {
+			roundTripErr = nil
+		})
+
+		JustBeforeEach(func() {
+			transport := metrics.InstrumentK8sClient(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				if roundTripErr != nil {
+					return nil, roundTripErr
+				}
+				return &http.Response{StatusCode: http.StatusConflict}, nil
+			}))
+
+			resp, err = transport.RoundTrip(httptest.NewRequest(http.MethodPatch, "https://k8s/apis/foo", nil))
+		})
+
+		It("returns the response of the wrapped transport", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		})
+
+		It("counts and times the request by method and status code", func() {
+			Expect(sample(`korifi_api_k8s_client_requests_total{code="409",method="PATCH"}`)).To(BeNumerically(">=", 1))
+			Expect(sample(`korifi_api_k8s_client_request_duration_seconds_count{method="PATCH"}`)).To(BeNumerically(">=", 1))
+		})
+
+		When("the request fails", func() {
+			BeforeEach(func() {
+				roundTripErr = errors.New("connection refused")
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(MatchError("connection refused"))
+			})
+
+			It("counts the request as an error", func() {
+				Expect(sample(`korifi_api_k8s_client_requests_total{code="error",method="PATCH"}`)).To(BeNumerically(">=", 1))
+			})
+		})
+	})
+})
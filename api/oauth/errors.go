@@ -0,0 +1,62 @@
+package oauth
+
+import "net/http"
+
+// OAuth error codes, as returned by UAA and understood by the cf CLI
+const (
+	UnauthorizedErrorCode         = "unauthorized"
+	InvalidTokenErrorCode         = "invalid_token"
+	InvalidRequestErrorCode       = "invalid_request"
+	UnsupportedGrantTypeErrorCode = "unsupported_grant_type"
+)
+
+// Error is an error that is presented to OAuth clients as described in RFC 6749, section 5.2
+type Error struct {
+	code        string
+	description string
+	cause       error
+}
+
+func (e Error) Error() string {
+	if e.cause == nil {
+		return e.description
+	}
+	return e.description + ": " + e.cause.Error()
+}
+
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
+func (e Error) Code() string {
+	return e.code
+}
+
+func (e Error) Description() string {
+	return e.description
+}
+
+func (e Error) HTTPStatus() int {
+	switch e.code {
+	case UnauthorizedErrorCode, InvalidTokenErrorCode:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func NewUnauthorizedError(cause error) Error {
+	return Error{code: UnauthorizedErrorCode, description: "Bad credentials", cause: cause}
+}
+
+func NewInvalidTokenError(cause error) Error {
+	return Error{code: InvalidTokenErrorCode, description: "Invalid refresh token", cause: cause}
+}
+
+func NewInvalidRequestError(description string) Error {
+	return Error{code: InvalidRequestErrorCode, description: description}
+}
+
+func NewUnsupportedGrantTypeError(grantType string) Error {
+	return Error{code: UnsupportedGrantTypeErrorCode, description: "Unsupported grant type: " + grantType}
+}
@@ -0,0 +1,193 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+)
+
+type Authenticator struct {
+	AuthenticateStub        func(context.Context, string) (oauth.Principal, error)
+	authenticateMutex       sync.RWMutex
+	authenticateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	authenticateReturns struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	authenticateReturnsOnCall map[int]struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	RecognisesStub        func(string) bool
+	recognisesMutex       sync.RWMutex
+	recognisesArgsForCall []struct {
+		arg1 string
+	}
+	recognisesReturns struct {
+		result1 bool
+	}
+	recognisesReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Authenticator) Authenticate(arg1 context.Context, arg2 string) (oauth.Principal, error) {
+	fake.authenticateMutex.Lock()
+	ret, specificReturn := fake.authenticateReturnsOnCall[len(fake.authenticateArgsForCall)]
+	fake.authenticateArgsForCall = append(fake.authenticateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AuthenticateStub
+	fakeReturns := fake.authenticateReturns
+	fake.recordInvocation("Authenticate", []interface{}{arg1, arg2})
+	fake.authenticateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Authenticator) AuthenticateCallCount() int {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	return len(fake.authenticateArgsForCall)
+}
+
+func (fake *Authenticator) AuthenticateCalls(stub func(context.Context, string) (oauth.Principal, error)) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = stub
+}
+
+func (fake *Authenticator) AuthenticateArgsForCall(i int) (context.Context, string) {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	argsForCall := fake.authenticateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Authenticator) AuthenticateReturns(result1 oauth.Principal, result2 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	fake.authenticateReturns = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Authenticator) AuthenticateReturnsOnCall(i int, result1 oauth.Principal, result2 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	if fake.authenticateReturnsOnCall == nil {
+		fake.authenticateReturnsOnCall = make(map[int]struct {
+			result1 oauth.Principal
+			result2 error
+		})
+	}
+	fake.authenticateReturnsOnCall[i] = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Authenticator) Recognises(arg1 string) bool {
+	fake.recognisesMutex.Lock()
+	ret, specificReturn := fake.recognisesReturnsOnCall[len(fake.recognisesArgsForCall)]
+	fake.recognisesArgsForCall = append(fake.recognisesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RecognisesStub
+	fakeReturns := fake.recognisesReturns
+	fake.recordInvocation("Recognises", []interface{}{arg1})
+	fake.recognisesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Authenticator) RecognisesCallCount() int {
+	fake.recognisesMutex.RLock()
+	defer fake.recognisesMutex.RUnlock()
+	return len(fake.recognisesArgsForCall)
+}
+
+func (fake *Authenticator) RecognisesCalls(stub func(string) bool) {
+	fake.recognisesMutex.Lock()
+	defer fake.recognisesMutex.Unlock()
+	fake.RecognisesStub = stub
+}
+
+func (fake *Authenticator) RecognisesArgsForCall(i int) string {
+	fake.recognisesMutex.RLock()
+	defer fake.recognisesMutex.RUnlock()
+	argsForCall := fake.recognisesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Authenticator) RecognisesReturns(result1 bool) {
+	fake.recognisesMutex.Lock()
+	defer fake.recognisesMutex.Unlock()
+	fake.RecognisesStub = nil
+	fake.recognisesReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *Authenticator) RecognisesReturnsOnCall(i int, result1 bool) {
+	fake.recognisesMutex.Lock()
+	defer fake.recognisesMutex.Unlock()
+	fake.RecognisesStub = nil
+	if fake.recognisesReturnsOnCall == nil {
+		fake.recognisesReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.recognisesReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *Authenticator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	fake.recognisesMutex.RLock()
+	defer fake.recognisesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Authenticator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ oauth.Authenticator = new(Authenticator)
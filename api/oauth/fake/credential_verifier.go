@@ -0,0 +1,285 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+)
+
+type CredentialVerifier struct {
+	VerifyClientCredentialsStub        func(context.Context, string, string) (oauth.Principal, error)
+	verifyClientCredentialsMutex       sync.RWMutex
+	verifyClientCredentialsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	verifyClientCredentialsReturns struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	verifyClientCredentialsReturnsOnCall map[int]struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	VerifyPasswordStub        func(context.Context, string, string) (oauth.Principal, error)
+	verifyPasswordMutex       sync.RWMutex
+	verifyPasswordArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	verifyPasswordReturns struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	verifyPasswordReturnsOnCall map[int]struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	VerifyRefreshStub        func(context.Context, oauth.Principal) (oauth.Principal, error)
+	verifyRefreshMutex       sync.RWMutex
+	verifyRefreshArgsForCall []struct {
+		arg1 context.Context
+		arg2 oauth.Principal
+	}
+	verifyRefreshReturns struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	verifyRefreshReturnsOnCall map[int]struct {
+		result1 oauth.Principal
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentials(arg1 context.Context, arg2 string, arg3 string) (oauth.Principal, error) {
+	fake.verifyClientCredentialsMutex.Lock()
+	ret, specificReturn := fake.verifyClientCredentialsReturnsOnCall[len(fake.verifyClientCredentialsArgsForCall)]
+	fake.verifyClientCredentialsArgsForCall = append(fake.verifyClientCredentialsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.VerifyClientCredentialsStub
+	fakeReturns := fake.verifyClientCredentialsReturns
+	fake.recordInvocation("VerifyClientCredentials", []interface{}{arg1, arg2, arg3})
+	fake.verifyClientCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentialsCallCount() int {
+	fake.verifyClientCredentialsMutex.RLock()
+	defer fake.verifyClientCredentialsMutex.RUnlock()
+	return len(fake.verifyClientCredentialsArgsForCall)
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentialsCalls(stub func(context.Context, string, string) (oauth.Principal, error)) {
+	fake.verifyClientCredentialsMutex.Lock()
+	defer fake.verifyClientCredentialsMutex.Unlock()
+	fake.VerifyClientCredentialsStub = stub
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentialsArgsForCall(i int) (context.Context, string, string) {
+	fake.verifyClientCredentialsMutex.RLock()
+	defer fake.verifyClientCredentialsMutex.RUnlock()
+	argsForCall := fake.verifyClientCredentialsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentialsReturns(result1 oauth.Principal, result2 error) {
+	fake.verifyClientCredentialsMutex.Lock()
+	defer fake.verifyClientCredentialsMutex.Unlock()
+	fake.VerifyClientCredentialsStub = nil
+	fake.verifyClientCredentialsReturns = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) VerifyClientCredentialsReturnsOnCall(i int, result1 oauth.Principal, result2 error) {
+	fake.verifyClientCredentialsMutex.Lock()
+	defer fake.verifyClientCredentialsMutex.Unlock()
+	fake.VerifyClientCredentialsStub = nil
+	if fake.verifyClientCredentialsReturnsOnCall == nil {
+		fake.verifyClientCredentialsReturnsOnCall = make(map[int]struct {
+			result1 oauth.Principal
+			result2 error
+		})
+	}
+	fake.verifyClientCredentialsReturnsOnCall[i] = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) VerifyPassword(arg1 context.Context, arg2 string, arg3 string) (oauth.Principal, error) {
+	fake.verifyPasswordMutex.Lock()
+	ret, specificReturn := fake.verifyPasswordReturnsOnCall[len(fake.verifyPasswordArgsForCall)]
+	fake.verifyPasswordArgsForCall = append(fake.verifyPasswordArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.VerifyPasswordStub
+	fakeReturns := fake.verifyPasswordReturns
+	fake.recordInvocation("VerifyPassword", []interface{}{arg1, arg2, arg3})
+	fake.verifyPasswordMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CredentialVerifier) VerifyPasswordCallCount() int {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return len(fake.verifyPasswordArgsForCall)
+}
+
+func (fake *CredentialVerifier) VerifyPasswordCalls(stub func(context.Context, string, string) (oauth.Principal, error)) {
+	fake.verifyPasswordMutex.Lock()
+	defer fake.verifyPasswordMutex.Unlock()
+	fake.VerifyPasswordStub = stub
+}
+
+func (fake *CredentialVerifier) VerifyPasswordArgsForCall(i int) (context.Context, string, string) {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	argsForCall := fake.verifyPasswordArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CredentialVerifier) VerifyPasswordReturns(result1 oauth.Principal, result2 error) {
+	fake.verifyPasswordMutex.Lock()
+	defer fake.verifyPasswordMutex.Unlock()
+	fake.VerifyPasswordStub = nil
+	fake.verifyPasswordReturns = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) VerifyPasswordReturnsOnCall(i int, result1 oauth.Principal, result2 error) {
+	fake.verifyPasswordMutex.Lock()
+	defer fake.verifyPasswordMutex.Unlock()
+	fake.VerifyPasswordStub = nil
+	if fake.verifyPasswordReturnsOnCall == nil {
+		fake.verifyPasswordReturnsOnCall = make(map[int]struct {
+			result1 oauth.Principal
+			result2 error
+		})
+	}
+	fake.verifyPasswordReturnsOnCall[i] = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) VerifyRefresh(arg1 context.Context, arg2 oauth.Principal) (oauth.Principal, error) {
+	fake.verifyRefreshMutex.Lock()
+	ret, specificReturn := fake.verifyRefreshReturnsOnCall[len(fake.verifyRefreshArgsForCall)]
+	fake.verifyRefreshArgsForCall = append(fake.verifyRefreshArgsForCall, struct {
+		arg1 context.Context
+		arg2 oauth.Principal
+	}{arg1, arg2})
+	stub := fake.VerifyRefreshStub
+	fakeReturns := fake.verifyRefreshReturns
+	fake.recordInvocation("VerifyRefresh", []interface{}{arg1, arg2})
+	fake.verifyRefreshMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CredentialVerifier) VerifyRefreshCallCount() int {
+	fake.verifyRefreshMutex.RLock()
+	defer fake.verifyRefreshMutex.RUnlock()
+	return len(fake.verifyRefreshArgsForCall)
+}
+
+func (fake *CredentialVerifier) VerifyRefreshCalls(stub func(context.Context, oauth.Principal) (oauth.Principal, error)) {
+	fake.verifyRefreshMutex.Lock()
+	defer fake.verifyRefreshMutex.Unlock()
+	fake.VerifyRefreshStub = stub
+}
+
+func (fake *CredentialVerifier) VerifyRefreshArgsForCall(i int) (context.Context, oauth.Principal) {
+	fake.verifyRefreshMutex.RLock()
+	defer fake.verifyRefreshMutex.RUnlock()
+	argsForCall := fake.verifyRefreshArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CredentialVerifier) VerifyRefreshReturns(result1 oauth.Principal, result2 error) {
+	fake.verifyRefreshMutex.Lock()
+	defer fake.verifyRefreshMutex.Unlock()
+	fake.VerifyRefreshStub = nil
+	fake.verifyRefreshReturns = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) VerifyRefreshReturnsOnCall(i int, result1 oauth.Principal, result2 error) {
+	fake.verifyRefreshMutex.Lock()
+	defer fake.verifyRefreshMutex.Unlock()
+	fake.VerifyRefreshStub = nil
+	if fake.verifyRefreshReturnsOnCall == nil {
+		fake.verifyRefreshReturnsOnCall = make(map[int]struct {
+			result1 oauth.Principal
+			result2 error
+		})
+	}
+	fake.verifyRefreshReturnsOnCall[i] = struct {
+		result1 oauth.Principal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CredentialVerifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.verifyClientCredentialsMutex.RLock()
+	defer fake.verifyClientCredentialsMutex.RUnlock()
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	fake.verifyRefreshMutex.RLock()
+	defer fake.verifyRefreshMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CredentialVerifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ oauth.CredentialVerifier = new(CredentialVerifier)
@@ -0,0 +1,198 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+)
+
+type KeyStore struct {
+	KeyStub        func(context.Context, string) (oauth.SigningKey, error)
+	keyMutex       sync.RWMutex
+	keyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	keyReturns struct {
+		result1 oauth.SigningKey
+		result2 error
+	}
+	keyReturnsOnCall map[int]struct {
+		result1 oauth.SigningKey
+		result2 error
+	}
+	SigningKeyStub        func(context.Context) (oauth.SigningKey, error)
+	signingKeyMutex       sync.RWMutex
+	signingKeyArgsForCall []struct {
+		arg1 context.Context
+	}
+	signingKeyReturns struct {
+		result1 oauth.SigningKey
+		result2 error
+	}
+	signingKeyReturnsOnCall map[int]struct {
+		result1 oauth.SigningKey
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *KeyStore) Key(arg1 context.Context, arg2 string) (oauth.SigningKey, error) {
+	fake.keyMutex.Lock()
+	ret, specificReturn := fake.keyReturnsOnCall[len(fake.keyArgsForCall)]
+	fake.keyArgsForCall = append(fake.keyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.KeyStub
+	fakeReturns := fake.keyReturns
+	fake.recordInvocation("Key", []interface{}{arg1, arg2})
+	fake.keyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *KeyStore) KeyCallCount() int {
+	fake.keyMutex.RLock()
+	defer fake.keyMutex.RUnlock()
+	return len(fake.keyArgsForCall)
+}
+
+func (fake *KeyStore) KeyCalls(stub func(context.Context, string) (oauth.SigningKey, error)) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = stub
+}
+
+func (fake *KeyStore) KeyArgsForCall(i int) (context.Context, string) {
+	fake.keyMutex.RLock()
+	defer fake.keyMutex.RUnlock()
+	argsForCall := fake.keyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *KeyStore) KeyReturns(result1 oauth.SigningKey, result2 error) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = nil
+	fake.keyReturns = struct {
+		result1 oauth.SigningKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyStore) KeyReturnsOnCall(i int, result1 oauth.SigningKey, result2 error) {
+	fake.keyMutex.Lock()
+	defer fake.keyMutex.Unlock()
+	fake.KeyStub = nil
+	if fake.keyReturnsOnCall == nil {
+		fake.keyReturnsOnCall = make(map[int]struct {
+			result1 oauth.SigningKey
+			result2 error
+		})
+	}
+	fake.keyReturnsOnCall[i] = struct {
+		result1 oauth.SigningKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyStore) SigningKey(arg1 context.Context) (oauth.SigningKey, error) {
+	fake.signingKeyMutex.Lock()
+	ret, specificReturn := fake.signingKeyReturnsOnCall[len(fake.signingKeyArgsForCall)]
+	fake.signingKeyArgsForCall = append(fake.signingKeyArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SigningKeyStub
+	fakeReturns := fake.signingKeyReturns
+	fake.recordInvocation("SigningKey", []interface{}{arg1})
+	fake.signingKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *KeyStore) SigningKeyCallCount() int {
+	fake.signingKeyMutex.RLock()
+	defer fake.signingKeyMutex.RUnlock()
+	return len(fake.signingKeyArgsForCall)
+}
+
+func (fake *KeyStore) SigningKeyCalls(stub func(context.Context) (oauth.SigningKey, error)) {
+	fake.signingKeyMutex.Lock()
+	defer fake.signingKeyMutex.Unlock()
+	fake.SigningKeyStub = stub
+}
+
+func (fake *KeyStore) SigningKeyArgsForCall(i int) context.Context {
+	fake.signingKeyMutex.RLock()
+	defer fake.signingKeyMutex.RUnlock()
+	argsForCall := fake.signingKeyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *KeyStore) SigningKeyReturns(result1 oauth.SigningKey, result2 error) {
+	fake.signingKeyMutex.Lock()
+	defer fake.signingKeyMutex.Unlock()
+	fake.SigningKeyStub = nil
+	fake.signingKeyReturns = struct {
+		result1 oauth.SigningKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyStore) SigningKeyReturnsOnCall(i int, result1 oauth.SigningKey, result2 error) {
+	fake.signingKeyMutex.Lock()
+	defer fake.signingKeyMutex.Unlock()
+	fake.SigningKeyStub = nil
+	if fake.signingKeyReturnsOnCall == nil {
+		fake.signingKeyReturnsOnCall = make(map[int]struct {
+			result1 oauth.SigningKey
+			result2 error
+		})
+	}
+	fake.signingKeyReturnsOnCall[i] = struct {
+		result1 oauth.SigningKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *KeyStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.keyMutex.RLock()
+	defer fake.keyMutex.RUnlock()
+	fake.signingKeyMutex.RLock()
+	defer fake.signingKeyMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *KeyStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ oauth.KeyStore = new(KeyStore)
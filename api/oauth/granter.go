@@ -0,0 +1,106 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	PasswordGrantType          = "password"
+	RefreshTokenGrantType      = "refresh_token"
+	ClientCredentialsGrantType = "client_credentials"
+)
+
+//counterfeiter:generate -o fake -fake-name CredentialVerifier . CredentialVerifier
+
+// CredentialVerifier authenticates users and clients against an identity backend. Rejected credentials are reported
+// with an unauthorized Error. VerifyRefresh checks that the principal of a refresh token still exists and is allowed
+// to log in, and returns it as currently known to the backend.
+type CredentialVerifier interface {
+	VerifyPassword(ctx context.Context, username, password string) (Principal, error)
+	VerifyClientCredentials(ctx context.Context, clientID, clientSecret string) (Principal, error)
+	VerifyRefresh(ctx context.Context, principal Principal) (Principal, error)
+}
+
+type GrantRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+	RefreshToken string
+}
+
+// TokenGranter implements the grants of the token endpoint
+type TokenGranter struct {
+	credentialVerifier CredentialVerifier
+	tokenIssuer        *TokenIssuer
+}
+
+func NewTokenGranter(credentialVerifier CredentialVerifier, tokenIssuer *TokenIssuer) *TokenGranter {
+	return &TokenGranter{
+		credentialVerifier: credentialVerifier,
+		tokenIssuer:        tokenIssuer,
+	}
+}
+
+func (g *TokenGranter) Grant(ctx context.Context, request GrantRequest) (Token, error) {
+	var (
+		principal Principal
+		err       error
+	)
+
+	switch request.GrantType {
+	case PasswordGrantType:
+		if request.Username == "" || request.Password == "" {
+			return Token{}, NewInvalidRequestError("username and password are required")
+		}
+		principal, err = g.credentialVerifier.VerifyPassword(ctx, request.Username, request.Password)
+
+	case ClientCredentialsGrantType:
+		if request.ClientID == "" || request.ClientSecret == "" {
+			return Token{}, NewInvalidRequestError("client_id and client_secret are required")
+		}
+		principal, err = g.credentialVerifier.VerifyClientCredentials(ctx, request.ClientID, request.ClientSecret)
+
+	case RefreshTokenGrantType:
+		if request.RefreshToken == "" {
+			return Token{}, NewInvalidRequestError("refresh_token is required")
+		}
+		principal, err = g.refreshPrincipal(ctx, request)
+
+	case "":
+		return Token{}, NewInvalidRequestError("grant_type is required")
+
+	default:
+		return Token{}, NewUnsupportedGrantTypeError(request.GrantType)
+	}
+
+	if err != nil {
+		return Token{}, err
+	}
+
+	return g.tokenIssuer.Issue(ctx, principal, request.ClientID, request.GrantType)
+}
+
+func (g *TokenGranter) refreshPrincipal(ctx context.Context, request GrantRequest) (Principal, error) {
+	claims, err := g.tokenIssuer.Verify(ctx, request.RefreshToken, RefreshTokenUse)
+	if err != nil {
+		return Principal{}, NewInvalidTokenError(err)
+	}
+
+	if claims.ClientID != request.ClientID {
+		return Principal{}, NewInvalidTokenError(errors.New("the refresh token was issued to another client"))
+	}
+
+	principal, err := g.credentialVerifier.VerifyRefresh(ctx, claims.Principal())
+	if err != nil {
+		var oauthErr Error
+		if errors.As(err, &oauthErr) {
+			return Principal{}, NewInvalidTokenError(err)
+		}
+		return Principal{}, err
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,237 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+	"code.cloudfoundry.org/korifi/api/oauth/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenGranter", func() {
+	var (
+		ctx                context.Context
+		credentialVerifier *fake.CredentialVerifier
+		tokenIssuer        *oauth.TokenIssuer
+		granter            *oauth.TokenGranter
+		request            oauth.GrantRequest
+		token              oauth.Token
+		grantErr           error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		signingKey := generateRSAKey()
+		keyStore := new(fake.KeyStore)
+		keyStore.SigningKeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: signingKey}, nil)
+		keyStore.KeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: signingKey}, nil)
+		tokenIssuer = oauth.NewTokenIssuer(keyStore, "https://api.example.com/oauth/token", time.Hour, time.Hour)
+
+		credentialVerifier = new(fake.CredentialVerifier)
+		credentialVerifier.VerifyPasswordReturns(oauth.Principal{Subject: "oidc:alice", UserName: "alice"}, nil)
+		credentialVerifier.VerifyClientCredentialsReturns(oauth.Principal{Subject: "oidc:my-client", UserName: "my-client"}, nil)
+
+		granter = oauth.NewTokenGranter(credentialVerifier, tokenIssuer)
+	})
+
+	JustBeforeEach(func() {
+		token, grantErr = granter.Grant(ctx, request)
+	})
+
+	expectOAuthError := func(code string) {
+		var oauthErr oauth.Error
+		ExpectWithOffset(1, errors.As(grantErr, &oauthErr)).To(BeTrue())
+		ExpectWithOffset(1, oauthErr.Code()).To(Equal(code))
+	}
+
+	Describe("password grant", func() {
+		BeforeEach(func() {
+			request = oauth.GrantRequest{
+				GrantType: oauth.PasswordGrantType,
+				ClientID:  "cf",
+				Username:  "alice",
+				Password:  "secret",
+			}
+		})
+
+		It("verifies the password", func() {
+			Expect(credentialVerifier.VerifyPasswordCallCount()).To(Equal(1))
+			_, actualUsername, actualPassword := credentialVerifier.VerifyPasswordArgsForCall(0)
+			Expect(actualUsername).To(Equal("alice"))
+			Expect(actualPassword).To(Equal("secret"))
+		})
+
+		It("issues a token for the user", func() {
+			Expect(grantErr).NotTo(HaveOccurred())
+			claims, err := tokenIssuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Subject).To(Equal("oidc:alice"))
+			Expect(claims.UserName).To(Equal("alice"))
+			Expect(claims.ClientID).To(Equal("cf"))
+			Expect(token.RefreshToken).NotTo(BeEmpty())
+		})
+
+		When("the password is missing", func() {
+			BeforeEach(func() {
+				request.Password = ""
+			})
+
+			It("returns an invalid request error", func() {
+				expectOAuthError(oauth.InvalidRequestErrorCode)
+				Expect(credentialVerifier.VerifyPasswordCallCount()).To(BeZero())
+			})
+		})
+
+		When("the credentials are rejected", func() {
+			BeforeEach(func() {
+				credentialVerifier.VerifyPasswordReturns(oauth.Principal{}, oauth.NewUnauthorizedError(errors.New("nope")))
+			})
+
+			It("returns the error", func() {
+				expectOAuthError(oauth.UnauthorizedErrorCode)
+			})
+		})
+	})
+
+	Describe("client credentials grant", func() {
+		BeforeEach(func() {
+			request = oauth.GrantRequest{
+				GrantType:    oauth.ClientCredentialsGrantType,
+				ClientID:     "my-client",
+				ClientSecret: "my-secret",
+			}
+		})
+
+		It("verifies the client credentials", func() {
+			Expect(credentialVerifier.VerifyClientCredentialsCallCount()).To(Equal(1))
+			_, actualClientID, actualClientSecret := credentialVerifier.VerifyClientCredentialsArgsForCall(0)
+			Expect(actualClientID).To(Equal("my-client"))
+			Expect(actualClientSecret).To(Equal("my-secret"))
+		})
+
+		It("issues an access token for the client", func() {
+			Expect(grantErr).NotTo(HaveOccurred())
+			claims, err := tokenIssuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Subject).To(Equal("oidc:my-client"))
+			Expect(token.RefreshToken).To(BeEmpty())
+		})
+
+		When("the client secret is missing", func() {
+			BeforeEach(func() {
+				request.ClientSecret = ""
+			})
+
+			It("returns an invalid request error", func() {
+				expectOAuthError(oauth.InvalidRequestErrorCode)
+			})
+		})
+	})
+
+	Describe("refresh token grant", func() {
+		var refreshToken string
+
+		BeforeEach(func() {
+			initialToken, err := tokenIssuer.Issue(ctx, oauth.Principal{Subject: "oidc:alice", UserName: "alice", UpstreamRefreshToken: "upstream-token"}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+			refreshToken = initialToken.RefreshToken
+
+			credentialVerifier.VerifyRefreshReturns(oauth.Principal{Subject: "oidc:alice", UserName: "alice", Groups: []string{"oidc:admins"}}, nil)
+
+			request = oauth.GrantRequest{
+				GrantType:    oauth.RefreshTokenGrantType,
+				ClientID:     "cf",
+				RefreshToken: refreshToken,
+			}
+		})
+
+		It("issues a new token for the subject of the refresh token", func() {
+			Expect(grantErr).NotTo(HaveOccurred())
+			claims, err := tokenIssuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Subject).To(Equal("oidc:alice"))
+			Expect(claims.UserName).To(Equal("alice"))
+			Expect(credentialVerifier.VerifyPasswordCallCount()).To(BeZero())
+		})
+
+		It("verifies the principal of the refresh token again", func() {
+			Expect(credentialVerifier.VerifyRefreshCallCount()).To(Equal(1))
+			_, principal := credentialVerifier.VerifyRefreshArgsForCall(0)
+			Expect(principal).To(Equal(oauth.Principal{Subject: "oidc:alice", UserName: "alice", UpstreamRefreshToken: "upstream-token"}))
+		})
+
+		It("issues the token with the current groups of the principal", func() {
+			Expect(grantErr).NotTo(HaveOccurred())
+			claims, err := tokenIssuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Groups).To(ConsistOf("oidc:admins"))
+		})
+
+		When("the principal is no longer allowed", func() {
+			BeforeEach(func() {
+				credentialVerifier.VerifyRefreshReturns(oauth.Principal{}, oauth.NewUnauthorizedError(errors.New("user disabled")))
+			})
+
+			It("returns an invalid token error", func() {
+				expectOAuthError(oauth.InvalidTokenErrorCode)
+			})
+		})
+
+		When("verifying the principal fails", func() {
+			BeforeEach(func() {
+				credentialVerifier.VerifyRefreshReturns(oauth.Principal{}, errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(grantErr).To(MatchError("boom"))
+			})
+		})
+
+		When("the refresh token was issued to another client", func() {
+			BeforeEach(func() {
+				request.ClientID = "other-client"
+			})
+
+			It("returns an invalid token error", func() {
+				expectOAuthError(oauth.InvalidTokenErrorCode)
+			})
+		})
+
+		When("an access token is used as refresh token", func() {
+			BeforeEach(func() {
+				initialToken, err := tokenIssuer.Issue(ctx, oauth.Principal{Subject: "oidc:alice"}, "cf", oauth.PasswordGrantType)
+				Expect(err).NotTo(HaveOccurred())
+				request.RefreshToken = initialToken.AccessToken
+			})
+
+			It("returns an invalid token error", func() {
+				expectOAuthError(oauth.InvalidTokenErrorCode)
+			})
+		})
+	})
+
+	When("the grant type is missing", func() {
+		BeforeEach(func() {
+			request = oauth.GrantRequest{}
+		})
+
+		It("returns an invalid request error", func() {
+			expectOAuthError(oauth.InvalidRequestErrorCode)
+		})
+	})
+
+	When("the grant type is not supported", func() {
+		BeforeEach(func() {
+			request = oauth.GrantRequest{GrantType: "implicit"}
+		})
+
+		It("returns an unsupported grant type error", func() {
+			expectOAuthError(oauth.UnsupportedGrantTypeErrorCode)
+		})
+	})
+})
@@ -0,0 +1,305 @@
+package oauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"gopkg.in/square/go-jose.v2"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const (
+	AccessTokenUse  = "access"
+	RefreshTokenUse = "refresh"
+
+	serviceAccountUserPrefix = "system:serviceaccount:"
+
+	upstreamTokenKeyLabel = "korifi upstream refresh token"
+)
+
+var defaultScopes = []string{"openid", "cloud_controller.read", "cloud_controller.write"}
+
+// Principal is an authenticated user, both as Kubernetes RBAC and as CF clients know it
+type Principal struct {
+	// Subject is the Kubernetes user name that RBAC rules apply to
+	Subject string
+	// UserName is the name presented to CF clients
+	UserName string
+	// Groups are the Kubernetes groups the subject is a member of
+	Groups []string
+	// UpstreamRefreshToken is the refresh token of the identity backend, or the token of a service account, with which
+	// the principal is verified again when its token is refreshed. It is only kept in refresh tokens, encrypted so that only the API can read it.
+	UpstreamRefreshToken string
+}
+
+// IdentityFromPrincipal maps a principal to the identity that the API authorizes
+func IdentityFromPrincipal(principal Principal) authorization.Identity {
+	if strings.HasPrefix(principal.Subject, serviceAccountUserPrefix) {
+		nameSegments := strings.Split(principal.Subject, ":")
+		return authorization.Identity{
+			Name:   nameSegments[len(nameSegments)-1],
+			Kind:   rbacv1.ServiceAccountKind,
+			Groups: principal.Groups,
+		}
+	}
+
+	return authorization.Identity{Name: principal.Subject, Kind: rbacv1.UserKind, Groups: principal.Groups}
+}
+
+//counterfeiter:generate -o fake -fake-name Authenticator . Authenticator
+
+// Authenticator authenticates bearer tokens that the Kubernetes API server cannot authenticate on its own
+type Authenticator interface {
+	// Recognises tells whether the token is meant for the authenticator, without verifying it
+	Recognises(token string) bool
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+type Claims struct {
+	jwt.StandardClaims
+	UserName  string   `json:"user_name"`
+	Groups    []string `json:"groups,omitempty"`
+	ClientID  string   `json:"client_id"`
+	GrantType string   `json:"grant_type"`
+	Scope     []string `json:"scope"`
+	TokenUse  string   `json:"token_use"`
+
+	// EncryptedUpstreamRefreshToken is the upstream refresh token of the principal as a JWE that only the API can
+	// decrypt, and UpstreamRefreshToken its plain text once the token has been verified
+	EncryptedUpstreamRefreshToken string `json:"upstream_refresh_token,omitempty"`
+	UpstreamRefreshToken          string `json:"-"`
+}
+
+func (c Claims) Principal() Principal {
+	return Principal{Subject: c.Subject, UserName: c.UserName, Groups: c.Groups, UpstreamRefreshToken: c.UpstreamRefreshToken}
+}
+
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+	Scope        []string
+	JTI          string
+}
+
+//counterfeiter:generate -o fake -fake-name KeyStore . KeyStore
+
+type KeyStore interface {
+	SigningKey(ctx context.Context) (SigningKey, error)
+	Key(ctx context.Context, keyID string) (SigningKey, error)
+}
+
+// TokenIssuer issues and verifies the RS256 signed access and refresh tokens of the API
+type TokenIssuer struct {
+	keyStore        KeyStore
+	issuerURL       string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewTokenIssuer(keyStore KeyStore, issuerURL string, accessTokenTTL, refreshTokenTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		keyStore:        keyStore,
+		issuerURL:       issuerURL,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+func (i *TokenIssuer) Issue(ctx context.Context, principal Principal, clientID, grantType string) (Token, error) {
+	key, err := i.keyStore.SigningKey(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+
+	now := time.Now()
+	jti := uuid.NewString()
+
+	accessToken, err := i.sign(key, Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			Issuer:    i.issuerURL,
+			Subject:   principal.Subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(i.accessTokenTTL).Unix(),
+		},
+		UserName:  principal.UserName,
+		Groups:    principal.Groups,
+		ClientID:  clientID,
+		GrantType: grantType,
+		Scope:     defaultScopes,
+		TokenUse:  AccessTokenUse,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	token := Token{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(i.accessTokenTTL.Seconds()),
+		Scope:       defaultScopes,
+		JTI:         jti,
+	}
+
+	// clients can simply authenticate again instead of refreshing their token
+	if grantType == ClientCredentialsGrantType {
+		return token, nil
+	}
+
+	encryptedUpstreamToken, err := encryptUpstreamToken(key, principal.UpstreamRefreshToken)
+	if err != nil {
+		return Token{}, err
+	}
+
+	token.RefreshToken, err = i.sign(key, Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti + "-r",
+			Issuer:    i.issuerURL,
+			Subject:   principal.Subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(i.refreshTokenTTL).Unix(),
+		},
+		UserName:  principal.UserName,
+		Groups:    principal.Groups,
+		ClientID:  clientID,
+		GrantType: grantType,
+		Scope:     defaultScopes,
+		TokenUse:  RefreshTokenUse,
+
+		EncryptedUpstreamRefreshToken: encryptedUpstreamToken,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	return token, nil
+}
+
+func (i *TokenIssuer) sign(key SigningKey, claims Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.ID
+
+	signedToken, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signedToken, nil
+}
+
+// Recognises tells whether the token claims to have been issued by the API, without verifying it
+func (i *TokenIssuer) Recognises(tokenString string) bool {
+	claims := new(Claims)
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+
+	return claims.Issuer == i.issuerURL
+}
+
+// Authenticate verifies an access token issued by the API and returns its subject
+func (i *TokenIssuer) Authenticate(ctx context.Context, tokenString string) (Principal, error) {
+	claims, err := i.Verify(ctx, tokenString, AccessTokenUse)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return claims.Principal(), nil
+}
+
+// Verify checks the signature, issuer, expiry and use of a token issued by the API and returns its claims
+func (i *TokenIssuer) Verify(ctx context.Context, tokenString, tokenUse string) (Claims, error) {
+	claims := new(Claims)
+	var key SigningKey
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Header["alg"])
+		}
+
+		keyID, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("token has no key id")
+		}
+
+		var err error
+		key, err = i.keyStore.Key(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &key.PrivateKey.PublicKey, nil
+	})
+	if err != nil {
+		return Claims{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if claims.Issuer != i.issuerURL {
+		return Claims{}, fmt.Errorf("invalid token: unexpected issuer %q", claims.Issuer)
+	}
+
+	if claims.TokenUse != tokenUse {
+		return Claims{}, fmt.Errorf("invalid token: expected a %q token, got %q", tokenUse, claims.TokenUse)
+	}
+
+	claims.UpstreamRefreshToken, err = decryptUpstreamToken(key, claims.EncryptedUpstreamRefreshToken)
+	if err != nil {
+		return Claims{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return *claims, nil
+}
+
+// encryptUpstreamToken encrypts the upstream refresh token with a key derived from the key the refresh token is signed
+// with, so that clients holding the refresh token cannot use the upstream token against the identity backend directly
+func encryptUpstreamToken(key SigningKey, upstreamToken string) (string, error) {
+	if upstreamToken == "" {
+		return "", nil
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: jose.DIRECT, Key: upstreamTokenKey(key)}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upstream token encrypter: %w", err)
+	}
+
+	encrypted, err := encrypter.Encrypt([]byte(upstreamToken))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt upstream token: %w", err)
+	}
+
+	return encrypted.CompactSerialize()
+}
+
+func decryptUpstreamToken(key SigningKey, encryptedUpstreamToken string) (string, error) {
+	if encryptedUpstreamToken == "" {
+		return "", nil
+	}
+
+	encrypted, err := jose.ParseEncrypted(encryptedUpstreamToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse upstream token: %w", err)
+	}
+
+	upstreamToken, err := encrypted.Decrypt(upstreamTokenKey(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt upstream token: %w", err)
+	}
+
+	return string(upstreamToken), nil
+}
+
+func upstreamTokenKey(key SigningKey) []byte {
+	mac := hmac.New(sha256.New, x509.MarshalPKCS1PrivateKey(key.PrivateKey))
+	mac.Write([]byte(upstreamTokenKeyLabel))
+
+	return mac.Sum(nil)
+}
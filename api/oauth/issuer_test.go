@@ -0,0 +1,271 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/api/oauth"
+	"code.cloudfoundry.org/korifi/api/oauth/fake"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("TokenIssuer", func() {
+	const issuerURL = "https://api.example.com/oauth/token"
+
+	var (
+		ctx        context.Context
+		keyStore   *fake.KeyStore
+		signingKey *rsa.PrivateKey
+		issuer     *oauth.TokenIssuer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		signingKey = generateRSAKey()
+
+		keyStore = new(fake.KeyStore)
+		keyStore.SigningKeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: signingKey, CreatedAt: time.Now()}, nil)
+		keyStore.KeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: signingKey}, nil)
+
+		issuer = oauth.NewTokenIssuer(keyStore, issuerURL, time.Hour, 24*time.Hour)
+	})
+
+	Describe("Issue", func() {
+		var (
+			grantType string
+			token     oauth.Token
+			issueErr  error
+		)
+
+		BeforeEach(func() {
+			grantType = oauth.PasswordGrantType
+		})
+
+		JustBeforeEach(func() {
+			token, issueErr = issuer.Issue(ctx, oauth.Principal{
+				Subject:              "oidc:alice",
+				UserName:             "alice",
+				Groups:               []string{"oidc:team-a"},
+				UpstreamRefreshToken: "upstream-token",
+			}, "cf", grantType)
+		})
+
+		It("issues an access token for the principal", func() {
+			Expect(issueErr).NotTo(HaveOccurred())
+			Expect(token.ExpiresIn).To(BeEquivalentTo(3600))
+			Expect(token.Scope).To(ConsistOf("openid", "cloud_controller.read", "cloud_controller.write"))
+			Expect(token.JTI).NotTo(BeEmpty())
+
+			claims, err := issuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Id).To(Equal(token.JTI))
+			Expect(claims.Issuer).To(Equal(issuerURL))
+			Expect(claims.Subject).To(Equal("oidc:alice"))
+			Expect(claims.UserName).To(Equal("alice"))
+			Expect(claims.Groups).To(ConsistOf("oidc:team-a"))
+			Expect(claims.ClientID).To(Equal("cf"))
+			Expect(claims.GrantType).To(Equal(oauth.PasswordGrantType))
+			Expect(claims.ExpiresAt).To(BeNumerically("~", time.Now().Add(time.Hour).Unix(), 5))
+		})
+
+		It("signs the tokens with the current signing key", func() {
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token.AccessToken, jwt.MapClaims{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Header).To(HaveKeyWithValue("alg", "RS256"))
+			Expect(parsed.Header).To(HaveKeyWithValue("kid", "key-1"))
+		})
+
+		It("issues a refresh token", func() {
+			claims, err := issuer.Verify(ctx, token.RefreshToken, oauth.RefreshTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims.Subject).To(Equal("oidc:alice"))
+			Expect(claims.Groups).To(ConsistOf("oidc:team-a"))
+			Expect(claims.ClientID).To(Equal("cf"))
+			Expect(claims.ExpiresAt).To(BeNumerically("~", time.Now().Add(24*time.Hour).Unix(), 5))
+			Expect(claims.UpstreamRefreshToken).To(Equal("upstream-token"))
+		})
+
+		It("encrypts the upstream refresh token in the refresh token", func() {
+			claims := jwt.MapClaims{}
+			_, _, err := new(jwt.Parser).ParseUnverified(token.RefreshToken, claims)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims).To(HaveKey("upstream_refresh_token"))
+			Expect(claims["upstream_refresh_token"]).NotTo(ContainSubstring("upstream-token"))
+			Expect(token.RefreshToken).NotTo(ContainSubstring(base64.RawURLEncoding.EncodeToString([]byte("upstream-token"))))
+		})
+
+		It("does not put the upstream refresh token in the access token", func() {
+			claims := jwt.MapClaims{}
+			_, _, err := new(jwt.Parser).ParseUnverified(token.AccessToken, claims)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims).NotTo(HaveKey("upstream_refresh_token"))
+		})
+
+		When("the grant is client credentials", func() {
+			BeforeEach(func() {
+				grantType = oauth.ClientCredentialsGrantType
+			})
+
+			It("does not issue a refresh token", func() {
+				Expect(issueErr).NotTo(HaveOccurred())
+				Expect(token.AccessToken).NotTo(BeEmpty())
+				Expect(token.RefreshToken).To(BeEmpty())
+			})
+		})
+
+		When("getting the signing key fails", func() {
+			BeforeEach(func() {
+				keyStore.SigningKeyReturns(oauth.SigningKey{}, errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(issueErr).To(MatchError("boom"))
+			})
+		})
+	})
+
+	Describe("Verify", func() {
+		var token oauth.Token
+
+		BeforeEach(func() {
+			var err error
+			token, err = issuer.Issue(ctx, oauth.Principal{Subject: "alice", UserName: "alice"}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("looks up the verification key by the key ID", func() {
+			_, err := issuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyStore.KeyCallCount()).To(Equal(1))
+			_, actualKeyID := keyStore.KeyArgsForCall(0)
+			Expect(actualKeyID).To(Equal("key-1"))
+		})
+
+		It("rejects tokens used for the wrong purpose", func() {
+			_, err := issuer.Verify(ctx, token.RefreshToken, oauth.AccessTokenUse)
+			Expect(err).To(MatchError(ContainSubstring(`expected a "access" token`)))
+		})
+
+		It("rejects tokens signed with another key", func() {
+			keyStore.KeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: generateRSAKey()}, nil)
+			_, err := issuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects tokens of other issuers", func() {
+			otherIssuer := oauth.NewTokenIssuer(keyStore, "https://elsewhere.example.com", time.Hour, time.Hour)
+			_, err := otherIssuer.Verify(ctx, token.AccessToken, oauth.AccessTokenUse)
+			Expect(err).To(MatchError(ContainSubstring("unexpected issuer")))
+		})
+
+		It("rejects expired tokens", func() {
+			expiringIssuer := oauth.NewTokenIssuer(keyStore, issuerURL, -time.Minute, time.Hour)
+			expiredToken, err := expiringIssuer.Issue(ctx, oauth.Principal{Subject: "alice"}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = issuer.Verify(ctx, expiredToken.AccessToken, oauth.AccessTokenUse)
+			Expect(err).To(MatchError(ContainSubstring("expired")))
+		})
+
+		It("rejects refresh tokens whose upstream token cannot be decrypted", func() {
+			otherKey := generateRSAKey()
+			otherKeyStore := new(fake.KeyStore)
+			otherKeyStore.SigningKeyReturns(oauth.SigningKey{ID: "key-1", PrivateKey: otherKey}, nil)
+			otherIssuer := oauth.NewTokenIssuer(otherKeyStore, issuerURL, time.Hour, time.Hour)
+			upstreamToken, err := otherIssuer.Issue(ctx, oauth.Principal{Subject: "alice", UpstreamRefreshToken: "upstream-token"}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(upstreamToken.RefreshToken, &oauth.Claims{})
+			Expect(err).NotTo(HaveOccurred())
+			claims := parsed.Claims.(*oauth.Claims)
+			claims.Issuer = issuerURL
+			forged := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			forged.Header["kid"] = "key-1"
+			forgedToken, err := forged.SignedString(signingKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = issuer.Verify(ctx, forgedToken, oauth.RefreshTokenUse)
+			Expect(err).To(MatchError(ContainSubstring("failed to decrypt upstream token")))
+		})
+
+		It("rejects tokens that are not signed with RSA", func() {
+			hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"iss":       issuerURL,
+				"token_use": oauth.AccessTokenUse,
+			}).SignedString([]byte("not-a-real-secret"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = issuer.Verify(ctx, hmacToken, oauth.AccessTokenUse)
+			Expect(err).To(MatchError(ContainSubstring("unexpected signing method")))
+		})
+	})
+
+	Describe("Authenticate", func() {
+		var token oauth.Token
+
+		BeforeEach(func() {
+			var err error
+			token, err = issuer.Issue(ctx, oauth.Principal{Subject: "oidc:alice", UserName: "alice", Groups: []string{"oidc:team-a"}}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the principal of access tokens", func() {
+			principal, err := issuer.Authenticate(ctx, token.AccessToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(principal).To(Equal(oauth.Principal{Subject: "oidc:alice", UserName: "alice", Groups: []string{"oidc:team-a"}}))
+		})
+
+		It("rejects refresh tokens", func() {
+			_, err := issuer.Authenticate(ctx, token.RefreshToken)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Recognises", func() {
+		It("recognises tokens of the issuer", func() {
+			token, err := issuer.Issue(ctx, oauth.Principal{Subject: "alice"}, "cf", oauth.PasswordGrantType)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuer.Recognises(token.AccessToken)).To(BeTrue())
+		})
+
+		It("does not recognise tokens of other issuers", func() {
+			otherToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "kubernetes/serviceaccount"}).
+				SignedString([]byte("secret"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuer.Recognises(otherToken)).To(BeFalse())
+		})
+
+		It("does not recognise opaque tokens", func() {
+			Expect(issuer.Recognises("not-a-jwt")).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("IdentityFromPrincipal", func() {
+	It("maps service account users to service account identities", func() {
+		Expect(oauth.IdentityFromPrincipal(oauth.Principal{Subject: "system:serviceaccount:my-ns:my-sa"})).To(Equal(authorization.Identity{
+			Name: "my-sa",
+			Kind: rbacv1.ServiceAccountKind,
+		}))
+	})
+
+	It("maps any other subject to a user identity with its groups", func() {
+		Expect(oauth.IdentityFromPrincipal(oauth.Principal{
+			Subject:  "oidc:alice",
+			UserName: "alice",
+			Groups:   []string{"oidc:team-a"},
+		})).To(Equal(authorization.Identity{
+			Name:   "oidc:alice",
+			Kind:   rbacv1.UserKind,
+			Groups: []string{"oidc:team-a"},
+		}))
+	})
+})
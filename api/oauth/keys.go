@@ -0,0 +1,294 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/square/go-jose.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update,namespace=ROOT_NAMESPACE
+
+const (
+	SigningKeysSecretName = "korifi-api-token-signing-keys"
+
+	signingKeyBits          = 2048
+	signingKeyPEMType       = "RSA PRIVATE KEY"
+	signingKeyCreatedHeader = "Created-At"
+
+	// keySetRefreshInterval bounds how long a replica keeps signing with a key after another replica rotated it
+	keySetRefreshInterval = time.Minute
+	// unknownKeyRefreshInterval bounds how often tokens with made up key IDs can make a replica read the Secret
+	unknownKeyRefreshInterval = 5 * time.Second
+)
+
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	CreatedAt  time.Time
+}
+
+// SecretKeyStore keeps the keys that tokens are signed with in a Secret, so that all API replicas share them. A new
+// key is generated every rotation interval. Previous keys are kept for verification until every token signed with
+// them has expired.
+type SecretKeyStore struct {
+	privilegedClient client.Client
+	namespace        string
+	rotationInterval time.Duration
+	retention        time.Duration
+
+	mu                    sync.Mutex
+	keys                  []SigningKey
+	refreshedAt           time.Time
+	unknownKeyRefreshedAt time.Time
+}
+
+func NewSecretKeyStore(privilegedClient client.Client, namespace string, rotationInterval, retention time.Duration) *SecretKeyStore {
+	return &SecretKeyStore{
+		privilegedClient: privilegedClient,
+		namespace:        namespace,
+		rotationInterval: rotationInterval,
+		retention:        retention,
+	}
+}
+
+// SigningKey returns the key that new tokens should be signed with, rotating it when it is due
+func (s *SecretKeyStore) SigningKey(ctx context.Context) (SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refreshIfStale(ctx); err != nil {
+		return SigningKey{}, err
+	}
+
+	if s.needsRotation() {
+		if err := s.rotate(ctx); err != nil {
+			return SigningKey{}, err
+		}
+	}
+
+	return s.keys[0], nil
+}
+
+// Key returns the key with the given ID, which tokens are verified and upstream tokens are decrypted with
+func (s *SecretKeyStore) Key(ctx context.Context, keyID string) (SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refreshIfStale(ctx); err != nil {
+		return SigningKey{}, err
+	}
+
+	key, ok := s.find(keyID)
+	if !ok && time.Since(s.unknownKeyRefreshedAt) >= unknownKeyRefreshInterval {
+		// the key might have just been generated by another replica
+		s.unknownKeyRefreshedAt = time.Now()
+		if err := s.refresh(ctx); err != nil {
+			return SigningKey{}, err
+		}
+		key, ok = s.find(keyID)
+	}
+
+	if !ok {
+		return SigningKey{}, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	return key, nil
+}
+
+// PublicKeys returns the public parts of all keys that issued tokens may be signed with
+func (s *SecretKeyStore) PublicKeys(ctx context.Context) (jose.JSONWebKeySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.refreshIfStale(ctx); err != nil {
+		return jose.JSONWebKeySet{}, err
+	}
+
+	keySet := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, key := range s.keys {
+		keySet.Keys = append(keySet.Keys, jose.JSONWebKey{
+			Key:       &key.PrivateKey.PublicKey,
+			KeyID:     key.ID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		})
+	}
+
+	return keySet, nil
+}
+
+func (s *SecretKeyStore) find(keyID string) (SigningKey, bool) {
+	for _, key := range s.keys {
+		if key.ID == keyID {
+			return key, true
+		}
+	}
+
+	return SigningKey{}, false
+}
+
+func (s *SecretKeyStore) needsRotation() bool {
+	return len(s.keys) == 0 || time.Since(s.keys[0].CreatedAt) >= s.rotationInterval
+}
+
+func (s *SecretKeyStore) refreshIfStale(ctx context.Context) error {
+	if time.Since(s.refreshedAt) < keySetRefreshInterval {
+		return nil
+	}
+
+	return s.refresh(ctx)
+}
+
+func (s *SecretKeyStore) refresh(ctx context.Context) error {
+	secret, err := s.getSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.keys, err = decodeSigningKeys(secret)
+	if err != nil {
+		return err
+	}
+	s.refreshedAt = time.Now()
+
+	return nil
+}
+
+func (s *SecretKeyStore) rotate(ctx context.Context) error {
+	newKey, err := generateSigningKey(time.Now())
+	if err != nil {
+		return err
+	}
+
+	secret, err := s.getSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := decodeSigningKeys(secret)
+	if err != nil {
+		return err
+	}
+	keys = retainedKeys(append([]SigningKey{newKey}, keys...), time.Now(), s.retention)
+
+	secret.Data = encodeSigningKeys(keys)
+
+	if secret.ResourceVersion == "" {
+		err = s.privilegedClient.Create(ctx, secret)
+	} else {
+		err = s.privilegedClient.Update(ctx, secret)
+	}
+	if k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err) {
+		// another replica rotated the key in the meantime, use its key instead
+		return s.refresh(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store signing keys: %w", err)
+	}
+
+	s.keys = keys
+	s.refreshedAt = time.Now()
+
+	return nil
+}
+
+func (s *SecretKeyStore) getSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := new(corev1.Secret)
+	err := s.privilegedClient.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: SigningKeysSecretName}, secret)
+	if k8serrors.IsNotFound(err) {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.namespace,
+				Name:      SigningKeysSecretName,
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	return secret, nil
+}
+
+// retainedKeys drops the keys that were superseded longer than the retention period ago. The keys must be sorted
+// from the newest to the oldest.
+func retainedKeys(keys []SigningKey, now time.Time, retention time.Duration) []SigningKey {
+	result := keys[:1]
+	for i := 1; i < len(keys); i++ {
+		supersededAt := keys[i-1].CreatedAt
+		if now.Sub(supersededAt) > retention {
+			break
+		}
+		result = append(result, keys[i])
+	}
+
+	return result
+}
+
+func generateSigningKey(now time.Time) (SigningKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	return SigningKey{
+		ID:         uuid.NewString(),
+		PrivateKey: privateKey,
+		CreatedAt:  now.UTC().Truncate(time.Second),
+	}, nil
+}
+
+func encodeSigningKeys(keys []SigningKey) map[string][]byte {
+	data := map[string][]byte{}
+	for _, key := range keys {
+		data[key.ID] = pem.EncodeToMemory(&pem.Block{
+			Type:    signingKeyPEMType,
+			Headers: map[string]string{signingKeyCreatedHeader: key.CreatedAt.Format(time.RFC3339)},
+			Bytes:   x509.MarshalPKCS1PrivateKey(key.PrivateKey),
+		})
+	}
+
+	return data
+}
+
+func decodeSigningKeys(secret *corev1.Secret) ([]SigningKey, error) {
+	keys := []SigningKey{}
+	for id, data := range secret.Data {
+		block, _ := pem.Decode(data)
+		if block == nil || block.Type != signingKeyPEMType {
+			return nil, fmt.Errorf("signing key %q is not a PEM encoded RSA private key", id)
+		}
+
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key %q: %w", id, err)
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, block.Headers[signingKeyCreatedHeader])
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q has no valid creation time: %w", id, err)
+		}
+
+		keys = append(keys, SigningKey{ID: id, PrivateKey: privateKey, CreatedAt: createdAt})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
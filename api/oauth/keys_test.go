@@ -0,0 +1,198 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("SecretKeyStore", func() {
+	const rootNamespace = "cf"
+
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+		keyStore  *oauth.SecretKeyStore
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		keyStore = oauth.NewSecretKeyStore(k8sClient, rootNamespace, time.Hour, 2*time.Hour)
+	})
+
+	getSecret := func() *corev1.Secret {
+		secret := new(corev1.Secret)
+		ExpectWithOffset(1, k8sClient.Get(ctx, client.ObjectKey{Namespace: rootNamespace, Name: oauth.SigningKeysSecretName}, secret)).To(Succeed())
+		return secret
+	}
+
+	encodeKey := func(createdAt time.Time) []byte {
+		return pem.EncodeToMemory(&pem.Block{
+			Type:    "RSA PRIVATE KEY",
+			Headers: map[string]string{"Created-At": createdAt.UTC().Format(time.RFC3339)},
+			Bytes:   x509.MarshalPKCS1PrivateKey(generateRSAKey()),
+		})
+	}
+
+	createSecret := func(data map[string][]byte) {
+		ExpectWithOffset(1, k8sClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: rootNamespace, Name: oauth.SigningKeysSecretName},
+			Data:       data,
+		})).To(Succeed())
+	}
+
+	Describe("SigningKey", func() {
+		When("there are no keys yet", func() {
+			It("generates a key and stores it in the secret", func() {
+				key, err := keyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(key.ID).NotTo(BeEmpty())
+				Expect(key.CreatedAt).To(BeTemporally("~", time.Now(), 5*time.Second))
+
+				Expect(getSecret().Data).To(HaveKey(key.ID))
+			})
+
+			It("keeps using the generated key", func() {
+				key, err := keyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherKeyStore := oauth.NewSecretKeyStore(k8sClient, rootNamespace, time.Hour, 2*time.Hour)
+				otherKey, err := otherKeyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(otherKey.ID).To(Equal(key.ID))
+			})
+		})
+
+		When("the current key is due for rotation", func() {
+			BeforeEach(func() {
+				createSecret(map[string][]byte{
+					"current": encodeKey(time.Now().Add(-90 * time.Minute)),
+					"recent":  encodeKey(time.Now().Add(-150 * time.Minute)),
+					"ancient": encodeKey(time.Now().Add(-10 * time.Hour)),
+				})
+			})
+
+			It("signs with a new key", func() {
+				key, err := keyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(key.ID).NotTo(BeElementOf("current", "recent", "ancient"))
+				Expect(getSecret().Data).To(HaveKey(key.ID))
+			})
+
+			It("drops the keys that no unexpired token can be signed with", func() {
+				key, err := keyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(getSecret().Data).To(HaveLen(3))
+				Expect(getSecret().Data).To(HaveKey(key.ID))
+				Expect(getSecret().Data).To(HaveKey("current"))
+				Expect(getSecret().Data).To(HaveKey("recent"))
+			})
+		})
+
+		When("the current key is fresh", func() {
+			BeforeEach(func() {
+				createSecret(map[string][]byte{
+					"current":  encodeKey(time.Now().Add(-10 * time.Minute)),
+					"previous": encodeKey(time.Now().Add(-70 * time.Minute)),
+				})
+			})
+
+			It("signs with the newest key", func() {
+				key, err := keyStore.SigningKey(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(key.ID).To(Equal("current"))
+				Expect(getSecret().Data).To(HaveLen(2))
+			})
+		})
+
+		When("the secret contains an invalid key", func() {
+			BeforeEach(func() {
+				createSecret(map[string][]byte{"broken": []byte("not a key")})
+			})
+
+			It("returns an error", func() {
+				_, err := keyStore.SigningKey(ctx)
+				Expect(err).To(MatchError(ContainSubstring(`signing key "broken"`)))
+			})
+		})
+	})
+
+	Describe("Key", func() {
+		var signingKey oauth.SigningKey
+
+		BeforeEach(func() {
+			var err error
+			signingKey, err = keyStore.SigningKey(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the signing key", func() {
+			key, err := keyStore.Key(ctx, signingKey.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.ID).To(Equal(signingKey.ID))
+			Expect(key.PrivateKey).To(Equal(signingKey.PrivateKey))
+		})
+
+		It("finds keys generated by other replicas", func() {
+			secret := getSecret()
+			secret.Data["other-key"] = encodeKey(time.Now())
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			_, err := keyStore.Key(ctx, "other-key")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error for unknown keys", func() {
+			_, err := keyStore.Key(ctx, "unknown")
+			Expect(err).To(MatchError(ContainSubstring(`unknown signing key "unknown"`)))
+		})
+
+		It("does not read the secret again for unknown keys straight away", func() {
+			_, err := keyStore.Key(ctx, "unknown")
+			Expect(err).To(HaveOccurred())
+
+			secret := getSecret()
+			secret.Data["other-key"] = encodeKey(time.Now())
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			_, err = keyStore.Key(ctx, "other-key")
+			Expect(err).To(MatchError(ContainSubstring(`unknown signing key "other-key"`)))
+		})
+	})
+
+	Describe("PublicKeys", func() {
+		BeforeEach(func() {
+			createSecret(map[string][]byte{
+				"current":  encodeKey(time.Now().Add(-10 * time.Minute)),
+				"previous": encodeKey(time.Now().Add(-70 * time.Minute)),
+			})
+		})
+
+		It("publishes the public part of all keys", func() {
+			keySet, err := keyStore.PublicKeys(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keySet.Keys).To(HaveLen(2))
+			Expect(keySet.Key("current")).To(HaveLen(1))
+			Expect(keySet.Key("previous")).To(HaveLen(1))
+
+			for _, key := range keySet.Keys {
+				Expect(key.IsPublic()).To(BeTrue())
+				Expect(key.Algorithm).To(Equal("RS256"))
+				Expect(key.Use).To(Equal("sig"))
+			}
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package oauth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestOAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OAuth Suite")
+}
+
+func generateRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	return key
+}
+
+func signIDToken(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: "provider-key"}},
+		nil,
+	)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	token, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	return token
+}
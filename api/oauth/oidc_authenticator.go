@@ -0,0 +1,115 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/api/authorization"
+
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+// OIDCClaimMapping maps the claims of ID tokens to Kubernetes users and groups. It must match the OIDC settings of
+// the Kubernetes API server, so that RBAC bindings apply the same way whoever authenticates the user. Names reserved for
+// Kubernetes are rejected, so that the provider cannot be used to claim the identity of a system user or group.
+type OIDCClaimMapping struct {
+	UsernameClaim  string
+	UsernamePrefix string
+	GroupsClaim    string
+	GroupsPrefix   string
+}
+
+func (m OIDCClaimMapping) principal(claims map[string]interface{}) (Principal, error) {
+	name, ok := claims[m.UsernameClaim].(string)
+	if !ok || name == "" {
+		return Principal{}, fmt.Errorf("the ID token has no %q claim", m.UsernameClaim)
+	}
+
+	groups, err := m.groups(claims)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if authorization.IsReservedName(m.UsernamePrefix + name) {
+		return Principal{}, fmt.Errorf("the user name %q is reserved for Kubernetes", m.UsernamePrefix+name)
+	}
+
+	return Principal{
+		Subject:  m.UsernamePrefix + name,
+		UserName: name,
+		Groups:   groups,
+	}, nil
+}
+
+// groups reads the groups claim, which may be a single group or a list of groups
+func (m OIDCClaimMapping) groups(claims map[string]interface{}) ([]string, error) {
+	if m.GroupsClaim == "" {
+		return nil, nil
+	}
+
+	var groups []string
+	switch value := claims[m.GroupsClaim].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		groups = []string{m.GroupsPrefix + value}
+	case []interface{}:
+		groups = make([]string, 0, len(value))
+		for _, v := range value {
+			group, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("the %q claim of the ID token is not a list of strings", m.GroupsClaim)
+			}
+			groups = append(groups, m.GroupsPrefix+group)
+		}
+	default:
+		return nil, fmt.Errorf("the %q claim of the ID token is not a list of strings", m.GroupsClaim)
+	}
+
+	for _, group := range groups {
+		if authorization.IsReservedName(group) {
+			return nil, fmt.Errorf("the group %q is reserved for Kubernetes", group)
+		}
+	}
+
+	return groups, nil
+}
+
+// OIDCAuthenticator authenticates ID tokens of an OpenID Connect provider that are presented as bearer tokens. Unlike
+// a TokenReview, it keeps the groups of the user, and does not need the Kubernetes API server to trust the provider.
+type OIDCAuthenticator struct {
+	provider     *OIDCProvider
+	audience     string
+	claimMapping OIDCClaimMapping
+}
+
+func NewOIDCAuthenticator(provider *OIDCProvider, audience string, claimMapping OIDCClaimMapping) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		provider:     provider,
+		audience:     audience,
+		claimMapping: claimMapping,
+	}
+}
+
+func (a *OIDCAuthenticator) Recognises(token string) bool {
+	parsedToken, err := josejwt.ParseSigned(token)
+	if err != nil {
+		return false
+	}
+
+	claims := josejwt.Claims{}
+	if err := parsedToken.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return false
+	}
+
+	return claims.Issuer == a.provider.issuerURL
+}
+
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (Principal, error) {
+	claims, err := a.provider.VerifyIDToken(ctx, token, a.audience)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return a.claimMapping.principal(claims)
+}
@@ -0,0 +1,193 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2"
+)
+
+var _ = Describe("OIDCAuthenticator", func() {
+	var (
+		server        *httptest.Server
+		providerKey   *rsa.PrivateKey
+		keyRequests   int
+		claims        map[string]interface{}
+		claimMapping  oauth.OIDCClaimMapping
+		authenticator *oauth.OIDCAuthenticator
+	)
+
+	BeforeEach(func() {
+		providerKey = generateRSAKey()
+		keyRequests = 0
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/.well-known/openid-configuration":
+				Expect(json.NewEncoder(w).Encode(map[string]string{
+					"issuer":   server.URL,
+					"jwks_uri": server.URL + "/keys",
+				})).To(Succeed())
+
+			case "/keys":
+				keyRequests++
+				Expect(json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+					Key:       &providerKey.PublicKey,
+					KeyID:     "provider-key",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				}}})).To(Succeed())
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		claims = map[string]interface{}{
+			"iss":    server.URL,
+			"aud":    "korifi",
+			"sub":    "alice",
+			"groups": []string{"team-a", "team-b"},
+			"exp":    time.Now().Add(time.Minute).Unix(),
+		}
+		claimMapping = oauth.OIDCClaimMapping{
+			UsernameClaim:  "sub",
+			UsernamePrefix: "oidc:",
+			GroupsClaim:    "groups",
+			GroupsPrefix:   "oidc-group:",
+		}
+	})
+
+	JustBeforeEach(func() {
+		httpClient, err := oauth.NewOIDCHTTPClient("")
+		Expect(err).NotTo(HaveOccurred())
+		authenticator = oauth.NewOIDCAuthenticator(oauth.NewOIDCProvider(server.URL, httpClient), "korifi", claimMapping)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Recognises", func() {
+		It("recognises tokens of the provider", func() {
+			Expect(authenticator.Recognises(signIDToken(providerKey, claims))).To(BeTrue())
+		})
+
+		It("does not recognise tokens of other issuers", func() {
+			claims["iss"] = "https://elsewhere.example.com"
+			Expect(authenticator.Recognises(signIDToken(providerKey, claims))).To(BeFalse())
+		})
+
+		It("does not recognise opaque tokens", func() {
+			Expect(authenticator.Recognises("not-a-jwt")).To(BeFalse())
+		})
+	})
+
+	Describe("Authenticate", func() {
+		var (
+			principal oauth.Principal
+			authErr   error
+		)
+
+		JustBeforeEach(func() {
+			principal, authErr = authenticator.Authenticate(context.Background(), signIDToken(providerKey, claims))
+		})
+
+		It("maps the user and its groups", func() {
+			Expect(authErr).NotTo(HaveOccurred())
+			Expect(principal).To(Equal(oauth.Principal{
+				Subject:  "oidc:alice",
+				UserName: "alice",
+				Groups:   []string{"oidc-group:team-a", "oidc-group:team-b"},
+			}))
+		})
+
+		It("caches the keys of the provider", func() {
+			_, err := authenticator.Authenticate(context.Background(), signIDToken(providerKey, claims))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyRequests).To(Equal(1))
+		})
+
+		When("the groups claim is a single group", func() {
+			BeforeEach(func() {
+				claims["groups"] = "team-a"
+			})
+
+			It("maps it", func() {
+				Expect(authErr).NotTo(HaveOccurred())
+				Expect(principal.Groups).To(Equal([]string{"oidc-group:team-a"}))
+			})
+		})
+
+		When("the groups claim is not a list of strings", func() {
+			BeforeEach(func() {
+				claims["groups"] = []int{1, 2}
+			})
+
+			It("returns an error", func() {
+				Expect(authErr).To(MatchError(`the "groups" claim of the ID token is not a list of strings`))
+			})
+		})
+
+		When("the user name is reserved for Kubernetes", func() {
+			BeforeEach(func() {
+				claimMapping.UsernamePrefix = ""
+				claims["sub"] = "system:admin"
+			})
+
+			It("returns an error", func() {
+				Expect(authErr).To(MatchError(`the user name "system:admin" is reserved for Kubernetes`))
+			})
+		})
+
+		When("a group is reserved for Kubernetes", func() {
+			BeforeEach(func() {
+				claimMapping.GroupsPrefix = ""
+				claims["groups"] = []string{"team-a", "system:masters"}
+			})
+
+			It("returns an error", func() {
+				Expect(authErr).To(MatchError(`the group "system:masters" is reserved for Kubernetes`))
+			})
+		})
+
+		When("no groups claim is configured", func() {
+			BeforeEach(func() {
+				claimMapping.GroupsClaim = ""
+			})
+
+			It("does not map any group", func() {
+				Expect(authErr).NotTo(HaveOccurred())
+				Expect(principal.Groups).To(BeEmpty())
+			})
+		})
+
+		When("the token is issued for another audience", func() {
+			BeforeEach(func() {
+				claims["aud"] = "someone-else"
+			})
+
+			It("returns an error", func() {
+				Expect(authErr).To(MatchError(ContainSubstring("invalid token")))
+			})
+		})
+
+		When("the token is not signed by the provider", func() {
+			It("returns an error", func() {
+				_, err := authenticator.Authenticate(context.Background(), signIDToken(generateRSAKey(), claims))
+				Expect(err).To(MatchError(ContainSubstring("failed to verify token")))
+			})
+		})
+	})
+})
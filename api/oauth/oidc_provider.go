@@ -0,0 +1,227 @@
+package oauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// oidcKeysMinRefetchInterval stops tokens with made up key IDs from hammering the provider
+	oidcKeysMinRefetchInterval = 10 * time.Second
+)
+
+type oidcDiscovery struct {
+	Issuer        string `json:"issuer"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JWKSURI       string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OIDCProvider talks to an OpenID Connect provider. Its discovery document is fetched once, its keys whenever a
+// token is signed with an unknown key.
+type OIDCProvider struct {
+	issuerURL  string
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          jose.JSONWebKeySet
+	keysFetchedAt time.Time
+}
+
+func NewOIDCProvider(issuerURL string, httpClient *http.Client) *OIDCProvider {
+	return &OIDCProvider{
+		issuerURL:  issuerURL,
+		httpClient: httpClient,
+	}
+}
+
+// NewOIDCHTTPClient returns a client that trusts the given PEM encoded CA certificate in addition to the system ones
+func NewOIDCHTTPClient(caCert string) (*http.Client, error) {
+	if caCert == "" {
+		return &http.Client{Timeout: 30 * time.Second}, nil
+	}
+
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		certPool = x509.NewCertPool()
+	}
+	if !certPool.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, errors.New("failed to parse the OIDC provider CA certificate")
+	}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+		},
+	}, nil
+}
+
+// requestToken performs a grant against the token endpoint of the provider, authenticating as the given client
+func (p *OIDCProvider) requestToken(ctx context.Context, clientID, clientSecret string, form url.Values) (oidcTokenResponse, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return oidcTokenResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return oidcTokenResponse{}, NewUnauthorizedError(fmt.Errorf("provider rejected the credentials: %s", body))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return oidcTokenResponse{}, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var tokenResponse oidcTokenResponse
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return oidcTokenResponse{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	return tokenResponse, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience and expiry of a token signed by the provider and returns all
+// its claims
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawToken, audience string) (map[string]interface{}, error) {
+	token, err := josejwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, errors.New("token must have exactly one signature")
+	}
+
+	key, err := p.verificationKey(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	standardClaims := josejwt.Claims{}
+	claims := map[string]interface{}{}
+	if err = token.Claims(key, &standardClaims, &claims); err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	err = standardClaims.Validate(josejwt.Expected{
+		Issuer:   p.issuerURL,
+		Audience: josejwt.Audience{audience},
+		Time:     time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return claims, nil
+}
+
+func (p *OIDCProvider) verificationKey(ctx context.Context, keyID string) (jose.JSONWebKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if keys := p.keys.Key(keyID); len(keys) > 0 {
+		return keys[0], nil
+	}
+
+	if time.Since(p.keysFetchedAt) < oidcKeysMinRefetchInterval {
+		return jose.JSONWebKey{}, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	discovery, err := p.discoverLocked(ctx)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	if err = p.getJSON(ctx, discovery.JWKSURI, &p.keys); err != nil {
+		return jose.JSONWebKey{}, fmt.Errorf("failed to get provider keys: %w", err)
+	}
+	p.keysFetchedAt = time.Now()
+
+	if keys := p.keys.Key(keyID); len(keys) > 0 {
+		return keys[0], nil
+	}
+
+	return jose.JSONWebKey{}, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.discoverLocked(ctx)
+}
+
+func (p *OIDCProvider) discoverLocked(ctx context.Context) (oidcDiscovery, error) {
+	if p.discovery != nil {
+		return *p.discovery, nil
+	}
+
+	discovery := oidcDiscovery{}
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.issuerURL, "/")+oidcDiscoveryPath, &discovery); err != nil {
+		return oidcDiscovery{}, fmt.Errorf("failed to discover the OIDC provider: %w", err)
+	}
+
+	if discovery.Issuer != p.issuerURL {
+		return oidcDiscovery{}, fmt.Errorf("the OIDC provider reports issuer %q instead of %q", discovery.Issuer, p.issuerURL)
+	}
+
+	p.discovery = &discovery
+
+	return discovery, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s failed with status %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
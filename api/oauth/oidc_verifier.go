@@ -0,0 +1,114 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// OIDCVerifier authenticates users and clients against an OpenID Connect provider. Users are authenticated with a
+// password grant on behalf of the configured client, and are mapped to Kubernetes users and groups from the claims of
+// the returned ID token. Clients are authenticated with their own client credentials grant, and are named after their
+// client ID, prefixed the same way as users. The refresh token of the provider is kept in the refresh tokens of users,
+// so that they are verified with the provider again when they refresh their token.
+type OIDCVerifier struct {
+	provider     *OIDCProvider
+	clientID     string
+	clientSecret string
+	claimMapping OIDCClaimMapping
+}
+
+func NewOIDCVerifier(provider *OIDCProvider, clientID, clientSecret string, claimMapping OIDCClaimMapping) *OIDCVerifier {
+	return &OIDCVerifier{
+		provider:     provider,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		claimMapping: claimMapping,
+	}
+}
+
+func (v *OIDCVerifier) VerifyPassword(ctx context.Context, username, password string) (Principal, error) {
+	tokenResponse, err := v.provider.requestToken(ctx, v.clientID, v.clientSecret, url.Values{
+		"grant_type": {PasswordGrantType},
+		"username":   {username},
+		"password":   {password},
+		"scope":      {"openid"},
+	})
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if tokenResponse.IDToken == "" {
+		return Principal{}, errors.New("the OIDC provider did not return an ID token")
+	}
+
+	return v.principalFromTokenResponse(ctx, tokenResponse)
+}
+
+// VerifyRefresh refreshes the token of the user with the provider, which fails once the user has been disabled or
+// has logged out there, and maps the user again from the refreshed ID token
+func (v *OIDCVerifier) VerifyRefresh(ctx context.Context, principal Principal) (Principal, error) {
+	if principal.UpstreamRefreshToken == "" {
+		return Principal{}, NewUnauthorizedError(errors.New("the OIDC provider did not issue a refresh token, log in again"))
+	}
+
+	tokenResponse, err := v.provider.requestToken(ctx, v.clientID, v.clientSecret, url.Values{
+		"grant_type":    {RefreshTokenGrantType},
+		"refresh_token": {principal.UpstreamRefreshToken},
+	})
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if tokenResponse.RefreshToken == "" {
+		// providers that do not rotate refresh tokens keep accepting the same one
+		tokenResponse.RefreshToken = principal.UpstreamRefreshToken
+	}
+
+	if tokenResponse.IDToken == "" {
+		// ID tokens are optional on refresh, in which case the user is known to be allowed but not their groups
+		principal.UpstreamRefreshToken = tokenResponse.RefreshToken
+		return principal, nil
+	}
+
+	refreshed, err := v.principalFromTokenResponse(ctx, tokenResponse)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if refreshed.Subject != principal.Subject {
+		return Principal{}, NewUnauthorizedError(fmt.Errorf("the refreshed ID token belongs to %q", refreshed.Subject))
+	}
+
+	return refreshed, nil
+}
+
+func (v *OIDCVerifier) principalFromTokenResponse(ctx context.Context, tokenResponse oidcTokenResponse) (Principal, error) {
+	claims, err := v.provider.VerifyIDToken(ctx, tokenResponse.IDToken, v.clientID)
+	if err != nil {
+		return Principal{}, fmt.Errorf("failed to verify the ID token of the OIDC provider: %w", err)
+	}
+
+	principal, err := v.claimMapping.principal(claims)
+	if err != nil {
+		return Principal{}, err
+	}
+	principal.UpstreamRefreshToken = tokenResponse.RefreshToken
+
+	return principal, nil
+}
+
+func (v *OIDCVerifier) VerifyClientCredentials(ctx context.Context, clientID, clientSecret string) (Principal, error) {
+	_, err := v.provider.requestToken(ctx, clientID, clientSecret, url.Values{
+		"grant_type": {ClientCredentialsGrantType},
+	})
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{
+		Subject:  v.claimMapping.UsernamePrefix + clientID,
+		UserName: clientID,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2"
+)
+
+var _ = Describe("OIDCVerifier", func() {
+	var (
+		ctx                  context.Context
+		server               *httptest.Server
+		providerKey          *rsa.PrivateKey
+		tokenStatus          int
+		idTokenClaim         map[string]interface{}
+		refreshTokenResponse string
+		tokenForms           []url.Values
+		basicAuths           [][2]string
+		verifier             *oauth.OIDCVerifier
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		providerKey = generateRSAKey()
+		tokenStatus = http.StatusOK
+		refreshTokenResponse = "provider-refresh-token"
+		tokenForms = nil
+		basicAuths = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/.well-known/openid-configuration":
+				Expect(json.NewEncoder(w).Encode(map[string]string{
+					"issuer":         server.URL,
+					"token_endpoint": server.URL + "/token",
+					"jwks_uri":       server.URL + "/keys",
+				})).To(Succeed())
+
+			case "/keys":
+				Expect(json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+					Key:       &providerKey.PublicKey,
+					KeyID:     "provider-key",
+					Algorithm: string(jose.RS256),
+					Use:       "sig",
+				}}})).To(Succeed())
+
+			case "/token":
+				Expect(r.ParseForm()).To(Succeed())
+				tokenForms = append(tokenForms, r.PostForm)
+				user, password, _ := r.BasicAuth()
+				basicAuths = append(basicAuths, [2]string{user, password})
+
+				w.WriteHeader(tokenStatus)
+				if tokenStatus != http.StatusOK {
+					_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+				response := map[string]string{
+					"access_token":  "provider-access-token",
+					"refresh_token": refreshTokenResponse,
+				}
+				if idTokenClaim != nil {
+					response["id_token"] = signIDToken(providerKey, idTokenClaim)
+				}
+				Expect(json.NewEncoder(w).Encode(response)).To(Succeed())
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		idTokenClaim = map[string]interface{}{
+			"iss":   server.URL,
+			"aud":   "korifi",
+			"sub":   "1234",
+			"email": "alice@example.com",
+			"teams": []string{"team-a", "team-b"},
+			"exp":   time.Now().Add(time.Minute).Unix(),
+		}
+
+		httpClient, err := oauth.NewOIDCHTTPClient("")
+		Expect(err).NotTo(HaveOccurred())
+		verifier = oauth.NewOIDCVerifier(oauth.NewOIDCProvider(server.URL, httpClient), "korifi", "korifi-secret", oauth.OIDCClaimMapping{
+			UsernameClaim:  "email",
+			UsernamePrefix: "oidc:",
+			GroupsClaim:    "teams",
+			GroupsPrefix:   "oidc:",
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("VerifyPassword", func() {
+		var (
+			principal oauth.Principal
+			verifyErr error
+		)
+
+		JustBeforeEach(func() {
+			principal, verifyErr = verifier.VerifyPassword(ctx, "alice", "secret")
+		})
+
+		It("performs a password grant on behalf of the configured client", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(tokenForms).To(HaveLen(1))
+			Expect(tokenForms[0].Get("grant_type")).To(Equal("password"))
+			Expect(tokenForms[0].Get("username")).To(Equal("alice"))
+			Expect(tokenForms[0].Get("password")).To(Equal("secret"))
+			Expect(tokenForms[0].Get("scope")).To(Equal("openid"))
+			Expect(basicAuths).To(Equal([][2]string{{"korifi", "korifi-secret"}}))
+		})
+
+		It("maps the user and groups from the configured claims of the ID token", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(principal).To(Equal(oauth.Principal{
+				Subject:              "oidc:alice@example.com",
+				UserName:             "alice@example.com",
+				Groups:               []string{"oidc:team-a", "oidc:team-b"},
+				UpstreamRefreshToken: "provider-refresh-token",
+			}))
+		})
+
+		When("the provider rejects the credentials", func() {
+			BeforeEach(func() {
+				tokenStatus = http.StatusUnauthorized
+			})
+
+			It("returns an unauthorized error", func() {
+				var oauthErr oauth.Error
+				Expect(errors.As(verifyErr, &oauthErr)).To(BeTrue())
+				Expect(oauthErr.Code()).To(Equal(oauth.UnauthorizedErrorCode))
+			})
+		})
+
+		When("the ID token is issued for another audience", func() {
+			BeforeEach(func() {
+				idTokenClaim["aud"] = "someone-else"
+			})
+
+			It("returns an error", func() {
+				Expect(verifyErr).To(MatchError(ContainSubstring("failed to verify the ID token")))
+			})
+		})
+
+		When("the ID token has expired", func() {
+			BeforeEach(func() {
+				idTokenClaim["exp"] = time.Now().Add(-time.Hour).Unix()
+			})
+
+			It("returns an error", func() {
+				Expect(verifyErr).To(MatchError(ContainSubstring("failed to verify the ID token")))
+			})
+		})
+
+		When("the ID token does not have the username claim", func() {
+			BeforeEach(func() {
+				delete(idTokenClaim, "email")
+			})
+
+			It("returns an error", func() {
+				Expect(verifyErr).To(MatchError(`the ID token has no "email" claim`))
+			})
+		})
+	})
+
+	Describe("VerifyRefresh", func() {
+		var (
+			principal        oauth.Principal
+			refreshPrincipal oauth.Principal
+			verifyErr        error
+		)
+
+		BeforeEach(func() {
+			refreshPrincipal = oauth.Principal{
+				Subject:              "oidc:alice@example.com",
+				UserName:             "alice@example.com",
+				Groups:               []string{"oidc:team-a"},
+				UpstreamRefreshToken: "old-provider-refresh-token",
+			}
+		})
+
+		JustBeforeEach(func() {
+			principal, verifyErr = verifier.VerifyRefresh(ctx, refreshPrincipal)
+		})
+
+		It("refreshes the token of the provider on behalf of the configured client", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(tokenForms).To(HaveLen(1))
+			Expect(tokenForms[0].Get("grant_type")).To(Equal("refresh_token"))
+			Expect(tokenForms[0].Get("refresh_token")).To(Equal("old-provider-refresh-token"))
+			Expect(basicAuths).To(Equal([][2]string{{"korifi", "korifi-secret"}}))
+		})
+
+		It("maps the user again from the refreshed ID token", func() {
+			Expect(principal).To(Equal(oauth.Principal{
+				Subject:              "oidc:alice@example.com",
+				UserName:             "alice@example.com",
+				Groups:               []string{"oidc:team-a", "oidc:team-b"},
+				UpstreamRefreshToken: "provider-refresh-token",
+			}))
+		})
+
+		When("the provider does not rotate the refresh token", func() {
+			BeforeEach(func() {
+				refreshTokenResponse = ""
+			})
+
+			It("keeps the previous one", func() {
+				Expect(principal.UpstreamRefreshToken).To(Equal("old-provider-refresh-token"))
+			})
+		})
+
+		When("the provider does not return an ID token", func() {
+			BeforeEach(func() {
+				idTokenClaim = nil
+			})
+
+			It("keeps the principal", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(principal.Groups).To(ConsistOf("oidc:team-a"))
+				Expect(principal.UpstreamRefreshToken).To(Equal("provider-refresh-token"))
+			})
+		})
+
+		When("the provider rejects the refresh token", func() {
+			BeforeEach(func() {
+				tokenStatus = http.StatusBadRequest
+			})
+
+			It("returns an unauthorized error", func() {
+				var oauthErr oauth.Error
+				Expect(errors.As(verifyErr, &oauthErr)).To(BeTrue())
+				Expect(oauthErr.Code()).To(Equal(oauth.UnauthorizedErrorCode))
+			})
+		})
+
+		When("the refreshed ID token belongs to another user", func() {
+			BeforeEach(func() {
+				idTokenClaim["email"] = "bob@example.com"
+			})
+
+			It("returns an unauthorized error", func() {
+				var oauthErr oauth.Error
+				Expect(errors.As(verifyErr, &oauthErr)).To(BeTrue())
+				Expect(oauthErr.Code()).To(Equal(oauth.UnauthorizedErrorCode))
+			})
+		})
+
+		When("there is no refresh token of the provider", func() {
+			BeforeEach(func() {
+				refreshPrincipal.UpstreamRefreshToken = ""
+			})
+
+			It("returns an unauthorized error without calling the provider", func() {
+				var oauthErr oauth.Error
+				Expect(errors.As(verifyErr, &oauthErr)).To(BeTrue())
+				Expect(oauthErr.Code()).To(Equal(oauth.UnauthorizedErrorCode))
+				Expect(tokenForms).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("VerifyClientCredentials", func() {
+		var (
+			principal oauth.Principal
+			verifyErr error
+		)
+
+		JustBeforeEach(func() {
+			principal, verifyErr = verifier.VerifyClientCredentials(ctx, "my-client", "my-secret")
+		})
+
+		It("performs a client credentials grant as the client", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(tokenForms).To(HaveLen(1))
+			Expect(tokenForms[0].Get("grant_type")).To(Equal("client_credentials"))
+			Expect(basicAuths).To(Equal([][2]string{{"my-client", "my-secret"}}))
+		})
+
+		It("names the principal after the client", func() {
+			Expect(principal).To(Equal(oauth.Principal{Subject: "oidc:my-client", UserName: "my-client"}))
+		})
+
+		When("the provider rejects the credentials", func() {
+			BeforeEach(func() {
+				tokenStatus = http.StatusBadRequest
+			})
+
+			It("returns an unauthorized error", func() {
+				var oauthErr oauth.Error
+				Expect(errors.As(verifyErr, &oauthErr)).To(BeTrue())
+				Expect(oauthErr.Code()).To(Equal(oauth.UnauthorizedErrorCode))
+			})
+		})
+	})
+})
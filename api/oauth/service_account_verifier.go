@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+// ServiceAccountVerifier authenticates Kubernetes service accounts. The user name or client ID is the service
+// account as `<namespace>:<name>` and the password or client secret is one of its tokens. Only the service accounts
+// of the given namespaces may log in, as the API is only allowed to impersonate those.
+type ServiceAccountVerifier struct {
+	privilegedClient client.Client
+	namespaces       map[string]bool
+}
+
+func NewServiceAccountVerifier(privilegedClient client.Client, namespaces []string) *ServiceAccountVerifier {
+	namespaceSet := map[string]bool{}
+	for _, namespace := range namespaces {
+		namespaceSet[namespace] = true
+	}
+
+	return &ServiceAccountVerifier{privilegedClient: privilegedClient, namespaces: namespaceSet}
+}
+
+func (v *ServiceAccountVerifier) VerifyPassword(ctx context.Context, username, password string) (Principal, error) {
+	return v.verify(ctx, username, password)
+}
+
+func (v *ServiceAccountVerifier) VerifyClientCredentials(ctx context.Context, clientID, clientSecret string) (Principal, error) {
+	return v.verify(ctx, clientID, clientSecret)
+}
+
+// VerifyRefresh reviews the token the principal authenticated with again, which fails once the token has expired, its
+// service account has been deleted or it has been revoked
+func (v *ServiceAccountVerifier) VerifyRefresh(ctx context.Context, principal Principal) (Principal, error) {
+	if principal.UpstreamRefreshToken == "" {
+		return Principal{}, NewUnauthorizedError(errors.New("the refresh token does not carry a service account token, log in again"))
+	}
+
+	refreshed, err := v.verify(ctx, principal.UserName, principal.UpstreamRefreshToken)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if refreshed.Subject != principal.Subject {
+		return Principal{}, NewUnauthorizedError(fmt.Errorf("the service account token belongs to %q", refreshed.Subject))
+	}
+
+	return refreshed, nil
+}
+
+func (v *ServiceAccountVerifier) verify(ctx context.Context, serviceAccount, token string) (Principal, error) {
+	tokenReview := &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	if err := v.privilegedClient.Create(ctx, tokenReview); err != nil {
+		return Principal{}, fmt.Errorf("failed to create token review: %w", err)
+	}
+
+	if !tokenReview.Status.Authenticated {
+		return Principal{}, NewUnauthorizedError(errors.New("token not authenticated"))
+	}
+
+	subject := tokenReview.Status.User.Username
+	userName := strings.TrimPrefix(subject, serviceAccountUserPrefix)
+	if userName == subject {
+		return Principal{}, NewUnauthorizedError(errors.New("token does not belong to a service account"))
+	}
+
+	if userName != strings.TrimPrefix(serviceAccount, serviceAccountUserPrefix) {
+		return Principal{}, NewUnauthorizedError(fmt.Errorf("token does not belong to service account %q", serviceAccount))
+	}
+
+	namespace := strings.SplitN(userName, ":", 2)[0]
+	if !v.namespaces[namespace] {
+		return Principal{}, NewUnauthorizedError(fmt.Errorf("service accounts of namespace %q may not log in", namespace))
+	}
+
+	// the token is kept, encrypted, in the refresh token, so that it can be reviewed again on refresh
+	return Principal{
+		Subject:              subject,
+		UserName:             userName,
+		UpstreamRefreshToken: token,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package oauth_test
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+	controllersfake "code.cloudfoundry.org/korifi/controllers/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ServiceAccountVerifier", func() {
+	var (
+		ctx       context.Context
+		k8sClient *controllersfake.Client
+		status    authv1.TokenReviewStatus
+		verifier  *oauth.ServiceAccountVerifier
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient = new(controllersfake.Client)
+		status = authv1.TokenReviewStatus{
+			Authenticated: true,
+			User:          authv1.UserInfo{Username: "system:serviceaccount:cf:robot"},
+		}
+		k8sClient.CreateStub = func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			obj.(*authv1.TokenReview).Status = status
+			return nil
+		}
+		verifier = oauth.NewServiceAccountVerifier(k8sClient, []string{"cf"})
+	})
+
+	Describe("VerifyPassword", func() {
+		It("keeps the token so that it can be reviewed again on refresh", func() {
+			principal, err := verifier.VerifyPassword(ctx, "cf:robot", "a-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(principal.Subject).To(Equal("system:serviceaccount:cf:robot"))
+			Expect(principal.UserName).To(Equal("cf:robot"))
+			Expect(principal.UpstreamRefreshToken).To(Equal("a-token"))
+		})
+
+		When("the service account is in a namespace whose service accounts may not log in", func() {
+			BeforeEach(func() {
+				status.User.Username = "system:serviceaccount:other:robot"
+			})
+
+			It("rejects it", func() {
+				_, err := verifier.VerifyPassword(ctx, "other:robot", "a-token")
+				Expect(err).To(MatchError(ContainSubstring(`service accounts of namespace "other" may not log in`)))
+			})
+		})
+
+		When("the token belongs to another service account", func() {
+			It("rejects it", func() {
+				_, err := verifier.VerifyPassword(ctx, "cf:other", "a-token")
+				Expect(err).To(MatchError(ContainSubstring(`token does not belong to service account "cf:other"`)))
+			})
+		})
+	})
+
+	Describe("VerifyRefresh", func() {
+		var (
+			principal oauth.Principal
+			refreshed oauth.Principal
+			err       error
+		)
+
+		BeforeEach(func() {
+			principal = oauth.Principal{
+				Subject:              "system:serviceaccount:cf:robot",
+				UserName:             "cf:robot",
+				UpstreamRefreshToken: "a-token",
+			}
+		})
+
+		JustBeforeEach(func() {
+			refreshed, err = verifier.VerifyRefresh(ctx, principal)
+		})
+
+		It("reviews the token again", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refreshed).To(Equal(principal))
+
+			Expect(k8sClient.CreateCallCount()).To(Equal(1))
+			_, obj, _ := k8sClient.CreateArgsForCall(0)
+			Expect(obj.(*authv1.TokenReview).Spec.Token).To(Equal("a-token"))
+		})
+
+		When("the token is no longer authenticated", func() {
+			BeforeEach(func() {
+				status.Authenticated = false
+			})
+
+			It("returns an unauthorized error", func() {
+				var oauthErr oauth.Error
+				Expect(err).To(BeAssignableToTypeOf(oauthErr))
+				Expect(err.(oauth.Error).Code()).To(Equal(oauth.UnauthorizedErrorCode))
+			})
+		})
+
+		When("the refresh token does not carry the service account token", func() {
+			BeforeEach(func() {
+				principal.UpstreamRefreshToken = ""
+			})
+
+			It("asks to log in again without reviewing anything", func() {
+				Expect(err).To(MatchError(ContainSubstring("log in again")))
+				Expect(k8sClient.CreateCallCount()).To(BeZero())
+			})
+		})
+	})
+})
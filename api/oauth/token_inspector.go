@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+)
+
+// TokenInspector identifies the subjects of tokens that one of its authenticators recognises, and leaves any other
+// token to the wrapped inspector
+type TokenInspector struct {
+	delegate       authorization.TokenIdentityInspector
+	authenticators []Authenticator
+}
+
+func NewTokenInspector(delegate authorization.TokenIdentityInspector, authenticators ...Authenticator) *TokenInspector {
+	return &TokenInspector{
+		delegate:       delegate,
+		authenticators: authenticators,
+	}
+}
+
+func (i *TokenInspector) WhoAmI(ctx context.Context, token string) (authorization.Identity, error) {
+	authenticator, ok := recognisingAuthenticator(i.authenticators, token)
+	if !ok {
+		return i.delegate.WhoAmI(ctx, token)
+	}
+
+	principal, err := authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return authorization.Identity{}, apierrors.NewInvalidAuthError(err)
+	}
+
+	return IdentityFromPrincipal(principal), nil
+}
+
+func recognisingAuthenticator(authenticators []Authenticator, token string) (Authenticator, bool) {
+	if token == "" {
+		return nil, false
+	}
+
+	for _, authenticator := range authenticators {
+		if authenticator.Recognises(token) {
+			return authenticator, true
+		}
+	}
+
+	return nil, false
+}
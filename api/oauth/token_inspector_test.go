@@ -0,0 +1,87 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	authfake "code.cloudfoundry.org/korifi/api/authorization/fake"
+	"code.cloudfoundry.org/korifi/api/oauth"
+	"code.cloudfoundry.org/korifi/api/oauth/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("TokenInspector", func() {
+	var (
+		delegate       *authfake.TokenIdentityInspector
+		authenticator1 *fake.Authenticator
+		authenticator2 *fake.Authenticator
+		inspector      *oauth.TokenInspector
+		identity       authorization.Identity
+		whoAmIErr      error
+	)
+
+	BeforeEach(func() {
+		delegate = new(authfake.TokenIdentityInspector)
+		delegate.WhoAmIReturns(authorization.Identity{Name: "k8s-user", Kind: rbacv1.UserKind}, nil)
+
+		authenticator1 = new(fake.Authenticator)
+		authenticator2 = new(fake.Authenticator)
+		authenticator2.AuthenticateReturns(oauth.Principal{
+			Subject:  "oidc:alice",
+			UserName: "alice",
+			Groups:   []string{"oidc:team-a"},
+		}, nil)
+
+		inspector = oauth.NewTokenInspector(delegate, authenticator1, authenticator2)
+	})
+
+	JustBeforeEach(func() {
+		identity, whoAmIErr = inspector.WhoAmI(context.Background(), "the-token")
+	})
+
+	When("an authenticator recognises the token", func() {
+		BeforeEach(func() {
+			authenticator2.RecognisesReturns(true)
+		})
+
+		It("authenticates the token with it", func() {
+			Expect(authenticator2.AuthenticateCallCount()).To(Equal(1))
+			_, actualToken := authenticator2.AuthenticateArgsForCall(0)
+			Expect(actualToken).To(Equal("the-token"))
+			Expect(authenticator1.AuthenticateCallCount()).To(BeZero())
+			Expect(delegate.WhoAmICallCount()).To(BeZero())
+		})
+
+		It("returns the identity of the principal, with its groups", func() {
+			Expect(whoAmIErr).NotTo(HaveOccurred())
+			Expect(identity).To(Equal(authorization.Identity{
+				Name:   "oidc:alice",
+				Kind:   rbacv1.UserKind,
+				Groups: []string{"oidc:team-a"},
+			}))
+		})
+
+		When("authentication fails", func() {
+			BeforeEach(func() {
+				authenticator2.AuthenticateReturns(oauth.Principal{}, errors.New("expired"))
+			})
+
+			It("returns an invalid auth error", func() {
+				Expect(whoAmIErr).To(BeAssignableToTypeOf(apierrors.InvalidAuthError{}))
+			})
+		})
+	})
+
+	When("no authenticator recognises the token", func() {
+		It("delegates", func() {
+			Expect(delegate.WhoAmICallCount()).To(Equal(1))
+			Expect(whoAmIErr).NotTo(HaveOccurred())
+			Expect(identity.Name).To(Equal("k8s-user"))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+
+	k8sclient "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UserClientFactory builds the clients of users authenticated with tokens that Kubernetes cannot authenticate, such
+// as the access tokens issued by the API, by impersonating the token subject and its groups. Clients of impersonated
+// users are built the same way for any auth info, once the identity provider has checked that the caller may
+// impersonate the user, so that only the API itself needs to be allowed to impersonate users in Kubernetes. Any other
+// auth info is handed to the wrapped factory.
+type UserClientFactory struct {
+	impersonatingFactory authorization.ImpersonatingClientFactory
+	delegate             authorization.UserK8sClientFactory
+	identityProvider     authorization.IdentityProvider
+	authenticators       []Authenticator
+}
+
+func NewUserClientFactory(
+	impersonatingFactory authorization.ImpersonatingClientFactory,
+	delegate authorization.UserK8sClientFactory,
+	identityProvider authorization.IdentityProvider,
+	authenticators ...Authenticator,
+) UserClientFactory {
+	return UserClientFactory{
+		impersonatingFactory: impersonatingFactory,
+		delegate:             delegate,
+		identityProvider:     identityProvider,
+		authenticators:       authenticators,
+	}
+}
+
+func (f UserClientFactory) BuildClient(ctx context.Context, authInfo authorization.Info) (client.WithWatch, error) {
+	if authInfo.ImpersonatedUser != "" {
+		identity, err := f.impersonatedIdentity(ctx, authInfo)
+		if err != nil {
+			return nil, err
+		}
+		return f.impersonatingFactory.BuildClient(identity.Name, nil)
+	}
+
+	authenticator, ok := recognisingAuthenticator(f.authenticators, authInfo.Token)
+	if !ok {
+		return f.delegate.BuildClient(ctx, authInfo)
+	}
+
+	principal, err := f.authenticate(ctx, authenticator, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.impersonatingFactory.BuildClient(principal.Subject, principal.Groups)
+}
+
+func (f UserClientFactory) BuildK8sClient(ctx context.Context, authInfo authorization.Info) (k8sclient.Interface, error) {
+	if authInfo.ImpersonatedUser != "" {
+		identity, err := f.impersonatedIdentity(ctx, authInfo)
+		if err != nil {
+			return nil, err
+		}
+		return f.impersonatingFactory.BuildK8sClient(identity.Name, nil)
+	}
+
+	authenticator, ok := recognisingAuthenticator(f.authenticators, authInfo.Token)
+	if !ok {
+		return f.delegate.BuildK8sClient(ctx, authInfo)
+	}
+
+	principal, err := f.authenticate(ctx, authenticator, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.impersonatingFactory.BuildK8sClient(principal.Subject, principal.Groups)
+}
+
+// impersonatedIdentity checks that the caller may impersonate the user of the auth info
+func (f UserClientFactory) impersonatedIdentity(ctx context.Context, authInfo authorization.Info) (authorization.Identity, error) {
+	identity, err := f.identityProvider.GetIdentity(ctx, authInfo)
+	if err != nil {
+		return authorization.Identity{}, err
+	}
+
+	if identity.Impersonator == nil {
+		return authorization.Identity{}, apierrors.NewForbiddenError(errors.New("the identity provider does not support impersonation"), "")
+	}
+
+	return identity, nil
+}
+
+func (f UserClientFactory) authenticate(ctx context.Context, authenticator Authenticator, authInfo authorization.Info) (Principal, error) {
+	principal, err := authenticator.Authenticate(ctx, authInfo.Token)
+	if err != nil {
+		return Principal{}, apierrors.NewInvalidAuthError(err)
+	}
+
+	return principal, nil
+}
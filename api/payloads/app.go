@@ -78,31 +78,36 @@ type AppPatchEnvVars struct {
 }
 
 func (a *AppPatchEnvVars) ToMessage(appGUID, spaceGUID string) repositories.PatchAppEnvVarsMessage {
-	message := repositories.PatchAppEnvVarsMessage{
+	return repositories.PatchAppEnvVarsMessage{
 		AppGUID:              appGUID,
 		SpaceGUID:            spaceGUID,
-		EnvironmentVariables: map[string]*string{},
+		EnvironmentVariables: envVarsPatch(a.Var),
 	}
+}
+
+// envVarsPatch converts the values of an env var patch to strings. A nil value removes the variable.
+func envVarsPatch(vars map[string]interface{}) map[string]*string {
+	patch := map[string]*string{}
 
-	for k, v := range a.Var {
+	for k, v := range vars {
 		switch v := v.(type) {
 		case nil:
-			message.EnvironmentVariables[k] = nil
+			patch[k] = nil
 		case bool:
 			stringVar := fmt.Sprintf("%t", v)
-			message.EnvironmentVariables[k] = &stringVar
+			patch[k] = &stringVar
 		case float32:
 			stringVar := fmt.Sprintf("%f", v)
-			message.EnvironmentVariables[k] = &stringVar
+			patch[k] = &stringVar
 		case int:
 			stringVar := fmt.Sprintf("%d", v)
-			message.EnvironmentVariables[k] = &stringVar
+			patch[k] = &stringVar
 		case string:
-			message.EnvironmentVariables[k] = &v
+			patch[k] = &v
 		}
 	}
 
-	return message
+	return patch
 }
 
 type AppPatch struct {
@@ -0,0 +1,25 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type AppUsageEventList struct {
+	GUIDs     *string `schema:"guids"`
+	AfterGUID string  `schema:"after_guid"`
+	PerPage   int     `schema:"per_page"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	Page    string `schema:"page"`
+}
+
+func (l *AppUsageEventList) ToMessage() repositories.ListAppUsageEventsMessage {
+	return repositories.ListAppUsageEventsMessage{
+		GUIDs:     ParseArrayParam(l.GUIDs),
+		AfterGUID: l.AfterGUID,
+		Limit:     l.PerPage,
+	}
+}
+
+func (l *AppUsageEventList) SupportedKeys() []string {
+	return []string{"guids", "after_guid", "per_page", "order_by", "page"}
+}
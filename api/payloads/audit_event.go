@@ -0,0 +1,89 @@
+package payloads
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+type AuditEventList struct {
+	Types             *string `schema:"types"`
+	TargetGUIDs       *string `schema:"target_guids"`
+	SpaceGUIDs        *string `schema:"space_guids"`
+	OrganizationGUIDs *string `schema:"organization_guids"`
+	CreatedAts        *string `schema:"created_ats"`
+	CreatedAtsLT      *string `schema:"created_ats[lt]"`
+	CreatedAtsLTE     *string `schema:"created_ats[lte]"`
+	CreatedAtsGT      *string `schema:"created_ats[gt]"`
+	CreatedAtsGTE     *string `schema:"created_ats[gte]"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	PerPage string `schema:"per_page"`
+	Page    string `schema:"page"`
+}
+
+func (l *AuditEventList) ToMessage() (repositories.ListAuditEventsMessage, error) {
+	message := repositories.ListAuditEventsMessage{
+		Types:             ParseArrayParam(l.Types),
+		TargetGUIDs:       ParseArrayParam(l.TargetGUIDs),
+		SpaceGUIDs:        ParseArrayParam(l.SpaceGUIDs),
+		OrganizationGUIDs: ParseArrayParam(l.OrganizationGUIDs),
+	}
+
+	for _, createdAt := range ParseArrayParam(l.CreatedAts) {
+		timestamp, err := parseTimestamp("created_ats", createdAt)
+		if err != nil {
+			return repositories.ListAuditEventsMessage{}, err
+		}
+		message.CreatedAts = append(message.CreatedAts, timestamp)
+	}
+
+	var err error
+	if message.CreatedBefore, err = parseOptionalTimestamp("created_ats[lt]", l.CreatedAtsLT); err != nil {
+		return repositories.ListAuditEventsMessage{}, err
+	}
+	if message.CreatedAtOrBefore, err = parseOptionalTimestamp("created_ats[lte]", l.CreatedAtsLTE); err != nil {
+		return repositories.ListAuditEventsMessage{}, err
+	}
+	if message.CreatedAfter, err = parseOptionalTimestamp("created_ats[gt]", l.CreatedAtsGT); err != nil {
+		return repositories.ListAuditEventsMessage{}, err
+	}
+	if message.CreatedAtOrAfter, err = parseOptionalTimestamp("created_ats[gte]", l.CreatedAtsGTE); err != nil {
+		return repositories.ListAuditEventsMessage{}, err
+	}
+
+	return message, nil
+}
+
+func (l *AuditEventList) SupportedKeys() []string {
+	return []string{
+		"types", "target_guids", "space_guids", "organization_guids",
+		"created_ats", "created_ats[lt]", "created_ats[lte]", "created_ats[gt]", "created_ats[gte]",
+		"order_by", "per_page", "page",
+	}
+}
+
+func parseOptionalTimestamp(key string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	timestamp, err := parseTimestamp(key, *value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timestamp, nil
+}
+
+func parseTimestamp(key, value string) (time.Time, error) {
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, apierrors.NewUnprocessableEntityError(err, fmt.Sprintf("%s must be a timestamp in ISO 8601 format", key))
+	}
+
+	return timestamp, nil
+}
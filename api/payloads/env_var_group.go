@@ -0,0 +1,14 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type EnvVarGroupPatch struct {
+	Var map[string]interface{} `json:"var" validate:"required,dive,keys,startsnotwith=VCAP_,startsnotwith=VMC_,ne=PORT,endkeys"`
+}
+
+func (p *EnvVarGroupPatch) ToMessage(name string) repositories.PatchEnvVarGroupMessage {
+	return repositories.PatchEnvVarGroupMessage{
+		Name:                 name,
+		EnvironmentVariables: envVarsPatch(p.Var),
+	}
+}
@@ -0,0 +1,52 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type IsolationSegmentCreate struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (p IsolationSegmentCreate) ToMessage() repositories.CreateIsolationSegmentMessage {
+	return repositories.CreateIsolationSegmentMessage{
+		Name: p.Name,
+	}
+}
+
+type IsolationSegmentList struct {
+	GUIDs             *string `schema:"guids"`
+	Names             *string `schema:"names"`
+	OrganizationGUIDs *string `schema:"organization_guids"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	PerPage string `schema:"per_page"`
+	Page    string `schema:"page"`
+}
+
+func (l *IsolationSegmentList) ToMessage() repositories.ListIsolationSegmentsMessage {
+	return repositories.ListIsolationSegmentsMessage{
+		GUIDs:             ParseArrayParam(l.GUIDs),
+		Names:             ParseArrayParam(l.Names),
+		OrganizationGUIDs: ParseArrayParam(l.OrganizationGUIDs),
+	}
+}
+
+func (l *IsolationSegmentList) SupportedKeys() []string {
+	return []string{"guids", "names", "organization_guids", "order_by", "per_page", "page"}
+}
+
+// SpaceIsolationSegmentPatch accepts a null data to reset the space to the shared isolation segment
+type SpaceIsolationSegmentPatch struct {
+	Data *RelationshipData `json:"data"`
+}
+
+func (p SpaceIsolationSegmentPatch) ToMessage(spaceGUID string) repositories.AssignSpaceIsolationSegmentMessage {
+	message := repositories.AssignSpaceIsolationSegmentMessage{
+		SpaceGUID: spaceGUID,
+	}
+	if p.Data != nil {
+		message.IsolationSegmentGUID = p.Data.GUID
+	}
+
+	return message
+}
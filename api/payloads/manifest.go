@@ -6,6 +6,7 @@ import (
 	"code.cloudfoundry.org/korifi/tools"
 
 	"code.cloudfoundry.org/bytefmt"
+	"gopkg.in/yaml.v3"
 )
 
 type Manifest struct {
@@ -15,49 +16,76 @@ type Manifest struct {
 
 type ManifestApplication struct {
 	Name         string            `yaml:"name" validate:"required"`
-	Env          map[string]string `yaml:"env"`
-	DefaultRoute bool              `yaml:"default-route"`
-	RandomRoute  bool              `yaml:"random-route"`
-	NoRoute      bool              `yaml:"no-route"`
-	Command      *string           `yaml:"command"`
-	Instances    *int              `yaml:"instances" validate:"omitempty,gte=0"`
-	Memory       *string           `yaml:"memory" validate:"megabytestring"`
-	DiskQuota    *string           `yaml:"disk_quota" validate:"megabytestring"`
+	Env          map[string]string `yaml:"env,omitempty"`
+	DefaultRoute bool              `yaml:"default-route,omitempty"`
+	RandomRoute  bool              `yaml:"random-route,omitempty"`
+	NoRoute      bool              `yaml:"no-route,omitempty"`
+	Command      *string           `yaml:"command,omitempty"`
+	Instances    *int              `yaml:"instances,omitempty" validate:"omitempty,gte=0"`
+	Memory       *string           `yaml:"memory,omitempty" validate:"megabytestring"`
+	DiskQuota    *string           `yaml:"disk_quota,omitempty" validate:"megabytestring"`
 	// AltDiskQuota supports `disk-quota` with a hyphen for backwards compatibility.
 	// Do not set both DiskQuota and AltDiskQuota.
 	//
 	// Deprecated: Use DiskQuota instead
-	AltDiskQuota                 *string                      `yaml:"disk-quota" validate:"megabytestring"`
-	HealthCheckHTTPEndpoint      *string                      `yaml:"health-check-http-endpoint"`
-	HealthCheckInvocationTimeout *int64                       `yaml:"health-check-invocation-timeout" validate:"omitempty,gte=1"`
-	HealthCheckType              *string                      `yaml:"health-check-type" validate:"omitempty,oneof=none process port http"`
-	Timeout                      *int64                       `yaml:"timeout" validate:"omitempty,gte=1"`
-	Processes                    []ManifestApplicationProcess `yaml:"processes" validate:"dive"`
-	Routes                       []ManifestRoute              `yaml:"routes" validate:"dive"`
-	Buildpacks                   []string                     `yaml:"buildpacks"`
+	AltDiskQuota                 *string                      `yaml:"disk-quota,omitempty" validate:"megabytestring"`
+	HealthCheckHTTPEndpoint      *string                      `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout *int64                       `yaml:"health-check-invocation-timeout,omitempty" validate:"omitempty,gte=1"`
+	HealthCheckType              *string                      `yaml:"health-check-type,omitempty" validate:"omitempty,oneof=none process port http"`
+	Timeout                      *int64                       `yaml:"timeout,omitempty" validate:"omitempty,gte=1"`
+	Processes                    []ManifestApplicationProcess `yaml:"processes,omitempty" validate:"dive"`
+	Routes                       []ManifestRoute              `yaml:"routes,omitempty" validate:"dive"`
+	Buildpacks                   []string                     `yaml:"buildpacks,omitempty"`
+	Services                     []ManifestApplicationService `yaml:"services,omitempty" validate:"dive"`
 	// Deprecated: Use Buildpacks instead
-	Buildpack string `yaml:"buildpack"`
+	Buildpack string `yaml:"buildpack,omitempty"`
 }
 
 type ManifestApplicationProcess struct {
 	Type      string  `yaml:"type" validate:"required"`
-	Command   *string `yaml:"command"`
-	DiskQuota *string `yaml:"disk_quota" validate:"megabytestring"`
+	Command   *string `yaml:"command,omitempty"`
+	DiskQuota *string `yaml:"disk_quota,omitempty" validate:"megabytestring"`
 	// AltDiskQuota supports `disk-quota` with a hyphen for backwards compatibility.
 	// Do not set both DiskQuota and AltDiskQuota.
 	//
 	// Deprecated: Use DiskQuota instead
-	AltDiskQuota                 *string `yaml:"disk-quota" validate:"megabytestring"`
-	HealthCheckHTTPEndpoint      *string `yaml:"health-check-http-endpoint"`
-	HealthCheckInvocationTimeout *int64  `yaml:"health-check-invocation-timeout" validate:"omitempty,gte=1"`
-	HealthCheckType              *string `yaml:"health-check-type" validate:"omitempty,oneof=none process port http"`
-	Instances                    *int    `yaml:"instances" validate:"omitempty,gte=0"`
-	Memory                       *string `yaml:"memory" validate:"megabytestring"`
-	Timeout                      *int64  `yaml:"timeout" validate:"omitempty,gte=1"`
+	AltDiskQuota                 *string `yaml:"disk-quota,omitempty" validate:"megabytestring"`
+	HealthCheckHTTPEndpoint      *string `yaml:"health-check-http-endpoint,omitempty"`
+	HealthCheckInvocationTimeout *int64  `yaml:"health-check-invocation-timeout,omitempty" validate:"omitempty,gte=1"`
+	HealthCheckType              *string `yaml:"health-check-type,omitempty" validate:"omitempty,oneof=none process port http"`
+	Instances                    *int    `yaml:"instances,omitempty" validate:"omitempty,gte=0"`
+	Memory                       *string `yaml:"memory,omitempty" validate:"megabytestring"`
+	Timeout                      *int64  `yaml:"timeout,omitempty" validate:"omitempty,gte=1"`
+}
+
+// ManifestApplicationService is either the name of a service instance to bind the app to, or a mapping with the name
+// of the instance and the name of the binding
+type ManifestApplicationService struct {
+	Name        string  `yaml:"name" validate:"required"`
+	BindingName *string `yaml:"binding_name,omitempty"`
+}
+
+func (s *ManifestApplicationService) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		s.Name = value.Value
+		return nil
+	}
+
+	type plainService ManifestApplicationService
+	return value.Decode((*plainService)(s))
+}
+
+func (s ManifestApplicationService) MarshalYAML() (interface{}, error) {
+	if s.BindingName == nil {
+		return s.Name, nil
+	}
+
+	type plainService ManifestApplicationService
+	return plainService(s), nil
 }
 
 type ManifestRoute struct {
-	Route *string `yaml:"route" validate:"route"`
+	Route *string `yaml:"route,omitempty" validate:"route"`
 }
 
 func (a ManifestApplication) ToAppCreateMessage(spaceGUID string) repositories.CreateAppMessage {
@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	"gopkg.in/yaml.v3"
 )
 
 var _ = Describe("ManifestApplicationProcess", func() {
@@ -198,3 +199,45 @@ var _ = Describe("ManifestApplicationProcess", func() {
 		})
 	})
 })
+
+var _ = Describe("ManifestApplicationService", func() {
+	var services []ManifestApplicationService
+
+	Describe("unmarshalling", func() {
+		BeforeEach(func() {
+			Expect(yaml.Unmarshal([]byte(`
+- my-instance
+- name: other-instance
+  binding_name: my-binding
+`), &services)).To(Succeed())
+		})
+
+		It("accepts both instance names and mappings", func() {
+			Expect(services).To(Equal([]ManifestApplicationService{
+				{Name: "my-instance"},
+				{Name: "other-instance", BindingName: tools.PtrTo("my-binding")},
+			}))
+		})
+	})
+
+	Describe("marshalling", func() {
+		var out []byte
+
+		BeforeEach(func() {
+			var err error
+			out, err = yaml.Marshal([]ManifestApplicationService{
+				{Name: "my-instance"},
+				{Name: "other-instance", BindingName: tools.PtrTo("my-binding")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("only uses a mapping when there is a binding name", func() {
+			Expect(string(out)).To(MatchYAML(`
+- my-instance
+- name: other-instance
+  binding_name: my-binding
+`))
+		})
+	})
+})
@@ -0,0 +1,117 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type QuotaApps struct {
+	TotalMemoryInMB              *int64 `json:"total_memory_in_mb" validate:"omitempty,gte=0"`
+	PerProcessMemoryInMB         *int64 `json:"per_process_memory_in_mb" validate:"omitempty,gte=0"`
+	TotalInstances               *int64 `json:"total_instances" validate:"omitempty,gte=0"`
+	PerAppTasks                  *int64 `json:"per_app_tasks" validate:"omitempty,gte=0"`
+	LogRateLimitInBytesPerSecond *int64 `json:"log_rate_limit_in_bytes_per_second" validate:"omitempty,gte=0"`
+}
+
+type QuotaServices struct {
+	PaidServicesAllowed   *bool  `json:"paid_services_allowed"`
+	TotalServiceInstances *int64 `json:"total_service_instances" validate:"omitempty,gte=0"`
+	TotalServiceKeys      *int64 `json:"total_service_keys" validate:"omitempty,gte=0"`
+}
+
+type QuotaRoutes struct {
+	TotalRoutes        *int64 `json:"total_routes" validate:"omitempty,gte=0"`
+	TotalReservedPorts *int64 `json:"total_reserved_ports" validate:"omitempty,gte=0"`
+}
+
+type QuotaDomains struct {
+	TotalDomains *int64 `json:"total_domains" validate:"omitempty,gte=0"`
+}
+
+type ToManyRelationship struct {
+	Data []RelationshipData `json:"data" validate:"dive"`
+}
+
+func (r ToManyRelationship) GUIDs() []string {
+	guids := []string{}
+	for _, d := range r.Data {
+		guids = append(guids, d.GUID)
+	}
+
+	return guids
+}
+
+type OrgQuotaCreate struct {
+	Name          string                `json:"name" validate:"required"`
+	Apps          QuotaApps             `json:"apps"`
+	Services      QuotaServices         `json:"services"`
+	Routes        QuotaRoutes           `json:"routes"`
+	Domains       QuotaDomains          `json:"domains"`
+	Relationships OrgQuotaRelationships `json:"relationships"`
+}
+
+type OrgQuotaRelationships struct {
+	Organizations ToManyRelationship `json:"organizations"`
+}
+
+func (p OrgQuotaCreate) ToMessage() repositories.CreateOrgQuotaMessage {
+	return repositories.CreateOrgQuotaMessage{
+		Name:              p.Name,
+		Apps:              p.Apps.toRecord(),
+		Services:          p.Services.toRecord(),
+		Routes:            p.Routes.toRecord(),
+		Domains:           repositories.QuotaDomains{TotalDomains: p.Domains.TotalDomains},
+		OrganizationGUIDs: p.Relationships.Organizations.GUIDs(),
+	}
+}
+
+type OrgQuotaList struct {
+	GUIDs             *string `schema:"guids"`
+	Names             *string `schema:"names"`
+	OrganizationGUIDs *string `schema:"organization_guids"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	PerPage string `schema:"per_page"`
+	Page    string `schema:"page"`
+}
+
+func (l *OrgQuotaList) ToMessage() repositories.ListOrgQuotasMessage {
+	return repositories.ListOrgQuotasMessage{
+		GUIDs:             ParseArrayParam(l.GUIDs),
+		Names:             ParseArrayParam(l.Names),
+		OrganizationGUIDs: ParseArrayParam(l.OrganizationGUIDs),
+	}
+}
+
+func (l *OrgQuotaList) SupportedKeys() []string {
+	return []string{"guids", "names", "organization_guids", "order_by", "per_page", "page"}
+}
+
+func (a QuotaApps) toRecord() repositories.QuotaApps {
+	return repositories.QuotaApps{
+		TotalMemoryInMB:              a.TotalMemoryInMB,
+		PerProcessMemoryInMB:         a.PerProcessMemoryInMB,
+		TotalInstances:               a.TotalInstances,
+		PerAppTasks:                  a.PerAppTasks,
+		LogRateLimitInBytesPerSecond: a.LogRateLimitInBytesPerSecond,
+	}
+}
+
+// toRecord defaults paid_services_allowed to true, as CF does
+func (s QuotaServices) toRecord() repositories.QuotaServices {
+	paidServicesAllowed := true
+	if s.PaidServicesAllowed != nil {
+		paidServicesAllowed = *s.PaidServicesAllowed
+	}
+
+	return repositories.QuotaServices{
+		PaidServicesAllowed:   paidServicesAllowed,
+		TotalServiceInstances: s.TotalServiceInstances,
+		TotalServiceKeys:      s.TotalServiceKeys,
+	}
+}
+
+func (r QuotaRoutes) toRecord() repositories.QuotaRoutes {
+	return repositories.QuotaRoutes{
+		TotalRoutes:        r.TotalRoutes,
+		TotalReservedPorts: r.TotalReservedPorts,
+	}
+}
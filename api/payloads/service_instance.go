@@ -7,12 +7,13 @@ import (
 )
 
 type ServiceInstanceCreate struct {
-	Name          string                       `json:"name" validate:"required"`
-	Type          string                       `json:"type" validate:"required,oneof=user-provided"`
-	Tags          []string                     `json:"tags" validate:"serviceinstancetaglength"`
-	Credentials   map[string]string            `json:"credentials"`
-	Relationships ServiceInstanceRelationships `json:"relationships" validate:"required"`
-	Metadata      Metadata                     `json:"metadata"`
+	Name           string                       `json:"name" validate:"required"`
+	Type           string                       `json:"type" validate:"required,oneof=user-provided"`
+	Tags           []string                     `json:"tags" validate:"serviceinstancetaglength"`
+	Credentials    map[string]string            `json:"credentials"`
+	SyslogDrainURL *string                      `json:"syslog_drain_url" validate:"omitempty,syslogdrainurl"`
+	Relationships  ServiceInstanceRelationships `json:"relationships" validate:"required"`
+	Metadata       Metadata                     `json:"metadata"`
 }
 
 type ServiceInstanceRelationships struct {
@@ -20,7 +21,7 @@ type ServiceInstanceRelationships struct {
 }
 
 func (p ServiceInstanceCreate) ToServiceInstanceCreateMessage() repositories.CreateServiceInstanceMessage {
-	return repositories.CreateServiceInstanceMessage{
+	message := repositories.CreateServiceInstanceMessage{
 		Name:        p.Name,
 		SpaceGUID:   p.Relationships.Space.Data.GUID,
 		Credentials: p.Credentials,
@@ -29,6 +30,34 @@ func (p ServiceInstanceCreate) ToServiceInstanceCreateMessage() repositories.Cre
 		Labels:      p.Metadata.Labels,
 		Annotations: p.Metadata.Annotations,
 	}
+	if p.SyslogDrainURL != nil {
+		message.SyslogDrainURL = *p.SyslogDrainURL
+	}
+
+	return message
+}
+
+type ServiceInstancePatch struct {
+	Name           *string            `json:"name"`
+	Tags           *[]string          `json:"tags" validate:"omitempty,serviceinstancetaglength"`
+	Credentials    *map[string]string `json:"credentials"`
+	SyslogDrainURL *string            `json:"syslog_drain_url" validate:"omitempty,syslogdrainurl"`
+	Metadata       MetadataPatch      `json:"metadata"`
+}
+
+func (p ServiceInstancePatch) ToServiceInstancePatchMessage(spaceGUID, guid string) repositories.PatchServiceInstanceMessage {
+	return repositories.PatchServiceInstanceMessage{
+		GUID:           guid,
+		SpaceGUID:      spaceGUID,
+		Name:           p.Name,
+		Credentials:    p.Credentials,
+		Tags:           p.Tags,
+		SyslogDrainURL: p.SyslogDrainURL,
+		MetadataPatch: repositories.MetadataPatch{
+			Annotations: p.Metadata.Annotations,
+			Labels:      p.Metadata.Labels,
+		},
+	}
 }
 
 type ServiceInstanceList struct {
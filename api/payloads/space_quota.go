@@ -0,0 +1,52 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type SpaceQuotaCreate struct {
+	Name          string                  `json:"name" validate:"required"`
+	Apps          QuotaApps               `json:"apps"`
+	Services      QuotaServices           `json:"services"`
+	Routes        QuotaRoutes             `json:"routes"`
+	Relationships SpaceQuotaRelationships `json:"relationships" validate:"required"`
+}
+
+type SpaceQuotaRelationships struct {
+	Org    Relationship       `json:"organization" validate:"required"`
+	Spaces ToManyRelationship `json:"spaces"`
+}
+
+func (p SpaceQuotaCreate) ToMessage() repositories.CreateSpaceQuotaMessage {
+	return repositories.CreateSpaceQuotaMessage{
+		Name:             p.Name,
+		OrganizationGUID: p.Relationships.Org.Data.GUID,
+		Apps:             p.Apps.toRecord(),
+		Services:         p.Services.toRecord(),
+		Routes:           p.Routes.toRecord(),
+		SpaceGUIDs:       p.Relationships.Spaces.GUIDs(),
+	}
+}
+
+type SpaceQuotaList struct {
+	GUIDs             *string `schema:"guids"`
+	Names             *string `schema:"names"`
+	OrganizationGUIDs *string `schema:"organization_guids"`
+	SpaceGUIDs        *string `schema:"space_guids"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	PerPage string `schema:"per_page"`
+	Page    string `schema:"page"`
+}
+
+func (l *SpaceQuotaList) ToMessage() repositories.ListSpaceQuotasMessage {
+	return repositories.ListSpaceQuotasMessage{
+		GUIDs:             ParseArrayParam(l.GUIDs),
+		Names:             ParseArrayParam(l.Names),
+		OrganizationGUIDs: ParseArrayParam(l.OrganizationGUIDs),
+		SpaceGUIDs:        ParseArrayParam(l.SpaceGUIDs),
+	}
+}
+
+func (l *SpaceQuotaList) SupportedKeys() []string {
+	return []string{"guids", "names", "organization_guids", "space_guids", "order_by", "per_page", "page"}
+}
@@ -0,0 +1,41 @@
+package payloads
+
+import "code.cloudfoundry.org/korifi/api/repositories"
+
+type UserCreate struct {
+	Username string   `json:"username" validate:"required"`
+	Origin   string   `json:"origin"`
+	Metadata Metadata `json:"metadata"`
+}
+
+func (p UserCreate) ToMessage() repositories.CreateUserMessage {
+	return repositories.CreateUserMessage{
+		Username:    p.Username,
+		Origin:      p.Origin,
+		Labels:      p.Metadata.Labels,
+		Annotations: p.Metadata.Annotations,
+	}
+}
+
+type UserList struct {
+	GUIDs     *string `schema:"guids"`
+	Usernames *string `schema:"usernames"`
+	Origins   *string `schema:"origins"`
+
+	// Below parameters are ignored, but must be included to ignore as query parameters
+	OrderBy string `schema:"order_by"`
+	PerPage string `schema:"per_page"`
+	Page    string `schema:"page"`
+}
+
+func (l *UserList) ToMessage() repositories.ListUsersMessage {
+	return repositories.ListUsersMessage{
+		GUIDs:     ParseArrayParam(l.GUIDs),
+		Usernames: ParseArrayParam(l.Usernames),
+		Origins:   ParseArrayParam(l.Origins),
+	}
+}
+
+func (l *UserList) SupportedKeys() []string {
+	return []string{"guids", "usernames", "origins", "order_by", "per_page", "page"}
+}
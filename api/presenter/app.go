@@ -179,11 +179,11 @@ type AppEnvResponse struct {
 	ApplicationEnvJSON   map[string]string      `json:"application_env_json"`
 }
 
-func ForAppEnv(envVarRecord repositories.AppEnvRecord) AppEnvResponse {
+func ForAppEnv(envVarRecord repositories.AppEnvRecord, runningEnvVarGroup, stagingEnvVarGroup repositories.EnvVarGroupRecord) AppEnvResponse {
 	return AppEnvResponse{
 		EnvironmentVariables: envVarRecord.EnvironmentVariables,
-		StagingEnvJSON:       map[string]string{},
-		RunningEnvJSON:       map[string]string{},
+		StagingEnvJSON:       stagingEnvVarGroup.EnvironmentVariables,
+		RunningEnvJSON:       runningEnvVarGroup.EnvironmentVariables,
 		SystemEnvJSON:        envVarRecord.SystemEnv,
 		ApplicationEnvJSON:   map[string]string{},
 	}
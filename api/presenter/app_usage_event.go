@@ -0,0 +1,104 @@
+package presenter
+
+import (
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	appUsageEventsBase = "/v3/app_usage_events"
+)
+
+type AppUsageEventResponse struct {
+	GUID                  string                       `json:"guid"`
+	CreatedAt             string                       `json:"created_at"`
+	UpdatedAt             string                       `json:"updated_at"`
+	State                 AppUsageEventChange[*string] `json:"state"`
+	App                   AppUsageEventResource        `json:"app"`
+	Process               *AppUsageEventProcess        `json:"process"`
+	Task                  *AppUsageEventResource       `json:"task"`
+	Space                 AppUsageEventResource        `json:"space"`
+	Organization          AppUsageEventOrganization    `json:"organization"`
+	MemoryInMBPerInstance AppUsageEventChange[*int64]  `json:"memory_in_mb_per_instance"`
+	InstanceCount         AppUsageEventChange[*int]    `json:"instance_count"`
+	Links                 map[string]Link              `json:"links"`
+}
+
+type AppUsageEventChange[T any] struct {
+	Current  T `json:"current"`
+	Previous T `json:"previous"`
+}
+
+type AppUsageEventResource struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+type AppUsageEventProcess struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+}
+
+type AppUsageEventOrganization struct {
+	GUID string `json:"guid"`
+}
+
+func ForAppUsageEvent(record repositories.AppUsageEventRecord, baseURL url.URL) AppUsageEventResponse {
+	createdAt := record.CreatedAt.UTC().Format(time.RFC3339)
+
+	response := AppUsageEventResponse{
+		GUID:      record.GUID,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		State: AppUsageEventChange[*string]{
+			Current: &record.State,
+		},
+		App: AppUsageEventResource{
+			GUID: record.AppGUID,
+			Name: record.AppName,
+		},
+		Space: AppUsageEventResource{
+			GUID: record.SpaceGUID,
+			Name: record.SpaceName,
+		},
+		Organization: AppUsageEventOrganization{
+			GUID: record.OrganizationGUID,
+		},
+		MemoryInMBPerInstance: AppUsageEventChange[*int64]{
+			Current:  &record.MemoryInMBPerInstance,
+			Previous: record.PreviousMemoryInMBPerInstance,
+		},
+		InstanceCount: AppUsageEventChange[*int]{
+			Current:  &record.InstanceCount,
+			Previous: record.PreviousInstanceCount,
+		},
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(appUsageEventsBase, record.GUID).build(),
+			},
+		},
+	}
+
+	if record.PreviousState != "" {
+		response.State.Previous = &record.PreviousState
+	}
+	if record.ProcessGUID != "" {
+		response.Process = &AppUsageEventProcess{GUID: record.ProcessGUID, Type: record.ProcessType}
+	}
+	if record.TaskGUID != "" {
+		response.Task = &AppUsageEventResource{GUID: record.TaskGUID, Name: record.TaskName}
+	}
+
+	return response
+}
+
+func ForAppUsageEventList(records []repositories.AppUsageEventRecord, baseURL, requestURL url.URL) ListResponse {
+	appUsageEventResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		appUsageEventResponses = append(appUsageEventResponses, ForAppUsageEvent(record, baseURL))
+	}
+
+	return ForList(appUsageEventResponses, baseURL, requestURL)
+}
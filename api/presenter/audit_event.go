@@ -0,0 +1,108 @@
+package presenter
+
+import (
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	auditEventsBase = "/v3/audit_events"
+)
+
+type AuditEventResponse struct {
+	GUID         string                  `json:"guid"`
+	CreatedAt    string                  `json:"created_at"`
+	UpdatedAt    string                  `json:"updated_at"`
+	Type         string                  `json:"type"`
+	Actor        AuditEventParticipant   `json:"actor"`
+	Target       AuditEventParticipant   `json:"target"`
+	Data         AuditEventData          `json:"data"`
+	Space        *AuditEventRelationship `json:"space"`
+	Organization *AuditEventRelationship `json:"organization"`
+	Links        map[string]Link         `json:"links"`
+}
+
+type AuditEventParticipant struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type AuditEventData struct {
+	Request *AuditEventRequest `json:"request,omitempty"`
+	*AuditEventCrashData
+}
+
+type AuditEventCrashData struct {
+	Index           int    `json:"index"`
+	Reason          string `json:"reason"`
+	ExitDescription string `json:"exit_description"`
+	ExitStatus      int32  `json:"exit_status"`
+	CrashCount      int32  `json:"crash_count"`
+	CrashTimestamp  string `json:"crash_timestamp"`
+}
+
+type AuditEventRequest struct {
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	UserAgent      string `json:"user_agent"`
+	CorrelationID  string `json:"correlation_id"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+}
+
+type AuditEventRelationship struct {
+	GUID string `json:"guid"`
+}
+
+func ForAuditEvent(record repositories.AuditEventRecord, baseURL url.URL) AuditEventResponse {
+	createdAt := record.CreatedAt.UTC().Format(time.RFC3339)
+
+	response := AuditEventResponse{
+		GUID:      record.GUID,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Type:      record.Type,
+		Actor:     AuditEventParticipant(record.Actor),
+		Target:    AuditEventParticipant(record.Target),
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(auditEventsBase, record.GUID).build(),
+			},
+		},
+	}
+
+	if record.Request.Method != "" {
+		request := AuditEventRequest(record.Request)
+		response.Data.Request = &request
+	}
+	if crash := record.Crash; crash != nil {
+		response.Data.AuditEventCrashData = &AuditEventCrashData{
+			Index:           crash.Index,
+			Reason:          crash.Reason,
+			ExitDescription: crash.ExitDescription,
+			ExitStatus:      crash.ExitStatus,
+			CrashCount:      crash.CrashCount,
+			CrashTimestamp:  crash.CrashTimestamp.UTC().Format(time.RFC3339),
+		}
+	}
+
+	if record.SpaceGUID != "" {
+		response.Space = &AuditEventRelationship{GUID: record.SpaceGUID}
+	}
+	if record.OrganizationGUID != "" {
+		response.Organization = &AuditEventRelationship{GUID: record.OrganizationGUID}
+	}
+
+	return response
+}
+
+func ForAuditEventList(records []repositories.AuditEventRecord, baseURL, requestURL url.URL) ListResponse {
+	auditEventResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		auditEventResponses = append(auditEventResponses, ForAuditEvent(record, baseURL))
+	}
+
+	return ForList(auditEventResponses, baseURL, requestURL)
+}
@@ -0,0 +1,36 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	envVarGroupsBase = "/v3/environment_variable_groups"
+)
+
+type EnvVarGroupResponse struct {
+	Name      string            `json:"name"`
+	Var       map[string]string `json:"var"`
+	UpdatedAt *string           `json:"updated_at"`
+	Links     map[string]Link   `json:"links"`
+}
+
+func ForEnvVarGroup(record repositories.EnvVarGroupRecord, baseURL url.URL) EnvVarGroupResponse {
+	var updatedAt *string
+	if record.UpdatedAt != "" {
+		updatedAt = &record.UpdatedAt
+	}
+
+	return EnvVarGroupResponse{
+		Name:      record.Name,
+		Var:       record.EnvironmentVariables,
+		UpdatedAt: updatedAt,
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(envVarGroupsBase, record.Name).build(),
+			},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	isolationSegmentsBase = "/v3/isolation_segments"
+)
+
+type IsolationSegmentResponse struct {
+	GUID      string          `json:"guid"`
+	Name      string          `json:"name"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+	Metadata  Metadata        `json:"metadata"`
+	Links     map[string]Link `json:"links"`
+}
+
+type SpaceIsolationSegmentResponse struct {
+	Data  *RelationshipData `json:"data"`
+	Links map[string]Link   `json:"links"`
+}
+
+func ForIsolationSegment(record repositories.IsolationSegmentRecord, baseURL url.URL) IsolationSegmentResponse {
+	return IsolationSegmentResponse{
+		GUID:      record.GUID,
+		Name:      record.Name,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+		Metadata: Metadata{
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(isolationSegmentsBase, record.GUID).build(),
+			},
+			"organizations": {
+				HRef: buildURL(baseURL).appendPath(isolationSegmentsBase, record.GUID, "organizations").build(),
+			},
+		},
+	}
+}
+
+func ForIsolationSegmentList(records []repositories.IsolationSegmentRecord, baseURL, requestURL url.URL) ListResponse {
+	isolationSegmentResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		isolationSegmentResponses = append(isolationSegmentResponses, ForIsolationSegment(record, baseURL))
+	}
+
+	return ForList(isolationSegmentResponses, baseURL, requestURL)
+}
+
+func ForIsolationSegmentOrganizations(record repositories.IsolationSegmentRecord, baseURL url.URL) ToManyRelationshipResponse {
+	return ToManyRelationshipResponse{
+		Data: forToManyRelationship(record.OrganizationGUIDs).Data,
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(isolationSegmentsBase, record.GUID, "relationships", "organizations").build(),
+			},
+		},
+	}
+}
+
+func ForSpaceIsolationSegment(record repositories.SpaceIsolationSegmentRecord, baseURL url.URL) SpaceIsolationSegmentResponse {
+	response := SpaceIsolationSegmentResponse{
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(spacesBase, record.SpaceGUID, "relationships", "isolation_segment").build(),
+			},
+		},
+	}
+
+	if record.IsolationSegmentGUID != "" {
+		response.Data = &RelationshipData{GUID: record.IsolationSegmentGUID}
+		response.Links["related"] = Link{
+			HRef: buildURL(baseURL).appendPath(isolationSegmentsBase, record.IsolationSegmentGUID).build(),
+		}
+	}
+
+	return response
+}
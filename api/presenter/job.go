@@ -10,9 +10,11 @@ const (
 
 	AppDeleteOperation          = "app.delete"
 	OrgDeleteOperation          = "org.delete"
+	OrgQuotaDeleteOperation     = "organization_quota.delete"
 	RouteDeleteOperation        = "route.delete"
 	SpaceApplyManifestOperation = "space.apply_manifest"
 	SpaceDeleteOperation        = "space.delete"
+	SpaceQuotaDeleteOperation   = "space_quota.delete"
 )
 
 type JobResponse struct {
@@ -14,9 +14,11 @@ type LogCacheReadResponseEnvelopes struct {
 }
 
 type LogCacheReadResponseBatch struct {
-	Timestamp int64                   `json:"timestamp"`
-	Log       LogCacheReadResponseLog `json:"log"`
-	Tags      map[string]string       `json:"tags,omitempty"`
+	Timestamp  int64                      `json:"timestamp"`
+	InstanceID string                     `json:"instance_id,omitempty"`
+	Log        *LogCacheReadResponseLog   `json:"log,omitempty"`
+	Gauge      *LogCacheReadResponseGauge `json:"gauge,omitempty"`
+	Tags       map[string]string          `json:"tags,omitempty"`
 }
 
 type LogCacheReadResponseLog struct {
@@ -24,24 +26,47 @@ type LogCacheReadResponseLog struct {
 	Type    loggregator_v2.Log_Type `json:"type"`
 }
 
+type LogCacheReadResponseGauge struct {
+	Metrics map[string]LogCacheReadResponseGaugeValue `json:"metrics"`
+}
+
+type LogCacheReadResponseGaugeValue struct {
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+}
+
 func ForLogs(logRecords []repositories.LogRecord) LogCacheReadResponse {
+	return LogCacheReadResponse{
+		Envelopes: ForLogEnvelopes(logRecords),
+	}
+}
+
+// ForLogEnvelopes presents a batch of log records as it is sent in each event of a log stream
+func ForLogEnvelopes(logRecords []repositories.LogRecord) LogCacheReadResponseEnvelopes {
 	envelopes := make([]LogCacheReadResponseBatch, 0, len(logRecords))
 	for _, logRecord := range logRecords {
 		batch := LogCacheReadResponseBatch{
-			Timestamp: logRecord.Timestamp,
-			Log: LogCacheReadResponseLog{
+			Timestamp:  logRecord.Timestamp,
+			InstanceID: logRecord.InstanceID,
+			Tags:       logRecord.Tags,
+		}
+
+		if logRecord.Gauge != nil {
+			batch.Gauge = &LogCacheReadResponseGauge{Metrics: map[string]LogCacheReadResponseGaugeValue{}}
+			for name, metric := range logRecord.Gauge {
+				batch.Gauge.Metrics[name] = LogCacheReadResponseGaugeValue{Unit: metric.Unit, Value: metric.Value}
+			}
+		} else {
+			batch.Log = &LogCacheReadResponseLog{
 				Payload: []byte(logRecord.Message),
 				Type:    loggregator_v2.Log_OUT,
-			},
-			Tags: logRecord.Tags,
+			}
 		}
 
 		envelopes = append(envelopes, batch)
 	}
 
-	return LogCacheReadResponse{
-		Envelopes: LogCacheReadResponseEnvelopes{
-			Batch: envelopes,
-		},
+	return LogCacheReadResponseEnvelopes{
+		Batch: envelopes,
 	}
 }
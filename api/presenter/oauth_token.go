@@ -0,0 +1,39 @@
+package presenter
+
+import (
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/oauth"
+)
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+	JTI          string `json:"jti"`
+}
+
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func ForOAuthToken(token oauth.Token) OAuthTokenResponse {
+	return OAuthTokenResponse{
+		AccessToken:  token.AccessToken,
+		TokenType:    "bearer",
+		RefreshToken: token.RefreshToken,
+		ExpiresIn:    token.ExpiresIn,
+		Scope:        strings.Join(token.Scope, " "),
+		JTI:          token.JTI,
+	}
+}
+
+func ForOAuthError(err oauth.Error) OAuthErrorResponse {
+	return OAuthErrorResponse{
+		Error:            err.Code(),
+		ErrorDescription: err.Description(),
+	}
+}
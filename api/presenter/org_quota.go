@@ -0,0 +1,137 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	orgQuotasBase = "/v3/organization_quotas"
+)
+
+type OrgQuotaResponse struct {
+	GUID          string                `json:"guid"`
+	Name          string                `json:"name"`
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+	Apps          QuotaAppsResponse     `json:"apps"`
+	Services      QuotaServicesResponse `json:"services"`
+	Routes        QuotaRoutesResponse   `json:"routes"`
+	Domains       QuotaDomainsResponse  `json:"domains"`
+	Relationships OrgQuotaRelationships `json:"relationships"`
+	Links         map[string]Link       `json:"links"`
+}
+
+type QuotaAppsResponse struct {
+	TotalMemoryInMB              *int64 `json:"total_memory_in_mb"`
+	PerProcessMemoryInMB         *int64 `json:"per_process_memory_in_mb"`
+	TotalInstances               *int64 `json:"total_instances"`
+	PerAppTasks                  *int64 `json:"per_app_tasks"`
+	LogRateLimitInBytesPerSecond *int64 `json:"log_rate_limit_in_bytes_per_second"`
+}
+
+type QuotaServicesResponse struct {
+	PaidServicesAllowed   bool   `json:"paid_services_allowed"`
+	TotalServiceInstances *int64 `json:"total_service_instances"`
+	TotalServiceKeys      *int64 `json:"total_service_keys"`
+}
+
+type QuotaRoutesResponse struct {
+	TotalRoutes        *int64 `json:"total_routes"`
+	TotalReservedPorts *int64 `json:"total_reserved_ports"`
+}
+
+type QuotaDomainsResponse struct {
+	TotalDomains *int64 `json:"total_domains"`
+}
+
+type OrgQuotaRelationships struct {
+	Organizations ToManyRelationship `json:"organizations"`
+}
+
+type ToManyRelationship struct {
+	Data []RelationshipData `json:"data"`
+}
+
+type ToManyRelationshipResponse struct {
+	Data  []RelationshipData `json:"data"`
+	Links map[string]Link    `json:"links"`
+}
+
+func ForOrgQuota(record repositories.OrgQuotaRecord, baseURL url.URL) OrgQuotaResponse {
+	return OrgQuotaResponse{
+		GUID:      record.GUID,
+		Name:      record.Name,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+		Apps:      forQuotaApps(record.Apps),
+		Services:  forQuotaServices(record.Services),
+		Routes:    forQuotaRoutes(record.Routes),
+		Domains: QuotaDomainsResponse{
+			TotalDomains: record.Domains.TotalDomains,
+		},
+		Relationships: OrgQuotaRelationships{
+			Organizations: forToManyRelationship(record.OrganizationGUIDs),
+		},
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(orgQuotasBase, record.GUID).build(),
+			},
+		},
+	}
+}
+
+func ForOrgQuotaList(records []repositories.OrgQuotaRecord, baseURL, requestURL url.URL) ListResponse {
+	orgQuotaResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		orgQuotaResponses = append(orgQuotaResponses, ForOrgQuota(record, baseURL))
+	}
+
+	return ForList(orgQuotaResponses, baseURL, requestURL)
+}
+
+func ForOrgQuotaOrganizations(record repositories.OrgQuotaRecord, baseURL url.URL) ToManyRelationshipResponse {
+	return ToManyRelationshipResponse{
+		Data: forToManyRelationship(record.OrganizationGUIDs).Data,
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(orgQuotasBase, record.GUID, "relationships", "organizations").build(),
+			},
+		},
+	}
+}
+
+func forQuotaApps(apps repositories.QuotaApps) QuotaAppsResponse {
+	return QuotaAppsResponse{
+		TotalMemoryInMB:              apps.TotalMemoryInMB,
+		PerProcessMemoryInMB:         apps.PerProcessMemoryInMB,
+		TotalInstances:               apps.TotalInstances,
+		PerAppTasks:                  apps.PerAppTasks,
+		LogRateLimitInBytesPerSecond: apps.LogRateLimitInBytesPerSecond,
+	}
+}
+
+func forQuotaServices(services repositories.QuotaServices) QuotaServicesResponse {
+	return QuotaServicesResponse{
+		PaidServicesAllowed:   services.PaidServicesAllowed,
+		TotalServiceInstances: services.TotalServiceInstances,
+		TotalServiceKeys:      services.TotalServiceKeys,
+	}
+}
+
+func forQuotaRoutes(routes repositories.QuotaRoutes) QuotaRoutesResponse {
+	return QuotaRoutesResponse{
+		TotalRoutes:        routes.TotalRoutes,
+		TotalReservedPorts: routes.TotalReservedPorts,
+	}
+}
+
+func forToManyRelationship(guids []string) ToManyRelationship {
+	data := make([]RelationshipData, 0, len(guids))
+	for _, guid := range guids {
+		data = append(data, RelationshipData{GUID: guid})
+	}
+
+	return ToManyRelationship{Data: data}
+}
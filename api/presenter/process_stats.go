@@ -1,6 +1,9 @@
 package presenter
 
 import (
+	"fmt"
+	"time"
+
 	"code.cloudfoundry.org/korifi/api/repositories"
 )
 
@@ -15,12 +18,12 @@ type ProcessStatsResource struct {
 	Usage            ProcessUsage           `json:"usage"`
 	Host             *string                `json:"host"`
 	InstancePorts    *[]ProcessInstancePort `json:"instance_ports,omitempty"`
-	Uptime           *int                   `json:"uptime"`
+	Uptime           *int64                 `json:"uptime"`
 	MemQuota         *int                   `json:"mem_quota"`
 	DiskQuota        *int                   `json:"disk_quota"`
 	FDSQuota         *int                   `json:"fds_quota"`
 	IsolationSegment *string                `json:"isolation_segment"`
-	Details          *ProcessDetails        `json:"details"`
+	Details          *string                `json:"details"`
 }
 
 type ProcessUsage struct {
@@ -37,8 +40,6 @@ type ProcessInstancePort struct {
 	InternalTLSProxyPort int `json:"internal_tls_proxy_port"`
 }
 
-type ProcessDetails struct{}
-
 func ForProcessStats(records []repositories.PodStatsRecord) ProcessStatsResponse {
 	resources := []ProcessStatsResource{}
 	for _, record := range records {
@@ -63,5 +64,21 @@ func statRecordToResource(record repositories.PodStatsRecord) ProcessStatsResour
 			Mem:  record.Usage.Mem,
 			Disk: record.Usage.Disk,
 		},
+		Uptime:  record.Uptime,
+		Details: restartDetails(record),
+	}
+}
+
+func restartDetails(record repositories.PodStatsRecord) *string {
+	if record.Restarts == 0 && record.LastCrash == nil {
+		return nil
+	}
+
+	details := fmt.Sprintf("Restarted %d times", record.Restarts)
+	if crash := record.LastCrash; crash != nil {
+		details = fmt.Sprintf("%s, last crash: %s (exit code %d) at %s",
+			details, crash.Reason, crash.ExitCode, crash.CrashedAt.UTC().Format(time.RFC3339))
 	}
+
+	return &details
 }
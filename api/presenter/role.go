@@ -23,6 +23,7 @@ type RoleResponse struct {
 
 type RoleLinks struct {
 	Self         *Link `json:"self"`
+	User         *Link `json:"user,omitempty"`
 	Space        *Link `json:"space,omitempty"`
 	Organization *Link `json:"organization,omitempty"`
 }
@@ -49,6 +50,13 @@ func toRoleResponse(role repositories.RoleRecord, apiBaseURL url.URL) RoleRespon
 		},
 	}
 
+	if role.UserGUID != "" {
+		resp.Relationships["user"] = Relationship{Data: &RelationshipData{GUID: role.UserGUID}}
+		resp.Links.User = &Link{
+			HRef: buildURL(apiBaseURL).appendPath(usersBase, role.UserGUID).build(),
+		}
+	}
+
 	if role.Org != "" {
 		resp.Relationships["organization"] = Relationship{Data: &RelationshipData{GUID: role.Org}}
 		resp.Links.Organization = &Link{
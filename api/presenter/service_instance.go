@@ -49,6 +49,11 @@ func ForServiceInstance(serviceInstanceRecord repositories.ServiceInstanceRecord
 		lastOperationType = "create"
 	}
 
+	var syslogDrainURL *string
+	if serviceInstanceRecord.SyslogDrainURL != "" {
+		syslogDrainURL = &serviceInstanceRecord.SyslogDrainURL
+	}
+
 	return ServiceInstanceResponse{
 		Name: serviceInstanceRecord.Name,
 		GUID: serviceInstanceRecord.GUID,
@@ -61,8 +66,9 @@ func ForServiceInstance(serviceInstanceRecord repositories.ServiceInstanceRecord
 			State:       "succeeded",
 			Type:        lastOperationType,
 		},
-		CreatedAt: serviceInstanceRecord.CreatedAt,
-		UpdatedAt: serviceInstanceRecord.UpdatedAt,
+		SyslogDrainURL: syslogDrainURL,
+		CreatedAt:      serviceInstanceRecord.CreatedAt,
+		UpdatedAt:      serviceInstanceRecord.UpdatedAt,
 		Relationships: Relationships{
 			"space": Relationship{
 				Data: &RelationshipData{
@@ -0,0 +1,76 @@
+package presenter
+
+import (
+	"net/url"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	spaceQuotasBase = "/v3/space_quotas"
+)
+
+type SpaceQuotaResponse struct {
+	GUID          string                  `json:"guid"`
+	Name          string                  `json:"name"`
+	CreatedAt     string                  `json:"created_at"`
+	UpdatedAt     string                  `json:"updated_at"`
+	Apps          QuotaAppsResponse       `json:"apps"`
+	Services      QuotaServicesResponse   `json:"services"`
+	Routes        QuotaRoutesResponse     `json:"routes"`
+	Relationships SpaceQuotaRelationships `json:"relationships"`
+	Links         map[string]Link         `json:"links"`
+}
+
+type SpaceQuotaRelationships struct {
+	Organization Relationship       `json:"organization"`
+	Spaces       ToManyRelationship `json:"spaces"`
+}
+
+func ForSpaceQuota(record repositories.SpaceQuotaRecord, baseURL url.URL) SpaceQuotaResponse {
+	return SpaceQuotaResponse{
+		GUID:      record.GUID,
+		Name:      record.Name,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+		Apps:      forQuotaApps(record.Apps),
+		Services:  forQuotaServices(record.Services),
+		Routes:    forQuotaRoutes(record.Routes),
+		Relationships: SpaceQuotaRelationships{
+			Organization: Relationship{
+				Data: &RelationshipData{
+					GUID: record.OrganizationGUID,
+				},
+			},
+			Spaces: forToManyRelationship(record.SpaceGUIDs),
+		},
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(spaceQuotasBase, record.GUID).build(),
+			},
+			"organization": {
+				HRef: buildURL(baseURL).appendPath(orgsBase, record.OrganizationGUID).build(),
+			},
+		},
+	}
+}
+
+func ForSpaceQuotaList(records []repositories.SpaceQuotaRecord, baseURL, requestURL url.URL) ListResponse {
+	spaceQuotaResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		spaceQuotaResponses = append(spaceQuotaResponses, ForSpaceQuota(record, baseURL))
+	}
+
+	return ForList(spaceQuotaResponses, baseURL, requestURL)
+}
+
+func ForSpaceQuotaSpaces(record repositories.SpaceQuotaRecord, baseURL url.URL) ToManyRelationshipResponse {
+	return ToManyRelationshipResponse{
+		Data: forToManyRelationship(record.SpaceGUIDs).Data,
+		Links: map[string]Link{
+			"self": {
+				HRef: buildURL(baseURL).appendPath(spaceQuotasBase, record.GUID, "relationships", "spaces").build(),
+			},
+		},
+	}
+}
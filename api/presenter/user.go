@@ -0,0 +1,56 @@
+package presenter
+
+import (
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	usersBase = "/v3/users"
+)
+
+type UserResponse struct {
+	GUID             string    `json:"guid"`
+	CreatedAt        string    `json:"created_at"`
+	UpdatedAt        string    `json:"updated_at"`
+	Username         string    `json:"username"`
+	PresentationName string    `json:"presentation_name"`
+	Origin           string    `json:"origin"`
+	Metadata         Metadata  `json:"metadata"`
+	Links            UserLinks `json:"links"`
+}
+
+type UserLinks struct {
+	Self *Link `json:"self"`
+}
+
+func ForUser(record repositories.UserRecord, baseURL url.URL) UserResponse {
+	return UserResponse{
+		GUID:             record.GUID,
+		CreatedAt:        record.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:        record.UpdatedAt.UTC().Format(time.RFC3339),
+		Username:         record.Username,
+		PresentationName: record.Username,
+		Origin:           record.Origin,
+		Metadata: Metadata{
+			Labels:      emptyMapIfNil(record.Labels),
+			Annotations: emptyMapIfNil(record.Annotations),
+		},
+		Links: UserLinks{
+			Self: &Link{
+				HRef: buildURL(baseURL).appendPath(usersBase, record.GUID).build(),
+			},
+		},
+	}
+}
+
+func ForUserList(records []repositories.UserRecord, baseURL, requestURL url.URL) ListResponse {
+	userResponses := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		userResponses = append(userResponses, ForUser(record, baseURL))
+	}
+
+	return ForList(userResponses, baseURL, requestURL)
+}
@@ -191,7 +191,7 @@ func (f *AppRepo) GetApp(ctx context.Context, authInfo authorization.Info, appGU
 		return AppRecord{}, err
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("get-app failed to build user client: %w", err)
 	}
@@ -206,7 +206,7 @@ func (f *AppRepo) GetApp(ctx context.Context, authInfo authorization.Info, appGU
 }
 
 func (f *AppRepo) GetAppByNameAndSpace(ctx context.Context, authInfo authorization.Info, appName string, spaceGUID string) (AppRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("get-app failed to build user client: %w", err)
 	}
@@ -235,7 +235,7 @@ func (f *AppRepo) GetAppByNameAndSpace(ctx context.Context, authInfo authorizati
 }
 
 func (f *AppRepo) CreateApp(ctx context.Context, authInfo authorization.Info, appCreateMessage CreateAppMessage) (AppRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -266,7 +266,7 @@ func (f *AppRepo) CreateApp(ctx context.Context, authInfo authorization.Info, ap
 }
 
 func (f *AppRepo) PatchApp(ctx context.Context, authInfo authorization.Info, appPatchMessage PatchAppMessage) (AppRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -304,7 +304,7 @@ func (f *AppRepo) ListApps(ctx context.Context, authInfo authorization.Info, mes
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []AppRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -416,7 +416,7 @@ func (f *AppRepo) PatchAppEnvVars(ctx context.Context, authInfo authorization.In
 		},
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppEnvVarsRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -442,7 +442,7 @@ func (f *AppRepo) PatchAppEnvVars(ctx context.Context, authInfo authorization.In
 func (f *AppRepo) CreateOrPatchAppEnvVars(ctx context.Context, authInfo authorization.Info, envVariables CreateOrPatchAppEnvVarsMessage) (AppEnvVarsRecord, error) {
 	secretObj := appEnvVarsRecordToSecret(envVariables)
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppEnvVarsRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -459,7 +459,7 @@ func (f *AppRepo) CreateOrPatchAppEnvVars(ctx context.Context, authInfo authoriz
 }
 
 func (f *AppRepo) PatchAppMetadata(ctx context.Context, authInfo authorization.Info, message PatchAppMetadataMessage) (AppRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -479,7 +479,7 @@ func (f *AppRepo) PatchAppMetadata(ctx context.Context, authInfo authorization.I
 }
 
 func (f *AppRepo) SetCurrentDroplet(ctx context.Context, authInfo authorization.Info, message SetCurrentDropletMessage) (CurrentDropletRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return CurrentDropletRecord{}, fmt.Errorf("set-current-droplet: failed to create k8s user client: %w", err)
 	}
@@ -510,7 +510,7 @@ func (f *AppRepo) SetCurrentDroplet(ctx context.Context, authInfo authorization.
 }
 
 func (f *AppRepo) SetAppDesiredState(ctx context.Context, authInfo authorization.Info, message SetAppDesiredStateMessage) (AppRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -539,7 +539,7 @@ func (f *AppRepo) DeleteApp(ctx context.Context, authInfo authorization.Info, me
 			Namespace: message.SpaceGUID,
 		},
 	}
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -553,7 +553,7 @@ func (f *AppRepo) GetAppEnv(ctx context.Context, authInfo authorization.Info, ap
 		return AppEnvRecord{}, err
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return AppEnvRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -1263,6 +1263,18 @@ var _ = Describe("AppRepository", func() {
 			})
 		})
 
+		When("the user is a space supporter", func() {
+			BeforeEach(func() {
+				createRoleBinding(testCtx, userName, spaceSupporterRole.Name, cfSpace.Name)
+				initialAppState = appStoppedValue
+			})
+
+			It("can start the app", func() {
+				Expect(returnedErr).NotTo(HaveOccurred())
+				Expect(returnedAppRecord.State).To(Equal(DesiredState("STARTED")))
+			})
+		})
+
 		When("not allowed to set the application state", func() {
 			It("returns a forbidden error", func() {
 				Expect(returnedErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
@@ -1483,6 +1495,16 @@ var _ = Describe("AppRepository", func() {
 			})
 		})
 
+		When("the user is a space supporter", func() {
+			BeforeEach(func() {
+				createRoleBinding(testCtx, userName, spaceSupporterRole.Name, cfSpace.Name)
+			})
+
+			It("cannot read the environment", func() {
+				Expect(getAppEnvErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
 		When("the user doesn't have permission to get secrets in the space", func() {
 			It("errors", func() {
 				Expect(getAppEnvErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
@@ -0,0 +1,336 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	AppUsageEventResourceType = "App Usage Event"
+)
+
+type AppUsageEventRecord struct {
+	GUID                          string
+	CreatedAt                     time.Time
+	State                         string
+	PreviousState                 string
+	AppGUID                       string
+	AppName                       string
+	ProcessGUID                   string
+	ProcessType                   string
+	TaskGUID                      string
+	TaskName                      string
+	SpaceGUID                     string
+	SpaceName                     string
+	OrganizationGUID              string
+	InstanceCount                 int
+	PreviousInstanceCount         *int
+	MemoryInMBPerInstance         int64
+	PreviousMemoryInMBPerInstance *int64
+}
+
+type ListAppUsageEventsMessage struct {
+	GUIDs     []string
+	AfterGUID string
+	Limit     int
+}
+
+type AppUsageEventRepo struct {
+	rootNamespace     string
+	userClientFactory authorization.UserK8sClientFactory
+	nsPerms           *authorization.NamespacePermissions
+}
+
+func NewAppUsageEventRepo(
+	rootNamespace string,
+	userClientFactory authorization.UserK8sClientFactory,
+	nsPerms *authorization.NamespacePermissions,
+) *AppUsageEventRepo {
+	return &AppUsageEventRepo{
+		rootNamespace:     rootNamespace,
+		userClientFactory: userClientFactory,
+		nsPerms:           nsPerms,
+	}
+}
+
+func (r *AppUsageEventRepo) GetAppUsageEvent(ctx context.Context, authInfo authorization.Info, guid string) (AppUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return AppUsageEventRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfAppUsageEvent := &korifiv1alpha1.CFAppUsageEvent{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, cfAppUsageEvent)
+	if err != nil {
+		return AppUsageEventRecord{}, fmt.Errorf("failed to get app usage event: %w", apierrors.FromK8sError(err, AppUsageEventResourceType))
+	}
+
+	return cfAppUsageEventToRecord(*cfAppUsageEvent), nil
+}
+
+// ListAppUsageEvents returns the events in the order they were recorded. When AfterGUID is set, only the events
+// recorded after that event are returned, which allows consumers to page through the events with a cursor. Events are
+// ordered by the resource version they were stored with rather than by the time of the usage transition, so that events
+// recorded late (e.g. when the controller retries) still come after the cursor. Events are never updated, and every
+// write gets a higher resource version, so unlike creation timestamps the resource versions never tie
+func (r *AppUsageEventRepo) ListAppUsageEvents(ctx context.Context, authInfo authorization.Info, message ListAppUsageEventsMessage) ([]AppUsageEventRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfAppUsageEventList := &korifiv1alpha1.CFAppUsageEventList{}
+	err = userClient.List(ctx, cfAppUsageEventList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list app usage events: %w", apierrors.FromK8sError(err, AppUsageEventResourceType))
+	}
+
+	events, err := sortByStoreOrder(cfAppUsageEventList.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.AfterGUID != "" {
+		events, err = eventsAfter(events, message.AfterGUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records := []AppUsageEventRecord{}
+	for _, event := range events {
+		if !matchesFilter(event.Name, message.GUIDs) {
+			continue
+		}
+		if message.Limit > 0 && len(records) == message.Limit {
+			break
+		}
+		records = append(records, cfAppUsageEventToRecord(event))
+	}
+
+	return records, nil
+}
+
+func sortByStoreOrder(events []korifiv1alpha1.CFAppUsageEvent) ([]korifiv1alpha1.CFAppUsageEvent, error) {
+	versions := map[string]uint64{}
+	for _, event := range events {
+		version, err := strconv.ParseUint(event.ResourceVersion, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the resource version of app usage event %q: %w", event.Name, err)
+		}
+		versions[event.Name] = version
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return versions[events[i].Name] < versions[events[j].Name]
+	})
+
+	return events, nil
+}
+
+func eventsAfter(events []korifiv1alpha1.CFAppUsageEvent, afterGUID string) ([]korifiv1alpha1.CFAppUsageEvent, error) {
+	for i, event := range events {
+		if event.Name == afterGUID {
+			return events[i+1:], nil
+		}
+	}
+
+	return nil, apierrors.NewUnprocessableEntityError(
+		fmt.Errorf("app usage event %q not found", afterGUID),
+		"After guid filter must be a valid app usage event guid.",
+	)
+}
+
+// PurgeAndReseed deletes all app usage events, then records a STARTED event for each started process and a
+// TASK_STARTED event for each running task, so that consumers can start over from a consistent baseline
+func (r *AppUsageEventRepo) PurgeAndReseed(ctx context.Context, authInfo authorization.Info) error {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.DeleteAllOf(ctx, &korifiv1alpha1.CFAppUsageEvent{}, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		return fmt.Errorf("failed to purge app usage events: %w", apierrors.FromK8sError(err, AppUsageEventResourceType))
+	}
+
+	spaces, err := r.listSpaces(ctx, authInfo, userClient)
+	if err != nil {
+		return err
+	}
+
+	spaceNamespaces, err := r.nsPerms.GetAuthorizedSpaceNamespaces(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
+	}
+
+	for ns := range spaceNamespaces {
+		err = r.reseedSpace(ctx, userClient, ns, spaces[ns])
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *AppUsageEventRepo) listSpaces(ctx context.Context, authInfo authorization.Info, userClient client.Client) (map[string]korifiv1alpha1.CFSpace, error) {
+	orgNamespaces, err := r.nsPerms.GetAuthorizedOrgNamespaces(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces for orgs with user role bindings: %w", err)
+	}
+
+	spaces := map[string]korifiv1alpha1.CFSpace{}
+	for ns := range orgNamespaces {
+		cfSpaceList := &korifiv1alpha1.CFSpaceList{}
+		err = userClient.List(ctx, cfSpaceList, client.InNamespace(ns))
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list spaces in namespace %s: %w", ns, apierrors.FromK8sError(err, SpaceResourceType))
+		}
+
+		for _, cfSpace := range cfSpaceList.Items {
+			spaces[cfSpace.Name] = cfSpace
+		}
+	}
+
+	return spaces, nil
+}
+
+// reseedSpace records the usage of the apps in the space namespace. The CFSpace is empty when the user cannot see
+// it in its org namespace, in which case the space name and org are left out of the events
+func (r *AppUsageEventRepo) reseedSpace(ctx context.Context, userClient client.Client, spaceGUID string, space korifiv1alpha1.CFSpace) error {
+	cfAppList := &korifiv1alpha1.CFAppList{}
+	if err := userClient.List(ctx, cfAppList, client.InNamespace(spaceGUID)); err != nil {
+		return fmt.Errorf("failed to list apps in namespace %s: %w", spaceGUID, err)
+	}
+
+	cfProcessList := &korifiv1alpha1.CFProcessList{}
+	if err := userClient.List(ctx, cfProcessList, client.InNamespace(spaceGUID)); err != nil {
+		return fmt.Errorf("failed to list processes in namespace %s: %w", spaceGUID, err)
+	}
+
+	cfTaskList := &korifiv1alpha1.CFTaskList{}
+	if err := userClient.List(ctx, cfTaskList, client.InNamespace(spaceGUID)); err != nil {
+		return fmt.Errorf("failed to list tasks in namespace %s: %w", spaceGUID, err)
+	}
+
+	apps := map[string]korifiv1alpha1.CFApp{}
+	for _, cfApp := range cfAppList.Items {
+		apps[cfApp.Name] = cfApp
+	}
+
+	for _, cfProcess := range cfProcessList.Items {
+		cfApp, ok := apps[cfProcess.Spec.AppRef.Name]
+		if !ok || cfApp.Status.ObservedDesiredState != korifiv1alpha1.StartedState {
+			continue
+		}
+
+		instances := 0
+		if cfProcess.Spec.DesiredInstances != nil {
+			instances = *cfProcess.Spec.DesiredInstances
+		}
+
+		err := r.createEvent(ctx, userClient, space, cfApp, korifiv1alpha1.CFAppUsageEventSpec{
+			State:                 korifiv1alpha1.AppUsageStateStarted,
+			Process:               &korifiv1alpha1.AppUsageEventResource{GUID: cfProcess.Name, Name: cfProcess.Spec.ProcessType},
+			InstanceCount:         instances,
+			MemoryInMBPerInstance: cfProcess.Spec.MemoryMB,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, cfTask := range cfTaskList.Items {
+		cfApp, ok := apps[cfTask.Spec.AppRef.Name]
+		if !ok || !isTaskRunning(cfTask) {
+			continue
+		}
+
+		err := r.createEvent(ctx, userClient, space, cfApp, korifiv1alpha1.CFAppUsageEventSpec{
+			State:                 korifiv1alpha1.AppUsageStateTaskStarted,
+			Task:                  &korifiv1alpha1.AppUsageEventResource{GUID: cfTask.Name, Name: cfTask.Name},
+			InstanceCount:         1,
+			MemoryInMBPerInstance: cfTask.Status.MemoryMB,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isTaskRunning(cfTask korifiv1alpha1.CFTask) bool {
+	return meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskStartedConditionType) &&
+		!meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskSucceededConditionType) &&
+		!meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskFailedConditionType)
+}
+
+func (r *AppUsageEventRepo) createEvent(ctx context.Context, userClient client.Client, space korifiv1alpha1.CFSpace, cfApp korifiv1alpha1.CFApp, spec korifiv1alpha1.CFAppUsageEventSpec) error {
+	spec.App = korifiv1alpha1.AppUsageEventResource{GUID: cfApp.Name, Name: cfApp.Spec.DisplayName}
+	spec.Space = korifiv1alpha1.AppUsageEventResource{GUID: cfApp.Namespace, Name: space.Spec.DisplayName}
+	spec.Organization = korifiv1alpha1.AppUsageEventResource{GUID: space.Namespace}
+	spec.Timestamp = metav1.NewMicroTime(time.Now())
+
+	err := userClient.Create(ctx, &korifiv1alpha1.CFAppUsageEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewString(),
+			Namespace: r.rootNamespace,
+		},
+		Spec: spec,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create app usage event: %w", apierrors.FromK8sError(err, AppUsageEventResourceType))
+	}
+
+	return nil
+}
+
+func cfAppUsageEventToRecord(event korifiv1alpha1.CFAppUsageEvent) AppUsageEventRecord {
+	record := AppUsageEventRecord{
+		GUID:                          event.Name,
+		CreatedAt:                     event.Spec.Timestamp.Time,
+		State:                         event.Spec.State,
+		PreviousState:                 event.Spec.PreviousState,
+		AppGUID:                       event.Spec.App.GUID,
+		AppName:                       event.Spec.App.Name,
+		SpaceGUID:                     event.Spec.Space.GUID,
+		SpaceName:                     event.Spec.Space.Name,
+		OrganizationGUID:              event.Spec.Organization.GUID,
+		InstanceCount:                 event.Spec.InstanceCount,
+		PreviousInstanceCount:         event.Spec.PreviousInstanceCount,
+		MemoryInMBPerInstance:         event.Spec.MemoryInMBPerInstance,
+		PreviousMemoryInMBPerInstance: event.Spec.PreviousMemoryInMBPerInstance,
+	}
+
+	if event.Spec.Process != nil {
+		record.ProcessGUID = event.Spec.Process.GUID
+		record.ProcessType = event.Spec.Process.Name
+	}
+	if event.Spec.Task != nil {
+		record.TaskGUID = event.Spec.Task.GUID
+		record.TaskName = event.Spec.Task.Name
+	}
+
+	return record
+}
@@ -0,0 +1,100 @@
+package repositories_test
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	. "code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tests/matchers"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("AppUsageEventRepository", func() {
+	var (
+		testCtx           context.Context
+		appUsageEventRepo *AppUsageEventRepo
+	)
+
+	createEvent := func(name string, timestamp time.Time) *korifiv1alpha1.CFAppUsageEvent {
+		event := &korifiv1alpha1.CFAppUsageEvent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: rootNamespace,
+			},
+			Spec: korifiv1alpha1.CFAppUsageEventSpec{
+				State:     korifiv1alpha1.AppUsageStateStarted,
+				App:       korifiv1alpha1.AppUsageEventResource{GUID: "app-guid", Name: "app"},
+				Space:     korifiv1alpha1.AppUsageEventResource{GUID: "space-guid"},
+				Timestamp: metav1.NewMicroTime(timestamp),
+			},
+		}
+		Expect(k8sClient.Create(testCtx, event)).To(Succeed())
+
+		return event
+	}
+
+	BeforeEach(func() {
+		testCtx = context.Background()
+		appUsageEventRepo = NewAppUsageEventRepo(rootNamespace, userClientFactory, nsPerms)
+	})
+
+	Describe("ListAppUsageEvents", func() {
+		var (
+			recordedEvent *korifiv1alpha1.CFAppUsageEvent
+			lateEvent     *korifiv1alpha1.CFAppUsageEvent
+			message       ListAppUsageEventsMessage
+			records       []AppUsageEventRecord
+			listErr       error
+		)
+
+		BeforeEach(func() {
+			createRoleBinding(testCtx, userName, adminRole.Name, rootNamespace)
+
+			// the late event is stored within the same second as the recorded one, and its name sorts before it
+			recordedEvent = createEvent("b-"+uuid.NewString(), time.Now())
+			lateEvent = createEvent("a-"+uuid.NewString(), time.Now().Add(-time.Minute))
+
+			message = ListAppUsageEventsMessage{GUIDs: []string{recordedEvent.Name, lateEvent.Name}}
+		})
+
+		JustBeforeEach(func() {
+			records, listErr = appUsageEventRepo.ListAppUsageEvents(testCtx, authInfo, message)
+		})
+
+		It("returns the events in the order they were stored", func() {
+			Expect(listErr).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].GUID).To(Equal(recordedEvent.Name))
+			Expect(records[1].GUID).To(Equal(lateEvent.Name))
+		})
+
+		When("listing the events after a cursor", func() {
+			BeforeEach(func() {
+				message.AfterGUID = recordedEvent.Name
+			})
+
+			It("returns the events stored after it, even if they happened before it", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(records).To(HaveLen(1))
+				Expect(records[0].GUID).To(Equal(lateEvent.Name))
+				Expect(records[0].CreatedAt).To(BeTemporally("<", recordedEvent.Spec.Timestamp.Time))
+			})
+		})
+
+		When("the cursor does not exist", func() {
+			BeforeEach(func() {
+				message.AfterGUID = "not-an-event"
+			})
+
+			It("returns an unprocessable entity error", func() {
+				Expect(listErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+			})
+		})
+	})
+})
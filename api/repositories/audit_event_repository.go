@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=create
+
+const (
+	AuditEventResourceType = "Audit Event"
+)
+
+type AuditEventParticipant struct {
+	GUID string
+	Type string
+	Name string
+}
+
+type AuditEventRequest struct {
+	Method         string
+	Path           string
+	UserAgent      string
+	CorrelationID  string
+	ImpersonatedBy string
+}
+
+type AuditEventCrash struct {
+	Index           int
+	Reason          string
+	ExitDescription string
+	ExitStatus      int32
+	CrashCount      int32
+	CrashTimestamp  time.Time
+}
+
+type AuditEventRecord struct {
+	GUID             string
+	Type             string
+	Actor            AuditEventParticipant
+	Target           AuditEventParticipant
+	SpaceGUID        string
+	OrganizationGUID string
+	Request          AuditEventRequest
+	Crash            *AuditEventCrash
+	CreatedAt        time.Time
+}
+
+type CreateAuditEventMessage struct {
+	Type             string
+	Actor            AuditEventParticipant
+	Target           AuditEventParticipant
+	SpaceGUID        string
+	OrganizationGUID string
+	Request          AuditEventRequest
+}
+
+type ListAuditEventsMessage struct {
+	GUIDs             []string
+	Types             []string
+	TargetGUIDs       []string
+	SpaceGUIDs        []string
+	OrganizationGUIDs []string
+	CreatedAts        []time.Time
+	CreatedAfter      *time.Time
+	CreatedAtOrAfter  *time.Time
+	CreatedBefore     *time.Time
+	CreatedAtOrBefore *time.Time
+}
+
+type AuditEventRepo struct {
+	rootNamespace     string
+	privilegedClient  client.Client
+	userClientFactory authorization.UserK8sClientFactory
+	nsPerms           *authorization.NamespacePermissions
+}
+
+func NewAuditEventRepo(
+	rootNamespace string,
+	privilegedClient client.Client,
+	userClientFactory authorization.UserK8sClientFactory,
+	nsPerms *authorization.NamespacePermissions,
+) *AuditEventRepo {
+	return &AuditEventRepo{
+		rootNamespace:     rootNamespace,
+		privilegedClient:  privilegedClient,
+		userClientFactory: userClientFactory,
+		nsPerms:           nsPerms,
+	}
+}
+
+// RecordAuditEvent stores the event in the namespace of the space it relates to, falling back to the org
+// namespace and then the root namespace. Users cannot create audit events, so the privileged client is used
+func (r *AuditEventRepo) RecordAuditEvent(ctx context.Context, message CreateAuditEventMessage) error {
+	namespace := r.rootNamespace
+	if message.OrganizationGUID != "" {
+		namespace = message.OrganizationGUID
+	}
+	if message.SpaceGUID != "" {
+		namespace = message.SpaceGUID
+	}
+
+	cfAuditEvent := &korifiv1alpha1.CFAuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewString(),
+			Namespace: namespace,
+		},
+		Spec: korifiv1alpha1.CFAuditEventSpec{
+			Type:             message.Type,
+			Actor:            korifiv1alpha1.AuditEventParticipant(message.Actor),
+			Target:           korifiv1alpha1.AuditEventParticipant(message.Target),
+			SpaceGUID:        message.SpaceGUID,
+			OrganizationGUID: message.OrganizationGUID,
+			Request:          korifiv1alpha1.AuditEventRequest(message.Request),
+		},
+	}
+
+	err := r.privilegedClient.Create(ctx, cfAuditEvent)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", apierrors.FromK8sError(err, AuditEventResourceType))
+	}
+
+	return nil
+}
+
+func (r *AuditEventRepo) GetAuditEvent(ctx context.Context, authInfo authorization.Info, guid string) (AuditEventRecord, error) {
+	records, err := r.ListAuditEvents(ctx, authInfo, ListAuditEventsMessage{GUIDs: []string{guid}})
+	if err != nil {
+		return AuditEventRecord{}, err
+	}
+
+	if len(records) == 0 {
+		return AuditEventRecord{}, apierrors.NewNotFoundError(fmt.Errorf("audit event %q not found", guid), AuditEventResourceType)
+	}
+
+	return records[0], nil
+}
+
+func (r *AuditEventRepo) ListAuditEvents(ctx context.Context, authInfo authorization.Info, message ListAuditEventsMessage) ([]AuditEventRecord, error) {
+	namespaces, err := r.authorizedNamespaces(ctx, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	records := []AuditEventRecord{}
+	for ns := range namespaces {
+		cfAuditEventList := &korifiv1alpha1.CFAuditEventList{}
+		err = userClient.List(ctx, cfAuditEventList, client.InNamespace(ns))
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit events in namespace %s: %w", ns, apierrors.FromK8sError(err, AuditEventResourceType))
+		}
+
+		for _, cfAuditEvent := range cfAuditEventList.Items {
+			if message.matches(cfAuditEvent) {
+				records = append(records, cfAuditEventToAuditEventRecord(cfAuditEvent))
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+
+	return records, nil
+}
+
+func (r *AuditEventRepo) authorizedNamespaces(ctx context.Context, authInfo authorization.Info) (map[string]bool, error) {
+	spaceNamespaces, err := r.nsPerms.GetAuthorizedSpaceNamespaces(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
+	}
+
+	orgNamespaces, err := r.nsPerms.GetAuthorizedOrgNamespaces(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces for orgs with user role bindings: %w", err)
+	}
+
+	namespaces := map[string]bool{r.rootNamespace: true}
+	for ns := range spaceNamespaces {
+		namespaces[ns] = true
+	}
+	for ns := range orgNamespaces {
+		namespaces[ns] = true
+	}
+
+	return namespaces, nil
+}
+
+func (m ListAuditEventsMessage) matches(cfAuditEvent korifiv1alpha1.CFAuditEvent) bool {
+	createdAt := cfAuditEvent.CreationTimestamp.Time
+
+	return matchesFilter(cfAuditEvent.Name, m.GUIDs) &&
+		matchesFilter(cfAuditEvent.Spec.Type, m.Types) &&
+		matchesFilter(cfAuditEvent.Spec.Target.GUID, m.TargetGUIDs) &&
+		matchesFilter(cfAuditEvent.Spec.SpaceGUID, m.SpaceGUIDs) &&
+		matchesFilter(cfAuditEvent.Spec.OrganizationGUID, m.OrganizationGUIDs) &&
+		matchesTimestamp(createdAt, m.CreatedAts) &&
+		(m.CreatedAfter == nil || createdAt.After(*m.CreatedAfter)) &&
+		(m.CreatedAtOrAfter == nil || !createdAt.Before(*m.CreatedAtOrAfter)) &&
+		(m.CreatedBefore == nil || createdAt.Before(*m.CreatedBefore)) &&
+		(m.CreatedAtOrBefore == nil || !createdAt.After(*m.CreatedAtOrBefore))
+}
+
+func matchesTimestamp(timestamp time.Time, filter []time.Time) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, t := range filter {
+		if timestamp.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func cfAuditEventToAuditEventRecord(cfAuditEvent korifiv1alpha1.CFAuditEvent) AuditEventRecord {
+	var crash *AuditEventCrash
+	if c := cfAuditEvent.Spec.Crash; c != nil {
+		crash = &AuditEventCrash{
+			Index:           c.Index,
+			Reason:          c.Reason,
+			ExitDescription: c.ExitDescription,
+			ExitStatus:      c.ExitStatus,
+			CrashCount:      c.CrashCount,
+			CrashTimestamp:  c.CrashTimestamp.Time,
+		}
+	}
+
+	return AuditEventRecord{
+		GUID:             cfAuditEvent.Name,
+		Type:             cfAuditEvent.Spec.Type,
+		Actor:            AuditEventParticipant(cfAuditEvent.Spec.Actor),
+		Target:           AuditEventParticipant(cfAuditEvent.Spec.Target),
+		SpaceGUID:        cfAuditEvent.Spec.SpaceGUID,
+		OrganizationGUID: cfAuditEvent.Spec.OrganizationGUID,
+		Request:          AuditEventRequest(cfAuditEvent.Spec.Request),
+		Crash:            crash,
+		CreatedAt:        cfAuditEvent.CreationTimestamp.Time,
+	}
+}
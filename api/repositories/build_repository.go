@@ -1,12 +1,9 @@
 package repositories
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"sort"
-	"strings"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
@@ -17,7 +14,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	k8sclient "k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,8 +25,7 @@ const (
 	StagingConditionType   = "Staging"
 	SucceededConditionType = "Succeeded"
 
-	BuildResourceType    = "Build"
-	stagingLogSourceType = "STG"
+	BuildResourceType = "Build"
 )
 
 type BuildRecord struct {
@@ -50,10 +45,18 @@ type BuildRecord struct {
 }
 
 type LogRecord struct {
-	Message   string
-	Timestamp int64
-	Header    string
-	Tags      map[string]string
+	Message    string
+	Timestamp  int64
+	Header     string
+	InstanceID string
+	Tags       map[string]string
+	// Gauge is only set on container metrics envelopes, which carry no message
+	Gauge map[string]GaugeMetric
+}
+
+type GaugeMetric struct {
+	Unit  string
+	Value float64
 }
 
 type BuildRepo struct {
@@ -74,7 +77,7 @@ func (b *BuildRepo) GetBuild(ctx context.Context, authInfo authorization.Info, b
 		return BuildRecord{}, err
 	}
 
-	userClient, err := b.userClientFactory.BuildClient(authInfo)
+	userClient, err := b.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return BuildRecord{}, fmt.Errorf("get-build failed to build user client: %w", err)
 	}
@@ -88,7 +91,7 @@ func (b *BuildRepo) GetBuild(ctx context.Context, authInfo authorization.Info, b
 }
 
 func (b *BuildRepo) GetLatestBuildByAppGUID(ctx context.Context, authInfo authorization.Info, spaceGUID string, appGUID string) (BuildRecord, error) {
-	userClient, err := b.userClientFactory.BuildClient(authInfo)
+	userClient, err := b.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil { // Untested
 		return BuildRecord{}, err
 	}
@@ -123,34 +126,6 @@ func orderBuilds(builds []korifiv1alpha1.CFBuild) []korifiv1alpha1.CFBuild {
 	return builds
 }
 
-func (b *BuildRepo) GetBuildLogs(ctx context.Context, authInfo authorization.Info, spaceGUID string, buildGUID string) ([]LogRecord, error) {
-	userClient, err := b.userClientFactory.BuildK8sClient(authInfo)
-	if err != nil { // Untested
-		return []LogRecord{}, err
-	}
-	logWriter := new(strings.Builder)
-	err = NewBuildLogsClient(userClient).GetImageLogs(ctx, logWriter, buildGUID, spaceGUID)
-	if err != nil {
-		return nil, err
-	}
-	buildLogs := strings.Split(strings.Trim(logWriter.String(), "\n"), "\n")
-	toReturn := make([]LogRecord, 0, len(buildLogs))
-
-	for _, log := range buildLogs {
-		logLine, logTime, _ := parseRFC3339NanoTime(log)
-
-		toReturn = append(toReturn, LogRecord{
-			Message:   logLine,
-			Timestamp: logTime,
-			Tags: map[string]string{
-				"source_type": stagingLogSourceType,
-			},
-		})
-	}
-
-	return toReturn, nil
-}
-
 func cfBuildToBuildRecord(cfBuild korifiv1alpha1.CFBuild) BuildRecord {
 	updatedAtTime, _ := getTimeLastUpdatedTimestamp(&cfBuild.ObjectMeta)
 
@@ -200,7 +175,7 @@ func cfBuildToBuildRecord(cfBuild korifiv1alpha1.CFBuild) BuildRecord {
 
 func (b *BuildRepo) CreateBuild(ctx context.Context, authInfo authorization.Info, message CreateBuildMessage) (BuildRecord, error) {
 	cfBuild := message.toCFBuild()
-	userClient, err := b.userClientFactory.BuildClient(authInfo)
+	userClient, err := b.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return BuildRecord{}, fmt.Errorf("failed to build user k8s client: %w", err)
 	}
@@ -251,118 +226,4 @@ func (m CreateBuildMessage) toCFBuild() korifiv1alpha1.CFBuild {
 	}
 }
 
-// This code was copied from https://github.com/pivotal/kpack/blob/37764d9940b2f45b1c7acb6c2cd33cf4f489c03b/pkg/logs/build_logs.go
-// with minor modifications.
-type readyContainer struct {
-	podName       string
-	containerName string
-	namespace     string
-}
-
-type BuildLogsClient struct {
-	k8sClient k8sclient.Interface
-	processed map[readyContainer]interface{}
-}
-
-func NewBuildLogsClient(k8sClient k8sclient.Interface) *BuildLogsClient {
-	return &BuildLogsClient{
-		k8sClient: k8sClient,
-		processed: make(map[readyContainer]interface{}),
-	}
-}
-
 const BuildWorkloadLabelKey = "korifi.cloudfoundry.org/build-workload-name"
-
-func (c *BuildLogsClient) GetImageLogs(ctx context.Context, writer io.Writer, buildGUID, namespace string) error {
-	return c.getPodLogs(ctx, writer, namespace, metav1.ListOptions{
-		Watch:         false,
-		LabelSelector: fmt.Sprintf("%s=%s", BuildWorkloadLabelKey, buildGUID),
-	}, false)
-}
-
-func (c *BuildLogsClient) getPodLogs(ctx context.Context, writer io.Writer, namespace string, listOptions metav1.ListOptions, follow bool) error {
-	readyContainers, err := c.getContainers(ctx, namespace, listOptions)
-	if err != nil {
-		return err
-	}
-
-	for _, container := range readyContainers {
-		err := c.streamLogsForContainer(ctx, writer, container, follow)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (c *BuildLogsClient) getContainers(ctx context.Context, namespace string, listOptions metav1.ListOptions) ([]readyContainer, error) {
-	readyContainers := make([]readyContainer, 0)
-	pods, err := c.k8sClient.CoreV1().Pods(namespace).List(ctx, listOptions)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, pod := range pods.Items {
-		for _, c := range pod.Status.InitContainerStatuses {
-			if c.State.Waiting == nil {
-				readyContainers = append(readyContainers, readyContainer{
-					podName:       pod.Name,
-					containerName: c.Name,
-					namespace:     pod.Namespace,
-				})
-			}
-		}
-
-		for _, c := range pod.Status.ContainerStatuses {
-			if c.State.Waiting == nil {
-				readyContainers = append(readyContainers, readyContainer{
-					podName:       pod.Name,
-					containerName: c.Name,
-					namespace:     pod.Namespace,
-				})
-			}
-		}
-	}
-
-	return readyContainers, nil
-}
-
-func (c *BuildLogsClient) streamLogsForContainer(ctx context.Context, writer io.Writer, readyContainer readyContainer, follow bool) error {
-	if _, alreadyProcessed := c.processed[readyContainer]; alreadyProcessed {
-		return nil
-	}
-	c.processed[readyContainer] = nil
-
-	logReadCloser, err := c.k8sClient.CoreV1().Pods(readyContainer.namespace).GetLogs(readyContainer.podName, &corev1.PodLogOptions{
-		Container:  readyContainer.containerName,
-		Follow:     follow,
-		Timestamps: true,
-	}).Stream(ctx)
-	if err != nil {
-		return err
-	}
-	defer logReadCloser.Close()
-
-	r := bufio.NewReader(logReadCloser)
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			line, err := r.ReadBytes('\n')
-			if err != nil {
-				if err == io.EOF {
-					return nil
-				}
-
-				return err
-			}
-
-			_, err = writer.Write(line)
-			if err != nil {
-				return err
-			}
-		}
-	}
-}
@@ -45,7 +45,7 @@ func NewBuildpackRepository(builderName string, userClientFactory authorization.
 func (r *BuildpackRepository) ListBuildpacks(ctx context.Context, authInfo authorization.Info) ([]BuildpackRecord, error) {
 	var builderInfo v1alpha1.BuilderInfo
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build user client: %w", err)
 	}
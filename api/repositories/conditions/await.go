@@ -3,8 +3,11 @@ package conditions
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
+	"code.cloudfoundry.org/korifi/api/metrics"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -58,6 +61,11 @@ func (a *Awaiter[T, L, PL]) AwaitCondition(ctx context.Context, k8sClient client
 		}
 	}
 
+	if ctx.Err() == nil {
+		// only count the awaiter timing out, not the caller giving up
+		metrics.ObserveConditionAwaitTimeout(reflect.TypeOf(object).Elem().Name(), conditionType)
+	}
+
 	return empty, fmt.Errorf("object %s:%s did not get the %s condition within timeout period %d ms",
 		object.GetNamespace(), object.GetName(), conditionType, a.timeout.Milliseconds(),
 	)
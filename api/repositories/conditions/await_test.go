@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var _ = Describe("Await", func() {
@@ -20,6 +21,8 @@ var _ = Describe("Await", func() {
 		task        *korifiv1alpha1.CFTask
 		awaitedTask *korifiv1alpha1.CFTask
 		awaitErr    error
+
+		timeoutsBefore float64
 	)
 
 	BeforeEach(func() {
@@ -38,6 +41,7 @@ var _ = Describe("Await", func() {
 	})
 
 	JustBeforeEach(func() {
+		timeoutsBefore = awaitTimeouts()
 		awaitedTask, awaitErr = awaiter.AwaitCondition(context.Background(), k8sClient, task, korifiv1alpha1.TaskInitializedConditionType)
 	})
 
@@ -45,6 +49,10 @@ var _ = Describe("Await", func() {
 		Expect(awaitErr).To(MatchError(ContainSubstring("did not get the Initialized condition")))
 	})
 
+	It("counts the timeout", func() {
+		Expect(awaitTimeouts()).To(Equal(timeoutsBefore + 1))
+	})
+
 	When("the condition becomes true", func() {
 		var wg sync.WaitGroup
 
@@ -77,5 +85,32 @@ var _ = Describe("Await", func() {
 			Expect(awaitedTask.Name).To(Equal(task.Name))
 			Expect(meta.IsStatusConditionTrue(awaitedTask.Status.Conditions, korifiv1alpha1.TaskInitializedConditionType)).To(BeTrue())
 		})
+
+		It("does not count a timeout", func() {
+			Expect(awaitTimeouts()).To(Equal(timeoutsBefore))
+		})
 	})
 })
+
+func awaitTimeouts() float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "korifi_api_condition_await_timeouts_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["kind"] == "CFTask" && labels["condition"] == korifiv1alpha1.TaskInitializedConditionType {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
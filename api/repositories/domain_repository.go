@@ -55,7 +55,7 @@ func (r *DomainRepo) GetDomain(ctx context.Context, authInfo authorization.Info,
 		return DomainRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return DomainRecord{}, fmt.Errorf("get-domain failed to create user client: %w", err)
 	}
@@ -70,7 +70,7 @@ func (r *DomainRepo) GetDomain(ctx context.Context, authInfo authorization.Info,
 }
 
 func (r *DomainRepo) ListDomains(ctx context.Context, authInfo authorization.Info, message ListDomainsMessage) ([]DomainRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []DomainRecord{}, fmt.Errorf("list-domain failed to create user client: %w", err)
 	}
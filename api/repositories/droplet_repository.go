@@ -63,7 +63,7 @@ func (r *DropletRepo) GetDroplet(ctx context.Context, authInfo authorization.Inf
 		return DropletRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return DropletRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -124,7 +124,7 @@ func (r *DropletRepo) ListDroplets(ctx context.Context, authInfo authorization.I
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []DropletRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	EnvVarGroupResourceType = "Environment Variable Group"
+
+	RunningEnvVarGroupName = "running"
+	StagingEnvVarGroupName = "staging"
+)
+
+var envVarGroupSecretNames = map[string]string{
+	RunningEnvVarGroupName: korifiv1alpha1.RunningEnvVarGroupSecretName,
+	StagingEnvVarGroupName: korifiv1alpha1.StagingEnvVarGroupSecretName,
+}
+
+type EnvVarGroupRecord struct {
+	Name                 string
+	EnvironmentVariables map[string]string
+	// UpdatedAt is empty for a group that has never been set
+	UpdatedAt string
+}
+
+type PatchEnvVarGroupMessage struct {
+	Name                 string
+	EnvironmentVariables map[string]*string
+}
+
+// EnvVarGroupRepo stores the platform wide environment variable groups as Secrets in the root namespace
+type EnvVarGroupRepo struct {
+	rootNamespace     string
+	userClientFactory authorization.UserK8sClientFactory
+}
+
+func NewEnvVarGroupRepo(rootNamespace string, userClientFactory authorization.UserK8sClientFactory) *EnvVarGroupRepo {
+	return &EnvVarGroupRepo{
+		rootNamespace:     rootNamespace,
+		userClientFactory: userClientFactory,
+	}
+}
+
+func (r *EnvVarGroupRepo) GetEnvVarGroup(ctx context.Context, authInfo authorization.Info, name string) (EnvVarGroupRecord, error) {
+	secretName, ok := envVarGroupSecretNames[name]
+	if !ok {
+		return EnvVarGroupRecord{}, apierrors.NewNotFoundError(fmt.Errorf("unknown environment variable group %q", name), EnvVarGroupResourceType)
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return EnvVarGroupRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	groupSecret := new(corev1.Secret)
+	err = userClient.Get(ctx, types.NamespacedName{Namespace: r.rootNamespace, Name: secretName}, groupSecret)
+	if k8serrors.IsNotFound(err) {
+		return EnvVarGroupRecord{Name: name, EnvironmentVariables: map[string]string{}}, nil
+	}
+	if err != nil {
+		return EnvVarGroupRecord{}, apierrors.FromK8sError(err, EnvVarGroupResourceType)
+	}
+
+	return envVarGroupSecretToRecord(name, groupSecret), nil
+}
+
+func (r *EnvVarGroupRepo) PatchEnvVarGroup(ctx context.Context, authInfo authorization.Info, message PatchEnvVarGroupMessage) (EnvVarGroupRecord, error) {
+	secretName, ok := envVarGroupSecretNames[message.Name]
+	if !ok {
+		return EnvVarGroupRecord{}, apierrors.NewNotFoundError(fmt.Errorf("unknown environment variable group %q", message.Name), EnvVarGroupResourceType)
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return EnvVarGroupRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	groupSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: r.rootNamespace,
+		},
+	}
+
+	_, err = controllerutil.CreateOrPatch(ctx, userClient, groupSecret, func() error {
+		groupSecret.StringData = map[string]string{}
+		for k, v := range message.EnvironmentVariables {
+			if v == nil {
+				delete(groupSecret.Data, k)
+			} else {
+				groupSecret.StringData[k] = *v
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return EnvVarGroupRecord{}, apierrors.FromK8sError(err, EnvVarGroupResourceType)
+	}
+
+	return envVarGroupSecretToRecord(message.Name, groupSecret), nil
+}
+
+func envVarGroupSecretToRecord(name string, groupSecret *corev1.Secret) EnvVarGroupRecord {
+	updatedAt, _ := getTimeLastUpdatedTimestamp(&groupSecret.ObjectMeta)
+
+	return EnvVarGroupRecord{
+		Name:                 name,
+		EnvironmentVariables: convertByteSliceValuesToStrings(groupSecret.Data),
+		UpdatedAt:            updatedAt,
+	}
+}
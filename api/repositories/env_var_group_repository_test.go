@@ -0,0 +1,137 @@
+package repositories_test
+
+import (
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	. "code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("EnvVarGroupRepository", func() {
+	var envVarGroupRepo *EnvVarGroupRepo
+
+	BeforeEach(func() {
+		envVarGroupRepo = NewEnvVarGroupRepo(rootNamespace, userClientFactory)
+	})
+
+	Describe("GetEnvVarGroup", func() {
+		var (
+			groupName string
+			record    EnvVarGroupRecord
+			getErr    error
+		)
+
+		BeforeEach(func() {
+			groupName = RunningEnvVarGroupName
+		})
+
+		JustBeforeEach(func() {
+			record, getErr = envVarGroupRepo.GetEnvVarGroup(ctx, authInfo, groupName)
+		})
+
+		It("returns an empty group when it has never been set", func() {
+			Expect(getErr).NotTo(HaveOccurred())
+			Expect(record.Name).To(Equal(RunningEnvVarGroupName))
+			Expect(record.EnvironmentVariables).To(BeEmpty())
+			Expect(record.UpdatedAt).To(BeEmpty())
+		})
+
+		When("the group is set", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      korifiv1alpha1.RunningEnvVarGroupSecretName,
+						Namespace: rootNamespace,
+					},
+					StringData: map[string]string{"https_proxy": "proxy.example.com"},
+				})).To(Succeed())
+			})
+
+			It("returns the group to any user with access to the root namespace", func() {
+				Expect(getErr).NotTo(HaveOccurred())
+				Expect(record.EnvironmentVariables).To(Equal(map[string]string{"https_proxy": "proxy.example.com"}))
+				Expect(record.UpdatedAt).NotTo(BeEmpty())
+			})
+		})
+
+		When("the group is unknown", func() {
+			BeforeEach(func() {
+				groupName = "bogus"
+			})
+
+			It("returns a not found error", func() {
+				Expect(getErr).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+	})
+
+	Describe("PatchEnvVarGroup", func() {
+		var (
+			record   EnvVarGroupRecord
+			patchErr error
+		)
+
+		JustBeforeEach(func() {
+			newValue := "new-proxy.example.com"
+			record, patchErr = envVarGroupRepo.PatchEnvVarGroup(ctx, authInfo, PatchEnvVarGroupMessage{
+				Name: StagingEnvVarGroupName,
+				EnvironmentVariables: map[string]*string{
+					"https_proxy": &newValue,
+					"no_proxy":    nil,
+				},
+			})
+		})
+
+		When("the user is not an admin", func() {
+			It("returns a forbidden error", func() {
+				Expect(patchErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("creates the group secret", func() {
+				Expect(patchErr).NotTo(HaveOccurred())
+				Expect(record.Name).To(Equal(StagingEnvVarGroupName))
+				Expect(record.EnvironmentVariables).To(Equal(map[string]string{"https_proxy": "new-proxy.example.com"}))
+
+				var groupSecret corev1.Secret
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: rootNamespace, Name: korifiv1alpha1.StagingEnvVarGroupSecretName}, &groupSecret)).To(Succeed())
+				Expect(asMapOfStrings(groupSecret.Data)).To(Equal(map[string]string{"https_proxy": "new-proxy.example.com"}))
+			})
+
+			When("the group is already set", func() {
+				BeforeEach(func() {
+					Expect(k8sClient.Create(ctx, &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      korifiv1alpha1.StagingEnvVarGroupSecretName,
+							Namespace: rootNamespace,
+						},
+						StringData: map[string]string{
+							"https_proxy": "proxy.example.com",
+							"no_proxy":    "localhost",
+							"ca_bundle":   "some-ca",
+						},
+					})).To(Succeed())
+				})
+
+				It("merges the patch into the group, removing null variables", func() {
+					Expect(patchErr).NotTo(HaveOccurred())
+					Expect(record.EnvironmentVariables).To(Equal(map[string]string{
+						"https_proxy": "new-proxy.example.com",
+						"ca_bundle":   "some-ca",
+					}))
+				})
+			})
+		})
+	})
+})
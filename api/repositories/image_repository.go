@@ -12,10 +12,13 @@ import (
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	authv1 "k8s.io/api/authorization/v1"
 	k8sclient "k8s.io/client-go/kubernetes"
@@ -68,7 +71,10 @@ func NewImageRepository(
 	}
 }
 
-func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo authorization.Info, imageRef string, srcReader io.Reader, spaceGUID string) (string, error) {
+func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo authorization.Info, imageRef string, srcReader io.Reader, spaceGUID string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "upload source image", trace.WithAttributes(attribute.String("korifi.image_ref", imageRef)))
+	defer func() { tracing.End(span, err) }()
+
 	authorized, err := r.canIPatchCFPackage(ctx, authInfo, spaceGUID)
 	if err != nil {
 		return "", fmt.Errorf("checking auth to upload source image for failed: %w", err)
@@ -93,7 +99,9 @@ func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo author
 		return "", fmt.Errorf("configuring transport for image ref '%s' failed: %w", imageRef, err)
 	}
 
+	_, pushSpan := tracing.Start(ctx, "push source image")
 	pushedRef, err := r.pusher.Push(imageRef, image, credentials, transport)
+	tracing.End(pushSpan, err)
 	if err != nil {
 		return "", apierrors.NewBlobstoreUnavailableError(fmt.Errorf("pushing image ref '%s' failed: %w", imageRef, err))
 	}
@@ -102,7 +110,7 @@ func (r *ImageRepository) UploadSourceImage(ctx context.Context, authInfo author
 }
 
 func (r *ImageRepository) canIPatchCFPackage(ctx context.Context, authInfo authorization.Info, spaceGUID string) (bool, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return false, fmt.Errorf("canIPatchCFPackage: failed to create user k8s client: %w", err)
 	}
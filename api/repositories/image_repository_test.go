@@ -10,11 +10,15 @@ import (
 	"code.cloudfoundry.org/korifi/api/repositories"
 	"code.cloudfoundry.org/korifi/api/repositories/fake"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "k8s.io/client-go/kubernetes"
@@ -33,6 +37,8 @@ var _ = Describe("ImageRepository", func() {
 
 		imageRepo *repositories.ImageRepository
 
+		spanExporter *tracetest.InMemoryExporter
+
 		imageRef  string
 		uploadErr error
 		org       *korifiv1alpha1.CFOrg
@@ -41,6 +47,9 @@ var _ = Describe("ImageRepository", func() {
 
 	BeforeEach(func() {
 		var err error
+		spanExporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(tracing.NewTracerProvider("test", sdktrace.WithSyncer(spanExporter)))
+
 		imageBuilder = new(fake.ImageBuilder)
 		image, err = random.Image(0, 0)
 		Expect(err).NotTo(HaveOccurred())
@@ -130,6 +139,14 @@ var _ = Describe("ImageRepository", func() {
 			Expect(credentials).NotTo(BeNil())
 		})
 
+		It("traces the upload and the push", func() {
+			spanNames := []string{}
+			for _, span := range spanExporter.GetSpans() {
+				spanNames = append(spanNames, span.Name)
+			}
+			Expect(spanNames).To(ContainElements("upload source image", "push source image"))
+		})
+
 		When("building the image fails", func() {
 			BeforeEach(func() {
 				imageBuilder.BuildReturns(nil, errors.New("build-error"))
@@ -0,0 +1,393 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	IsolationSegmentResourceType = "Isolation Segment"
+)
+
+type IsolationSegmentRecord struct {
+	GUID              string
+	Name              string
+	OrganizationGUIDs []string
+	CreatedAt         string
+	UpdatedAt         string
+}
+
+type SpaceIsolationSegmentRecord struct {
+	SpaceGUID            string
+	IsolationSegmentGUID string
+}
+
+type CreateIsolationSegmentMessage struct {
+	Name string
+}
+
+type ListIsolationSegmentsMessage struct {
+	GUIDs             []string
+	Names             []string
+	OrganizationGUIDs []string
+}
+
+type EntitleIsolationSegmentMessage struct {
+	GUID              string
+	OrganizationGUIDs []string
+}
+
+type RevokeIsolationSegmentMessage struct {
+	GUID             string
+	OrganizationGUID string
+}
+
+type AssignSpaceIsolationSegmentMessage struct {
+	SpaceGUID string
+	// An empty IsolationSegmentGUID resets the space to the shared isolation segment
+	IsolationSegmentGUID string
+}
+
+type IsolationSegmentRepo struct {
+	rootNamespace      string
+	namespaceRetriever NamespaceRetriever
+	userClientFactory  authorization.UserK8sClientFactory
+}
+
+func NewIsolationSegmentRepo(
+	rootNamespace string,
+	namespaceRetriever NamespaceRetriever,
+	userClientFactory authorization.UserK8sClientFactory,
+) *IsolationSegmentRepo {
+	return &IsolationSegmentRepo{
+		rootNamespace:      rootNamespace,
+		namespaceRetriever: namespaceRetriever,
+		userClientFactory:  userClientFactory,
+	}
+}
+
+func (r *IsolationSegmentRepo) CreateIsolationSegment(ctx context.Context, authInfo authorization.Info, message CreateIsolationSegmentMessage) (IsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return IsolationSegmentRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	existing, err := r.ListIsolationSegments(ctx, authInfo, ListIsolationSegmentsMessage{Names: []string{message.Name}})
+	if err != nil {
+		return IsolationSegmentRecord{}, err
+	}
+	if len(existing) > 0 {
+		return IsolationSegmentRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("isolation segment %q already exists", message.Name),
+			"Isolation Segment names are case insensitive and must be unique",
+		)
+	}
+
+	// By default, a segment maps to the node pool labelled and tainted with its name
+	cfIsolationSegment := &korifiv1alpha1.CFIsolationSegment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewString(),
+			Namespace: r.rootNamespace,
+		},
+		Spec: korifiv1alpha1.CFIsolationSegmentSpec{
+			DisplayName: message.Name,
+			NodeSelector: map[string]string{
+				korifiv1alpha1.IsolationSegmentLabelKey: message.Name,
+			},
+			Tolerations: []corev1.Toleration{{
+				Key:      korifiv1alpha1.IsolationSegmentLabelKey,
+				Operator: corev1.TolerationOpEqual,
+				Value:    message.Name,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		},
+	}
+
+	if err = userClient.Create(ctx, cfIsolationSegment); err != nil {
+		return IsolationSegmentRecord{}, apierrors.FromK8sError(err, IsolationSegmentResourceType)
+	}
+
+	return cfIsolationSegmentToRecord(*cfIsolationSegment), nil
+}
+
+func (r *IsolationSegmentRepo) GetIsolationSegment(ctx context.Context, authInfo authorization.Info, guid string) (IsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return IsolationSegmentRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfIsolationSegment, err := r.getIsolationSegment(ctx, userClient, guid)
+	if err != nil {
+		return IsolationSegmentRecord{}, err
+	}
+
+	return cfIsolationSegmentToRecord(*cfIsolationSegment), nil
+}
+
+func (r *IsolationSegmentRepo) ListIsolationSegments(ctx context.Context, authInfo authorization.Info, message ListIsolationSegmentsMessage) ([]IsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfIsolationSegmentList := &korifiv1alpha1.CFIsolationSegmentList{}
+	err = userClient.List(ctx, cfIsolationSegmentList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		if k8serrors.IsForbidden(err) {
+			return []IsolationSegmentRecord{}, nil
+		}
+		return nil, apierrors.FromK8sError(err, IsolationSegmentResourceType)
+	}
+
+	records := []IsolationSegmentRecord{}
+	for _, cfIsolationSegment := range cfIsolationSegmentList.Items {
+		if !matchesFilter(cfIsolationSegment.Name, message.GUIDs) {
+			continue
+		}
+
+		if !matchesFilter(cfIsolationSegment.Spec.DisplayName, message.Names) {
+			continue
+		}
+
+		if len(message.OrganizationGUIDs) > 0 && !anyMatchesFilter(cfIsolationSegment.Spec.Organizations, message.OrganizationGUIDs) {
+			continue
+		}
+
+		records = append(records, cfIsolationSegmentToRecord(cfIsolationSegment))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt < records[j].CreatedAt
+	})
+
+	return records, nil
+}
+
+func (r *IsolationSegmentRepo) DeleteIsolationSegment(ctx context.Context, authInfo authorization.Info, guid string) error {
+	record, err := r.GetIsolationSegment(ctx, authInfo, guid)
+	if err != nil {
+		return err
+	}
+
+	if len(record.OrganizationGUIDs) > 0 {
+		return apierrors.NewUnprocessableEntityError(
+			errors.New("isolation segment is still entitled to orgs"),
+			"Revoke the Organization entitlements for your Isolation Segment.",
+		)
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.Delete(ctx, &korifiv1alpha1.CFIsolationSegment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      guid,
+			Namespace: r.rootNamespace,
+		},
+	})
+
+	return apierrors.FromK8sError(err, IsolationSegmentResourceType)
+}
+
+func (r *IsolationSegmentRepo) EntitleOrganizations(ctx context.Context, authInfo authorization.Info, message EntitleIsolationSegmentMessage) (IsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return IsolationSegmentRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfIsolationSegment, err := r.getIsolationSegment(ctx, userClient, message.GUID)
+	if err != nil {
+		return IsolationSegmentRecord{}, err
+	}
+
+	var missing []string
+	for _, orgGUID := range message.OrganizationGUIDs {
+		err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: orgGUID}, &korifiv1alpha1.CFOrg{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+				missing = append(missing, orgGUID)
+				continue
+			}
+			return IsolationSegmentRecord{}, apierrors.FromK8sError(err, OrgResourceType)
+		}
+	}
+
+	if len(missing) > 0 {
+		return IsolationSegmentRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("orgs %v not found", missing),
+			fmt.Sprintf("Organizations with guids %s do not exist, or you do not have access to them.", quotedList(missing)),
+		)
+	}
+
+	err = k8s.PatchResource(ctx, userClient, cfIsolationSegment, func() {
+		for _, orgGUID := range message.OrganizationGUIDs {
+			if !isEntitled(*cfIsolationSegment, orgGUID) {
+				cfIsolationSegment.Spec.Organizations = append(cfIsolationSegment.Spec.Organizations, orgGUID)
+			}
+		}
+	})
+	if err != nil {
+		return IsolationSegmentRecord{}, apierrors.FromK8sError(err, IsolationSegmentResourceType)
+	}
+
+	return cfIsolationSegmentToRecord(*cfIsolationSegment), nil
+}
+
+func (r *IsolationSegmentRepo) RevokeOrganization(ctx context.Context, authInfo authorization.Info, message RevokeIsolationSegmentMessage) error {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfIsolationSegment, err := r.getIsolationSegment(ctx, userClient, message.GUID)
+	if err != nil {
+		return err
+	}
+
+	cfSpaceList := &korifiv1alpha1.CFSpaceList{}
+	err = userClient.List(ctx, cfSpaceList, client.InNamespace(message.OrganizationGUID))
+	if err != nil && !k8serrors.IsForbidden(err) {
+		return apierrors.FromK8sError(err, SpaceResourceType)
+	}
+
+	for _, cfSpace := range cfSpaceList.Items {
+		if cfSpace.Spec.IsolationSegmentRef.Name == message.GUID {
+			return apierrors.NewUnprocessableEntityError(
+				fmt.Errorf("space %q is assigned to isolation segment %q", cfSpace.Name, message.GUID),
+				fmt.Sprintf("Cannot remove the entitlement while this Isolation Segment is assigned to any Spaces. Space %q is assigned to it.", cfSpace.Spec.DisplayName),
+			)
+		}
+	}
+
+	err = k8s.PatchResource(ctx, userClient, cfIsolationSegment, func() {
+		orgGUIDs := []string{}
+		for _, orgGUID := range cfIsolationSegment.Spec.Organizations {
+			if orgGUID != message.OrganizationGUID {
+				orgGUIDs = append(orgGUIDs, orgGUID)
+			}
+		}
+		cfIsolationSegment.Spec.Organizations = orgGUIDs
+	})
+
+	return apierrors.FromK8sError(err, IsolationSegmentResourceType)
+}
+
+func (r *IsolationSegmentRepo) GetSpaceIsolationSegment(ctx context.Context, authInfo authorization.Info, spaceGUID string) (SpaceIsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return SpaceIsolationSegmentRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfSpace, err := r.getSpace(ctx, userClient, spaceGUID)
+	if err != nil {
+		return SpaceIsolationSegmentRecord{}, err
+	}
+
+	return SpaceIsolationSegmentRecord{
+		SpaceGUID:            cfSpace.Name,
+		IsolationSegmentGUID: cfSpace.Spec.IsolationSegmentRef.Name,
+	}, nil
+}
+
+func (r *IsolationSegmentRepo) AssignSpaceIsolationSegment(ctx context.Context, authInfo authorization.Info, message AssignSpaceIsolationSegmentMessage) (SpaceIsolationSegmentRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return SpaceIsolationSegmentRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfSpace, err := r.getSpace(ctx, userClient, message.SpaceGUID)
+	if err != nil {
+		return SpaceIsolationSegmentRecord{}, err
+	}
+
+	if message.IsolationSegmentGUID != "" {
+		var cfIsolationSegment *korifiv1alpha1.CFIsolationSegment
+		cfIsolationSegment, err = r.getIsolationSegment(ctx, userClient, message.IsolationSegmentGUID)
+		if err != nil && !errors.As(err, new(apierrors.NotFoundError)) && !errors.As(err, new(apierrors.ForbiddenError)) {
+			return SpaceIsolationSegmentRecord{}, err
+		}
+
+		if err != nil || !isEntitled(*cfIsolationSegment, cfSpace.Namespace) {
+			return SpaceIsolationSegmentRecord{}, apierrors.NewUnprocessableEntityError(
+				fmt.Errorf("isolation segment %q is not entitled to org %q", message.IsolationSegmentGUID, cfSpace.Namespace),
+				fmt.Sprintf("Unable to assign isolation segment with guid '%s'. Ensure it has been entitled to the organization that this space belongs to.", message.IsolationSegmentGUID),
+			)
+		}
+	}
+
+	err = k8s.PatchResource(ctx, userClient, cfSpace, func() {
+		cfSpace.Spec.IsolationSegmentRef.Name = message.IsolationSegmentGUID
+	})
+	if err != nil {
+		return SpaceIsolationSegmentRecord{}, apierrors.FromK8sError(err, SpaceResourceType)
+	}
+
+	return SpaceIsolationSegmentRecord{
+		SpaceGUID:            cfSpace.Name,
+		IsolationSegmentGUID: cfSpace.Spec.IsolationSegmentRef.Name,
+	}, nil
+}
+
+func (r *IsolationSegmentRepo) getIsolationSegment(ctx context.Context, userClient client.Client, guid string) (*korifiv1alpha1.CFIsolationSegment, error) {
+	cfIsolationSegment := &korifiv1alpha1.CFIsolationSegment{}
+	err := userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, cfIsolationSegment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get isolation segment: %w", apierrors.FromK8sError(err, IsolationSegmentResourceType))
+	}
+
+	return cfIsolationSegment, nil
+}
+
+func (r *IsolationSegmentRepo) getSpace(ctx context.Context, userClient client.Client, spaceGUID string) (*korifiv1alpha1.CFSpace, error) {
+	ns, err := r.namespaceRetriever.NamespaceFor(ctx, spaceGUID, SpaceResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	cfSpace := &korifiv1alpha1.CFSpace{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: spaceGUID}, cfSpace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get space: %w", apierrors.FromK8sError(err, SpaceResourceType))
+	}
+
+	return cfSpace, nil
+}
+
+func cfIsolationSegmentToRecord(cfIsolationSegment korifiv1alpha1.CFIsolationSegment) IsolationSegmentRecord {
+	orgGUIDs := []string{}
+	orgGUIDs = append(orgGUIDs, cfIsolationSegment.Spec.Organizations...)
+
+	updatedAtTime, _ := getTimeLastUpdatedTimestamp(&cfIsolationSegment.ObjectMeta)
+	return IsolationSegmentRecord{
+		GUID:              cfIsolationSegment.Name,
+		Name:              cfIsolationSegment.Spec.DisplayName,
+		OrganizationGUIDs: orgGUIDs,
+		CreatedAt:         formatTimestamp(cfIsolationSegment.CreationTimestamp),
+		UpdatedAt:         updatedAtTime,
+	}
+}
+
+func isEntitled(cfIsolationSegment korifiv1alpha1.CFIsolationSegment, orgGUID string) bool {
+	for _, entitledOrgGUID := range cfIsolationSegment.Spec.Organizations {
+		if entitledOrgGUID == orgGUID {
+			return true
+		}
+	}
+
+	return false
+}
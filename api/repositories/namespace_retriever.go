@@ -6,14 +6,23 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/metrics"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
 )
 
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps;cfbuilds;cfpackages;cfprocesses;cfspaces;cftasks,verbs=list
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains;cfroutes,verbs=list
-//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings;cfserviceinstances,verbs=list
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps;cfbuilds;cfpackages;cfprocesses;cfspaces;cftasks,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfdomains;cfroutes,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings;cfserviceinstances,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspacequotas,verbs=list;watch
+
+// guidIndex indexes the cached resource metadata by name, i.e. by CF GUID, across namespaces
+const guidIndex = "guid"
 
 var (
 	CFAppsGVR = schema.GroupVersionResource{
@@ -70,6 +79,12 @@ var (
 		Resource: "cfserviceinstances",
 	}
 
+	CFSpaceQuotasGVR = schema.GroupVersionResource{
+		Group:    "korifi.cloudfoundry.org",
+		Version:  "v1alpha1",
+		Resource: "cfspacequotas",
+	}
+
 	CFSpacesGVR = schema.GroupVersionResource{
 		Group:    "korifi.cloudfoundry.org",
 		Version:  "v1alpha1",
@@ -93,12 +108,14 @@ var (
 		ServiceBindingResourceType:  CFServiceBindingsGVR,
 		ServiceInstanceResourceType: CFServiceInstancesGVR,
 		SpaceResourceType:           CFSpacesGVR,
+		SpaceQuotaResourceType:      CFSpaceQuotasGVR,
 		TaskResourceType:            CFTasksGVR,
 	}
 )
 
 type NamespaceRetriever struct {
-	client dynamic.Interface
+	client   dynamic.Interface
+	indexers map[schema.GroupVersionResource]cache.Indexer
 }
 
 func NewNamespaceRetriever(client dynamic.Interface) NamespaceRetriever {
@@ -107,12 +124,82 @@ func NewNamespaceRetriever(client dynamic.Interface) NamespaceRetriever {
 	}
 }
 
+// NewCachingNamespaceRetriever returns a NamespaceRetriever that looks GUIDs up in metadata-only informers of the
+// resources in ResourceMap, and falls back to listing the resource across namespaces on a cache miss, e.g. for
+// resources that have just been created. The informer factory must be started after this call.
+func NewCachingNamespaceRetriever(client dynamic.Interface, informerFactory metadatainformer.SharedInformerFactory) (NamespaceRetriever, error) {
+	indexers := map[schema.GroupVersionResource]cache.Indexer{}
+	for _, gvr := range ResourceMap {
+		if _, ok := indexers[gvr]; ok {
+			continue
+		}
+
+		informer := informerFactory.ForResource(gvr).Informer()
+		err := informer.AddIndexers(cache.Indexers{guidIndex: guidIndexFn})
+		if err != nil {
+			return NamespaceRetriever{}, fmt.Errorf("failed to index %s by guid: %w", gvr.Resource, err)
+		}
+		indexers[gvr] = informer.GetIndexer()
+	}
+
+	return NamespaceRetriever{
+		client:   client,
+		indexers: indexers,
+	}, nil
+}
+
+func guidIndexFn(obj interface{}) ([]string, error) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return []string{object.GetName()}, nil
+}
+
 func (nr NamespaceRetriever) NamespaceFor(ctx context.Context, resourceGUID, resourceType string) (string, error) {
 	gvr, ok := ResourceMap[resourceType]
 	if !ok {
 		return "", fmt.Errorf("resource type %q unknown", resourceType)
 	}
 
+	if indexer, ok := nr.indexers[gvr]; ok {
+		ns, found, err := cachedNamespaceFor(indexer, resourceGUID, resourceType)
+		metrics.ObserveCacheLookup(metrics.NamespaceCache, found)
+		if err != nil || found {
+			return ns, err
+		}
+	}
+
+	return nr.liveNamespaceFor(ctx, gvr, resourceGUID, resourceType)
+}
+
+func cachedNamespaceFor(indexer cache.Indexer, resourceGUID, resourceType string) (string, bool, error) {
+	objects, err := indexer.ByIndex(guidIndex, resourceGUID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up %v in cache: %w", resourceType, err)
+	}
+
+	if len(objects) == 0 {
+		return "", false, nil
+	}
+
+	if len(objects) > 1 {
+		return "", true, fmt.Errorf("get-%s duplicate records exist", strings.ToLower(resourceType))
+	}
+
+	object, err := meta.Accessor(objects[0])
+	if err != nil {
+		return "", true, err
+	}
+
+	if object.GetNamespace() == "" {
+		return "", true, fmt.Errorf("get-%s: resource is not namespace-scoped", strings.ToLower(resourceType))
+	}
+
+	return object.GetNamespace(), true, nil
+}
+
+func (nr NamespaceRetriever) liveNamespaceFor(ctx context.Context, gvr schema.GroupVersionResource, resourceGUID, resourceType string) (string, error) {
 	opts := metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("metadata.name=%s", resourceGUID),
 	}
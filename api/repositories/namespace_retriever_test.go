@@ -6,6 +6,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/rest"
 )
 
 var _ = Describe("NamespaceRetriever", func() {
@@ -67,4 +71,79 @@ var _ = Describe("NamespaceRetriever", func() {
 			Expect(retErr).To(MatchError(ContainSubstring("duplicate records exist")))
 		})
 	})
+
+	Describe("with metadata informers", func() {
+		var (
+			informerFactory metadatainformer.SharedInformerFactory
+			startInformers  bool
+			stopInformers   chan struct{}
+			liveClient      dynamic.Interface
+		)
+
+		BeforeEach(func() {
+			startInformers = true
+			stopInformers = make(chan struct{})
+			informerFactory = metadatainformer.NewSharedInformerFactory(metadata.NewForConfigOrDie(k8sConfig), 0)
+			liveClient = dynamic.NewForConfigOrDie(k8sConfig)
+		})
+
+		JustBeforeEach(func() {
+			cachingRetriever, err := repositories.NewCachingNamespaceRetriever(liveClient, informerFactory)
+			Expect(err).NotTo(HaveOccurred())
+
+			if startInformers {
+				informerFactory.Start(stopInformers)
+				informerFactory.WaitForCacheSync(stopInformers)
+			}
+
+			retNS, retErr = cachingRetriever.NamespaceFor(ctx, appGUID, resourceType)
+		})
+
+		AfterEach(func() {
+			close(stopInformers)
+		})
+
+		When("the resource cannot be listed live", func() {
+			BeforeEach(func() {
+				liveClient = dynamic.NewForConfigOrDie(&rest.Config{Host: "http://127.0.0.1:1"})
+			})
+
+			It("returns the namespace from the informer cache", func() {
+				Expect(retErr).NotTo(HaveOccurred())
+				Expect(retNS).To(Equal(spaceGUID))
+			})
+
+			When("there are duplicate guids", func() {
+				BeforeEach(func() {
+					space2 := createSpaceWithCleanup(ctx, orgGUID, prefixedGUID("space2"))
+					_ = createAppCR(ctx, k8sClient, "app2", appGUID, space2.Name, "STOPPED")
+				})
+
+				It("returns a duplicate error", func() {
+					Expect(retErr).To(MatchError(ContainSubstring("duplicate records exist")))
+				})
+			})
+		})
+
+		When("the informers have not seen the resource yet", func() {
+			BeforeEach(func() {
+				startInformers = false
+			})
+
+			It("falls back to listing the resource", func() {
+				Expect(retErr).NotTo(HaveOccurred())
+				Expect(retNS).To(Equal(spaceGUID))
+			})
+		})
+
+		When("the guid does not exist", func() {
+			BeforeEach(func() {
+				appGUID = "does-not-exist"
+			})
+
+			It("returns a not found error", func() {
+				Expect(retErr).To(BeAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+	})
 })
@@ -0,0 +1,381 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OrgQuotaResourceType = "Organization Quota"
+)
+
+type QuotaApps struct {
+	TotalMemoryInMB              *int64
+	PerProcessMemoryInMB         *int64
+	TotalInstances               *int64
+	PerAppTasks                  *int64
+	LogRateLimitInBytesPerSecond *int64
+}
+
+type QuotaServices struct {
+	PaidServicesAllowed   bool
+	TotalServiceInstances *int64
+	TotalServiceKeys      *int64
+}
+
+type QuotaRoutes struct {
+	TotalRoutes        *int64
+	TotalReservedPorts *int64
+}
+
+type QuotaDomains struct {
+	TotalDomains *int64
+}
+
+type OrgQuotaRecord struct {
+	GUID              string
+	Name              string
+	Apps              QuotaApps
+	Services          QuotaServices
+	Routes            QuotaRoutes
+	Domains           QuotaDomains
+	OrganizationGUIDs []string
+	CreatedAt         string
+	UpdatedAt         string
+}
+
+type CreateOrgQuotaMessage struct {
+	Name              string
+	Apps              QuotaApps
+	Services          QuotaServices
+	Routes            QuotaRoutes
+	Domains           QuotaDomains
+	OrganizationGUIDs []string
+}
+
+type ListOrgQuotasMessage struct {
+	GUIDs             []string
+	Names             []string
+	OrganizationGUIDs []string
+}
+
+type ApplyOrgQuotaMessage struct {
+	GUID              string
+	OrganizationGUIDs []string
+}
+
+type OrgQuotaRepo struct {
+	rootNamespace     string
+	userClientFactory authorization.UserK8sClientFactory
+}
+
+func NewOrgQuotaRepo(
+	rootNamespace string,
+	userClientFactory authorization.UserK8sClientFactory,
+) *OrgQuotaRepo {
+	return &OrgQuotaRepo{
+		rootNamespace:     rootNamespace,
+		userClientFactory: userClientFactory,
+	}
+}
+
+func (r *OrgQuotaRepo) CreateOrgQuota(ctx context.Context, authInfo authorization.Info, message CreateOrgQuotaMessage) (OrgQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return OrgQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	existing, err := r.ListOrgQuotas(ctx, authInfo, ListOrgQuotasMessage{Names: []string{message.Name}})
+	if err != nil {
+		return OrgQuotaRecord{}, err
+	}
+	if len(existing) > 0 {
+		return OrgQuotaRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("org quota %q already exists", message.Name),
+			fmt.Sprintf("Quota Definition '%s' already exists", message.Name),
+		)
+	}
+
+	cfOrgQuota := &korifiv1alpha1.CFOrgQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewString(),
+			Namespace: r.rootNamespace,
+		},
+		Spec: korifiv1alpha1.CFOrgQuotaSpec{
+			DisplayName: message.Name,
+			Apps:        message.Apps.toCFAppsLimits(),
+			Services:    message.Services.toCFServicesLimits(),
+			Routes:      message.Routes.toCFRoutesLimits(),
+			Domains:     korifiv1alpha1.QuotaDomainsLimits{TotalDomains: message.Domains.TotalDomains},
+		},
+	}
+
+	if err = userClient.Create(ctx, cfOrgQuota); err != nil {
+		return OrgQuotaRecord{}, apierrors.FromK8sError(err, OrgQuotaResourceType)
+	}
+
+	if len(message.OrganizationGUIDs) > 0 {
+		return r.ApplyOrgQuota(ctx, authInfo, ApplyOrgQuotaMessage{
+			GUID:              cfOrgQuota.Name,
+			OrganizationGUIDs: message.OrganizationGUIDs,
+		})
+	}
+
+	return cfOrgQuotaToOrgQuotaRecord(*cfOrgQuota, nil), nil
+}
+
+func (r *OrgQuotaRepo) GetOrgQuota(ctx context.Context, authInfo authorization.Info, guid string) (OrgQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return OrgQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfOrgQuota := &korifiv1alpha1.CFOrgQuota{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, cfOrgQuota)
+	if err != nil {
+		return OrgQuotaRecord{}, fmt.Errorf("failed to get org quota: %w", apierrors.FromK8sError(err, OrgQuotaResourceType))
+	}
+
+	orgs, err := r.listOrgs(ctx, userClient)
+	if err != nil {
+		return OrgQuotaRecord{}, err
+	}
+
+	return cfOrgQuotaToOrgQuotaRecord(*cfOrgQuota, orgs), nil
+}
+
+func (r *OrgQuotaRepo) ListOrgQuotas(ctx context.Context, authInfo authorization.Info, message ListOrgQuotasMessage) ([]OrgQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfOrgQuotaList := &korifiv1alpha1.CFOrgQuotaList{}
+	err = userClient.List(ctx, cfOrgQuotaList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		if k8serrors.IsForbidden(err) {
+			return []OrgQuotaRecord{}, nil
+		}
+		return nil, apierrors.FromK8sError(err, OrgQuotaResourceType)
+	}
+
+	orgs, err := r.listOrgs(ctx, userClient)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []OrgQuotaRecord{}
+	for _, cfOrgQuota := range cfOrgQuotaList.Items {
+		if !matchesFilter(cfOrgQuota.Name, message.GUIDs) {
+			continue
+		}
+
+		if !matchesFilter(cfOrgQuota.Spec.DisplayName, message.Names) {
+			continue
+		}
+
+		record := cfOrgQuotaToOrgQuotaRecord(cfOrgQuota, orgs)
+		if len(message.OrganizationGUIDs) > 0 && !anyMatchesFilter(record.OrganizationGUIDs, message.OrganizationGUIDs) {
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt < records[j].CreatedAt
+	})
+
+	return records, nil
+}
+
+func (r *OrgQuotaRepo) ApplyOrgQuota(ctx context.Context, authInfo authorization.Info, message ApplyOrgQuotaMessage) (OrgQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return OrgQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfOrgQuota := &korifiv1alpha1.CFOrgQuota{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: message.GUID}, cfOrgQuota)
+	if err != nil {
+		return OrgQuotaRecord{}, fmt.Errorf("failed to get org quota: %w", apierrors.FromK8sError(err, OrgQuotaResourceType))
+	}
+
+	cfOrgs := make([]*korifiv1alpha1.CFOrg, 0, len(message.OrganizationGUIDs))
+	var missing []string
+	for _, orgGUID := range message.OrganizationGUIDs {
+		cfOrg := &korifiv1alpha1.CFOrg{}
+		err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: orgGUID}, cfOrg)
+		if err != nil {
+			if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+				missing = append(missing, orgGUID)
+				continue
+			}
+			return OrgQuotaRecord{}, apierrors.FromK8sError(err, OrgResourceType)
+		}
+		cfOrgs = append(cfOrgs, cfOrg)
+	}
+
+	if len(missing) > 0 {
+		return OrgQuotaRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("orgs %v not found", missing),
+			fmt.Sprintf("Organizations with guids %s do not exist, or you do not have access to them.", quotedList(missing)),
+		)
+	}
+
+	for _, cfOrg := range cfOrgs {
+		err = k8s.PatchResource(ctx, userClient, cfOrg, func() {
+			cfOrg.Spec.QuotaRef.Name = message.GUID
+		})
+		if err != nil {
+			return OrgQuotaRecord{}, apierrors.FromK8sError(err, OrgResourceType)
+		}
+	}
+
+	return r.GetOrgQuota(ctx, authInfo, message.GUID)
+}
+
+func (r *OrgQuotaRepo) DeleteOrgQuota(ctx context.Context, authInfo authorization.Info, guid string) error {
+	record, err := r.GetOrgQuota(ctx, authInfo, guid)
+	if err != nil {
+		return err
+	}
+
+	if len(record.OrganizationGUIDs) > 0 {
+		return apierrors.NewUnprocessableEntityError(
+			errors.New("org quota is still applied to orgs"),
+			"This quota is applied to one or more organizations. Remove this quota from all organizations before deleting.",
+		)
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.Delete(ctx, &korifiv1alpha1.CFOrgQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      guid,
+			Namespace: r.rootNamespace,
+		},
+	})
+
+	return apierrors.FromK8sError(err, OrgQuotaResourceType)
+}
+
+func (r *OrgQuotaRepo) listOrgs(ctx context.Context, userClient client.Client) ([]korifiv1alpha1.CFOrg, error) {
+	cfOrgList := &korifiv1alpha1.CFOrgList{}
+	err := userClient.List(ctx, cfOrgList, client.InNamespace(r.rootNamespace))
+	if err != nil {
+		if k8serrors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, apierrors.FromK8sError(err, OrgResourceType)
+	}
+
+	return cfOrgList.Items, nil
+}
+
+func cfOrgQuotaToOrgQuotaRecord(cfOrgQuota korifiv1alpha1.CFOrgQuota, orgs []korifiv1alpha1.CFOrg) OrgQuotaRecord {
+	orgGUIDs := []string{}
+	for _, org := range orgs {
+		if org.Spec.QuotaRef.Name == cfOrgQuota.Name {
+			orgGUIDs = append(orgGUIDs, org.Name)
+		}
+	}
+
+	updatedAtTime, _ := getTimeLastUpdatedTimestamp(&cfOrgQuota.ObjectMeta)
+	return OrgQuotaRecord{
+		GUID:              cfOrgQuota.Name,
+		Name:              cfOrgQuota.Spec.DisplayName,
+		Apps:              quotaAppsFromCF(cfOrgQuota.Spec.Apps),
+		Services:          quotaServicesFromCF(cfOrgQuota.Spec.Services),
+		Routes:            quotaRoutesFromCF(cfOrgQuota.Spec.Routes),
+		Domains:           QuotaDomains{TotalDomains: cfOrgQuota.Spec.Domains.TotalDomains},
+		OrganizationGUIDs: orgGUIDs,
+		CreatedAt:         formatTimestamp(cfOrgQuota.CreationTimestamp),
+		UpdatedAt:         updatedAtTime,
+	}
+}
+
+func (a QuotaApps) toCFAppsLimits() korifiv1alpha1.QuotaAppsLimits {
+	return korifiv1alpha1.QuotaAppsLimits{
+		TotalMemoryInMB:              a.TotalMemoryInMB,
+		PerProcessMemoryInMB:         a.PerProcessMemoryInMB,
+		TotalInstances:               a.TotalInstances,
+		PerAppTasks:                  a.PerAppTasks,
+		LogRateLimitInBytesPerSecond: a.LogRateLimitInBytesPerSecond,
+	}
+}
+
+func (s QuotaServices) toCFServicesLimits() korifiv1alpha1.QuotaServicesLimits {
+	return korifiv1alpha1.QuotaServicesLimits{
+		PaidServicesAllowed:   s.PaidServicesAllowed,
+		TotalServiceInstances: s.TotalServiceInstances,
+		TotalServiceKeys:      s.TotalServiceKeys,
+	}
+}
+
+func (r QuotaRoutes) toCFRoutesLimits() korifiv1alpha1.QuotaRoutesLimits {
+	return korifiv1alpha1.QuotaRoutesLimits{
+		TotalRoutes:        r.TotalRoutes,
+		TotalReservedPorts: r.TotalReservedPorts,
+	}
+}
+
+func quotaAppsFromCF(limits korifiv1alpha1.QuotaAppsLimits) QuotaApps {
+	return QuotaApps{
+		TotalMemoryInMB:              limits.TotalMemoryInMB,
+		PerProcessMemoryInMB:         limits.PerProcessMemoryInMB,
+		TotalInstances:               limits.TotalInstances,
+		PerAppTasks:                  limits.PerAppTasks,
+		LogRateLimitInBytesPerSecond: limits.LogRateLimitInBytesPerSecond,
+	}
+}
+
+func quotaServicesFromCF(limits korifiv1alpha1.QuotaServicesLimits) QuotaServices {
+	return QuotaServices{
+		PaidServicesAllowed:   limits.PaidServicesAllowed,
+		TotalServiceInstances: limits.TotalServiceInstances,
+		TotalServiceKeys:      limits.TotalServiceKeys,
+	}
+}
+
+func quotaRoutesFromCF(limits korifiv1alpha1.QuotaRoutesLimits) QuotaRoutes {
+	return QuotaRoutes{
+		TotalRoutes:        limits.TotalRoutes,
+		TotalReservedPorts: limits.TotalReservedPorts,
+	}
+}
+
+func anyMatchesFilter(fields []string, filter []string) bool {
+	for _, field := range fields {
+		if matchesFilter(field, filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func quotedList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(quoted, ", "))
+}
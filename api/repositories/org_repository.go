@@ -76,7 +76,7 @@ func NewOrgRepo(
 }
 
 func (r *OrgRepo) CreateOrg(ctx context.Context, info authorization.Info, message CreateOrgMessage) (OrgRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return OrgRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -149,7 +149,7 @@ func (r *OrgRepo) ListOrgs(ctx context.Context, info authorization.Info, filter
 		return nil, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return []OrgRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -198,7 +198,7 @@ func (r *OrgRepo) GetOrg(ctx context.Context, info authorization.Info, orgGUID s
 }
 
 func (r *OrgRepo) DeleteOrg(ctx context.Context, info authorization.Info, message DeleteOrgMessage) error {
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -213,7 +213,7 @@ func (r *OrgRepo) DeleteOrg(ctx context.Context, info authorization.Info, messag
 }
 
 func (r *OrgRepo) PatchOrgMetadata(ctx context.Context, authInfo authorization.Info, message PatchOrgMetadataMessage) (OrgRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return OrgRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -207,6 +207,27 @@ var _ = Describe("OrgRepository", func() {
 			))
 		})
 
+		When("the user is only an auditor or billing manager of an org", func() {
+			var auditedOrg, billedOrg *korifiv1alpha1.CFOrg
+
+			BeforeEach(func() {
+				auditedOrg = createOrgWithCleanup(ctx, prefixedGUID("audited-org"))
+				createRoleBinding(ctx, userName, orgAuditorRole.Name, auditedOrg.Name)
+				billedOrg = createOrgWithCleanup(ctx, prefixedGUID("billed-org"))
+				createRoleBinding(ctx, userName, orgBillingManagerRole.Name, billedOrg.Name)
+			})
+
+			It("lists the org", func() {
+				orgs, err := orgRepo.ListOrgs(ctx, authInfo, repositories.ListOrgsMessage{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(orgs).To(ContainElements(
+					MatchFields(IgnoreExtras, Fields{"GUID": Equal(auditedOrg.Name)}),
+					MatchFields(IgnoreExtras, Fields{"GUID": Equal(billedOrg.Name)}),
+				))
+			})
+		})
+
 		When("the org is not ready", func() {
 			BeforeEach(func() {
 				meta.SetStatusCondition(&(cfOrg1.Status.Conditions), metav1.Condition{
@@ -111,7 +111,7 @@ type UpdatePackageSourceMessage struct {
 }
 
 func (r *PackageRepo) CreatePackage(ctx context.Context, authInfo authorization.Info, message CreatePackageMessage) (PackageRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return PackageRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -131,7 +131,7 @@ func (r *PackageRepo) UpdatePackage(ctx context.Context, authInfo authorization.
 		return PackageRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return PackageRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -157,7 +157,7 @@ func (r *PackageRepo) GetPackage(ctx context.Context, authInfo authorization.Inf
 		return PackageRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return PackageRecord{}, fmt.Errorf("failed to build user k8s client: %w", err)
 	}
@@ -175,7 +175,7 @@ func (r *PackageRepo) ListPackages(ctx context.Context, authInfo authorization.I
 	if err != nil {
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []PackageRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -248,7 +248,7 @@ func applyPackageFilter(packages []korifiv1alpha1.CFPackage, message ListPackage
 }
 
 func (r *PackageRepo) UpdatePackageSource(ctx context.Context, authInfo authorization.Info, message UpdatePackageSourceMessage) (PackageRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return PackageRecord{}, fmt.Errorf("failed to build user k8s client: %w", err)
 	}
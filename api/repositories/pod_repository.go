@@ -1,19 +1,15 @@
 package repositories
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"strconv"
-	"strings"
 	"time"
 
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 
-	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,7 +34,6 @@ const (
 	unknownState             = "DOWN"
 	ProcessStatsResourceType = "Process Stats"
 	PodMetricsResourceType   = "Pod Metrics"
-	appLogSourceType         = "APP"
 )
 
 type PodRepo struct {
@@ -60,10 +55,20 @@ func NewPodRepo(
 }
 
 type PodStatsRecord struct {
-	Type  string
-	Index int
-	State string `default:"DOWN"`
-	Usage Usage
+	Type      string
+	Index     int
+	State     string `default:"DOWN"`
+	Usage     Usage
+	Uptime    *int64
+	Restarts  int32
+	LastCrash *CrashRecord
+}
+
+// CrashRecord describes the last termination of an instance that has been restarted
+type CrashRecord struct {
+	Reason    string
+	ExitCode  int32
+	CrashedAt time.Time
 }
 
 type Usage struct {
@@ -114,11 +119,15 @@ func (r *PodRepo) ListPodStats(ctx context.Context, authInfo authorization.Info,
 			return nil, err
 		}
 
-		podState := getPodState(p)
-		if podState == "DOWN" {
+		if index >= len(records) {
 			continue
 		}
-		if index >= len(records) {
+
+		// crash details are reported for instances that are down too, as they explain why
+		setRestartDetails(&records[index], p)
+
+		podState := getPodState(p)
+		if podState == "DOWN" {
 			continue
 		}
 		records[index].State = podState
@@ -156,8 +165,87 @@ func (r *PodRepo) ListPodStats(ctx context.Context, authInfo authorization.Info,
 	return records, nil
 }
 
+type ListPodMetricsMessage struct {
+	Namespace string
+	AppGUID   string
+}
+
+// PodMetricsRecord holds the resource usage of an app instance at a point in time, together with its quotas
+type PodMetricsRecord struct {
+	ProcessGUID string
+	ProcessType string
+	Index       int
+	Timestamp   time.Time
+	CPU         float64
+	Mem         int64
+	Disk        int64
+	MemQuota    int64
+	DiskQuota   int64
+}
+
+// ListPodMetrics returns the current metrics of every instance of the app that is up. Instances without metrics
+// yet are skipped.
+func (r *PodRepo) ListPodMetrics(ctx context.Context, authInfo authorization.Info, message ListPodMetricsMessage) ([]PodMetricsRecord, error) {
+	labelSelector, err := labels.ValidatedSelectorFromSet(map[string]string{
+		korifiv1alpha1.CFAppGUIDLabelKey: message.AppGUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := r.ListPods(ctx, authInfo, client.ListOptions{Namespace: message.Namespace, LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	records := []PodMetricsRecord{}
+	for _, p := range pods {
+		if getPodState(p) == crashedState {
+			continue
+		}
+
+		index, err := extractIndex(p)
+		if err != nil {
+			continue
+		}
+
+		podMetrics, err := r.metricsFetcher(ctx, p.Namespace, p.Name)
+		if err != nil {
+			continue
+		}
+
+		record := PodMetricsRecord{
+			ProcessGUID: p.Labels[LabelGUID],
+			ProcessType: p.Labels[korifiv1alpha1.CFProcessTypeLabelKey],
+			Index:       index,
+			Timestamp:   podMetrics.Timestamp.Time,
+		}
+
+		metricsMap := aggregateContainerMetrics(podMetrics.Containers)
+		if CPUquantity, ok := metricsMap["cpu"]; ok {
+			// the same fraction of cores as in the process stats
+			record.CPU = float64(CPUquantity.ScaledValue(resource.Nano)) / 1e9
+		}
+		if memQuantity, ok := metricsMap["memory"]; ok {
+			record.Mem = memQuantity.Value()
+		}
+		if storageQuantity, ok := metricsMap["storage"]; ok {
+			record.Disk = storageQuantity.Value()
+		}
+
+		if container, err := extractProcessContainer(p.Spec.Containers); err == nil {
+			record.MemQuota = container.Resources.Limits.Memory().Value()
+			record.DiskQuota = container.Resources.Limits.StorageEphemeral().Value()
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
 func (r *PodRepo) ListPods(ctx context.Context, authInfo authorization.Info, listOpts client.ListOptions) ([]corev1.Pod, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -213,6 +301,37 @@ func extractIndex(pod corev1.Pod) (int, error) {
 	return index, nil
 }
 
+func setRestartDetails(record *PodStatsRecord, pod corev1.Pod) {
+	status := applicationContainerStatus(pod.Status.ContainerStatuses)
+	if status == nil {
+		return
+	}
+
+	if status.State.Running != nil && !status.State.Running.StartedAt.IsZero() {
+		uptime := int64(time.Since(status.State.Running.StartedAt.Time).Seconds())
+		record.Uptime = &uptime
+	}
+
+	record.Restarts = status.RestartCount
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		record.LastCrash = &CrashRecord{
+			Reason:    terminated.Reason,
+			ExitCode:  terminated.ExitCode,
+			CrashedAt: terminated.FinishedAt.Time,
+		}
+	}
+}
+
+func applicationContainerStatus(statuses []corev1.ContainerStatus) *corev1.ContainerStatus {
+	for i, status := range statuses {
+		if status.Name == ApplicationContainerName {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
+
 func getPodState(pod corev1.Pod) string {
 	if len(pod.Status.ContainerStatuses) == 0 || pod.Status.Phase == corev1.PodUnknown {
 		return unknownState
@@ -331,96 +450,3 @@ func aggregateContainerMetrics(containers []metricsv1beta1.ContainerMetrics) map
 
 	return metrics
 }
-
-type RuntimeLogsMessage struct {
-	SpaceGUID   string
-	AppGUID     string
-	AppRevision string
-	Limit       int64
-}
-
-func (r *PodRepo) GetRuntimeLogsForApp(ctx context.Context, logger logr.Logger, authInfo authorization.Info, message RuntimeLogsMessage) ([]LogRecord, error) {
-	labelSelector, err := labels.ValidatedSelectorFromSet(map[string]string{
-		korifiv1alpha1.CFAppGUIDLabelKey:  message.AppGUID,
-		"korifi.cloudfoundry.org/version": message.AppRevision,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to build labelSelector: %w", err)
-	}
-	listOpts := client.ListOptions{Namespace: message.SpaceGUID, LabelSelector: labelSelector}
-
-	pods, err := r.ListPods(ctx, authInfo, listOpts)
-	if err != nil {
-		return nil, err
-	}
-
-	appLogs := make([]LogRecord, 0, int64(len(pods))*message.Limit/2)
-
-	k8sClient, err := r.userClientFactory.BuildK8sClient(authInfo)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build user client: %w", err)
-	}
-
-	for _, pod := range pods {
-		var logReadCloser io.ReadCloser
-		logReadCloser, err = k8sClient.CoreV1().Pods(message.SpaceGUID).GetLogs(pod.Name, &corev1.PodLogOptions{Timestamps: true, TailLines: &message.Limit}).Stream(ctx)
-		if err != nil {
-			// untested
-			logger.Info(fmt.Sprintf("failed to fetch logs for pod: %s", pod.Name), "err", err)
-			continue
-		}
-
-		r := bufio.NewReader(logReadCloser)
-		for {
-			var line []byte
-			line, err = r.ReadBytes('\n')
-			if err != nil {
-				if err == io.EOF {
-					break
-				} else {
-					_ = logReadCloser.Close()
-					return nil, fmt.Errorf("failed to parse pod logs: %w", err)
-				}
-			}
-
-			logRecord := lineToAppLogRecord(line)
-
-			appLogs = append(appLogs, logRecord)
-		}
-
-		_ = logReadCloser.Close()
-	}
-
-	return appLogs, nil
-}
-
-func lineToAppLogRecord(line []byte) LogRecord {
-	logLine := string(line)
-	var logTime int64
-	logLine, logTime, _ = parseRFC3339NanoTime(logLine)
-
-	// trim trailing newlines so that the CLI doesn't render extra log lines for them
-	logLine = strings.TrimRight(logLine, "\r\n")
-
-	logRecord := LogRecord{
-		Message:   logLine,
-		Timestamp: logTime,
-		Tags: map[string]string{
-			"source_type": appLogSourceType,
-		},
-	}
-	return logRecord
-}
-
-func parseRFC3339NanoTime(input string) (string, int64, error) {
-	if len(input) < 30 {
-		return input, 0, fmt.Errorf("string not long enough")
-	}
-
-	t, err := time.Parse(time.RFC3339Nano, input[:30])
-	if err != nil {
-		return input, 0, err
-	}
-
-	return input[31:], t.UnixNano(), nil
-}
@@ -306,6 +306,73 @@ var _ = Describe("PodRepository", func() {
 				})
 			})
 
+			When("the application container of a pod has been restarted", func() {
+				var (
+					startedAt  time.Time
+					finishedAt time.Time
+				)
+
+				BeforeEach(func() {
+					message.Instances = 3
+					startedAt = time.Now().Add(-time.Minute).Truncate(time.Second)
+					finishedAt = startedAt.Add(-time.Second)
+
+					pod3 := createPodDef(prefixedGUID("pod3"), spaceGUID, appGUID, processGUID, "2", "1")
+					Expect(k8sClient.Create(ctx, pod3)).To(Succeed())
+
+					pod3.Status = corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name: ApplicationContainerName,
+								State: corev1.ContainerState{
+									Running: &corev1.ContainerStateRunning{
+										StartedAt: metav1.NewTime(startedAt),
+									},
+								},
+								LastTerminationState: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{
+										Reason:     "OOMKilled",
+										ExitCode:   137,
+										FinishedAt: metav1.NewTime(finishedAt),
+									},
+								},
+								RestartCount: 4,
+								Ready:        true,
+							},
+						},
+					}
+					Expect(k8sClient.Status().Update(ctx, pod3)).To(Succeed())
+				})
+
+				It("reports the uptime and the last crash of the instance", func() {
+					Expect(listStatsErr).NotTo(HaveOccurred())
+
+					Expect(records).To(MatchElementsWithIndex(matchElementsWithIndexIDFn, IgnoreExtras, Elements{
+						"0": MatchFields(IgnoreExtras, Fields{
+							"Restarts":  BeZero(),
+							"LastCrash": BeNil(),
+						}),
+						"1": MatchFields(IgnoreExtras, Fields{
+							"Uptime":    BeNil(),
+							"Restarts":  BeZero(),
+							"LastCrash": BeNil(),
+						}),
+						"2": MatchFields(IgnoreExtras, Fields{
+							"Index":    Equal(2),
+							"State":    Equal("RUNNING"),
+							"Uptime":   PointTo(BeNumerically(">=", 60)),
+							"Restarts": Equal(int32(4)),
+							"LastCrash": PointTo(MatchAllFields(Fields{
+								"Reason":    Equal("OOMKilled"),
+								"ExitCode":  Equal(int32(137)),
+								"CrashedAt": BeTemporally("==", finishedAt),
+							})),
+						}),
+					}))
+				})
+			})
+
 			When("MetricFetcherFunction return an metrics resource not found error", func() {
 				BeforeEach(func() {
 					metricFetcherFn.Returns(nil, errors.New("boom"))
@@ -369,6 +436,99 @@ var _ = Describe("PodRepository", func() {
 			})
 		})
 	})
+
+	Describe("ListPodMetrics", func() {
+		var (
+			records        []PodMetricsRecord
+			listMetricsErr error
+			metricsTime    time.Time
+		)
+
+		BeforeEach(func() {
+			runningPod := createPodDef(pod1Name, spaceGUID, appGUID, processGUID, "1", "1")
+			runningPod.Labels[korifiv1alpha1.CFProcessTypeLabelKey] = "web"
+			runningPod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+				corev1.ResourceMemory:           resource.MustParse("1Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+			}
+			Expect(k8sClient.Create(ctx, runningPod)).To(Succeed())
+			runningPod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "application",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+					Ready: true,
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, runningPod)).To(Succeed())
+
+			crashedPod := createPodDef(pod2Name, spaceGUID, appGUID, processGUID, "0", "1")
+			Expect(k8sClient.Create(ctx, crashedPod)).To(Succeed())
+			crashedPod.Status = corev1.PodStatus{Phase: corev1.PodFailed}
+			Expect(k8sClient.Status().Update(ctx, crashedPod)).To(Succeed())
+
+			otherAppPod := createPodDef(prefixedGUID("other-app-pod"), spaceGUID, uuid.NewString(), processGUID, "0", "1")
+			Expect(k8sClient.Create(ctx, otherAppPod)).To(Succeed())
+
+			metricsTime = time.Now()
+			metricFetcherFn.Returns(&metricsv1beta1.PodMetrics{
+				Timestamp: metav1.NewTime(metricsTime),
+				Containers: []metricsv1beta1.ContainerMetrics{{
+					Name: "application",
+					Usage: corev1.ResourceList{
+						corev1.ResourceCPU:     resource.MustParse("250m"),
+						corev1.ResourceMemory:  resource.MustParse("100Mi"),
+						corev1.ResourceStorage: resource.MustParse("200Mi"),
+					},
+				}},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			records, listMetricsErr = podRepo.ListPodMetrics(ctx, authInfo, ListPodMetricsMessage{
+				Namespace: spaceGUID,
+				AppGUID:   appGUID,
+			})
+		})
+
+		When("authorized in the space", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, spaceGUID)
+			})
+
+			It("returns the metrics of the app instances that are up", func() {
+				Expect(listMetricsErr).NotTo(HaveOccurred())
+				Expect(records).To(ConsistOf(MatchAllFields(Fields{
+					"ProcessGUID": Equal(processGUID),
+					"ProcessType": Equal("web"),
+					"Index":       Equal(1),
+					"Timestamp":   BeTemporally("~", metricsTime, time.Second),
+					"CPU":         Equal(0.25),
+					"Mem":         Equal(int64(100 * 1024 * 1024)),
+					"Disk":        Equal(int64(200 * 1024 * 1024)),
+					"MemQuota":    Equal(int64(1024 * 1024 * 1024)),
+					"DiskQuota":   Equal(int64(2 * 1024 * 1024 * 1024)),
+				})))
+			})
+
+			When("fetching the metrics of a pod fails", func() {
+				BeforeEach(func() {
+					metricFetcherFn.Returns(nil, errors.New("metrics not available yet"))
+				})
+
+				It("skips the pod", func() {
+					Expect(listMetricsErr).NotTo(HaveOccurred())
+					Expect(records).To(BeEmpty())
+				})
+			})
+		})
+
+		When("the user is not authorized in the space", func() {
+			It("returns a forbidden error", func() {
+				Expect(listMetricsErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+	})
 })
 
 func createPodDef(name, namespace, appGUID, processGUID, index, version string) *corev1.Pod {
@@ -110,7 +110,7 @@ func (r *ProcessRepo) GetProcess(ctx context.Context, authInfo authorization.Inf
 		return ProcessRecord{}, err
 	}
 
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return ProcessRecord{}, fmt.Errorf("get-process: failed to build user k8s client: %w", err)
 	}
@@ -130,7 +130,7 @@ func (r *ProcessRepo) ListProcesses(ctx context.Context, authInfo authorization.
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []ProcessRecord{}, fmt.Errorf("get-process: failed to build user k8s client: %w", err)
 	}
@@ -156,7 +156,7 @@ func (r *ProcessRepo) ListProcesses(ctx context.Context, authInfo authorization.
 }
 
 func (r *ProcessRepo) ScaleProcess(ctx context.Context, authInfo authorization.Info, scaleProcessMessage ScaleProcessMessage) (ProcessRecord, error) {
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return ProcessRecord{}, fmt.Errorf("get-process: failed to build user k8s client: %w", err)
 	}
@@ -186,7 +186,7 @@ func (r *ProcessRepo) ScaleProcess(ctx context.Context, authInfo authorization.I
 }
 
 func (r *ProcessRepo) CreateProcess(ctx context.Context, authInfo authorization.Info, message CreateProcessMessage) error {
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return fmt.Errorf("get-process: failed to build user k8s client: %w", err)
 	}
@@ -217,7 +217,7 @@ func (r *ProcessRepo) CreateProcess(ctx context.Context, authInfo authorization.
 func (r *ProcessRepo) GetProcessByAppTypeAndSpace(ctx context.Context, authInfo authorization.Info, appGUID, processType, spaceGUID string) (ProcessRecord, error) {
 	// Could narrow down process results via AppGUID label, but that is set up by a webhook that isn't configured in our integration tests
 	// For now, don't use labels
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return ProcessRecord{}, fmt.Errorf("get-process-by-app-type-and-space: failed to build user k8s client: %w", err)
 	}
@@ -239,7 +239,7 @@ func (r *ProcessRepo) GetProcessByAppTypeAndSpace(ctx context.Context, authInfo
 }
 
 func (r *ProcessRepo) PatchProcess(ctx context.Context, authInfo authorization.Info, message PatchProcessMessage) (ProcessRecord, error) {
-	userClient, err := r.clientFactory.BuildClient(authInfo)
+	userClient, err := r.clientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return ProcessRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -244,6 +244,17 @@ var _ = Describe("ProcessRepo", func() {
 			Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
 		})
 
+		When("the user has the SpaceSupporter role", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, spaceSupporterRole.Name, space1.Name)
+			})
+
+			It("returns a forbidden error", func() {
+				_, err := processRepo.ScaleProcess(ctx, authInfo, *scaleProcessMessage)
+				Expect(err).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+			})
+		})
+
 		When("the user has the SpaceDeveloper role", func() {
 			BeforeEach(func() {
 				createRoleBinding(ctx, userName, spaceDeveloperRole.Name, space1.Name)
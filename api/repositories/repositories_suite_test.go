@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -45,6 +46,8 @@ var (
 	ctx                   context.Context
 	testEnv               *envtest.Environment
 	k8sClient             client.WithWatch
+	permissionsCache      crcache.Cache
+	stopPermissionsCache  context.CancelFunc
 	namespaceRetriever    repositories.NamespaceRetriever
 	userClientFactory     authorization.UserK8sClientFactory
 	k8sConfig             *rest.Config
@@ -60,6 +63,9 @@ var (
 	orgManagerRole        *rbacv1.ClusterRole
 	orgUserRole           *rbacv1.ClusterRole
 	spaceAuditorRole      *rbacv1.ClusterRole
+	spaceSupporterRole    *rbacv1.ClusterRole
+	orgAuditorRole        *rbacv1.ClusterRole
+	orgBillingManagerRole *rbacv1.ClusterRole
 	rootNamespaceUserRole *rbacv1.ClusterRole
 )
 
@@ -88,6 +94,17 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	permissionsCache, err = crcache.New(k8sConfig, crcache.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(authorization.IndexRoleBindingSubjects(context.Background(), permissionsCache)).To(Succeed())
+	var cacheCtx context.Context
+	cacheCtx, stopPermissionsCache = context.WithCancel(context.Background())
+	go func() {
+		defer GinkgoRecover()
+		Expect(permissionsCache.Start(cacheCtx)).To(Succeed())
+	}()
+	Expect(permissionsCache.WaitForCacheSync(context.Background())).To(BeTrue())
+
 	dynamicClient, err := dynamic.NewForConfig(k8sConfig)
 	Expect(err).NotTo(HaveOccurred())
 	Expect(dynamicClient).NotTo(BeNil())
@@ -100,10 +117,16 @@ var _ = BeforeSuite(func() {
 	spaceDeveloperRole = createClusterRole(context.Background(), "cf_space_developer")
 	spaceManagerRole = createClusterRole(context.Background(), "cf_space_manager")
 	spaceAuditorRole = createClusterRole(context.Background(), "cf_space_auditor")
+	spaceSupporterRole = createClusterRole(context.Background(), "cf_space_supporter")
+	orgAuditorRole = createClusterRole(context.Background(), "cf_org_auditor")
+	orgBillingManagerRole = createClusterRole(context.Background(), "cf_org_billing_manager")
 	rootNamespaceUserRole = createClusterRole(context.Background(), "cf_root_namespace_user")
 })
 
 var _ = AfterSuite(func() {
+	if stopPermissionsCache != nil {
+		stopPermissionsCache()
+	}
 	Expect(testEnv.Stop()).To(Succeed())
 })
 
@@ -115,10 +138,10 @@ var _ = BeforeEach(func() {
 	rootNamespace = prefixedGUID("root-ns")
 	builderName = "kpack-image-builder"
 	tokenInspector := authorization.NewTokenReviewer(k8sClient)
-	certInspector := authorization.NewCertInspector(k8sConfig)
+	certInspector := authorization.NewCertInspector(k8sConfig, authorization.NewCertificatePolicy(0))
 	baseIDProvider := authorization.NewCertTokenIdentityProvider(tokenInspector, certInspector)
 	idProvider = authorization.NewCachingIdentityProvider(baseIDProvider, cache.NewExpiring())
-	nsPerms = authorization.NewNamespacePermissions(k8sClient, idProvider)
+	nsPerms = authorization.NewNamespacePermissions(k8sClient, permissionsCache, idProvider)
 
 	mapper, err := apiutil.NewDynamicRESTMapper(k8sConfig)
 	Expect(err).NotTo(HaveOccurred())
@@ -162,6 +185,7 @@ func createOrgWithCleanup(ctx context.Context, displayName string) *korifiv1alph
 		},
 	}
 	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	waitForPermissionsCache(ctx, namespace)
 
 	DeferCleanup(func() {
 		_ = k8sClient.Delete(ctx, cfOrg)
@@ -202,6 +226,7 @@ func createSpaceWithCleanup(ctx context.Context, orgGUID, name string) *korifiv1
 		},
 	}
 	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	waitForPermissionsCache(ctx, namespace)
 
 	DeferCleanup(func() {
 		_ = k8sClient.Delete(ctx, cfSpace)
@@ -219,9 +244,17 @@ func createNamespace(ctx context.Context, orgName, name string, labels map[strin
 		},
 	}
 	Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	waitForPermissionsCache(ctx, namespace)
 	return namespace
 }
 
+// waitForPermissionsCache waits for the namespace permissions to see an object that has just been created
+func waitForPermissionsCache(ctx context.Context, obj client.Object) {
+	EventuallyWithOffset(2, func() error {
+		return permissionsCache.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+	}).Should(Succeed())
+}
+
 func createClusterRole(ctx context.Context, filename string) *rbacv1.ClusterRole {
 	filepath := filepath.Join("..", "..", "helm", "controllers", "templates", "cf_roles", filename+".yaml")
 	content, err := os.ReadFile(filepath)
@@ -254,4 +287,5 @@ func createRoleBinding(ctx context.Context, userName, roleName, namespace string
 		},
 	}
 	Expect(k8sClient.Create(ctx, &roleBinding)).To(Succeed())
+	waitForPermissionsCache(ctx, &roleBinding)
 }
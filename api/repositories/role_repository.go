@@ -25,6 +25,23 @@ const (
 	RoleResourceType      = "Role"
 )
 
+const (
+	orgRoleScope   = "organization"
+	spaceRoleScope = "space"
+)
+
+// roleScopes maps the CF role types that can be assigned through the API to the kind of namespace they are bound in
+var roleScopes = map[string]string{
+	"organization_auditor":         orgRoleScope,
+	"organization_billing_manager": orgRoleScope,
+	"organization_manager":         orgRoleScope,
+	"organization_user":            orgRoleScope,
+	"space_auditor":                spaceRoleScope,
+	"space_developer":              spaceRoleScope,
+	"space_manager":                spaceRoleScope,
+	"space_supporter":              spaceRoleScope,
+}
+
 //counterfeiter:generate -o fake -fake-name AuthorizedInChecker . AuthorizedInChecker
 
 type AuthorizedInChecker interface {
@@ -48,6 +65,7 @@ type RoleRecord struct {
 	Space     string
 	Org       string
 	User      string
+	UserGUID  string
 	Kind      string
 }
 
@@ -57,27 +75,36 @@ type RoleRepo struct {
 	authorizedInChecker AuthorizedInChecker
 	userClientFactory   authorization.UserK8sClientFactory
 	spaceRepo           *SpaceRepo
+	userRepo            *UserRepo
 }
 
-func NewRoleRepo(userClientFactory authorization.UserK8sClientFactory, spaceRepo *SpaceRepo, authorizedInChecker AuthorizedInChecker, rootNamespace string, roleMappings map[string]config.Role) *RoleRepo {
+func NewRoleRepo(userClientFactory authorization.UserK8sClientFactory, spaceRepo *SpaceRepo, userRepo *UserRepo, authorizedInChecker AuthorizedInChecker, rootNamespace string, roleMappings map[string]config.Role) *RoleRepo {
 	return &RoleRepo{
 		rootNamespace:       rootNamespace,
 		roleMappings:        roleMappings,
 		authorizedInChecker: authorizedInChecker,
 		userClientFactory:   userClientFactory,
 		spaceRepo:           spaceRepo,
+		userRepo:            userRepo,
 	}
 }
 
 func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info, role CreateRoleMessage) (RoleRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RoleRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
 
-	k8sRoleConfig, ok := r.roleMappings[role.Type]
-	if !ok {
-		return RoleRecord{}, fmt.Errorf("invalid role type: %q", role.Type)
+	k8sRoleConfig, err := r.roleConfigFor(role)
+	if err != nil {
+		return RoleRecord{}, err
+	}
+
+	if role.Kind == rbacv1.UserKind {
+		role.User, err = r.resolveUsername(ctx, role.User)
+		if err != nil {
+			return RoleRecord{}, err
+		}
 	}
 
 	userIdentity := authorization.Identity{
@@ -123,6 +150,14 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		}
 	}
 
+	var userGUID string
+	if role.Kind == rbacv1.UserKind {
+		if err = r.userRepo.RecordUser(ctx, role.User); err != nil {
+			return RoleRecord{}, err
+		}
+		userGUID = UserGUID(KubernetesUserOrigin, role.User)
+	}
+
 	roleRecord := RoleRecord{
 		GUID:      role.GUID,
 		CreatedAt: roleBinding.CreationTimestamp.Time,
@@ -131,12 +166,59 @@ func (r *RoleRepo) CreateRole(ctx context.Context, authInfo authorization.Info,
 		Space:     role.Space,
 		Org:       role.Org,
 		User:      role.User,
+		UserGUID:  userGUID,
 		Kind:      role.Kind,
 	}
 
 	return roleRecord, nil
 }
 
+// resolveUsername returns the username of the CFUser a role is assigned to by GUID. Anything that is not the
+// GUID of a CFUser is taken to be a username, as clients may refer to users that have not been recorded yet
+func (r *RoleRepo) resolveUsername(ctx context.Context, user string) (string, error) {
+	if _, err := uuid.Parse(user); err != nil {
+		return user, nil
+	}
+
+	username, found, err := r.userRepo.usernameFor(ctx, user)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return user, nil
+	}
+
+	return username, nil
+}
+
+func (r *RoleRepo) roleConfigFor(role CreateRoleMessage) (config.Role, error) {
+	scope, isAssignable := roleScopes[role.Type]
+	k8sRoleConfig, isMapped := r.roleMappings[role.Type]
+	if !isAssignable || !isMapped {
+		return config.Role{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("invalid role type: %q", role.Type),
+			fmt.Sprintf("Role type '%s' is not supported", role.Type),
+		)
+	}
+
+	if scope == spaceRoleScope && role.Space == "" {
+		return config.Role{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("role type %q requires a space", role.Type),
+			"relationships.space is a required field",
+		)
+	}
+
+	if scope == orgRoleScope && role.Org == "" {
+		return config.Role{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("role type %q requires an organization", role.Type),
+			"relationships.organization is a required field",
+		)
+	}
+
+	return k8sRoleConfig, nil
+}
+
 func (r *RoleRepo) validateOrgRequirements(ctx context.Context, role CreateRoleMessage, userIdentity authorization.Identity, authInfo authorization.Info) error {
 	space, err := r.spaceRepo.GetSpace(ctx, authInfo, role.Space)
 	if err != nil {
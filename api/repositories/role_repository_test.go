@@ -36,6 +36,8 @@ var _ = Describe("RoleRepository", func() {
 			"space_developer":      {Name: spaceDeveloperRole.Name},
 			"organization_manager": {Name: orgManagerRole.Name, Propagate: true},
 			"organization_user":    {Name: orgUserRole.Name},
+			"organization_auditor": {Name: orgAuditorRole.Name},
+			"space_supporter":      {Name: spaceSupporterRole.Name},
 			"cf_user":              {Name: rootNamespaceUserRole.Name},
 		}
 		orgRepo := repositories.NewOrgRepo(rootNamespace, k8sClient, userClientFactory, nsPerms, time.Millisecond*2000)
@@ -43,6 +45,7 @@ var _ = Describe("RoleRepository", func() {
 		roleRepo = repositories.NewRoleRepo(
 			userClientFactory,
 			spaceRepo,
+			repositories.NewUserRepo(rootNamespace, k8sClient, userClientFactory),
 			authorizedInChecker,
 			rootNamespace,
 			roleMappings,
@@ -120,6 +123,40 @@ var _ = Describe("RoleRepository", func() {
 				Expect(roleBinding.Subjects[0].Name).To(Equal("myuser@example.com"))
 			})
 
+			It("records the user", func() {
+				Expect(createdRole.UserGUID).To(Equal(repositories.UserGUID(repositories.KubernetesUserOrigin, "myuser@example.com")))
+
+				cfUser := korifiv1alpha1.CFUser{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: createdRole.UserGUID, Namespace: rootNamespace}, &cfUser)).To(Succeed())
+				Expect(cfUser.Spec.Username).To(Equal("myuser@example.com"))
+				Expect(cfUser.Spec.Origin).To(Equal(repositories.KubernetesUserOrigin))
+			})
+
+			When("the user is referred to by the GUID of a recorded user", func() {
+				BeforeEach(func() {
+					Expect(k8sClient.Create(ctx, &korifiv1alpha1.CFUser{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      repositories.UserGUID(repositories.KubernetesUserOrigin, "myuser@example.com"),
+							Namespace: rootNamespace,
+						},
+						Spec: korifiv1alpha1.CFUserSpec{
+							Username: "myuser@example.com",
+							Origin:   repositories.KubernetesUserOrigin,
+						},
+					})).To(Succeed())
+
+					roleCreateMessage.User = repositories.UserGUID(repositories.KubernetesUserOrigin, "myuser@example.com")
+				})
+
+				It("assigns the role to the username of that user", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(createdRole.User).To(Equal("myuser@example.com"))
+
+					roleBinding := getTheRoleBinding(expectedName, cfOrg.Name)
+					Expect(roleBinding.Subjects[0].Name).To(Equal("myuser@example.com"))
+				})
+			})
+
 			It("updated the create/updated timestamps", func() {
 				Expect(createdRole.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
 				Expect(createdRole.UpdatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
@@ -169,6 +206,11 @@ var _ = Describe("RoleRepository", func() {
 					Expect(roleBinding.Subjects[0].Name).To(Equal("my-service-account"))
 					Expect(roleBinding.Subjects[0].Kind).To(Equal(rbacv1.ServiceAccountKind))
 				})
+
+				It("does not record a user", func() {
+					Expect(createdRole.UserGUID).To(BeEmpty())
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Name: repositories.UserGUID(repositories.KubernetesUserOrigin, "my-service-account"), Namespace: rootNamespace}, &korifiv1alpha1.CFUser{})).To(MatchError(ContainSubstring("not found")))
+				})
 			})
 
 			When("the org does not exist", func() {
@@ -186,8 +228,44 @@ var _ = Describe("RoleRepository", func() {
 					roleCreateMessage.Type = "i-am-invalid"
 				})
 
-				It("returns an error", func() {
+				It("returns an unprocessable entity error", func() {
 					Expect(createErr).To(MatchError(ContainSubstring("invalid role type")))
+					Expect(createErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+				})
+			})
+
+			When("the role type is not mapped to a cluster role", func() {
+				BeforeEach(func() {
+					roleCreateMessage.Type = "organization_billing_manager"
+				})
+
+				It("returns an unprocessable entity error", func() {
+					Expect(createErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+				})
+			})
+
+			When("the role is an org auditor", func() {
+				BeforeEach(func() {
+					roleCreateMessage.Type = "organization_auditor"
+				})
+
+				It("binds the org auditor cluster role in the org namespace", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					// Sha256 sum of "organization_auditor::myuser@example.com"
+					roleBinding := getTheRoleBinding("cf-de782a71fa4620b8eb0f1d383fd0776a57a382a232c8fed9cea8caf3bba735ed", cfOrg.Name)
+					Expect(roleBinding.RoleRef.Name).To(Equal(orgAuditorRole.Name))
+				})
+			})
+
+			When("the role is a space role", func() {
+				BeforeEach(func() {
+					roleCreateMessage.Type = "space_supporter"
+				})
+
+				It("requires a space", func() {
+					var apiErr apierrors.UnprocessableEntityError
+					Expect(errors.As(createErr, &apiErr)).To(BeTrue())
+					Expect(apiErr.Detail()).To(Equal("relationships.space is a required field"))
 				})
 			})
 
@@ -340,6 +418,19 @@ var _ = Describe("RoleRepository", func() {
 			})
 		})
 
+		When("the role is a space supporter", func() {
+			BeforeEach(func() {
+				roleCreateMessage.Type = "space_supporter"
+			})
+
+			It("binds the space supporter cluster role in the space namespace", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+				// Sha256 sum of "space_supporter::myuser@example.com"
+				roleBinding := getTheRoleBinding("cf-84022b045125560d3115dd150d18513a3ec220f7a28bf692d6844a59ff373f45", cfSpace.Name)
+				Expect(roleBinding.RoleRef.Name).To(Equal(spaceSupporterRole.Name))
+			})
+		})
+
 		When("the user is already bound to that role", func() {
 			It("returns an unprocessable entity error", func() {
 				anotherRoleCreateMessage := repositories.CreateRoleMessage{
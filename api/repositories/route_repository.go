@@ -152,7 +152,7 @@ func (f *RouteRepo) GetRoute(ctx context.Context, authInfo authorization.Info, r
 		return RouteRecord{}, fmt.Errorf("failed to get namespace for route: %w", err)
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -172,7 +172,7 @@ func (f *RouteRepo) ListRoutes(ctx context.Context, authInfo authorization.Info,
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -233,7 +233,7 @@ func applyRouteListFilter(routes []korifiv1alpha1.CFRoute, message ListRoutesMes
 }
 
 func (f *RouteRepo) ListRoutesForApp(ctx context.Context, authInfo authorization.Info, appGUID string, spaceGUID string) ([]RouteRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -316,7 +316,7 @@ func cfRouteDestinationToDestination(cfRouteDestination korifiv1alpha1.Destinati
 
 func (f *RouteRepo) CreateRoute(ctx context.Context, authInfo authorization.Info, message CreateRouteMessage) (RouteRecord, error) {
 	cfRoute := message.toCFRoute()
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -330,7 +330,7 @@ func (f *RouteRepo) CreateRoute(ctx context.Context, authInfo authorization.Info
 }
 
 func (f *RouteRepo) DeleteRoute(ctx context.Context, authInfo authorization.Info, message DeleteRouteMessage) error {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -358,7 +358,7 @@ func (f *RouteRepo) GetOrCreateRoute(ctx context.Context, authInfo authorization
 }
 
 func (f *RouteRepo) AddDestinationsToRoute(ctx context.Context, authInfo authorization.Info, message AddDestinationsToRouteMessage) (RouteRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -380,7 +380,7 @@ func (f *RouteRepo) AddDestinationsToRoute(ctx context.Context, authInfo authori
 }
 
 func (f *RouteRepo) RemoveDestinationFromRoute(ctx context.Context, authInfo authorization.Info, message RemoveDestinationFromRouteMessage) (RouteRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -471,7 +471,7 @@ func destinationRecordsToCFDestinations(destinationRecords []DestinationRecord)
 }
 
 func (f *RouteRepo) PatchRouteMetadata(ctx context.Context, authInfo authorization.Info, message PatchRouteMetadataMessage) (RouteRecord, error) {
-	userClient, err := f.userClientFactory.BuildClient(authInfo)
+	userClient, err := f.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return RouteRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
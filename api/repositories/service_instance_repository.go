@@ -8,6 +8,7 @@ import (
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/authorization"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
@@ -46,13 +47,24 @@ func NewServiceInstanceRepo(
 }
 
 type CreateServiceInstanceMessage struct {
-	Name        string
-	SpaceGUID   string
-	Credentials map[string]string
-	Type        string
-	Tags        []string
-	Labels      map[string]string
-	Annotations map[string]string
+	Name           string
+	SpaceGUID      string
+	Credentials    map[string]string
+	Type           string
+	Tags           []string
+	SyslogDrainURL string
+	Labels         map[string]string
+	Annotations    map[string]string
+}
+
+type PatchServiceInstanceMessage struct {
+	GUID           string
+	SpaceGUID      string
+	Name           *string
+	Credentials    *map[string]string
+	Tags           *[]string
+	SyslogDrainURL *string
+	MetadataPatch  MetadataPatch
 }
 
 type ListServiceInstanceMessage struct {
@@ -68,18 +80,19 @@ type DeleteServiceInstanceMessage struct {
 }
 
 type ServiceInstanceRecord struct {
-	Name       string
-	GUID       string
-	SpaceGUID  string
-	SecretName string
-	Tags       []string
-	Type       string
-	CreatedAt  string
-	UpdatedAt  string
+	Name           string
+	GUID           string
+	SpaceGUID      string
+	SecretName     string
+	Tags           []string
+	Type           string
+	SyslogDrainURL string
+	CreatedAt      string
+	UpdatedAt      string
 }
 
 func (r *ServiceInstanceRepo) CreateServiceInstance(ctx context.Context, authInfo authorization.Info, message CreateServiceInstanceMessage) (ServiceInstanceRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		// untested
 		return ServiceInstanceRecord{}, fmt.Errorf("failed to build user client: %w", err)
@@ -108,6 +121,64 @@ func (r *ServiceInstanceRepo) CreateServiceInstance(ctx context.Context, authInf
 	return cfServiceInstanceToServiceInstanceRecord(cfServiceInstance), nil
 }
 
+func (r *ServiceInstanceRepo) PatchServiceInstance(ctx context.Context, authInfo authorization.Info, message PatchServiceInstanceMessage) (ServiceInstanceRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return ServiceInstanceRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfServiceInstance := new(korifiv1alpha1.CFServiceInstance)
+	// fetch the instance first, so that fields can be cleared by the patch
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: message.SpaceGUID, Name: message.GUID}, cfServiceInstance)
+	if err != nil {
+		return ServiceInstanceRecord{}, fmt.Errorf("failed to get service instance: %w", apierrors.FromK8sError(err, ServiceInstanceResourceType))
+	}
+
+	err = k8s.PatchResource(ctx, userClient, cfServiceInstance, func() {
+		if message.Name != nil {
+			cfServiceInstance.Spec.DisplayName = *message.Name
+		}
+		if message.Tags != nil {
+			cfServiceInstance.Spec.Tags = *message.Tags
+		}
+		if message.SyslogDrainURL != nil {
+			cfServiceInstance.Spec.SyslogDrainURL = *message.SyslogDrainURL
+		}
+
+		if cfServiceInstance.GetAnnotations() == nil {
+			cfServiceInstance.SetAnnotations(map[string]string{})
+		}
+		if cfServiceInstance.GetLabels() == nil {
+			cfServiceInstance.SetLabels(map[string]string{})
+		}
+		patchMap(cfServiceInstance.GetAnnotations(), message.MetadataPatch.Annotations)
+		patchMap(cfServiceInstance.GetLabels(), message.MetadataPatch.Labels)
+	})
+	if err != nil {
+		return ServiceInstanceRecord{}, fmt.Errorf("failed to patch service instance: %w", apierrors.FromK8sError(err, ServiceInstanceResourceType))
+	}
+
+	if message.Credentials != nil {
+		secretObj := cfServiceInstanceToSecret(*cfServiceInstance)
+		_, err = controllerutil.CreateOrPatch(ctx, userClient, &secretObj, func() error {
+			// credentials are replaced as a whole
+			secretObj.Data = nil
+			secretObj.StringData = *message.Credentials
+			if secretObj.StringData == nil {
+				secretObj.StringData = map[string]string{}
+			}
+			updateSecretTypeFields(&secretObj)
+
+			return nil
+		})
+		if err != nil {
+			return ServiceInstanceRecord{}, fmt.Errorf("failed to patch service instance credentials: %w", apierrors.FromK8sError(err, ServiceInstanceResourceType))
+		}
+	}
+
+	return cfServiceInstanceToServiceInstanceRecord(*cfServiceInstance), nil
+}
+
 func (r *ServiceInstanceRepo) ListServiceInstances(ctx context.Context, authInfo authorization.Info, message ListServiceInstanceMessage) ([]ServiceInstanceRecord, error) {
 	nsList, err := r.namespacePermissions.GetAuthorizedSpaceNamespaces(ctx, authInfo)
 	if err != nil {
@@ -115,7 +186,7 @@ func (r *ServiceInstanceRepo) ListServiceInstances(ctx context.Context, authInfo
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return []ServiceInstanceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -142,7 +213,7 @@ func (r *ServiceInstanceRepo) ListServiceInstances(ctx context.Context, authInfo
 }
 
 func (r *ServiceInstanceRepo) GetServiceInstance(ctx context.Context, authInfo authorization.Info, guid string) (ServiceInstanceRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return ServiceInstanceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -161,7 +232,7 @@ func (r *ServiceInstanceRepo) GetServiceInstance(ctx context.Context, authInfo a
 }
 
 func (r *ServiceInstanceRepo) DeleteServiceInstance(ctx context.Context, authInfo authorization.Info, message DeleteServiceInstanceMessage) error {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -190,10 +261,11 @@ func (m CreateServiceInstanceMessage) toCFServiceInstance() korifiv1alpha1.CFSer
 			Annotations: m.Annotations,
 		},
 		Spec: korifiv1alpha1.CFServiceInstanceSpec{
-			DisplayName: m.Name,
-			SecretName:  guid,
-			Type:        korifiv1alpha1.InstanceType(m.Type),
-			Tags:        m.Tags,
+			DisplayName:    m.Name,
+			SecretName:     guid,
+			Type:           korifiv1alpha1.InstanceType(m.Type),
+			Tags:           m.Tags,
+			SyslogDrainURL: m.SyslogDrainURL,
 		},
 	}
 }
@@ -202,14 +274,15 @@ func cfServiceInstanceToServiceInstanceRecord(cfServiceInstance korifiv1alpha1.C
 	updatedAtTime, _ := getTimeLastUpdatedTimestamp(&cfServiceInstance.ObjectMeta)
 
 	return ServiceInstanceRecord{
-		Name:       cfServiceInstance.Spec.DisplayName,
-		GUID:       cfServiceInstance.Name,
-		SpaceGUID:  cfServiceInstance.Namespace,
-		SecretName: cfServiceInstance.Spec.SecretName,
-		Tags:       cfServiceInstance.Spec.Tags,
-		Type:       string(cfServiceInstance.Spec.Type),
-		CreatedAt:  cfServiceInstance.CreationTimestamp.UTC().Format(TimestampFormat),
-		UpdatedAt:  updatedAtTime,
+		Name:           cfServiceInstance.Spec.DisplayName,
+		GUID:           cfServiceInstance.Name,
+		SpaceGUID:      cfServiceInstance.Namespace,
+		SecretName:     cfServiceInstance.Spec.SecretName,
+		Tags:           cfServiceInstance.Spec.Tags,
+		Type:           string(cfServiceInstance.Spec.Type),
+		SyslogDrainURL: cfServiceInstance.Spec.SyslogDrainURL,
+		CreatedAt:      cfServiceInstance.CreationTimestamp.UTC().Format(TimestampFormat),
+		UpdatedAt:      updatedAtTime,
 	}
 }
 
@@ -9,6 +9,8 @@ import (
 	"code.cloudfoundry.org/korifi/api/apierrors"
 	"code.cloudfoundry.org/korifi/api/repositories"
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -17,6 +19,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("ServiceInstanceRepository", func() {
@@ -95,6 +98,20 @@ var _ = Describe("ServiceInstanceRepository", func() {
 				Expect(recordUpdatedTime).To(BeTemporally("~", time.Now(), 2*time.Second))
 			})
 
+			When("a syslog drain URL is provided", func() {
+				BeforeEach(func() {
+					serviceInstanceCreateMessage.SyslogDrainURL = "syslog://logs.example.com"
+				})
+
+				It("stores the drain URL on the ServiceInstance CR", func() {
+					Expect(createdServiceInstanceRecord.SyslogDrainURL).To(Equal("syslog://logs.example.com"))
+
+					cfServiceInstance := new(korifiv1alpha1.CFServiceInstance)
+					Expect(k8sClient.Get(testCtx, types.NamespacedName{Namespace: space.Name, Name: createdServiceInstanceRecord.GUID}, cfServiceInstance)).To(Succeed())
+					Expect(cfServiceInstance.Spec.SyslogDrainURL).To(Equal("syslog://logs.example.com"))
+				})
+			})
+
 			When("ServiceInstance credentials are NOT provided", func() {
 				BeforeEach(func() {
 					serviceInstanceCreateMessage.Credentials = nil
@@ -158,6 +175,120 @@ var _ = Describe("ServiceInstanceRepository", func() {
 		})
 	})
 
+	Describe("PatchServiceInstance", func() {
+		var (
+			serviceInstance *korifiv1alpha1.CFServiceInstance
+			secret          *corev1.Secret
+			patchMessage    repositories.PatchServiceInstanceMessage
+			record          repositories.ServiceInstanceRecord
+			patchErr        error
+		)
+
+		BeforeEach(func() {
+			serviceInstanceGUID := prefixedGUID("service-instance")
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: space.Name, Name: serviceInstanceGUID},
+				StringData: map[string]string{"type": "user-provided", "old-cred": "old-val"},
+			}
+			Expect(k8sClient.Create(testCtx, secret)).To(Succeed())
+
+			serviceInstance = createServiceInstanceCR(testCtx, k8sClient, serviceInstanceGUID, space.Name, "the-service-instance", secret.Name)
+			Expect(k8s.PatchResource(testCtx, k8sClient, serviceInstance, func() {
+				serviceInstance.Spec.SyslogDrainURL = "syslog://old.example.com"
+				serviceInstance.Spec.Tags = []string{"old"}
+				serviceInstance.Labels = map[string]string{"keep": "me", "drop": "me"}
+			})).To(Succeed())
+
+			patchMessage = repositories.PatchServiceInstanceMessage{
+				GUID:           serviceInstance.Name,
+				SpaceGUID:      space.Name,
+				Name:           tools.PtrTo("new-name"),
+				Tags:           &[]string{"new"},
+				SyslogDrainURL: tools.PtrTo("https://new.example.com/drain"),
+				MetadataPatch: repositories.MetadataPatch{
+					Labels: map[string]*string{"drop": nil, "add": tools.PtrTo("me")},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			record, patchErr = serviceInstanceRepo.PatchServiceInstance(testCtx, authInfo, patchMessage)
+		})
+
+		When("the user has permissions to patch the service instance", func() {
+			BeforeEach(func() {
+				createRoleBinding(testCtx, userName, spaceDeveloperRole.Name, space.Name)
+			})
+
+			It("patches the service instance", func() {
+				Expect(patchErr).NotTo(HaveOccurred())
+				Expect(record.Name).To(Equal("new-name"))
+				Expect(record.Tags).To(Equal([]string{"new"}))
+				Expect(record.SyslogDrainURL).To(Equal("https://new.example.com/drain"))
+
+				updated := new(korifiv1alpha1.CFServiceInstance)
+				Expect(k8sClient.Get(testCtx, client.ObjectKeyFromObject(serviceInstance), updated)).To(Succeed())
+				Expect(updated.Spec.DisplayName).To(Equal("new-name"))
+				Expect(updated.Spec.Tags).To(Equal([]string{"new"}))
+				Expect(updated.Spec.SyslogDrainURL).To(Equal("https://new.example.com/drain"))
+				Expect(updated.Labels).To(Equal(map[string]string{"keep": "me", "add": "me"}))
+			})
+
+			When("the syslog drain URL is cleared", func() {
+				BeforeEach(func() {
+					patchMessage = repositories.PatchServiceInstanceMessage{
+						GUID:           serviceInstance.Name,
+						SpaceGUID:      space.Name,
+						SyslogDrainURL: tools.PtrTo(""),
+					}
+				})
+
+				It("removes the drain URL and leaves the other fields alone", func() {
+					Expect(patchErr).NotTo(HaveOccurred())
+
+					updated := new(korifiv1alpha1.CFServiceInstance)
+					Expect(k8sClient.Get(testCtx, client.ObjectKeyFromObject(serviceInstance), updated)).To(Succeed())
+					Expect(updated.Spec.SyslogDrainURL).To(BeEmpty())
+					Expect(updated.Spec.DisplayName).To(Equal("the-service-instance"))
+					Expect(updated.Spec.Tags).To(Equal([]string{"old"}))
+				})
+			})
+
+			When("the credentials are provided", func() {
+				BeforeEach(func() {
+					patchMessage.Credentials = &map[string]string{"new-cred": "new-val"}
+				})
+
+				It("replaces the credentials in the secret", func() {
+					Expect(patchErr).NotTo(HaveOccurred())
+
+					updatedSecret := new(corev1.Secret)
+					Expect(k8sClient.Get(testCtx, client.ObjectKeyFromObject(secret), updatedSecret)).To(Succeed())
+					Expect(updatedSecret.Data).To(MatchAllKeys(Keys{
+						"type":     BeEquivalentTo("user-provided"),
+						"new-cred": BeEquivalentTo("new-val"),
+					}))
+				})
+			})
+
+			When("the service instance does not exist", func() {
+				BeforeEach(func() {
+					patchMessage.GUID = "does-not-exist"
+				})
+
+				It("returns a not found error", func() {
+					Expect(errors.As(patchErr, &apierrors.NotFoundError{})).To(BeTrue())
+				})
+			})
+		})
+
+		When("the user is not authorized to patch the service instance", func() {
+			It("returns a forbidden error", func() {
+				Expect(errors.As(patchErr, &apierrors.ForbiddenError{})).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("ListServiceInstances", func() {
 		var (
 			space2, space3                                             *korifiv1alpha1.CFSpace
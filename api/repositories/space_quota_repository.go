@@ -0,0 +1,360 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SpaceQuotaResourceType = "Space Quota"
+)
+
+type SpaceQuotaRecord struct {
+	GUID             string
+	Name             string
+	OrganizationGUID string
+	Apps             QuotaApps
+	Services         QuotaServices
+	Routes           QuotaRoutes
+	SpaceGUIDs       []string
+	CreatedAt        string
+	UpdatedAt        string
+}
+
+type CreateSpaceQuotaMessage struct {
+	Name             string
+	OrganizationGUID string
+	Apps             QuotaApps
+	Services         QuotaServices
+	Routes           QuotaRoutes
+	SpaceGUIDs       []string
+}
+
+type ListSpaceQuotasMessage struct {
+	GUIDs             []string
+	Names             []string
+	OrganizationGUIDs []string
+	SpaceGUIDs        []string
+}
+
+type ApplySpaceQuotaMessage struct {
+	GUID       string
+	SpaceGUIDs []string
+}
+
+type RemoveSpaceQuotaMessage struct {
+	GUID      string
+	SpaceGUID string
+}
+
+type SpaceQuotaRepo struct {
+	namespaceRetriever NamespaceRetriever
+	userClientFactory  authorization.UserK8sClientFactory
+	nsPerms            *authorization.NamespacePermissions
+}
+
+func NewSpaceQuotaRepo(
+	namespaceRetriever NamespaceRetriever,
+	userClientFactory authorization.UserK8sClientFactory,
+	nsPerms *authorization.NamespacePermissions,
+) *SpaceQuotaRepo {
+	return &SpaceQuotaRepo{
+		namespaceRetriever: namespaceRetriever,
+		userClientFactory:  userClientFactory,
+		nsPerms:            nsPerms,
+	}
+}
+
+func (r *SpaceQuotaRepo) CreateSpaceQuota(ctx context.Context, authInfo authorization.Info, message CreateSpaceQuotaMessage) (SpaceQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return SpaceQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	existing, err := r.ListSpaceQuotas(ctx, authInfo, ListSpaceQuotasMessage{
+		Names:             []string{message.Name},
+		OrganizationGUIDs: []string{message.OrganizationGUID},
+	})
+	if err != nil {
+		return SpaceQuotaRecord{}, err
+	}
+	if len(existing) > 0 {
+		return SpaceQuotaRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("space quota %q already exists in org %q", message.Name, message.OrganizationGUID),
+			fmt.Sprintf("Space Quota '%s' already exists.", message.Name),
+		)
+	}
+
+	cfSpaceQuota := &korifiv1alpha1.CFSpaceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewString(),
+			Namespace: message.OrganizationGUID,
+		},
+		Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+			DisplayName: message.Name,
+			Apps:        message.Apps.toCFAppsLimits(),
+			Services:    message.Services.toCFServicesLimits(),
+			Routes:      message.Routes.toCFRoutesLimits(),
+		},
+	}
+
+	if err = userClient.Create(ctx, cfSpaceQuota); err != nil {
+		return SpaceQuotaRecord{}, apierrors.FromK8sError(err, SpaceQuotaResourceType)
+	}
+
+	if len(message.SpaceGUIDs) > 0 {
+		return r.ApplySpaceQuota(ctx, authInfo, ApplySpaceQuotaMessage{
+			GUID:       cfSpaceQuota.Name,
+			SpaceGUIDs: message.SpaceGUIDs,
+		})
+	}
+
+	return cfSpaceQuotaToSpaceQuotaRecord(*cfSpaceQuota, nil), nil
+}
+
+func (r *SpaceQuotaRepo) GetSpaceQuota(ctx context.Context, authInfo authorization.Info, guid string) (SpaceQuotaRecord, error) {
+	cfSpaceQuota, err := r.getSpaceQuota(ctx, authInfo, guid)
+	if err != nil {
+		return SpaceQuotaRecord{}, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return SpaceQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	spaces, err := r.listSpaces(ctx, userClient, cfSpaceQuota.Namespace)
+	if err != nil {
+		return SpaceQuotaRecord{}, err
+	}
+
+	return cfSpaceQuotaToSpaceQuotaRecord(*cfSpaceQuota, spaces), nil
+}
+
+func (r *SpaceQuotaRepo) ListSpaceQuotas(ctx context.Context, authInfo authorization.Info, message ListSpaceQuotasMessage) ([]SpaceQuotaRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	authorizedOrgNamespaces, err := r.nsPerms.GetAuthorizedOrgNamespaces(ctx, authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []SpaceQuotaRecord{}
+	for org := range authorizedOrgNamespaces {
+		if !matchesFilter(org, message.OrganizationGUIDs) {
+			continue
+		}
+
+		cfSpaceQuotaList := new(korifiv1alpha1.CFSpaceQuotaList)
+		err = userClient.List(ctx, cfSpaceQuotaList, client.InNamespace(org))
+		if k8serrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, apierrors.FromK8sError(err, SpaceQuotaResourceType)
+		}
+
+		if len(cfSpaceQuotaList.Items) == 0 {
+			continue
+		}
+
+		spaces, err := r.listSpaces(ctx, userClient, org)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cfSpaceQuota := range cfSpaceQuotaList.Items {
+			if !matchesFilter(cfSpaceQuota.Name, message.GUIDs) {
+				continue
+			}
+
+			if !matchesFilter(cfSpaceQuota.Spec.DisplayName, message.Names) {
+				continue
+			}
+
+			record := cfSpaceQuotaToSpaceQuotaRecord(cfSpaceQuota, spaces)
+			if len(message.SpaceGUIDs) > 0 && !anyMatchesFilter(record.SpaceGUIDs, message.SpaceGUIDs) {
+				continue
+			}
+
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt < records[j].CreatedAt
+	})
+
+	return records, nil
+}
+
+func (r *SpaceQuotaRepo) ApplySpaceQuota(ctx context.Context, authInfo authorization.Info, message ApplySpaceQuotaMessage) (SpaceQuotaRecord, error) {
+	cfSpaceQuota, err := r.getSpaceQuota(ctx, authInfo, message.GUID)
+	if err != nil {
+		return SpaceQuotaRecord{}, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return SpaceQuotaRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfSpaces := make([]*korifiv1alpha1.CFSpace, 0, len(message.SpaceGUIDs))
+	var missing []string
+	for _, spaceGUID := range message.SpaceGUIDs {
+		cfSpace := &korifiv1alpha1.CFSpace{}
+		err = userClient.Get(ctx, client.ObjectKey{Namespace: cfSpaceQuota.Namespace, Name: spaceGUID}, cfSpace)
+		if err != nil {
+			if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) {
+				missing = append(missing, spaceGUID)
+				continue
+			}
+			return SpaceQuotaRecord{}, apierrors.FromK8sError(err, SpaceResourceType)
+		}
+		cfSpaces = append(cfSpaces, cfSpace)
+	}
+
+	if len(missing) > 0 {
+		return SpaceQuotaRecord{}, apierrors.NewUnprocessableEntityError(
+			fmt.Errorf("spaces %v not found in org %q", missing, cfSpaceQuota.Namespace),
+			fmt.Sprintf("Spaces with guids %s do not exist within the organization specified, or you do not have access to them.", quotedList(missing)),
+		)
+	}
+
+	for _, cfSpace := range cfSpaces {
+		err = k8s.PatchResource(ctx, userClient, cfSpace, func() {
+			cfSpace.Spec.QuotaRef.Name = message.GUID
+		})
+		if err != nil {
+			return SpaceQuotaRecord{}, apierrors.FromK8sError(err, SpaceResourceType)
+		}
+	}
+
+	return r.GetSpaceQuota(ctx, authInfo, message.GUID)
+}
+
+func (r *SpaceQuotaRepo) RemoveSpaceQuota(ctx context.Context, authInfo authorization.Info, message RemoveSpaceQuotaMessage) error {
+	cfSpaceQuota, err := r.getSpaceQuota(ctx, authInfo, message.GUID)
+	if err != nil {
+		return err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfSpace := &korifiv1alpha1.CFSpace{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: cfSpaceQuota.Namespace, Name: message.SpaceGUID}, cfSpace)
+	if err != nil {
+		return fmt.Errorf("failed to get space: %w", apierrors.FromK8sError(err, SpaceResourceType))
+	}
+
+	if cfSpace.Spec.QuotaRef.Name != message.GUID {
+		return nil
+	}
+
+	err = k8s.PatchResource(ctx, userClient, cfSpace, func() {
+		cfSpace.Spec.QuotaRef.Name = ""
+	})
+
+	return apierrors.FromK8sError(err, SpaceResourceType)
+}
+
+func (r *SpaceQuotaRepo) DeleteSpaceQuota(ctx context.Context, authInfo authorization.Info, guid string) error {
+	record, err := r.GetSpaceQuota(ctx, authInfo, guid)
+	if err != nil {
+		return err
+	}
+
+	if len(record.SpaceGUIDs) > 0 {
+		return apierrors.NewUnprocessableEntityError(
+			errors.New("space quota is still applied to spaces"),
+			"This quota is applied to one or more spaces. Remove this quota from all spaces before deleting.",
+		)
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	err = userClient.Delete(ctx, &korifiv1alpha1.CFSpaceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      guid,
+			Namespace: record.OrganizationGUID,
+		},
+	})
+
+	return apierrors.FromK8sError(err, SpaceQuotaResourceType)
+}
+
+func (r *SpaceQuotaRepo) getSpaceQuota(ctx context.Context, authInfo authorization.Info, guid string) (*korifiv1alpha1.CFSpaceQuota, error) {
+	ns, err := r.namespaceRetriever.NamespaceFor(ctx, guid, SpaceQuotaResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfSpaceQuota := &korifiv1alpha1.CFSpaceQuota{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: guid}, cfSpaceQuota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get space quota: %w", apierrors.FromK8sError(err, SpaceQuotaResourceType))
+	}
+
+	return cfSpaceQuota, nil
+}
+
+func (r *SpaceQuotaRepo) listSpaces(ctx context.Context, userClient client.Client, orgGUID string) ([]korifiv1alpha1.CFSpace, error) {
+	cfSpaceList := &korifiv1alpha1.CFSpaceList{}
+	err := userClient.List(ctx, cfSpaceList, client.InNamespace(orgGUID))
+	if err != nil {
+		if k8serrors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, apierrors.FromK8sError(err, SpaceResourceType)
+	}
+
+	return cfSpaceList.Items, nil
+}
+
+func cfSpaceQuotaToSpaceQuotaRecord(cfSpaceQuota korifiv1alpha1.CFSpaceQuota, spaces []korifiv1alpha1.CFSpace) SpaceQuotaRecord {
+	spaceGUIDs := []string{}
+	for _, space := range spaces {
+		if space.Spec.QuotaRef.Name == cfSpaceQuota.Name {
+			spaceGUIDs = append(spaceGUIDs, space.Name)
+		}
+	}
+
+	updatedAtTime, _ := getTimeLastUpdatedTimestamp(&cfSpaceQuota.ObjectMeta)
+	return SpaceQuotaRecord{
+		GUID:             cfSpaceQuota.Name,
+		Name:             cfSpaceQuota.Spec.DisplayName,
+		OrganizationGUID: cfSpaceQuota.Namespace,
+		Apps:             quotaAppsFromCF(cfSpaceQuota.Spec.Apps),
+		Services:         quotaServicesFromCF(cfSpaceQuota.Spec.Services),
+		Routes:           quotaRoutesFromCF(cfSpaceQuota.Spec.Routes),
+		SpaceGUIDs:       spaceGUIDs,
+		CreatedAt:        formatTimestamp(cfSpaceQuota.CreationTimestamp),
+		UpdatedAt:        updatedAtTime,
+	}
+}
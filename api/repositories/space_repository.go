@@ -83,7 +83,7 @@ func (r *SpaceRepo) CreateSpace(ctx context.Context, info authorization.Info, me
 		return SpaceRecord{}, fmt.Errorf("failed to get parent organization: %w", err)
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return SpaceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -155,7 +155,7 @@ func (r *SpaceRepo) createSpaceCR(ctx context.Context,
 }
 
 func (r *SpaceRepo) ListSpaces(ctx context.Context, info authorization.Info, message ListSpacesMessage) ([]SpaceRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return []SpaceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -220,7 +220,7 @@ func (r *SpaceRepo) GetSpace(ctx context.Context, info authorization.Info, space
 		return SpaceRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return SpaceRecord{}, fmt.Errorf("get-space failed to build user client: %w", err)
 	}
@@ -247,7 +247,7 @@ func cfSpaceToSpaceRecord(cfSpace korifiv1alpha1.CFSpace) SpaceRecord {
 }
 
 func (r *SpaceRepo) DeleteSpace(ctx context.Context, info authorization.Info, message DeleteSpaceMessage) error {
-	userClient, err := r.userClientFactory.BuildClient(info)
+	userClient, err := r.userClientFactory.BuildClient(ctx, info)
 	if err != nil {
 		return fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -263,7 +263,7 @@ func (r *SpaceRepo) DeleteSpace(ctx context.Context, info authorization.Info, me
 }
 
 func (r *SpaceRepo) PatchSpaceMetadata(ctx context.Context, authInfo authorization.Info, message PatchSpaceMetadataMessage) (SpaceRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return SpaceRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -93,7 +93,7 @@ func NewTaskRepo(
 }
 
 func (r *TaskRepo) CreateTask(ctx context.Context, authInfo authorization.Info, createMessage CreateTaskMessage) (TaskRecord, error) {
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return TaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -118,7 +118,7 @@ func (r *TaskRepo) GetTask(ctx context.Context, authInfo authorization.Info, tas
 		return TaskRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return TaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -153,7 +153,7 @@ func (r *TaskRepo) ListTasks(ctx context.Context, authInfo authorization.Info, m
 		return nil, fmt.Errorf("failed to list namespaces for spaces with user role bindings: %w", err)
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -185,7 +185,7 @@ func (r *TaskRepo) CancelTask(ctx context.Context, authInfo authorization.Info,
 		return TaskRecord{}, err
 	}
 
-	userClient, err := r.userClientFactory.BuildClient(authInfo)
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
 	if err != nil {
 		return TaskRecord{}, fmt.Errorf("failed to build user client: %w", err)
 	}
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/authorization"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfusers,verbs=get;create
+
+const (
+	UserResourceType     = "User"
+	KubernetesUserOrigin = "kubernetes"
+)
+
+// userGUIDNamespace is the UUID namespace the GUIDs of CFUsers are derived from, so that every username of an origin
+// maps to exactly one CFUser no matter how it was first observed
+var userGUIDNamespace = uuid.MustParse("4f0c5b8e-2a1d-4e36-9c8b-7d3f1a6e5b20")
+
+type UserRecord struct {
+	GUID        string
+	Username    string
+	Origin      string
+	Labels      map[string]string
+	Annotations map[string]string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CreateUserMessage struct {
+	Username    string
+	Origin      string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+type ListUsersMessage struct {
+	GUIDs     []string
+	Usernames []string
+	Origins   []string
+}
+
+type UserRepo struct {
+	rootNamespace     string
+	privilegedClient  client.Client
+	userClientFactory authorization.UserK8sClientFactory
+}
+
+func NewUserRepo(
+	rootNamespace string,
+	privilegedClient client.Client,
+	userClientFactory authorization.UserK8sClientFactory,
+) *UserRepo {
+	return &UserRepo{
+		rootNamespace:     rootNamespace,
+		privilegedClient:  privilegedClient,
+		userClientFactory: userClientFactory,
+	}
+}
+
+// UserGUID returns the GUID of the CFUser recording the given username of the given origin. Users with the same name
+// from different origins are different users. An empty origin is the kubernetes origin.
+func UserGUID(origin, username string) string {
+	if origin == "" {
+		origin = KubernetesUserOrigin
+	}
+
+	originNamespace := uuid.NewSHA1(userGUIDNamespace, []byte(origin))
+	return uuid.NewSHA1(originNamespace, []byte(username)).String()
+}
+
+func (r *UserRepo) CreateUser(ctx context.Context, authInfo authorization.Info, message CreateUserMessage) (UserRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return UserRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfUser := r.newCFUser(message.Username, message.Origin)
+	cfUser.Labels = message.Labels
+	cfUser.Annotations = message.Annotations
+
+	err = userClient.Create(ctx, cfUser)
+	if err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return UserRecord{}, apierrors.NewUnprocessableEntityError(
+				fmt.Errorf("cfuser %s:%s already exists", cfUser.Namespace, cfUser.Name),
+				fmt.Sprintf("User with username '%s' and origin '%s' already exists", message.Username, cfUser.Spec.Origin),
+			)
+		}
+		return UserRecord{}, fmt.Errorf("failed to create user %q: %w", message.Username, apierrors.FromK8sError(err, UserResourceType))
+	}
+
+	return cfUserToUserRecord(*cfUser), nil
+}
+
+// RecordUser makes sure there is a CFUser for a user that has been observed by the API, e.g. because it has been
+// granted a role or has authenticated. Such users are not created on behalf of anyone, so the privileged client
+// is used and users that are already recorded are left alone
+func (r *UserRepo) RecordUser(ctx context.Context, username string) error {
+	err := r.privilegedClient.Create(ctx, r.newCFUser(username, KubernetesUserOrigin))
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to record user %q: %w", username, apierrors.FromK8sError(err, UserResourceType))
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetUser(ctx context.Context, authInfo authorization.Info, guid string) (UserRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return UserRecord{}, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfUser := &korifiv1alpha1.CFUser{}
+	err = userClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, cfUser)
+	if err != nil {
+		return UserRecord{}, fmt.Errorf("failed to get user %q: %w", guid, apierrors.FromK8sError(err, UserResourceType))
+	}
+
+	return cfUserToUserRecord(*cfUser), nil
+}
+
+func (r *UserRepo) ListUsers(ctx context.Context, authInfo authorization.Info, message ListUsersMessage) ([]UserRecord, error) {
+	userClient, err := r.userClientFactory.BuildClient(ctx, authInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user client: %w", err)
+	}
+
+	cfUserList := &korifiv1alpha1.CFUserList{}
+	err = userClient.List(ctx, cfUserList, client.InNamespace(r.rootNamespace))
+	if k8serrors.IsForbidden(err) {
+		return []UserRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", apierrors.FromK8sError(err, UserResourceType))
+	}
+
+	records := []UserRecord{}
+	for _, cfUser := range cfUserList.Items {
+		if message.matches(cfUser) {
+			records = append(records, cfUserToUserRecord(cfUser))
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+
+	return records, nil
+}
+
+// usernameFor returns the username of the CFUser with the given GUID, if there is one
+func (r *UserRepo) usernameFor(ctx context.Context, guid string) (string, bool, error) {
+	cfUser := &korifiv1alpha1.CFUser{}
+	err := r.privilegedClient.Get(ctx, client.ObjectKey{Namespace: r.rootNamespace, Name: guid}, cfUser)
+	if k8serrors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get user %q: %w", guid, apierrors.FromK8sError(err, UserResourceType))
+	}
+
+	return cfUser.Spec.Username, true, nil
+}
+
+func (r *UserRepo) newCFUser(username, origin string) *korifiv1alpha1.CFUser {
+	if origin == "" {
+		origin = KubernetesUserOrigin
+	}
+
+	return &korifiv1alpha1.CFUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UserGUID(origin, username),
+			Namespace: r.rootNamespace,
+		},
+		Spec: korifiv1alpha1.CFUserSpec{
+			Username: username,
+			Origin:   origin,
+		},
+	}
+}
+
+func (m ListUsersMessage) matches(cfUser korifiv1alpha1.CFUser) bool {
+	return matchesFilter(cfUser.Name, m.GUIDs) &&
+		matchesFilter(cfUser.Spec.Username, m.Usernames) &&
+		matchesFilter(cfUser.Spec.Origin, m.Origins)
+}
+
+func cfUserToUserRecord(cfUser korifiv1alpha1.CFUser) UserRecord {
+	return UserRecord{
+		GUID:        cfUser.Name,
+		Username:    cfUser.Spec.Username,
+		Origin:      cfUser.Spec.Origin,
+		Labels:      cfUser.Labels,
+		Annotations: cfUser.Annotations,
+		CreatedAt:   cfUser.CreationTimestamp.Time,
+		UpdatedAt:   cfUser.CreationTimestamp.Time,
+	}
+}
@@ -0,0 +1,263 @@
+package repositories_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/apierrors"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tests/matchers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("UserRepository", func() {
+	var userRepo *repositories.UserRepo
+
+	createCFUser := func(username, origin string) *korifiv1alpha1.CFUser {
+		cfUser := &korifiv1alpha1.CFUser{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      repositories.UserGUID(origin, username),
+				Namespace: rootNamespace,
+			},
+			Spec: korifiv1alpha1.CFUserSpec{
+				Username: username,
+				Origin:   origin,
+			},
+		}
+		ExpectWithOffset(1, k8sClient.Create(ctx, cfUser)).To(Succeed())
+
+		return cfUser
+	}
+
+	BeforeEach(func() {
+		userRepo = repositories.NewUserRepo(rootNamespace, k8sClient, userClientFactory)
+	})
+
+	Describe("UserGUID", func() {
+		It("is the same for the same username and origin", func() {
+			Expect(repositories.UserGUID("ldap", "bob")).To(Equal(repositories.UserGUID("ldap", "bob")))
+		})
+
+		It("differs between usernames", func() {
+			Expect(repositories.UserGUID("ldap", "bob")).NotTo(Equal(repositories.UserGUID("ldap", "alice")))
+		})
+
+		It("differs between origins", func() {
+			Expect(repositories.UserGUID("ldap", "bob")).NotTo(Equal(repositories.UserGUID("uaa", "bob")))
+		})
+
+		It("defaults to the kubernetes origin", func() {
+			Expect(repositories.UserGUID("", "bob")).To(Equal(repositories.UserGUID(repositories.KubernetesUserOrigin, "bob")))
+		})
+	})
+
+	Describe("CreateUser", func() {
+		var (
+			message    repositories.CreateUserMessage
+			userRecord repositories.UserRecord
+			createErr  error
+		)
+
+		BeforeEach(func() {
+			message = repositories.CreateUserMessage{
+				Username: "bob@example.com",
+				Origin:   "ldap",
+				Labels:   map[string]string{"team": "a"},
+			}
+		})
+
+		JustBeforeEach(func() {
+			userRecord, createErr = userRepo.CreateUser(ctx, authInfo, message)
+		})
+
+		It("returns a forbidden error for users that are not admins", func() {
+			Expect(createErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.ForbiddenError{}))
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				createRoleBinding(ctx, userName, adminRole.Name, rootNamespace)
+			})
+
+			It("creates a CFUser in the root namespace", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+				Expect(userRecord.GUID).To(Equal(repositories.UserGUID("ldap", "bob@example.com")))
+				Expect(userRecord.Username).To(Equal("bob@example.com"))
+				Expect(userRecord.Origin).To(Equal("ldap"))
+				Expect(userRecord.Labels).To(HaveKeyWithValue("team", "a"))
+				Expect(userRecord.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+
+				cfUser := korifiv1alpha1.CFUser{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: userRecord.GUID, Namespace: rootNamespace}, &cfUser)).To(Succeed())
+				Expect(cfUser.Spec.Username).To(Equal("bob@example.com"))
+				Expect(cfUser.Spec.Origin).To(Equal("ldap"))
+			})
+
+			When("no origin is given", func() {
+				BeforeEach(func() {
+					message.Origin = ""
+				})
+
+				It("defaults to the kubernetes origin", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(userRecord.Origin).To(Equal(repositories.KubernetesUserOrigin))
+				})
+			})
+
+			When("the user already exists", func() {
+				BeforeEach(func() {
+					createCFUser("bob@example.com", "ldap")
+				})
+
+				It("returns an unprocessable entity error", func() {
+					Expect(createErr).To(BeAssignableToTypeOf(apierrors.UnprocessableEntityError{}))
+					Expect(createErr.(apierrors.UnprocessableEntityError).Detail()).To(Equal("User with username 'bob@example.com' and origin 'ldap' already exists"))
+				})
+			})
+
+			When("a user with the same username exists in another origin", func() {
+				BeforeEach(func() {
+					createCFUser("bob@example.com", repositories.KubernetesUserOrigin)
+				})
+
+				It("creates another user", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(userRecord.GUID).NotTo(Equal(repositories.UserGUID(repositories.KubernetesUserOrigin, "bob@example.com")))
+				})
+			})
+		})
+	})
+
+	Describe("RecordUser", func() {
+		var recordErr error
+
+		JustBeforeEach(func() {
+			recordErr = userRepo.RecordUser(ctx, "bob@example.com")
+		})
+
+		It("creates a CFUser with the kubernetes origin", func() {
+			Expect(recordErr).NotTo(HaveOccurred())
+
+			cfUser := korifiv1alpha1.CFUser{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: repositories.UserGUID(repositories.KubernetesUserOrigin, "bob@example.com"), Namespace: rootNamespace}, &cfUser)).To(Succeed())
+			Expect(cfUser.Spec.Username).To(Equal("bob@example.com"))
+			Expect(cfUser.Spec.Origin).To(Equal(repositories.KubernetesUserOrigin))
+		})
+
+		When("the user has already been recorded", func() {
+			BeforeEach(func() {
+				cfUser := createCFUser("bob@example.com", repositories.KubernetesUserOrigin)
+				cfUser.Labels = map[string]string{"team": "a"}
+				Expect(k8sClient.Update(ctx, cfUser)).To(Succeed())
+			})
+
+			It("leaves the existing record alone", func() {
+				Expect(recordErr).NotTo(HaveOccurred())
+
+				cfUser := korifiv1alpha1.CFUser{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: repositories.UserGUID(repositories.KubernetesUserOrigin, "bob@example.com"), Namespace: rootNamespace}, &cfUser)).To(Succeed())
+				Expect(cfUser.Labels).To(HaveKeyWithValue("team", "a"))
+			})
+		})
+	})
+
+	Describe("GetUser", func() {
+		var (
+			guid       string
+			userRecord repositories.UserRecord
+			getErr     error
+		)
+
+		BeforeEach(func() {
+			guid = createCFUser("bob@example.com", "ldap").Name
+		})
+
+		JustBeforeEach(func() {
+			userRecord, getErr = userRepo.GetUser(ctx, authInfo, guid)
+		})
+
+		It("returns the user", func() {
+			Expect(getErr).NotTo(HaveOccurred())
+			Expect(userRecord.GUID).To(Equal(guid))
+			Expect(userRecord.Username).To(Equal("bob@example.com"))
+			Expect(userRecord.Origin).To(Equal("ldap"))
+		})
+
+		When("the user does not exist", func() {
+			BeforeEach(func() {
+				guid = "i-do-not-exist"
+			})
+
+			It("returns a not found error", func() {
+				Expect(getErr).To(matchers.WrapErrorAssignableToTypeOf(apierrors.NotFoundError{}))
+			})
+		})
+	})
+
+	Describe("ListUsers", func() {
+		var (
+			message     repositories.ListUsersMessage
+			userRecords []repositories.UserRecord
+			listErr     error
+		)
+
+		BeforeEach(func() {
+			createCFUser("bob@example.com", "ldap")
+			createCFUser("alice@example.com", repositories.KubernetesUserOrigin)
+			message = repositories.ListUsersMessage{}
+		})
+
+		JustBeforeEach(func() {
+			userRecords, listErr = userRepo.ListUsers(ctx, authInfo, message)
+		})
+
+		It("lists all users", func() {
+			Expect(listErr).NotTo(HaveOccurred())
+			Expect(userRecords).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{"Username": Equal("bob@example.com")}),
+				MatchFields(IgnoreExtras, Fields{"Username": Equal("alice@example.com")}),
+			))
+		})
+
+		When("filtering by username", func() {
+			BeforeEach(func() {
+				message.Usernames = []string{"alice@example.com"}
+			})
+
+			It("returns the matching users", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(userRecords).To(HaveLen(1))
+				Expect(userRecords[0].Username).To(Equal("alice@example.com"))
+			})
+		})
+
+		When("filtering by origin", func() {
+			BeforeEach(func() {
+				message.Origins = []string{"ldap"}
+			})
+
+			It("returns the matching users", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(userRecords).To(HaveLen(1))
+				Expect(userRecords[0].Username).To(Equal("bob@example.com"))
+			})
+		})
+
+		When("filtering by guid", func() {
+			BeforeEach(func() {
+				message.GUIDs = []string{repositories.UserGUID("ldap", "bob@example.com")}
+			})
+
+			It("returns the matching users", func() {
+				Expect(listErr).NotTo(HaveOccurred())
+				Expect(userRecords).To(HaveLen(1))
+				Expect(userRecords[0].Username).To(Equal("bob@example.com"))
+			})
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package syslogdrain
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// AddressFilter keeps drains from connecting to addresses inside the cluster or on the host, as drain URLs are set by
+// space developers but connected to by the API. Loopback, link-local, private, unspecified and multicast addresses
+// are rejected, along with the denied ranges, e.g. the pod and service CIDRs of the cluster. Allowed ranges take
+// precedence, e.g. for a log collector inside the cluster.
+type AddressFilter struct {
+	allowed []*net.IPNet
+	denied  []*net.IPNet
+}
+
+func NewAddressFilter(allowedCIDRs, deniedCIDRs []string) (*AddressFilter, error) {
+	allowed, err := parseCIDRs(allowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := parseCIDRs(deniedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressFilter{allowed: allowed, denied: denied}, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	var ipNets []*net.IPNet
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets, nil
+}
+
+// Control is a net.Dialer Control function. It is called with the resolved address of every connection, so that
+// host names resolving to rejected addresses are rejected too.
+func (f *AddressFilter) Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("syslog drain address %q is not an IP address", host)
+	}
+
+	if !f.Allows(ip) {
+		return fmt.Errorf("syslog drains may not connect to %s", ip)
+	}
+
+	return nil
+}
+
+func (f *AddressFilter) Allows(ip net.IP) bool {
+	if containsIP(f.allowed, ip) {
+		return true
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+
+	return !containsIP(f.denied, ip)
+}
+
+func containsIP(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package syslogdrain_test
+
+import (
+	"net"
+
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AddressFilter", func() {
+	var addressFilter *syslogdrain.AddressFilter
+
+	BeforeEach(func() {
+		var err error
+		addressFilter, err = syslogdrain.NewAddressFilter([]string{"10.1.2.0/24"}, []string{"100.64.0.0/10"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("addresses",
+		func(address string, allowed bool) {
+			Expect(addressFilter.Allows(net.ParseIP(address))).To(Equal(allowed))
+		},
+		Entry("public IPv4", "203.0.113.10", true),
+		Entry("public IPv6", "2001:db8::1", true),
+		Entry("loopback", "127.0.0.1", false),
+		Entry("IPv6 loopback", "::1", false),
+		Entry("IPv4 mapped loopback", "::ffff:127.0.0.1", false),
+		Entry("private", "192.168.1.1", false),
+		Entry("IPv6 unique local", "fd00::1", false),
+		Entry("link-local", "169.254.169.254", false),
+		Entry("IPv6 link-local", "fe80::1", false),
+		Entry("unspecified", "0.0.0.0", false),
+		Entry("multicast", "224.0.0.1", false),
+		Entry("denied range", "100.64.1.1", false),
+		Entry("allowed private range", "10.1.2.3", true),
+	)
+
+	It("checks the resolved address of connections", func() {
+		Expect(addressFilter.Control("tcp", "203.0.113.10:514", nil)).To(Succeed())
+		Expect(addressFilter.Control("tcp", "[fe80::1]:514", nil)).To(MatchError(ContainSubstring("syslog drains may not connect to fe80::1")))
+	})
+
+	It("rejects invalid CIDRs", func() {
+		_, err := syslogdrain.NewAddressFilter([]string{"10.0.0.0"}, nil)
+		Expect(err).To(MatchError(ContainSubstring(`invalid CIDR "10.0.0.0"`)))
+	})
+})
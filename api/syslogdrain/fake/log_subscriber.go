@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+)
+
+type LogSubscriber struct {
+	SubscribeStub        func(string) (<-chan repositories.LogRecord, func())
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct {
+		arg1 string
+	}
+	subscribeReturns struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+	}
+	subscribeReturnsOnCall map[int]struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *LogSubscriber) Subscribe(arg1 string) (<-chan repositories.LogRecord, func()) {
+	fake.subscribeMutex.Lock()
+	ret, specificReturn := fake.subscribeReturnsOnCall[len(fake.subscribeArgsForCall)]
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SubscribeStub
+	fakeReturns := fake.subscribeReturns
+	fake.recordInvocation("Subscribe", []interface{}{arg1})
+	fake.subscribeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *LogSubscriber) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *LogSubscriber) SubscribeCalls(stub func(string) (<-chan repositories.LogRecord, func())) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = stub
+}
+
+func (fake *LogSubscriber) SubscribeArgsForCall(i int) string {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	argsForCall := fake.subscribeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *LogSubscriber) SubscribeReturns(result1 <-chan repositories.LogRecord, result2 func()) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *LogSubscriber) SubscribeReturnsOnCall(i int, result1 <-chan repositories.LogRecord, result2 func()) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	if fake.subscribeReturnsOnCall == nil {
+		fake.subscribeReturnsOnCall = make(map[int]struct {
+			result1 <-chan repositories.LogRecord
+			result2 func()
+		})
+	}
+	fake.subscribeReturnsOnCall[i] = struct {
+		result1 <-chan repositories.LogRecord
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *LogSubscriber) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *LogSubscriber) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ syslogdrain.LogSubscriber = new(LogSubscriber)
@@ -0,0 +1,115 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+)
+
+type WriterFactory struct {
+	Stub        func(string) (syslogdrain.Writer, error)
+	mutex       sync.RWMutex
+	argsForCall []struct {
+		arg1 string
+	}
+	returns struct {
+		result1 syslogdrain.Writer
+		result2 error
+	}
+	returnsOnCall map[int]struct {
+		result1 syslogdrain.Writer
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *WriterFactory) Spy(arg1 string) (syslogdrain.Writer, error) {
+	fake.mutex.Lock()
+	ret, specificReturn := fake.returnsOnCall[len(fake.argsForCall)]
+	fake.argsForCall = append(fake.argsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.Stub
+	returns := fake.returns
+	fake.recordInvocation("WriterFactory", []interface{}{arg1})
+	fake.mutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return returns.result1, returns.result2
+}
+
+func (fake *WriterFactory) CallCount() int {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return len(fake.argsForCall)
+}
+
+func (fake *WriterFactory) Calls(stub func(string) (syslogdrain.Writer, error)) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = stub
+}
+
+func (fake *WriterFactory) ArgsForCall(i int) string {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return fake.argsForCall[i].arg1
+}
+
+func (fake *WriterFactory) Returns(result1 syslogdrain.Writer, result2 error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	fake.returns = struct {
+		result1 syslogdrain.Writer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WriterFactory) ReturnsOnCall(i int, result1 syslogdrain.Writer, result2 error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	if fake.returnsOnCall == nil {
+		fake.returnsOnCall = make(map[int]struct {
+			result1 syslogdrain.Writer
+			result2 error
+		})
+	}
+	fake.returnsOnCall[i] = struct {
+		result1 syslogdrain.Writer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WriterFactory) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *WriterFactory) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ syslogdrain.WriterFactory = new(WriterFactory).Spy
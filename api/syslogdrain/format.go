@@ -0,0 +1,61 @@
+package syslogdrain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+)
+
+const (
+	// user-level messages (facility 1) with informational severity (6)
+	priority = 1*8 + 6
+
+	maxHostnameLength = 255
+	maxProcIDLength   = 128
+)
+
+// Format renders a log record as an RFC 5424 syslog message, following the conventions of Cloud Foundry drains:
+// the app guid is the APP-NAME and the source type and instance of the line are the PROCID
+func Format(record repositories.LogRecord, hostname, appGUID string) []byte {
+	timestamp := time.Now()
+	if record.Timestamp != 0 {
+		timestamp = time.Unix(0, record.Timestamp)
+	}
+
+	instanceID := record.InstanceID
+	if instanceID == "" {
+		instanceID = "0"
+	}
+	procID := fmt.Sprintf("[%s/%s]", record.Tags["source_type"], instanceID)
+
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s %s - - %s\n",
+		priority,
+		timestamp.UTC().Format(time.RFC3339Nano),
+		headerField(hostname, maxHostnameLength),
+		headerField(appGUID, maxHostnameLength),
+		headerField(procID, maxProcIDLength),
+		record.Message,
+	))
+}
+
+// headerField makes a value fit into a syslog header field, which may only contain printable ASCII characters
+// other than spaces
+func headerField(value string, maxLength int) string {
+	field := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '-'
+		}
+		return r
+	}, value)
+
+	if field == "" {
+		return "-"
+	}
+	if len(field) > maxLength {
+		return field[:maxLength]
+	}
+
+	return field
+}
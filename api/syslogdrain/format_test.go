@@ -0,0 +1,58 @@
+package syslogdrain_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+	var (
+		record   repositories.LogRecord
+		hostname string
+		message  string
+	)
+
+	BeforeEach(func() {
+		record = repositories.LogRecord{
+			Message:    "hello world",
+			Timestamp:  time.Date(2022, 10, 1, 12, 0, 0, 1000, time.UTC).UnixNano(),
+			InstanceID: "2",
+			Tags:       map[string]string{"source_type": "APP/PROC/WEB"},
+		}
+		hostname = "my-app"
+	})
+
+	JustBeforeEach(func() {
+		message = string(syslogdrain.Format(record, hostname, "app-guid"))
+	})
+
+	It("formats the record as an RFC 5424 message", func() {
+		Expect(message).To(Equal("<14>1 2022-10-01T12:00:00.000001Z my-app app-guid [APP/PROC/WEB/2] - - hello world\n"))
+	})
+
+	When("the hostname contains spaces", func() {
+		BeforeEach(func() {
+			hostname = "my great app"
+		})
+
+		It("replaces them", func() {
+			Expect(message).To(HavePrefix("<14>1 2022-10-01T12:00:00.000001Z my-great-app app-guid "))
+		})
+	})
+
+	When("the record has no instance id", func() {
+		BeforeEach(func() {
+			record.InstanceID = ""
+			record.Tags["source_type"] = "STG"
+		})
+
+		It("uses instance 0", func() {
+			Expect(message).To(ContainSubstring(" [STG/0] "))
+		})
+	})
+})
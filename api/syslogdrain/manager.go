@@ -0,0 +1,276 @@
+package syslogdrain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/repositories"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfserviceinstances;cfservicebindings,verbs=list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfservicebindings/status,verbs=patch
+
+// DefaultBackoff retries writes to an unavailable drain with exponentially growing intervals of up to a minute
+var DefaultBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Steps:    math.MaxInt32,
+	Cap:      time.Minute,
+}
+
+//counterfeiter:generate -o fake -fake-name LogSubscriber . LogSubscriber
+
+type LogSubscriber interface {
+	Subscribe(appGUID string) (<-chan repositories.LogRecord, func())
+}
+
+// Manager forwards the logs of every app bound to a user-provided service instance with a syslog drain URL to
+// that drain. The health of each drain is reported on the SyslogDrainHealthy condition of the binding.
+type Manager struct {
+	k8sClient client.Client
+	logs      LogSubscriber
+	newWriter WriterFactory
+	backoff   wait.Backoff
+	logger    logr.Logger
+
+	mutex  sync.Mutex
+	drains map[types.NamespacedName]*runningDrain
+
+	healthMutex sync.Mutex
+	healthy     map[types.NamespacedName]bool
+}
+
+// drainSpec describes a drain of the logs of an app, keyed by the binding of the app to the drain instance
+type drainSpec struct {
+	binding  types.NamespacedName
+	appGUID  string
+	hostname string
+	url      string
+}
+
+type runningDrain struct {
+	spec   drainSpec
+	cancel context.CancelFunc
+}
+
+func NewManager(k8sClient client.Client, logs LogSubscriber, newWriter WriterFactory, backoff wait.Backoff, logger logr.Logger) *Manager {
+	return &Manager{
+		k8sClient: k8sClient,
+		logs:      logs,
+		newWriter: newWriter,
+		backoff:   backoff,
+		logger:    logger,
+		drains:    map[types.NamespacedName]*runningDrain{},
+		healthy:   map[types.NamespacedName]bool{},
+	}
+}
+
+// EventHandler returns the handler to register with the service instance and service binding informers, so
+// that drains are started and stopped as bindings and drain URLs change
+func (m *Manager) EventHandler(ctx context.Context) toolscache.ResourceEventHandler {
+	sync := func() {
+		if err := m.Sync(ctx); err != nil {
+			m.logger.Info("failed to sync syslog drains", "err", err)
+		}
+	}
+
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { sync() },
+		UpdateFunc: func(interface{}, interface{}) { sync() },
+		DeleteFunc: func(interface{}) { sync() },
+	}
+}
+
+// Sync starts the drains of new bindings, and stops the drains of deleted bindings and of instances whose
+// drain URL has been removed. Drains whose URL has changed are restarted. Drains are stopped when ctx is done.
+func (m *Manager) Sync(ctx context.Context) error {
+	desired, err := m.desiredDrains(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key, running := range m.drains {
+		if spec, ok := desired[key]; ok && spec == running.spec {
+			continue
+		}
+
+		running.cancel()
+		delete(m.drains, key)
+		m.forgetHealth(key)
+	}
+
+	for key, spec := range desired {
+		if _, ok := m.drains[key]; ok {
+			continue
+		}
+
+		drainCtx, cancel := context.WithCancel(ctx)
+		m.drains[key] = &runningDrain{spec: spec, cancel: cancel}
+		go m.run(drainCtx, spec)
+	}
+
+	return nil
+}
+
+func (m *Manager) desiredDrains(ctx context.Context) (map[types.NamespacedName]drainSpec, error) {
+	instanceList := new(korifiv1alpha1.CFServiceInstanceList)
+	if err := m.k8sClient.List(ctx, instanceList); err != nil {
+		return nil, fmt.Errorf("failed to list service instances: %w", err)
+	}
+
+	drainURLs := map[types.NamespacedName]string{}
+	for _, instance := range instanceList.Items {
+		if instance.Spec.Type == korifiv1alpha1.UserProvidedType && instance.Spec.SyslogDrainURL != "" {
+			drainURLs[client.ObjectKeyFromObject(&instance)] = instance.Spec.SyslogDrainURL
+		}
+	}
+
+	bindingList := new(korifiv1alpha1.CFServiceBindingList)
+	if err := m.k8sClient.List(ctx, bindingList); err != nil {
+		return nil, fmt.Errorf("failed to list service bindings: %w", err)
+	}
+
+	desired := map[types.NamespacedName]drainSpec{}
+	for _, binding := range bindingList.Items {
+		if !binding.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		drainURL, ok := drainURLs[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Spec.Service.Name}]
+		if !ok {
+			continue
+		}
+
+		key := client.ObjectKeyFromObject(&binding)
+		desired[key] = drainSpec{
+			binding:  key,
+			appGUID:  binding.Spec.AppRef.Name,
+			hostname: m.appName(ctx, binding.Namespace, binding.Spec.AppRef.Name),
+			url:      drainURL,
+		}
+	}
+
+	return desired, nil
+}
+
+func (m *Manager) appName(ctx context.Context, namespace, appGUID string) string {
+	cfApp := new(korifiv1alpha1.CFApp)
+	if err := m.k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: appGUID}, cfApp); err != nil {
+		return appGUID
+	}
+
+	return cfApp.Spec.DisplayName
+}
+
+func (m *Manager) run(ctx context.Context, spec drainSpec) {
+	records, unsubscribe := m.logs.Subscribe(spec.appGUID)
+	defer unsubscribe()
+
+	writer, err := m.newWriter(spec.url)
+	if err != nil {
+		m.reportHealth(ctx, spec, err)
+		return
+	}
+	defer writer.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record, ok := <-records:
+			if !ok {
+				return
+			}
+			m.deliver(ctx, writer, spec, Format(record, spec.hostname, spec.appGUID))
+		}
+	}
+}
+
+// deliver retries writing a message until it succeeds or the drain is stopped. Records logged in the meantime
+// queue up in the log subscription, and are dropped once it is full.
+func (m *Manager) deliver(ctx context.Context, writer Writer, spec drainSpec, message []byte) {
+	backoff := m.backoff
+	for {
+		err := writer.Write(ctx, message)
+		m.reportHealth(ctx, spec, err)
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// reportHealth sets the SyslogDrainHealthy condition of the binding whenever the health of its drain changes
+func (m *Manager) reportHealth(ctx context.Context, spec drainSpec, drainErr error) {
+	if ctx.Err() != nil {
+		// the drain has been stopped
+		return
+	}
+
+	healthy := drainErr == nil
+
+	m.healthMutex.Lock()
+	wasHealthy, known := m.healthy[spec.binding]
+	m.healthy[spec.binding] = healthy
+	m.healthMutex.Unlock()
+
+	if known && wasHealthy == healthy {
+		return
+	}
+
+	logger := m.logger.WithValues("namespace", spec.binding.Namespace, "binding", spec.binding.Name)
+	if !healthy {
+		logger.Info("syslog drain is unhealthy", "err", drainErr)
+	}
+
+	condition := metav1.Condition{
+		Type:    korifiv1alpha1.SyslogDrainHealthyConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "LogsForwarded",
+		Message: "Logs are forwarded to the syslog drain",
+	}
+	if !healthy {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ForwardingFailed"
+		condition.Message = drainErr.Error()
+	}
+
+	binding := new(korifiv1alpha1.CFServiceBinding)
+	if err := m.k8sClient.Get(ctx, spec.binding, binding); err != nil {
+		logger.Info("failed to get service binding", "err", err)
+		return
+	}
+
+	originalBinding := binding.DeepCopy()
+	meta.SetStatusCondition(&binding.Status.Conditions, condition)
+	if err := m.k8sClient.Status().Patch(ctx, binding, client.MergeFrom(originalBinding)); err != nil {
+		logger.Info("failed to report syslog drain health", "err", err)
+	}
+}
+
+func (m *Manager) forgetHealth(key types.NamespacedName) {
+	m.healthMutex.Lock()
+	defer m.healthMutex.Unlock()
+
+	delete(m.healthy, key)
+}
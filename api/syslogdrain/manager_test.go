@@ -0,0 +1,298 @@
+package syslogdrain_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/korifi/api/logcache"
+	"code.cloudfoundry.org/korifi/api/repositories"
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+	"code.cloudfoundry.org/korifi/api/syslogdrain/fake"
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	controllersfake "code.cloudfoundry.org/korifi/controllers/fake"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		ctx           context.Context
+		cancel        context.CancelFunc
+		k8sClient     *controllersfake.Client
+		statusWriter  *controllersfake.StatusWriter
+		store         *logcache.Store
+		writerFactory *fake.WriterFactory
+		writer        *fake.Writer
+		manager       *syslogdrain.Manager
+
+		mutex     sync.Mutex
+		instances []korifiv1alpha1.CFServiceInstance
+		bindings  []korifiv1alpha1.CFServiceBinding
+		syncErr   error
+	)
+
+	setInstances := func(items ...korifiv1alpha1.CFServiceInstance) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		instances = items
+	}
+
+	setBindings := func(items ...korifiv1alpha1.CFServiceBinding) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		bindings = items
+	}
+
+	instance := func(name, drainURL string) korifiv1alpha1.CFServiceInstance {
+		return korifiv1alpha1.CFServiceInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "space-guid", Name: name},
+			Spec: korifiv1alpha1.CFServiceInstanceSpec{
+				Type:           korifiv1alpha1.UserProvidedType,
+				SyslogDrainURL: drainURL,
+			},
+		}
+	}
+
+	binding := func(name, instanceName, appGUID string) korifiv1alpha1.CFServiceBinding {
+		return korifiv1alpha1.CFServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "space-guid", Name: name},
+			Spec: korifiv1alpha1.CFServiceBindingSpec{
+				Service: corev1.ObjectReference{Name: instanceName},
+				AppRef:  corev1.LocalObjectReference{Name: appGUID},
+			},
+		}
+	}
+
+	patchedConditions := func() []metav1.Condition {
+		conditions := []metav1.Condition{}
+		for i := 0; i < statusWriter.PatchCallCount(); i++ {
+			_, obj, _, _ := statusWriter.PatchArgsForCall(i)
+			cfBinding, ok := obj.(*korifiv1alpha1.CFServiceBinding)
+			Expect(ok).To(BeTrue())
+			conditions = append(conditions, *meta.FindStatusCondition(cfBinding.Status.Conditions, korifiv1alpha1.SyslogDrainHealthyConditionType))
+		}
+
+		return conditions
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		k8sClient = new(controllersfake.Client)
+		statusWriter = new(controllersfake.StatusWriter)
+		k8sClient.StatusReturns(statusWriter)
+
+		setInstances(instance("drain-instance", "syslog://logs.example.com"), instance("other-instance", ""))
+		setBindings(binding("drain-binding", "drain-instance", "app-guid"), binding("other-binding", "other-instance", "other-app-guid"))
+
+		k8sClient.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			switch list := list.(type) {
+			case *korifiv1alpha1.CFServiceInstanceList:
+				list.Items = append([]korifiv1alpha1.CFServiceInstance{}, instances...)
+			case *korifiv1alpha1.CFServiceBindingList:
+				list.Items = append([]korifiv1alpha1.CFServiceBinding{}, bindings...)
+			default:
+				Fail("unexpected list")
+			}
+			return nil
+		}
+		k8sClient.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+			switch obj := obj.(type) {
+			case *korifiv1alpha1.CFApp:
+				obj.Spec.DisplayName = "my-app"
+			case *korifiv1alpha1.CFServiceBinding:
+				obj.Name = key.Name
+				obj.Namespace = key.Namespace
+			}
+			return nil
+		}
+
+		store = logcache.NewStore(10)
+		writer = new(fake.Writer)
+		writerFactory = new(fake.WriterFactory)
+		writerFactory.Returns(writer, nil)
+
+		manager = syslogdrain.NewManager(k8sClient, store, writerFactory.Spy, wait.Backoff{
+			Duration: time.Millisecond,
+			Factor:   1,
+			Steps:    math.MaxInt32,
+		}, logr.Discard())
+	})
+
+	JustBeforeEach(func() {
+		syncErr = manager.Sync(ctx)
+	})
+
+	It("starts a drain for every binding to an instance with a drain URL", func() {
+		Expect(syncErr).NotTo(HaveOccurred())
+		Eventually(writerFactory.CallCount).Should(Equal(1))
+		Expect(writerFactory.ArgsForCall(0)).To(Equal("syslog://logs.example.com"))
+	})
+
+	When("the bound app logs", func() {
+		JustBeforeEach(func() {
+			Eventually(writerFactory.CallCount).Should(Equal(1))
+			// wait for the drain to subscribe before logging
+			Eventually(func() int {
+				store.Append("app-guid", repositories.LogRecord{
+					Message:   "hello",
+					Timestamp: 1,
+					Tags:      map[string]string{"source_type": "APP/PROC/WEB"},
+				})
+				return writer.WriteCallCount()
+			}).ShouldNot(BeZero())
+		})
+
+		It("forwards the logs to the drain", func() {
+			_, message := writer.WriteArgsForCall(0)
+			Expect(string(message)).To(Equal("<14>1 1970-01-01T00:00:00.000000001Z my-app app-guid [APP/PROC/WEB/0] - - hello\n"))
+		})
+
+		It("reports the drain as healthy", func() {
+			Eventually(patchedConditions).Should(ConsistOf(haveConditionStatus(metav1.ConditionTrue)))
+		})
+
+		It("does not forward the logs of other apps", func() {
+			store.Append("other-app-guid", repositories.LogRecord{Message: "other"})
+			Consistently(func() []string {
+				messages := []string{}
+				for i := 0; i < writer.WriteCallCount(); i++ {
+					_, message := writer.WriteArgsForCall(i)
+					messages = append(messages, string(message))
+				}
+				return messages
+			}).ShouldNot(ContainElement(HaveSuffix(" other\n")))
+		})
+	})
+
+	When("writing to the drain fails", func() {
+		BeforeEach(func() {
+			var calls int
+			var callsMutex sync.Mutex
+			writer.WriteStub = func(context.Context, []byte) error {
+				callsMutex.Lock()
+				defer callsMutex.Unlock()
+				calls++
+				if calls <= 2 {
+					return errors.New("drain-down")
+				}
+				return nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			Eventually(writerFactory.CallCount).Should(Equal(1))
+			Eventually(func() int {
+				store.Append("app-guid", repositories.LogRecord{Message: "retried"})
+				return writer.WriteCallCount()
+			}).ShouldNot(BeZero())
+		})
+
+		It("retries until the message is written", func() {
+			Eventually(writer.WriteCallCount).Should(BeNumerically(">=", 3))
+			for i := 0; i < 3; i++ {
+				_, message := writer.WriteArgsForCall(i)
+				Expect(string(message)).To(HaveSuffix(" retried\n"))
+			}
+		})
+
+		It("reports the drain as unhealthy until it recovers", func() {
+			Eventually(patchedConditions).Should(HaveLen(2))
+			Expect(patchedConditions()).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(korifiv1alpha1.SyslogDrainHealthyConditionType),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("ForwardingFailed"),
+					"Message": Equal("drain-down"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal(korifiv1alpha1.SyslogDrainHealthyConditionType),
+					"Status":  Equal(metav1.ConditionTrue),
+					"Reason":  Equal("LogsForwarded"),
+					"Message": Equal("Logs are forwarded to the syslog drain"),
+				}),
+			))
+			_, firstPatched, _, _ := statusWriter.PatchArgsForCall(0)
+			Expect(firstPatched.(*korifiv1alpha1.CFServiceBinding).Status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	When("the drain writer cannot be created", func() {
+		BeforeEach(func() {
+			writerFactory.Returns(nil, errors.New("bad-url"))
+		})
+
+		It("reports the drain as unhealthy", func() {
+			Eventually(patchedConditions).Should(ConsistOf(haveConditionStatus(metav1.ConditionFalse)))
+		})
+	})
+
+	When("the drain is synced again without changes", func() {
+		JustBeforeEach(func() {
+			Eventually(writerFactory.CallCount).Should(Equal(1))
+			Expect(manager.Sync(ctx)).To(Succeed())
+		})
+
+		It("keeps the running drain", func() {
+			Consistently(writerFactory.CallCount).Should(Equal(1))
+		})
+	})
+
+	When("the drain URL changes", func() {
+		JustBeforeEach(func() {
+			Eventually(writerFactory.CallCount).Should(Equal(1))
+			setInstances(instance("drain-instance", "syslog-tls://logs.example.com"))
+			Expect(manager.Sync(ctx)).To(Succeed())
+		})
+
+		It("restarts the drain with the new URL", func() {
+			Eventually(writerFactory.CallCount).Should(Equal(2))
+			Expect(writerFactory.ArgsForCall(1)).To(Equal("syslog-tls://logs.example.com"))
+			Eventually(writer.CloseCallCount).Should(Equal(1))
+		})
+	})
+
+	When("the binding is deleted", func() {
+		JustBeforeEach(func() {
+			Eventually(writerFactory.CallCount).Should(Equal(1))
+			setBindings()
+			Expect(manager.Sync(ctx)).To(Succeed())
+		})
+
+		It("stops the drain", func() {
+			Eventually(writer.CloseCallCount).Should(Equal(1))
+		})
+	})
+
+	When("listing service instances fails", func() {
+		BeforeEach(func() {
+			k8sClient.ListStub = nil
+			k8sClient.ListReturns(errors.New("list-boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(syncErr).To(MatchError(ContainSubstring("list-boom")))
+		})
+	})
+})
+
+func haveConditionStatus(status metav1.ConditionStatus) OmegaMatcher {
+	return WithTransform(func(condition metav1.Condition) metav1.ConditionStatus {
+		return condition.Status
+	}, Equal(status))
+}
@@ -0,0 +1,3 @@
+package syslogdrain
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -0,0 +1,13 @@
+package syslogdrain_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSyslogDrain(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Syslog Drain Suite")
+}
@@ -0,0 +1,196 @@
+package syslogdrain
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	SyslogScheme    = "syslog"
+	SyslogTLSScheme = "syslog-tls"
+	HTTPSScheme     = "https"
+
+	defaultSyslogPort    = "514"
+	defaultSyslogTLSPort = "6514"
+
+	writeTimeout = 10 * time.Second
+)
+
+//counterfeiter:generate -o fake -fake-name Writer . Writer
+
+// Writer sends syslog messages to a drain
+type Writer interface {
+	Write(ctx context.Context, message []byte) error
+	Close() error
+}
+
+//counterfeiter:generate -o fake -fake-name WriterFactory . WriterFactory
+
+type WriterFactory func(drainURL string) (Writer, error)
+
+// ValidateURL checks that a drain URL uses one of the supported schemes
+func ValidateURL(drainURL string) error {
+	u, err := url.Parse(drainURL)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case SyslogScheme, SyslogTLSScheme, HTTPSScheme:
+	default:
+		return fmt.Errorf("unsupported syslog drain scheme %q: supported schemes are %s, %s and %s", u.Scheme, SyslogScheme, SyslogTLSScheme, HTTPSScheme)
+	}
+
+	if u.Hostname() == "" {
+		return fmt.Errorf("syslog drain URL %q has no host", drainURL)
+	}
+
+	return nil
+}
+
+// NewWriterFactory returns a factory of writers for the supported drain schemes. Messages are framed with octet
+// counting (RFC 6587) over TCP and TLS, and sent one per request over HTTPS. Writers only connect to the addresses
+// the filter allows.
+func NewWriterFactory(tlsConfig *tls.Config, addressFilter *AddressFilter) WriterFactory {
+	return func(drainURL string) (Writer, error) {
+		if err := ValidateURL(drainURL); err != nil {
+			return nil, err
+		}
+
+		u, err := url.Parse(drainURL)
+		if err != nil {
+			return nil, err
+		}
+
+		switch u.Scheme {
+		case SyslogScheme:
+			return &streamWriter{address: hostPort(u, defaultSyslogPort), dial: dialTCP(addressFilter)}, nil
+		case SyslogTLSScheme:
+			return &streamWriter{address: hostPort(u, defaultSyslogTLSPort), dial: dialTLS(tlsConfig, addressFilter, u.Hostname())}, nil
+		default:
+			return &httpsWriter{
+				url: drainURL,
+				client: &http.Client{
+					Timeout: writeTimeout,
+					Transport: &http.Transport{
+						DialContext:     newDialer(addressFilter).DialContext,
+						TLSClientConfig: tlsConfig,
+					},
+				},
+			}, nil
+		}
+	}
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+type dialFunc func(ctx context.Context, address string) (net.Conn, error)
+
+func newDialer(addressFilter *AddressFilter) *net.Dialer {
+	return &net.Dialer{Timeout: writeTimeout, Control: addressFilter.Control}
+}
+
+func dialTCP(addressFilter *AddressFilter) dialFunc {
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		return newDialer(addressFilter).DialContext(ctx, "tcp", address)
+	}
+}
+
+func dialTLS(tlsConfig *tls.Config, addressFilter *AddressFilter, serverName string) dialFunc {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tlsConfig != nil {
+		config = tlsConfig.Clone()
+	}
+	config.ServerName = serverName
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		dialer := tls.Dialer{NetDialer: newDialer(addressFilter), Config: config}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+}
+
+// streamWriter keeps a connection to the drain open across writes, and reconnects on the next write after a
+// failure
+type streamWriter struct {
+	address string
+	dial    dialFunc
+	conn    net.Conn
+}
+
+func (w *streamWriter) Write(ctx context.Context, message []byte) error {
+	if w.conn == nil {
+		conn, err := w.dial(ctx, w.address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog drain: %w", err)
+		}
+		w.conn = conn
+	}
+
+	if err := w.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return w.fail(err)
+	}
+
+	if _, err := fmt.Fprintf(w.conn, "%d %s", len(message), message); err != nil {
+		return w.fail(err)
+	}
+
+	return nil
+}
+
+func (w *streamWriter) fail(err error) error {
+	_ = w.Close()
+	return fmt.Errorf("failed to write to syslog drain: %w", err)
+}
+
+func (w *streamWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+type httpsWriter struct {
+	url    string
+	client *http.Client
+}
+
+func (w *httpsWriter) Write(ctx context.Context, message []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to syslog drain: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("syslog drain responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (w *httpsWriter) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
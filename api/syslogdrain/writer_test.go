@@ -0,0 +1,242 @@
+package syslogdrain_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/korifi/api/syslogdrain"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Writers", func() {
+	var (
+		ctx           context.Context
+		tlsServer     *httptest.Server
+		addressFilter *syslogdrain.AddressFilter
+		writerFactory syslogdrain.WriterFactory
+	)
+
+	// readFrame reads an octet counted syslog message
+	readFrame := func(reader *bufio.Reader) string {
+		length, err := reader.ReadString(' ')
+		Expect(err).NotTo(HaveOccurred())
+		size, err := strconv.Atoi(strings.TrimSpace(length))
+		Expect(err).NotTo(HaveOccurred())
+
+		frame := make([]byte, size)
+		_, err = io.ReadFull(reader, frame)
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(frame)
+	}
+
+	// listen accepts a single connection and sends the messages it receives to the returned channel
+	listen := func(listener net.Listener) <-chan string {
+		messages := make(chan string, 10)
+		go func() {
+			defer GinkgoRecover()
+
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			for {
+				if _, err := reader.Peek(1); err != nil {
+					return
+				}
+				messages <- readFrame(reader)
+			}
+		}()
+
+		return messages
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		// only used for its certificate
+		tlsServer = httptest.NewTLSServer(http.NotFoundHandler())
+		DeferCleanup(tlsServer.Close)
+
+		// the test drains listen on the loopback interface
+		var err error
+		addressFilter, err = syslogdrain.NewAddressFilter([]string{"127.0.0.0/8"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		writerFactory = syslogdrain.NewWriterFactory(tlsServer.Client().Transport.(*http.Transport).TLSClientConfig, addressFilter)
+	})
+
+	Describe("syslog drains", func() {
+		var (
+			listener net.Listener
+			messages <-chan string
+			writer   syslogdrain.Writer
+		)
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(func() { _ = listener.Close() })
+			messages = listen(listener)
+
+			writer, err = writerFactory("syslog://" + listener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(writer.Close)
+		})
+
+		It("sends octet counted messages over TCP", func() {
+			Expect(writer.Write(ctx, []byte("<14>1 first\n"))).To(Succeed())
+			Expect(writer.Write(ctx, []byte("<14>1 second\n"))).To(Succeed())
+
+			Eventually(messages).Should(Receive(Equal("<14>1 first\n")))
+			Eventually(messages).Should(Receive(Equal("<14>1 second\n")))
+		})
+
+		When("the drain address is not allowed", func() {
+			BeforeEach(func() {
+				var err error
+				addressFilter, err = syslogdrain.NewAddressFilter(nil, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				writer, err = syslogdrain.NewWriterFactory(nil, addressFilter)("syslog://" + listener.Addr().String())
+				Expect(err).NotTo(HaveOccurred())
+				DeferCleanup(writer.Close)
+			})
+
+			It("does not connect to it", func() {
+				Expect(writer.Write(ctx, []byte("<14>1 blocked\n"))).To(MatchError(ContainSubstring("syslog drains may not connect to 127.0.0.1")))
+				Consistently(messages).ShouldNot(Receive())
+			})
+		})
+
+		When("the drain is not listening", func() {
+			BeforeEach(func() {
+				Expect(listener.Close()).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				Expect(writer.Write(ctx, []byte("<14>1 lost\n"))).To(MatchError(ContainSubstring("failed to connect")))
+			})
+		})
+	})
+
+	Describe("syslog-tls drains", func() {
+		var messages <-chan string
+
+		BeforeEach(func() {
+			listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+				Certificates: tlsServer.TLS.Certificates,
+				MinVersion:   tls.VersionTLS12,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(listener.Close)
+			messages = listen(listener)
+
+			writer, err := writerFactory("syslog-tls://" + listener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(writer.Close)
+
+			Expect(writer.Write(ctx, []byte("<14>1 secret\n"))).To(Succeed())
+		})
+
+		It("sends octet counted messages over TLS", func() {
+			Eventually(messages).Should(Receive(Equal("<14>1 secret\n")))
+		})
+	})
+
+	Describe("https drains", func() {
+		var (
+			httpsServer *httptest.Server
+			bodies      chan string
+			status      int
+			writeErr    error
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			bodies = make(chan string, 10)
+			httpsServer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies <- string(body)
+				w.WriteHeader(status)
+			}))
+			DeferCleanup(httpsServer.Close)
+
+			writerFactory = syslogdrain.NewWriterFactory(httpsServer.Client().Transport.(*http.Transport).TLSClientConfig, addressFilter)
+		})
+
+		JustBeforeEach(func() {
+			writer, err := writerFactory(httpsServer.URL + "/drain")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(writer.Close)
+
+			writeErr = writer.Write(ctx, []byte("<14>1 posted\n"))
+		})
+
+		It("posts each message", func() {
+			Expect(writeErr).NotTo(HaveOccurred())
+			Expect(bodies).To(Receive(Equal("<14>1 posted\n")))
+		})
+
+		When("the drain address is not allowed", func() {
+			BeforeEach(func() {
+				var err error
+				addressFilter, err = syslogdrain.NewAddressFilter(nil, nil)
+				Expect(err).NotTo(HaveOccurred())
+				writerFactory = syslogdrain.NewWriterFactory(httpsServer.Client().Transport.(*http.Transport).TLSClientConfig, addressFilter)
+			})
+
+			It("does not post to it", func() {
+				Expect(writeErr).To(MatchError(ContainSubstring("syslog drains may not connect to 127.0.0.1")))
+				Expect(bodies).NotTo(Receive())
+			})
+		})
+
+		When("the drain rejects the message", func() {
+			BeforeEach(func() {
+				status = http.StatusServiceUnavailable
+			})
+
+			It("returns an error", func() {
+				Expect(writeErr).To(MatchError(ContainSubstring("503")))
+			})
+		})
+	})
+
+	It("does not create writers for unsupported schemes", func() {
+		_, err := writerFactory("ftp://logs.example.com")
+		Expect(err).To(MatchError(ContainSubstring("unsupported syslog drain scheme")))
+	})
+})
+
+var _ = Describe("ValidateURL", func() {
+	DescribeTable("drain URLs",
+		func(drainURL string, valid bool) {
+			err := syslogdrain.ValidateURL(drainURL)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("syslog", "syslog://logs.example.com:514", true),
+		Entry("syslog-tls", "syslog-tls://logs.example.com", true),
+		Entry("https", "https://logs.example.com/drain", true),
+		Entry("http", "http://logs.example.com/drain", false),
+		Entry("no host", "syslog://", false),
+		Entry("not a URL", "::", false),
+	)
+})
@@ -57,6 +57,10 @@ type AppWorkloadSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The isolation segment the instances should be scheduled on. Nil means the shared segment
+	// +kubebuilder:validation:Optional
+	IsolationSegment *IsolationSegmentPlacement `json:"isolationSegment,omitempty"`
 }
 
 // AppWorkloadStatus defines the observed state of AppWorkload
@@ -56,6 +56,12 @@ type CFAppStatus struct {
 
 	// VCAPServicesSecretName contains the name of the CFApp's VCAP_SERVICES Secret, which should exist in the same namespace
 	VCAPServicesSecretName string `json:"vcapServicesSecretName"`
+
+	// RunningEnvVarGroupHash is the hash of the running environment variable group that the app runs with, whose values
+	// are read from the group Secret in the root namespace. It is only refreshed while the app is not running, unless the
+	// app opts in to restarts on changes to the group by setting the
+	// "korifi.cloudfoundry.org/restart-on-env-var-group-change" annotation to "true"
+	RunningEnvVarGroupHash string `json:"runningEnvVarGroupHash,omitempty"`
 }
 
 //+kubebuilder:object:root=true
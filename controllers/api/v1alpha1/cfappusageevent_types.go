@@ -0,0 +1,110 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AppUsageStateStarted     = "STARTED"
+	AppUsageStateStopped     = "STOPPED"
+	AppUsageStateTaskStarted = "TASK_STARTED"
+	AppUsageStateTaskStopped = "TASK_STOPPED"
+)
+
+// CFAppUsageEventSpec defines the desired state of CFAppUsageEvent
+type CFAppUsageEventSpec struct {
+	// The state of the app or task after the change, e.g. STARTED or TASK_STOPPED
+	State string `json:"state"`
+
+	// The state of the app or task before the change
+	// +optional
+	PreviousState string `json:"previousState,omitempty"`
+
+	App AppUsageEventResource `json:"app"`
+
+	// The process the event refers to. Not set for task events
+	// +optional
+	Process *AppUsageEventResource `json:"process,omitempty"`
+
+	// The task the event refers to. Only set for task events
+	// +optional
+	Task *AppUsageEventResource `json:"task,omitempty"`
+
+	Space AppUsageEventResource `json:"space"`
+
+	Organization AppUsageEventResource `json:"organization"`
+
+	// The number of instances after the change
+	InstanceCount int `json:"instanceCount"`
+
+	// The number of instances before the change
+	// +optional
+	PreviousInstanceCount *int `json:"previousInstanceCount,omitempty"`
+
+	// The memory limit per instance in MiB after the change
+	MemoryInMBPerInstance int64 `json:"memoryInMBPerInstance"`
+
+	// The memory limit per instance in MiB before the change
+	// +optional
+	PreviousMemoryInMBPerInstance *int64 `json:"previousMemoryInMBPerInstance,omitempty"`
+
+	// When the change was observed. Events are listed in the order they were stored, which may differ from
+	// the order of their timestamps
+	Timestamp metav1.MicroTime `json:"timestamp"`
+}
+
+// AppUsageEventResource identifies a resource referred to by a CFAppUsageEvent. For processes the name is
+// the process type
+type AppUsageEventResource struct {
+	GUID string `json:"guid"`
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// CFAppUsageEventStatus defines the observed state of CFAppUsageEvent
+type CFAppUsageEventStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.spec.state`
+//+kubebuilder:printcolumn:name="App",type=string,JSONPath=`.spec.app.name`
+//+kubebuilder:printcolumn:name="Instances",type=integer,JSONPath=`.spec.instanceCount`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFAppUsageEvent is the Schema for the cfappusageevents API. CFAppUsageEvents live in the root namespace
+type CFAppUsageEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFAppUsageEventSpec   `json:"spec,omitempty"`
+	Status CFAppUsageEventStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFAppUsageEventList contains a list of CFAppUsageEvent
+type CFAppUsageEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFAppUsageEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFAppUsageEvent{}, &CFAppUsageEventList{})
+}
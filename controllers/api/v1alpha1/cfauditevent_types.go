@@ -0,0 +1,118 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CFAuditEventSpec defines the desired state of CFAuditEvent
+type CFAuditEventSpec struct {
+	// The CF event type, e.g. audit.app.start
+	Type string `json:"type"`
+
+	// The identity that performed the action
+	Actor AuditEventParticipant `json:"actor"`
+
+	// The resource the action was performed on
+	Target AuditEventParticipant `json:"target"`
+
+	// The GUID of the space the target belongs to, if any
+	// +optional
+	SpaceGUID string `json:"spaceGUID,omitempty"`
+
+	// The GUID of the org the target belongs to, if any
+	// +optional
+	OrganizationGUID string `json:"organizationGUID,omitempty"`
+
+	// Metadata of the API request that caused the event
+	// +optional
+	Request AuditEventRequest `json:"request,omitempty"`
+
+	// Details of an app instance crash, set on audit.app.process.crash events
+	// +optional
+	Crash *AuditEventCrash `json:"crash,omitempty"`
+}
+
+// AuditEventParticipant identifies the actor or the target of a CFAuditEvent
+type AuditEventParticipant struct {
+	GUID string `json:"guid"`
+	Type string `json:"type"`
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// AuditEventRequest captures the API request that caused a CFAuditEvent
+type AuditEventRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// +optional
+	UserAgent string `json:"userAgent,omitempty"`
+	// +optional
+	CorrelationID string `json:"correlationID,omitempty"`
+	// The admin that made the request while impersonating the actor
+	// +optional
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+}
+
+// AuditEventCrash describes the termination of an app instance container
+type AuditEventCrash struct {
+	// The index of the crashed instance
+	Index int `json:"index"`
+	// The reason the container terminated, e.g. OOMKilled or Error
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	ExitDescription string `json:"exitDescription,omitempty"`
+	ExitStatus      int32  `json:"exitStatus"`
+	// The number of times the instance has been restarted
+	CrashCount     int32       `json:"crashCount"`
+	CrashTimestamp metav1.Time `json:"crashTimestamp"`
+}
+
+// CFAuditEventStatus defines the observed state of CFAuditEvent
+type CFAuditEventStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Actor",type=string,JSONPath=`.spec.actor.name`
+//+kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.target.guid`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFAuditEvent is the Schema for the cfauditevents API. CFAuditEvents live in the namespace of the space
+// they relate to, or else in the org namespace or the root namespace, and are deleted after a retention period
+type CFAuditEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFAuditEventSpec   `json:"spec,omitempty"`
+	Status CFAuditEventStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFAuditEventList contains a list of CFAuditEvent
+type CFAuditEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFAuditEvent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFAuditEvent{}, &CFAuditEventList{})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IsolationSegmentLabelKey is the node label and taint key the API uses by default to map an isolation segment to a node pool
+	IsolationSegmentLabelKey = "korifi.cloudfoundry.org/isolation-segment"
+)
+
+// CFIsolationSegmentSpec defines the desired state of CFIsolationSegment
+type CFIsolationSegmentSpec struct {
+	// The user-friendly name of the isolation segment. Must be unique
+	DisplayName string `json:"displayName"`
+
+	// Node labels the workloads of spaces assigned to the isolation segment must be scheduled on
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations added to the workloads of spaces assigned to the isolation segment,
+	// typically matching the taints of the segment's node pool
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// The GUIDs of the orgs entitled to use the isolation segment
+	// +optional
+	Organizations []string `json:"organizations,omitempty"`
+}
+
+// CFIsolationSegmentStatus defines the observed state of CFIsolationSegment
+type CFIsolationSegmentStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFIsolationSegment is the Schema for the cfisolationsegments API. CFIsolationSegments live in the
+// root namespace and are assigned to spaces via CFSpace.Spec.IsolationSegmentRef
+type CFIsolationSegment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFIsolationSegmentSpec   `json:"spec,omitempty"`
+	Status CFIsolationSegmentStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFIsolationSegmentList contains a list of CFIsolationSegment
+type CFIsolationSegmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFIsolationSegment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFIsolationSegment{}, &CFIsolationSegmentList{})
+}
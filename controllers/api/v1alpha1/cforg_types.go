@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,10 @@ type CFOrgSpec struct {
 	// The mutable, user-friendly name of the CFOrg. Unlike metadata.name, the user can change this field.
 	// +kubebuilder:validation:Pattern="^[-\\w]+$"
 	DisplayName string `json:"displayName"`
+
+	// A reference to the CFOrgQuota (in the root namespace) limiting the resources of the org. Empty means unlimited
+	// +optional
+	QuotaRef corev1.LocalObjectReference `json:"quotaRef,omitempty"`
 }
 
 // CFOrgStatus defines the observed state of CFOrg
@@ -0,0 +1,130 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CFOrgQuotaSpec defines the desired state of CFOrgQuota
+type CFOrgQuotaSpec struct {
+	// The mutable, user-friendly name of the quota. Unlike metadata.name, the user can change this field
+	DisplayName string `json:"displayName"`
+
+	// Limits applying to the apps of every org the quota is assigned to
+	// +optional
+	Apps QuotaAppsLimits `json:"apps"`
+
+	// Limits applying to the service instances of every org the quota is assigned to
+	// +optional
+	Services QuotaServicesLimits `json:"services"`
+
+	// Limits applying to the routes of every org the quota is assigned to
+	// +optional
+	Routes QuotaRoutesLimits `json:"routes"`
+
+	// Limits applying to the private domains of every org the quota is assigned to
+	// +optional
+	Domains QuotaDomainsLimits `json:"domains"`
+}
+
+// QuotaAppsLimits is shared by CFOrgQuota and CFSpaceQuota. A nil limit means unlimited
+type QuotaAppsLimits struct {
+	// The total memory in MiB that all started process instances and running tasks can use
+	// +optional
+	TotalMemoryInMB *int64 `json:"totalMemoryInMB,omitempty"`
+
+	// The maximum memory in MiB a single process instance or task can use
+	// +optional
+	PerProcessMemoryInMB *int64 `json:"perProcessMemoryInMB,omitempty"`
+
+	// The total number of started process instances
+	// +optional
+	TotalInstances *int64 `json:"totalInstances,omitempty"`
+
+	// The maximum number of tasks that can run concurrently for a single app
+	// +optional
+	PerAppTasks *int64 `json:"perAppTasks,omitempty"`
+
+	// The total log rate limit in bytes per second. Not enforced
+	// +optional
+	LogRateLimitInBytesPerSecond *int64 `json:"logRateLimitInBytesPerSecond,omitempty"`
+}
+
+// QuotaServicesLimits is shared by CFOrgQuota and CFSpaceQuota. A nil limit means unlimited
+type QuotaServicesLimits struct {
+	// Whether paid service plans can be provisioned
+	// +optional
+	PaidServicesAllowed bool `json:"paidServicesAllowed"`
+
+	// The total number of service instances
+	// +optional
+	TotalServiceInstances *int64 `json:"totalServiceInstances,omitempty"`
+
+	// The total number of service keys. Not enforced
+	// +optional
+	TotalServiceKeys *int64 `json:"totalServiceKeys,omitempty"`
+}
+
+// QuotaRoutesLimits is shared by CFOrgQuota and CFSpaceQuota. A nil limit means unlimited
+type QuotaRoutesLimits struct {
+	// The total number of routes
+	// +optional
+	TotalRoutes *int64 `json:"totalRoutes,omitempty"`
+
+	// The total number of reserved ports. Not enforced
+	// +optional
+	TotalReservedPorts *int64 `json:"totalReservedPorts,omitempty"`
+}
+
+// QuotaDomainsLimits is only used by CFOrgQuota. A nil limit means unlimited
+type QuotaDomainsLimits struct {
+	// The total number of private domains. Not enforced
+	// +optional
+	TotalDomains *int64 `json:"totalDomains,omitempty"`
+}
+
+// CFOrgQuotaStatus defines the observed state of CFOrgQuota
+type CFOrgQuotaStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFOrgQuota is the Schema for the cforgquotas API. CFOrgQuotas live in the root namespace
+// and are assigned to orgs via CFOrg.Spec.QuotaRef
+type CFOrgQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFOrgQuotaSpec   `json:"spec,omitempty"`
+	Status CFOrgQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFOrgQuotaList contains a list of CFOrgQuota
+type CFOrgQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFOrgQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFOrgQuota{}, &CFOrgQuotaList{})
+}
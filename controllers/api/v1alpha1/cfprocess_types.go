@@ -85,6 +85,17 @@ type HealthCheckData struct {
 type CFProcessStatus struct {
 	// Conditions capture the current status of the Process
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// The last usage reported in a CFAppUsageEvent
+	// +optional
+	ReportedUsage *ProcessUsage `json:"reportedUsage,omitempty"`
+}
+
+// ProcessUsage is the running state of a CFProcess as reported in CFAppUsageEvents
+type ProcessUsage struct {
+	State     string `json:"state"`
+	Instances int    `json:"instances"`
+	MemoryMB  int64  `json:"memoryMB"`
 }
 
 //+kubebuilder:object:root=true
@@ -36,6 +36,10 @@ type CFServiceBindingSpec struct {
 	AppRef v1.LocalObjectReference `json:"appRef"`
 }
 
+// SyslogDrainHealthyConditionType is set on the bindings of service instances with a syslog drain URL, reporting
+// whether the logs of the bound app are forwarded to the drain
+const SyslogDrainHealthyConditionType = "SyslogDrainHealthy"
+
 // CFServiceBindingStatus defines the observed state of CFServiceBinding
 type CFServiceBindingStatus struct {
 	// A reference to the Secret containing the credentials.
@@ -41,6 +41,10 @@ type CFServiceInstanceSpec struct {
 
 	// Tags are used by apps to identify service instances
 	Tags []string `json:"tags,omitempty"`
+
+	// URL of a syslog drain the logs of bound apps are forwarded to. Only supported on user-provided instances
+	// +optional
+	SyslogDrainURL string `json:"syslogDrainURL,omitempty"`
 }
 
 // InstanceType defines the type of the Service Instance
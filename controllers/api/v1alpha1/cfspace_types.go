@@ -17,11 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	SpaceNameLabel = "cloudfoundry.org/space-name"
+	// OrgGUIDLabel labels the namespace of a space with the GUID of its org, i.e. the namespace of the CFSpace
+	OrgGUIDLabel = "cloudfoundry.org/org-guid"
 )
 
 // CFSpaceSpec defines the desired state of CFSpace
@@ -29,6 +32,14 @@ type CFSpaceSpec struct {
 	// The mutable, user-friendly name of the space. Unlike metadata.name, the user can change this field
 	// +kubebuilder:validation:Pattern="^[-\\w]+$"
 	DisplayName string `json:"displayName"`
+
+	// A reference to the CFSpaceQuota (in the org namespace) limiting the resources of the space. Empty means unlimited
+	// +optional
+	QuotaRef corev1.LocalObjectReference `json:"quotaRef,omitempty"`
+
+	// A reference to the CFIsolationSegment (in the root namespace) the space's workloads run on. Empty means the shared segment
+	// +optional
+	IsolationSegmentRef corev1.LocalObjectReference `json:"isolationSegmentRef,omitempty"`
 }
 
 // CFSpaceStatus defines the observed state of CFSpace
@@ -0,0 +1,70 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CFSpaceQuotaSpec defines the desired state of CFSpaceQuota
+type CFSpaceQuotaSpec struct {
+	// The mutable, user-friendly name of the quota. Unlike metadata.name, the user can change this field
+	DisplayName string `json:"displayName"`
+
+	// Limits applying to the apps of every space the quota is assigned to
+	// +optional
+	Apps QuotaAppsLimits `json:"apps"`
+
+	// Limits applying to the service instances of every space the quota is assigned to
+	// +optional
+	Services QuotaServicesLimits `json:"services"`
+
+	// Limits applying to the routes of every space the quota is assigned to
+	// +optional
+	Routes QuotaRoutesLimits `json:"routes"`
+}
+
+// CFSpaceQuotaStatus defines the observed state of CFSpaceQuota
+type CFSpaceQuotaStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFSpaceQuota is the Schema for the cfspacequotas API. CFSpaceQuotas live in the namespace of
+// the org owning them and are assigned to spaces of that org via CFSpace.Spec.QuotaRef
+type CFSpaceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFSpaceQuotaSpec   `json:"spec,omitempty"`
+	Status CFSpaceQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFSpaceQuotaList contains a list of CFSpaceQuota
+type CFSpaceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFSpaceQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFSpaceQuota{}, &CFSpaceQuotaList{})
+}
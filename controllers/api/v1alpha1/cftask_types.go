@@ -62,6 +62,9 @@ type CFTaskStatus struct {
 	DiskQuotaMB int64 `json:"diskQuotaMB"`
 	// +optional
 	DropletRef corev1.LocalObjectReference `json:"dropletRef"`
+	// The last usage state reported in a CFAppUsageEvent
+	// +optional
+	ReportedUsageState string `json:"reportedUsageState,omitempty"`
 }
 
 //+kubebuilder:object:root=true
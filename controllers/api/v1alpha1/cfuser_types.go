@@ -0,0 +1,64 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CFUserSpec defines the desired state of CFUser
+type CFUserSpec struct {
+	// The name of the user as it appears in the subjects of role bindings
+	Username string `json:"username"`
+
+	// The identity provider the user comes from
+	// +kubebuilder:default=kubernetes
+	// +optional
+	Origin string `json:"origin,omitempty"`
+}
+
+// CFUserStatus defines the observed state of CFUser
+type CFUserStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.spec.username`
+//+kubebuilder:printcolumn:name="Origin",type=string,JSONPath=`.spec.origin`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// CFUser is the Schema for the cfusers API. CFUsers live in the root namespace and record the users that have
+// been granted a CF role, have authenticated against the API, or have been created ahead of time by an admin
+type CFUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CFUserSpec   `json:"spec,omitempty"`
+	Status CFUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CFUserList contains a list of CFUser
+type CFUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CFUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CFUser{}, &CFUserList{})
+}
@@ -23,6 +23,10 @@ const (
 	PropagateRoleBindingAnnotation    = "cloudfoundry.org/propagate-cf-role"
 	PropagateServiceAccountAnnotation = "cloudfoundry.org/propagate-service-account"
 	PropagatedFromLabel               = "cloudfoundry.org/propagated-from"
+
+	RunningEnvVarGroupSecretName         = "cf-running-env-var-group"
+	StagingEnvVarGroupSecretName         = "cf-staging-env-var-group"
+	RestartOnEnvVarGroupChangeAnnotation = "korifi.cloudfoundry.org/restart-on-env-var-group-change"
 )
 
 type Lifecycle struct {
@@ -61,3 +65,14 @@ type Registry struct {
 type RequiredLocalObjectReference struct {
 	Name string `json:"name"`
 }
+
+// IsolationSegmentPlacement is used by AppWorkload and TaskWorkload to carry the scheduling
+// constraints of the isolation segment the workload's space is assigned to
+type IsolationSegmentPlacement struct {
+	// The GUID of the CFIsolationSegment
+	Name string `json:"name"`
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
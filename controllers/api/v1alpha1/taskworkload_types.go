@@ -43,6 +43,10 @@ type TaskWorkloadSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Env []corev1.EnvVar `json:"env"`
+
+	// The isolation segment the task should be scheduled on. Nil means the shared segment
+	// +kubebuilder:validation:Optional
+	IsolationSegment *IsolationSegmentPlacement `json:"isolationSegment,omitempty"`
 }
 
 // TaskWorkloadStatus defines the observed state of TaskWorkload
@@ -27,6 +27,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppUsageEventResource) DeepCopyInto(out *AppUsageEventResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppUsageEventResource.
+func (in *AppUsageEventResource) DeepCopy() *AppUsageEventResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AppUsageEventResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppWorkload) DeepCopyInto(out *AppWorkload) {
 	*out = *in
@@ -127,6 +142,11 @@ func (in *AppWorkloadSpec) DeepCopyInto(out *AppWorkloadSpec) {
 		copy(*out, *in)
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.IsolationSegment != nil {
+		in, out := &in.IsolationSegment, &out.IsolationSegment
+		*out = new(IsolationSegmentPlacement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppWorkloadSpec.
@@ -161,6 +181,52 @@ func (in *AppWorkloadStatus) DeepCopy() *AppWorkloadStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEventCrash) DeepCopyInto(out *AuditEventCrash) {
+	*out = *in
+	in.CrashTimestamp.DeepCopyInto(&out.CrashTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEventCrash.
+func (in *AuditEventCrash) DeepCopy() *AuditEventCrash {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEventCrash)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEventParticipant) DeepCopyInto(out *AuditEventParticipant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEventParticipant.
+func (in *AuditEventParticipant) DeepCopy() *AuditEventParticipant {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEventParticipant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEventRequest) DeepCopyInto(out *AuditEventRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEventRequest.
+func (in *AuditEventRequest) DeepCopy() *AuditEventRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEventRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildDropletStatus) DeepCopyInto(out *BuildDropletStatus) {
 	*out = *in
@@ -549,6 +615,216 @@ func (in *CFAppStatus) DeepCopy() *CFAppStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAppUsageEvent) DeepCopyInto(out *CFAppUsageEvent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAppUsageEvent.
+func (in *CFAppUsageEvent) DeepCopy() *CFAppUsageEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAppUsageEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFAppUsageEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAppUsageEventList) DeepCopyInto(out *CFAppUsageEventList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFAppUsageEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAppUsageEventList.
+func (in *CFAppUsageEventList) DeepCopy() *CFAppUsageEventList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAppUsageEventList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFAppUsageEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAppUsageEventSpec) DeepCopyInto(out *CFAppUsageEventSpec) {
+	*out = *in
+	out.App = in.App
+	if in.Process != nil {
+		in, out := &in.Process, &out.Process
+		*out = new(AppUsageEventResource)
+		**out = **in
+	}
+	if in.Task != nil {
+		in, out := &in.Task, &out.Task
+		*out = new(AppUsageEventResource)
+		**out = **in
+	}
+	out.Space = in.Space
+	out.Organization = in.Organization
+	if in.PreviousInstanceCount != nil {
+		in, out := &in.PreviousInstanceCount, &out.PreviousInstanceCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.PreviousMemoryInMBPerInstance != nil {
+		in, out := &in.PreviousMemoryInMBPerInstance, &out.PreviousMemoryInMBPerInstance
+		*out = new(int64)
+		**out = **in
+	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAppUsageEventSpec.
+func (in *CFAppUsageEventSpec) DeepCopy() *CFAppUsageEventSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAppUsageEventSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAppUsageEventStatus) DeepCopyInto(out *CFAppUsageEventStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAppUsageEventStatus.
+func (in *CFAppUsageEventStatus) DeepCopy() *CFAppUsageEventStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAppUsageEventStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAuditEvent) DeepCopyInto(out *CFAuditEvent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAuditEvent.
+func (in *CFAuditEvent) DeepCopy() *CFAuditEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAuditEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFAuditEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAuditEventList) DeepCopyInto(out *CFAuditEventList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFAuditEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAuditEventList.
+func (in *CFAuditEventList) DeepCopy() *CFAuditEventList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAuditEventList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFAuditEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAuditEventSpec) DeepCopyInto(out *CFAuditEventSpec) {
+	*out = *in
+	out.Actor = in.Actor
+	out.Target = in.Target
+	out.Request = in.Request
+	if in.Crash != nil {
+		in, out := &in.Crash, &out.Crash
+		*out = new(AuditEventCrash)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAuditEventSpec.
+func (in *CFAuditEventSpec) DeepCopy() *CFAuditEventSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAuditEventSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFAuditEventStatus) DeepCopyInto(out *CFAuditEventStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFAuditEventStatus.
+func (in *CFAuditEventStatus) DeepCopy() *CFAuditEventStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CFAuditEventStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CFBuild) DeepCopyInto(out *CFBuild) {
 	*out = *in
@@ -742,6 +1018,114 @@ func (in *CFDomainStatus) DeepCopy() *CFDomainStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFIsolationSegment) DeepCopyInto(out *CFIsolationSegment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFIsolationSegment.
+func (in *CFIsolationSegment) DeepCopy() *CFIsolationSegment {
+	if in == nil {
+		return nil
+	}
+	out := new(CFIsolationSegment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFIsolationSegment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFIsolationSegmentList) DeepCopyInto(out *CFIsolationSegmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFIsolationSegment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFIsolationSegmentList.
+func (in *CFIsolationSegmentList) DeepCopy() *CFIsolationSegmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFIsolationSegmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFIsolationSegmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFIsolationSegmentSpec) DeepCopyInto(out *CFIsolationSegmentSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFIsolationSegmentSpec.
+func (in *CFIsolationSegmentSpec) DeepCopy() *CFIsolationSegmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CFIsolationSegmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFIsolationSegmentStatus) DeepCopyInto(out *CFIsolationSegmentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFIsolationSegmentStatus.
+func (in *CFIsolationSegmentStatus) DeepCopy() *CFIsolationSegmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CFIsolationSegmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CFOrg) DeepCopyInto(out *CFOrg) {
 	*out = *in
@@ -783,27 +1167,121 @@ func (in *CFOrgList) DeepCopyInto(out *CFOrgList) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgList.
-func (in *CFOrgList) DeepCopy() *CFOrgList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgList.
+func (in *CFOrgList) DeepCopy() *CFOrgList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFOrgList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFOrgList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFOrgQuota) DeepCopyInto(out *CFOrgQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgQuota.
+func (in *CFOrgQuota) DeepCopy() *CFOrgQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(CFOrgQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFOrgQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFOrgQuotaList) DeepCopyInto(out *CFOrgQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFOrgQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgQuotaList.
+func (in *CFOrgQuotaList) DeepCopy() *CFOrgQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFOrgQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFOrgQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFOrgQuotaSpec) DeepCopyInto(out *CFOrgQuotaSpec) {
+	*out = *in
+	in.Apps.DeepCopyInto(&out.Apps)
+	in.Services.DeepCopyInto(&out.Services)
+	in.Routes.DeepCopyInto(&out.Routes)
+	in.Domains.DeepCopyInto(&out.Domains)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgQuotaSpec.
+func (in *CFOrgQuotaSpec) DeepCopy() *CFOrgQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CFOrgList)
+	out := new(CFOrgQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CFOrgList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFOrgQuotaStatus) DeepCopyInto(out *CFOrgQuotaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgQuotaStatus.
+func (in *CFOrgQuotaStatus) DeepCopy() *CFOrgQuotaStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(CFOrgQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CFOrgSpec) DeepCopyInto(out *CFOrgSpec) {
 	*out = *in
+	out.QuotaRef = in.QuotaRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFOrgSpec.
@@ -1047,6 +1525,11 @@ func (in *CFProcessStatus) DeepCopyInto(out *CFProcessStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReportedUsage != nil {
+		in, out := &in.ReportedUsage, &out.ReportedUsage
+		*out = new(ProcessUsage)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFProcessStatus.
@@ -1431,9 +1914,103 @@ func (in *CFSpaceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFSpaceQuota) DeepCopyInto(out *CFSpaceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFSpaceQuota.
+func (in *CFSpaceQuota) DeepCopy() *CFSpaceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(CFSpaceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFSpaceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFSpaceQuotaList) DeepCopyInto(out *CFSpaceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFSpaceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFSpaceQuotaList.
+func (in *CFSpaceQuotaList) DeepCopy() *CFSpaceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFSpaceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFSpaceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFSpaceQuotaSpec) DeepCopyInto(out *CFSpaceQuotaSpec) {
+	*out = *in
+	in.Apps.DeepCopyInto(&out.Apps)
+	in.Services.DeepCopyInto(&out.Services)
+	in.Routes.DeepCopyInto(&out.Routes)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFSpaceQuotaSpec.
+func (in *CFSpaceQuotaSpec) DeepCopy() *CFSpaceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CFSpaceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFSpaceQuotaStatus) DeepCopyInto(out *CFSpaceQuotaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFSpaceQuotaStatus.
+func (in *CFSpaceQuotaStatus) DeepCopy() *CFSpaceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CFSpaceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CFSpaceSpec) DeepCopyInto(out *CFSpaceSpec) {
 	*out = *in
+	out.QuotaRef = in.QuotaRef
+	out.IsolationSegmentRef = in.IsolationSegmentRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFSpaceSpec.
@@ -1566,6 +2143,95 @@ func (in *CFTaskStatus) DeepCopy() *CFTaskStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFUser) DeepCopyInto(out *CFUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFUser.
+func (in *CFUser) DeepCopy() *CFUser {
+	if in == nil {
+		return nil
+	}
+	out := new(CFUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFUserList) DeepCopyInto(out *CFUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CFUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFUserList.
+func (in *CFUserList) DeepCopy() *CFUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(CFUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CFUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFUserSpec) DeepCopyInto(out *CFUserSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFUserSpec.
+func (in *CFUserSpec) DeepCopy() *CFUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CFUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CFUserStatus) DeepCopyInto(out *CFUserStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CFUserStatus.
+func (in *CFUserStatus) DeepCopy() *CFUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CFUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Destination) DeepCopyInto(out *Destination) {
 	*out = *in
@@ -1613,6 +2279,35 @@ func (in *HealthCheckData) DeepCopy() *HealthCheckData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IsolationSegmentPlacement) DeepCopyInto(out *IsolationSegmentPlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IsolationSegmentPlacement.
+func (in *IsolationSegmentPlacement) DeepCopy() *IsolationSegmentPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(IsolationSegmentPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Lifecycle) DeepCopyInto(out *Lifecycle) {
 	*out = *in
@@ -1680,6 +2375,131 @@ func (in *ProcessType) DeepCopy() *ProcessType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessUsage) DeepCopyInto(out *ProcessUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessUsage.
+func (in *ProcessUsage) DeepCopy() *ProcessUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaAppsLimits) DeepCopyInto(out *QuotaAppsLimits) {
+	*out = *in
+	if in.TotalMemoryInMB != nil {
+		in, out := &in.TotalMemoryInMB, &out.TotalMemoryInMB
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PerProcessMemoryInMB != nil {
+		in, out := &in.PerProcessMemoryInMB, &out.PerProcessMemoryInMB
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TotalInstances != nil {
+		in, out := &in.TotalInstances, &out.TotalInstances
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PerAppTasks != nil {
+		in, out := &in.PerAppTasks, &out.PerAppTasks
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LogRateLimitInBytesPerSecond != nil {
+		in, out := &in.LogRateLimitInBytesPerSecond, &out.LogRateLimitInBytesPerSecond
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaAppsLimits.
+func (in *QuotaAppsLimits) DeepCopy() *QuotaAppsLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaAppsLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaDomainsLimits) DeepCopyInto(out *QuotaDomainsLimits) {
+	*out = *in
+	if in.TotalDomains != nil {
+		in, out := &in.TotalDomains, &out.TotalDomains
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaDomainsLimits.
+func (in *QuotaDomainsLimits) DeepCopy() *QuotaDomainsLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaDomainsLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRoutesLimits) DeepCopyInto(out *QuotaRoutesLimits) {
+	*out = *in
+	if in.TotalRoutes != nil {
+		in, out := &in.TotalRoutes, &out.TotalRoutes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TotalReservedPorts != nil {
+		in, out := &in.TotalReservedPorts, &out.TotalReservedPorts
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRoutesLimits.
+func (in *QuotaRoutesLimits) DeepCopy() *QuotaRoutesLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRoutesLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaServicesLimits) DeepCopyInto(out *QuotaServicesLimits) {
+	*out = *in
+	if in.TotalServiceInstances != nil {
+		in, out := &in.TotalServiceInstances, &out.TotalServiceInstances
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TotalServiceKeys != nil {
+		in, out := &in.TotalServiceKeys, &out.TotalServiceKeys
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaServicesLimits.
+func (in *QuotaServicesLimits) DeepCopy() *QuotaServicesLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaServicesLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Registry) DeepCopyInto(out *Registry) {
 	*out = *in
@@ -1795,6 +2615,11 @@ func (in *TaskWorkloadSpec) DeepCopyInto(out *TaskWorkloadSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IsolationSegment != nil {
+		in, out := &in.IsolationSegment, &out.IsolationSegment
+		*out = new(IsolationSegmentPlacement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskWorkloadSpec.
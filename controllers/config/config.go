@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/korifi/tools"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 )
 
 type ControllerConfig struct {
@@ -12,10 +13,12 @@ type ControllerConfig struct {
 	CFRootNamespace             string            `yaml:"cfRootNamespace"`
 	PackageRegistrySecretName   string            `yaml:"packageRegistrySecretName"`
 	TaskTTL                     string            `yaml:"taskTTL"`
+	AuditEventRetention         string            `yaml:"auditEventRetention"`
 	WorkloadsTLSSecretName      string            `yaml:"workloads_tls_secret_name"`
 	WorkloadsTLSSecretNamespace string            `yaml:"workloads_tls_secret_namespace"`
 	BuilderName                 string            `yaml:"builderName"`
 	RunnerName                  string            `yaml:"runnerName"`
+	Tracing                     tracing.Config    `yaml:"tracing"`
 }
 
 type CFProcessDefaults struct {
@@ -25,8 +28,9 @@ type CFProcessDefaults struct {
 }
 
 const (
-	defaultTaskTTL       = 30 * 24 * time.Hour
-	defaultTimeout int64 = 60
+	defaultTaskTTL             = 30 * 24 * time.Hour
+	defaultAuditEventTTL       = 31 * 24 * time.Hour
+	defaultTimeout       int64 = 60
 )
 
 func LoadFromPath(path string) (*ControllerConfig, error) {
@@ -57,3 +61,11 @@ func (c ControllerConfig) ParseTaskTTL() (time.Duration, error) {
 
 	return tools.ParseDuration(c.TaskTTL)
 }
+
+func (c ControllerConfig) ParseAuditEventRetention() (time.Duration, error) {
+	if c.AuditEventRetention == "" {
+		return defaultAuditEventTTL, nil
+	}
+
+	return tools.ParseDuration(c.AuditEventRetention)
+}
@@ -131,3 +131,49 @@ var _ = Describe("ParseTaskTTL", func() {
 		})
 	})
 })
+
+var _ = Describe("ParseAuditEventRetention", func() {
+	var (
+		retentionString string
+		retention       time.Duration
+		parseErr        error
+	)
+
+	BeforeEach(func() {
+		retentionString = ""
+	})
+
+	JustBeforeEach(func() {
+		cfg := config.ControllerConfig{
+			AuditEventRetention: retentionString,
+		}
+
+		retention, parseErr = cfg.ParseAuditEventRetention()
+	})
+
+	It("return 31 days by default", func() {
+		Expect(parseErr).NotTo(HaveOccurred())
+		Expect(retention).To(Equal(31 * 24 * time.Hour))
+	})
+
+	When("entering something parseable by tools.ParseDuration", func() {
+		BeforeEach(func() {
+			retentionString = "7d"
+		})
+
+		It("parses ok", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(retention).To(Equal(7 * 24 * time.Hour))
+		})
+	})
+
+	When("entering something that cannot be parsed", func() {
+		BeforeEach(func() {
+			retentionString = "foreva"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(HaveOccurred())
+		})
+	})
+})
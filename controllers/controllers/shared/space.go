@@ -0,0 +1,60 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FindSpace returns the CFSpace backing spaceNamespace, or nil when there is none. CFSpaces live in the namespace of
+// their org, which the space controller records in the OrgGUIDLabel of the space namespace. Spaces whose namespace
+// has not been labelled yet are looked up among all spaces
+func FindSpace(ctx context.Context, k8sClient client.Client, spaceNamespace string) (*korifiv1alpha1.CFSpace, error) {
+	namespace := new(corev1.Namespace)
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: spaceNamespace}, namespace)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %q: %w", spaceNamespace, err)
+	}
+
+	orgGUID := namespace.Labels[korifiv1alpha1.OrgGUIDLabel]
+	if orgGUID == "" {
+		// namespaces of spaces created before the label was introduced are only labelled once their space is
+		// reconciled again
+		return findSpaceByName(ctx, k8sClient, spaceNamespace)
+	}
+
+	space := new(korifiv1alpha1.CFSpace)
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: orgGUID, Name: spaceNamespace}, space)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get space %s/%s: %w", orgGUID, spaceNamespace, err)
+	}
+
+	return space, nil
+}
+
+func findSpaceByName(ctx context.Context, k8sClient client.Client, spaceNamespace string) (*korifiv1alpha1.CFSpace, error) {
+	spaces := &korifiv1alpha1.CFSpaceList{}
+	if err := k8sClient.List(ctx, spaces); err != nil {
+		return nil, fmt.Errorf("failed to list spaces: %w", err)
+	}
+
+	for i := range spaces.Items {
+		if spaces.Items[i].Name == spaceNamespace {
+			return &spaces.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfappusageevents,verbs=create
+
+// recordAppUsageEvent stores a CFAppUsageEvent for an app in the root namespace, filling in the app, space and
+// org details of the event spec. The event is named after eventKey, which must identify the usage transition, so that
+// recording a transition again (e.g. when updating the status of the reporter failed) does not duplicate the event
+func recordAppUsageEvent(ctx context.Context, kClient client.Client, rootNamespace string, cfApp *korifiv1alpha1.CFApp, eventKey string, spec korifiv1alpha1.CFAppUsageEventSpec) error {
+	space, err := shared.FindSpace(ctx, kClient, cfApp.Namespace)
+	if err != nil {
+		return err
+	}
+
+	spec.App = korifiv1alpha1.AppUsageEventResource{GUID: cfApp.Name, Name: cfApp.Spec.DisplayName}
+	spec.Space = korifiv1alpha1.AppUsageEventResource{GUID: cfApp.Namespace}
+	if space != nil {
+		spec.Space.Name = space.Spec.DisplayName
+		spec.Organization = korifiv1alpha1.AppUsageEventResource{GUID: space.Namespace}
+	}
+	spec.Timestamp = metav1.NewMicroTime(time.Now())
+
+	err = kClient.Create(ctx, &korifiv1alpha1.CFAppUsageEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uuid.NewSHA1(uuid.NameSpaceOID, []byte(eventKey)).String(),
+			Namespace: rootNamespace,
+		},
+		Spec: spec,
+	})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create app usage event: %w", err)
+	}
+
+	return nil
+}
@@ -3,10 +3,13 @@ package workloads
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
 	"code.cloudfoundry.org/korifi/tools/k8s"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -40,14 +43,16 @@ type CFAppReconciler struct {
 	k8sClient           client.Client
 	scheme              *runtime.Scheme
 	vcapServicesBuilder VCAPServicesSecretBuilder
+	rootNamespace       string
 }
 
-func NewCFAppReconciler(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger, vcapServicesBuilder VCAPServicesSecretBuilder) *k8s.PatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp] {
+func NewCFAppReconciler(k8sClient client.Client, scheme *runtime.Scheme, log logr.Logger, vcapServicesBuilder VCAPServicesSecretBuilder, rootNamespace string) *k8s.PatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp] {
 	appReconciler := CFAppReconciler{
 		log:                 log,
 		k8sClient:           k8sClient,
 		scheme:              scheme,
 		vcapServicesBuilder: vcapServicesBuilder,
+		rootNamespace:       rootNamespace,
 	}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFApp, *korifiv1alpha1.CFApp](log, k8sClient, &appReconciler)
 }
@@ -76,6 +81,12 @@ func (r *CFAppReconciler) ReconcileResource(ctx context.Context, cfApp *korifiv1
 		return ctrl.Result{}, err
 	}
 
+	err = r.reconcileRunningEnvVarGroup(ctx, cfApp)
+	if err != nil {
+		log.Error(err, "unable to snapshot the running env var group")
+		return ctrl.Result{}, err
+	}
+
 	if cfApp.Status.ObservedDesiredState != cfApp.Spec.DesiredState {
 		cfApp.Status.ObservedDesiredState = cfApp.Spec.DesiredState
 	}
@@ -122,6 +133,28 @@ func (r *CFAppReconciler) ReconcileResource(ctx context.Context, cfApp *korifiv1
 	return ctrl.Result{}, nil
 }
 
+// reconcileRunningEnvVarGroup refreshes the hash of the running env var group the app runs with. Changing the hash of a
+// running app rolls its processes, so that only happens when the app opts in. Other apps pick up changes to the group
+// the next time they are started.
+func (r *CFAppReconciler) reconcileRunningEnvVarGroup(ctx context.Context, cfApp *korifiv1alpha1.CFApp) error {
+	isRunning := cfApp.Spec.DesiredState == korifiv1alpha1.StartedState && cfApp.Status.ObservedDesiredState == korifiv1alpha1.StartedState
+	if isRunning && !restartsOnEnvVarGroupChange(cfApp) {
+		return nil
+	}
+
+	group, err := env.GetEnvVarGroup(ctx, r.k8sClient, r.rootNamespace, korifiv1alpha1.RunningEnvVarGroupSecretName)
+	if err != nil {
+		return err
+	}
+
+	cfApp.Status.RunningEnvVarGroupHash = env.HashEnvVarGroup(group)
+	return nil
+}
+
+func restartsOnEnvVarGroupChange(cfApp *korifiv1alpha1.CFApp) bool {
+	return cfApp.Annotations[korifiv1alpha1.RestartOnEnvVarGroupChangeAnnotation] == "true"
+}
+
 func (r *CFAppReconciler) getDroplet(ctx context.Context, log logr.Logger, cfApp *korifiv1alpha1.CFApp) (*korifiv1alpha1.BuildDropletStatus, error) {
 	log = log.WithName("getDroplet").WithValues("dropletName", cfApp.Spec.CurrentDropletRef.Name)
 
@@ -210,6 +243,8 @@ func (r *CFAppReconciler) createCFProcess(ctx context.Context, log logr.Logger,
 		return err
 	}
 
+	tracing.InjectIntoObject(ctx, desiredCFProcess)
+
 	return r.k8sClient.Create(ctx, desiredCFProcess)
 }
 
@@ -257,6 +292,11 @@ func (r *CFAppReconciler) finalizeCFApp(ctx context.Context, log logr.Logger, cf
 		return err
 	}
 
+	err = r.finalizeUsage(ctx, log, cfApp)
+	if err != nil {
+		return err
+	}
+
 	if controllerutil.RemoveFinalizer(cfApp, cfAppFinalizerName) {
 		log.Info("finalizer removed")
 	}
@@ -297,6 +337,41 @@ func (r *CFAppReconciler) finalizeCFServiceBindings(ctx context.Context, log log
 	return nil
 }
 
+// finalizeUsage records a STOPPED app usage event for every process of the app that was last reported as started, as
+// the processes are garbage collected along with the app and never report that they stopped
+func (r *CFAppReconciler) finalizeUsage(ctx context.Context, log logr.Logger, cfApp *korifiv1alpha1.CFApp) error {
+	processList := korifiv1alpha1.CFProcessList{}
+	err := r.k8sClient.List(ctx, &processList, client.InNamespace(cfApp.Namespace), client.MatchingLabels{korifiv1alpha1.CFAppGUIDLabelKey: cfApp.Name})
+	if err != nil {
+		log.Error(err, "failed to list app processes")
+		return err
+	}
+
+	for i := range processList.Items {
+		cfProcess := &processList.Items[i]
+		reportedUsage := cfProcess.Status.ReportedUsage
+		if reportedUsage == nil || reportedUsage.State != korifiv1alpha1.AppUsageStateStarted {
+			continue
+		}
+
+		err = recordAppUsageEvent(ctx, r.k8sClient, r.rootNamespace, cfApp, fmt.Sprintf("%s/deleted", cfProcess.UID), korifiv1alpha1.CFAppUsageEventSpec{
+			State:                         korifiv1alpha1.AppUsageStateStopped,
+			PreviousState:                 reportedUsage.State,
+			Process:                       &korifiv1alpha1.AppUsageEventResource{GUID: cfProcess.Name, Name: cfProcess.Spec.ProcessType},
+			InstanceCount:                 reportedUsage.Instances,
+			MemoryInMBPerInstance:         reportedUsage.MemoryMB,
+			PreviousInstanceCount:         &reportedUsage.Instances,
+			PreviousMemoryInMBPerInstance: &reportedUsage.MemoryMB,
+		})
+		if err != nil {
+			log.Error(err, "failed to record app usage event", "processName", cfProcess.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *CFAppReconciler) updateRouteDestinations(ctx context.Context, log logr.Logger, cfAppGUID string, cfRoutes []korifiv1alpha1.CFRoute) error {
 	log = log.WithName("updateRouteDestinations")
 
@@ -341,7 +416,31 @@ func (r *CFAppReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&korifiv1alpha1.CFApp{}).
 		Watches(&source.Kind{Type: &korifiv1alpha1.CFBuild{}}, handler.EnqueueRequestsFromMapFunc(buildToApp)).
-		Watches(&source.Kind{Type: &korifiv1alpha1.CFServiceBinding{}}, handler.EnqueueRequestsFromMapFunc(serviceBindingToApp))
+		Watches(&source.Kind{Type: &korifiv1alpha1.CFServiceBinding{}}, handler.EnqueueRequestsFromMapFunc(serviceBindingToApp)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.runningEnvVarGroupToApps))
+}
+
+// runningEnvVarGroupToApps enqueues the apps that restart on changes to the running env var group
+func (r *CFAppReconciler) runningEnvVarGroupToApps(o client.Object) []reconcile.Request {
+	if o.GetNamespace() != r.rootNamespace || o.GetName() != korifiv1alpha1.RunningEnvVarGroupSecretName {
+		return nil
+	}
+
+	appList := &korifiv1alpha1.CFAppList{}
+	err := r.k8sClient.List(context.Background(), appList)
+	if err != nil {
+		r.log.Error(err, "Error when trying to list CFApps")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range appList.Items {
+		if restartsOnEnvVarGroupChange(&appList.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&appList.Items[i])})
+		}
+	}
+
+	return requests
 }
 
 func buildToApp(o client.Object) []reconcile.Request {
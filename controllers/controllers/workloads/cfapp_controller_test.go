@@ -6,6 +6,7 @@ import (
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/controllers/workloads"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
 	. "code.cloudfoundry.org/korifi/controllers/controllers/workloads/testutils"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
@@ -630,6 +631,106 @@ var _ = Describe("CFAppReconciler Integration Tests", func() {
 			})
 		})
 	})
+
+	When("the running env var group is set", func() {
+		var (
+			ctx                context.Context
+			cfAppGUID          string
+			cfApp              *korifiv1alpha1.CFApp
+			runningGroupSecret *corev1.Secret
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+
+			runningGroupSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      korifiv1alpha1.RunningEnvVarGroupSecretName,
+					Namespace: cfRootNamespace,
+				},
+				StringData: map[string]string{"https_proxy": "proxy.example.com"},
+			}
+			Expect(k8sClient.Create(ctx, runningGroupSecret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.Background(), runningGroupSecret)).To(Succeed())
+			})
+
+			cfAppGUID = GenerateGUID()
+			cfApp = BuildCFAppCRObject(cfAppGUID, namespaceGUID)
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Create(ctx, cfApp)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				createdCFApp, err := getApp(namespaceGUID, cfAppGUID)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(createdCFApp.Status.RunningEnvVarGroupHash).To(Equal(env.HashEnvVarGroup(map[string]string{"https_proxy": "proxy.example.com"})))
+			}).Should(Succeed())
+		})
+
+		updateGroup := func() {
+			Expect(k8s.Patch(ctx, k8sClient, runningGroupSecret, func() {
+				runningGroupSecret.StringData = map[string]string{"https_proxy": "new-proxy.example.com"}
+			})).To(Succeed())
+		}
+
+		When("the app is started", func() {
+			BeforeEach(func() {
+				cfApp.Spec.DesiredState = korifiv1alpha1.StartedState
+			})
+
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					createdCFApp, err := getApp(namespaceGUID, cfAppGUID)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(createdCFApp.Status.ObservedDesiredState).To(Equal(korifiv1alpha1.StartedState))
+				}).Should(Succeed())
+
+				updateGroup()
+			})
+
+			It("keeps the snapshot the app was started with", func() {
+				Consistently(func(g Gomega) {
+					createdCFApp, err := getApp(namespaceGUID, cfAppGUID)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(createdCFApp.Status.RunningEnvVarGroupHash).To(Equal(env.HashEnvVarGroup(map[string]string{"https_proxy": "proxy.example.com"})))
+				}, "1s").Should(Succeed())
+			})
+
+			When("the app opts in to restarts on env var group changes", func() {
+				BeforeEach(func() {
+					cfApp.Annotations[korifiv1alpha1.RestartOnEnvVarGroupChangeAnnotation] = "true"
+				})
+
+				It("refreshes the snapshot", func() {
+					Eventually(func(g Gomega) {
+						createdCFApp, err := getApp(namespaceGUID, cfAppGUID)
+						g.Expect(err).NotTo(HaveOccurred())
+						g.Expect(createdCFApp.Status.RunningEnvVarGroupHash).To(Equal(env.HashEnvVarGroup(map[string]string{"https_proxy": "new-proxy.example.com"})))
+					}).Should(Succeed())
+				})
+			})
+		})
+
+		When("the app is stopped", func() {
+			JustBeforeEach(func() {
+				updateGroup()
+
+				Expect(k8s.Patch(ctx, k8sClient, cfApp, func() {
+					cfApp.Spec.DesiredState = korifiv1alpha1.StartedState
+				})).To(Succeed())
+			})
+
+			It("refreshes the snapshot when the app is started", func() {
+				Eventually(func(g Gomega) {
+					createdCFApp, err := getApp(namespaceGUID, cfAppGUID)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(createdCFApp.Status.RunningEnvVarGroupHash).To(Equal(env.HashEnvVarGroup(map[string]string{"https_proxy": "new-proxy.example.com"})))
+				}).Should(Succeed())
+			})
+		})
+	})
 })
 
 func getApp(nsGUID, appGUID string) (*korifiv1alpha1.CFApp, error) {
@@ -0,0 +1,57 @@
+package workloads
+
+import (
+	"context"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/tools/k8s"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CFAuditEventReconciler deletes CFAuditEvents once their retention period is over
+type CFAuditEventReconciler struct {
+	k8sClient client.Client
+	logger    logr.Logger
+	retention time.Duration
+}
+
+func NewCFAuditEventReconciler(
+	client client.Client,
+	logger logr.Logger,
+	retention time.Duration,
+) *k8s.PatchingReconciler[korifiv1alpha1.CFAuditEvent, *korifiv1alpha1.CFAuditEvent] {
+	auditEventReconciler := CFAuditEventReconciler{
+		k8sClient: client,
+		logger:    logger,
+		retention: retention,
+	}
+	return k8s.NewPatchingReconciler[korifiv1alpha1.CFAuditEvent, *korifiv1alpha1.CFAuditEvent](logger, client, &auditEventReconciler)
+}
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents/status,verbs=get;update;patch
+
+func (r *CFAuditEventReconciler) ReconcileResource(ctx context.Context, cfAuditEvent *korifiv1alpha1.CFAuditEvent) (ctrl.Result, error) {
+	expiresAt := cfAuditEvent.CreationTimestamp.Add(r.retention)
+	if time.Now().Before(expiresAt) {
+		return ctrl.Result{RequeueAfter: time.Until(expiresAt)}, nil
+	}
+
+	r.logger.Info("deleting-expired-audit-event", "namespace", cfAuditEvent.Namespace, "name", cfAuditEvent.Name)
+	err := r.k8sClient.Delete(ctx, cfAuditEvent)
+	if err != nil {
+		r.logger.Error(err, "error-deleting-audit-event")
+	}
+
+	return ctrl.Result{}, client.IgnoreNotFound(err)
+}
+
+func (r *CFAuditEventReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builder {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&korifiv1alpha1.CFAuditEvent{})
+}
@@ -24,7 +24,9 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
 	"code.cloudfoundry.org/korifi/tools/k8s"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -123,6 +125,8 @@ func (r *CFBuildReconciler) ReconcileResource(ctx context.Context, cfBuild *kori
 			Reason:  "BuildWorkload",
 			Message: fmt.Sprintf("%s: %s", workloadSucceededStatus.Reason, workloadSucceededStatus.Message),
 		})
+
+		observeStagingDuration(outcomeFailed, workloadSucceededStatus.LastTransitionTime.Sub(cfBuild.CreationTimestamp.Time))
 	case metav1.ConditionTrue:
 		meta.SetStatusCondition(&cfBuild.Status.Conditions, metav1.Condition{
 			Type:    korifiv1alpha1.StagingConditionType,
@@ -139,6 +143,8 @@ func (r *CFBuildReconciler) ReconcileResource(ctx context.Context, cfBuild *kori
 		})
 
 		cfBuild.Status.Droplet = buildWorkload.Status.Droplet
+
+		observeStagingDuration(outcomeSucceeded, workloadSucceededStatus.LastTransitionTime.Sub(cfBuild.CreationTimestamp.Time))
 	default:
 		return ctrl.Result{}, nil
 	}
@@ -178,12 +184,18 @@ func (r *CFBuildReconciler) createBuildWorkload(ctx context.Context, cfBuild *ko
 	}
 	desiredWorkload.Spec.Services = buildServices
 
-	imageEnvironment, err := r.envBuilder.BuildEnv(ctx, cfApp)
+	imageEnvironment, err := r.envBuilder.BuildAppEnv(ctx, cfApp)
 	if err != nil {
 		r.log.Error(err, "failed building environment")
 		return fmt.Errorf("prepareEnvironment: %w", err)
 	}
-	desiredWorkload.Spec.Env = imageEnvironment
+
+	stagingEnvVarGroup, err := env.GetEnvVarGroup(ctx, r.k8sClient, r.controllerConfig.CFRootNamespace, korifiv1alpha1.StagingEnvVarGroupSecretName)
+	if err != nil {
+		r.log.Error(err, "failed getting the staging env var group")
+		return fmt.Errorf("prepareEnvironment: %w", err)
+	}
+	desiredWorkload.Spec.Env = env.MergeEnvVarGroup(imageEnvironment, stagingEnvVarGroup)
 
 	err = controllerutil.SetOwnerReference(cfBuild, &desiredWorkload, r.scheme)
 	if err != nil {
@@ -238,6 +250,8 @@ func (r *CFBuildReconciler) createBuildWorkloadIfNotExists(ctx context.Context,
 	err := r.k8sClient.Get(ctx, client.ObjectKeyFromObject(&desiredWorkload), &foundWorkload)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			// let the builder continue the trace of the build
+			tracing.InjectIntoObject(ctx, &desiredWorkload)
 			err = r.k8sClient.Create(ctx, &desiredWorkload)
 			if err != nil {
 				r.log.Error(err, "Error when creating BuildWorkload")
@@ -163,6 +163,38 @@ var _ = Describe("CFBuildReconciler Integration Tests", func() {
 			})
 		})
 
+		When("the staging env var group is set", func() {
+			BeforeEach(func() {
+				stagingGroupSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      korifiv1alpha1.StagingEnvVarGroupSecretName,
+						Namespace: cfRootNamespace,
+					},
+					StringData: map[string]string{
+						"a_key":       "group-val",
+						"https_proxy": "proxy.example.com",
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), stagingGroupSecret)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), stagingGroupSecret)).To(Succeed())
+				})
+			})
+
+			It("adds the group vars that the app does not set itself to the BuildWorkload env", func() {
+				eventuallyBuildWorkloadShould(func(workload *korifiv1alpha1.BuildWorkload, g Gomega) {
+					g.Expect(workload.Spec.Env).To(HaveLen(4))
+					g.Expect(workload.Spec.Env).To(ContainElements(
+						corev1.EnvVar{Name: "https_proxy", Value: "proxy.example.com"},
+						MatchFields(IgnoreExtras, Fields{
+							"Name":      Equal("a_key"),
+							"ValueFrom": Not(BeNil()),
+						}),
+					))
+				})
+			})
+		})
+
 		When("BuildWorkload with CFBuild GUID doesn't exist", func() {
 			It("creates a BuildWorkload owned by the CFBuild", func() {
 				lookupKey := types.NamespacedName{Name: cfBuildGUID, Namespace: namespaceGUID}
@@ -27,6 +27,7 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/config"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+	"code.cloudfoundry.org/korifi/controllers/controllers/workloads/env"
 	"code.cloudfoundry.org/korifi/tools/k8s"
 
 	"github.com/go-logr/logr"
@@ -41,12 +42,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 type EnvBuilder interface {
 	BuildEnv(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error)
+	BuildAppEnv(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error)
 }
 
 // CFProcessReconciler reconciles a CFProcess object
@@ -73,6 +76,8 @@ func NewCFProcessReconciler(
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfprocesses/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=appworkloads,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfisolationsegments,verbs=get;list;watch
 
 func (r *CFProcessReconciler) ReconcileResource(ctx context.Context, cfProcess *korifiv1alpha1.CFProcess) (ctrl.Result, error) {
 	cfApp := new(korifiv1alpha1.CFApp)
@@ -104,9 +109,62 @@ func (r *CFProcessReconciler) ReconcileResource(ctx context.Context, cfProcess *
 		return ctrl.Result{}, err
 	}
 
+	err = r.reportUsage(ctx, cfApp, cfProcess)
+	if err != nil {
+		r.log.Error(err, fmt.Sprintf("Error when trying to report usage for CFProcess %s/%s", cfProcess.Namespace, cfProcess.Name))
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// reportUsage records an app usage event when the app has been started or stopped, or when a started process
+// has been scaled, since the last reported usage
+func (r *CFProcessReconciler) reportUsage(ctx context.Context, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess) error {
+	currentUsage := korifiv1alpha1.ProcessUsage{
+		State:    korifiv1alpha1.AppUsageStateStopped,
+		MemoryMB: cfProcess.Spec.MemoryMB,
+	}
+	if cfApp.Status.ObservedDesiredState == korifiv1alpha1.StartedState {
+		currentUsage.State = korifiv1alpha1.AppUsageStateStarted
+	}
+	if cfProcess.Spec.DesiredInstances != nil {
+		currentUsage.Instances = *cfProcess.Spec.DesiredInstances
+	}
+
+	previousUsage := cfProcess.Status.ReportedUsage
+	if previousUsage != nil && *previousUsage == currentUsage {
+		return nil
+	}
+
+	wasStopped := previousUsage == nil || previousUsage.State == korifiv1alpha1.AppUsageStateStopped
+	if !wasStopped || currentUsage.State == korifiv1alpha1.AppUsageStateStarted {
+		spec := korifiv1alpha1.CFAppUsageEventSpec{
+			State:                 currentUsage.State,
+			PreviousState:         korifiv1alpha1.AppUsageStateStopped,
+			Process:               &korifiv1alpha1.AppUsageEventResource{GUID: cfProcess.Name, Name: cfProcess.Spec.ProcessType},
+			InstanceCount:         currentUsage.Instances,
+			MemoryInMBPerInstance: currentUsage.MemoryMB,
+		}
+		if previousUsage != nil {
+			spec.PreviousState = previousUsage.State
+			spec.PreviousInstanceCount = &previousUsage.Instances
+			spec.PreviousMemoryInMBPerInstance = &previousUsage.MemoryMB
+		}
+
+		// the usage only changes along with the generation of the app (start and stop) or of the process (scale)
+		eventKey := fmt.Sprintf("%s/%d/%d/%s", cfProcess.UID, cfApp.Generation, cfProcess.Generation, currentUsage.State)
+		err := recordAppUsageEvent(ctx, r.k8sClient, r.controllerConfig.CFRootNamespace, cfApp, eventKey, spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfProcess.Status.ReportedUsage = &currentUsage
+
+	return nil
+}
+
 func needsAppWorkload(cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess) bool {
 	if cfApp.Spec.DesiredState != korifiv1alpha1.StartedState {
 		return false
@@ -142,6 +200,18 @@ func (r *CFProcessReconciler) createOrPatchAppWorkload(ctx context.Context, cfAp
 		return err
 	}
 
+	keepEnv, err := r.runningEnvVarGroupChanged(ctx, cfApp)
+	if err != nil {
+		r.log.Error(err, fmt.Sprintf("Error when trying to fetch the running env var group for CFApp %s/%s", cfProcess.Namespace, cfApp.Spec.DisplayName))
+		return err
+	}
+
+	placement, err := isolationSegmentPlacement(ctx, r.k8sClient, r.controllerConfig.CFRootNamespace, cfProcess.Namespace)
+	if err != nil {
+		r.log.Error(err, fmt.Sprintf("Error when trying to resolve the isolation segment for CFProcess %s/%s", cfProcess.Namespace, cfProcess.Name))
+		return err
+	}
+
 	actualAppWorkload := &korifiv1alpha1.AppWorkload{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: cfProcess.Namespace,
@@ -150,13 +220,13 @@ func (r *CFProcessReconciler) createOrPatchAppWorkload(ctx context.Context, cfAp
 	}
 
 	var desiredAppWorkload *korifiv1alpha1.AppWorkload
-	desiredAppWorkload, err = r.generateAppWorkload(actualAppWorkload, cfApp, cfProcess, cfBuild, appPort, envVars)
+	desiredAppWorkload, err = r.generateAppWorkload(actualAppWorkload, cfApp, cfProcess, cfBuild, appPort, envVars, placement)
 	if err != nil { // untested
 		r.log.Error(err, "Error when initializing AppWorkload")
 		return err
 	}
 
-	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, actualAppWorkload, appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload))
+	_, err = controllerutil.CreateOrPatch(ctx, r.k8sClient, actualAppWorkload, appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload, keepEnv))
 	if err != nil {
 		r.log.Error(err, "Error calling CreateOrPatch on AppWorkload")
 		return err
@@ -194,17 +264,33 @@ func needsToDeleteAppWorkload(
 		appWorkload.Labels[korifiv1alpha1.CFAppRevisionKey] != cfAppRev
 }
 
-func appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload *korifiv1alpha1.AppWorkload) controllerutil.MutateFn {
+// runningEnvVarGroupChanged tells whether the running env var group has changed since the app was started. Only the
+// hash of the group the app was started with is kept, so the workloads of the app keep their environment until the
+// app is restarted.
+func (r *CFProcessReconciler) runningEnvVarGroupChanged(ctx context.Context, cfApp *korifiv1alpha1.CFApp) (bool, error) {
+	group, err := env.GetEnvVarGroup(ctx, r.k8sClient, r.controllerConfig.CFRootNamespace, korifiv1alpha1.RunningEnvVarGroupSecretName)
+	if err != nil {
+		return false, err
+	}
+
+	return env.HashEnvVarGroup(group) != cfApp.Status.RunningEnvVarGroupHash, nil
+}
+
+func appWorkloadMutateFunction(actualAppWorkload, desiredAppWorkload *korifiv1alpha1.AppWorkload, keepEnv bool) controllerutil.MutateFn {
 	return func() error {
+		actualEnv := actualAppWorkload.Spec.Env
 		actualAppWorkload.Labels = desiredAppWorkload.Labels
 		actualAppWorkload.Annotations = desiredAppWorkload.Annotations
 		actualAppWorkload.OwnerReferences = desiredAppWorkload.OwnerReferences
 		actualAppWorkload.Spec = desiredAppWorkload.Spec
+		if keepEnv && !actualAppWorkload.CreationTimestamp.IsZero() {
+			actualAppWorkload.Spec.Env = actualEnv
+		}
 		return nil
 	}
 }
 
-func (r *CFProcessReconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWorkload, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, cfBuild *korifiv1alpha1.CFBuild, appPort int, envVars []corev1.EnvVar) (*korifiv1alpha1.AppWorkload, error) {
+func (r *CFProcessReconciler) generateAppWorkload(actualAppWorkload *korifiv1alpha1.AppWorkload, cfApp *korifiv1alpha1.CFApp, cfProcess *korifiv1alpha1.CFProcess, cfBuild *korifiv1alpha1.CFBuild, appPort int, envVars []corev1.EnvVar, placement *korifiv1alpha1.IsolationSegmentPlacement) (*korifiv1alpha1.AppWorkload, error) {
 	var desiredAppWorkload korifiv1alpha1.AppWorkload
 	actualAppWorkload.DeepCopyInto(&desiredAppWorkload)
 
@@ -245,6 +331,7 @@ func (r *CFProcessReconciler) generateAppWorkload(actualAppWorkload *korifiv1alp
 	desiredAppWorkload.Spec.StartupProbe = startupProbe(cfProcess, appPort)
 	desiredAppWorkload.Spec.LivenessProbe = livenessProbe(cfProcess, appPort)
 	desiredAppWorkload.Spec.RunnerName = r.controllerConfig.RunnerName
+	desiredAppWorkload.Spec.IsolationSegment = placement
 
 	err := controllerutil.SetOwnerReference(cfProcess, &desiredAppWorkload, r.scheme)
 	if err != nil {
@@ -404,7 +491,35 @@ func (r *CFProcessReconciler) SetupWithManager(mgr ctrl.Manager) *builder.Builde
 			}
 
 			return requests
-		}))
+		})).
+		Watches(&source.Kind{Type: &korifiv1alpha1.CFSpace{}}, handler.EnqueueRequestsFromMapFunc(r.isolationSegmentToProcesses), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &korifiv1alpha1.CFIsolationSegment{}}, handler.EnqueueRequestsFromMapFunc(r.isolationSegmentToProcesses), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+}
+
+// isolationSegmentToProcesses enqueues the processes of the spaces placed by the given CFSpace or CFIsolationSegment,
+// so that running apps move when their space is assigned to another isolation segment
+func (r *CFProcessReconciler) isolationSegmentToProcesses(obj client.Object) []reconcile.Request {
+	namespaces, err := isolationSegmentNamespaces(context.Background(), r.k8sClient, obj)
+	if err != nil {
+		r.log.Error(err, "Error when trying to list the spaces of an isolation segment", "name", obj.GetName())
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, ns := range namespaces {
+		processList := &korifiv1alpha1.CFProcessList{}
+		err = r.k8sClient.List(context.Background(), processList, client.InNamespace(ns))
+		if err != nil {
+			r.log.Error(err, fmt.Sprintf("Error when trying to list CFProcesses in namespace %q", ns))
+			return []reconcile.Request{}
+		}
+
+		for i := range processList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&processList.Items[i])})
+		}
+	}
+
+	return requests
 }
 
 func mebibyteQuantity(miB int64) resource.Quantity {
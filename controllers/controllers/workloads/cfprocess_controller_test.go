@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha1"
 	"fmt"
+	"sort"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	. "code.cloudfoundry.org/korifi/controllers/controllers/workloads/testutils"
@@ -183,6 +184,64 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 			})
 		})
 
+		It("records a STARTED app usage event", func() {
+			Eventually(func(g Gomega) {
+				events := appUsageEventsFor(g, testAppGUID)
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0].Spec.State).To(Equal(korifiv1alpha1.AppUsageStateStarted))
+				g.Expect(events[0].Spec.PreviousState).To(Equal(korifiv1alpha1.AppUsageStateStopped))
+				g.Expect(events[0].Spec.App.Name).To(Equal(cfApp.Spec.DisplayName))
+				g.Expect(events[0].Spec.Process).To(PointTo(Equal(korifiv1alpha1.AppUsageEventResource{GUID: testProcessGUID, Name: processTypeWeb})))
+				g.Expect(events[0].Spec.Space.GUID).To(Equal(testNamespace))
+				g.Expect(events[0].Spec.InstanceCount).To(Equal(*cfProcess.Spec.DesiredInstances))
+				g.Expect(events[0].Spec.MemoryInMBPerInstance).To(Equal(cfProcess.Spec.MemoryMB))
+			}).Should(Succeed())
+		})
+
+		When("the space is assigned to an isolation segment after the app has started", func() {
+			var orgNamespace string
+
+			JustBeforeEach(func() {
+				eventuallyCreatedAppWorkloadShould(testProcessGUID, testNamespace, func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.IsolationSegment).To(BeNil())
+				})
+
+				orgNamespace = GenerateGUID()
+				createNamespace(ctx, k8sClient, orgNamespace)
+				Expect(k8s.PatchResource(ctx, k8sClient, ns, func() {
+					if ns.Labels == nil {
+						ns.Labels = map[string]string{}
+					}
+					ns.Labels[korifiv1alpha1.OrgGUIDLabel] = orgNamespace
+				})).To(Succeed())
+
+				Expect(k8sClient.Create(ctx, &korifiv1alpha1.CFIsolationSegment{
+					ObjectMeta: metav1.ObjectMeta{Name: "segment-" + testNamespace, Namespace: cfRootNamespace},
+					Spec: korifiv1alpha1.CFIsolationSegmentSpec{
+						DisplayName:  "segment-" + testNamespace,
+						NodeSelector: map[string]string{"pool": "isolated"},
+					},
+				})).To(Succeed())
+
+				cfSpace := &korifiv1alpha1.CFSpace{
+					ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Namespace: orgNamespace},
+					Spec:       korifiv1alpha1.CFSpaceSpec{DisplayName: "space-" + testNamespace},
+				}
+				Expect(k8sClient.Create(ctx, cfSpace)).To(Succeed())
+				Expect(k8s.PatchResource(ctx, k8sClient, cfSpace, func() {
+					cfSpace.Spec.IsolationSegmentRef.Name = "segment-" + testNamespace
+				})).To(Succeed())
+			})
+
+			It("places the existing app workload on the isolation segment", func() {
+				eventuallyCreatedAppWorkloadShould(testProcessGUID, testNamespace, func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.IsolationSegment).NotTo(BeNil())
+					g.Expect(appWorkload.Spec.IsolationSegment.Name).To(Equal("segment-" + testNamespace))
+					g.Expect(appWorkload.Spec.IsolationSegment.NodeSelector).To(Equal(map[string]string{"pool": "isolated"}))
+				})
+			})
+		})
+
 		When("The process command field isn't set", func() {
 			BeforeEach(func() {
 				cfProcess.Spec.Command = ""
@@ -215,6 +274,73 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					return appWorkloads.Items, err
 				}).Should(BeEmpty(), "Timed out waiting for deletion of AppWorkload/%s in namespace %s to cause NotFound error", testProcessGUID, testNamespace)
 			})
+
+			It("records a STOPPED app usage event", func() {
+				Eventually(func(g Gomega) {
+					events := appUsageEventsFor(g, testAppGUID)
+					g.Expect(events).To(HaveLen(2))
+					g.Expect(events[1].Spec.State).To(Equal(korifiv1alpha1.AppUsageStateStopped))
+					g.Expect(events[1].Spec.PreviousState).To(Equal(korifiv1alpha1.AppUsageStateStarted))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the CFApp is deleted", func() {
+			JustBeforeEach(func() {
+				Eventually(func(g Gomega) {
+					g.Expect(appUsageEventsFor(g, testAppGUID)).To(HaveLen(1))
+				}).Should(Succeed())
+				Expect(k8sClient.Delete(ctx, cfApp)).To(Succeed())
+			})
+
+			It("records a STOPPED app usage event", func() {
+				Eventually(func(g Gomega) {
+					events := appUsageEventsFor(g, testAppGUID)
+					g.Expect(events).To(HaveLen(2))
+					g.Expect(events[1].Spec.State).To(Equal(korifiv1alpha1.AppUsageStateStopped))
+					g.Expect(events[1].Spec.PreviousState).To(Equal(korifiv1alpha1.AppUsageStateStarted))
+					g.Expect(events[1].Spec.Process).To(PointTo(Equal(korifiv1alpha1.AppUsageEventResource{GUID: testProcessGUID, Name: processTypeWeb})))
+					g.Expect(events[1].Spec.InstanceCount).To(Equal(*cfProcess.Spec.DesiredInstances))
+				}).Should(Succeed())
+			})
+		})
+
+		When("the running env var group changes while the app runs", func() {
+			var groupSecret *corev1.Secret
+
+			BeforeEach(func() {
+				groupSecret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      korifiv1alpha1.RunningEnvVarGroupSecretName,
+						Namespace: cfRootNamespace,
+					},
+					StringData: map[string]string{"https_proxy": "proxy.example.com"},
+				}
+				Expect(k8sClient.Create(ctx, groupSecret)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), groupSecret)).To(Succeed())
+				})
+			})
+
+			JustBeforeEach(func() {
+				eventuallyCreatedAppWorkloadShould(testProcessGUID, testNamespace, func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Env).To(ContainElement(Equal(corev1.EnvVar{Name: "https_proxy", Value: "proxy.example.com"})))
+				})
+
+				Expect(k8s.Patch(ctx, k8sClient, groupSecret, func() {
+					groupSecret.StringData = map[string]string{"https_proxy": "new-proxy.example.com"}
+				})).To(Succeed())
+				Expect(k8s.Patch(ctx, k8sClient, cfProcess, func() {
+					cfProcess.Spec.DesiredInstances = tools.PtrTo(2)
+				})).To(Succeed())
+			})
+
+			It("keeps the environment the app was started with", func() {
+				eventuallyCreatedAppWorkloadShould(testProcessGUID, testNamespace, func(g Gomega, appWorkload korifiv1alpha1.AppWorkload) {
+					g.Expect(appWorkload.Spec.Instances).To(BeEquivalentTo(2))
+					g.Expect(appWorkload.Spec.Env).To(ContainElement(Equal(corev1.EnvVar{Name: "https_proxy", Value: "proxy.example.com"})))
+				})
+			})
 		})
 
 		When("the app process instances are scaled down to 0", func() {
@@ -235,6 +361,16 @@ var _ = Describe("CFProcessReconciler Integration Tests", func() {
 					g.Expect(appWorkloads.Items).To(BeEmpty())
 				}).Should(Succeed())
 			})
+
+			It("records a scaling app usage event", func() {
+				Eventually(func(g Gomega) {
+					events := appUsageEventsFor(g, testAppGUID)
+					g.Expect(events).To(HaveLen(2))
+					g.Expect(events[1].Spec.State).To(Equal(korifiv1alpha1.AppUsageStateStarted))
+					g.Expect(events[1].Spec.InstanceCount).To(BeZero())
+					g.Expect(events[1].Spec.PreviousInstanceCount).To(PointTo(Equal(1)))
+				}).Should(Succeed())
+			})
 		})
 
 		When("the app process instances are unset", func() {
@@ -554,3 +690,21 @@ func eventuallyCreatedAppWorkloadShould(processGUID, namespace string, shouldFn
 		shouldFn(g, appWorkloads.Items[0])
 	}).Should(Succeed())
 }
+
+func appUsageEventsFor(g Gomega, appGUID string) []korifiv1alpha1.CFAppUsageEvent {
+	var eventList korifiv1alpha1.CFAppUsageEventList
+	g.Expect(k8sClient.List(context.Background(), &eventList, client.InNamespace(cfRootNamespace))).To(Succeed())
+
+	events := []korifiv1alpha1.CFAppUsageEvent{}
+	for _, event := range eventList.Items {
+		if event.Spec.App.GUID == appGUID {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Spec.Timestamp.Before(&events[j].Spec.Timestamp)
+	})
+
+	return events
+}
@@ -102,7 +102,10 @@ func (r *CFSpaceReconciler) ReconcileResource(ctx context.Context, cfSpace *kori
 		return finalize(ctx, r.client, log, cfSpace, spaceFinalizerName)
 	}
 
-	labels := map[string]string{korifiv1alpha1.SpaceNameLabel: cfSpace.Spec.DisplayName}
+	labels := map[string]string{
+		korifiv1alpha1.SpaceNameLabel: cfSpace.Spec.DisplayName,
+		korifiv1alpha1.OrgGUIDLabel:   cfSpace.Namespace,
+	}
 	err := createOrPatchNamespace(ctx, r.client, log, cfSpace, labels)
 	if err != nil {
 		log.Error(err, "Error creating namespace")
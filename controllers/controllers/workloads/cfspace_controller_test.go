@@ -86,6 +86,7 @@ var _ = Describe("CFSpaceReconciler Integration Tests", func() {
 				var createdSpace corev1.Namespace
 				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: spaceGUID}, &createdSpace)).To(Succeed())
 				g.Expect(createdSpace.Labels).To(HaveKeyWithValue(korifiv1alpha1.SpaceNameLabel, spaceName))
+				g.Expect(createdSpace.Labels).To(HaveKeyWithValue(korifiv1alpha1.OrgGUIDLabel, orgNamespace.Name))
 			}).Should(Succeed())
 		})
 
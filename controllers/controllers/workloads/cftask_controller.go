@@ -52,7 +52,9 @@ type CFTaskReconciler struct {
 	recorder        record.EventRecorder
 	logger          logr.Logger
 	envBuilder      EnvBuilder
+	rootNamespace   string
 	taskTTLDuration time.Duration
+	defaultMemoryMB int64
 }
 
 func NewCFTaskReconciler(
@@ -61,7 +63,9 @@ func NewCFTaskReconciler(
 	recorder record.EventRecorder,
 	logger logr.Logger,
 	envBuilder EnvBuilder,
+	rootNamespace string,
 	taskTTLDuration time.Duration,
+	defaultMemoryMB int64,
 ) *k8s.PatchingReconciler[korifiv1alpha1.CFTask, *korifiv1alpha1.CFTask] {
 	taskReconciler := CFTaskReconciler{
 		k8sClient:       client,
@@ -69,7 +73,9 @@ func NewCFTaskReconciler(
 		recorder:        recorder,
 		logger:          logger,
 		envBuilder:      envBuilder,
+		rootNamespace:   rootNamespace,
 		taskTTLDuration: taskTTLDuration,
+		defaultMemoryMB: defaultMemoryMB,
 	}
 	return k8s.NewPatchingReconciler[korifiv1alpha1.CFTask, *korifiv1alpha1.CFTask](logger, client, &taskReconciler)
 }
@@ -78,6 +84,8 @@ func NewCFTaskReconciler(
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cftasks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=taskworkloads,verbs=get;list;watch;create;patch;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfspaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfisolationsegments,verbs=get;list;watch
 
 func (r *CFTaskReconciler) ReconcileResource(ctx context.Context, cfTask *korifiv1alpha1.CFTask) (ctrl.Result, error) {
 	if r.alreadyExpired(cfTask) {
@@ -91,6 +99,9 @@ func (r *CFTaskReconciler) ReconcileResource(ctx context.Context, cfTask *korifi
 
 	if cfTask.Spec.Canceled {
 		err := r.handleCancelation(ctx, cfTask)
+		if err == nil {
+			err = r.reportCanceledUsage(ctx, cfTask)
+		}
 		return r.reconcileResult(cfTask, err)
 	}
 
@@ -117,20 +128,97 @@ func (r *CFTaskReconciler) ReconcileResource(ctx context.Context, cfTask *korifi
 		return r.reconcileResult(cfTask, err)
 	}
 
+	// the API server drops the status set by the defaulting webhook when the task is created, so tasks get the
+	// default memory until the webhook sets it, as the quota validator assumes
+	if cfTask.Status.MemoryMB == 0 {
+		cfTask.Status.MemoryMB = r.defaultMemoryMB
+	}
+
 	env, err := r.envBuilder.BuildEnv(ctx, cfApp)
 	if err != nil {
 		r.logger.Error(err, "failed to build env")
 		return r.reconcileResult(cfTask, err)
 	}
 
-	taskWorkload, err := r.createOrPatchTaskWorkload(ctx, cfTask, cfDroplet, webProcess, env)
+	placement, err := isolationSegmentPlacement(ctx, r.k8sClient, r.rootNamespace, cfTask.Namespace)
+	if err != nil {
+		r.logger.Error(err, "failed to resolve isolation segment")
+		return r.reconcileResult(cfTask, err)
+	}
+
+	taskWorkload, err := r.createOrPatchTaskWorkload(ctx, cfTask, cfDroplet, webProcess, env, placement)
 	if err != nil {
 		return r.reconcileResult(cfTask, err)
 	}
 
 	r.setTaskStatus(cfTask, taskWorkload.Status.Conditions)
 
-	return r.reconcileResult(cfTask, nil)
+	err = r.reportUsage(ctx, cfTask, cfApp)
+	if err != nil {
+		r.logger.Error(err, "failed to report task usage")
+	}
+
+	return r.reconcileResult(cfTask, err)
+}
+
+// reportUsage records an app usage event when the task has started or stopped since the last reported usage
+func (r *CFTaskReconciler) reportUsage(ctx context.Context, cfTask *korifiv1alpha1.CFTask, cfApp *korifiv1alpha1.CFApp) error {
+	state := ""
+	switch {
+	case meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskSucceededConditionType),
+		meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskFailedConditionType):
+		state = korifiv1alpha1.AppUsageStateTaskStopped
+	case meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskStartedConditionType):
+		state = korifiv1alpha1.AppUsageStateTaskStarted
+	}
+
+	if state == "" || state == cfTask.Status.ReportedUsageState {
+		return nil
+	}
+
+	err := recordAppUsageEvent(ctx, r.k8sClient, r.rootNamespace, cfApp, fmt.Sprintf("%s/%s", cfTask.UID, state), korifiv1alpha1.CFAppUsageEventSpec{
+		State:                 state,
+		PreviousState:         cfTask.Status.ReportedUsageState,
+		Task:                  &korifiv1alpha1.AppUsageEventResource{GUID: cfTask.Name, Name: cfTask.Name},
+		InstanceCount:         1,
+		MemoryInMBPerInstance: cfTask.Status.MemoryMB,
+	})
+	if err != nil {
+		return err
+	}
+
+	cfTask.Status.ReportedUsageState = state
+	if state == korifiv1alpha1.AppUsageStateTaskStopped {
+		observeTaskOutcome(taskOutcome(cfTask))
+	}
+
+	return nil
+}
+
+func taskOutcome(cfTask *korifiv1alpha1.CFTask) string {
+	switch {
+	case meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskCanceledConditionType):
+		return outcomeCanceled
+	case meta.IsStatusConditionTrue(cfTask.Status.Conditions, korifiv1alpha1.TaskSucceededConditionType):
+		return outcomeSucceeded
+	default:
+		return outcomeFailed
+	}
+}
+
+// reportCanceledUsage reports a task stopped by cancelation. Canceled tasks may belong to apps that are no
+// longer staged, so the app is fetched without the checks the task workload needs
+func (r *CFTaskReconciler) reportCanceledUsage(ctx context.Context, cfTask *korifiv1alpha1.CFTask) error {
+	cfApp := new(korifiv1alpha1.CFApp)
+	err := r.k8sClient.Get(ctx, types.NamespacedName{Namespace: cfTask.Namespace, Name: cfTask.Spec.AppRef.Name}, cfApp)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.reportUsage(ctx, cfTask, cfApp)
 }
 
 func (r *CFTaskReconciler) setTaskStatus(cfTask *korifiv1alpha1.CFTask, taskWorkloadConditions []metav1.Condition) {
@@ -221,7 +309,7 @@ func (r *CFTaskReconciler) getWebProcess(ctx context.Context, cfApp *korifiv1alp
 	return processList.Items[0], err
 }
 
-func (r *CFTaskReconciler) createOrPatchTaskWorkload(ctx context.Context, cfTask *korifiv1alpha1.CFTask, cfDroplet *korifiv1alpha1.CFBuild, webProcess korifiv1alpha1.CFProcess, env []corev1.EnvVar) (*korifiv1alpha1.TaskWorkload, error) {
+func (r *CFTaskReconciler) createOrPatchTaskWorkload(ctx context.Context, cfTask *korifiv1alpha1.CFTask, cfDroplet *korifiv1alpha1.CFBuild, webProcess korifiv1alpha1.CFProcess, env []corev1.EnvVar, placement *korifiv1alpha1.IsolationSegmentPlacement) (*korifiv1alpha1.TaskWorkload, error) {
 	taskWorkload := &korifiv1alpha1.TaskWorkload{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cfTask.Name,
@@ -252,6 +340,7 @@ func (r *CFTaskReconciler) createOrPatchTaskWorkload(ctx context.Context, cfTask
 		taskWorkload.Spec.Resources.Limits[corev1.ResourceEphemeralStorage] = *resource.NewScaledQuantity(cfTask.Status.DiskQuotaMB, resource.Mega)
 		taskWorkload.Spec.Resources.Requests[corev1.ResourceCPU] = *resource.NewScaledQuantity(calculateDefaultCPURequestMillicores(webProcess.Spec.MemoryMB), resource.Milli)
 		taskWorkload.Spec.Env = env
+		taskWorkload.Spec.IsolationSegment = placement
 
 		if err := ctrl.SetControllerReference(cfTask, taskWorkload, r.scheme); err != nil {
 			r.logger.Error(err, "failed to set owner ref")
@@ -139,6 +139,21 @@ var _ = Describe("CFTaskReconciler Integration Tests", func() {
 		}
 	})
 
+	Describe("CFTask creation without a recorded memory", func() {
+		JustBeforeEach(func() {
+			Expect(k8sClient.Create(ctx, cfTask)).To(Succeed())
+		})
+
+		It("sizes the TaskWorkload with the default task memory rather than the memory of the web process", func() {
+			Eventually(func(g Gomega) {
+				taskWorkload := korifiv1alpha1.TaskWorkload{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: cfTask.Name}, &taskWorkload)).To(Succeed())
+				g.Expect(taskWorkload.Spec.Resources.Requests.Memory().String()).To(Equal("500M"))
+				g.Expect(taskWorkload.Spec.Resources.Limits.Memory().String()).To(Equal("500M"))
+			}).Should(Succeed())
+		})
+	})
+
 	Describe("CFTask creation", func() {
 		JustBeforeEach(func() {
 			Expect(k8sClient.Create(ctx, cfTask)).To(Succeed())
@@ -270,6 +285,16 @@ var _ = Describe("CFTaskReconciler Integration Tests", func() {
 					g.Expect(meta.IsStatusConditionTrue(task.Status.Conditions, korifiv1alpha1.TaskStartedConditionType)).To(BeTrue())
 				}).Should(Succeed())
 			})
+
+			It("records a TASK_STARTED app usage event", func() {
+				Eventually(func(g Gomega) {
+					events := appUsageEventsFor(g, cfApp.Name)
+					g.Expect(events).To(HaveLen(1))
+					g.Expect(events[0].Spec.State).To(Equal(korifiv1alpha1.AppUsageStateTaskStarted))
+					g.Expect(events[0].Spec.Task).To(PointTo(Equal(korifiv1alpha1.AppUsageEventResource{GUID: cfTask.Name, Name: cfTask.Name})))
+					g.Expect(events[0].Spec.InstanceCount).To(Equal(1))
+				}).Should(Succeed())
+			})
 		})
 	})
 
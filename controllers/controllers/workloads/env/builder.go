@@ -2,13 +2,17 @@ package env
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,14 +35,32 @@ type ServiceDetails struct {
 }
 
 type Builder struct {
-	k8sClient client.Client
+	k8sClient     client.Client
+	rootNamespace string
 }
 
-func NewBuilder(k8sClient client.Client) *Builder {
-	return &Builder{k8sClient: k8sClient}
+func NewBuilder(k8sClient client.Client, rootNamespace string) *Builder {
+	return &Builder{k8sClient: k8sClient, rootNamespace: rootNamespace}
 }
 
+// BuildEnv returns the environment of the running app: its own environment variables and VCAP_SERVICES, on top of the
+// running environment variable group
 func (b *Builder) BuildEnv(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error) {
+	envVars, err := b.BuildAppEnv(ctx, cfApp)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := GetEnvVarGroup(ctx, b.k8sClient, b.rootNamespace, korifiv1alpha1.RunningEnvVarGroupSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	return MergeEnvVarGroup(envVars, group), nil
+}
+
+// BuildAppEnv returns the app's own environment variables and VCAP_SERVICES, without any environment variable group
+func (b *Builder) BuildAppEnv(ctx context.Context, cfApp *korifiv1alpha1.CFApp) ([]corev1.EnvVar, error) {
 	var appEnvSecret, vcapServicesSecret corev1.Secret
 
 	if cfApp.Spec.EnvSecretName != "" {
@@ -99,6 +121,62 @@ func (b *Builder) BuildVCAPServicesEnvValue(ctx context.Context, cfApp *korifiv1
 	return string(toReturn), nil
 }
 
+// GetEnvVarGroup returns the variables of the named environment variable group in the root namespace. A group that
+// has never been set is empty.
+func GetEnvVarGroup(ctx context.Context, k8sClient client.Client, rootNamespace, secretName string) (map[string]string, error) {
+	var groupSecret corev1.Secret
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: rootNamespace, Name: secretName}, &groupSecret)
+	if k8serrors.IsNotFound(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when trying to fetch env var group Secret %s/%s: %w", rootNamespace, secretName, err)
+	}
+
+	return mapFromSecret(groupSecret), nil
+}
+
+// HashEnvVarGroup returns a hash of the variables of the group, which changes whenever any of them does
+func HashEnvVarGroup(group map[string]string) string {
+	names := make([]string, 0, len(group))
+	for name := range group {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		// the lengths keep names and values from running into each other
+		fmt.Fprintf(hash, "%d:%s%d:%s", len(name), name, len(group[name]), group[name])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// MergeEnvVarGroup appends the variables of the group that are not already set, so that the group has the lowest
+// precedence
+func MergeEnvVarGroup(envVars []corev1.EnvVar, group map[string]string) []corev1.EnvVar {
+	alreadySet := map[string]bool{}
+	for _, envVar := range envVars {
+		alreadySet[envVar.Name] = true
+	}
+
+	var names []string
+	for name := range group {
+		if !alreadySet[name] {
+			names = append(names, name)
+		}
+	}
+	// keep the order stable so that workloads do not change unless the group does
+	sort.Strings(names)
+
+	for _, name := range names {
+		envVars = append(envVars, corev1.EnvVar{Name: name, Value: group[name]})
+	}
+
+	return envVars
+}
+
 func mapFromSecret(secret corev1.Secret) map[string]string {
 	convertedMap := make(map[string]string)
 	for k, v := range secret.Data {
@@ -146,6 +224,11 @@ func fromServiceBinding(
 		tags = []string{}
 	}
 
+	var syslogDrainURL *string
+	if serviceInstance.Spec.SyslogDrainURL != "" {
+		syslogDrainURL = &serviceInstance.Spec.SyslogDrainURL
+	}
+
 	return ServiceDetails{
 		Label:          "user-provided",
 		Name:           serviceName,
@@ -155,7 +238,7 @@ func fromServiceBinding(
 		BindingGUID:    serviceBinding.Name,
 		BindingName:    bindingName,
 		Credentials:    mapFromSecret(serviceBindingSecret),
-		SyslogDrainURL: nil,
+		SyslogDrainURL: syslogDrainURL,
 		VolumeMounts:   []string{},
 	}
 }
@@ -46,6 +46,7 @@ var _ = Describe("Builder", func() {
 		serviceBindingSecret2 corev1.Secret
 		vcapServicesSecret    corev1.Secret
 		appSecret             corev1.Secret
+		runningGroupSecret    corev1.Secret
 		cfApp                 *korifiv1alpha1.CFApp
 
 		builder *env.Builder
@@ -56,7 +57,7 @@ var _ = Describe("Builder", func() {
 
 	BeforeEach(func() {
 		cfClient = new(fake.Client)
-		builder = env.NewBuilder(cfClient)
+		builder = env.NewBuilder(cfClient, "root-ns")
 		listServiceBindingsError = nil
 		getServiceInstanceError = nil
 		getAppSecretError = nil
@@ -140,6 +141,7 @@ var _ = Describe("Builder", func() {
 				"app-secret": []byte("top-secret"),
 			},
 		}
+		runningGroupSecret = corev1.Secret{}
 		cfApp = &korifiv1alpha1.CFApp{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "app-ns",
@@ -202,6 +204,10 @@ var _ = Describe("Builder", func() {
 					vcapServicesSecret.DeepCopyInto(obj)
 					return getVCAPServicesSecretError
 				}
+				if nsName.Name == korifiv1alpha1.RunningEnvVarGroupSecretName {
+					runningGroupSecret.DeepCopyInto(obj)
+					return nil
+				}
 				if nsName.Name == serviceBindingSecret.Name {
 					serviceBindingSecret.DeepCopyInto(obj)
 				}
@@ -223,14 +229,16 @@ var _ = Describe("Builder", func() {
 			Expect(buildEnvErr).NotTo(HaveOccurred())
 		})
 
-		It("gets the app secrets (env and vcap services)", func() {
-			Expect(cfClient.GetCallCount()).To(Equal(2))
+		It("gets the app secrets (env and vcap services) and the running env var group", func() {
+			Expect(cfClient.GetCallCount()).To(Equal(3))
 			_, actualNsName, _, _ := cfClient.GetArgsForCall(0)
 			Expect(actualNsName.Namespace).To(Equal(cfApp.Namespace))
 			Expect(actualNsName.Name).To(Equal(cfApp.Spec.EnvSecretName))
 			_, actualNsName, _, _ = cfClient.GetArgsForCall(1)
 			Expect(actualNsName.Namespace).To(Equal(cfApp.Namespace))
 			Expect(actualNsName.Name).To(Equal(cfApp.Status.VCAPServicesSecretName))
+			_, actualNsName, _, _ = cfClient.GetArgsForCall(2)
+			Expect(actualNsName).To(Equal(types.NamespacedName{Namespace: "root-ns", Name: korifiv1alpha1.RunningEnvVarGroupSecretName}))
 		})
 
 		It("returns the user defined and vcap services env vars", func() {
@@ -430,6 +438,109 @@ var _ = Describe("Builder", func() {
 				})
 			})
 		})
+
+		When("the running env var group is set", func() {
+			BeforeEach(func() {
+				runningGroupSecret.Data = map[string][]byte{
+					"https_proxy":   []byte("proxy.example.com"),
+					"app-secret":    []byte("group-value"),
+					"VCAP_SERVICES": []byte("group-value"),
+					"no_proxy":      []byte("localhost"),
+				}
+			})
+
+			It("appends the group vars that the app does not set itself, in name order", func() {
+				Expect(envVars).To(HaveLen(4))
+				Expect(envVars[2:]).To(Equal([]corev1.EnvVar{
+					{Name: "https_proxy", Value: "proxy.example.com"},
+					{Name: "no_proxy", Value: "localhost"},
+				}))
+			})
+		})
+	})
+
+	Describe("BuildAppEnv", func() {
+		BeforeEach(func() {
+			runningGroupSecret.Data = map[string][]byte{"https_proxy": []byte("proxy.example.com")}
+		})
+
+		JustBeforeEach(func() {
+			envVars, buildEnvErr = builder.BuildAppEnv(context.Background(), cfApp)
+		})
+
+		It("returns the user defined and vcap services env vars only", func() {
+			Expect(buildEnvErr).NotTo(HaveOccurred())
+			Expect(envVars).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("VCAP_SERVICES")}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("app-secret")}),
+			))
+		})
+	})
+
+	Describe("GetEnvVarGroup", func() {
+		var (
+			getGroupErr error
+			group       map[string]string
+			groupErr    error
+		)
+
+		BeforeEach(func() {
+			getGroupErr = nil
+			cfClient.GetStub = func(_ context.Context, nsName types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+				secret, ok := obj.(*corev1.Secret)
+				Expect(ok).To(BeTrue())
+				secret.Data = map[string][]byte{"https_proxy": []byte("proxy.example.com")}
+				return getGroupErr
+			}
+		})
+
+		JustBeforeEach(func() {
+			group, groupErr = env.GetEnvVarGroup(context.Background(), cfClient, "root-ns", korifiv1alpha1.StagingEnvVarGroupSecretName)
+		})
+
+		It("returns the variables of the group secret", func() {
+			Expect(groupErr).NotTo(HaveOccurred())
+			Expect(group).To(Equal(map[string]string{"https_proxy": "proxy.example.com"}))
+
+			Expect(cfClient.GetCallCount()).To(Equal(1))
+			_, actualNsName, _, _ := cfClient.GetArgsForCall(0)
+			Expect(actualNsName).To(Equal(types.NamespacedName{Namespace: "root-ns", Name: "cf-staging-env-var-group"}))
+		})
+
+		When("the group secret does not exist", func() {
+			BeforeEach(func() {
+				cfClient.GetReturns(apierrors.NewNotFound(schema.GroupResource{}, "cf-staging-env-var-group"))
+			})
+
+			It("returns an empty group", func() {
+				Expect(groupErr).NotTo(HaveOccurred())
+				Expect(group).To(BeEmpty())
+			})
+		})
+
+		When("getting the group secret fails", func() {
+			BeforeEach(func() {
+				getGroupErr = errors.New("get-group-err")
+			})
+
+			It("returns an error", func() {
+				Expect(groupErr).To(MatchError(ContainSubstring("get-group-err")))
+			})
+		})
+	})
+
+	Describe("HashEnvVarGroup", func() {
+		It("does not depend on the order of the variables", func() {
+			Expect(env.HashEnvVarGroup(map[string]string{"a": "1", "b": "2"})).To(Equal(env.HashEnvVarGroup(map[string]string{"b": "2", "a": "1"})))
+		})
+
+		It("changes when a value changes", func() {
+			Expect(env.HashEnvVarGroup(map[string]string{"a": "1"})).NotTo(Equal(env.HashEnvVarGroup(map[string]string{"a": "2"})))
+		})
+
+		It("tells names and values apart", func() {
+			Expect(env.HashEnvVarGroup(map[string]string{"ab": "c"})).NotTo(Equal(env.HashEnvVarGroup(map[string]string{"a": "bc"})))
+		})
 	})
 
 	Describe("BuildVCAPServicesEnvValue", func() {
@@ -509,6 +620,16 @@ var _ = Describe("Builder", func() {
 			})
 		})
 
+		When("the service instance has a syslog drain URL", func() {
+			BeforeEach(func() {
+				serviceInstance.Spec.SyslogDrainURL = "syslog-tls://logs.example.com:6514"
+			})
+
+			It("sets the syslog drain URL", func() {
+				Expect(extractServiceInfo(vcapServicesString)).To(ContainElement(HaveKeyWithValue("syslog_drain_url", "syslog-tls://logs.example.com:6514")))
+			})
+		})
+
 		When("service instance tags are nil", func() {
 			BeforeEach(func() {
 				serviceInstance.Spec.Tags = nil
@@ -0,0 +1,50 @@
+package workloads
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	outcomeSucceeded = "succeeded"
+	outcomeFailed    = "failed"
+	outcomeCanceled  = "canceled"
+)
+
+// These metrics are served by the manager alongside the controller-runtime defaults
+var (
+	stagingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_controllers_staging_duration_seconds",
+		Help:    "Time from the creation of a build to the end of its staging, by outcome",
+		Buckets: []float64{10, 30, 60, 120, 180, 300, 600, 900, 1800},
+	}, []string{"outcome"})
+
+	appInstanceStartDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "korifi_controllers_app_instance_start_duration_seconds",
+		Help:    "Time from the creation of an app instance pod to it becoming ready, by process type",
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"process_type"})
+
+	taskOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "korifi_controllers_task_outcomes_total",
+		Help: "Number of finished tasks, by outcome (succeeded, failed or canceled)",
+	}, []string{"outcome"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(stagingDuration, appInstanceStartDuration, taskOutcomes)
+}
+
+func observeStagingDuration(outcome string, duration time.Duration) {
+	stagingDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+func observeAppInstanceStart(processType string, duration time.Duration) {
+	appInstanceStartDuration.WithLabelValues(processType).Observe(duration.Seconds())
+}
+
+func observeTaskOutcome(outcome string) {
+	taskOutcomes.WithLabelValues(outcome).Inc()
+}
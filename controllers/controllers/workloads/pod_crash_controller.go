@@ -0,0 +1,229 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	AppCrashEventType = "audit.app.process.crash"
+
+	// app instance pods are labelled with the process guid by the workload runner
+	podProcessGUIDLabelKey   = "korifi.cloudfoundry.org/guid"
+	applicationContainerName = "application"
+	instanceIndexEnvVar      = "CF_INSTANCE_INDEX"
+)
+
+// PodCrashReconciler watches the pods of app instances and records an audit.app.process.crash CFAuditEvent
+// every time the application container of an instance is restarted after terminating. It also measures how long
+// instances take to become ready.
+type PodCrashReconciler struct {
+	k8sClient client.Client
+	logger    logr.Logger
+
+	// pods that became ready before the reconciler started are not measured, as they may have been measured before
+	// the controllers restarted
+	createdAt    time.Time
+	readyMutex   sync.Mutex
+	readyPodUIDs map[types.NamespacedName]types.UID
+}
+
+// NewPodCrashReconciler does not wrap the reconciler in a PatchingReconciler, as pods are only observed and never
+// modified
+func NewPodCrashReconciler(client client.Client, logger logr.Logger) *PodCrashReconciler {
+	return &PodCrashReconciler{
+		k8sClient:    client,
+		logger:       logger,
+		createdAt:    time.Now(),
+		readyPodUIDs: map[types.NamespacedName]types.UID{},
+	}
+}
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfapps,verbs=get
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfauditevents,verbs=create
+
+func (r *PodCrashReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod := new(corev1.Pod)
+	err := r.k8sClient.Get(ctx, req.NamespacedName, pod)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.forgetReadyPod(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	r.observeStart(pod)
+
+	status := applicationContainerStatus(pod)
+	if status == nil || status.RestartCount == 0 || status.LastTerminationState.Terminated == nil {
+		return ctrl.Result{}, nil
+	}
+
+	appGUID := pod.Labels[korifiv1alpha1.CFAppGUIDLabelKey]
+	cfApp := new(korifiv1alpha1.CFApp)
+	err = r.k8sClient.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: appGUID}, cfApp)
+	if err != nil {
+		r.logger.Error(err, "error fetching app of crashed pod", "pod", pod.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	space, err := shared.FindSpace(ctx, r.k8sClient, pod.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	orgGUID := ""
+	if space != nil {
+		orgGUID = space.Namespace
+	}
+
+	terminated := status.LastTerminationState.Terminated
+	crashTimestamp := terminated.FinishedAt
+	if crashTimestamp.IsZero() {
+		crashTimestamp = metav1.Now()
+	}
+
+	auditEvent := &korifiv1alpha1.CFAuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			// one event per restart, no matter how often the pod is reconciled
+			Name:      uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/%d", pod.UID, status.RestartCount))).String(),
+			Namespace: pod.Namespace,
+		},
+		Spec: korifiv1alpha1.CFAuditEventSpec{
+			Type: AppCrashEventType,
+			Actor: korifiv1alpha1.AuditEventParticipant{
+				GUID: pod.Labels[podProcessGUIDLabelKey],
+				Type: "process",
+				Name: pod.Labels[korifiv1alpha1.CFProcessTypeLabelKey],
+			},
+			Target: korifiv1alpha1.AuditEventParticipant{
+				GUID: cfApp.Name,
+				Type: "app",
+				Name: cfApp.Spec.DisplayName,
+			},
+			SpaceGUID:        pod.Namespace,
+			OrganizationGUID: orgGUID,
+			Crash: &korifiv1alpha1.AuditEventCrash{
+				Index:           instanceIndex(pod),
+				Reason:          terminated.Reason,
+				ExitDescription: terminated.Message,
+				ExitStatus:      terminated.ExitCode,
+				CrashCount:      status.RestartCount,
+				CrashTimestamp:  crashTimestamp,
+			},
+		},
+	}
+
+	err = r.k8sClient.Create(ctx, auditEvent)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		r.logger.Error(err, "error recording crash event", "pod", pod.Name)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// observeStart records the start duration of an instance the first time its pod is seen ready
+func (r *PodCrashReconciler) observeStart(pod *corev1.Pod) {
+	ready := podReadyCondition(pod)
+	if ready == nil || ready.Status != corev1.ConditionTrue || ready.LastTransitionTime.Time.Before(r.createdAt) {
+		return
+	}
+
+	key := client.ObjectKeyFromObject(pod)
+
+	r.readyMutex.Lock()
+	defer r.readyMutex.Unlock()
+
+	if r.readyPodUIDs[key] == pod.UID {
+		return
+	}
+	r.readyPodUIDs[key] = pod.UID
+
+	observeAppInstanceStart(pod.Labels[korifiv1alpha1.CFProcessTypeLabelKey], ready.LastTransitionTime.Sub(pod.CreationTimestamp.Time))
+}
+
+func (r *PodCrashReconciler) forgetReadyPod(key types.NamespacedName) {
+	r.readyMutex.Lock()
+	defer r.readyMutex.Unlock()
+
+	delete(r.readyPodUIDs, key)
+}
+
+// AppPodSelector selects the pods of app instances, which are the only pods the reconciler watches. The manager should
+// restrict its pod cache to them with it, so that the controllers do not cache every pod of the cluster.
+func AppPodSelector() labels.Selector {
+	hasAppGUID, err := labels.NewRequirement(korifiv1alpha1.CFAppGUIDLabelKey, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return labels.NewSelector().Add(*hasAppGUID)
+}
+
+func (r *PodCrashReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	appPodSelector := AppPodSelector()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return appPodSelector.Matches(labels.Set(object.GetLabels()))
+		}))).
+		Complete(r)
+}
+
+func applicationContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i, status := range pod.Status.ContainerStatuses {
+		if status.Name == applicationContainerName {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+
+	return nil
+}
+
+func podReadyCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return &pod.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+func instanceIndex(pod *corev1.Pod) int {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != applicationContainerName {
+			continue
+		}
+
+		for _, env := range container.Env {
+			if env.Name == instanceIndexEnvVar {
+				index, _ := strconv.Atoi(env.Value)
+				return index
+			}
+		}
+	}
+
+	return 0
+}
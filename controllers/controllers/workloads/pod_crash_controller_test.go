@@ -0,0 +1,201 @@
+package workloads_test
+
+import (
+	"context"
+	"time"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	. "code.cloudfoundry.org/korifi/controllers/controllers/workloads/testutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("PodCrashReconciler Integration Tests", func() {
+	var (
+		ctx           context.Context
+		testNamespace string
+		appGUID       string
+		processGUID   string
+		pod           *corev1.Pod
+		finishedAt    time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		testNamespace = GenerateGUID()
+		createNamespace(ctx, k8sClient, testNamespace)
+
+		appGUID = GenerateGUID()
+		processGUID = GenerateGUID()
+		Expect(k8sClient.Create(ctx, BuildCFAppCRObject(appGUID, testNamespace))).To(Succeed())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GenerateGUID(),
+				Namespace: testNamespace,
+				Labels: map[string]string{
+					korifiv1alpha1.CFAppGUIDLabelKey:     appGUID,
+					korifiv1alpha1.CFProcessTypeLabelKey: "web",
+					"korifi.cloudfoundry.org/guid":       processGUID,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "application",
+					Image: "some-image",
+					Env: []corev1.EnvVar{
+						{Name: "CF_INSTANCE_INDEX", Value: "1"},
+					},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+		finishedAt = time.Now().Add(-time.Minute).Truncate(time.Second)
+	})
+
+	crashEvents := func(g Gomega) []korifiv1alpha1.CFAuditEvent {
+		var eventList korifiv1alpha1.CFAuditEventList
+		g.Expect(k8sClient.List(ctx, &eventList, client.InNamespace(testNamespace))).To(Succeed())
+
+		events := []korifiv1alpha1.CFAuditEvent{}
+		for _, event := range eventList.Items {
+			if event.Spec.Type == "audit.app.process.crash" {
+				events = append(events, event)
+			}
+		}
+
+		return events
+	}
+
+	When("the application container has restarted after crashing", func() {
+		BeforeEach(func() {
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "application",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Reason:     "OOMKilled",
+							Message:    "out of memory",
+							ExitCode:   137,
+							FinishedAt: metav1.NewTime(finishedAt),
+						},
+					},
+					RestartCount: 2,
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		})
+
+		It("records a single crash audit event for the restart", func() {
+			Eventually(func(g Gomega) {
+				events := crashEvents(g)
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0].Spec).To(MatchFields(IgnoreExtras, Fields{
+					"Actor": Equal(korifiv1alpha1.AuditEventParticipant{
+						GUID: processGUID,
+						Type: "process",
+						Name: "web",
+					}),
+					"Target": Equal(korifiv1alpha1.AuditEventParticipant{
+						GUID: appGUID,
+						Type: "app",
+						Name: "test-app-name",
+					}),
+					"SpaceGUID": Equal(testNamespace),
+					"Crash": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Index":           Equal(1),
+						"Reason":          Equal("OOMKilled"),
+						"ExitDescription": Equal("out of memory"),
+						"ExitStatus":      Equal(int32(137)),
+						"CrashCount":      Equal(int32(2)),
+						"CrashTimestamp": WithTransform(func(t metav1.Time) time.Time {
+							return t.Time
+						}, BeTemporally("==", finishedAt)),
+					})),
+				}))
+			}).Should(Succeed())
+
+			Consistently(func(g Gomega) {
+				g.Expect(crashEvents(g)).To(HaveLen(1))
+			}, "1s").Should(Succeed())
+		})
+	})
+
+	When("the application container has never restarted", func() {
+		BeforeEach(func() {
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "application",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+					Ready: true,
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		})
+
+		It("does not record a crash audit event", func() {
+			Consistently(func(g Gomega) {
+				g.Expect(crashEvents(g)).To(BeEmpty())
+			}, "1s").Should(Succeed())
+		})
+	})
+
+	When("the pod becomes ready", func() {
+		var startsBefore uint64
+
+		BeforeEach(func() {
+			startsBefore = appInstanceStartCount("web")
+
+			pod.Status = corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(time.Second)),
+				}},
+			}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		})
+
+		It("records the start duration of the instance once", func() {
+			Eventually(func() uint64 { return appInstanceStartCount("web") }).Should(Equal(startsBefore + 1))
+			Consistently(func() uint64 { return appInstanceStartCount("web") }, "1s").Should(Equal(startsBefore + 1))
+		})
+	})
+})
+
+func appInstanceStartCount(processType string) uint64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "korifi_controllers_app_instance_start_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "process_type" && label.GetValue() == processType {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+
+	return 0
+}
@@ -2,12 +2,15 @@ package workloads
 
 import (
 	"context"
+	"fmt"
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -194,3 +197,58 @@ func getNamespace(ctx context.Context, log logr.Logger, client client.Client, na
 	}
 	return namespace, true
 }
+
+// isolationSegmentPlacement returns the scheduling constraints of the isolation segment assigned to the space
+// backing spaceNamespace, or nil when the space runs on the shared segment. Spaces assigned to a segment that has
+// been deleted fall back to the shared segment
+func isolationSegmentPlacement(ctx context.Context, kClient client.Client, rootNamespace, spaceNamespace string) (*korifiv1alpha1.IsolationSegmentPlacement, error) {
+	space, err := shared.FindSpace(ctx, kClient, spaceNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if space == nil || space.Spec.IsolationSegmentRef.Name == "" {
+		return nil, nil
+	}
+
+	segmentName := space.Spec.IsolationSegmentRef.Name
+	segment := new(korifiv1alpha1.CFIsolationSegment)
+	err = kClient.Get(ctx, types.NamespacedName{Namespace: rootNamespace, Name: segmentName}, segment)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get isolation segment %s/%s: %w", rootNamespace, segmentName, err)
+	}
+
+	return &korifiv1alpha1.IsolationSegmentPlacement{
+		Name:         segment.Name,
+		NodeSelector: segment.Spec.NodeSelector,
+		Tolerations:  segment.Spec.Tolerations,
+	}, nil
+}
+
+// isolationSegmentNamespaces returns the namespaces of the spaces whose workloads are placed by the given CFSpace or
+// CFIsolationSegment, so that workloads move when spaces are assigned to segments or segments change
+func isolationSegmentNamespaces(ctx context.Context, kClient client.Client, obj client.Object) ([]string, error) {
+	switch obj.(type) {
+	case *korifiv1alpha1.CFSpace:
+		return []string{obj.GetName()}, nil
+	case *korifiv1alpha1.CFIsolationSegment:
+		spaceList := &korifiv1alpha1.CFSpaceList{}
+		if err := kClient.List(ctx, spaceList); err != nil {
+			return nil, err
+		}
+
+		var namespaces []string
+		for _, space := range spaceList.Items {
+			if space.Spec.IsolationSegmentRef.Name == obj.GetName() {
+				namespaces = append(namespaces, space.Name)
+			}
+		}
+
+		return namespaces, nil
+	default:
+		return nil, nil
+	}
+}
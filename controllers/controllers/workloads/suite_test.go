@@ -24,6 +24,7 @@ import (
 	k8sclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -85,6 +86,11 @@ var _ = BeforeSuite(func() {
 		CertDir:            webhookInstallOptions.LocalServingCertDir,
 		LeaderElection:     false,
 		MetricsBindAddress: "0",
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Pod{}: {Label: AppPodSelector()},
+			},
+		}),
 	})
 	Expect(err).NotTo(HaveOccurred())
 
@@ -107,7 +113,8 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetClient(),
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFApp"),
-		env.NewBuilder(k8sManager.GetClient()),
+		env.NewBuilder(k8sManager.GetClient(), cfRootNamespace),
+		cfRootNamespace,
 	)).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -119,7 +126,7 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFBuild"),
 		controllerConfig,
-		env.NewBuilder(k8sManager.GetClient()),
+		env.NewBuilder(k8sManager.GetClient(), cfRootNamespace),
 	)
 	err = (cfBuildReconciler).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
@@ -129,7 +136,7 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetScheme(),
 		ctrl.Log.WithName("controllers").WithName("CFProcess"),
 		controllerConfig,
-		env.NewBuilder(k8sManager.GetClient()),
+		env.NewBuilder(k8sManager.GetClient(), cfRootNamespace),
 	)).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -153,8 +160,16 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetScheme(),
 		k8sManager.GetEventRecorderFor("cftask-controller"),
 		ctrl.Log.WithName("controllers").WithName("CFTask"),
-		env.NewBuilder(k8sManager.GetClient()),
+		env.NewBuilder(k8sManager.GetClient(), cfRootNamespace),
+		cfRootNamespace,
 		2*time.Second,
+		controllerConfig.CFProcessDefaults.MemoryMB,
+	).SetupWithManager(k8sManager)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = NewPodCrashReconciler(
+		k8sManager.GetClient(),
+		ctrl.Log.WithName("controllers").WithName("PodCrash"),
 	).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -34,15 +35,18 @@ import (
 	"code.cloudfoundry.org/korifi/controllers/webhooks/networking"
 	"code.cloudfoundry.org/korifi/controllers/webhooks/services"
 	"code.cloudfoundry.org/korifi/controllers/webhooks/workloads"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	contourv1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	servicebindingv1beta1 "github.com/servicebinding/service-binding-controller/apis/v1beta1"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -101,6 +105,11 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "13c200ec.cloudfoundry.org",
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Pod{}: {Label: workloadscontrollers.AppPodSelector()},
+			},
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -118,6 +127,11 @@ func main() {
 		panic(errorMessage)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "korifi-controllers", controllerConfig.Tracing)
+	if err != nil {
+		panic(fmt.Sprintf("could not set up tracing: %v", err))
+	}
+
 	// Setup with manager
 
 	if os.Getenv("ENABLE_CONTROLLERS") != "false" {
@@ -125,7 +139,8 @@ func main() {
 			mgr.GetClient(),
 			mgr.GetScheme(),
 			ctrl.Log.WithName("controllers").WithName("CFApp"),
-			env.NewBuilder(mgr.GetClient()),
+			env.NewBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+			controllerConfig.CFRootNamespace,
 		)).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFApp")
 			os.Exit(1)
@@ -136,7 +151,7 @@ func main() {
 			mgr.GetScheme(),
 			ctrl.Log.WithName("controllers").WithName("CFBuild"),
 			controllerConfig,
-			env.NewBuilder(mgr.GetClient()),
+			env.NewBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
 		)).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFBuild")
 			os.Exit(1)
@@ -156,7 +171,7 @@ func main() {
 			mgr.GetScheme(),
 			ctrl.Log.WithName("controllers").WithName("CFProcess"),
 			controllerConfig,
-			env.NewBuilder(mgr.GetClient()),
+			env.NewBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
 		)).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFProcess")
 			os.Exit(1)
@@ -223,12 +238,37 @@ func main() {
 			mgr.GetScheme(),
 			mgr.GetEventRecorderFor("cftask-controller"),
 			ctrl.Log.WithName("controllers").WithName("CFTask"),
-			env.NewBuilder(mgr.GetClient()),
+			env.NewBuilder(mgr.GetClient(), controllerConfig.CFRootNamespace),
+			controllerConfig.CFRootNamespace,
 			taskTTL,
+			controllerConfig.CFProcessDefaults.MemoryMB,
 		).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CFTask")
 			os.Exit(1)
 		}
+
+		var auditEventRetention time.Duration
+		auditEventRetention, err = controllerConfig.ParseAuditEventRetention()
+		if err != nil {
+			setupLog.Error(err, "failed to parse audit event retention", "controller", "CFAuditEvent", "auditEventRetention", controllerConfig.AuditEventRetention)
+			os.Exit(1)
+		}
+		if err = workloadscontrollers.NewCFAuditEventReconciler(
+			mgr.GetClient(),
+			ctrl.Log.WithName("controllers").WithName("CFAuditEvent"),
+			auditEventRetention,
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CFAuditEvent")
+			os.Exit(1)
+		}
+
+		if err = workloadscontrollers.NewPodCrashReconciler(
+			mgr.GetClient(),
+			ctrl.Log.WithName("controllers").WithName("PodCrash"),
+		).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PodCrash")
+			os.Exit(1)
+		}
 		//+kubebuilder:scaffold:builder
 
 		// Setup Index with Manager
@@ -242,6 +282,8 @@ func main() {
 	// Setup webhooks with manager
 
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		quotaValidator := webhooks.NewQuotaUsageValidator(mgr.GetClient(), controllerConfig.CFRootNamespace, controllerConfig.CFProcessDefaults.MemoryMB)
+
 		if err = (&korifiv1alpha1.CFApp{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFApp")
 			os.Exit(1)
@@ -265,8 +307,15 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = workloads.NewCFProcessValidator(quotaValidator).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CFProcess")
+			os.Exit(1)
+		}
+
 		if err = workloads.NewCFAppValidator(
 			webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), workloads.AppEntityType)),
+			quotaValidator,
+			mgr.GetClient(),
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFApp")
 			os.Exit(1)
@@ -274,6 +323,7 @@ func main() {
 
 		if err = networking.NewCFRouteValidator(
 			webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), networking.RouteEntityType)),
+			quotaValidator,
 			controllerConfig.CFRootNamespace,
 			mgr.GetClient(),
 		).SetupWebhookWithManager(mgr); err != nil {
@@ -283,6 +333,7 @@ func main() {
 
 		if err = services.NewCFServiceInstanceValidator(
 			webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), services.ServiceInstanceEntityType)),
+			quotaValidator,
 		).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFServiceInstance")
 			os.Exit(1)
@@ -328,7 +379,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		if err = workloads.NewCFTaskValidator().SetupWebhookWithManager(mgr); err != nil {
+		if err = workloads.NewCFTaskValidator(quotaValidator).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "CFTask")
 			os.Exit(1)
 		}
@@ -351,4 +402,8 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem shutting down tracing")
+	}
 }
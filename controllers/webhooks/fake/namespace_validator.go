@@ -2,6 +2,7 @@
 package fake
 
 import (
+	"context"
 	"sync"
 
 	"code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
@@ -20,6 +21,19 @@ type NamespaceValidator struct {
 	validateOrgCreateReturnsOnCall map[int]struct {
 		result1 *webhooks.ValidationError
 	}
+	ValidateSpaceAssignmentsStub        func(context.Context, v1alpha1.CFSpace, v1alpha1.CFSpace) *webhooks.ValidationError
+	validateSpaceAssignmentsMutex       sync.RWMutex
+	validateSpaceAssignmentsArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1alpha1.CFSpace
+		arg3 v1alpha1.CFSpace
+	}
+	validateSpaceAssignmentsReturns struct {
+		result1 *webhooks.ValidationError
+	}
+	validateSpaceAssignmentsReturnsOnCall map[int]struct {
+		result1 *webhooks.ValidationError
+	}
 	ValidateSpaceCreateStub        func(v1alpha1.CFSpace) *webhooks.ValidationError
 	validateSpaceCreateMutex       sync.RWMutex
 	validateSpaceCreateArgsForCall []struct {
@@ -96,6 +110,69 @@ func (fake *NamespaceValidator) ValidateOrgCreateReturnsOnCall(i int, result1 *w
 	}{result1}
 }
 
+func (fake *NamespaceValidator) ValidateSpaceAssignments(arg1 context.Context, arg2 v1alpha1.CFSpace, arg3 v1alpha1.CFSpace) *webhooks.ValidationError {
+	fake.validateSpaceAssignmentsMutex.Lock()
+	ret, specificReturn := fake.validateSpaceAssignmentsReturnsOnCall[len(fake.validateSpaceAssignmentsArgsForCall)]
+	fake.validateSpaceAssignmentsArgsForCall = append(fake.validateSpaceAssignmentsArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1alpha1.CFSpace
+		arg3 v1alpha1.CFSpace
+	}{arg1, arg2, arg3})
+	stub := fake.ValidateSpaceAssignmentsStub
+	fakeReturns := fake.validateSpaceAssignmentsReturns
+	fake.recordInvocation("ValidateSpaceAssignments", []interface{}{arg1, arg2, arg3})
+	fake.validateSpaceAssignmentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *NamespaceValidator) ValidateSpaceAssignmentsCallCount() int {
+	fake.validateSpaceAssignmentsMutex.RLock()
+	defer fake.validateSpaceAssignmentsMutex.RUnlock()
+	return len(fake.validateSpaceAssignmentsArgsForCall)
+}
+
+func (fake *NamespaceValidator) ValidateSpaceAssignmentsCalls(stub func(context.Context, v1alpha1.CFSpace, v1alpha1.CFSpace) *webhooks.ValidationError) {
+	fake.validateSpaceAssignmentsMutex.Lock()
+	defer fake.validateSpaceAssignmentsMutex.Unlock()
+	fake.ValidateSpaceAssignmentsStub = stub
+}
+
+func (fake *NamespaceValidator) ValidateSpaceAssignmentsArgsForCall(i int) (context.Context, v1alpha1.CFSpace, v1alpha1.CFSpace) {
+	fake.validateSpaceAssignmentsMutex.RLock()
+	defer fake.validateSpaceAssignmentsMutex.RUnlock()
+	argsForCall := fake.validateSpaceAssignmentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *NamespaceValidator) ValidateSpaceAssignmentsReturns(result1 *webhooks.ValidationError) {
+	fake.validateSpaceAssignmentsMutex.Lock()
+	defer fake.validateSpaceAssignmentsMutex.Unlock()
+	fake.ValidateSpaceAssignmentsStub = nil
+	fake.validateSpaceAssignmentsReturns = struct {
+		result1 *webhooks.ValidationError
+	}{result1}
+}
+
+func (fake *NamespaceValidator) ValidateSpaceAssignmentsReturnsOnCall(i int, result1 *webhooks.ValidationError) {
+	fake.validateSpaceAssignmentsMutex.Lock()
+	defer fake.validateSpaceAssignmentsMutex.Unlock()
+	fake.ValidateSpaceAssignmentsStub = nil
+	if fake.validateSpaceAssignmentsReturnsOnCall == nil {
+		fake.validateSpaceAssignmentsReturnsOnCall = make(map[int]struct {
+			result1 *webhooks.ValidationError
+		})
+	}
+	fake.validateSpaceAssignmentsReturnsOnCall[i] = struct {
+		result1 *webhooks.ValidationError
+	}{result1}
+}
+
 func (fake *NamespaceValidator) ValidateSpaceCreate(arg1 v1alpha1.CFSpace) *webhooks.ValidationError {
 	fake.validateSpaceCreateMutex.Lock()
 	ret, specificReturn := fake.validateSpaceCreateReturnsOnCall[len(fake.validateSpaceCreateArgsForCall)]
@@ -162,6 +239,8 @@ func (fake *NamespaceValidator) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.validateOrgCreateMutex.RLock()
 	defer fake.validateOrgCreateMutex.RUnlock()
+	fake.validateSpaceAssignmentsMutex.RLock()
+	defer fake.validateSpaceAssignmentsMutex.RUnlock()
 	fake.validateSpaceCreateMutex.RLock()
 	defer fake.validateSpaceCreateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
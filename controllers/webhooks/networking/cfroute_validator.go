@@ -47,6 +47,7 @@ var logger = logf.Log.WithName("route-validation")
 
 type CFRouteValidator struct {
 	duplicateValidator webhooks.NameValidator
+	quotaValidator     webhooks.QuotaValidator
 	rootNamespace      string
 	client             client.Client
 }
@@ -55,11 +56,13 @@ var _ webhook.CustomValidator = &CFRouteValidator{}
 
 func NewCFRouteValidator(
 	nameValidator webhooks.NameValidator,
+	quotaValidator webhooks.QuotaValidator,
 	rootNamespace string,
 	client client.Client,
 ) *CFRouteValidator {
 	return &CFRouteValidator{
 		duplicateValidator: nameValidator,
+		quotaValidator:     quotaValidator,
 		rootNamespace:      rootNamespace,
 		client:             client,
 	}
@@ -83,6 +86,10 @@ func (v *CFRouteValidator) ValidateCreate(ctx context.Context, obj runtime.Objec
 		return err
 	}
 
+	if validationErr := v.quotaValidator.ValidateRouteCreate(ctx, *route); validationErr != nil {
+		return validationErr.ExportJSONError()
+	}
+
 	duplicateErrorMessage := generateDuplicateErrorMessage(route, domain)
 	validationErr := v.duplicateValidator.ValidateCreate(ctx, logger, v.rootNamespace, uniqueName(*route), duplicateErrorMessage)
 	if validationErr != nil {
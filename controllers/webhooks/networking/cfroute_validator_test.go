@@ -29,6 +29,7 @@ var _ = Describe("CFRouteValidator", func() {
 	var (
 		ctx                context.Context
 		duplicateValidator *fake.NameValidator
+		quotaValidator     *fake.QuotaValidator
 		fakeClient         *controllerfake.Client
 		cfRoute            *korifiv1alpha1.CFRoute
 		cfDomain           *korifiv1alpha1.CFDomain
@@ -98,7 +99,8 @@ var _ = Describe("CFRouteValidator", func() {
 			}
 		}
 
-		validatingWebhook = networking.NewCFRouteValidator(duplicateValidator, rootNamespace, fakeClient)
+		quotaValidator = new(fake.QuotaValidator)
+		validatingWebhook = networking.NewCFRouteValidator(duplicateValidator, quotaValidator, rootNamespace, fakeClient)
 	})
 
 	Describe("ValidateCreate", func() {
@@ -118,6 +120,33 @@ var _ = Describe("CFRouteValidator", func() {
 			Expect(name).To(Equal(testRouteHost + "::" + testDomainNamespace + "::" + testDomainGUID + "::" + testRoutePath))
 		})
 
+		It("invokes the quota validator correctly", func() {
+			Expect(quotaValidator.ValidateRouteCreateCallCount()).To(Equal(1))
+			actualContext, actualRoute := quotaValidator.ValidateRouteCreateArgsForCall(0)
+			Expect(actualContext).To(Equal(ctx))
+			Expect(actualRoute.Name).To(Equal(testRouteGUID))
+		})
+
+		When("the route would exceed the quota", func() {
+			BeforeEach(func() {
+				quotaValidator.ValidateRouteCreateReturns(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceRoutesLimitExceededErrorMessage,
+				})
+			})
+
+			It("denies the request", func() {
+				Expect(retErr).To(matchers.BeValidationError(
+					webhooks.QuotaExceededErrorType,
+					Equal(webhooks.SpaceRoutesLimitExceededErrorMessage),
+				))
+			})
+
+			It("does not reserve the route name", func() {
+				Expect(duplicateValidator.ValidateCreateCallCount()).To(Equal(0))
+			})
+		})
+
 		When("the host is '*'", func() {
 			BeforeEach(func() {
 				cfRoute.Spec.Host = "*"
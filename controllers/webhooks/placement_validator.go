@@ -6,15 +6,23 @@ import (
 
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cfisolationsegments,verbs=get
+
 const (
 	OrgPlacementErrorType      = "OrgPlacementError"
 	OrgPlacementErrorMessage   = "Organization '%s' must be placed in the root 'cf' namespace"
 	SpacePlacementErrorType    = "SpacePlacementError"
 	SpacePlacementErrorMessage = "Organization '%s' does not exist for Space '%s'"
+
+	IsolationSegmentNotEntitledErrorType    = "IsolationSegmentNotEntitledError"
+	IsolationSegmentNotEntitledErrorMessage = "Unable to assign isolation segment with guid '%s'. Ensure it has been entitled to the organization that this space belongs to."
+	SpaceQuotaNotInOrgErrorType             = "SpaceQuotaNotInOrgError"
+	SpaceQuotaNotInOrgErrorMessage          = "Space quota with guid '%s' does not exist in the organization that this space belongs to."
 )
 
 type PlacementValidator struct {
@@ -50,3 +58,50 @@ func (v PlacementValidator) ValidateSpaceCreate(space korifiv1alpha1.CFSpace) *V
 
 	return nil
 }
+
+// ValidateSpaceAssignments checks that the isolation segment and the space quota a space refers to belong to its org.
+// Only references that change are checked, so that spaces keep working when an entitlement is revoked behind their back
+func (v PlacementValidator) ValidateSpaceAssignments(ctx context.Context, oldSpace, space korifiv1alpha1.CFSpace) *ValidationError {
+	segmentName := space.Spec.IsolationSegmentRef.Name
+	if segmentName != "" && segmentName != oldSpace.Spec.IsolationSegmentRef.Name {
+		segment := korifiv1alpha1.CFIsolationSegment{}
+		err := v.client.Get(ctx, types.NamespacedName{Name: segmentName, Namespace: v.rootNamespace}, &segment)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return &ValidationError{Type: UnknownErrorType, Message: UnknownErrorMessage}
+		}
+
+		if err != nil || !isEntitled(segment, space.Namespace) {
+			return &ValidationError{
+				Type:    IsolationSegmentNotEntitledErrorType,
+				Message: fmt.Sprintf(IsolationSegmentNotEntitledErrorMessage, segmentName),
+			}
+		}
+	}
+
+	quotaName := space.Spec.QuotaRef.Name
+	if quotaName != "" && quotaName != oldSpace.Spec.QuotaRef.Name {
+		err := v.client.Get(ctx, types.NamespacedName{Name: quotaName, Namespace: space.Namespace}, &korifiv1alpha1.CFSpaceQuota{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return &ValidationError{Type: UnknownErrorType, Message: UnknownErrorMessage}
+		}
+
+		if err != nil {
+			return &ValidationError{
+				Type:    SpaceQuotaNotInOrgErrorType,
+				Message: fmt.Sprintf(SpaceQuotaNotInOrgErrorMessage, quotaName),
+			}
+		}
+	}
+
+	return nil
+}
+
+func isEntitled(segment korifiv1alpha1.CFIsolationSegment, orgGUID string) bool {
+	for _, entitledOrgGUID := range segment.Spec.Organizations {
+		if entitledOrgGUID == orgGUID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,6 +1,7 @@
 package webhooks_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -11,8 +12,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("CFPlacementValidation", func() {
@@ -107,4 +112,144 @@ var _ = Describe("CFPlacementValidation", func() {
 			})
 		})
 	})
+
+	Describe("ValidateSpaceAssignments", func() {
+		var (
+			oldSpace korifiv1alpha1.CFSpace
+			segment  *korifiv1alpha1.CFIsolationSegment
+			getErr   error
+		)
+
+		BeforeEach(func() {
+			space = korifiv1alpha1.CFSpace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-space",
+					Namespace: "org-ns",
+				},
+				Spec: korifiv1alpha1.CFSpaceSpec{
+					DisplayName:         "test-space-display-name",
+					IsolationSegmentRef: corev1.LocalObjectReference{Name: "segment-guid"},
+				},
+			}
+			oldSpace = korifiv1alpha1.CFSpace{}
+
+			segment = &korifiv1alpha1.CFIsolationSegment{
+				Spec: korifiv1alpha1.CFIsolationSegmentSpec{
+					Organizations: []string{"other-org-ns", "org-ns"},
+				},
+			}
+			getErr = nil
+
+			fakeClient.GetStub = func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+				if getErr != nil {
+					return getErr
+				}
+
+				if s, ok := obj.(*korifiv1alpha1.CFIsolationSegment); ok {
+					segment.DeepCopyInto(s)
+				}
+
+				return nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = placementValidator.ValidateSpaceAssignments(context.Background(), oldSpace, space)
+		})
+
+		It("succeeds", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		It("gets the isolation segment from the root namespace", func() {
+			Expect(fakeClient.GetCallCount()).To(Equal(1))
+			_, key, _, _ := fakeClient.GetArgsForCall(0)
+			Expect(key).To(Equal(types.NamespacedName{Namespace: rootNamespace, Name: "segment-guid"}))
+		})
+
+		When("the isolation segment is not entitled to the org of the space", func() {
+			BeforeEach(func() {
+				segment.Spec.Organizations = []string{"other-org-ns"}
+			})
+
+			It("fails", func() {
+				Expect(*validationErr).To(MatchError(webhooks.ValidationError{
+					Type:    webhooks.IsolationSegmentNotEntitledErrorType,
+					Message: fmt.Sprintf(webhooks.IsolationSegmentNotEntitledErrorMessage, "segment-guid"),
+				}))
+			})
+
+			When("the space already refers to the isolation segment", func() {
+				BeforeEach(func() {
+					oldSpace = *space.DeepCopy()
+				})
+
+				It("succeeds without getting the isolation segment", func() {
+					Expect(validationErr).To(BeNil())
+					Expect(fakeClient.GetCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		When("the isolation segment does not exist", func() {
+			BeforeEach(func() {
+				getErr = k8serrors.NewNotFound(korifiv1alpha1.GroupVersion.WithResource("cfisolationsegments").GroupResource(), "segment-guid")
+			})
+
+			It("fails", func() {
+				Expect(validationErr).NotTo(BeNil())
+				Expect(validationErr.Type).To(Equal(webhooks.IsolationSegmentNotEntitledErrorType))
+			})
+		})
+
+		When("getting the isolation segment fails", func() {
+			BeforeEach(func() {
+				getErr = errors.New("boom")
+			})
+
+			It("returns an unknown error", func() {
+				Expect(validationErr).NotTo(BeNil())
+				Expect(validationErr.Type).To(Equal(webhooks.UnknownErrorType))
+			})
+		})
+
+		When("the space is reset to the shared isolation segment", func() {
+			BeforeEach(func() {
+				space.Spec.IsolationSegmentRef.Name = ""
+			})
+
+			It("succeeds without getting the isolation segment", func() {
+				Expect(validationErr).To(BeNil())
+				Expect(fakeClient.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("a space quota is assigned", func() {
+			BeforeEach(func() {
+				oldSpace = *space.DeepCopy()
+				space.Spec.QuotaRef.Name = "quota-guid"
+			})
+
+			It("gets the space quota from the org namespace", func() {
+				Expect(validationErr).To(BeNil())
+				Expect(fakeClient.GetCallCount()).To(Equal(1))
+				_, key, obj, _ := fakeClient.GetArgsForCall(0)
+				Expect(key).To(Equal(types.NamespacedName{Namespace: "org-ns", Name: "quota-guid"}))
+				Expect(obj).To(BeAssignableToTypeOf(&korifiv1alpha1.CFSpaceQuota{}))
+			})
+
+			When("the space quota does not exist in the org", func() {
+				BeforeEach(func() {
+					getErr = k8serrors.NewNotFound(korifiv1alpha1.GroupVersion.WithResource("cfspacequotas").GroupResource(), "quota-guid")
+				})
+
+				It("fails", func() {
+					Expect(*validationErr).To(MatchError(webhooks.ValidationError{
+						Type:    webhooks.SpaceQuotaNotInOrgErrorType,
+						Message: fmt.Sprintf(webhooks.SpaceQuotaNotInOrgErrorMessage, "quota-guid"),
+					}))
+				})
+			})
+		})
+	})
 })
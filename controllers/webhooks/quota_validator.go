@@ -0,0 +1,456 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/controllers/shared"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//+kubebuilder:rbac:groups=korifi.cloudfoundry.org,resources=cforgquotas;cfspacequotas,verbs=get;list;watch
+
+const (
+	QuotaExceededErrorType = "QuotaExceededError"
+
+	OrgMemoryQuotaExceededErrorMessage           = "You have exceeded your organization's memory limit: app requested more memory than available"
+	SpaceMemoryQuotaExceededErrorMessage         = "You have exceeded your space's memory limit: app requested more memory than available"
+	OrgInstanceMemoryLimitExceededErrorMessage   = "You have exceeded the instance memory limit for your organization's quota."
+	SpaceInstanceMemoryLimitExceededErrorMessage = "You have exceeded the instance memory limit for your space's quota."
+	OrgInstanceLimitExceededErrorMessage         = "You have exceeded the instance limit for your organization's quota."
+	SpaceInstanceLimitExceededErrorMessage       = "You have exceeded the instance limit for your space's quota."
+	OrgAppTasksLimitExceededErrorMessage         = "You have exceeded the maximum number of running tasks per app for your organization's quota."
+	SpaceAppTasksLimitExceededErrorMessage       = "You have exceeded the maximum number of running tasks per app for your space's quota."
+	OrgRoutesLimitExceededErrorMessage           = "You have exceeded the total routes for your organization's quota."
+	SpaceRoutesLimitExceededErrorMessage         = "You have exceeded the total routes for your space's quota."
+	OrgServicesLimitExceededErrorMessage         = "You have exceeded your organization's services limit."
+	SpaceServicesLimitExceededErrorMessage       = "You have exceeded your space's services limit."
+)
+
+var quotaLogger = logf.Log.WithName("quota-validator")
+
+// QuotaUsageValidator checks that creating or updating a resource in a space
+// does not exceed the limits of the quotas assigned to the space and its org.
+// Only processes of started apps and tasks that have not completed count
+// towards the memory and instance totals.
+type QuotaUsageValidator struct {
+	client              client.Client
+	rootNamespace       string
+	defaultTaskMemoryMB int64
+}
+
+func NewQuotaUsageValidator(client client.Client, rootNamespace string, defaultTaskMemoryMB int64) *QuotaUsageValidator {
+	return &QuotaUsageValidator{
+		client:              client,
+		rootNamespace:       rootNamespace,
+		defaultTaskMemoryMB: defaultTaskMemoryMB,
+	}
+}
+
+type appsUsage struct {
+	memoryMB  int64
+	instances int64
+}
+
+type quotaScope struct {
+	limits     quotaLimits
+	namespaces []string
+
+	memoryExceeded         string
+	instanceMemoryExceeded string
+	instancesExceeded      string
+	appTasksExceeded       string
+	routesExceeded         string
+	servicesExceeded       string
+}
+
+type quotaLimits struct {
+	apps     korifiv1alpha1.QuotaAppsLimits
+	services korifiv1alpha1.QuotaServicesLimits
+	routes   korifiv1alpha1.QuotaRoutesLimits
+}
+
+// ValidateProcess checks that a process of a started app fits into the quotas. Processes of stopped apps do not count
+// towards the quotas, so they are only checked once their app is started, see ValidateAppStart
+func (v QuotaUsageValidator) ValidateProcess(ctx context.Context, process korifiv1alpha1.CFProcess) *ValidationError {
+	app := &korifiv1alpha1.CFApp{}
+	err := v.client.Get(ctx, client.ObjectKey{Namespace: process.Namespace, Name: process.Spec.AppRef.Name}, app)
+	if client.IgnoreNotFound(err) != nil {
+		return unknownError(fmt.Errorf("failed to get app %q: %w", process.Spec.AppRef.Name, err))
+	}
+	if err != nil || app.Spec.DesiredState != korifiv1alpha1.StartedState {
+		return nil
+	}
+
+	instances := int64(0)
+	if process.Spec.DesiredInstances != nil {
+		instances = int64(*process.Spec.DesiredInstances)
+	}
+
+	return v.validate(ctx, process.Namespace, func(scope quotaScope) *ValidationError {
+		if exceeds(scope.limits.apps.PerProcessMemoryInMB, process.Spec.MemoryMB) {
+			return quotaExceededError(scope.instanceMemoryExceeded)
+		}
+
+		usage, err := v.appsUsage(ctx, scope.namespaces, func(p korifiv1alpha1.CFProcess) bool {
+			return p.Namespace == process.Namespace && p.Name == process.Name
+		})
+		if err != nil {
+			return unknownError(err)
+		}
+
+		if exceeds(scope.limits.apps.TotalInstances, usage.instances+instances) {
+			return quotaExceededError(scope.instancesExceeded)
+		}
+
+		if exceeds(scope.limits.apps.TotalMemoryInMB, usage.memoryMB+instances*process.Spec.MemoryMB) {
+			return quotaExceededError(scope.memoryExceeded)
+		}
+
+		return nil
+	})
+}
+
+// ValidateAppStart checks that the processes of an app that is being started fit into the quotas. Processes of
+// stopped apps do not count towards the quotas, so they may have been scaled beyond them while the app was stopped
+func (v QuotaUsageValidator) ValidateAppStart(ctx context.Context, app korifiv1alpha1.CFApp) *ValidationError {
+	processes := &korifiv1alpha1.CFProcessList{}
+	if err := v.client.List(ctx, processes, client.InNamespace(app.Namespace)); err != nil {
+		return unknownError(fmt.Errorf("failed to list processes in namespace %q: %w", app.Namespace, err))
+	}
+
+	isAppProcess := func(p korifiv1alpha1.CFProcess) bool {
+		return p.Namespace == app.Namespace && p.Spec.AppRef.Name == app.Name
+	}
+
+	return v.validate(ctx, app.Namespace, func(scope quotaScope) *ValidationError {
+		appUsage := appsUsage{}
+		for _, process := range processes.Items {
+			if !isAppProcess(process) || process.Spec.DesiredInstances == nil || *process.Spec.DesiredInstances == 0 {
+				continue
+			}
+
+			if exceeds(scope.limits.apps.PerProcessMemoryInMB, process.Spec.MemoryMB) {
+				return quotaExceededError(scope.instanceMemoryExceeded)
+			}
+
+			instances := int64(*process.Spec.DesiredInstances)
+			appUsage.instances += instances
+			appUsage.memoryMB += instances * process.Spec.MemoryMB
+		}
+
+		usage, err := v.appsUsage(ctx, scope.namespaces, isAppProcess)
+		if err != nil {
+			return unknownError(err)
+		}
+
+		if exceeds(scope.limits.apps.TotalInstances, usage.instances+appUsage.instances) {
+			return quotaExceededError(scope.instancesExceeded)
+		}
+
+		if exceeds(scope.limits.apps.TotalMemoryInMB, usage.memoryMB+appUsage.memoryMB) {
+			return quotaExceededError(scope.memoryExceeded)
+		}
+
+		return nil
+	})
+}
+
+func (v QuotaUsageValidator) ValidateTaskCreate(ctx context.Context, task korifiv1alpha1.CFTask) *ValidationError {
+	return v.validate(ctx, task.Namespace, func(scope quotaScope) *ValidationError {
+		// the status of a new task is reset by the API server before it is validated, so unlike running tasks it
+		// is not known yet and the task is assumed to get the default memory
+		memoryMB := v.taskMemoryMB(task)
+
+		if exceeds(scope.limits.apps.PerProcessMemoryInMB, memoryMB) {
+			return quotaExceededError(scope.instanceMemoryExceeded)
+		}
+
+		if scope.limits.apps.PerAppTasks != nil {
+			appTasks, err := v.runningTasks(ctx, []string{task.Namespace}, task.Spec.AppRef.Name)
+			if err != nil {
+				return unknownError(err)
+			}
+
+			if exceeds(scope.limits.apps.PerAppTasks, int64(len(appTasks))+1) {
+				return quotaExceededError(scope.appTasksExceeded)
+			}
+		}
+
+		usage, err := v.appsUsage(ctx, scope.namespaces, func(korifiv1alpha1.CFProcess) bool { return false })
+		if err != nil {
+			return unknownError(err)
+		}
+
+		if exceeds(scope.limits.apps.TotalMemoryInMB, usage.memoryMB+memoryMB) {
+			return quotaExceededError(scope.memoryExceeded)
+		}
+
+		return nil
+	})
+}
+
+func (v QuotaUsageValidator) ValidateRouteCreate(ctx context.Context, route korifiv1alpha1.CFRoute) *ValidationError {
+	return v.validate(ctx, route.Namespace, func(scope quotaScope) *ValidationError {
+		if scope.limits.routes.TotalRoutes == nil {
+			return nil
+		}
+
+		count, err := v.count(ctx, scope.namespaces, &korifiv1alpha1.CFRouteList{})
+		if err != nil {
+			return unknownError(err)
+		}
+
+		if exceeds(scope.limits.routes.TotalRoutes, count+1) {
+			return quotaExceededError(scope.routesExceeded)
+		}
+
+		return nil
+	})
+}
+
+func (v QuotaUsageValidator) ValidateServiceInstanceCreate(ctx context.Context, serviceInstance korifiv1alpha1.CFServiceInstance) *ValidationError {
+	return v.validate(ctx, serviceInstance.Namespace, func(scope quotaScope) *ValidationError {
+		if scope.limits.services.TotalServiceInstances == nil {
+			return nil
+		}
+
+		count, err := v.count(ctx, scope.namespaces, &korifiv1alpha1.CFServiceInstanceList{})
+		if err != nil {
+			return unknownError(err)
+		}
+
+		if exceeds(scope.limits.services.TotalServiceInstances, count+1) {
+			return quotaExceededError(scope.servicesExceeded)
+		}
+
+		return nil
+	})
+}
+
+// validate runs the check function against the space quota and then the org
+// quota of the space living in spaceNamespace. Resources created in
+// namespaces that do not belong to a space are never limited.
+func (v QuotaUsageValidator) validate(ctx context.Context, spaceNamespace string, check func(quotaScope) *ValidationError) *ValidationError {
+	space, err := shared.FindSpace(ctx, v.client, spaceNamespace)
+	if err != nil {
+		return unknownError(err)
+	}
+	if space == nil {
+		return nil
+	}
+
+	scopes, err := v.quotaScopes(ctx, *space)
+	if err != nil {
+		return unknownError(err)
+	}
+
+	for _, scope := range scopes {
+		if validationErr := check(scope); validationErr != nil {
+			return validationErr
+		}
+	}
+
+	return nil
+}
+
+func (v QuotaUsageValidator) quotaScopes(ctx context.Context, space korifiv1alpha1.CFSpace) ([]quotaScope, error) {
+	var scopes []quotaScope
+
+	if space.Spec.QuotaRef.Name != "" {
+		spaceQuota := &korifiv1alpha1.CFSpaceQuota{}
+		err := v.client.Get(ctx, types.NamespacedName{Namespace: space.Namespace, Name: space.Spec.QuotaRef.Name}, spaceQuota)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get space quota %q: %w", space.Spec.QuotaRef.Name, err)
+		}
+
+		if err == nil {
+			scopes = append(scopes, quotaScope{
+				limits: quotaLimits{
+					apps:     spaceQuota.Spec.Apps,
+					services: spaceQuota.Spec.Services,
+					routes:   spaceQuota.Spec.Routes,
+				},
+				namespaces:             []string{space.Name},
+				memoryExceeded:         SpaceMemoryQuotaExceededErrorMessage,
+				instanceMemoryExceeded: SpaceInstanceMemoryLimitExceededErrorMessage,
+				instancesExceeded:      SpaceInstanceLimitExceededErrorMessage,
+				appTasksExceeded:       SpaceAppTasksLimitExceededErrorMessage,
+				routesExceeded:         SpaceRoutesLimitExceededErrorMessage,
+				servicesExceeded:       SpaceServicesLimitExceededErrorMessage,
+			})
+		}
+	}
+
+	org := &korifiv1alpha1.CFOrg{}
+	err := v.client.Get(ctx, types.NamespacedName{Namespace: v.rootNamespace, Name: space.Namespace}, org)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return scopes, nil
+		}
+		return nil, fmt.Errorf("failed to get org %q: %w", space.Namespace, err)
+	}
+
+	if org.Spec.QuotaRef.Name == "" {
+		return scopes, nil
+	}
+
+	orgQuota := &korifiv1alpha1.CFOrgQuota{}
+	err = v.client.Get(ctx, types.NamespacedName{Namespace: v.rootNamespace, Name: org.Spec.QuotaRef.Name}, orgQuota)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return scopes, nil
+		}
+		return nil, fmt.Errorf("failed to get org quota %q: %w", org.Spec.QuotaRef.Name, err)
+	}
+
+	orgSpaces := &korifiv1alpha1.CFSpaceList{}
+	if err = v.client.List(ctx, orgSpaces, client.InNamespace(org.Name)); err != nil {
+		return nil, fmt.Errorf("failed to list spaces of org %q: %w", org.Name, err)
+	}
+
+	orgSpaceNamespaces := []string{}
+	for _, orgSpace := range orgSpaces.Items {
+		orgSpaceNamespaces = append(orgSpaceNamespaces, orgSpace.Name)
+	}
+
+	return append(scopes, quotaScope{
+		limits: quotaLimits{
+			apps:     orgQuota.Spec.Apps,
+			services: orgQuota.Spec.Services,
+			routes:   orgQuota.Spec.Routes,
+		},
+		namespaces:             orgSpaceNamespaces,
+		memoryExceeded:         OrgMemoryQuotaExceededErrorMessage,
+		instanceMemoryExceeded: OrgInstanceMemoryLimitExceededErrorMessage,
+		instancesExceeded:      OrgInstanceLimitExceededErrorMessage,
+		appTasksExceeded:       OrgAppTasksLimitExceededErrorMessage,
+		routesExceeded:         OrgRoutesLimitExceededErrorMessage,
+		servicesExceeded:       OrgServicesLimitExceededErrorMessage,
+	}), nil
+}
+
+// appsUsage sums the memory and instances of the started processes and
+// running tasks in the given namespaces, skipping processes matched by skip
+func (v QuotaUsageValidator) appsUsage(ctx context.Context, namespaces []string, skip func(korifiv1alpha1.CFProcess) bool) (appsUsage, error) {
+	usage := appsUsage{}
+
+	for _, ns := range namespaces {
+		apps := &korifiv1alpha1.CFAppList{}
+		if err := v.client.List(ctx, apps, client.InNamespace(ns)); err != nil {
+			return appsUsage{}, fmt.Errorf("failed to list apps in namespace %q: %w", ns, err)
+		}
+
+		startedApps := map[string]bool{}
+		for _, app := range apps.Items {
+			if app.Spec.DesiredState == korifiv1alpha1.StartedState {
+				startedApps[app.Name] = true
+			}
+		}
+
+		processes := &korifiv1alpha1.CFProcessList{}
+		if err := v.client.List(ctx, processes, client.InNamespace(ns)); err != nil {
+			return appsUsage{}, fmt.Errorf("failed to list processes in namespace %q: %w", ns, err)
+		}
+
+		for _, process := range processes.Items {
+			if skip(process) || !startedApps[process.Spec.AppRef.Name] || process.Spec.DesiredInstances == nil {
+				continue
+			}
+
+			instances := int64(*process.Spec.DesiredInstances)
+			usage.instances += instances
+			usage.memoryMB += instances * process.Spec.MemoryMB
+		}
+	}
+
+	tasks, err := v.runningTasks(ctx, namespaces, "")
+	if err != nil {
+		return appsUsage{}, err
+	}
+
+	for _, task := range tasks {
+		usage.memoryMB += v.taskMemoryMB(task)
+	}
+
+	return usage, nil
+}
+
+// taskMemoryMB returns the memory of a task, falling back to the default memory for tasks whose memory has not been
+// recorded
+func (v QuotaUsageValidator) taskMemoryMB(task korifiv1alpha1.CFTask) int64 {
+	if task.Status.MemoryMB == 0 {
+		return v.defaultTaskMemoryMB
+	}
+
+	return task.Status.MemoryMB
+}
+
+// runningTasks lists the tasks in the given namespaces that have neither
+// succeeded nor failed. If appGUID is not empty, only the tasks of that app
+// are returned
+func (v QuotaUsageValidator) runningTasks(ctx context.Context, namespaces []string, appGUID string) ([]korifiv1alpha1.CFTask, error) {
+	var running []korifiv1alpha1.CFTask
+
+	for _, ns := range namespaces {
+		tasks := &korifiv1alpha1.CFTaskList{}
+		if err := v.client.List(ctx, tasks, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("failed to list tasks in namespace %q: %w", ns, err)
+		}
+
+		for _, task := range tasks.Items {
+			if appGUID != "" && task.Spec.AppRef.Name != appGUID {
+				continue
+			}
+
+			if meta.IsStatusConditionTrue(task.Status.Conditions, korifiv1alpha1.TaskSucceededConditionType) ||
+				meta.IsStatusConditionTrue(task.Status.Conditions, korifiv1alpha1.TaskFailedConditionType) {
+				continue
+			}
+
+			running = append(running, task)
+		}
+	}
+
+	return running, nil
+}
+
+func (v QuotaUsageValidator) count(ctx context.Context, namespaces []string, list client.ObjectList) (int64, error) {
+	total := int64(0)
+
+	for _, ns := range namespaces {
+		if err := v.client.List(ctx, list, client.InNamespace(ns)); err != nil {
+			return 0, fmt.Errorf("failed to list %T in namespace %q: %w", list, ns, err)
+		}
+
+		total += int64(meta.LenList(list))
+	}
+
+	return total, nil
+}
+
+func exceeds(limit *int64, value int64) bool {
+	return limit != nil && value > *limit
+}
+
+func quotaExceededError(message string) *ValidationError {
+	return &ValidationError{
+		Type:    QuotaExceededErrorType,
+		Message: message,
+	}
+}
+
+func unknownError(err error) *ValidationError {
+	quotaLogger.Info("failed to compute quota usage", "error", err)
+
+	return &ValidationError{
+		Type:    UnknownErrorType,
+		Message: UnknownErrorMessage,
+	}
+}
@@ -0,0 +1,681 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+
+	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/fake"
+	"code.cloudfoundry.org/korifi/controllers/webhooks"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("QuotaUsageValidator", func() {
+	const (
+		rootNamespace = "cf"
+		orgGUID       = "org-guid"
+		spaceGUID     = "space-guid"
+		appGUID       = "app-guid"
+	)
+
+	var (
+		ctx            context.Context
+		fakeClient     *fake.Client
+		quotaValidator *webhooks.QuotaUsageValidator
+		validationErr  *webhooks.ValidationError
+
+		spaces     []korifiv1alpha1.CFSpace
+		org        korifiv1alpha1.CFOrg
+		orgQuota   *korifiv1alpha1.CFOrgQuota
+		spaceQuota *korifiv1alpha1.CFSpaceQuota
+		apps       []korifiv1alpha1.CFApp
+		processes  []korifiv1alpha1.CFProcess
+		tasks      []korifiv1alpha1.CFTask
+		routes     []korifiv1alpha1.CFRoute
+		instances  []korifiv1alpha1.CFServiceInstance
+		listErr    error
+
+		unlabelledNamespaces bool
+	)
+
+	int64Ptr := func(i int64) *int64 { return &i }
+	intPtr := func(i int) *int { return &i }
+
+	notFound := func(name string) error {
+		return k8serrors.NewNotFound(schema.GroupResource{}, name)
+	}
+
+	inNamespace := func(opts []client.ListOption) string {
+		listOpts := &client.ListOptions{}
+		for _, opt := range opts {
+			opt.ApplyToList(listOpts)
+		}
+		return listOpts.Namespace
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClient = new(fake.Client)
+		listErr = nil
+		unlabelledNamespaces = false
+
+		org = korifiv1alpha1.CFOrg{
+			ObjectMeta: metav1.ObjectMeta{Name: orgGUID, Namespace: rootNamespace},
+		}
+		spaces = []korifiv1alpha1.CFSpace{{
+			ObjectMeta: metav1.ObjectMeta{Name: spaceGUID, Namespace: orgGUID},
+		}}
+		orgQuota = nil
+		spaceQuota = nil
+		apps = []korifiv1alpha1.CFApp{{
+			ObjectMeta: metav1.ObjectMeta{Name: appGUID, Namespace: spaceGUID},
+			Spec:       korifiv1alpha1.CFAppSpec{DesiredState: korifiv1alpha1.StartedState},
+		}}
+		processes = []korifiv1alpha1.CFProcess{{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing-process", Namespace: spaceGUID},
+			Spec: korifiv1alpha1.CFProcessSpec{
+				AppRef:           corev1.LocalObjectReference{Name: appGUID},
+				DesiredInstances: intPtr(2),
+				MemoryMB:         256,
+			},
+		}}
+		tasks = nil
+		routes = nil
+		instances = nil
+
+		fakeClient.GetStub = func(_ context.Context, key types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			switch obj := obj.(type) {
+			case *corev1.Namespace:
+				obj.Name = key.Name
+				for _, space := range spaces {
+					if space.Name == key.Name && !unlabelledNamespaces {
+						obj.Labels = map[string]string{korifiv1alpha1.OrgGUIDLabel: space.Namespace}
+					}
+				}
+			case *korifiv1alpha1.CFSpace:
+				for _, space := range spaces {
+					if space.Namespace == key.Namespace && space.Name == key.Name {
+						space.DeepCopyInto(obj)
+						return nil
+					}
+				}
+				return notFound(key.Name)
+			case *korifiv1alpha1.CFApp:
+				for _, app := range apps {
+					if app.Namespace == key.Namespace && app.Name == key.Name {
+						app.DeepCopyInto(obj)
+						return nil
+					}
+				}
+				return notFound(key.Name)
+			case *korifiv1alpha1.CFOrg:
+				if key.Name != org.Name {
+					return notFound(key.Name)
+				}
+				org.DeepCopyInto(obj)
+			case *korifiv1alpha1.CFOrgQuota:
+				if orgQuota == nil {
+					return notFound(key.Name)
+				}
+				orgQuota.DeepCopyInto(obj)
+			case *korifiv1alpha1.CFSpaceQuota:
+				if spaceQuota == nil {
+					return notFound(key.Name)
+				}
+				spaceQuota.DeepCopyInto(obj)
+			default:
+				panic("TestClient Get provided an unexpected object type")
+			}
+			return nil
+		}
+
+		fakeClient.ListStub = func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			if listErr != nil {
+				return listErr
+			}
+
+			ns := inNamespace(opts)
+			var items []runtime.Object
+			switch list.(type) {
+			case *korifiv1alpha1.CFSpaceList:
+				items = filter(spaces, ns)
+			case *korifiv1alpha1.CFAppList:
+				items = filter(apps, ns)
+			case *korifiv1alpha1.CFProcessList:
+				items = filter(processes, ns)
+			case *korifiv1alpha1.CFTaskList:
+				items = filter(tasks, ns)
+			case *korifiv1alpha1.CFRouteList:
+				items = filter(routes, ns)
+			case *korifiv1alpha1.CFServiceInstanceList:
+				items = filter(instances, ns)
+			default:
+				panic("TestClient List provided an unexpected object type")
+			}
+
+			return meta.SetList(list, items)
+		}
+
+		quotaValidator = webhooks.NewQuotaUsageValidator(fakeClient, rootNamespace, 128)
+	})
+
+	Describe("ValidateProcess", func() {
+		var process korifiv1alpha1.CFProcess
+
+		BeforeEach(func() {
+			process = korifiv1alpha1.CFProcess{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-process", Namespace: spaceGUID},
+				Spec: korifiv1alpha1.CFProcessSpec{
+					AppRef:           corev1.LocalObjectReference{Name: appGUID},
+					DesiredInstances: intPtr(1),
+					MemoryMB:         512,
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = quotaValidator.ValidateProcess(ctx, process)
+		})
+
+		It("succeeds when no quota is assigned", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		When("the process is not in a space", func() {
+			BeforeEach(func() {
+				spaces = nil
+			})
+
+			It("succeeds", func() {
+				Expect(validationErr).To(BeNil())
+			})
+		})
+
+		When("the space has a quota", func() {
+			BeforeEach(func() {
+				spaces[0].Spec.QuotaRef.Name = "space-quota"
+				spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+					Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+						Apps: korifiv1alpha1.QuotaAppsLimits{TotalMemoryInMB: int64Ptr(1024)},
+					},
+				}
+			})
+
+			It("succeeds when the total memory stays within the limit", func() {
+				Expect(validationErr).To(BeNil())
+			})
+
+			When("the total memory would exceed the limit", func() {
+				BeforeEach(func() {
+					process.Spec.DesiredInstances = intPtr(2)
+				})
+
+				It("fails with the space memory message", func() {
+					Expect(validationErr).To(Equal(&webhooks.ValidationError{
+						Type:    webhooks.QuotaExceededErrorType,
+						Message: webhooks.SpaceMemoryQuotaExceededErrorMessage,
+					}))
+				})
+			})
+
+			When("the existing process belongs to a stopped app", func() {
+				BeforeEach(func() {
+					apps[0].Spec.DesiredState = korifiv1alpha1.StoppedState
+					apps = append(apps, korifiv1alpha1.CFApp{
+						ObjectMeta: metav1.ObjectMeta{Name: "other-app-guid", Namespace: spaceGUID},
+						Spec:       korifiv1alpha1.CFAppSpec{DesiredState: korifiv1alpha1.StartedState},
+					})
+					process.Spec.AppRef.Name = "other-app-guid"
+					process.Spec.DesiredInstances = intPtr(2)
+				})
+
+				It("does not count it", func() {
+					Expect(validationErr).To(BeNil())
+				})
+			})
+
+			When("a process of a stopped app is scaled above the limit", func() {
+				BeforeEach(func() {
+					apps[0].Spec.DesiredState = korifiv1alpha1.StoppedState
+					process.Name = "existing-process"
+					process.Spec.DesiredInstances = intPtr(10)
+				})
+
+				It("succeeds, as the app is checked when it is started", func() {
+					Expect(validationErr).To(BeNil())
+				})
+			})
+
+			When("the app of the process does not exist", func() {
+				BeforeEach(func() {
+					process.Spec.AppRef.Name = "missing-app-guid"
+					process.Spec.DesiredInstances = intPtr(10)
+				})
+
+				It("succeeds, as the process does not run", func() {
+					Expect(validationErr).To(BeNil())
+				})
+			})
+
+			When("the process being validated is being scaled", func() {
+				BeforeEach(func() {
+					process.Name = "existing-process"
+					process.Spec.DesiredInstances = intPtr(4)
+					process.Spec.MemoryMB = 256
+				})
+
+				It("replaces its current usage rather than adding to it", func() {
+					Expect(validationErr).To(BeNil())
+				})
+			})
+
+			When("running tasks use memory", func() {
+				BeforeEach(func() {
+					tasks = []korifiv1alpha1.CFTask{{
+						ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: spaceGUID},
+						Status:     korifiv1alpha1.CFTaskStatus{MemoryMB: 100},
+					}}
+				})
+
+				It("counts them", func() {
+					Expect(validationErr).To(Equal(&webhooks.ValidationError{
+						Type:    webhooks.QuotaExceededErrorType,
+						Message: webhooks.SpaceMemoryQuotaExceededErrorMessage,
+					}))
+				})
+			})
+		})
+
+		When("the org has a quota", func() {
+			BeforeEach(func() {
+				org.Spec.QuotaRef.Name = "org-quota"
+				orgQuota = &korifiv1alpha1.CFOrgQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "org-quota", Namespace: rootNamespace},
+					Spec: korifiv1alpha1.CFOrgQuotaSpec{
+						Apps: korifiv1alpha1.QuotaAppsLimits{
+							PerProcessMemoryInMB: int64Ptr(1024),
+							TotalInstances:       int64Ptr(3),
+						},
+					},
+				}
+			})
+
+			It("succeeds when the process stays within the limits", func() {
+				Expect(validationErr).To(BeNil())
+			})
+
+			When("the instance memory exceeds the limit", func() {
+				BeforeEach(func() {
+					process.Spec.MemoryMB = 2048
+				})
+
+				It("fails with the org instance memory message", func() {
+					Expect(validationErr).To(Equal(&webhooks.ValidationError{
+						Type:    webhooks.QuotaExceededErrorType,
+						Message: webhooks.OrgInstanceMemoryLimitExceededErrorMessage,
+					}))
+				})
+			})
+
+			When("the total instances would exceed the limit", func() {
+				BeforeEach(func() {
+					process.Spec.DesiredInstances = intPtr(2)
+				})
+
+				It("fails with the org instances message", func() {
+					Expect(validationErr).To(Equal(&webhooks.ValidationError{
+						Type:    webhooks.QuotaExceededErrorType,
+						Message: webhooks.OrgInstanceLimitExceededErrorMessage,
+					}))
+				})
+			})
+		})
+
+		When("the space namespace has not been labelled with its org yet", func() {
+			BeforeEach(func() {
+				unlabelledNamespaces = true
+				spaces[0].Spec.QuotaRef.Name = "space-quota"
+				spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+					Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+						Apps: korifiv1alpha1.QuotaAppsLimits{TotalInstances: int64Ptr(2)},
+					},
+				}
+			})
+
+			It("finds the space among all spaces and enforces its quota", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceInstanceLimitExceededErrorMessage,
+				}))
+			})
+		})
+
+		When("listing resources fails", func() {
+			BeforeEach(func() {
+				spaces[0].Spec.QuotaRef.Name = "space-quota"
+				spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+				}
+				listErr = errors.New("boom")
+			})
+
+			It("returns an unknown error", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.UnknownErrorType,
+					Message: webhooks.UnknownErrorMessage,
+				}))
+			})
+		})
+	})
+
+	Describe("ValidateAppStart", func() {
+		var app korifiv1alpha1.CFApp
+
+		BeforeEach(func() {
+			app = korifiv1alpha1.CFApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "stopped-app-guid", Namespace: spaceGUID},
+				Spec:       korifiv1alpha1.CFAppSpec{DesiredState: korifiv1alpha1.StartedState},
+			}
+			apps = append(apps, korifiv1alpha1.CFApp{
+				ObjectMeta: app.ObjectMeta,
+				Spec:       korifiv1alpha1.CFAppSpec{DesiredState: korifiv1alpha1.StoppedState},
+			})
+			processes = append(processes, korifiv1alpha1.CFProcess{
+				ObjectMeta: metav1.ObjectMeta{Name: "stopped-app-process", Namespace: spaceGUID},
+				Spec: korifiv1alpha1.CFProcessSpec{
+					AppRef:           corev1.LocalObjectReference{Name: app.Name},
+					DesiredInstances: intPtr(2),
+					MemoryMB:         256,
+				},
+			})
+
+			spaces[0].Spec.QuotaRef.Name = "space-quota"
+			spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+				Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+					Apps: korifiv1alpha1.QuotaAppsLimits{
+						PerProcessMemoryInMB: int64Ptr(512),
+						TotalMemoryInMB:      int64Ptr(1024),
+						TotalInstances:       int64Ptr(4),
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = quotaValidator.ValidateAppStart(ctx, app)
+		})
+
+		It("succeeds when the processes of the app stay within the limits", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		When("the app has been scaled beyond the total instances while it was stopped", func() {
+			BeforeEach(func() {
+				processes[1].Spec.DesiredInstances = intPtr(3)
+				processes[1].Spec.MemoryMB = 128
+			})
+
+			It("fails with the space instances message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceInstanceLimitExceededErrorMessage,
+				}))
+			})
+		})
+
+		When("the app has been scaled beyond the total memory while it was stopped", func() {
+			BeforeEach(func() {
+				processes[1].Spec.MemoryMB = 512
+			})
+
+			It("fails with the space memory message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceMemoryQuotaExceededErrorMessage,
+				}))
+			})
+		})
+
+		When("a process of the app exceeds the instance memory", func() {
+			BeforeEach(func() {
+				processes[1].Spec.DesiredInstances = intPtr(1)
+				processes[1].Spec.MemoryMB = 768
+			})
+
+			It("fails with the space instance memory message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceInstanceMemoryLimitExceededErrorMessage,
+				}))
+			})
+		})
+
+		When("a process of the app has been scaled to zero", func() {
+			BeforeEach(func() {
+				processes[1].Spec.DesiredInstances = intPtr(0)
+				processes[1].Spec.MemoryMB = 768
+			})
+
+			It("does not count it", func() {
+				Expect(validationErr).To(BeNil())
+			})
+		})
+
+		When("the app is already started", func() {
+			BeforeEach(func() {
+				app = apps[0]
+			})
+
+			It("does not count its processes twice", func() {
+				Expect(validationErr).To(BeNil())
+			})
+		})
+	})
+
+	Describe("ValidateTaskCreate", func() {
+		var task korifiv1alpha1.CFTask
+
+		BeforeEach(func() {
+			task = korifiv1alpha1.CFTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-task", Namespace: spaceGUID},
+				Spec:       korifiv1alpha1.CFTaskSpec{AppRef: corev1.LocalObjectReference{Name: appGUID}},
+			}
+			tasks = []korifiv1alpha1.CFTask{{
+				ObjectMeta: metav1.ObjectMeta{Name: "running-task", Namespace: spaceGUID},
+				Spec:       korifiv1alpha1.CFTaskSpec{AppRef: corev1.LocalObjectReference{Name: appGUID}},
+				Status:     korifiv1alpha1.CFTaskStatus{MemoryMB: 128},
+			}}
+
+			spaces[0].Spec.QuotaRef.Name = "space-quota"
+			spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+				Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+					Apps: korifiv1alpha1.QuotaAppsLimits{
+						TotalMemoryInMB: int64Ptr(1024),
+						PerAppTasks:     int64Ptr(2),
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = quotaValidator.ValidateTaskCreate(ctx, task)
+		})
+
+		It("succeeds", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		When("the app already runs the maximum number of tasks", func() {
+			BeforeEach(func() {
+				tasks = append(tasks, korifiv1alpha1.CFTask{
+					ObjectMeta: metav1.ObjectMeta{Name: "other-running-task", Namespace: spaceGUID},
+					Spec:       korifiv1alpha1.CFTaskSpec{AppRef: corev1.LocalObjectReference{Name: appGUID}},
+				})
+			})
+
+			It("fails with the space app tasks message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceAppTasksLimitExceededErrorMessage,
+				}))
+			})
+
+			When("one of the tasks has completed", func() {
+				BeforeEach(func() {
+					meta.SetStatusCondition(&tasks[1].Status.Conditions, metav1.Condition{
+						Type:   korifiv1alpha1.TaskSucceededConditionType,
+						Status: metav1.ConditionTrue,
+						Reason: "succeeded",
+					})
+				})
+
+				It("succeeds", func() {
+					Expect(validationErr).To(BeNil())
+				})
+			})
+		})
+
+		When("the task memory would exceed the total memory", func() {
+			BeforeEach(func() {
+				task.Status.MemoryMB = 512
+			})
+
+			It("fails with the space memory message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceMemoryQuotaExceededErrorMessage,
+				}))
+			})
+		})
+
+		When("the web process of the app uses more memory than the default", func() {
+			BeforeEach(func() {
+				processes[0].Spec.ProcessType = korifiv1alpha1.ProcessTypeWeb
+				processes[0].Spec.DesiredInstances = intPtr(1)
+				processes[0].Spec.MemoryMB = 700
+			})
+
+			It("assumes the new task gets the default memory", func() {
+				Expect(validationErr).To(BeNil())
+			})
+		})
+
+		When("the memory of a running task has not been recorded", func() {
+			BeforeEach(func() {
+				tasks[0].Status.MemoryMB = 0
+				processes[0].Spec.MemoryMB = 400
+			})
+
+			It("counts the default memory for it", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceMemoryQuotaExceededErrorMessage,
+				}))
+			})
+		})
+	})
+
+	Describe("ValidateRouteCreate", func() {
+		BeforeEach(func() {
+			org.Spec.QuotaRef.Name = "org-quota"
+			orgQuota = &korifiv1alpha1.CFOrgQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "org-quota", Namespace: rootNamespace},
+				Spec: korifiv1alpha1.CFOrgQuotaSpec{
+					Routes: korifiv1alpha1.QuotaRoutesLimits{TotalRoutes: int64Ptr(1)},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = quotaValidator.ValidateRouteCreate(ctx, korifiv1alpha1.CFRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-route", Namespace: spaceGUID},
+			})
+		})
+
+		It("succeeds", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		When("the org already has the maximum number of routes", func() {
+			BeforeEach(func() {
+				routes = []korifiv1alpha1.CFRoute{{
+					ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: spaceGUID},
+				}}
+			})
+
+			It("fails with the org routes message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.OrgRoutesLimitExceededErrorMessage,
+				}))
+			})
+		})
+	})
+
+	Describe("ValidateServiceInstanceCreate", func() {
+		BeforeEach(func() {
+			spaces[0].Spec.QuotaRef.Name = "space-quota"
+			spaceQuota = &korifiv1alpha1.CFSpaceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "space-quota", Namespace: orgGUID},
+				Spec: korifiv1alpha1.CFSpaceQuotaSpec{
+					Services: korifiv1alpha1.QuotaServicesLimits{TotalServiceInstances: int64Ptr(1)},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			validationErr = quotaValidator.ValidateServiceInstanceCreate(ctx, korifiv1alpha1.CFServiceInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "new-instance", Namespace: spaceGUID},
+			})
+		})
+
+		It("succeeds", func() {
+			Expect(validationErr).To(BeNil())
+		})
+
+		When("the space already has the maximum number of service instances", func() {
+			BeforeEach(func() {
+				instances = []korifiv1alpha1.CFServiceInstance{{
+					ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: spaceGUID},
+				}}
+			})
+
+			It("fails with the space services message", func() {
+				Expect(validationErr).To(Equal(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.SpaceServicesLimitExceededErrorMessage,
+				}))
+			})
+		})
+	})
+})
+
+// filter returns copies of the items living in ns, or all of them if ns is empty
+func filter[T any, PT interface {
+	*T
+	client.Object
+}](items []T, ns string) []runtime.Object {
+	var objs []runtime.Object
+	for i := range items {
+		obj := PT(&items[i])
+		if ns == "" || obj.GetNamespace() == ns {
+			objs = append(objs, obj.DeepCopyObject())
+		}
+	}
+	return objs
+}
@@ -26,6 +26,7 @@ var _ = Describe("CFServiceInstanceValidatingWebhook", func() {
 		serviceInstanceName string
 		ctx                 context.Context
 		duplicateValidator  *fake.NameValidator
+		quotaValidator      *fake.QuotaValidator
 		serviceInstance     *korifiv1alpha1.CFServiceInstance
 		validatingWebhook   *services.CFServiceInstanceValidator
 		retErr              error
@@ -52,7 +53,8 @@ var _ = Describe("CFServiceInstanceValidatingWebhook", func() {
 		}
 
 		duplicateValidator = new(fake.NameValidator)
-		validatingWebhook = services.NewCFServiceInstanceValidator(duplicateValidator)
+		quotaValidator = new(fake.QuotaValidator)
+		validatingWebhook = services.NewCFServiceInstanceValidator(duplicateValidator, quotaValidator)
 	})
 
 	Describe("ValidateCreate", func() {
@@ -72,6 +74,33 @@ var _ = Describe("CFServiceInstanceValidatingWebhook", func() {
 			Expect(name).To(Equal(serviceInstance.Spec.DisplayName))
 		})
 
+		It("invokes the quota validator correctly", func() {
+			Expect(quotaValidator.ValidateServiceInstanceCreateCallCount()).To(Equal(1))
+			actualContext, actualServiceInstance := quotaValidator.ValidateServiceInstanceCreateArgsForCall(0)
+			Expect(actualContext).To(Equal(ctx))
+			Expect(actualServiceInstance.Name).To(Equal(serviceInstanceGUID))
+		})
+
+		When("the serviceInstance would exceed the quota", func() {
+			BeforeEach(func() {
+				quotaValidator.ValidateServiceInstanceCreateReturns(&webhooks.ValidationError{
+					Type:    webhooks.QuotaExceededErrorType,
+					Message: webhooks.OrgServicesLimitExceededErrorMessage,
+				})
+			})
+
+			It("denies the request", func() {
+				Expect(retErr).To(matchers.BeValidationError(
+					webhooks.QuotaExceededErrorType,
+					Equal(webhooks.OrgServicesLimitExceededErrorMessage),
+				))
+			})
+
+			It("does not reserve the serviceInstance name", func() {
+				Expect(duplicateValidator.ValidateCreateCallCount()).To(Equal(0))
+			})
+		})
+
 		When("the serviceInstance name is a duplicate", func() {
 			BeforeEach(func() {
 				duplicateValidator.ValidateCreateReturns(&webhooks.ValidationError{
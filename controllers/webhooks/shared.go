@@ -21,4 +21,15 @@ type NameValidator interface {
 type NamespaceValidator interface {
 	ValidateOrgCreate(org korifiv1alpha1.CFOrg) *ValidationError
 	ValidateSpaceCreate(space korifiv1alpha1.CFSpace) *ValidationError
+	ValidateSpaceAssignments(ctx context.Context, oldSpace, space korifiv1alpha1.CFSpace) *ValidationError
+}
+
+//counterfeiter:generate -o fake -fake-name QuotaValidator . QuotaValidator
+
+type QuotaValidator interface {
+	ValidateProcess(ctx context.Context, process korifiv1alpha1.CFProcess) *ValidationError
+	ValidateAppStart(ctx context.Context, app korifiv1alpha1.CFApp) *ValidationError
+	ValidateTaskCreate(ctx context.Context, task korifiv1alpha1.CFTask) *ValidationError
+	ValidateRouteCreate(ctx context.Context, route korifiv1alpha1.CFRoute) *ValidationError
+	ValidateServiceInstanceCreate(ctx context.Context, serviceInstance korifiv1alpha1.CFServiceInstance) *ValidationError
 }
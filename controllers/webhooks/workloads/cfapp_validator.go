@@ -8,32 +8,46 @@ import (
 	korifiv1alpha1 "code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
 	"code.cloudfoundry.org/korifi/controllers/webhooks"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 const (
 	AppEntityType                = "app"
 	AppDecodingErrorType         = "AppDecodingError"
 	duplicateAppNameErrorMessage = "App with the name '%s' already exists."
+
+	// fullUpdateVerb is the verb on cfapps that users need to change anything but the desired state of an app. CF space
+	// supporters may patch apps in order to start, stop and restart them, but are not granted it.
+	fullUpdateVerb = "update"
 )
 
 var cfapplog = logf.Log.WithName("cfapp-validate")
 
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
 //+kubebuilder:webhook:path=/validate-korifi-cloudfoundry-org-v1alpha1-cfapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=korifi.cloudfoundry.org,resources=cfapps,verbs=create;update;delete,versions=v1alpha1,name=vcfapp.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
 
 type CFAppValidator struct {
 	duplicateValidator webhooks.NameValidator
+	quotaValidator     webhooks.QuotaValidator
+	k8sClient          client.Client
 }
 
 var _ webhook.CustomValidator = &CFAppValidator{}
 
-func NewCFAppValidator(duplicateValidator webhooks.NameValidator) *CFAppValidator {
+func NewCFAppValidator(duplicateValidator webhooks.NameValidator, quotaValidator webhooks.QuotaValidator, k8sClient client.Client) *CFAppValidator {
 	return &CFAppValidator{
 		duplicateValidator: duplicateValidator,
+		quotaValidator:     quotaValidator,
+		k8sClient:          k8sClient,
 	}
 }
 
@@ -74,15 +88,98 @@ func (v *CFAppValidator) ValidateUpdate(ctx context.Context, oldObj, obj runtime
 		return apierrors.NewBadRequest(fmt.Sprintf("expected a CFApp but got a %T", oldObj))
 	}
 
+	if err := v.validateUpdater(ctx, oldApp, app); err != nil {
+		return err
+	}
+
 	duplicateErrorMessage := fmt.Sprintf(duplicateAppNameErrorMessage, app.Spec.DisplayName)
 	validationErr := v.duplicateValidator.ValidateUpdate(ctx, cfapplog, app.Namespace, strings.ToLower(oldApp.Spec.DisplayName), strings.ToLower(app.Spec.DisplayName), duplicateErrorMessage)
 	if validationErr != nil {
 		return validationErr.ExportJSONError()
 	}
 
+	if oldApp.Spec.DesiredState != korifiv1alpha1.StartedState && app.Spec.DesiredState == korifiv1alpha1.StartedState {
+		validationErr = v.quotaValidator.ValidateAppStart(ctx, *app)
+		if validationErr != nil {
+			return validationErr.ExportJSONError()
+		}
+	}
+
+	return nil
+}
+
+// validateUpdater rejects updates that change more than the desired state of the app from users that may only patch
+// apps, i.e. that lack the update verb
+func (v *CFAppValidator) validateUpdater(ctx context.Context, oldApp, app *korifiv1alpha1.CFApp) error {
+	if onlyChangesDesiredState(oldApp, app) {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review := authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: app.Namespace,
+				Name:      app.Name,
+				Verb:      fullUpdateVerb,
+				Group:     korifiv1alpha1.GroupVersion.Group,
+				Resource:  "cfapps",
+			},
+		},
+	}
+	if err := v.k8sClient.Create(ctx, &review); err != nil {
+		cfapplog.Error(err, "failed to review the access of the user", "user", req.UserInfo.Username)
+		return apierrors.NewInternalError(err)
+	}
+
+	if !review.Status.Allowed {
+		return apierrors.NewForbidden(
+			korifiv1alpha1.GroupVersion.WithResource("cfapps").GroupResource(),
+			app.Name,
+			fmt.Errorf("user %q may only change the desired state of the app", req.UserInfo.Username),
+		)
+	}
+
 	return nil
 }
 
+func onlyChangesDesiredState(oldApp, app *korifiv1alpha1.CFApp) bool {
+	oldSpec := oldApp.Spec.DeepCopy()
+	oldSpec.DesiredState = app.Spec.DesiredState
+
+	return equality.Semantic.DeepEqual(*oldSpec, app.Spec) &&
+		equality.Semantic.DeepEqual(oldApp.Labels, app.Labels) &&
+		equality.Semantic.DeepEqual(withoutRevision(oldApp.Annotations), withoutRevision(app.Annotations)) &&
+		equality.Semantic.DeepEqual(oldApp.Finalizers, app.Finalizers) &&
+		equality.Semantic.DeepEqual(oldApp.OwnerReferences, app.OwnerReferences)
+}
+
+// withoutRevision drops the app revision annotation, which the mutating webhook bumps when an app is stopped, so that
+// stopping and restarting apps counts as changing their desired state only
+func withoutRevision(annotations map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range annotations {
+		if key != korifiv1alpha1.CFAppRevisionKey {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
 func (v *CFAppValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
 	app, ok := obj.(*korifiv1alpha1.CFApp)
 	if !ok {
@@ -12,7 +12,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 var _ = Describe("CFAppValidatingWebhook", func() {
@@ -116,16 +118,18 @@ var _ = Describe("CFAppValidatingWebhook", func() {
 		var (
 			updateErr    error
 			originalApp1 *korifiv1alpha1.CFApp
+			updateClient client.Client
 		)
 
 		BeforeEach(func() {
 			app1 = makeCFApp(app1Guid, namespace1, app1Name)
 			Expect(k8sClient.Create(ctx, app1)).To(Succeed())
 			originalApp1 = app1.DeepCopy()
+			updateClient = k8sClient
 		})
 
 		JustBeforeEach(func() {
-			updateErr = k8sClient.Patch(context.Background(), app1, client.MergeFrom(originalApp1))
+			updateErr = updateClient.Patch(context.Background(), app1, client.MergeFrom(originalApp1))
 		})
 
 		When("changing the name", func() {
@@ -179,6 +183,96 @@ var _ = Describe("CFAppValidatingWebhook", func() {
 				Expect(updateErr).To(MatchError(ContainSubstring(fmt.Sprintf("App with the name '%s' already exists.", app2Name))))
 			})
 		})
+
+		When("the user may patch apps but not update them", func() {
+			BeforeEach(func() {
+				userName := uuid.NewString()
+				Expect(k8sClient.Create(ctx, &rbacv1.Role{
+					ObjectMeta: metav1.ObjectMeta{Name: "app-patcher", Namespace: namespace1},
+					Rules: []rbacv1.PolicyRule{{
+						APIGroups: []string{"korifi.cloudfoundry.org"},
+						Resources: []string{"cfapps"},
+						Verbs:     []string{"get", "patch"},
+					}},
+				})).To(Succeed())
+				Expect(k8sClient.Create(ctx, &rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "app-patcher", Namespace: namespace1},
+					Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: userName}},
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "app-patcher"},
+				})).To(Succeed())
+
+				restConfig := rest.CopyConfig(testEnv.Config)
+				restConfig.Impersonate = rest.ImpersonationConfig{UserName: userName}
+				var err error
+				updateClient, err = client.New(restConfig, client.Options{Scheme: k8sClient.Scheme()})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("changing the desired state", func() {
+				BeforeEach(func() {
+					app1.Spec.DesiredState = korifiv1alpha1.StartedState
+				})
+
+				It("should succeed", func() {
+					Expect(updateErr).NotTo(HaveOccurred())
+				})
+			})
+
+			When("stopping a started app", func() {
+				BeforeEach(func() {
+					app1.Spec.DesiredState = korifiv1alpha1.StartedState
+					Expect(k8sClient.Patch(ctx, app1, client.MergeFrom(originalApp1))).To(Succeed())
+					app1.Status = korifiv1alpha1.CFAppStatus{
+						Conditions:           []metav1.Condition{},
+						ObservedDesiredState: korifiv1alpha1.StartedState,
+					}
+					Expect(k8sClient.Status().Update(ctx, app1)).To(Succeed())
+
+					originalApp1 = app1.DeepCopy()
+					app1.Spec.DesiredState = korifiv1alpha1.StoppedState
+				})
+
+				It("should succeed and bump the app revision", func() {
+					Expect(updateErr).NotTo(HaveOccurred())
+
+					app1Actual := korifiv1alpha1.CFApp{}
+					Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(app1), &app1Actual)).To(Succeed())
+					Expect(app1Actual.Spec.DesiredState).To(Equal(korifiv1alpha1.StoppedState))
+					Expect(app1Actual.Annotations).To(HaveKeyWithValue(korifiv1alpha1.CFAppRevisionKey, "1"))
+				})
+			})
+
+			When("changing the app revision only", func() {
+				BeforeEach(func() {
+					app1.Annotations[korifiv1alpha1.CFAppRevisionKey] = "5"
+				})
+
+				It("should succeed", func() {
+					Expect(updateErr).NotTo(HaveOccurred())
+				})
+			})
+
+			When("changing anything else", func() {
+				BeforeEach(func() {
+					app1.Spec.DesiredState = korifiv1alpha1.StartedState
+					app1.Spec.DisplayName = uuid.NewString()
+				})
+
+				It("should fail", func() {
+					Expect(updateErr).To(MatchError(ContainSubstring("may only change the desired state of the app")))
+				})
+			})
+
+			When("changing the labels", func() {
+				BeforeEach(func() {
+					app1.Labels = map[string]string{"foo": "bar"}
+				})
+
+				It("should fail", func() {
+					Expect(updateErr).To(MatchError(ContainSubstring("may only change the desired state of the app")))
+				})
+			})
+		})
 	})
 
 	Describe("Delete", func() {
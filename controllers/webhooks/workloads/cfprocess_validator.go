@@ -0,0 +1,120 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/korifi/controllers/api/v1alpha1"
+	"code.cloudfoundry.org/korifi/controllers/webhooks"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var cfprocesslog = logf.Log.WithName("cfprocess-validator")
+
+//+kubebuilder:webhook:path=/validate-korifi-cloudfoundry-org-v1alpha1-cfprocess,mutating=false,failurePolicy=fail,sideEffects=None,groups=korifi.cloudfoundry.org,resources=cfprocesses,verbs=create;update,versions=v1alpha1,name=vcfprocess.korifi.cloudfoundry.org,admissionReviewVersions={v1,v1beta1}
+
+type CFProcessValidator struct {
+	quotaValidator webhooks.QuotaValidator
+}
+
+var _ webhook.CustomValidator = &CFProcessValidator{}
+
+func NewCFProcessValidator(quotaValidator webhooks.QuotaValidator) *CFProcessValidator {
+	return &CFProcessValidator{
+		quotaValidator: quotaValidator,
+	}
+}
+
+func (v *CFProcessValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.CFProcess{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *CFProcessValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	process, ok := obj.(*v1alpha1.CFProcess)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a CFProcess but got a %T", obj))
+	}
+
+	cfprocesslog.V(1).Info("validate process creation", "namespace", process.Namespace, "name", process.Name)
+
+	if validationErr := v.quotaValidator.ValidateProcess(ctx, *process); validationErr != nil {
+		return validationErr.ExportJSONError()
+	}
+
+	return nil
+}
+
+func (v *CFProcessValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, obj runtime.Object) error {
+	process, ok := obj.(*v1alpha1.CFProcess)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a CFProcess but got a %T", obj))
+	}
+
+	if !process.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	oldProcess, ok := oldObj.(*v1alpha1.CFProcess)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a CFProcess but got a %T", oldObj))
+	}
+
+	if !scaledUp(oldProcess, process) {
+		return nil
+	}
+
+	cfprocesslog.V(1).Info("validate process scale", "namespace", process.Namespace, "name", process.Name)
+
+	if validationErr := v.quotaValidator.ValidateProcess(ctx, *process); validationErr != nil {
+		return validationErr.ExportJSONError()
+	}
+
+	return nil
+}
+
+func (v *CFProcessValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// scaledUp reports whether the update asks for more instances or more memory
+// per instance. Scaling down is always allowed, even when the space or org is
+// already over quota.
+func scaledUp(oldProcess, newProcess *v1alpha1.CFProcess) bool {
+	if newProcess.Spec.MemoryMB > oldProcess.Spec.MemoryMB {
+		return true
+	}
+
+	return desiredInstances(newProcess) > desiredInstances(oldProcess)
+}
+
+func desiredInstances(process *v1alpha1.CFProcess) int {
+	if process.Spec.DesiredInstances == nil {
+		return 0
+	}
+
+	return *process.Spec.DesiredInstances
+}
@@ -63,6 +63,11 @@ func (v *CFSpaceValidator) ValidateCreate(ctx context.Context, obj runtime.Objec
 		return err.ExportJSONError()
 	}
 
+	err = v.placementValidator.ValidateSpaceAssignments(ctx, korifiv1alpha1.CFSpace{}, *space)
+	if err != nil {
+		return err.ExportJSONError()
+	}
+
 	return nil
 }
 
@@ -87,6 +92,11 @@ func (v *CFSpaceValidator) ValidateUpdate(ctx context.Context, oldObj, obj runti
 		return validationErr.ExportJSONError()
 	}
 
+	validationErr = v.placementValidator.ValidateSpaceAssignments(ctx, *oldSpace, *space)
+	if validationErr != nil {
+		return validationErr.ExportJSONError()
+	}
+
 	return nil
 }
 
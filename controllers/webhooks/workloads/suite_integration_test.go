@@ -18,8 +18,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -72,6 +74,8 @@ var _ = BeforeSuite(func() {
 	Expect(admissionv1beta1.AddToScheme(scheme)).To(Succeed())
 	Expect(corev1.AddToScheme(scheme)).To(Succeed())
 	Expect(coordinationv1.AddToScheme(scheme)).To(Succeed())
+	Expect(authorizationv1.AddToScheme(scheme)).To(Succeed())
+	Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
 
 	//+kubebuilder:scaffold:scheme
 
@@ -102,9 +106,6 @@ var _ = BeforeSuite(func() {
 
 	Expect((&korifiv1alpha1.CFApp{}).SetupWebhookWithManager(mgr)).To(Succeed())
 
-	appNameDuplicateValidator := webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), workloads.AppEntityType))
-	Expect(workloads.NewCFAppValidator(appNameDuplicateValidator).SetupWebhookWithManager(mgr)).To(Succeed())
-
 	orgNameDuplicateValidator := webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), workloads.CFOrgEntityType))
 	orgPlacementValidator := webhooks.NewPlacementValidator(mgr.GetClient(), rootNamespace)
 	Expect(workloads.NewCFOrgValidator(orgNameDuplicateValidator, orgPlacementValidator).SetupWebhookWithManager(mgr)).To(Succeed())
@@ -118,7 +119,11 @@ var _ = BeforeSuite(func() {
 		DiskQuotaMB: 512,
 	}
 	Expect(workloads.NewCFTaskDefaulter(cfProcessDefaults).SetupWebhookWithManager(mgr)).To(Succeed())
-	Expect(workloads.NewCFTaskValidator().SetupWebhookWithManager(mgr)).To(Succeed())
+	quotaValidator := webhooks.NewQuotaUsageValidator(mgr.GetClient(), rootNamespace, cfProcessDefaults.MemoryMB)
+	appNameDuplicateValidator := webhooks.NewDuplicateValidator(coordination.NewNameRegistry(mgr.GetClient(), workloads.AppEntityType))
+	Expect(workloads.NewCFAppValidator(appNameDuplicateValidator, quotaValidator, mgr.GetClient()).SetupWebhookWithManager(mgr)).To(Succeed())
+	Expect(workloads.NewCFTaskValidator(quotaValidator).SetupWebhookWithManager(mgr)).To(Succeed())
+	Expect(workloads.NewCFProcessValidator(quotaValidator).SetupWebhookWithManager(mgr)).To(Succeed())
 
 	//+kubebuilder:scaffold:webhook
 
@@ -180,6 +180,11 @@ func (r *TaskWorkloadReconciler) workloadToJob(taskWorkload *korifiv1alpha1.Task
 		},
 	}
 
+	if taskWorkload.Spec.IsolationSegment != nil {
+		job.Spec.Template.Spec.NodeSelector = taskWorkload.Spec.IsolationSegment.NodeSelector
+		job.Spec.Template.Spec.Tolerations = taskWorkload.Spec.IsolationSegment.Tolerations
+	}
+
 	err := controllerutil.SetControllerReference(taskWorkload, job, r.scheme)
 	if err != nil {
 		return nil, err
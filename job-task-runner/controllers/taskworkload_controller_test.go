@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +35,7 @@ var _ = Describe("TaskworkloadController", func() {
 		taskWorkload         *korifiv1alpha1.TaskWorkload
 		getTaskWorkloadError error
 		createdJob           *batchv1.Job
+		submittedJob         *batchv1.Job
 		existingJob          *batchv1.Job
 		getExistingJobError  error
 		createJobError       error
@@ -82,6 +84,7 @@ var _ = Describe("TaskworkloadController", func() {
 		fakeClient.CreateStub = func(ctx context.Context, obj client.Object, option ...client.CreateOption) error {
 			switch obj := obj.(type) {
 			case *batchv1.Job:
+				submittedJob = obj.DeepCopy()
 				createdJob.DeepCopyInto(obj)
 				return createJobError
 			default:
@@ -155,6 +158,36 @@ var _ = Describe("TaskworkloadController", func() {
 			Expect(job.Name).To(Equal(taskWorkload.Name))
 		})
 
+		It("does not constrain the job scheduling", func() {
+			Expect(submittedJob.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+			Expect(submittedJob.Spec.Template.Spec.Tolerations).To(BeEmpty())
+		})
+
+		When("the task workload has an isolation segment", func() {
+			BeforeEach(func() {
+				taskWorkload.Spec.IsolationSegment = &korifiv1alpha1.IsolationSegmentPlacement{
+					Name:         "my-segment",
+					NodeSelector: map[string]string{"pool": "my-pool"},
+					Tolerations: []corev1.Toleration{{
+						Key:      "dedicated",
+						Operator: corev1.TolerationOpEqual,
+						Value:    "my-pool",
+						Effect:   corev1.TaintEffectNoSchedule,
+					}},
+				}
+			})
+
+			It("schedules the job on the segment's node pool", func() {
+				Expect(submittedJob.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "my-pool"}))
+				Expect(submittedJob.Spec.Template.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+					Key:      "dedicated",
+					Operator: corev1.TolerationOpEqual,
+					Value:    "my-pool",
+					Effect:   corev1.TaintEffectNoSchedule,
+				}))
+			})
+		})
+
 		When("the job already exists while creating", func() {
 			BeforeEach(func() {
 				createJobError = k8serrors.NewAlreadyExists(schema.GroupResource{}, "foo")
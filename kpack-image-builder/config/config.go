@@ -6,13 +6,16 @@ import (
 	"path/filepath"
 	"strings"
 
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
 	"gopkg.in/yaml.v3"
 )
 
 type ControllerConfig struct {
-	CFRootNamespace    string `yaml:"cfRootNamespace"`
-	DropletRepository  string `yaml:"dropletRepository"`
-	ClusterBuilderName string `yaml:"clusterBuilderName"`
+	CFRootNamespace    string         `yaml:"cfRootNamespace"`
+	DropletRepository  string         `yaml:"dropletRepository"`
+	ClusterBuilderName string         `yaml:"clusterBuilderName"`
+	Tracing            tracing.Config `yaml:"tracing"`
 }
 
 func LoadFromPath(path string) (*ControllerConfig, error) {
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"code.cloudfoundry.org/korifi/kpack-image-builder/config"
 	"code.cloudfoundry.org/korifi/kpack-image-builder/controllers"
 	"code.cloudfoundry.org/korifi/kpack-image-builder/controllers/imageprocessfetcher"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	//+kubebuilder:scaffold:imports
 
@@ -103,6 +105,11 @@ func main() {
 		panic(errorMessage)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), "korifi-kpack-image-builder", controllerConfig.Tracing)
+	if err != nil {
+		panic(fmt.Sprintf("could not set up tracing: %v", err))
+	}
+
 	k8sClientConfig := ctrl.GetConfigOrDie()
 	k8sClient, err := k8sclient.NewForConfig(k8sClientConfig)
 	if err != nil {
@@ -163,4 +170,8 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem shutting down tracing")
+	}
 }
@@ -154,6 +154,11 @@ func (r *AppWorkloadToStatefulsetConverter) Convert(appWorkload *korifiv1alpha1.
 		},
 	}
 
+	if appWorkload.Spec.IsolationSegment != nil {
+		statefulSet.Spec.Template.Spec.NodeSelector = appWorkload.Spec.IsolationSegment.NodeSelector
+		statefulSet.Spec.Template.Spec.Tolerations = appWorkload.Spec.IsolationSegment.Tolerations
+	}
+
 	err = controllerutil.SetOwnerReference(appWorkload, statefulSet, r.scheme)
 	if err != nil {
 		r.log.Error(err, "failed to set OwnerRef on StatefulSet")
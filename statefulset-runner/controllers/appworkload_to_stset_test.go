@@ -223,6 +223,36 @@ var _ = Describe("AppWorkload to StatefulSet Converter", func() {
 		Expect(statefulSet.Spec.Template.Spec.ServiceAccountName).To(Equal("korifi-app"))
 	})
 
+	It("should not constrain the pod scheduling", func() {
+		Expect(statefulSet.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+		Expect(statefulSet.Spec.Template.Spec.Tolerations).To(BeEmpty())
+	})
+
+	When("the app workload has an isolation segment", func() {
+		BeforeEach(func() {
+			appWorkload.Spec.IsolationSegment = &korifiv1alpha1.IsolationSegmentPlacement{
+				Name:         "my-segment",
+				NodeSelector: map[string]string{"pool": "my-pool"},
+				Tolerations: []corev1.Toleration{{
+					Key:      "dedicated",
+					Operator: corev1.TolerationOpEqual,
+					Value:    "my-pool",
+					Effect:   corev1.TaintEffectNoSchedule,
+				}},
+			}
+		})
+
+		It("should schedule the pods on the segment's node pool", func() {
+			Expect(statefulSet.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "my-pool"}))
+			Expect(statefulSet.Spec.Template.Spec.Tolerations).To(ConsistOf(corev1.Toleration{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "my-pool",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}))
+		})
+	})
+
 	When("the app has environment set", func() {
 		BeforeEach(func() {
 			appWorkload.Spec.Env = []corev1.EnvVar{
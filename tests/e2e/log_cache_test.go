@@ -49,7 +49,7 @@ var _ = Describe("LogCache", func() {
 				g.Expect(result.Envelopes.Batch).NotTo(BeEmpty())
 				g.Expect(result.Envelopes.Batch).To(ContainElements(
 					MatchFields(IgnoreExtras, Fields{
-						"Tags": HaveKeyWithValue("source_type", "APP"),
+						"Tags": HaveKeyWithValue("source_type", "APP/PROC/WEB"),
 					})))
 			}).Should(Succeed())
 		})
@@ -3,6 +3,9 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"reflect"
+
+	"code.cloudfoundry.org/korifi/tools/tracing"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,6 +46,13 @@ func (r *PatchingReconciler[T, PT]) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	// continue the trace of the API request that last created or changed the object, if any
+	ctx, span := tracing.Start(
+		tracing.ExtractFromObject(ctx, obj),
+		"reconcile "+reflect.TypeOf(obj).Elem().Name(),
+		tracing.ObjectAttributes(obj),
+	)
+
 	var (
 		result      ctrl.Result
 		delegateErr error
@@ -53,9 +63,11 @@ func (r *PatchingReconciler[T, PT]) Reconcile(ctx context.Context, req ctrl.Requ
 	})
 	if err != nil {
 		log.Error(err, "patch object failed")
+		tracing.End(span, err)
 		return ctrl.Result{}, err
 	}
 
+	tracing.End(span, delegateErr)
 	return result, delegateErr
 }
 
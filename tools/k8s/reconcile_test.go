@@ -7,6 +7,11 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,17 +23,20 @@ import (
 
 	"code.cloudfoundry.org/korifi/controllers/fake"
 	"code.cloudfoundry.org/korifi/tools/k8s"
+	"code.cloudfoundry.org/korifi/tools/tracing"
 )
 
 type fakeObjectReconciler struct {
 	reconcileResourceError     error
 	reconcileResourceCallCount int
 	reconcileResourceObj       *corev1.Pod
+	reconcileResourceCtx       context.Context
 }
 
 func (f *fakeObjectReconciler) ReconcileResource(ctx context.Context, obj *corev1.Pod) (ctrl.Result, error) {
 	f.reconcileResourceCallCount++
 	f.reconcileResourceObj = obj
+	f.reconcileResourceCtx = ctx
 
 	obj.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
 	obj.Status.Message = "hello"
@@ -50,11 +58,15 @@ var _ = Describe("Reconcile", func() {
 		patchingReconciler *k8s.PatchingReconciler[corev1.Pod, *corev1.Pod]
 		objectReconciler   *fakeObjectReconciler
 		pod                *corev1.Pod
+		spanExporter       *tracetest.InMemoryExporter
 		result             ctrl.Result
 		err                error
 	)
 
 	BeforeEach(func() {
+		spanExporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(tracing.NewTracerProvider("test", sdktrace.WithSyncer(spanExporter)))
+
 		objectReconciler = new(fakeObjectReconciler)
 		fakeClient = new(fake.Client)
 		fakeStatusWriter = new(fake.StatusWriter)
@@ -105,6 +117,30 @@ var _ = Describe("Reconcile", func() {
 		Expect(obj).To(BeAssignableToTypeOf(&corev1.Pod{}))
 	})
 
+	It("traces the reconcile", func() {
+		spans := spanExporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("reconcile Pod"))
+		Expect(spans[0].Parent.IsValid()).To(BeFalse())
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+		Expect(trace.SpanContextFromContext(objectReconciler.reconcileResourceCtx)).To(Equal(spans[0].SpanContext))
+	})
+
+	When("the object has a trace context annotation", func() {
+		BeforeEach(func() {
+			pod.Annotations = map[string]string{
+				tracing.TraceParentAnnotation: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			}
+		})
+
+		It("continues the trace", func() {
+			spans := spanExporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].SpanContext.TraceID().String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(spans[0].Parent.SpanID().String()).To(Equal("00f067aa0ba902b7"))
+		})
+	})
+
 	When("the object does not exist", func() {
 		BeforeEach(func() {
 			fakeClient.GetReturns(apierrors.NewNotFound(schema.GroupResource{}, "pod"))
@@ -184,6 +220,12 @@ var _ = Describe("Reconcile", func() {
 			Expect(err).To(MatchError("reconcile-error"))
 		})
 
+		It("marks the reconcile span as failed", func() {
+			spans := spanExporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Status.Code).To(Equal(codes.Error))
+		})
+
 		It("updates the object and its status nevertheless", func() {
 			Expect(fakeClient.PatchCallCount()).To(Equal(1))
 			Expect(fakeStatusWriter.PatchCallCount()).To(Equal(1))
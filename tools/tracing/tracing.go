@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TraceParentAnnotation holds the W3C traceparent of the request that last created or changed an object, so that
+	// the reconciles it triggers join the same trace
+	TraceParentAnnotation = "korifi.cloudfoundry.org/traceparent"
+
+	instrumentationName = "code.cloudfoundry.org/korifi"
+	traceParentKey      = "traceparent"
+)
+
+type Config struct {
+	OTLPEndpoint string `yaml:"otlpEndpoint"`
+	Insecure     bool   `yaml:"insecure"`
+}
+
+// Setup installs the global tracer provider. Spans are only exported when an OTLP endpoint is configured, otherwise
+// tracing is a no-op. The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, serviceName string, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if config.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.OTLPEndpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := NewTracerProvider(serviceName, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// NewTracerProvider builds a tracer provider for the named service. Tests pass a syncer over an in-memory exporter.
+func NewTracerProvider(serviceName string, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	}, opts...)
+
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func ObjectAttributes(obj metav1.Object) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("k8s.namespace.name", obj.GetNamespace()),
+		attribute.String("k8s.object.name", obj.GetName()),
+	)
+}
+
+// InjectIntoObject stamps the span context of ctx onto the object annotations. It does nothing when ctx carries no
+// span.
+func InjectIntoObject(ctx context.Context, obj metav1.Object) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[TraceParentAnnotation] = carrier.Get(traceParentKey)
+	obj.SetAnnotations(annotations)
+}
+
+// ExtractFromObject returns a context whose remote parent span is the one stamped on the object, if any
+func ExtractFromObject(ctx context.Context, obj metav1.Object) context.Context {
+	traceParent, ok := obj.GetAnnotations()[TraceParentAnnotation]
+	if !ok {
+		return ctx
+	}
+
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
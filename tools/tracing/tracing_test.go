@@ -0,0 +1,110 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+
+	"code.cloudfoundry.org/korifi/tools/tracing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		ctx      context.Context
+		exporter *tracetest.InMemoryExporter
+		obj      *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(tracing.NewTracerProvider("test", sdktrace.WithSyncer(exporter)))
+
+		obj = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "name",
+				Annotations: map[string]string{"foo": "bar"},
+			},
+		}
+	})
+
+	Describe("End", func() {
+		It("exports the span", func() {
+			_, span := tracing.Start(ctx, "my-span")
+			tracing.End(span, nil)
+
+			Expect(exporter.GetSpans()).To(HaveLen(1))
+			Expect(exporter.GetSpans()[0].Name).To(Equal("my-span"))
+			Expect(exporter.GetSpans()[0].Status.Code).To(Equal(codes.Unset))
+		})
+
+		When("there is an error", func() {
+			It("marks the span as failed", func() {
+				_, span := tracing.Start(ctx, "my-span")
+				tracing.End(span, errors.New("boom"))
+
+				Expect(exporter.GetSpans()).To(HaveLen(1))
+				Expect(exporter.GetSpans()[0].Status.Code).To(Equal(codes.Error))
+				Expect(exporter.GetSpans()[0].Status.Description).To(Equal("boom"))
+				Expect(exporter.GetSpans()[0].Events).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("InjectIntoObject and ExtractFromObject", func() {
+		It("continues the trace from the object annotation", func() {
+			parentCtx, parent := tracing.Start(ctx, "parent")
+			tracing.InjectIntoObject(parentCtx, obj)
+			parent.End()
+
+			Expect(obj.Annotations).To(HaveKeyWithValue("foo", "bar"))
+			Expect(obj.Annotations).To(HaveKeyWithValue(tracing.TraceParentAnnotation, MatchRegexp(
+				"^00-%s-%s-01$", parent.SpanContext().TraceID(), parent.SpanContext().SpanID(),
+			)))
+
+			_, child := tracing.Start(tracing.ExtractFromObject(ctx, obj), "child")
+			child.End()
+
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(2))
+			Expect(spans[1].SpanContext.TraceID()).To(Equal(parent.SpanContext().TraceID()))
+			Expect(spans[1].Parent.SpanID()).To(Equal(parent.SpanContext().SpanID()))
+			Expect(spans[1].Parent.IsRemote()).To(BeTrue())
+		})
+
+		When("the context has no span", func() {
+			It("does not annotate the object", func() {
+				tracing.InjectIntoObject(ctx, obj)
+				Expect(obj.Annotations).NotTo(HaveKey(tracing.TraceParentAnnotation))
+			})
+		})
+
+		When("the object has no annotations", func() {
+			BeforeEach(func() {
+				obj.Annotations = nil
+			})
+
+			It("initialises them", func() {
+				spanCtx, span := tracing.Start(ctx, "span")
+				defer span.End()
+
+				tracing.InjectIntoObject(spanCtx, obj)
+				Expect(obj.Annotations).To(HaveKey(tracing.TraceParentAnnotation))
+			})
+
+			It("extracts no span context", func() {
+				Expect(trace.SpanContextFromContext(tracing.ExtractFromObject(ctx, obj)).IsValid()).To(BeFalse())
+			})
+		})
+	})
+})